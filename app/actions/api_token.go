@@ -0,0 +1,63 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/pkg/validate"
+)
+
+// CreateAPIToken is the input model used to create a personal access token
+type CreateAPIToken struct {
+	Name          string          `json:"name"`
+	Scopes        []enum.APIScope `json:"scopes"`
+	ExpiresInDays int             `json:"expiresInDays"`
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *CreateAPIToken) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.IsCollaborator()
+}
+
+// Validate if current model is valid
+func (action *CreateAPIToken) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	if action.Name == "" {
+		result.AddFieldFailure("name", "Name is required.")
+	} else if len(action.Name) > 100 {
+		result.AddFieldFailure("name", "Name must have less than 100 characters.")
+	}
+
+	if len(action.Scopes) == 0 {
+		result.AddFieldFailure("scopes", "At least one scope is required.")
+	}
+
+	seen := make(map[enum.APIScope]bool)
+	for _, scope := range action.Scopes {
+		if !scope.IsValid() {
+			result.AddFieldFailure("scopes", fmt.Sprintf("'%s' is not a valid scope.", scope))
+		} else if seen[scope] {
+			result.AddFieldFailure("scopes", fmt.Sprintf("Scope '%s' is duplicated.", scope))
+		}
+		seen[scope] = true
+	}
+
+	if action.ExpiresInDays < 0 || action.ExpiresInDays > 365 {
+		result.AddFieldFailure("expiresInDays", "Expiration must be between 1 and 365 days, or 0 for tokens that never expire.")
+	}
+
+	return result
+}
+
+// ExpiresAt returns when the token expires, nil if it never expires
+func (action *CreateAPIToken) ExpiresAt() *time.Time {
+	if action.ExpiresInDays == 0 {
+		return nil
+	}
+	expiresAt := time.Now().Add(time.Duration(action.ExpiresInDays) * 24 * time.Hour)
+	return &expiresAt
+}
@@ -0,0 +1,79 @@
+package actions_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/getfider/fider/app/actions"
+	"github.com/getfider/fider/app/models/enum"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/mock"
+	"github.com/getfider/fider/app/pkg/rand"
+)
+
+func TestCreateAPIToken_InvalidInput(t *testing.T) {
+	RegisterT(t)
+
+	testCases := []struct {
+		expected []string
+		action   *actions.CreateAPIToken
+	}{
+		{
+			expected: []string{"name", "scopes"},
+			action:   &actions.CreateAPIToken{},
+		},
+		{
+			expected: []string{"name", "expiresInDays"},
+			action: &actions.CreateAPIToken{
+				Name:          rand.String(101),
+				Scopes:        []enum.APIScope{enum.APIScopeReadPosts},
+				ExpiresInDays: 400,
+			},
+		},
+		{
+			expected: []string{"scopes"},
+			action: &actions.CreateAPIToken{
+				Name:   "Zapier",
+				Scopes: []enum.APIScope{enum.APIScopeReadPosts, "posts:delete"},
+			},
+		},
+		{
+			expected: []string{"scopes", "expiresInDays"},
+			action: &actions.CreateAPIToken{
+				Name:          "Zapier",
+				Scopes:        []enum.APIScope{enum.APIScopeReadPosts, enum.APIScopeReadPosts},
+				ExpiresInDays: -1,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		result := testCase.action.Validate(context.Background(), mock.JonSnow)
+		ExpectFailed(result, testCase.expected...)
+	}
+}
+
+func TestCreateAPIToken_ValidInput(t *testing.T) {
+	RegisterT(t)
+
+	action := &actions.CreateAPIToken{
+		Name:   "Zapier",
+		Scopes: []enum.APIScope{enum.APIScopeReadPosts, enum.APIScopeManageTags},
+	}
+	result := action.Validate(context.Background(), mock.JonSnow)
+	ExpectSuccess(result)
+	Expect(action.ExpiresAt()).IsNil()
+
+	action.ExpiresInDays = 90
+	Expect(*action.ExpiresAt()).TemporarilySimilar(time.Now().Add(90*24*time.Hour), time.Minute)
+}
+
+func TestCreateAPIToken_IsAuthorized(t *testing.T) {
+	RegisterT(t)
+
+	action := &actions.CreateAPIToken{}
+	Expect(action.IsAuthorized(context.Background(), mock.JonSnow)).IsTrue()
+	Expect(action.IsAuthorized(context.Background(), mock.AryaStark)).IsFalse()
+	Expect(action.IsAuthorized(context.Background(), nil)).IsFalse()
+}
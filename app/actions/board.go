@@ -0,0 +1,152 @@
+package actions
+
+import (
+	"context"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/validate"
+	"github.com/gosimple/slug"
+)
+
+// CreateEditBoard is used to create a new board or edit existing
+type CreateEditBoard struct {
+	Slug                string   `route:"slug"`
+	Name                string   `json:"name"`
+	Description         string   `json:"description"`
+	DescriptionTemplate string   `json:"descriptionTemplate"`
+	IsPublic            bool     `json:"isPublic"`
+	HasRoadmap          bool     `json:"hasRoadmap"`
+	DefaultTagSlugs     []string `json:"defaultTags"`
+
+	Board       *entity.Board
+	DefaultTags []*entity.Tag
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *CreateEditBoard) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.IsAdministrator()
+}
+
+// Validate if current model is valid
+func (action *CreateEditBoard) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	if action.Slug != "" {
+		getSlug := &query.GetBoardBySlug{Slug: action.Slug}
+		if err := bus.Dispatch(ctx, getSlug); err != nil {
+			return validate.Error(err)
+		}
+		action.Board = getSlug.Result
+	}
+
+	if action.Name == "" {
+		result.AddFieldFailure("name", "Name is required.")
+	} else if len(action.Name) > 60 {
+		result.AddFieldFailure("name", "Name must have less than 60 characters.")
+	} else if slug.Make(action.Name) == "" {
+		result.AddFieldFailure("name", "Name must have at least one letter or number.")
+	} else {
+		getDuplicateSlug := &query.GetBoardBySlug{Slug: slug.Make(action.Name)}
+		err := bus.Dispatch(ctx, getDuplicateSlug)
+		if err != nil && errors.Cause(err) != app.ErrNotFound {
+			return validate.Error(err)
+		} else if err == nil && (action.Board == nil || action.Board.ID != getDuplicateSlug.Result.ID) {
+			result.AddFieldFailure("name", "This board name is already in use.")
+		}
+	}
+
+	if len(action.Description) > 1000 {
+		result.AddFieldFailure("description", "Description must have less than 1000 characters.")
+	}
+
+	if len(action.DescriptionTemplate) > 1000 {
+		result.AddFieldFailure("descriptionTemplate", "Description template must have less than 1000 characters.")
+	}
+
+	action.DefaultTags = make([]*entity.Tag, 0, len(action.DefaultTagSlugs))
+	for _, tagSlug := range action.DefaultTagSlugs {
+		getTag := &query.GetTagBySlug{Slug: tagSlug}
+		err := bus.Dispatch(ctx, getTag)
+		if err != nil && errors.Cause(err) != app.ErrNotFound {
+			return validate.Error(err)
+		} else if err != nil {
+			result.AddFieldFailure("defaultTags", "Tag '"+tagSlug+"' does not exist.")
+		} else {
+			action.DefaultTags = append(action.DefaultTags, getTag.Result)
+		}
+	}
+
+	return result
+}
+
+// DeleteBoard is used to delete an existing board
+type DeleteBoard struct {
+	Slug string `route:"slug"`
+
+	Board *entity.Board
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *DeleteBoard) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.IsAdministrator()
+}
+
+// Validate if current model is valid
+func (action *DeleteBoard) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	getSlug := &query.GetBoardBySlug{Slug: action.Slug}
+	if err := bus.Dispatch(ctx, getSlug); err != nil {
+		return validate.Error(err)
+	}
+
+	action.Board = getSlug.Result
+
+	// Posts of a deleted board are moved to the main list, which would publish the posts of a private board
+	if !action.Board.IsPublic && action.Board.PostsCount > 0 {
+		return validate.Failed("This board is private and still has posts. Move its posts to another board before deleting it.")
+	}
+
+	return validate.Success()
+}
+
+// SetPostBoard is used to move a post to another board, or out of any board when BoardSlug is empty
+type SetPostBoard struct {
+	Number    int    `route:"number"`
+	BoardSlug string `json:"board"`
+
+	Post  *entity.Post
+	Board *entity.Board
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *SetPostBoard) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.IsCollaborator()
+}
+
+// Validate if current model is valid
+func (action *SetPostBoard) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	getPost := &query.GetPostByNumber{Number: action.Number}
+	if err := bus.Dispatch(ctx, getPost); err != nil {
+		return validate.Error(err)
+	}
+	action.Post = getPost.Result
+
+	if action.BoardSlug != "" {
+		getBoard := &query.GetBoardBySlug{Slug: action.BoardSlug}
+		err := bus.Dispatch(ctx, getBoard)
+		if err != nil && errors.Cause(err) != app.ErrNotFound {
+			return validate.Error(err)
+		} else if err != nil {
+			result.AddFieldFailure("board", propertyIsInvalid(ctx, "board"))
+		} else {
+			action.Board = getBoard.Result
+		}
+	}
+
+	return result
+}
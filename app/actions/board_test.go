@@ -0,0 +1,107 @@
+package actions_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getfider/fider/app"
+
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+
+	"github.com/getfider/fider/app/actions"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/rand"
+)
+
+func TestCreateEditBoard_InvalidName(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetBoardBySlug) error {
+		q.Result = &entity.Board{ID: 1, Slug: "mobile", Name: "Mobile"}
+		return nil
+	})
+
+	for _, name := range []string{
+		"",
+		"Mobile",
+		"!!!",
+		rand.String(61),
+	} {
+		action := &actions.CreateEditBoard{Name: name}
+		result := action.Validate(context.Background(), nil)
+		ExpectFailed(result, "name")
+	}
+}
+
+func TestCreateEditBoard_UnknownDefaultTag(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetBoardBySlug) error {
+		return app.ErrNotFound
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetTagBySlug) error {
+		return app.ErrNotFound
+	})
+
+	action := &actions.CreateEditBoard{Name: "Mobile", DefaultTagSlugs: []string{"ios"}}
+	result := action.Validate(context.Background(), nil)
+	ExpectFailed(result, "defaultTags")
+}
+
+func TestCreateEditBoard_ValidInput(t *testing.T) {
+	RegisterT(t)
+
+	board := &entity.Board{ID: 1, Slug: "mobile", Name: "Mobile"}
+	tag := &entity.Tag{ID: 2, Slug: "ios", Name: "iOS"}
+	bus.AddHandler(func(ctx context.Context, q *query.GetBoardBySlug) error {
+		if q.Slug == board.Slug {
+			q.Result = board
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetTagBySlug) error {
+		q.Result = tag
+		return nil
+	})
+
+	action := &actions.CreateEditBoard{Name: "Billing", DefaultTagSlugs: []string{"ios"}}
+	result := action.Validate(context.Background(), nil)
+	ExpectSuccess(result)
+	Expect(action.Board).IsNil()
+	Expect(action.DefaultTags).HasLen(1)
+	Expect(action.DefaultTags[0]).Equals(tag)
+
+	action = &actions.CreateEditBoard{Name: "Mobile", Slug: "mobile"}
+	result = action.Validate(context.Background(), nil)
+	ExpectSuccess(result)
+	Expect(action.Board).Equals(board)
+}
+
+func TestDeleteBoard_PrivateWithPosts(t *testing.T) {
+	RegisterT(t)
+
+	board := &entity.Board{ID: 1, Slug: "internal", Name: "Internal", IsPublic: false, PostsCount: 2}
+	bus.AddHandler(func(ctx context.Context, q *query.GetBoardBySlug) error {
+		q.Result = board
+		return nil
+	})
+
+	action := &actions.DeleteBoard{Slug: "internal"}
+	result := action.Validate(context.Background(), nil)
+	Expect(result.Ok).IsFalse()
+
+	board.PostsCount = 0
+	result = action.Validate(context.Background(), nil)
+	ExpectSuccess(result)
+
+	board.IsPublic = true
+	board.PostsCount = 2
+	result = action.Validate(context.Background(), nil)
+	ExpectSuccess(result)
+	Expect(action.Board).Equals(board)
+}
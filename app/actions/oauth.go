@@ -2,6 +2,7 @@ package actions
 
 import (
 	"context"
+	"slices"
 	"strings"
 
 	"github.com/getfider/fider/app"
@@ -30,6 +31,8 @@ type CreateEditOAuthConfig struct {
 	Scope             string           `json:"scope"`
 	ProfileURL        string           `json:"profileURL"`
 	IsTrusted         bool             `json:"isTrusted"`
+	IsOIDC            bool             `json:"isOIDC"`
+	IssuerURL         string           `json:"issuerURL"`
 	JSONUserIDPath    string           `json:"jsonUserIDPath"`
 	JSONUserNamePath  string           `json:"jsonUserNamePath"`
 	JSONUserEmailPath string           `json:"jsonUserEmailPath"`
@@ -148,10 +151,37 @@ func (action *CreateEditOAuthConfig) Validate(ctx context.Context, user *entity.
 		result.AddFieldFailure("clientSecret", "Client Secret must have less than 500 characters.")
 	}
 
+	if action.IsOIDC && action.Scope == "" {
+		action.Scope = "openid profile email"
+	}
+
 	if action.Scope == "" {
 		result.AddFieldFailure("scope", "Scope is required.")
 	} else if len(action.Scope) > 100 {
 		result.AddFieldFailure("scope", "Scope must have less than 100 characters.")
+	} else if action.IsOIDC && !slices.Contains(strings.Fields(action.Scope), "openid") {
+		result.AddFieldFailure("scope", "Scope must include 'openid'.")
+	}
+
+	if action.IsOIDC {
+		// Endpoints and claims of OpenID Providers are discovered from the issuer
+		if action.IssuerURL == "" {
+			result.AddFieldFailure("issuerURL", "Issuer URL is required.")
+		} else if len(action.IssuerURL) > 300 {
+			result.AddFieldFailure("issuerURL", "Issuer URL must have less than 300 characters.")
+		} else if messages := validate.URL(ctx, action.IssuerURL); len(messages) > 0 {
+			result.AddFieldFailure("issuerURL", messages...)
+		}
+
+		if len(action.JSONUserRolesPath) > 100 {
+			result.AddFieldFailure("jsonUserRolesPath", "JSON User Roles Path must have less than 100 characters.")
+		}
+
+		if len(action.AllowedRoles) > 500 {
+			result.AddFieldFailure("allowedRoles", "Allowed Roles must have less than 500 characters.")
+		}
+
+		return result
 	}
 
 	if action.AuthorizeURL == "" {
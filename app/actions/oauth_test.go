@@ -98,6 +98,42 @@ func TestCreateEditOAuthConfig_AddNew_ValidInput(t *testing.T) {
 	Expect(string(action.Provider[0])).Equals("_")
 }
 
+func TestCreateEditOAuthConfig_OIDC(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.ListActiveOAuthProviders) error {
+		q.Result = []*dto.OAuthProviderOption{}
+		return nil
+	})
+
+	ctx := context.WithValue(context.Background(), app.TenantCtxKey, &entity.Tenant{
+		IsEmailAuthAllowed: true,
+	})
+
+	action := &actions.CreateEditOAuthConfig{
+		DisplayName:  "My Provider",
+		Status:       enum.OAuthConfigEnabled,
+		ClientID:     "823187ahjjfdha8fds7yfdashfjkdsa",
+		ClientSecret: "jijads78d76cn347768x3t4668q275",
+		IsOIDC:       true,
+		Scope:        "profile email",
+	}
+	result := action.Validate(ctx, nil)
+	ExpectFailed(result, "scope", "issuerURL")
+
+	action = &actions.CreateEditOAuthConfig{
+		DisplayName:  "My Provider",
+		Status:       enum.OAuthConfigEnabled,
+		ClientID:     "823187ahjjfdha8fds7yfdashfjkdsa",
+		ClientSecret: "jijads78d76cn347768x3t4668q275",
+		IsOIDC:       true,
+		IssuerURL:    "https://login.example.org",
+	}
+	result = action.Validate(ctx, nil)
+	ExpectSuccess(result)
+	Expect(action.Scope).Equals("openid profile email")
+}
+
 func TestCreateEditOAuthConfig_EditExisting_NewSecret(t *testing.T) {
 	RegisterT(t)
 
@@ -26,12 +26,23 @@ type CreateNewPost struct {
 	Description string             `json:"description"`
 	TagSlugs    []string           `json:"tags"`
 	Attachments []*dto.ImageUpload `json:"attachments"`
+	BoardSlug   string             `json:"board"`
 
-	Tags []*entity.Tag
+	Tags  []*entity.Tag
+	Board *entity.Board
 }
 
-// OnPreExecute prefetches Tags for later use
+// OnPreExecute prefetches Tags and Board for later use
 func (input *CreateNewPost) OnPreExecute(ctx context.Context) error {
+	if input.BoardSlug != "" {
+		getBoard := &query.GetBoardBySlug{Slug: input.BoardSlug}
+		err := bus.Dispatch(ctx, getBoard)
+		if err != nil && errors.Cause(err) != app.ErrNotFound {
+			return err
+		}
+		input.Board = getBoard.Result
+	}
+
 	if env.Config.PostCreationWithTagsEnabled {
 		input.Tags = make([]*entity.Tag, 0, len(input.TagSlugs))
 		for _, slug := range input.TagSlugs {
@@ -86,6 +97,10 @@ func (action *CreateNewPost) Validate(ctx context.Context, user *entity.User) *v
 		}
 	}
 
+	if action.BoardSlug != "" && action.Board == nil {
+		result.AddFieldFailure("board", propertyIsInvalid(ctx, "board"))
+	}
+
 	messages, err := validate.MultiImageUpload(ctx, nil, action.Attachments, validate.MultiImageUploadOpts{
 		MaxUploads:   3,
 		MaxKilobytes: 5120,
@@ -171,6 +186,55 @@ func (action *UpdatePost) Validate(ctx context.Context, user *entity.User) *vali
 	return result
 }
 
+// AddVote is used to vote on a post, several votes can be placed on the same post when vote budgets are enabled
+type AddVote struct {
+	Number int `route:"number"`
+	Amount int `json:"amount"`
+
+	Post   *entity.Post
+	Budget *entity.VoteBudget
+}
+
+// OnPreExecute prefetches Post and current user's vote budget for later use
+func (action *AddVote) OnPreExecute(ctx context.Context) error {
+	getPost := &query.GetPostByNumber{Number: action.Number}
+	getBudget := &query.GetVoteBudget{}
+	if err := bus.Dispatch(ctx, getPost, getBudget); err != nil {
+		return err
+	}
+
+	action.Post = getPost.Result
+	action.Budget = getBudget.Result
+	return nil
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *AddVote) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil
+}
+
+// Validate if current model is valid
+func (action *AddVote) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	if action.Amount == 0 {
+		action.Amount = 1
+	}
+
+	tenant := ctx.Value(app.TenantCtxKey).(*entity.Tenant)
+	if action.Amount < 1 || action.Amount > tenant.MaxVoteWeight() {
+		result.AddFieldFailure("amount", i18n.T(ctx, "validation.custom.voteamount", i18n.Params{"max": tenant.MaxVoteWeight()}))
+	} else if tenant.IsVoteBudgetEnabled() && action.Post.CanBeVoted() {
+		// Votes already placed on this post are given back before spending the new amount
+		available := action.Budget.Total - action.Budget.Spent + action.Post.VoteWeight
+		if action.Amount > available {
+			result.AddFieldFailure("amount", i18n.T(ctx, "validation.custom.votebudget", i18n.Params{"remaining": action.Budget.Remaining()}))
+		}
+	}
+
+	return result
+}
+
 type ToggleCommentReaction struct {
 	Number   int    `route:"number"`
 	Comment  int    `route:"id"`
@@ -208,10 +272,17 @@ type AddNewComment struct {
 	Number      int                `route:"number"`
 	Content     string             `json:"content"`
 	Attachments []*dto.ImageUpload `json:"attachments"`
+	IsInternal  bool               `json:"isInternal"`
+	ParentID    int                `json:"parentId"`
+
+	Parent *entity.Comment
 }
 
 // IsAuthorized returns true if current user is authorized to perform this action
 func (action *AddNewComment) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	if action.IsInternal {
+		return user != nil && user.IsCollaborator()
+	}
 	return user != nil
 }
 
@@ -235,6 +306,19 @@ func (action *AddNewComment) Validate(ctx context.Context, user *entity.User) *v
 	}
 	result.AddFieldFailure("attachments", messages...)
 
+	if action.ParentID > 0 {
+		getPost := &query.GetPostByNumber{Number: action.Number}
+		getParent := &query.GetCommentByID{CommentID: action.ParentID}
+		err := bus.Dispatch(ctx, getPost, getParent)
+		if err != nil && errors.Cause(err) != app.ErrNotFound {
+			return validate.Error(err)
+		} else if err != nil || getParent.Result.PostID != getPost.Result.ID {
+			result.AddFieldFailure("parentId", i18n.T(ctx, "validation.custom.replyparentnotfound"))
+		} else {
+			action.Parent = getParent.Result
+		}
+	}
+
 	return result
 }
 
@@ -257,7 +341,20 @@ func (action *SetResponse) IsAuthorized(ctx context.Context, user *entity.User)
 func (action *SetResponse) Validate(ctx context.Context, user *entity.User) *validate.Result {
 	result := validate.Success()
 
-	if action.Status < enum.PostOpen || action.Status > enum.PostDuplicate {
+	if action.Status.IsCustom() {
+		getStatuses := &query.GetCustomPostStatuses{}
+		if err := bus.Dispatch(ctx, getStatuses); err != nil {
+			return validate.Error(err)
+		}
+
+		found := false
+		for _, status := range getStatuses.Result {
+			found = found || status.Status == action.Status
+		}
+		if !found {
+			result.AddFieldFailure("status", propertyIsInvalid(ctx, "status"))
+		}
+	} else if action.Status < enum.PostOpen || action.Status > enum.PostDuplicate {
 		result.AddFieldFailure("status", propertyIsInvalid(ctx, "status"))
 	}
 
@@ -284,6 +381,54 @@ func (action *SetResponse) Validate(ctx context.Context, user *entity.User) *val
 	return result
 }
 
+// MergePosts represents the action of merging a duplicate post into its original
+type MergePosts struct {
+	Number         int  `route:"number"`
+	OriginalNumber int  `json:"originalNumber"`
+	MergeComments  bool `json:"mergeComments"`
+
+	Post     *entity.Post
+	Original *entity.Post
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *MergePosts) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.IsCollaborator()
+}
+
+// Validate if current model is valid
+func (action *MergePosts) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	if action.OriginalNumber == action.Number {
+		result.AddFieldFailure("originalNumber", i18n.T(ctx, "validation.custom.selfduplicate"))
+		return result
+	}
+
+	getPost := &query.GetPostByNumber{Number: action.Number}
+	if err := bus.Dispatch(ctx, getPost); err != nil {
+		return validate.Error(err)
+	}
+	action.Post = getPost.Result
+
+	getOriginalPost := &query.GetPostByNumber{Number: action.OriginalNumber}
+	err := bus.Dispatch(ctx, getOriginalPost)
+	if err != nil {
+		if errors.Cause(err) == app.ErrNotFound {
+			result.AddFieldFailure("originalNumber", i18n.T(ctx, "validation.custom.originalpostnotfound"))
+			return result
+		}
+		return validate.Error(err)
+	}
+	action.Original = getOriginalPost.Result
+
+	if action.Original.Status == enum.PostDuplicate {
+		result.AddFieldFailure("originalNumber", i18n.T(ctx, "validation.custom.mergeintoduplicate"))
+	}
+
+	return result
+}
+
 // DeletePost represents the action of an administrator deleting an existing Post
 type DeletePost struct {
 	Number int    `route:"number"`
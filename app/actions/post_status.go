@@ -0,0 +1,100 @@
+package actions
+
+import (
+	"context"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/validate"
+	"github.com/gosimple/slug"
+)
+
+// CreateEditPostStatus is used to create a new custom post status or edit existing
+type CreateEditPostStatus struct {
+	Slug          string `route:"slug"`
+	Name          string `json:"name"`
+	Color         string `json:"color" format:"upper"`
+	AcceptsVotes  bool   `json:"acceptsVotes"`
+	RoadmapColumn string `json:"roadmapColumn" format:"lower"`
+
+	Status *entity.CustomPostStatus
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *CreateEditPostStatus) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.IsAdministrator()
+}
+
+// Validate if current model is valid
+func (action *CreateEditPostStatus) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	if action.Slug != "" {
+		getSlug := &query.GetCustomPostStatusBySlug{Slug: action.Slug}
+		err := bus.Dispatch(ctx, getSlug)
+		if err != nil {
+			return validate.Error(err)
+		}
+		action.Status = getSlug.Result
+	}
+
+	if action.Name == "" {
+		result.AddFieldFailure("name", "Name is required.")
+	} else if len(action.Name) > 30 {
+		result.AddFieldFailure("name", "Name must have less than 30 characters.")
+	} else {
+		getDuplicateSlug := &query.GetCustomPostStatusBySlug{Slug: slug.Make(action.Name)}
+		err := bus.Dispatch(ctx, getDuplicateSlug)
+		if err != nil && errors.Cause(err) != app.ErrNotFound {
+			return validate.Error(err)
+		} else if err == nil && (action.Status == nil || action.Status.Status != getDuplicateSlug.Result.Status) {
+			result.AddFieldFailure("name", "This status name is already in use.")
+		}
+	}
+
+	if action.Color == "" {
+		result.AddFieldFailure("color", "Color is required.")
+	} else if len(action.Color) != 6 {
+		result.AddFieldFailure("color", "Color must be exactly 6 characters.")
+	} else if !colorRegex.MatchString(action.Color) {
+		result.AddFieldFailure("color", "Color is invalid.")
+	}
+
+	if !entity.IsValidRoadmapColumn(action.RoadmapColumn) {
+		result.AddFieldFailure("roadmapColumn", "Roadmap column must be one of planned, started or completed.")
+	}
+
+	return result
+}
+
+// DeletePostStatus is used to delete an existing custom post status
+type DeletePostStatus struct {
+	Slug string `route:"slug"`
+
+	Status *entity.CustomPostStatus
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *DeletePostStatus) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.IsAdministrator()
+}
+
+// Validate if current model is valid
+func (action *DeletePostStatus) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	getSlug := &query.GetCustomPostStatusBySlug{Slug: action.Slug}
+	countPerStatus := &query.CountPostPerStatus{}
+	if err := bus.Dispatch(ctx, getSlug, countPerStatus); err != nil {
+		return validate.Error(err)
+	}
+
+	action.Status = getSlug.Result
+
+	result := validate.Success()
+	if countPerStatus.Result[action.Status.Status] > 0 {
+		result.AddFieldFailure("slug", "This status is in use, move its posts to another status first.")
+	}
+	return result
+}
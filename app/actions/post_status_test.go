@@ -0,0 +1,78 @@
+package actions_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getfider/fider/app"
+
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+
+	"github.com/getfider/fider/app/actions"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/rand"
+)
+
+func TestCreateEditPostStatus_InvalidName(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCustomPostStatusBySlug) error {
+		q.Result = &entity.CustomPostStatus{Status: 100, Slug: "under-review", Name: "Under review", Color: "000000"}
+		return nil
+	})
+
+	for _, name := range []string{
+		"",
+		"Under review",
+		rand.String(31),
+	} {
+		action := &actions.CreateEditPostStatus{Name: name, Color: "FFFFFF"}
+		result := action.Validate(context.Background(), nil)
+		ExpectFailed(result, "name")
+	}
+}
+
+func TestCreateEditPostStatus_InvalidRoadmapColumn(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCustomPostStatusBySlug) error {
+		return app.ErrNotFound
+	})
+
+	action := &actions.CreateEditPostStatus{Name: "In beta", Color: "FFFFFF", RoadmapColumn: "declined"}
+	result := action.Validate(context.Background(), nil)
+	ExpectFailed(result, "roadmapColumn")
+}
+
+func TestCreateEditPostStatus_Valid(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCustomPostStatusBySlug) error {
+		return app.ErrNotFound
+	})
+
+	action := &actions.CreateEditPostStatus{Name: "In beta", Color: "FFFFFF", RoadmapColumn: "started"}
+	result := action.Validate(context.Background(), nil)
+	ExpectSuccess(result)
+}
+
+func TestDeletePostStatus_InUse(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCustomPostStatusBySlug) error {
+		q.Result = &entity.CustomPostStatus{Status: 100, Slug: "under-review", Name: "Under review"}
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.CountPostPerStatus) error {
+		q.Result = map[enum.PostStatus]int{100: 2}
+		return nil
+	})
+
+	action := &actions.DeletePostStatus{Slug: "under-review"}
+	result := action.Validate(context.Background(), nil)
+	ExpectFailed(result, "slug")
+}
@@ -68,6 +68,34 @@ func TestSetResponse_InvalidStatus(t *testing.T) {
 	ExpectFailed(result, "status")
 }
 
+func TestSetResponse_CustomStatus(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCustomPostStatuses) error {
+		q.Result = []*entity.CustomPostStatus{{Status: 100, Name: "Under review", Slug: "under-review"}}
+		return nil
+	})
+
+	action := &actions.SetResponse{Status: 100, Text: "Looking into it"}
+	result := action.Validate(context.Background(), nil)
+	ExpectSuccess(result)
+
+	action = &actions.SetResponse{Status: 101, Text: "Looking into it"}
+	result = action.Validate(context.Background(), nil)
+	ExpectFailed(result, "status")
+}
+
+func TestAddNewComment_InternalNoteRequiresStaff(t *testing.T) {
+	RegisterT(t)
+
+	action := &actions.AddNewComment{Content: "Internal note", IsInternal: true}
+	Expect(action.IsAuthorized(context.Background(), &entity.User{Role: enum.RoleVisitor})).IsFalse()
+	Expect(action.IsAuthorized(context.Background(), &entity.User{Role: enum.RoleCollaborator})).IsTrue()
+
+	action = &actions.AddNewComment{Content: "Public comment"}
+	Expect(action.IsAuthorized(context.Background(), &entity.User{Role: enum.RoleVisitor})).IsTrue()
+}
+
 func TestDeletePost_WhenIsBeingReferenced(t *testing.T) {
 	RegisterT(t)
 
@@ -166,3 +194,40 @@ func TestEditComment_AtMaxLength(t *testing.T) {
 	result := action.Validate(context.Background(), nil)
 	ExpectSuccess(result)
 }
+
+func TestAddVote_WithoutBudget(t *testing.T) {
+	RegisterT(t)
+
+	ctx := context.WithValue(context.Background(), app.TenantCtxKey, &entity.Tenant{})
+	post := &entity.Post{Status: enum.PostOpen}
+
+	action := &actions.AddVote{Post: post, Budget: &entity.VoteBudget{}}
+	ExpectSuccess(action.Validate(ctx, nil))
+	Expect(action.Amount).Equals(1)
+
+	action = &actions.AddVote{Amount: 2, Post: post, Budget: &entity.VoteBudget{}}
+	ExpectFailed(action.Validate(ctx, nil), "amount")
+}
+
+func TestAddVote_WithBudget(t *testing.T) {
+	RegisterT(t)
+
+	ctx := context.WithValue(context.Background(), app.TenantCtxKey, &entity.Tenant{VoteBudget: 10, MaxVotesPerPost: 3})
+	budget := &entity.VoteBudget{Total: 10, Spent: 8}
+
+	action := &actions.AddVote{Amount: 2, Post: &entity.Post{Status: enum.PostOpen}, Budget: budget}
+	ExpectSuccess(action.Validate(ctx, nil))
+
+	action = &actions.AddVote{Amount: 3, Post: &entity.Post{Status: enum.PostOpen}, Budget: budget}
+	ExpectFailed(action.Validate(ctx, nil), "amount")
+
+	action = &actions.AddVote{Amount: 4, Post: &entity.Post{Status: enum.PostOpen}, Budget: budget}
+	ExpectFailed(action.Validate(ctx, nil), "amount")
+
+	action = &actions.AddVote{Amount: -1, Post: &entity.Post{Status: enum.PostOpen}, Budget: budget}
+	ExpectFailed(action.Validate(ctx, nil), "amount")
+
+	// votes already placed on the post can be moved around
+	action = &actions.AddVote{Amount: 3, Post: &entity.Post{Status: enum.PostOpen, HasVoted: true, VoteWeight: 1}, Budget: budget}
+	ExpectSuccess(action.Validate(ctx, nil))
+}
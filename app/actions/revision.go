@@ -0,0 +1,100 @@
+package actions
+
+import (
+	"context"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/i18n"
+	"github.com/getfider/fider/app/pkg/validate"
+	"github.com/gosimple/slug"
+)
+
+// RevertPostRevision restores the title and description of a post to a previous revision
+type RevertPostRevision struct {
+	Number  int `route:"number"`
+	Version int `route:"version"`
+
+	Post     *entity.Post
+	Revision *entity.Revision
+}
+
+// OnPreExecute prefetches Post and Revision for later use
+func (action *RevertPostRevision) OnPreExecute(ctx context.Context) error {
+	getPost := &query.GetPostByNumber{Number: action.Number}
+	if err := bus.Dispatch(ctx, getPost); err != nil {
+		return err
+	}
+
+	getRevision := &query.GetPostRevision{PostID: getPost.Result.ID, Version: action.Version}
+	if err := bus.Dispatch(ctx, getRevision); err != nil {
+		return err
+	}
+
+	action.Post = getPost.Result
+	action.Revision = getRevision.Result
+	return nil
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *RevertPostRevision) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.IsCollaborator()
+}
+
+// Validate if current model is valid
+func (action *RevertPostRevision) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	postBySlug := &query.GetPostBySlug{Slug: slug.Make(action.Revision.Title)}
+	err := bus.Dispatch(ctx, postBySlug)
+	if err != nil && errors.Cause(err) != app.ErrNotFound {
+		return validate.Error(err)
+	} else if err == nil && postBySlug.Result.ID != action.Post.ID {
+		result.AddFieldFailure("title", i18n.T(ctx, "validation.custom.duplicatetitle"))
+	}
+
+	return result
+}
+
+// RevertCommentRevision restores the content of a comment to a previous revision
+type RevertCommentRevision struct {
+	PostNumber int `route:"number"`
+	ID         int `route:"id"`
+	Version    int `route:"version"`
+
+	Post     *entity.Post
+	Comment  *entity.Comment
+	Revision *entity.Revision
+}
+
+// OnPreExecute prefetches Post, Comment and Revision for later use
+func (action *RevertCommentRevision) OnPreExecute(ctx context.Context) error {
+	getPost := &query.GetPostByNumber{Number: action.PostNumber}
+	getComment := &query.GetCommentByID{CommentID: action.ID}
+	getRevision := &query.GetCommentRevision{CommentID: action.ID, Version: action.Version}
+	if err := bus.Dispatch(ctx, getPost, getComment, getRevision); err != nil {
+		return err
+	}
+
+	if getComment.Result.PostID != getPost.Result.ID {
+		return app.ErrNotFound
+	}
+
+	action.Post = getPost.Result
+	action.Comment = getComment.Result
+	action.Revision = getRevision.Result
+	return nil
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *RevertCommentRevision) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.IsCollaborator()
+}
+
+// Validate if current model is valid
+func (action *RevertCommentRevision) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	return validate.Success()
+}
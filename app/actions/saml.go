@@ -0,0 +1,116 @@
+package actions
+
+import (
+	"context"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/saml"
+	"github.com/getfider/fider/app/pkg/validate"
+)
+
+// SaveSAMLConfig is used to configure the SAML Identity Provider of a tenant
+// IdP settings can either be given individually or extracted from an uploaded IdP metadata file
+type SaveSAMLConfig struct {
+	Metadata       string `json:"metadata"`
+	Status         int    `json:"status"`
+	DisplayName    string `json:"displayName"`
+	IdPEntityID    string `json:"idpEntityID"`
+	IdPSSOURL      string `json:"idpSSOURL"`
+	IdPCertificate string `json:"idpCertificate"`
+	NameAttribute  string `json:"nameAttribute"`
+	EmailAttribute string `json:"emailAttribute"`
+	RolesAttribute string `json:"rolesAttribute"`
+	AllowedRoles   string `json:"allowedRoles"`
+	IsTrusted      bool   `json:"isTrusted"`
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *SaveSAMLConfig) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.IsAdministrator()
+}
+
+// Validate if current model is valid
+func (action *SaveSAMLConfig) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	if action.Status == enum.OAuthConfigDisabled {
+		tenant := ctx.Value(app.TenantCtxKey).(*entity.Tenant)
+		activeProviders := &query.ListActiveOAuthProviders{}
+		if err := bus.Dispatch(ctx, activeProviders); err != nil {
+			return validate.Failed("Cannot retrieve OAuth providers")
+		}
+
+		otherProviders := 0
+		for _, p := range activeProviders.Result {
+			if p.Provider != app.SAMLProvider {
+				otherProviders++
+			}
+		}
+
+		if !tenant.IsEmailAuthAllowed && otherProviders == 0 {
+			result.AddFieldFailure("status", "You cannot disable SAML with neither email auth nor any other provider enabled.")
+		}
+	}
+
+	if action.Metadata != "" {
+		metadata, err := saml.ParseIdPMetadata([]byte(action.Metadata))
+		if err != nil {
+			result.AddFieldFailure("metadata", "IdP metadata is invalid: "+err.Error())
+			return result
+		}
+		action.IdPEntityID = metadata.EntityID
+		action.IdPSSOURL = metadata.SSOURL
+		action.IdPCertificate = metadata.Certificate
+	}
+
+	if action.Status != enum.OAuthConfigEnabled &&
+		action.Status != enum.OAuthConfigDisabled {
+		result.AddFieldFailure("status", "Invalid status.")
+	}
+
+	if action.DisplayName == "" {
+		result.AddFieldFailure("displayName", "Display Name is required.")
+	} else if len(action.DisplayName) > 50 {
+		result.AddFieldFailure("displayName", "Display Name must have less than 50 characters.")
+	}
+
+	if action.IdPEntityID == "" {
+		result.AddFieldFailure("idpEntityID", "IdP Entity ID is required.")
+	} else if len(action.IdPEntityID) > 300 {
+		result.AddFieldFailure("idpEntityID", "IdP Entity ID must have less than 300 characters.")
+	}
+
+	if action.IdPSSOURL == "" {
+		result.AddFieldFailure("idpSSOURL", "IdP SSO URL is required.")
+	} else if messages := validate.URL(ctx, action.IdPSSOURL); len(messages) > 0 {
+		result.AddFieldFailure("idpSSOURL", messages...)
+	}
+
+	if action.IdPCertificate == "" {
+		result.AddFieldFailure("idpCertificate", "IdP Certificate is required.")
+	} else if _, err := saml.ParseCertificate(action.IdPCertificate); err != nil {
+		result.AddFieldFailure("idpCertificate", "IdP Certificate is invalid.")
+	}
+
+	if len(action.NameAttribute) > 100 {
+		result.AddFieldFailure("nameAttribute", "Name Attribute must have less than 100 characters.")
+	}
+
+	if len(action.EmailAttribute) > 100 {
+		result.AddFieldFailure("emailAttribute", "Email Attribute must have less than 100 characters.")
+	}
+
+	if len(action.RolesAttribute) > 100 {
+		result.AddFieldFailure("rolesAttribute", "Roles Attribute must have less than 100 characters.")
+	}
+
+	if len(action.AllowedRoles) > 500 {
+		result.AddFieldFailure("allowedRoles", "Allowed Roles must have less than 500 characters.")
+	}
+
+	return result
+}
@@ -0,0 +1,73 @@
+package actions_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/actions"
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/rand"
+)
+
+func TestSaveSAMLConfig_InvalidInput(t *testing.T) {
+	RegisterT(t)
+
+	testCases := []struct {
+		expected []string
+		action   *actions.SaveSAMLConfig
+	}{
+		{
+			expected: []string{"status", "displayName", "idpEntityID", "idpSSOURL", "idpCertificate"},
+			action:   &actions.SaveSAMLConfig{},
+		},
+		{
+			expected: []string{"displayName", "idpEntityID", "idpSSOURL", "idpCertificate", "nameAttribute", "emailAttribute", "rolesAttribute", "allowedRoles"},
+			action: &actions.SaveSAMLConfig{
+				Status:         enum.OAuthConfigEnabled,
+				DisplayName:    rand.String(51),
+				IdPEntityID:    rand.String(301),
+				IdPSSOURL:      rand.String(301),
+				IdPCertificate: "not a certificate",
+				NameAttribute:  rand.String(101),
+				EmailAttribute: rand.String(101),
+				RolesAttribute: rand.String(101),
+				AllowedRoles:   rand.String(501),
+			},
+		},
+		{
+			expected: []string{"metadata"},
+			action: &actions.SaveSAMLConfig{
+				Status:   enum.OAuthConfigEnabled,
+				Metadata: "<html></html>",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		result := testCase.action.Validate(context.Background(), nil)
+		ExpectFailed(result, testCase.expected...)
+	}
+}
+
+func TestSaveSAMLConfig_CannotDisableLastProvider(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.ListActiveOAuthProviders) error {
+		q.Result = []*dto.OAuthProviderOption{{Provider: app.SAMLProvider, IsEnabled: true}}
+		return nil
+	})
+
+	ctx := context.WithValue(context.Background(), app.TenantCtxKey, &entity.Tenant{
+		IsEmailAuthAllowed: false,
+	})
+
+	action := &actions.SaveSAMLConfig{Status: enum.OAuthConfigDisabled}
+	result := action.Validate(ctx, nil)
+	ExpectFailed(result, "status", "displayName", "idpEntityID", "idpSSOURL", "idpCertificate")
+}
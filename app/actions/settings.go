@@ -60,6 +60,13 @@ func (action *UpdateUserSettings) Validate(ctx context.Context, user *entity.Use
 
 	if action.Settings != nil {
 		for k, v := range action.Settings {
+			if k == enum.EmailDigestSettingsKeyName {
+				if !enum.EmailDigestFrequency(v).IsValid() {
+					result.AddFieldFailure("settings", i18n.T(ctx, "validation.invalidvalue", i18n.Params{"name": k}, i18n.Params{"value": v}))
+				}
+				continue
+			}
+
 			ok := false
 			for _, e := range enum.AllNotificationEvents {
 				if e.UserSettingsKeyName == k {
@@ -51,6 +51,9 @@ func TestInvalidSettings(t *testing.T) {
 		{
 			enum.NotificationEventNewComment.UserSettingsKeyName: "4",
 		},
+		{
+			enum.EmailDigestSettingsKeyName: "hourly",
+		},
 	} {
 		action := actions.NewUpdateUserSettings()
 		action.Name = "John Snow"
@@ -73,6 +76,9 @@ func TestValidSettings(t *testing.T) {
 		{
 			enum.NotificationEventNewComment.UserSettingsKeyName: enum.NotificationEventNewComment.DefaultSettingValue,
 		},
+		{
+			enum.EmailDigestSettingsKeyName: string(enum.EmailDigestWeekly),
+		},
 	} {
 		action := actions.NewUpdateUserSettings()
 		action.Name = "John Snow"
@@ -300,6 +300,34 @@ func (action *UpdateTenantPrivacySettings) Validate(ctx context.Context, user *e
 	return validate.Success()
 }
 
+// UpdateTenantVotingSettings is the input model used to update tenant voting settings
+type UpdateTenantVotingSettings struct {
+	VoteBudget      int `json:"voteBudget"`
+	MaxVotesPerPost int `json:"maxVotesPerPost"`
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *UpdateTenantVotingSettings) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.Role == enum.RoleAdministrator
+}
+
+// Validate if current model is valid
+func (action *UpdateTenantVotingSettings) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	if action.VoteBudget < 0 {
+		result.AddFieldFailure("voteBudget", "Vote budget cannot be negative.")
+	}
+
+	if action.MaxVotesPerPost < 1 {
+		result.AddFieldFailure("maxVotesPerPost", "Users must be able to place at least one vote per post.")
+	} else if action.VoteBudget > 0 && action.MaxVotesPerPost > action.VoteBudget {
+		result.AddFieldFailure("maxVotesPerPost", "Votes per post cannot be greater than the vote budget.")
+	}
+
+	return result
+}
+
 // UpdateTenantEmailAuthAllowed is the input model used to update tenant privacy settings
 type UpdateTenantEmailAuthAllowed struct {
 	IsEmailAuthAllowed bool `json:"isEmailAuthAllowed"`
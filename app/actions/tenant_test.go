@@ -181,3 +181,22 @@ func TestUpdateTenantSettings_ExistingTenant_WithLogo(t *testing.T) {
 	ExpectSuccess(result)
 	Expect(action.Logo.BlobKey).Equals("hello-world.png")
 }
+
+func TestUpdateTenantVotingSettings(t *testing.T) {
+	RegisterT(t)
+
+	action := &actions.UpdateTenantVotingSettings{VoteBudget: -1, MaxVotesPerPost: 1}
+	ExpectFailed(action.Validate(context.Background(), nil), "voteBudget")
+
+	action = &actions.UpdateTenantVotingSettings{VoteBudget: 10, MaxVotesPerPost: 0}
+	ExpectFailed(action.Validate(context.Background(), nil), "maxVotesPerPost")
+
+	action = &actions.UpdateTenantVotingSettings{VoteBudget: 5, MaxVotesPerPost: 6}
+	ExpectFailed(action.Validate(context.Background(), nil), "maxVotesPerPost")
+
+	action = &actions.UpdateTenantVotingSettings{VoteBudget: 10, MaxVotesPerPost: 3}
+	ExpectSuccess(action.Validate(context.Background(), nil))
+
+	action = &actions.UpdateTenantVotingSettings{VoteBudget: 0, MaxVotesPerPost: 1}
+	ExpectSuccess(action.Validate(context.Background(), nil))
+}
@@ -2,6 +2,7 @@ package actions
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/getfider/fider/app"
 	"github.com/getfider/fider/app/models/entity"
@@ -53,6 +54,43 @@ func (action *CreateUser) Validate(ctx context.Context, user *entity.User) *vali
 	return result
 }
 
+// SetUserAttributes is the input model used to set attributes of an user
+type SetUserAttributes struct {
+	UserID     int               `route:"userID"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *SetUserAttributes) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.IsAdministrator()
+}
+
+// Validate if current model is valid
+func (action *SetUserAttributes) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	if len(action.Attributes) == 0 {
+		result.AddFieldFailure("attributes", "At least one attribute is required.")
+	} else if len(action.Attributes) > 50 {
+		result.AddFieldFailure("attributes", "A maximum of 50 attributes can be set at once.")
+	}
+
+	for key, value := range action.Attributes {
+		if !entity.IsValidUserAttributeKey(key) {
+			result.AddFieldFailure("attributes", fmt.Sprintf("Attribute '%s' must have up to 50 lowercase letters, numbers or underscores.", key))
+		} else if len(value) > 200 {
+			result.AddFieldFailure("attributes", fmt.Sprintf("Attribute '%s' must have less than 200 characters.", key))
+		}
+	}
+
+	userByID := &query.GetUserByID{UserID: action.UserID}
+	if err := bus.Dispatch(ctx, userByID); err != nil {
+		return validate.Error(err)
+	}
+
+	return result
+}
+
 //ChangeUserRole is the input model change role of an user
 type ChangeUserRole struct {
 	Role   enum.Role `route:"role"`
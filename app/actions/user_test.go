@@ -189,3 +189,61 @@ func TestChangeUserRole_CurrentUser(t *testing.T) {
 	result := action.Validate(context.Background(), currentUser)
 	ExpectFailed(result, "userID")
 }
+
+func TestSetUserAttributes_Unauthorized(t *testing.T) {
+	RegisterT(t)
+
+	for _, user := range []*entity.User{
+		nil,
+		{ID: 1, Role: enum.RoleVisitor},
+		{ID: 1, Role: enum.RoleCollaborator},
+	} {
+		action := actions.SetUserAttributes{UserID: 2}
+		Expect(action.IsAuthorized(context.Background(), user)).IsFalse()
+	}
+}
+
+func TestSetUserAttributes_InvalidInput(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByID) error {
+		q.Result = &entity.User{ID: q.UserID}
+		return nil
+	})
+
+	for _, attributes := range []map[string]string{
+		nil,
+		{"Monthly Revenue": "1200"},
+		{"plan-name": "pro"},
+		{"plan": rand.String(201)},
+	} {
+		action := actions.SetUserAttributes{UserID: 2, Attributes: attributes}
+		result := action.Validate(context.Background(), nil)
+		ExpectFailed(result, "attributes")
+	}
+}
+
+func TestSetUserAttributes_ValidInput(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByID) error {
+		q.Result = &entity.User{ID: q.UserID}
+		return nil
+	})
+
+	action := actions.SetUserAttributes{UserID: 2, Attributes: map[string]string{"plan": "pro", "mrr": "1200", "region": ""}}
+	result := action.Validate(context.Background(), nil)
+	ExpectSuccess(result)
+}
+
+func TestSetUserAttributes_UnknownUser(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByID) error {
+		return app.ErrNotFound
+	})
+
+	action := actions.SetUserAttributes{UserID: 999, Attributes: map[string]string{"plan": "pro"}}
+	result := action.Validate(context.Background(), nil)
+	Expect(result.Err).Equals(app.ErrNotFound)
+}
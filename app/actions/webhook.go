@@ -36,10 +36,7 @@ func (action *CreateEditWebhook) Validate(ctx context.Context, _ *entity.User) *
 
 	if action.Type == 0 {
 		result.AddFieldFailure("type", "Type is required.")
-	} else if action.Type != enum.WebhookNewPost &&
-		action.Type != enum.WebhookNewComment &&
-		action.Type != enum.WebhookChangeStatus &&
-		action.Type != enum.WebhookDeletePost {
+	} else if !action.Type.IsValid() {
 		result.AddFieldFailure("type", "Type must be valid.")
 	}
 
@@ -126,10 +123,7 @@ func (action *PreviewWebhook) Validate(context.Context, *entity.User) *validate.
 
 	if action.Type == 0 {
 		result.AddFieldFailure("type", "Type is required.")
-	} else if action.Type != enum.WebhookNewPost &&
-		action.Type != enum.WebhookNewComment &&
-		action.Type != enum.WebhookChangeStatus &&
-		action.Type != enum.WebhookDeletePost {
+	} else if !action.Type.IsValid() {
 		result.AddFieldFailure("type", "Type must be valid.")
 	}
 
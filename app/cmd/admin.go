@@ -0,0 +1,389 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/actions"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/env"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/log"
+	"github.com/getfider/fider/app/pkg/rand"
+	"github.com/getfider/fider/app/pkg/validate"
+	"github.com/getfider/fider/app/pkg/web"
+	"github.com/getfider/fider/app/pkg/worker"
+	"github.com/getfider/fider/app/tasks"
+)
+
+type adminCommand struct {
+	name        string
+	params      []string
+	description string
+	// onSite commands act on a single site, which is identified by a trailing subdomain argument on multi host mode
+	onSite bool
+	run    func(ctx context.Context, subdomain string, args []string) error
+}
+
+var adminCommands = []*adminCommand{
+	{name: "tenants list", description: "List all sites and their status", run: adminListTenants},
+	{name: "tenants create", params: []string{"<name>", "<admin email>", "<admin name>"}, onSite: true, description: "Create a site along with its first administrator", run: adminCreateTenant},
+	{name: "tenants disable", onSite: true, description: "Block all access to a site", run: adminSetTenantStatus(enum.TenantDisabled)},
+	{name: "tenants enable", onSite: true, description: "Allow access to a disabled site again", run: adminSetTenantStatus(enum.TenantActive)},
+	{name: "users promote", params: []string{"<email>"}, onSite: true, description: "Make a user an administrator, unblocking it if needed", run: adminPromoteUser},
+	{name: "users purge", params: []string{"<email>"}, onSite: true, description: "Delete a user, its content is kept anonymously", run: adminPurgeUser},
+	{name: "oauth reset", onSite: true, description: "Disable custom OAuth providers and allow sign in by email again", run: adminResetOAuth},
+	{name: "search regenerate", onSite: true, description: "Detect the language of all posts again and regenerate their search column and similarity index", run: adminRegenerateSearch},
+	{name: "verifications resend", onSite: true, description: "Resend the emails of all pending verifications", run: adminResendVerifications},
+}
+
+// RunAdmin executes an operational task directly against the database.
+// Returns an exitcode, 0 for OK and 1 for ERROR
+func RunAdmin(args []string) int {
+	command, subdomain, params, ok := parseAdminArgs(args)
+	if !ok {
+		fmt.Print(adminUsage())
+		return 1
+	}
+
+	bus.Init()
+
+	ctx := log.WithProperties(context.Background(), dto.Props{
+		log.PropertyKeyTag:       "ADMIN",
+		log.PropertyKeyContextID: rand.String(32),
+	})
+
+	trx, err := dbx.BeginTx(ctx)
+	if err != nil {
+		log.Error(ctx, err)
+		return 1
+	}
+	ctx = context.WithValue(ctx, app.TransactionCtxKey, trx)
+
+	if err := command.run(ctx, subdomain, params); err != nil {
+		trx.MustRollback()
+		log.Error(ctx, err)
+		return 1
+	}
+
+	trx.MustCommit()
+	return 0
+}
+
+func parseAdminArgs(args []string) (*adminCommand, string, []string, bool) {
+	if len(args) < 2 {
+		return nil, "", nil, false
+	}
+
+	for _, command := range adminCommands {
+		if command.name != args[0]+" "+args[1] {
+			continue
+		}
+
+		params := args[2:]
+		subdomain := ""
+		if command.onSite && !env.IsSingleHostMode() {
+			if len(params) != len(command.params)+1 {
+				return nil, "", nil, false
+			}
+			subdomain = params[len(params)-1]
+			params = params[:len(params)-1]
+		}
+
+		if len(params) != len(command.params) {
+			return nil, "", nil, false
+		}
+		return command, subdomain, params, true
+	}
+
+	return nil, "", nil, false
+}
+
+func adminUsage() string {
+	var sb strings.Builder
+	sb.WriteString("Usage: fider admin <command>\n\nCommands:\n")
+
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	for _, command := range adminCommands {
+		usage := strings.Join(append([]string{command.name}, command.params...), " ")
+		if command.onSite && !env.IsSingleHostMode() {
+			usage += " <subdomain>"
+		}
+		fmt.Fprintf(w, "  %s\t%s\n", usage, command.description)
+	}
+	_ = w.Flush()
+
+	return sb.String()
+}
+
+// withAdminTenant returns a context for the site with given subdomain, or the only site on single host mode.
+// Jobs don't have a request, so the links sent by email are built from the site address
+func withAdminTenant(ctx context.Context, subdomain string) (context.Context, error) {
+	var tenant *entity.Tenant
+	if env.IsSingleHostMode() {
+		getFirstTenant := &query.GetFirstTenant{}
+		if err := bus.Dispatch(ctx, getFirstTenant); err != nil {
+			return nil, errors.Wrap(err, "failed to get site")
+		}
+		tenant = getFirstTenant.Result
+	} else {
+		getTenant := &query.GetTenantByDomain{Domain: subdomain}
+		if err := bus.Dispatch(ctx, getTenant); err != nil {
+			return nil, errors.Wrap(err, "failed to get site '%s'", subdomain)
+		}
+		tenant = getTenant.Result
+	}
+
+	baseURL := env.Config.BaseURL
+	if !env.IsSingleHostMode() {
+		baseURL = "https://" + tenant.Subdomain + env.MultiTenantDomain()
+		if tenant.CNAME != "" {
+			baseURL = "https://" + tenant.CNAME
+		}
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse base url '%s'", baseURL)
+	}
+
+	ctx = context.WithValue(ctx, app.TenantCtxKey, tenant)
+	ctx = context.WithValue(ctx, app.LocaleCtxKey, tenant.Locale)
+	ctx = context.WithValue(ctx, app.RequestCtxKey, web.Request{URL: u})
+	ctx = log.WithProperty(ctx, log.PropertyKeyTenantID, tenant.ID)
+	return ctx, nil
+}
+
+func getAdminUser(ctx context.Context, email string) (*entity.User, error) {
+	getUser := &query.GetUserByEmail{Email: email}
+	if err := bus.Dispatch(ctx, getUser); err != nil {
+		if errors.Cause(err) == app.ErrNotFound {
+			return nil, errors.New("user with email '%s' was not found", email)
+		}
+		return nil, err
+	}
+	return getUser.Result, nil
+}
+
+func adminListTenants(ctx context.Context, _ string, _ []string) error {
+	getTenants := &query.GetAllTenants{}
+	if err := bus.Dispatch(ctx, getTenants); err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSUBDOMAIN\tCNAME\tSTATUS\tNAME")
+	for _, tenant := range getTenants.Result {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", tenant.ID, tenant.Subdomain, tenant.CNAME, tenant.Status, tenant.Name)
+	}
+	return w.Flush()
+}
+
+func adminCreateTenant(ctx context.Context, subdomain string, args []string) error {
+	name, email, userName := args[0], args[1], args[2]
+
+	if env.IsSingleHostMode() {
+		err := bus.Dispatch(ctx, &query.GetFirstTenant{})
+		if err == nil {
+			return errors.New("a site already exists, only one site is allowed on single host mode")
+		} else if errors.Cause(err) != app.ErrNotFound {
+			return err
+		}
+		subdomain = "default"
+	} else {
+		messages, err := validate.Subdomain(ctx, subdomain)
+		if err != nil {
+			return err
+		}
+		if len(messages) > 0 {
+			return errors.New("%s", strings.Join(messages, " "))
+		}
+	}
+
+	if messages := validate.Email(ctx, email); len(messages) > 0 {
+		return errors.New("%s", strings.Join(messages, " "))
+	}
+
+	createTenant := &cmd.CreateTenant{Name: name, Subdomain: subdomain, Status: enum.TenantActive}
+	if err := bus.Dispatch(ctx, createTenant); err != nil {
+		return err
+	}
+
+	ctx = context.WithValue(ctx, app.TenantCtxKey, createTenant.Result)
+	user := &entity.User{
+		Name:   userName,
+		Email:  email,
+		Tenant: createTenant.Result,
+		Role:   enum.RoleAdministrator,
+	}
+	if err := bus.Dispatch(ctx, &cmd.RegisterUser{User: user}); err != nil {
+		return err
+	}
+
+	fmt.Printf("Site '%s' created with id %d, %s can now sign in by email.\n", name, createTenant.Result.ID, email)
+	return nil
+}
+
+func adminSetTenantStatus(status enum.TenantStatus) func(ctx context.Context, subdomain string, args []string) error {
+	return func(ctx context.Context, subdomain string, _ []string) error {
+		ctx, err := withAdminTenant(ctx, subdomain)
+		if err != nil {
+			return err
+		}
+
+		tenant := ctx.Value(app.TenantCtxKey).(*entity.Tenant)
+		if status == enum.TenantDisabled {
+			err = bus.Dispatch(ctx, &cmd.DisableTenant{TenantID: tenant.ID})
+		} else {
+			err = bus.Dispatch(ctx, &cmd.ActivateTenant{TenantID: tenant.ID})
+		}
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Site '%s' is now %s.\n", tenant.Name, status)
+		return nil
+	}
+}
+
+func adminPromoteUser(ctx context.Context, subdomain string, args []string) error {
+	ctx, err := withAdminTenant(ctx, subdomain)
+	if err != nil {
+		return err
+	}
+
+	user, err := getAdminUser(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	if user.Status == enum.UserBlocked {
+		if err := bus.Dispatch(ctx, &cmd.UnblockUser{UserID: user.ID}); err != nil {
+			return err
+		}
+	}
+
+	if err := bus.Dispatch(ctx, &cmd.ChangeUserRole{UserID: user.ID, Role: enum.RoleAdministrator}); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s is now an administrator.\n", user.Email)
+	return nil
+}
+
+func adminPurgeUser(ctx context.Context, subdomain string, args []string) error {
+	ctx, err := withAdminTenant(ctx, subdomain)
+	if err != nil {
+		return err
+	}
+
+	user, err := getAdminUser(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	if err := bus.Dispatch(ctx, &cmd.DeleteUser{UserID: user.ID}); err != nil {
+		return err
+	}
+
+	fmt.Printf("User %d was deleted.\n", user.ID)
+	return nil
+}
+
+func adminResetOAuth(ctx context.Context, subdomain string, _ []string) error {
+	ctx, err := withAdminTenant(ctx, subdomain)
+	if err != nil {
+		return err
+	}
+
+	resetOAuth := &cmd.ResetOAuthConfig{}
+	if err := bus.Dispatch(ctx, resetOAuth); err != nil {
+		return err
+	}
+
+	fmt.Printf("%d custom OAuth providers were disabled and sign in by email is allowed.\n", resetOAuth.Result)
+	return nil
+}
+
+func adminRegenerateSearch(ctx context.Context, subdomain string, _ []string) error {
+	ctx, err := withAdminTenant(ctx, subdomain)
+	if err != nil {
+		return err
+	}
+
+	regenerate := &cmd.RegeneratePostsSearch{}
+	if err := bus.Dispatch(ctx, regenerate); err != nil {
+		return err
+	}
+
+	fmt.Printf("Search regenerated for %d posts.\n", regenerate.Result)
+	return nil
+}
+
+// pendingSignUp exposes a pending sign up verification as the data needed by the sign up email
+type pendingSignUp struct {
+	*entity.EmailVerification
+}
+
+func (p pendingSignUp) GetEmail() string           { return p.Email }
+func (p pendingSignUp) GetName() string            { return p.Name }
+func (p pendingSignUp) GetVerificationKey() string { return p.Key }
+
+func adminResendVerifications(ctx context.Context, subdomain string, _ []string) error {
+	ctx, err := withAdminTenant(ctx, subdomain)
+	if err != nil {
+		return err
+	}
+
+	getVerifications := &query.GetPendingVerifications{}
+	if err := bus.Dispatch(ctx, getVerifications); err != nil {
+		return err
+	}
+
+	sent, skipped := 0, 0
+	for _, verification := range getVerifications.Result {
+		var task worker.Task
+		taskCtx := ctx
+
+		switch verification.Kind {
+		case enum.EmailVerificationKindSignIn:
+			task = tasks.SendSignInEmail(verification.Email, verification.Key, verification.Code)
+		case enum.EmailVerificationKindSignUp:
+			task = tasks.SendSignUpEmail(pendingSignUp{verification}, web.BaseURL(ctx))
+		case enum.EmailVerificationKindChangeEmail:
+			getUser := &query.GetUserByID{UserID: verification.UserID}
+			if err := bus.Dispatch(ctx, getUser); err != nil {
+				return err
+			}
+			taskCtx = context.WithValue(ctx, app.UserCtxKey, getUser.Result)
+			task = tasks.SendChangeEmailConfirmation(&actions.ChangeUserEmail{
+				Email:           verification.Email,
+				VerificationKey: verification.Key,
+				Requestor:       getUser.Result,
+			})
+		default:
+			// The message of an invitation is not stored, so it can't be sent again
+			skipped++
+			continue
+		}
+
+		if err := task.Job(worker.NewContext(taskCtx, "admin", task)); err != nil {
+			return err
+		}
+		sent++
+	}
+
+	fmt.Printf("%d verification emails were sent again, %d invitations were skipped.\n", sent, skipped)
+	return nil
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/mock"
+)
+
+func TestParseAdminArgs(t *testing.T) {
+	RegisterT(t)
+
+	command, subdomain, params, ok := parseAdminArgs([]string{"users", "promote", "jon.snow@got.com", "demo"})
+	Expect(ok).IsTrue()
+	Expect(command.name).Equals("users promote")
+	Expect(subdomain).Equals("demo")
+	Expect(params).Equals([]string{"jon.snow@got.com"})
+
+	command, subdomain, params, ok = parseAdminArgs([]string{"tenants", "list"})
+	Expect(ok).IsTrue()
+	Expect(command.name).Equals("tenants list")
+	Expect(subdomain).Equals("")
+	Expect(params).HasLen(0)
+
+	_, _, _, ok = parseAdminArgs([]string{"users", "promote", "jon.snow@got.com"})
+	Expect(ok).IsFalse()
+
+	_, _, _, ok = parseAdminArgs([]string{"users", "unknown", "demo"})
+	Expect(ok).IsFalse()
+
+	_, _, _, ok = parseAdminArgs([]string{"users"})
+	Expect(ok).IsFalse()
+}
+
+func TestAdminPromoteUser(t *testing.T) {
+	RegisterT(t)
+
+	user := &entity.User{ID: 4, Email: "arya.stark@got.com", Role: enum.RoleVisitor, Status: enum.UserBlocked}
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetTenantByDomain) error {
+		if q.Domain == "demo" {
+			q.Result = mock.DemoTenant
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByEmail) error {
+		if q.Email == user.Email && ctx.Value(app.TenantCtxKey) == mock.DemoTenant {
+			q.Result = user
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	var unblockUser *cmd.UnblockUser
+	bus.AddHandler(func(ctx context.Context, c *cmd.UnblockUser) error {
+		unblockUser = c
+		return nil
+	})
+
+	var changeRole *cmd.ChangeUserRole
+	bus.AddHandler(func(ctx context.Context, c *cmd.ChangeUserRole) error {
+		changeRole = c
+		return nil
+	})
+
+	err := adminPromoteUser(context.Background(), "demo", []string{user.Email})
+	Expect(err).IsNil()
+	Expect(unblockUser.UserID).Equals(user.ID)
+	Expect(changeRole.UserID).Equals(user.ID)
+	Expect(changeRole.Role).Equals(enum.RoleAdministrator)
+
+	err = adminPromoteUser(context.Background(), "demo", []string{"unknown@got.com"})
+	Expect(err).IsNotNil()
+
+	err = adminPromoteUser(context.Background(), "unknown", []string{user.Email})
+	Expect(err).IsNotNil()
+}
+
+func TestAdminSetTenantStatus(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetTenantByDomain) error {
+		q.Result = mock.DemoTenant
+		return nil
+	})
+
+	var disableTenant *cmd.DisableTenant
+	bus.AddHandler(func(ctx context.Context, c *cmd.DisableTenant) error {
+		disableTenant = c
+		return nil
+	})
+
+	var activateTenant *cmd.ActivateTenant
+	bus.AddHandler(func(ctx context.Context, c *cmd.ActivateTenant) error {
+		activateTenant = c
+		return nil
+	})
+
+	err := adminSetTenantStatus(enum.TenantDisabled)(context.Background(), "demo", nil)
+	Expect(err).IsNil()
+	Expect(disableTenant.TenantID).Equals(mock.DemoTenant.ID)
+	Expect(activateTenant).IsNil()
+
+	err = adminSetTenantStatus(enum.TenantActive)(context.Background(), "demo", nil)
+	Expect(err).IsNil()
+	Expect(activateTenant.TenantID).Equals(mock.DemoTenant.ID)
+}
@@ -2,18 +2,52 @@ package cmd
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
 
 	"github.com/getfider/fider/app/models/dto"
 	"github.com/getfider/fider/app/pkg/bus"
 	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
 	"github.com/getfider/fider/app/pkg/log"
 	"github.com/getfider/fider/app/pkg/rand"
 	_ "github.com/getfider/fider/app/services/log/console"
 )
 
-// RunMigrate run all pending migrations on current DATABASE_URL
+const migrateUsage = `Usage:
+  fider migrate           Apply all pending migrations
+  fider migrate status    List applied and pending migrations
+  fider migrate down <N>  Revert the last N applied migrations
+`
+
+// RunMigrate run all pending migrations on current DATABASE_URL,
+// or lists their status or reverts the last ones, see migrateUsage
 // Returns an exitcode, 0 for OK and 1 for ERROR
-func RunMigrate() int {
+func RunMigrate(args []string) int {
+	var run func(ctx context.Context) error
+	switch {
+	case len(args) == 0:
+		run = func(ctx context.Context) error {
+			return dbx.Migrate(ctx, "/migrations")
+		}
+	case len(args) == 1 && args[0] == "status":
+		run = printMigrationStatus
+	case len(args) == 2 && args[0] == "down":
+		count, err := strconv.Atoi(args[1])
+		if err != nil || count <= 0 {
+			fmt.Print(migrateUsage)
+			return 1
+		}
+		run = func(ctx context.Context) error {
+			return dbx.MigrateDown(ctx, "/migrations", count)
+		}
+	default:
+		fmt.Print(migrateUsage)
+		return 1
+	}
+
 	bus.Init()
 
 	ctx := log.WithProperties(context.Background(), dto.Props{
@@ -21,10 +55,49 @@ func RunMigrate() int {
 		log.PropertyKeyContextID: rand.String(32),
 	})
 
-	err := dbx.Migrate(ctx, "/migrations")
+	err := run(ctx)
 	if err != nil {
 		log.Error(ctx, err)
 		return 1
 	}
 	return 0
 }
+
+// printMigrationStatus lists all migrations and fails when an applied migration was modified or is missing
+func printMigrationStatus(ctx context.Context) error {
+	migrations, err := dbx.GetMigrationStatus(ctx, "/migrations")
+	if err != nil {
+		return err
+	}
+
+	pending, changed := 0, 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tSTATUS\tREVERSIBLE\tCHECKSUM\tFILE")
+	for _, m := range migrations {
+		checksum := m.Checksum
+		if len(checksum) > 12 {
+			checksum = checksum[:12]
+		}
+
+		reversible := "no"
+		if m.Reversible {
+			reversible = "yes"
+		}
+
+		switch m.Status {
+		case dbx.MigrationPending:
+			pending++
+		case dbx.MigrationModified, dbx.MigrationMissing:
+			changed++
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", m.Version, m.Status, reversible, checksum, m.FileName)
+	}
+	_ = w.Flush()
+
+	fmt.Printf("\n%d migrations, %d pending.\n", len(migrations), pending)
+	if changed > 0 {
+		return errors.Wrap(dbx.ErrMigrationModified, "%d applied migrations were modified or are missing", changed)
+	}
+	return nil
+}
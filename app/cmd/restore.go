@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/backup"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/env"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/log"
+	"github.com/getfider/fider/app/pkg/rand"
+	"github.com/getfider/fider/app/pkg/validate"
+)
+
+// RunRestore restores a backup archive created by Fider.
+// On multi host mode the archive is restored into the site with given subdomain,
+// while on single host mode it's restored into the only site. The site is created when it doesn't exist yet.
+// Returns an exitcode, 0 for OK and 1 for ERROR
+func RunRestore(args []string) int {
+	subdomain := ""
+	if env.IsSingleHostMode() && len(args) != 1 {
+		fmt.Println("Usage: fider restore <file>")
+		return 1
+	} else if !env.IsSingleHostMode() {
+		if len(args) != 2 {
+			fmt.Println("Usage: fider restore <file> <subdomain>")
+			return 1
+		}
+		subdomain = args[1]
+	}
+
+	bus.Init()
+
+	ctx := log.WithProperties(context.Background(), dto.Props{
+		log.PropertyKeyTag:       "RESTORE",
+		log.PropertyKeyContextID: rand.String(32),
+	})
+
+	archive, err := os.ReadFile(args[0])
+	if err != nil {
+		log.Error(ctx, errors.Wrap(err, "failed to read %s", args[0]))
+		return 1
+	}
+
+	trx, err := dbx.BeginTx(ctx)
+	if err != nil {
+		log.Error(ctx, err)
+		return 1
+	}
+	ctx = context.WithValue(ctx, app.TransactionCtxKey, trx)
+
+	summary, err := restoreArchive(ctx, archive, subdomain)
+	if err != nil {
+		trx.MustRollback()
+		log.Error(ctx, err)
+		return 1
+	}
+
+	trx.MustCommit()
+	log.Infof(ctx, "Backup restored with @{Rows} rows and @{Blobs} blobs.", dto.Props{
+		"Rows":  summary.Rows,
+		"Blobs": summary.Blobs,
+	})
+	return 0
+}
+
+func restoreArchive(ctx context.Context, archive []byte, subdomain string) (*backup.RestoreSummary, error) {
+	var tenant *entity.Tenant
+	var err error
+	if env.IsSingleHostMode() {
+		getFirstTenant := &query.GetFirstTenant{}
+		err = bus.Dispatch(ctx, getFirstTenant)
+		tenant = getFirstTenant.Result
+	} else {
+		getTenant := &query.GetTenantByDomain{Domain: subdomain}
+		err = bus.Dispatch(ctx, getTenant)
+		tenant = getTenant.Result
+	}
+
+	if err == nil {
+		return backup.Restore(ctx, archive, tenant, false)
+	}
+	if errors.Cause(err) != app.ErrNotFound {
+		return nil, err
+	}
+
+	if !env.IsSingleHostMode() {
+		messages, err := validate.Subdomain(ctx, subdomain)
+		if err != nil {
+			return nil, err
+		}
+		if len(messages) > 0 {
+			return nil, errors.New("%s", strings.Join(messages, " "))
+		}
+	}
+
+	createTenant := &cmd.CreateTenant{Name: subdomain, Subdomain: subdomain, Status: enum.TenantActive}
+	if err := bus.Dispatch(ctx, createTenant); err != nil {
+		return nil, err
+	}
+
+	return backup.Restore(ctx, archive, createTenant.Result, true)
+}
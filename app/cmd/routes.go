@@ -6,6 +6,7 @@ import (
 
 	"github.com/getfider/fider/app/handlers"
 	"github.com/getfider/fider/app/handlers/apiv1"
+	"github.com/getfider/fider/app/handlers/scimv2"
 	"github.com/getfider/fider/app/handlers/webhooks"
 	"github.com/getfider/fider/app/middlewares"
 	"github.com/getfider/fider/app/models/enum"
@@ -77,6 +78,29 @@ func routes(r *web.Engine) *web.Engine {
 		stripeWh.Post("/webhooks/stripe", webhooks.IncomingStripeWebhook())
 	}
 
+	// SAML Assertion Consumer Service receives cross-site form posts from the IdP (before CSRF middleware)
+	samlAcs := r.Group()
+	{
+		samlAcs.Post("/saml/acs", handlers.SAMLAssertionConsumer())
+	}
+
+	// SCIM provisioning is authenticated by a bearer token and uses application/scim+json (before CSRF middleware)
+	scimApi := r.Group()
+	{
+		scimApi.Use(middlewares.SCIM())
+
+		scimApi.Get("/scim/v2/ServiceProviderConfig", scimv2.ServiceProviderConfig())
+		scimApi.Get("/scim/v2/Users", scimv2.ListUsers())
+		scimApi.Post("/scim/v2/Users", scimv2.CreateUser())
+		scimApi.Get("/scim/v2/Users/:id", scimv2.GetUser())
+		scimApi.Put("/scim/v2/Users/:id", scimv2.ReplaceUser())
+		scimApi.Patch("/scim/v2/Users/:id", scimv2.PatchUser())
+		scimApi.Delete("/scim/v2/Users/:id", scimv2.DeleteUser())
+		scimApi.Get("/scim/v2/Groups", scimv2.ListGroups())
+		scimApi.Get("/scim/v2/Groups/:id", scimv2.GetGroup())
+		scimApi.Patch("/scim/v2/Groups/:id", scimv2.PatchGroup())
+	}
+
 	r.Use(middlewares.CSRF())
 
 	r.Get("/terms", handlers.LegalPage("Terms of Service", "terms.md"))
@@ -112,6 +136,9 @@ func routes(r *web.Engine) *web.Engine {
 	r.Get("/signout", handlers.SignOut())
 	r.Get("/oauth/:provider/token", handlers.OAuthToken())
 	r.Get("/oauth/:provider/echo", handlers.OAuthEcho())
+	r.Get("/saml/metadata", handlers.SAMLMetadata())
+	r.Get("/saml/login", handlers.SignInBySAML())
+	r.Get("/saml/token", handlers.SAMLToken())
 
 	// If tenant is pending, block it from using any other route
 	r.Use(middlewares.BlockPendingTenants())
@@ -140,6 +167,8 @@ func routes(r *web.Engine) *web.Engine {
 
 	r.Get("/", handlers.Index())
 	r.Get("/roadmap", handlers.RoadmapPage())
+	r.Get("/b/:board", handlers.Index())
+	r.Get("/b/:board/roadmap", handlers.RoadmapPage())
 	r.Get("/posts/:number", handlers.PostDetails())
 	r.Get("/posts/:number/:slug", handlers.PostDetails())
 
@@ -156,6 +185,8 @@ func routes(r *web.Engine) *web.Engine {
 
 		ui.Delete("/_api/user", handlers.DeleteUser())
 		ui.Post("/_api/user/regenerate-apikey", handlers.RegenerateAPIKey())
+		ui.Post("/_api/user/tokens", handlers.CreateAPIToken())
+		ui.Delete("/_api/user/tokens/:id", handlers.RevokeAPIToken())
 		ui.Post("/_api/user/settings", handlers.UpdateUserSettings())
 		ui.Post("/_api/user/change-email", handlers.ChangeUserEmail())
 		ui.Post("/_api/notifications/read-all", handlers.ReadAllNotifications())
@@ -174,6 +205,7 @@ func routes(r *web.Engine) *web.Engine {
 		ui.Get("/admin/invitations", handlers.Page("Invitations · Site Settings", "", "Administration/pages/Invitations.page"))
 		ui.Get("/admin/users", handlers.ManageMembers())
 		ui.Get("/admin/tags", handlers.ManageTags())
+		ui.Get("/admin/duplicates", handlers.PossibleDuplicates())
 		ui.Get("/admin/authentication", handlers.ManageAuthentication())
 		ui.Get("/_api/admin/oauth/:provider", handlers.GetOAuthConfig())
 
@@ -207,12 +239,19 @@ func routes(r *web.Engine) *web.Engine {
 		ui.Get("/_api/admin/webhook/test/:id", handlers.TestWebhook())
 		ui.Post("/_api/admin/webhook/preview", handlers.PreviewWebhook())
 		ui.Get("/_api/admin/webhook/props/:type", handlers.GetWebhookProps())
+		ui.Get("/_api/admin/webhook/deliveries/:id", handlers.ListWebhookDeliveries())
+		ui.Post("/_api/admin/webhook/redeliver/:id/:deliveryId", handlers.RedeliverWebhook())
+		ui.Post("/_api/admin/webhook/rotate/:id", handlers.RotateWebhookSecret())
 		ui.Post("/_api/admin/settings/general", handlers.UpdateSettings())
 		ui.Post("/_api/admin/settings/advanced", handlers.UpdateAdvancedSettings())
 		ui.Post("/_api/admin/settings/privacy", handlers.UpdatePrivacySettings())
 		ui.Post("/_api/admin/settings/emailauth", handlers.UpdateEmailAuthAllowed())
+		ui.Post("/_api/admin/settings/voting", handlers.UpdateVotingSettings())
 		ui.Post("/_api/admin/oauth", handlers.SaveOAuthConfig())
 		ui.Post("/_api/admin/oauth/:provider/status", handlers.SetSystemProviderStatus())
+		ui.Get("/_api/admin/saml", handlers.GetSAMLConfig())
+		ui.Post("/_api/admin/saml", handlers.SaveSAMLConfig())
+		ui.Post("/_api/admin/scim/token", handlers.RegenerateSCIMToken())
 		ui.Post("/_api/admin/roles/:role/users", handlers.ChangeUserRole())
 		ui.Put("/_api/admin/users/:userID/block", handlers.BlockUser())
 		ui.Delete("/_api/admin/users/:userID/block", handlers.UnblockUser())
@@ -239,9 +278,13 @@ func routes(r *web.Engine) *web.Engine {
 	// Does not require authentication
 	publicApi := r.Group()
 	{
+		publicApi.Use(middlewares.RequireAPIScope(enum.APIScopeReadPosts))
+
 		publicApi.Get("/api/v1/similarposts", apiv1.FindSimilarPosts())
 		publicApi.Get("/api/v1/posts", apiv1.SearchPosts())
 		publicApi.Get("/api/v1/tags", apiv1.ListTags())
+		publicApi.Get("/api/v1/boards", apiv1.ListBoards())
+		publicApi.Get("/api/v1/post-statuses", apiv1.ListPostStatuses())
 		publicApi.Get("/api/v1/posts/:number", apiv1.GetPost())
 		publicApi.Get("/api/v1/posts/:number/comments", apiv1.ListComments())
 		publicApi.Get("/api/v1/posts/:number/comments/:id", apiv1.GetComment())
@@ -256,20 +299,37 @@ func routes(r *web.Engine) *web.Engine {
 		membersApi.Use(middlewares.IsAuthenticated())
 		membersApi.Use(middlewares.BlockLockedTenants())
 
-		membersApi.Post("/api/v1/posts", apiv1.CreatePost())
-		membersApi.Put("/api/v1/posts/:number", apiv1.UpdatePost())
-		membersApi.Post("/api/v1/posts/:number/comments/:id/reactions/:reaction", apiv1.ToggleReaction())
-		membersApi.Post("/api/v1/posts/:number/comments", apiv1.PostComment())
-		membersApi.Put("/api/v1/posts/:number/comments/:id", apiv1.UpdateComment())
-		membersApi.Delete("/api/v1/posts/:number/comments/:id", apiv1.DeleteComment())
-		membersApi.Post("/api/v1/posts/:number/votes", apiv1.AddVote())
-		membersApi.Delete("/api/v1/posts/:number/votes", apiv1.RemoveVote())
-		membersApi.Post("/api/v1/posts/:number/votes/toggle", apiv1.ToggleVote())
-		membersApi.Post("/api/v1/posts/:number/subscription", apiv1.Subscribe())
-		membersApi.Delete("/api/v1/posts/:number/subscription", apiv1.Unsubscribe())
-
-		membersApi.Use(middlewares.IsAuthorized(enum.RoleCollaborator, enum.RoleAdministrator))
-		membersApi.Put("/api/v1/posts/:number/status", apiv1.SetResponse())
+		postsApi := membersApi.Group()
+		{
+			postsApi.Use(middlewares.RequireAPIScope(enum.APIScopeWritePosts))
+
+			postsApi.Post("/api/v1/posts", apiv1.CreatePost())
+			postsApi.Put("/api/v1/posts/:number", apiv1.UpdatePost())
+			postsApi.Post("/api/v1/posts/:number/votes", apiv1.AddVote())
+			postsApi.Delete("/api/v1/posts/:number/votes", apiv1.RemoveVote())
+			postsApi.Post("/api/v1/posts/:number/votes/toggle", apiv1.ToggleVote())
+			postsApi.Post("/api/v1/posts/:number/subscription", apiv1.Subscribe())
+			postsApi.Delete("/api/v1/posts/:number/subscription", apiv1.Unsubscribe())
+
+			postsApi.Use(middlewares.IsAuthorized(enum.RoleCollaborator, enum.RoleAdministrator))
+			postsApi.Put("/api/v1/posts/:number/status", apiv1.SetResponse())
+			postsApi.Post("/api/v1/posts/:number/merge", apiv1.MergePosts())
+			postsApi.Put("/api/v1/posts/:number/board", apiv1.SetPostBoard())
+			postsApi.Post("/api/v1/posts/:number/revisions/:version/revert", apiv1.RevertPostRevision())
+		}
+
+		commentsApi := membersApi.Group()
+		{
+			commentsApi.Use(middlewares.RequireAPIScope(enum.APIScopeWriteComments))
+
+			commentsApi.Post("/api/v1/posts/:number/comments/:id/reactions/:reaction", apiv1.ToggleReaction())
+			commentsApi.Post("/api/v1/posts/:number/comments", apiv1.PostComment())
+			commentsApi.Put("/api/v1/posts/:number/comments/:id", apiv1.UpdateComment())
+			commentsApi.Delete("/api/v1/posts/:number/comments/:id", apiv1.DeleteComment())
+
+			commentsApi.Use(middlewares.IsAuthorized(enum.RoleCollaborator, enum.RoleAdministrator))
+			commentsApi.Post("/api/v1/posts/:number/comments/:id/revisions/:version/revert", apiv1.RevertCommentRevision())
+		}
 	}
 
 	// Operations used to manage a site
@@ -280,13 +340,27 @@ func routes(r *web.Engine) *web.Engine {
 		staffApi.Use(middlewares.IsAuthenticated())
 		staffApi.Use(middlewares.IsAuthorized(enum.RoleCollaborator, enum.RoleAdministrator))
 
+		staffTagsApi := staffApi.Group()
+		{
+			staffTagsApi.Use(middlewares.RequireAPIScope(enum.APIScopeManageTags))
+			staffTagsApi.Use(middlewares.BlockLockedTenants())
+			staffTagsApi.Post("/api/v1/posts/:number/tags/:slug", apiv1.AssignTag())
+			staffTagsApi.Delete("/api/v1/posts/:number/tags/:slug", apiv1.UnassignTag())
+		}
+
+		staffRevisionsApi := staffApi.Group()
+		{
+			staffRevisionsApi.Use(middlewares.RequireAPIScope(enum.APIScopeReadPosts))
+			staffRevisionsApi.Get("/api/v1/posts/:number/revisions", apiv1.ListPostRevisions())
+			staffRevisionsApi.Get("/api/v1/posts/:number/revisions/diff", apiv1.DiffPostRevisions())
+			staffRevisionsApi.Get("/api/v1/posts/:number/comments/:id/revisions", apiv1.ListCommentRevisions())
+			staffRevisionsApi.Get("/api/v1/posts/:number/comments/:id/revisions/diff", apiv1.DiffCommentRevisions())
+		}
+
+		staffApi.Use(middlewares.RequireAPIScope(enum.APIScopeAdmin))
 		staffApi.Get("/api/v1/users", apiv1.ListUsers())
 		staffApi.Post("/api/v1/invitations/send", apiv1.SendInvites())
 		staffApi.Post("/api/v1/invitations/sample", apiv1.SendSampleInvite())
-
-		staffApi.Use(middlewares.BlockLockedTenants())
-		staffApi.Post("/api/v1/posts/:number/tags/:slug", apiv1.AssignTag())
-		staffApi.Delete("/api/v1/posts/:number/tags/:slug", apiv1.UnassignTag())
 	}
 
 	// Operations used to manage a site
@@ -297,10 +371,28 @@ func routes(r *web.Engine) *web.Engine {
 		adminApi.Use(middlewares.IsAuthenticated())
 		adminApi.Use(middlewares.IsAuthorized(enum.RoleAdministrator))
 
+		adminTagsApi := adminApi.Group()
+		{
+			adminTagsApi.Use(middlewares.RequireAPIScope(enum.APIScopeManageTags))
+			adminTagsApi.Post("/api/v1/tags", apiv1.CreateEditTag())
+			adminTagsApi.Put("/api/v1/tags/:slug", apiv1.CreateEditTag())
+			adminTagsApi.Delete("/api/v1/tags/:slug", apiv1.DeleteTag())
+		}
+
+		adminApi.Use(middlewares.RequireAPIScope(enum.APIScopeAdmin))
 		adminApi.Post("/api/v1/users", apiv1.CreateUser())
-		adminApi.Post("/api/v1/tags", apiv1.CreateEditTag())
-		adminApi.Put("/api/v1/tags/:slug", apiv1.CreateEditTag())
-		adminApi.Delete("/api/v1/tags/:slug", apiv1.DeleteTag())
+		adminApi.Post("/api/v1/users/attributes", apiv1.ImportUserAttributes())
+		adminApi.Put("/api/v1/users/:userID/attributes", apiv1.SetUserAttributes())
+		adminApi.Post("/api/v1/post-statuses", apiv1.CreateEditPostStatus())
+		adminApi.Put("/api/v1/post-statuses/:slug", apiv1.CreateEditPostStatus())
+		adminApi.Delete("/api/v1/post-statuses/:slug", apiv1.DeletePostStatus())
+		adminApi.Post("/api/v1/boards", apiv1.CreateEditBoard())
+		adminApi.Put("/api/v1/boards/:slug", apiv1.CreateEditBoard())
+		adminApi.Delete("/api/v1/boards/:slug", apiv1.DeleteBoard())
+		adminApi.Get("/api/v1/backup/archives", apiv1.ListBackupArchives())
+		adminApi.Get("/api/v1/backup/archives/:name", apiv1.DownloadBackupArchive())
+		adminApi.Post("/api/v1/backup/restore", apiv1.RestoreBackup())
+		adminApi.Post("/api/v1/import/posts", apiv1.ImportPosts())
 
 		// Pro features (available to self-hosters and pro hosted customers)
 		proAdminApi := adminApi.Group()
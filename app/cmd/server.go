@@ -59,6 +59,14 @@ func startJobs(ctx context.Context) {
 	_ = c.AddJob(jobs.NewJob(ctx, "PurgeExpiredNotificationsJob", jobs.PurgeExpiredNotificationsJobHandler{}))
 	_ = c.AddJob(jobs.NewJob(ctx, "EmailSupressionJob", jobs.EmailSupressionJobHandler{}))
 	_ = c.AddJob(jobs.NewJob(ctx, "DeleteScheduledTenantsJob", jobs.DeleteScheduledTenantsJobHandler{}))
+	_ = c.AddJob(jobs.NewJob(ctx, "WebhookDeliveryJob", jobs.WebhookDeliveryJobHandler{}))
+	_ = c.AddJob(jobs.NewJob(ctx, "EmailDigestJob", jobs.EmailDigestJobHandler{}))
+	_ = c.AddJob(jobs.NewJob(ctx, "BackfillPostSignaturesJob", jobs.BackfillPostSignaturesJobHandler{}))
+	if env.Config.Backup.Retention > 0 {
+		if err := c.AddJob(jobs.NewJob(ctx, "BackupJob", jobs.BackupJobHandler{})); err != nil {
+			panic(errors.Wrap(err, "invalid BACKUP_SCHEDULE '%s'", env.Config.Backup.Schedule))
+		}
+	}
 
 	c.Start()
 }
@@ -11,6 +11,9 @@ var InvitePlaceholder = "%invite%"
 // ErrUserIDRequired is used when OAuth integration returns an empty user ID
 var ErrUserIDRequired = errors.New("UserID is required during OAuth integration")
 
+// ErrVoteBudgetExceeded is used when a vote would spend more than the vote budget of the user
+var ErrVoteBudgetExceeded = errors.New("vote budget exceeded")
+
 type key string
 
 func createKey(name string) key {
@@ -24,6 +27,10 @@ const (
 	GoogleProvider = "google"
 	//GitHubProvider is const for 'github'
 	GitHubProvider = "github"
+	//SAMLProvider is const for 'saml', the provider name of users signed in through the tenant SAML IdP
+	SAMLProvider = "saml"
+	//SCIMProvider is const for 'scim', the provider name holding the external ID of users provisioned through SCIM
+	SCIMProvider = "scim"
 )
 
 var (
@@ -32,5 +39,6 @@ var (
 	TenantCtxKey      = createKey("TENANT")
 	LocaleCtxKey      = createKey("LOCALE")
 	UserCtxKey        = createKey("USER")
+	APITokenCtxKey    = createKey("API_TOKEN")
 	LogPropsCtxKey    = createKey("LOG_PROPS")
 )
@@ -2,7 +2,9 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
 
+	"github.com/getfider/fider/app"
 	"github.com/getfider/fider/app/actions"
 	"github.com/getfider/fider/app/models/cmd"
 	"github.com/getfider/fider/app/models/dto"
@@ -10,6 +12,7 @@ import (
 	"github.com/getfider/fider/app/models/query"
 	"github.com/getfider/fider/app/pkg/bus"
 	"github.com/getfider/fider/app/pkg/env"
+	"github.com/getfider/fider/app/pkg/errors"
 	"github.com/getfider/fider/app/pkg/web"
 	"github.com/getfider/fider/app/tasks"
 )
@@ -117,6 +120,26 @@ func UpdatePrivacySettings() web.HandlerFunc {
 	}
 }
 
+// UpdateVotingSettings update current tenant's vote budget settings
+func UpdateVotingSettings() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.UpdateTenantVotingSettings)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		updateSettings := &cmd.UpdateTenantVotingSettings{
+			VoteBudget:      action.VoteBudget,
+			MaxVotesPerPost: action.MaxVotesPerPost,
+		}
+		if err := bus.Dispatch(c, updateSettings); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
+
 // UpdateEmailAuthAllowed update current tenant's allow email auth settings
 func UpdateEmailAuthAllowed() web.HandlerFunc {
 	return func(c *web.Context) error {
@@ -175,6 +198,33 @@ func ManageMembers() web.HandlerFunc {
 	}
 }
 
+// defaultDuplicateSimilarity is how similar two posts must be to be listed as possible duplicates when not specified
+const defaultDuplicateSimilarity = 0.5
+
+// PossibleDuplicates is the page used by staff to review pairs of posts that are likely to be duplicates
+func PossibleDuplicates() web.HandlerFunc {
+	return func(c *web.Context) error {
+		minSimilarity, err := strconv.ParseFloat(c.QueryParam("similarity"), 64)
+		if err != nil || minSimilarity <= 0 || minSimilarity > 1 {
+			minSimilarity = defaultDuplicateSimilarity
+		}
+
+		getPossibleDuplicates := &query.GetPossibleDuplicates{MinSimilarity: minSimilarity}
+		if err := bus.Dispatch(c, getPossibleDuplicates); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Page(http.StatusOK, web.Props{
+			Page:  "Administration/pages/PossibleDuplicates.page",
+			Title: "Possible Duplicates · Site Settings",
+			Data: web.Map{
+				"duplicates":    getPossibleDuplicates.Result,
+				"minSimilarity": minSimilarity,
+			},
+		})
+	}
+}
+
 // ManageAuthentication is the page used by administrators to change site authentication settings
 func ManageAuthentication() web.HandlerFunc {
 	return func(c *web.Context) error {
@@ -183,16 +233,49 @@ func ManageAuthentication() web.HandlerFunc {
 			return c.Failure(err)
 		}
 
+		getSAMLConfig := &query.GetSAMLConfig{}
+		if err := bus.Dispatch(c, getSAMLConfig); err != nil && errors.Cause(err) != app.ErrNotFound {
+			return c.Failure(err)
+		}
+
+		getSCIMTokenInfo := &query.GetSCIMTokenInfo{}
+		if err := bus.Dispatch(c, getSCIMTokenInfo); err != nil {
+			return c.Failure(err)
+		}
+
 		return c.Page(http.StatusOK, web.Props{
 			Page:  "Administration/pages/ManageAuthentication.page",
 			Title: "Authentication · Site Settings",
 			Data: web.Map{
-				"providers": listProviders.Result,
+				"providers":  listProviders.Result,
+				"samlConfig": getSAMLConfig.Result,
+				"samlServiceProvider": web.Map{
+					"entityID": samlEntityID(c),
+					"acsURL":   samlACSURL(c),
+				},
+				"scim": web.Map{
+					"baseURL":        c.BaseURL() + "/scim/v2",
+					"tokenCreatedAt": getSCIMTokenInfo.Result,
+				},
 			},
 		})
 	}
 }
 
+// RegenerateSCIMToken replaces the bearer token used by identity providers to provision users
+func RegenerateSCIMToken() web.HandlerFunc {
+	return func(c *web.Context) error {
+		regenerateToken := &cmd.RegenerateSCIMToken{}
+		if err := bus.Dispatch(c, regenerateToken); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{
+			"token": regenerateToken.Result,
+		})
+	}
+}
+
 // GetOAuthConfig returns OAuth config based on given provider
 func GetOAuthConfig() web.HandlerFunc {
 	return func(c *web.Context) error {
@@ -233,6 +316,8 @@ func SaveOAuthConfig() web.HandlerFunc {
 				Scope:             action.Scope,
 				ProfileURL:        action.ProfileURL,
 				IsTrusted:         action.IsTrusted,
+				IsOIDC:            action.IsOIDC,
+				IssuerURL:         action.IssuerURL,
 				JSONUserIDPath:    action.JSONUserIDPath,
 				JSONUserNamePath:  action.JSONUserNamePath,
 				JSONUserEmailPath: action.JSONUserEmailPath,
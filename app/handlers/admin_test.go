@@ -216,3 +216,34 @@ func TestManageMembersHandler(t *testing.T) {
 
 	Expect(code).Equals(http.StatusOK)
 }
+
+func TestPossibleDuplicatesHandler(t *testing.T) {
+	RegisterT(t)
+
+	var getPossibleDuplicates *query.GetPossibleDuplicates
+	bus.AddHandler(func(ctx context.Context, q *query.GetPossibleDuplicates) error {
+		getPossibleDuplicates = q
+		q.Result = []*entity.PossibleDuplicate{
+			{Post: &entity.Post{ID: 1, Number: 1}, Duplicate: &entity.Post{ID: 2, Number: 2}, Similarity: 0.75},
+		}
+		return nil
+	})
+
+	code, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		WithURL("http://demo.test.fider.io/admin/duplicates?similarity=0.7").
+		Execute(handlers.PossibleDuplicates())
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(getPossibleDuplicates.MinSimilarity).Equals(0.7)
+
+	code, _ = mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		WithURL("http://demo.test.fider.io/admin/duplicates?similarity=abc").
+		Execute(handlers.PossibleDuplicates())
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(getPossibleDuplicates.MinSimilarity).Equals(0.5)
+}
@@ -0,0 +1,65 @@
+package apiv1
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/getfider/fider/app/pkg/backup"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/log"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+// ListBackupArchives returns the archives stored by scheduled backups, newest first
+func ListBackupArchives() web.HandlerFunc {
+	return func(c *web.Context) error {
+		archives, err := backup.List(c)
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(archives)
+	}
+}
+
+// DownloadBackupArchive streams an archive stored by scheduled backups
+func DownloadBackupArchive() web.HandlerFunc {
+	return func(c *web.Context) error {
+		name := c.Param("name")
+		reader, err := backup.Open(c, name)
+		if err != nil {
+			return c.Failure(err)
+		}
+		defer func() { _ = reader.Close() }()
+
+		c.Response.Header().Set("Content-Type", "application/zip")
+		c.Response.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", name))
+		c.Response.WriteHeader(http.StatusOK)
+
+		if _, err := io.Copy(&c.Response, reader); err != nil {
+			log.Error(c, errors.Wrap(err, "failed to download archive '%s'", name))
+		}
+		return nil
+	}
+}
+
+// RestoreBackup restores a backup archive created by Fider into current site, which must not have any posts yet
+func RestoreBackup() web.HandlerFunc {
+	return func(c *web.Context) error {
+		if c.Request.ContentLength == 0 {
+			return c.BadRequest(web.Map{"error": "request body is empty"})
+		}
+
+		summary, err := backup.Restore(c, []byte(c.Request.Body), c.Tenant(), false)
+		if err != nil {
+			switch errors.Cause(err) {
+			case backup.ErrInvalidArchive, backup.ErrUnsupportedVersion, backup.ErrNewerSchema, backup.ErrTenantNotEmpty:
+				return c.BadRequest(web.Map{"error": errors.Cause(err).Error()})
+			}
+			return c.Failure(err)
+		}
+
+		return c.Ok(summary)
+	}
+}
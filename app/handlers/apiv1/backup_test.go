@@ -0,0 +1,93 @@
+package apiv1_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getfider/fider/app/handlers/apiv1"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/backup"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/mock"
+	"github.com/getfider/fider/app/services/blob"
+)
+
+func TestRestoreBackupHandler_EmptyBody(t *testing.T) {
+	RegisterT(t)
+
+	code, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		ExecutePost(apiv1.RestoreBackup(), "")
+
+	Expect(code).Equals(http.StatusBadRequest)
+}
+
+func TestRestoreBackupHandler_InvalidArchive(t *testing.T) {
+	RegisterT(t)
+
+	code, query := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		ExecutePostAsJSON(apiv1.RestoreBackup(), "this is not a zip file")
+
+	Expect(code).Equals(http.StatusBadRequest)
+	Expect(query.String("error")).Equals("file is not a valid backup archive")
+}
+
+func TestListBackupArchivesHandler(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.ListBlobs) error {
+		q.Result = []string{"backups/backup-20261017T030000Z.zip", "backups/backup-20261018T030000Z.zip"}
+		return nil
+	})
+
+	code, response := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		Execute(apiv1.ListBackupArchives())
+
+	Expect(code).Equals(http.StatusOK)
+	archives := []*backup.Archive{}
+	Expect(json.Unmarshal(response.Body.Bytes(), &archives)).IsNil()
+	Expect(archives).HasLen(2)
+	Expect(archives[0].Name).Equals("backup-20261018T030000Z.zip")
+	Expect(archives[0].CreatedAt).Equals(time.Date(2026, 10, 18, 3, 0, 0, 0, time.UTC))
+}
+
+func TestDownloadBackupArchiveHandler(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.OpenBlobByKey) error {
+		if q.Key != "backups/backup-20261018T030000Z.zip" {
+			return blob.ErrNotFound
+		}
+		q.Result = io.NopCloser(strings.NewReader("zip content"))
+		return nil
+	})
+
+	code, response := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("name", "backup-20261018T030000Z.zip").
+		Execute(apiv1.DownloadBackupArchive())
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(response.Header().Get("Content-Type")).Equals("application/zip")
+	Expect(response.Body.String()).Equals("zip content")
+
+	code, _ = mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("name", "backup-20261016T030000Z.zip").
+		Execute(apiv1.DownloadBackupArchive())
+
+	Expect(code).Equals(http.StatusNotFound)
+}
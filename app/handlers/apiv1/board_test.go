@@ -0,0 +1,107 @@
+package apiv1_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/handlers/apiv1"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/mock"
+)
+
+func TestCreateBoardHandler_ValidRequest(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetBoardBySlug) error {
+		return app.ErrNotFound
+	})
+
+	var addNewBoard *cmd.AddNewBoard
+	bus.AddHandler(func(ctx context.Context, c *cmd.AddNewBoard) error {
+		addNewBoard = c
+		return nil
+	})
+
+	server := mock.NewServer()
+	status, _ := server.
+		AsUser(mock.JonSnow).
+		ExecutePost(
+			apiv1.CreateEditBoard(),
+			`{ "name": "Mobile", "description": "Our apps", "descriptionTemplate": "Device:", "isPublic": false, "hasRoadmap": true }`,
+		)
+
+	Expect(status).Equals(http.StatusOK)
+	Expect(addNewBoard.Name).Equals("Mobile")
+	Expect(addNewBoard.Description).Equals("Our apps")
+	Expect(addNewBoard.DescriptionTemplate).Equals("Device:")
+	Expect(addNewBoard.IsPublic).IsFalse()
+	Expect(addNewBoard.HasRoadmap).IsTrue()
+	Expect(addNewBoard.DefaultTags).HasLen(0)
+}
+
+func TestCreateBoardHandler_Visitor(t *testing.T) {
+	RegisterT(t)
+
+	server := mock.NewServer()
+	status, _ := server.
+		AsUser(mock.AryaStark).
+		ExecutePost(apiv1.CreateEditBoard(), `{ "name": "Mobile" }`)
+
+	Expect(status).Equals(http.StatusForbidden)
+}
+
+func TestSetPostBoardHandler(t *testing.T) {
+	RegisterT(t)
+
+	post := &entity.Post{ID: 1, Number: 1, Title: "Dark mode"}
+	board := &entity.Board{ID: 2, Name: "Mobile", Slug: "mobile"}
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = post
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetBoardBySlug) error {
+		if q.Slug == board.Slug {
+			q.Result = board
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	var setPostBoard *cmd.SetPostBoard
+	bus.AddHandler(func(ctx context.Context, c *cmd.SetPostBoard) error {
+		setPostBoard = c
+		return nil
+	})
+
+	status, _ := mock.NewServer().
+		AsUser(mock.AryaStark).
+		AddParam("number", "1").
+		ExecutePost(apiv1.SetPostBoard(), `{ "board": "mobile" }`)
+
+	Expect(status).Equals(http.StatusForbidden)
+	Expect(setPostBoard).IsNil()
+
+	status, _ = mock.NewServer().
+		AsUser(mock.JonSnow).
+		AddParam("number", "1").
+		ExecutePost(apiv1.SetPostBoard(), `{ "board": "mobile" }`)
+
+	Expect(status).Equals(http.StatusOK)
+	Expect(setPostBoard.Post).Equals(post)
+	Expect(setPostBoard.Board).Equals(board)
+
+	status, _ = mock.NewServer().
+		AsUser(mock.JonSnow).
+		AddParam("number", "1").
+		ExecutePost(apiv1.SetPostBoard(), `{ "board": "billing" }`)
+
+	Expect(status).Equals(http.StatusBadRequest)
+}
@@ -0,0 +1,40 @@
+package apiv1
+
+import (
+	"net/http"
+
+	"github.com/getfider/fider/app/pkg/importer"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+// ImportPosts imports posts, votes and comments exported from another feedback tool.
+// Nothing is imported on a dry run, which returns a summary of what would be imported
+func ImportPosts() web.HandlerFunc {
+	return func(c *web.Context) error {
+		if c.Request.ContentLength == 0 {
+			return c.BadRequest(web.Map{"error": "request body is empty"})
+		}
+
+		format := c.QueryParam("format")
+		if format == "" {
+			format = importer.FormatFider
+		}
+
+		data, err := importer.Parse(format, []byte(c.Request.Body))
+		if err != nil {
+			return c.BadRequest(web.Map{"error": err.Error()})
+		}
+
+		dryRun, _ := c.QueryParamAsBool("dryRun")
+		summary, err := importer.Run(c, data, dryRun)
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		if len(summary.Errors) > 0 {
+			return c.JSON(http.StatusBadRequest, summary)
+		}
+
+		return c.Ok(summary)
+	}
+}
@@ -0,0 +1,84 @@
+package apiv1_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/handlers/apiv1"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/mock"
+)
+
+func TestImportPostsHandler_DryRun(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCustomPostStatuses) error {
+		return nil
+	})
+	bus.AddHandler(func(ctx context.Context, q *query.GetAllTags) error {
+		q.Result = []*entity.Tag{}
+		return nil
+	})
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByEmail) error {
+		return app.ErrNotFound
+	})
+
+	imported := false
+	bus.AddHandler(func(ctx context.Context, c *cmd.ImportPost) error {
+		imported = true
+		return nil
+	})
+
+	code, query := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		WithURL("http://demo.test.fider.io/api/v1/import/posts?format=csv&dryRun=true").
+		ExecutePostAsJSON(apiv1.ImportPosts(), "Title,Created,Status,Tags,Voters\nDark mode,2018-03-01,Planned,ui,arya.stark@got.com")
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(imported).IsFalse()
+	Expect(query.Contains("dryRun")).IsTrue()
+	Expect(query.Int32("posts")).Equals(1)
+	Expect(query.Int32("votes")).Equals(1)
+	Expect(query.Int32("newUsers")).Equals(1)
+	Expect(query.Int32("newTags")).Equals(1)
+}
+
+func TestImportPostsHandler_InvalidData(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCustomPostStatuses) error {
+		return nil
+	})
+	bus.AddHandler(func(ctx context.Context, q *query.GetAllTags) error {
+		return nil
+	})
+
+	code, query := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		WithURL("http://demo.test.fider.io/api/v1/import/posts").
+		ExecutePostAsJSON(apiv1.ImportPosts(), `{ "posts": [{ "title": "Dark mode" }] }`)
+
+	Expect(code).Equals(http.StatusBadRequest)
+	Expect(query.Strings("errors")).Equals([]string{"post 'Dark mode': creation date is required"})
+}
+
+func TestImportPostsHandler_UnsupportedFormat(t *testing.T) {
+	RegisterT(t)
+
+	code, query := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		WithURL("http://demo.test.fider.io/api/v1/import/posts?format=xml").
+		ExecutePostAsJSON(apiv1.ImportPosts(), "<posts />")
+
+	Expect(code).Equals(http.StatusBadRequest)
+	Expect(query.String("error")).Equals("format 'xml' is not supported")
+}
@@ -0,0 +1,52 @@
+package apiv1
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getfider/fider/app/pkg/cursor"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+// validCursor returns false when the cursor sent by the client can't be decoded
+// or its sort key isn't valid for the sort in use, as checked by validKey
+func validCursor(c *web.Context, validKey func(key string) bool) bool {
+	if value := c.QueryParam("cursor"); value != "" {
+		key, _, err := cursor.Decode(value)
+		return err == nil && validKey(key)
+	}
+	return true
+}
+
+// postCursorKey is the sort key of posts, a number optionally followed by the time the list was sorted at
+func postCursorKey(key string) bool {
+	score, listedAt, ok := strings.Cut(key, "@")
+	if ok && !timestampCursorKey(listedAt) {
+		return false
+	}
+	value, err := strconv.ParseFloat(score, 64)
+	return err == nil && !math.IsNaN(value) && !math.IsInf(value, 0)
+}
+
+// timestampCursorKey is the sort key of lists sorted by creation date, such as comments and votes
+func timestampCursorKey(key string) bool {
+	_, err := time.Parse(time.RFC3339Nano, key)
+	return err == nil
+}
+
+// setPaginationHeaders sets the total count of items and, when there are more items, the link to the next page
+func setPaginationHeaders(c *web.Context, totalCount int, nextCursor string) {
+	header := c.Response.Header()
+	header.Set("X-Total-Count", strconv.Itoa(totalCount))
+
+	if nextCursor != "" {
+		next := *c.Request.URL
+		params := next.Query()
+		params.Set("cursor", nextCursor)
+		next.RawQuery = params.Encode()
+		header.Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next.String()))
+	}
+}
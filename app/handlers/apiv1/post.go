@@ -2,8 +2,10 @@ package apiv1
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/getfider/fider/app"
 	"github.com/getfider/fider/app/actions"
 	"github.com/getfider/fider/app/metrics"
 	"github.com/getfider/fider/app/models/cmd"
@@ -13,6 +15,9 @@ import (
 	"github.com/getfider/fider/app/models/query"
 	"github.com/getfider/fider/app/pkg/bus"
 	"github.com/getfider/fider/app/pkg/env"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/i18n"
+	"github.com/getfider/fider/app/pkg/validate"
 	"github.com/getfider/fider/app/pkg/web"
 	"github.com/getfider/fider/app/tasks"
 )
@@ -49,6 +54,10 @@ func appendUnreferencedAttachments(description string, attachments []*dto.ImageU
 // SearchPosts return existing posts based on search criteria
 func SearchPosts() web.HandlerFunc {
 	return func(c *web.Context) error {
+		if !validCursor(c, postCursorKey) {
+			return c.BadRequest(web.Map{"error": "Invalid cursor"})
+		}
+
 		viewQueryParams := c.QueryParam("view")
 		if viewQueryParams == "" {
 			viewQueryParams = "all" // Set default value to "all" if not provided
@@ -59,6 +68,9 @@ func SearchPosts() web.HandlerFunc {
 			Limit:            c.QueryParam("limit"),
 			Tags:             c.QueryParamAsArray("tags"),
 			ModerationFilter: c.QueryParam("moderation"),
+			Cursor:           c.QueryParam("cursor"),
+			Board:            c.QueryParam("board"),
+			CountTotal:       true,
 		}
 		if myVotesOnly, err := c.QueryParamAsBool("myvotes"); err == nil {
 			searchPosts.MyVotesOnly = myVotesOnly
@@ -69,12 +81,33 @@ func SearchPosts() web.HandlerFunc {
 		if myPostsOnly, err := c.QueryParamAsBool("myposts"); err == nil {
 			searchPosts.MyPostsOnly = myPostsOnly
 		}
+		if includeComments, err := c.QueryParamAsBool("comments"); err == nil {
+			searchPosts.IncludeComments = includeComments
+		}
+		if excludeRoadmapBoards, err := c.QueryParamAsBool("excluderoadmapboards"); err == nil {
+			searchPosts.ExcludeRoadmapBoards = excludeRoadmapBoards
+		}
 		searchPosts.SetStatusesFromStrings(c.QueryParamAsArray("statuses"))
 
+		// User attributes are private, so only staff can segment posts by them
+		if c.User() != nil && c.User().IsCollaborator() {
+			searchPosts.SortAttribute = c.QueryParam("attribute")
+			searchPosts.VoterAttributes = make(map[string]string)
+			for _, filter := range c.QueryParamAsArray("voters") {
+				if key, value, ok := strings.Cut(filter, ":"); ok {
+					searchPosts.VoterAttributes[key] = value
+				}
+			}
+			if minTotal, err := strconv.ParseFloat(c.QueryParam("attributemin"), 64); err == nil {
+				searchPosts.MinAttributeTotal = minTotal
+			}
+		}
+
 		if err := bus.Dispatch(c, searchPosts); err != nil {
 			return c.Failure(err)
 		}
 
+		setPaginationHeaders(c, searchPosts.TotalCount, searchPosts.NextCursor)
 		return c.Ok(searchPosts.Result)
 	}
 }
@@ -107,6 +140,7 @@ func CreatePost() web.HandlerFunc {
 		newPost := &cmd.AddNewPost{
 			Title:       action.Title,
 			Description: appendUnreferencedAttachments(action.Description, action.Attachments),
+			Board:       action.Board,
 		}
 		err := bus.Dispatch(c, newPost)
 		if err != nil {
@@ -232,6 +266,35 @@ func SetResponse() web.HandlerFunc {
 	}
 }
 
+// MergePosts merges a duplicate post into its original, moving its votes, subscribers, tags and optionally comments
+func MergePosts() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.MergePosts)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		prevStatus := action.Post.Status
+		mergePosts := &cmd.MergePosts{
+			Post:          action.Post,
+			Original:      action.Original,
+			MergeComments: action.MergeComments,
+		}
+		if err := bus.Dispatch(c, mergePosts); err != nil {
+			return c.Failure(err)
+		}
+
+		c.Enqueue(tasks.NotifyAboutMergedStatusChange(action.Post, mergePosts.Result, prevStatus))
+		c.Enqueue(tasks.NotifyAboutMergedPost(action.Post, mergePosts.Result, mergePosts.MovedVoters))
+
+		return c.Ok(web.Map{
+			"number":     mergePosts.Result.Number,
+			"votesCount": mergePosts.Result.VotesCount,
+			"movedVotes": len(mergePosts.MovedVoters),
+		})
+	}
+}
+
 // DeletePost deletes an existing post of current tenant
 func DeletePost() web.HandlerFunc {
 	return func(c *web.Context) error {
@@ -268,37 +331,82 @@ func ListComments() web.HandlerFunc {
 			return c.Failure(err)
 		}
 
-		getComments := &query.GetCommentsByPost{Post: getPost.Result}
+		limit, err := c.QueryParamAsInt("limit")
+		if err != nil || limit < 0 {
+			return c.BadRequest(web.Map{"error": "Invalid limit"})
+		}
+		if !validCursor(c, timestampCursorKey) {
+			return c.BadRequest(web.Map{"error": "Invalid cursor"})
+		}
+		threaded, err := c.QueryParamAsBool("threaded")
+		if err != nil {
+			return c.BadRequest(web.Map{"error": "Invalid threaded"})
+		}
+
+		getComments := &query.GetCommentsByPost{
+			Post:       getPost.Result,
+			Limit:      limit,
+			Cursor:     c.QueryParam("cursor"),
+			CountTotal: true,
+			Threaded:   threaded,
+		}
 		if err := bus.Dispatch(c, getComments); err != nil {
 			return c.Failure(err)
 		}
 
-		for _, comment := range getComments.Result {
-			commentString := entity.CommentString(comment.Content)
-			comment.Content = commentString.SanitizeMentions()
-		}
+		sanitizeCommentMentions(getComments.Result)
 
+		setPaginationHeaders(c, getComments.TotalCount, getComments.NextCursor)
 		return c.Ok(getComments.Result)
 	}
 }
 
+// sanitizeCommentMentions sanitizes the mentions of given comments and of their replies
+func sanitizeCommentMentions(comments []*entity.Comment) {
+	for _, comment := range comments {
+		commentString := entity.CommentString(comment.Content)
+		comment.Content = commentString.SanitizeMentions()
+		sanitizeCommentMentions(comment.Replies)
+	}
+}
+
+// getPostComment returns the comment on the route, or app.ErrNotFound when it isn't a comment of the post on the route.
+// The post is loaded by number so that comments of posts the user can't see aren't found either
+func getPostComment(c *web.Context) (*entity.Comment, error) {
+	number, err := c.ParamAsInt("number")
+	if err != nil {
+		return nil, app.ErrNotFound
+	}
+
+	id, err := c.ParamAsInt("id")
+	if err != nil {
+		return nil, app.ErrNotFound
+	}
+
+	getPost := &query.GetPostByNumber{Number: number}
+	getComment := &query.GetCommentByID{CommentID: id}
+	if err := bus.Dispatch(c, getPost, getComment); err != nil {
+		return nil, err
+	}
+
+	if getComment.Result.PostID != getPost.Result.ID {
+		return nil, app.ErrNotFound
+	}
+	return getComment.Result, nil
+}
+
 // GetComment returns a single comment by its ID
 func GetComment() web.HandlerFunc {
 	return func(c *web.Context) error {
-		id, err := c.ParamAsInt("id")
+		comment, err := getPostComment(c)
 		if err != nil {
-			return c.NotFound()
-		}
-
-		commentByID := &query.GetCommentByID{CommentID: id}
-		if err := bus.Dispatch(c, commentByID); err != nil {
 			return c.Failure(err)
 		}
 
-		commentString := entity.CommentString(commentByID.Result.Content)
-		commentByID.Result.Content = commentString.SanitizeMentions()
+		commentString := entity.CommentString(comment.Content)
+		comment.Content = commentString.SanitizeMentions()
 
-		return c.Ok(commentByID.Result)
+		return c.Ok(comment)
 	}
 }
 
@@ -310,13 +418,13 @@ func ToggleReaction() web.HandlerFunc {
 			return c.HandleValidation(result)
 		}
 
-		getComment := &query.GetCommentByID{CommentID: action.Comment}
-		if err := bus.Dispatch(c, getComment); err != nil {
+		comment, err := getPostComment(c)
+		if err != nil {
 			return c.Failure(err)
 		}
 
 		toggleReaction := &cmd.ToggleCommentReaction{
-			Comment: getComment.Result,
+			Comment: comment,
 			Emoji:   action.Reaction,
 			User:    c.User(),
 		}
@@ -348,8 +456,14 @@ func PostComment() web.HandlerFunc {
 		}
 
 		addNewComment := &cmd.AddNewComment{
-			Post:    getPost.Result,
-			Content: action.Content,
+			Post:       getPost.Result,
+			Content:    action.Content,
+			IsInternal: action.IsInternal,
+		}
+		if action.Parent != nil {
+			addNewComment.ParentID = action.Parent.ID
+			// Replies to internal notes are internal as well
+			addNewComment.IsInternal = action.IsInternal || action.Parent.IsInternal
 		}
 		if err := bus.Dispatch(c, addNewComment); err != nil {
 			return c.Failure(err)
@@ -389,8 +503,11 @@ func UpdateComment() web.HandlerFunc {
 		}
 
 		comment := &entity.Comment{
-			ID:      action.ID,
-			Content: action.Content,
+			ID:         action.ID,
+			Content:    action.Content,
+			CreatedAt:  action.Comment.CreatedAt,
+			User:       action.Comment.User,
+			IsInternal: action.Comment.IsInternal,
 		}
 
 		err := bus.Dispatch(c,
@@ -428,6 +545,12 @@ func DeleteComment() web.HandlerFunc {
 			return c.HandleValidation(result)
 		}
 
+		getPost := &query.GetPostByNumber{Number: action.PostNumber}
+		getComment := &query.GetCommentByID{CommentID: action.CommentID}
+		if err := bus.Dispatch(c, getPost, getComment); err != nil {
+			return c.Failure(err)
+		}
+
 		err := bus.Dispatch(c, &cmd.DeleteComment{
 			CommentID: action.CommentID,
 		})
@@ -435,22 +558,45 @@ func DeleteComment() web.HandlerFunc {
 			return c.Failure(err)
 		}
 
+		c.Enqueue(tasks.NotifyAboutDeletedComment(getPost.Result, getComment.Result))
+
 		return c.Ok(web.Map{})
 	}
 }
 
 // AddVote adds current user to given post list of votes
+// When vote budgets are enabled, the amount of votes can be given and the remaining budget is returned
 func AddVote() web.HandlerFunc {
 	return func(c *web.Context) error {
-		err := addOrRemove(c, func(post *entity.Post, user *entity.User) bus.Msg {
-			return &cmd.AddVote{Post: post, User: user}
-		})
+		action := new(actions.AddVote)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
 
-		if err == nil {
-			metrics.TotalVotes.Inc()
+		post := action.Post
+		if err := bus.Dispatch(c, &cmd.AddVote{Post: post, User: c.User(), Weight: action.Amount}); err != nil {
+			if errors.Cause(err) == app.ErrVoteBudgetExceeded {
+				return voteBudgetExceeded(c)
+			}
+			return c.Failure(err)
 		}
 
-		return err
+		if post.CanBeVoted() && !post.HasVoted {
+			c.Enqueue(tasks.NotifyAboutNewVote(post))
+		}
+		metrics.TotalVotes.Inc()
+
+		if !c.Tenant().IsVoteBudgetEnabled() {
+			return c.Ok(web.Map{})
+		}
+
+		budget := action.Budget
+		if post.CanBeVoted() {
+			budget.Spent += action.Amount - post.VoteWeight
+		}
+		return c.Ok(web.Map{
+			"remaining": budget.Remaining(),
+		})
 	}
 }
 
@@ -459,10 +605,26 @@ func RemoveVote() web.HandlerFunc {
 	return func(c *web.Context) error {
 		return addOrRemove(c, func(post *entity.Post, user *entity.User) bus.Msg {
 			return &cmd.RemoveVote{Post: post, User: user}
+		}, func(post *entity.Post) {
+			if post.CanBeVoted() && post.HasVoted {
+				c.Enqueue(tasks.NotifyAboutRemovedVote(post))
+			}
 		})
 	}
 }
 
+// voteBudgetExceeded responds with the remaining budget of current user when a vote was rejected by the storage,
+// which happens when another vote of the same user was placed after the budget was checked by the action
+func voteBudgetExceeded(c *web.Context) error {
+	getBudget := &query.GetVoteBudget{}
+	if err := bus.Dispatch(c, getBudget); err != nil {
+		return c.Failure(err)
+	}
+	result := validate.Success()
+	result.AddFieldFailure("amount", i18n.T(c, "validation.custom.votebudget", i18n.Params{"remaining": getBudget.Result.Remaining()}))
+	return c.HandleValidation(result)
+}
+
 func ToggleVote() web.HandlerFunc {
 	return func(c *web.Context) error {
 		number, err := c.ParamAsInt("number")
@@ -497,13 +659,27 @@ func ToggleVote() web.HandlerFunc {
 			if err != nil {
 				return c.Failure(err)
 			}
+			if getPost.Result.CanBeVoted() {
+				c.Enqueue(tasks.NotifyAboutRemovedVote(getPost.Result))
+			}
 			return c.Ok(web.Map{"voted": false})
 		}
 
-		err = bus.Dispatch(c, &cmd.AddVote{Post: getPost.Result, User: c.User()})
+		action := new(actions.AddVote)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		err = bus.Dispatch(c, &cmd.AddVote{Post: getPost.Result, User: c.User(), Weight: action.Amount})
 		if err != nil {
+			if errors.Cause(err) == app.ErrVoteBudgetExceeded {
+				return voteBudgetExceeded(c)
+			}
 			return c.Failure(err)
 		}
+		if getPost.Result.CanBeVoted() {
+			c.Enqueue(tasks.NotifyAboutNewVote(getPost.Result))
+		}
 		metrics.TotalVotes.Inc()
 		return c.Ok(web.Map{"voted": true})
 	}
@@ -514,7 +690,7 @@ func Subscribe() web.HandlerFunc {
 	return func(c *web.Context) error {
 		return addOrRemove(c, func(post *entity.Post, user *entity.User) bus.Msg {
 			return &cmd.AddSubscriber{Post: post, User: user}
-		})
+		}, nil)
 	}
 }
 
@@ -523,7 +699,7 @@ func Unsubscribe() web.HandlerFunc {
 	return func(c *web.Context) error {
 		return addOrRemove(c, func(post *entity.Post, user *entity.User) bus.Msg {
 			return &cmd.RemoveSubscriber{Post: post, User: user}
-		})
+		}, nil)
 	}
 }
 
@@ -540,17 +716,32 @@ func ListVotes() web.HandlerFunc {
 			return c.Failure(err)
 		}
 
+		limit, err := c.QueryParamAsInt("limit")
+		if err != nil || limit < 0 {
+			return c.BadRequest(web.Map{"error": "Invalid limit"})
+		}
+		if !validCursor(c, timestampCursorKey) {
+			return c.BadRequest(web.Map{"error": "Invalid cursor"})
+		}
+
 		includeEmail := c.User() != nil && c.User().IsCollaborator()
-		listVotes := &query.ListPostVotes{PostID: getPost.Result.ID, IncludeEmail: includeEmail}
+		listVotes := &query.ListPostVotes{
+			PostID:       getPost.Result.ID,
+			Limit:        limit,
+			IncludeEmail: includeEmail,
+			Cursor:       c.QueryParam("cursor"),
+			CountTotal:   true,
+		}
 		if err := bus.Dispatch(c, listVotes); err != nil {
 			return c.Failure(err)
 		}
 
+		setPaginationHeaders(c, listVotes.TotalCount, listVotes.NextCursor)
 		return c.Ok(listVotes.Result)
 	}
 }
 
-func addOrRemove(c *web.Context, getCommand func(post *entity.Post, user *entity.User) bus.Msg) error {
+func addOrRemove(c *web.Context, getCommand func(post *entity.Post, user *entity.User) bus.Msg, onSuccess func(post *entity.Post)) error {
 	number, err := c.ParamAsInt("number")
 	if err != nil {
 		return c.NotFound()
@@ -566,5 +757,9 @@ func addOrRemove(c *web.Context, getCommand func(post *entity.Post, user *entity
 		return c.Failure(err)
 	}
 
+	if onSuccess != nil {
+		onSuccess(getPost.Result)
+	}
+
 	return c.Ok(web.Map{})
 }
@@ -0,0 +1,73 @@
+package apiv1
+
+import (
+	"github.com/getfider/fider/app/actions"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+// ListPostStatuses returns all custom post statuses of current tenant
+func ListPostStatuses() web.HandlerFunc {
+	return func(c *web.Context) error {
+		q := &query.GetCustomPostStatuses{}
+		if err := bus.Dispatch(c, q); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(q.Result)
+	}
+}
+
+// CreateEditPostStatus creates a new custom post status on current tenant or edits an existing one
+func CreateEditPostStatus() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.CreateEditPostStatus)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		if action.Slug != "" {
+			updateStatus := &cmd.UpdateCustomPostStatus{
+				Status:        action.Status,
+				Name:          action.Name,
+				Color:         action.Color,
+				AcceptsVotes:  action.AcceptsVotes,
+				RoadmapColumn: action.RoadmapColumn,
+			}
+			if err := bus.Dispatch(c, updateStatus); err != nil {
+				return c.Failure(err)
+			}
+			return c.Ok(updateStatus.Result)
+		}
+
+		addStatus := &cmd.AddCustomPostStatus{
+			Name:          action.Name,
+			Color:         action.Color,
+			AcceptsVotes:  action.AcceptsVotes,
+			RoadmapColumn: action.RoadmapColumn,
+		}
+		if err := bus.Dispatch(c, addStatus); err != nil {
+			return c.Failure(err)
+		}
+		return c.Ok(addStatus.Result)
+	}
+}
+
+// DeletePostStatus deletes an existing custom post status
+func DeletePostStatus() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.DeletePostStatus)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		err := bus.Dispatch(c, &cmd.DeleteCustomPostStatus{Status: action.Status})
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
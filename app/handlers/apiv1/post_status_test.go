@@ -0,0 +1,89 @@
+package apiv1_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/handlers/apiv1"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/mock"
+)
+
+func TestCreatePostStatusHandler_ValidRequest(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCustomPostStatusBySlug) error {
+		return app.ErrNotFound
+	})
+
+	var addStatus *cmd.AddCustomPostStatus
+	bus.AddHandler(func(ctx context.Context, c *cmd.AddCustomPostStatus) error {
+		addStatus = c
+		return nil
+	})
+
+	server := mock.NewServer()
+	status, _ := server.
+		AsUser(mock.JonSnow).
+		ExecutePost(
+			apiv1.CreateEditPostStatus(),
+			`{ "name": "In beta", "color": "00ff00", "acceptsVotes": true, "roadmapColumn": "started" }`,
+		)
+
+	Expect(status).Equals(http.StatusOK)
+	Expect(addStatus.Name).Equals("In beta")
+	Expect(addStatus.Color).Equals("00FF00")
+	Expect(addStatus.AcceptsVotes).IsTrue()
+	Expect(addStatus.RoadmapColumn).Equals("started")
+}
+
+func TestCreatePostStatusHandler_Collaborator(t *testing.T) {
+	RegisterT(t)
+
+	server := mock.NewServer()
+	status, _ := server.
+		AsUser(mock.AryaStark).
+		ExecutePost(
+			apiv1.CreateEditPostStatus(),
+			`{ "name": "In beta", "color": "00FF00" }`,
+		)
+
+	Expect(status).Equals(http.StatusForbidden)
+}
+
+func TestEditExistingPostStatusHandler(t *testing.T) {
+	RegisterT(t)
+
+	existing := &entity.CustomPostStatus{Status: 100, Name: "Under review", Slug: "under-review", Color: "0000FF"}
+	bus.AddHandler(func(ctx context.Context, q *query.GetCustomPostStatusBySlug) error {
+		q.Result = existing
+		return nil
+	})
+
+	var updateStatus *cmd.UpdateCustomPostStatus
+	bus.AddHandler(func(ctx context.Context, c *cmd.UpdateCustomPostStatus) error {
+		updateStatus = c
+		return nil
+	})
+
+	server := mock.NewServer()
+	status, _ := server.
+		AsUser(mock.JonSnow).
+		AddParam("slug", existing.Slug).
+		ExecutePost(
+			apiv1.CreateEditPostStatus(),
+			`{ "name": "Under review", "color": "FF0000", "acceptsVotes": false, "roadmapColumn": "planned" }`,
+		)
+
+	Expect(status).Equals(http.StatusOK)
+	Expect(updateStatus.Status).Equals(existing)
+	Expect(updateStatus.Color).Equals("FF0000")
+	Expect(updateStatus.AcceptsVotes).IsFalse()
+	Expect(updateStatus.RoadmapColumn).Equals("planned")
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/getfider/fider/app/handlers/apiv1"
 	. "github.com/getfider/fider/app/pkg/assert"
 	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/cursor"
 	"github.com/getfider/fider/app/pkg/env"
 	"github.com/getfider/fider/app/pkg/mock"
 )
@@ -668,6 +669,83 @@ func TestSetResponseHandler_Duplicate_Itself(t *testing.T) {
 	Expect(code).Equals(http.StatusBadRequest)
 }
 
+func TestMergePostsHandler(t *testing.T) {
+	RegisterT(t)
+
+	post1 := &entity.Post{ID: 1, Number: 1, Title: "The Post #1", Description: "The Description #1", VotesCount: 3}
+	post2 := &entity.Post{ID: 2, Number: 2, Title: "The Post #2", Description: "The Description #2", VotesCount: 1}
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		if q.Number == post1.Number {
+			q.Result = post1
+			return nil
+		}
+		if q.Number == post2.Number {
+			q.Result = post2
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	var mergePosts *cmd.MergePosts
+	bus.AddHandler(func(ctx context.Context, c *cmd.MergePosts) error {
+		mergePosts = c
+		c.Result = &entity.Post{ID: 1, Number: 1, Title: "The Post #1", VotesCount: 4}
+		c.MovedVoters = []*entity.User{mock.AryaStark}
+		return nil
+	})
+
+	code, json := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("number", post2.Number).
+		ExecutePostAsJSON(apiv1.MergePosts(), `{ "originalNumber": 1, "mergeComments": true }`)
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(mergePosts.Post).Equals(post2)
+	Expect(mergePosts.Original).Equals(post1)
+	Expect(mergePosts.MergeComments).IsTrue()
+	Expect(json.Int32("votesCount")).Equals(4)
+	Expect(json.Int32("movedVotes")).Equals(1)
+}
+
+func TestMergePostsHandler_Unauthorized(t *testing.T) {
+	RegisterT(t)
+
+	code, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.AryaStark).
+		AddParam("number", 2).
+		ExecutePost(apiv1.MergePosts(), `{ "originalNumber": 1 }`)
+
+	Expect(code).Equals(http.StatusForbidden)
+}
+
+func TestMergePostsHandler_IntoDuplicate(t *testing.T) {
+	RegisterT(t)
+
+	post1 := &entity.Post{ID: 1, Number: 1, Title: "The Post #1", Status: enum.PostDuplicate}
+	post2 := &entity.Post{ID: 2, Number: 2, Title: "The Post #2"}
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		if q.Number == post1.Number {
+			q.Result = post1
+			return nil
+		}
+		if q.Number == post2.Number {
+			q.Result = post2
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	code, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("number", post2.Number).
+		ExecutePost(apiv1.MergePosts(), `{ "originalNumber": 1 }`)
+
+	Expect(code).Equals(http.StatusBadRequest)
+}
+
 func TestAddVoteHandler(t *testing.T) {
 	RegisterT(t)
 
@@ -677,6 +755,11 @@ func TestAddVoteHandler(t *testing.T) {
 		return nil
 	})
 
+	bus.AddHandler(func(ctx context.Context, q *query.GetVoteBudget) error {
+		q.Result = &entity.VoteBudget{}
+		return nil
+	})
+
 	var addVote *cmd.AddVote
 	bus.AddHandler(func(ctx context.Context, c *cmd.AddVote) error {
 		addVote = c
@@ -692,6 +775,120 @@ func TestAddVoteHandler(t *testing.T) {
 	Expect(code).Equals(http.StatusOK)
 	Expect(addVote.Post).Equals(post)
 	Expect(addVote.User).Equals(mock.AryaStark)
+	Expect(addVote.Weight).Equals(1)
+}
+
+func TestAddVoteHandler_WithBudget(t *testing.T) {
+	RegisterT(t)
+
+	post := &entity.Post{ID: 1, Number: 1, Title: "The Post #1", Description: "The Description #1", HasVoted: true, VoteWeight: 1}
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = post
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetVoteBudget) error {
+		q.Result = &entity.VoteBudget{Total: 10, Spent: 6}
+		return nil
+	})
+
+	var addVote *cmd.AddVote
+	bus.AddHandler(func(ctx context.Context, c *cmd.AddVote) error {
+		addVote = c
+		return nil
+	})
+
+	server := mock.NewServer()
+	mock.DemoTenant.VoteBudget = 10
+	mock.DemoTenant.MaxVotesPerPost = 5
+
+	code, json := server.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.AryaStark).
+		AddParam("number", post.Number).
+		ExecutePostAsJSON(apiv1.AddVote(), `{ "amount": 3 }`)
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(addVote.Weight).Equals(3)
+	Expect(json.Int32("remaining")).Equals(2)
+
+	server = mock.NewServer()
+	mock.DemoTenant.VoteBudget = 10
+	mock.DemoTenant.MaxVotesPerPost = 5
+
+	code, _ = server.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.AryaStark).
+		AddParam("number", post.Number).
+		ExecutePost(apiv1.AddVote(), `{ "amount": 6 }`)
+
+	Expect(code).Equals(http.StatusBadRequest)
+}
+
+func TestAddVoteHandler_BudgetSpentConcurrently(t *testing.T) {
+	RegisterT(t)
+
+	post := &entity.Post{ID: 1, Number: 1, Title: "The Post #1", Description: "The Description #1"}
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = post
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetVoteBudget) error {
+		q.Result = &entity.VoteBudget{Total: 10, Spent: 6}
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, c *cmd.AddVote) error {
+		return app.ErrVoteBudgetExceeded
+	})
+
+	server := mock.NewServer()
+	mock.DemoTenant.VoteBudget = 10
+	mock.DemoTenant.MaxVotesPerPost = 5
+
+	code, _ := server.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.AryaStark).
+		AddParam("number", post.Number).
+		ExecutePost(apiv1.AddVote(), `{ "amount": 3 }`)
+
+	Expect(code).Equals(http.StatusBadRequest)
+}
+
+func TestToggleVoteHandler_BudgetSpentConcurrently(t *testing.T) {
+	RegisterT(t)
+
+	post := &entity.Post{ID: 1, Number: 1, Title: "The Post #1", Description: "The Description #1"}
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = post
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.ListPostVotes) error {
+		q.Result = []*entity.Vote{}
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetVoteBudget) error {
+		q.Result = &entity.VoteBudget{Total: 10, Spent: 9}
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, c *cmd.AddVote) error {
+		return app.ErrVoteBudgetExceeded
+	})
+
+	mock.DemoTenant.VoteBudget = 10
+	mock.DemoTenant.MaxVotesPerPost = 5
+
+	code, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.AryaStark).
+		AddParam("number", post.Number).
+		ExecutePost(apiv1.ToggleVote(), `{ "amount": 1 }`)
+
+	Expect(code).Equals(http.StatusBadRequest)
 }
 
 func TestAddVoteHandler_InvalidPost(t *testing.T) {
@@ -846,6 +1043,72 @@ func TestPostCommentHandler_WithoutContent(t *testing.T) {
 	Expect(code).Equals(http.StatusBadRequest)
 }
 
+func TestPostCommentHandler_Reply(t *testing.T) {
+	RegisterT(t)
+
+	post := &entity.Post{ID: 1, Number: 1, Title: "The Post #1", Description: "The Description #1"}
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = post
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCommentByID) error {
+		if q.CommentID == 5 {
+			q.Result = &entity.Comment{ID: 5, PostID: post.ID, Content: "Staff only", IsInternal: true, User: mock.AryaStark}
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	var newComment *cmd.AddNewComment
+	bus.AddHandler(func(ctx context.Context, c *cmd.AddNewComment) error {
+		newComment = c
+		c.Result = &entity.Comment{ID: 6, Content: c.Content}
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, c *cmd.SetAttachments) error { return nil })
+	bus.AddHandler(func(ctx context.Context, c *cmd.UploadImages) error { return nil })
+
+	code, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("number", post.Number).
+		ExecutePost(apiv1.PostComment(), `{ "content": "I agree", "parentId": 5 }`)
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(newComment.ParentID).Equals(5)
+	Expect(newComment.IsInternal).IsTrue()
+}
+
+func TestPostCommentHandler_ReplyToInvalidParent(t *testing.T) {
+	RegisterT(t)
+
+	post := &entity.Post{ID: 1, Number: 1, Title: "The Post #1", Description: "The Description #1"}
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = post
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCommentByID) error {
+		if q.CommentID == 5 {
+			q.Result = &entity.Comment{ID: 5, PostID: 2, Content: "On another post", User: mock.AryaStark}
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	for _, parentID := range []int{5, 9} {
+		code, _ := mock.NewServer().
+			OnTenant(mock.DemoTenant).
+			AsUser(mock.JonSnow).
+			AddParam("number", post.Number).
+			ExecutePost(apiv1.PostComment(), fmt.Sprintf(`{ "content": "I agree", "parentId": %d }`, parentID))
+
+		Expect(code).Equals(http.StatusBadRequest)
+	}
+}
+
 func TestUpdateCommentHandler_Authorized(t *testing.T) {
 	RegisterT(t)
 
@@ -947,7 +1210,12 @@ func TestListCommentHandler(t *testing.T) {
 func TestCommentReactionToggleHandler(t *testing.T) {
 	RegisterT(t)
 
-	comment := &entity.Comment{ID: 5, Content: "Old comment text", User: mock.AryaStark}
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = &entity.Post{ID: 1, Number: 1}
+		return nil
+	})
+
+	comment := &entity.Comment{ID: 5, PostID: 1, Content: "Old comment text", User: mock.AryaStark}
 
 	bus.AddHandler(func(ctx context.Context, q *query.GetCommentByID) error {
 		q.Result = comment
@@ -1037,6 +1305,11 @@ func TestCommentReactionToggleHandler_UnAuthorised(t *testing.T) {
 func TestCommentReactionToggleHandler_MismatchingTenantAndComment(t *testing.T) {
 	RegisterT(t)
 
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = &entity.Post{ID: 1, Number: 1}
+		return nil
+	})
+
 	bus.AddHandler(func(ctx context.Context, q *query.GetCommentByID) error {
 		return app.ErrNotFound
 	})
@@ -1055,3 +1328,320 @@ func TestCommentReactionToggleHandler_MismatchingTenantAndComment(t *testing.T)
 
 	Expect(code).Equals(http.StatusNotFound)
 }
+
+func TestCommentReactionToggleHandler_CommentOfAnotherPost(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = &entity.Post{ID: 1, Number: 1}
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCommentByID) error {
+		q.Result = &entity.Comment{ID: 5, PostID: 2, Content: "On a private board", User: mock.AryaStark}
+		return nil
+	})
+
+	toggled := false
+	bus.AddHandler(func(ctx context.Context, c *cmd.ToggleCommentReaction) error {
+		toggled = true
+		return nil
+	})
+
+	code, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.AryaStark).
+		AddParam("number", 1).
+		AddParam("id", 5).
+		AddParam("reaction", "👍").
+		ExecutePost(apiv1.ToggleReaction(), ``)
+
+	Expect(code).Equals(http.StatusNotFound)
+	Expect(toggled).IsFalse()
+}
+
+func TestGetCommentHandler(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		if q.Number == 1 {
+			q.Result = &entity.Post{ID: 1, Number: 1}
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCommentByID) error {
+		q.Result = &entity.Comment{ID: 5, PostID: 1, Content: "Hello", User: mock.AryaStark}
+		return nil
+	})
+
+	code, response := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AddParam("number", 1).
+		AddParam("id", 5).
+		ExecuteAsJSON(apiv1.GetComment())
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(response.String("content")).Equals("Hello")
+
+	// Post #2 is on a private board, so it isn't found for anonymous users
+	code, _ = mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AddParam("number", 2).
+		AddParam("id", 5).
+		Execute(apiv1.GetComment())
+
+	Expect(code).Equals(http.StatusNotFound)
+}
+
+func TestSearchPostsHandler_Pagination(t *testing.T) {
+	RegisterT(t)
+
+	var searchPosts *query.SearchPosts
+	bus.AddHandler(func(ctx context.Context, q *query.SearchPosts) error {
+		searchPosts = q
+		q.Result = []*entity.Post{{ID: 2, Number: 2}}
+		q.TotalCount = 5
+		q.NextCursor = "next-page"
+		return nil
+	})
+
+	code, response := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		WithURL("http://demo.test.fider.io/api/v1/posts?view=recent&limit=1&cursor=" + cursor.Encode("3", 3)).
+		Execute(apiv1.SearchPosts())
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(searchPosts.View).Equals("recent")
+	Expect(searchPosts.Limit).Equals("1")
+	Expect(searchPosts.Cursor).Equals(cursor.Encode("3", 3))
+	Expect(searchPosts.CountTotal).IsTrue()
+	Expect(response.Header().Get("X-Total-Count")).Equals("5")
+	Expect(response.Header().Get("Link")).Equals(`<http://demo.test.fider.io/api/v1/posts?cursor=next-page&limit=1&view=recent>; rel="next"`)
+}
+
+func TestSearchPostsHandler_TrendingCursor(t *testing.T) {
+	RegisterT(t)
+
+	var searchPosts *query.SearchPosts
+	bus.AddHandler(func(ctx context.Context, q *query.SearchPosts) error {
+		searchPosts = q
+		return nil
+	})
+
+	next := cursor.Encode("0.0123@2026-10-18T10:00:00.123456Z", 3)
+	code, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		WithURL("http://demo.test.fider.io/api/v1/posts?view=trending&cursor=" + next).
+		Execute(apiv1.SearchPosts())
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(searchPosts.Cursor).Equals(next)
+}
+
+func TestSearchPostsHandler_LastPage(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.SearchPosts) error {
+		q.Result = []*entity.Post{}
+		q.TotalCount = 0
+		return nil
+	})
+
+	code, response := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		WithURL("http://demo.test.fider.io/api/v1/posts").
+		Execute(apiv1.SearchPosts())
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(response.Header().Get("X-Total-Count")).Equals("0")
+	Expect(response.Header().Get("Link")).Equals("")
+}
+
+func TestSearchPostsHandler_IncludeComments(t *testing.T) {
+	RegisterT(t)
+
+	var searchPosts *query.SearchPosts
+	bus.AddHandler(func(ctx context.Context, q *query.SearchPosts) error {
+		searchPosts = q
+		q.Result = []*entity.Post{{ID: 1, Number: 1, Snippet: "A <mark>dark</mark> theme"}}
+		q.TotalCount = 1
+		return nil
+	})
+
+	code, response := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		WithURL("http://demo.test.fider.io/api/v1/posts?query=dark&comments=true").
+		ExecuteAsJSON(apiv1.SearchPosts())
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(searchPosts.Query).Equals("dark")
+	Expect(searchPosts.IncludeComments).IsTrue()
+	Expect(response.ArrayLength()).Equals(1)
+}
+
+func TestSearchPostsHandler_InvalidCursor(t *testing.T) {
+	RegisterT(t)
+
+	for _, value := range []string{"not-a-cursor", cursor.Encode("abc", 3), cursor.Encode("NaN", 3), cursor.Encode("2026-10-18T10:00:00Z", 3), cursor.Encode("0.5@yesterday", 3)} {
+		code, _ := mock.NewServer().
+			OnTenant(mock.DemoTenant).
+			WithURL("http://demo.test.fider.io/api/v1/posts?cursor=" + value).
+			Execute(apiv1.SearchPosts())
+
+		Expect(code).Equals(http.StatusBadRequest)
+	}
+}
+
+func TestListCommentsHandler_Pagination(t *testing.T) {
+	RegisterT(t)
+
+	post := &entity.Post{ID: 1, Number: 1, Title: "The Post #1"}
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = post
+		return nil
+	})
+
+	var getComments *query.GetCommentsByPost
+	bus.AddHandler(func(ctx context.Context, q *query.GetCommentsByPost) error {
+		getComments = q
+		q.Result = []*entity.Comment{{ID: 4, Content: "Hello"}}
+		q.TotalCount = 3
+		q.NextCursor = "next-page"
+		return nil
+	})
+
+	code, response := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AddParam("number", 1).
+		WithURL("http://demo.test.fider.io/api/v1/posts/1/comments?limit=1").
+		Execute(apiv1.ListComments())
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(getComments.Post).Equals(post)
+	Expect(getComments.Limit).Equals(1)
+	Expect(getComments.Cursor).Equals("")
+	Expect(response.Header().Get("X-Total-Count")).Equals("3")
+	Expect(response.Header().Get("Link")).Equals(`<http://demo.test.fider.io/api/v1/posts/1/comments?cursor=next-page&limit=1>; rel="next"`)
+}
+
+func TestListCommentsHandler_Threaded(t *testing.T) {
+	RegisterT(t)
+
+	post := &entity.Post{ID: 1, Number: 1, Title: "The Post #1"}
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = post
+		return nil
+	})
+
+	var getComments *query.GetCommentsByPost
+	bus.AddHandler(func(ctx context.Context, q *query.GetCommentsByPost) error {
+		getComments = q
+		parentID := 4
+		q.Result = []*entity.Comment{
+			{ID: 4, Content: "Hello", Replies: []*entity.Comment{
+				{ID: 7, Content: "Hi @[Jon Snow]", ParentID: &parentID},
+			}},
+		}
+		return nil
+	})
+
+	code, query := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AddParam("number", 1).
+		WithURL("http://demo.test.fider.io/api/v1/posts/1/comments?threaded=true").
+		ExecuteAsJSON(apiv1.ListComments())
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(getComments.Threaded).IsTrue()
+	Expect(query.IsArray()).IsTrue()
+	Expect(query.ArrayLength()).Equals(1)
+
+	Expect(getComments.Result[0].Replies[0].Content).Equals("Hi @Jon Snow")
+}
+
+func TestListCommentsHandler_InvalidThreaded(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = &entity.Post{ID: 1, Number: 1, Title: "The Post #1"}
+		return nil
+	})
+
+	code, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AddParam("number", 1).
+		WithURL("http://demo.test.fider.io/api/v1/posts/1/comments?threaded=maybe").
+		Execute(apiv1.ListComments())
+
+	Expect(code).Equals(http.StatusBadRequest)
+}
+
+func TestListVotesHandler_Pagination(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = &entity.Post{ID: 7, Number: 1}
+		return nil
+	})
+
+	var listVotes *query.ListPostVotes
+	bus.AddHandler(func(ctx context.Context, q *query.ListPostVotes) error {
+		listVotes = q
+		q.Result = []*entity.Vote{}
+		q.TotalCount = 2
+		return nil
+	})
+
+	nextPage := cursor.Encode("2026-10-18T10:00:00Z", 3)
+	code, response := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AddParam("number", 1).
+		WithURL("http://demo.test.fider.io/api/v1/posts/1/votes?limit=10&cursor=" + nextPage).
+		Execute(apiv1.ListVotes())
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(listVotes.PostID).Equals(7)
+	Expect(listVotes.Limit).Equals(10)
+	Expect(listVotes.Cursor).Equals(nextPage)
+	Expect(listVotes.IncludeEmail).IsFalse()
+	Expect(response.Header().Get("X-Total-Count")).Equals("2")
+	Expect(response.Header().Get("Link")).Equals("")
+}
+
+func TestListVotesHandler_InvalidCursor(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = &entity.Post{ID: 7, Number: 1}
+		return nil
+	})
+
+	for _, value := range []string{"not-a-cursor", cursor.Encode("3", 3), cursor.Encode("2026-10-18", 3)} {
+		code, _ := mock.NewServer().
+			OnTenant(mock.DemoTenant).
+			AddParam("number", 1).
+			WithURL("http://demo.test.fider.io/api/v1/posts/1/votes?limit=10&cursor=" + value).
+			Execute(apiv1.ListVotes())
+
+		Expect(code).Equals(http.StatusBadRequest)
+	}
+}
+
+func TestListVotesHandler_InvalidLimit(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = &entity.Post{ID: 7, Number: 1}
+		return nil
+	})
+
+	code, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AddParam("number", 1).
+		WithURL("http://demo.test.fider.io/api/v1/posts/1/votes?limit=abc").
+		Execute(apiv1.ListVotes())
+
+	Expect(code).Equals(http.StatusBadRequest)
+}
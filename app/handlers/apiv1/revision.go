@@ -0,0 +1,183 @@
+package apiv1
+
+import (
+	"github.com/getfider/fider/app/actions"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/diff"
+	"github.com/getfider/fider/app/pkg/web"
+	"github.com/getfider/fider/app/tasks"
+)
+
+// ListPostRevisions returns every revision of a post, oldest first
+func ListPostRevisions() web.HandlerFunc {
+	return func(c *web.Context) error {
+		number, err := c.ParamAsInt("number")
+		if err != nil {
+			return c.NotFound()
+		}
+
+		getPost := &query.GetPostByNumber{Number: number}
+		if err := bus.Dispatch(c, getPost); err != nil {
+			return c.Failure(err)
+		}
+
+		listRevisions := &query.ListPostRevisions{PostID: getPost.Result.ID}
+		if err := bus.Dispatch(c, listRevisions); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(listRevisions.Result)
+	}
+}
+
+// DiffPostRevisions returns the changes between two revisions of a post
+func DiffPostRevisions() web.HandlerFunc {
+	return func(c *web.Context) error {
+		number, err := c.ParamAsInt("number")
+		if err != nil {
+			return c.NotFound()
+		}
+
+		from, to, ok := diffVersions(c)
+		if !ok {
+			return c.BadRequest(web.Map{"error": "Invalid versions"})
+		}
+
+		getPost := &query.GetPostByNumber{Number: number}
+		if err := bus.Dispatch(c, getPost); err != nil {
+			return c.Failure(err)
+		}
+
+		fromRevision := &query.GetPostRevision{PostID: getPost.Result.ID, Version: from}
+		toRevision := &query.GetPostRevision{PostID: getPost.Result.ID, Version: to}
+		if err := bus.Dispatch(c, fromRevision, toRevision); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{
+			"from":    from,
+			"to":      to,
+			"title":   diff.Lines(fromRevision.Result.Title, toRevision.Result.Title),
+			"content": diff.Lines(fromRevision.Result.Content, toRevision.Result.Content),
+		})
+	}
+}
+
+// RevertPostRevision restores the title and description of a post to a previous revision
+func RevertPostRevision() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.RevertPostRevision)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		updatePost := &cmd.UpdatePost{
+			Post:        action.Post,
+			Title:       action.Revision.Title,
+			Description: action.Revision.Content,
+		}
+		if err := bus.Dispatch(c, updatePost); err != nil {
+			return c.Failure(err)
+		}
+
+		c.Enqueue(tasks.NotifyAboutUpdatedPost(updatePost.Result))
+
+		return c.Ok(updatePost.Result)
+	}
+}
+
+// ListCommentRevisions returns every revision of a comment, oldest first
+func ListCommentRevisions() web.HandlerFunc {
+	return func(c *web.Context) error {
+		comment, err := getPostComment(c)
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		listRevisions := &query.ListCommentRevisions{CommentID: comment.ID}
+		if err := bus.Dispatch(c, listRevisions); err != nil {
+			return c.Failure(err)
+		}
+
+		for _, revision := range listRevisions.Result {
+			commentString := entity.CommentString(revision.Content)
+			revision.Content = commentString.SanitizeMentions()
+		}
+
+		return c.Ok(listRevisions.Result)
+	}
+}
+
+// DiffCommentRevisions returns the changes between two revisions of a comment
+func DiffCommentRevisions() web.HandlerFunc {
+	return func(c *web.Context) error {
+		from, to, ok := diffVersions(c)
+		if !ok {
+			return c.BadRequest(web.Map{"error": "Invalid versions"})
+		}
+
+		comment, err := getPostComment(c)
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		fromRevision := &query.GetCommentRevision{CommentID: comment.ID, Version: from}
+		toRevision := &query.GetCommentRevision{CommentID: comment.ID, Version: to}
+		if err := bus.Dispatch(c, fromRevision, toRevision); err != nil {
+			return c.Failure(err)
+		}
+
+		fromContent := entity.CommentString(fromRevision.Result.Content)
+		toContent := entity.CommentString(toRevision.Result.Content)
+		return c.Ok(web.Map{
+			"from":    from,
+			"to":      to,
+			"content": diff.Lines(fromContent.SanitizeMentions(), toContent.SanitizeMentions()),
+		})
+	}
+}
+
+// RevertCommentRevision restores the content of a comment to a previous revision
+func RevertCommentRevision() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.RevertCommentRevision)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		err := bus.Dispatch(c, &cmd.UpdateComment{
+			CommentID: action.Comment.ID,
+			Content:   action.Revision.Content,
+		})
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		comment := &entity.Comment{
+			ID:         action.Comment.ID,
+			Content:    action.Revision.Content,
+			CreatedAt:  action.Comment.CreatedAt,
+			User:       action.Comment.User,
+			IsInternal: action.Comment.IsInternal,
+		}
+		c.Enqueue(tasks.NotifyAboutUpdatedComment(action.Post, comment))
+
+		return c.Ok(web.Map{})
+	}
+}
+
+// diffVersions returns the versions to compare from the querystring
+func diffVersions(c *web.Context) (int, int, bool) {
+	from, err := c.QueryParamAsInt("from")
+	if err != nil || from <= 0 {
+		return 0, 0, false
+	}
+	to, err := c.QueryParamAsInt("to")
+	if err != nil || to <= 0 {
+		return 0, 0, false
+	}
+	return from, to, true
+}
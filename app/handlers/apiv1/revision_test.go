@@ -0,0 +1,316 @@
+package apiv1_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/handlers/apiv1"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/mock"
+)
+
+var postRevisions = []*entity.Revision{
+	{Version: 1, Title: "Add dark mode", Content: "It hurts my eyes", CreatedBy: mock.AryaStark},
+	{Version: 2, Title: "Add light mode", Content: "It hurts my eyes\nPlease", CreatedBy: mock.AryaStark},
+}
+
+func mockPostRevisions(post *entity.Post) {
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		if q.Number == post.Number {
+			q.Result = post
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.ListPostRevisions) error {
+		q.Result = postRevisions
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostRevision) error {
+		if q.PostID == post.ID && q.Version > 0 && q.Version <= len(postRevisions) {
+			q.Result = postRevisions[q.Version-1]
+			return nil
+		}
+		return app.ErrNotFound
+	})
+}
+
+func TestListPostRevisionsHandler(t *testing.T) {
+	RegisterT(t)
+
+	mockPostRevisions(&entity.Post{ID: 5, Number: 5, Title: "Add light mode"})
+
+	code, response := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("number", 5).
+		Execute(apiv1.ListPostRevisions())
+
+	Expect(code).Equals(http.StatusOK)
+	revisions := []*entity.Revision{}
+	Expect(json.Unmarshal(response.Body.Bytes(), &revisions)).IsNil()
+	Expect(revisions).HasLen(2)
+	Expect(revisions[0].Version).Equals(1)
+	Expect(revisions[0].Title).Equals("Add dark mode")
+	Expect(revisions[1].Version).Equals(2)
+}
+
+func TestDiffPostRevisionsHandler(t *testing.T) {
+	RegisterT(t)
+
+	mockPostRevisions(&entity.Post{ID: 5, Number: 5, Title: "Add light mode"})
+
+	code, query := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		WithURL("http://demo.test.fider.io/api/v1/posts/5/revisions/diff?from=1&to=2").
+		AddParam("number", 5).
+		ExecuteAsJSON(apiv1.DiffPostRevisions())
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(query.Int32("from")).Equals(1)
+	Expect(query.Int32("to")).Equals(2)
+	Expect(query.String("title[0].op")).Equals("delete")
+	Expect(query.String("title[1].op")).Equals("insert")
+	Expect(query.String("title[1].text")).Equals("Add light mode")
+	Expect(query.String("content[0].op")).Equals("equal")
+	Expect(query.String("content[1].op")).Equals("insert")
+	Expect(query.String("content[1].text")).Equals("Please")
+}
+
+func TestDiffPostRevisionsHandler_InvalidVersions(t *testing.T) {
+	RegisterT(t)
+
+	mockPostRevisions(&entity.Post{ID: 5, Number: 5, Title: "Add light mode"})
+
+	code, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		WithURL("http://demo.test.fider.io/api/v1/posts/5/revisions/diff?from=abc&to=2").
+		AddParam("number", 5).
+		Execute(apiv1.DiffPostRevisions())
+
+	Expect(code).Equals(http.StatusBadRequest)
+
+	code, _ = mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		WithURL("http://demo.test.fider.io/api/v1/posts/5/revisions/diff?from=1&to=9").
+		AddParam("number", 5).
+		Execute(apiv1.DiffPostRevisions())
+
+	Expect(code).Equals(http.StatusNotFound)
+}
+
+func TestRevertPostRevisionHandler(t *testing.T) {
+	RegisterT(t)
+
+	post := &entity.Post{ID: 5, Number: 5, Title: "Add light mode", Slug: "add-light-mode"}
+	mockPostRevisions(post)
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostBySlug) error { return app.ErrNotFound })
+
+	var updatePost *cmd.UpdatePost
+	bus.AddHandler(func(ctx context.Context, c *cmd.UpdatePost) error {
+		updatePost = c
+		c.Result = post
+		return nil
+	})
+
+	code, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("number", post.Number).
+		AddParam("version", 1).
+		ExecutePost(apiv1.RevertPostRevision(), "")
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(updatePost.Post).Equals(post)
+	Expect(updatePost.Title).Equals("Add dark mode")
+	Expect(updatePost.Description).Equals("It hurts my eyes")
+}
+
+func TestRevertPostRevisionHandler_Unauthorized(t *testing.T) {
+	RegisterT(t)
+
+	mockPostRevisions(&entity.Post{ID: 5, Number: 5, Title: "Add light mode", User: mock.AryaStark})
+
+	code, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.AryaStark).
+		AddParam("number", 5).
+		AddParam("version", 1).
+		ExecutePost(apiv1.RevertPostRevision(), "")
+
+	Expect(code).Equals(http.StatusForbidden)
+}
+
+func TestRevertPostRevisionHandler_DuplicateTitle(t *testing.T) {
+	RegisterT(t)
+
+	mockPostRevisions(&entity.Post{ID: 5, Number: 5, Title: "Add light mode"})
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostBySlug) error {
+		q.Result = &entity.Post{ID: 6, Number: 6, Title: "Add dark mode", Slug: "add-dark-mode"}
+		return nil
+	})
+
+	code, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("number", 5).
+		AddParam("version", 1).
+		ExecutePost(apiv1.RevertPostRevision(), "")
+
+	Expect(code).Equals(http.StatusBadRequest)
+}
+
+func TestRevertCommentRevisionHandler(t *testing.T) {
+	RegisterT(t)
+
+	post := &entity.Post{ID: 1, Number: 1, Title: "The Post #1"}
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = post
+		return nil
+	})
+
+	comment := &entity.Comment{ID: 5, PostID: post.ID, Content: "Edited comment", User: mock.AryaStark}
+	bus.AddHandler(func(ctx context.Context, q *query.GetCommentByID) error {
+		q.Result = comment
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCommentRevision) error {
+		if q.CommentID == comment.ID && q.Version == 1 {
+			q.Result = &entity.Revision{Version: 1, Content: "Original comment", CreatedBy: mock.AryaStark}
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	var updateComment *cmd.UpdateComment
+	bus.AddHandler(func(ctx context.Context, c *cmd.UpdateComment) error {
+		updateComment = c
+		return nil
+	})
+
+	code, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("number", post.Number).
+		AddParam("id", comment.ID).
+		AddParam("version", 1).
+		ExecutePost(apiv1.RevertCommentRevision(), "")
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(updateComment.CommentID).Equals(comment.ID)
+	Expect(updateComment.Content).Equals("Original comment")
+
+	code, _ = mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("number", post.Number).
+		AddParam("id", comment.ID).
+		AddParam("version", 2).
+		ExecutePost(apiv1.RevertCommentRevision(), "")
+
+	Expect(code).Equals(http.StatusNotFound)
+}
+
+func mockCommentRevisions(posts ...*entity.Post) *entity.Comment {
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		for _, post := range posts {
+			if q.Number == post.Number {
+				q.Result = post
+				return nil
+			}
+		}
+		return app.ErrNotFound
+	})
+
+	comment := &entity.Comment{ID: 5, PostID: posts[0].ID, Content: "Edited comment", User: mock.AryaStark}
+	bus.AddHandler(func(ctx context.Context, q *query.GetCommentByID) error {
+		q.Result = comment
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.ListCommentRevisions) error {
+		q.Result = []*entity.Revision{{Version: 1, Content: "Original comment", CreatedBy: mock.AryaStark}}
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCommentRevision) error {
+		q.Result = &entity.Revision{Version: q.Version, Content: "Original comment", CreatedBy: mock.AryaStark}
+		return nil
+	})
+
+	return comment
+}
+
+func TestListCommentRevisionsHandler_CommentOfAnotherPost(t *testing.T) {
+	RegisterT(t)
+
+	post := &entity.Post{ID: 1, Number: 1, Title: "The Post #1"}
+	otherPost := &entity.Post{ID: 2, Number: 2, Title: "The Post #2"}
+	comment := mockCommentRevisions(post, otherPost)
+
+	code, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("number", post.Number).
+		AddParam("id", comment.ID).
+		Execute(apiv1.ListCommentRevisions())
+
+	Expect(code).Equals(http.StatusOK)
+
+	code, _ = mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("number", otherPost.Number).
+		AddParam("id", comment.ID).
+		Execute(apiv1.ListCommentRevisions())
+
+	Expect(code).Equals(http.StatusNotFound)
+
+	code, _ = mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		WithURL("http://demo.test.fider.io/api/v1/posts/2/comments/5/revisions/diff?from=1&to=2").
+		AddParam("number", otherPost.Number).
+		AddParam("id", comment.ID).
+		Execute(apiv1.DiffCommentRevisions())
+
+	Expect(code).Equals(http.StatusNotFound)
+}
+
+func TestRevertCommentRevisionHandler_CommentOfAnotherPost(t *testing.T) {
+	RegisterT(t)
+
+	post := &entity.Post{ID: 1, Number: 1, Title: "The Post #1"}
+	otherPost := &entity.Post{ID: 2, Number: 2, Title: "The Post #2"}
+	comment := mockCommentRevisions(post, otherPost)
+
+	updated := false
+	bus.AddHandler(func(ctx context.Context, c *cmd.UpdateComment) error {
+		updated = true
+		return nil
+	})
+
+	code, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("number", otherPost.Number).
+		AddParam("id", comment.ID).
+		AddParam("version", 1).
+		ExecutePost(apiv1.RevertCommentRevision(), "")
+
+	Expect(code).Equals(http.StatusNotFound)
+	Expect(updated).IsFalse()
+}
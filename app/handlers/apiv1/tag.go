@@ -6,6 +6,7 @@ import (
 	"github.com/getfider/fider/app/models/query"
 	"github.com/getfider/fider/app/pkg/bus"
 	"github.com/getfider/fider/app/pkg/web"
+	"github.com/getfider/fider/app/tasks"
 )
 
 // ListTags returns all tags
@@ -33,6 +34,8 @@ func AssignTag() web.HandlerFunc {
 			return c.Failure(err)
 		}
 
+		c.Enqueue(tasks.NotifyAboutAssignedTag(action.Post, action.Tag))
+
 		return c.Ok(web.Map{})
 	}
 }
@@ -50,6 +53,8 @@ func UnassignTag() web.HandlerFunc {
 			return c.Failure(err)
 		}
 
+		c.Enqueue(tasks.NotifyAboutUnassignedTag(action.Post, action.Tag))
+
 		return c.Ok(web.Map{})
 	}
 }
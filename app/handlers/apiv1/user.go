@@ -1,6 +1,8 @@
 package apiv1
 
 import (
+	"fmt"
+
 	"github.com/getfider/fider/app"
 	"github.com/getfider/fider/app/actions"
 	"github.com/getfider/fider/app/models/cmd"
@@ -8,8 +10,10 @@ import (
 	"github.com/getfider/fider/app/models/enum"
 	"github.com/getfider/fider/app/models/query"
 	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/csv"
 	"github.com/getfider/fider/app/pkg/errors"
 	"github.com/getfider/fider/app/pkg/web"
+	"github.com/getfider/fider/app/tasks"
 )
 
 // ListUsers returns paginated registered users
@@ -36,9 +40,20 @@ func ListUsers() web.HandlerFunc {
 			return c.Failure(err)
 		}
 
+		userIDs := make([]int, len(searchUsers.Result))
+		for i, user := range searchUsers.Result {
+			userIDs[i] = user.ID
+		}
+
+		getAttributes := &query.GetUserAttributes{UserIDs: userIDs}
+		if err := bus.Dispatch(c, getAttributes); err != nil {
+			return c.Failure(err)
+		}
+
 		// Create an array of UserWithEmail structs to include email in JSON response
 		allUsersWithEmail := make([]entity.UserWithEmail, len(searchUsers.Result))
 		for i, user := range searchUsers.Result {
+			user.Attributes = getAttributes.Result[user.ID]
 			allUsersWithEmail[i] = entity.UserWithEmail{
 				User: user,
 			}
@@ -94,6 +109,9 @@ func CreateUser() web.HandlerFunc {
 					Role:   enum.RoleVisitor,
 				}
 				err = bus.Dispatch(c, &cmd.RegisterUser{User: user})
+				if err == nil {
+					c.Enqueue(tasks.NotifyAboutNewUser(user))
+				}
 			}
 		}
 
@@ -116,3 +134,110 @@ func CreateUser() web.HandlerFunc {
 		})
 	}
 }
+
+// SetUserAttributes sets attributes of given user, attributes with an empty value are removed
+func SetUserAttributes() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.SetUserAttributes)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		if err := bus.Dispatch(c, &cmd.SetUserAttributes{
+			UserID:     action.UserID,
+			Attributes: action.Attributes,
+		}); err != nil {
+			return c.Failure(err)
+		}
+
+		getAttributes := &query.GetUserAttributes{UserIDs: []int{action.UserID}}
+		if err := bus.Dispatch(c, getAttributes); err != nil {
+			return c.Failure(err)
+		}
+
+		attributes := getAttributes.Result[action.UserID]
+		if attributes == nil {
+			attributes = make(map[string]string)
+		}
+
+		return c.Ok(web.Map{
+			"attributes": attributes,
+		})
+	}
+}
+
+// ImportUserAttributesResult is returned after an import
+type ImportUserAttributesResult struct {
+	Updated int      `json:"updated"`
+	Skipped int      `json:"skipped"`
+	Errors  []string `json:"errors"`
+}
+
+// ImportUserAttributes sets attributes of multiple users from a CSV file
+// Users are identified by the email or reference columns and every other column is an attribute
+func ImportUserAttributes() web.HandlerFunc {
+	return func(c *web.Context) error {
+		if c.Request.ContentLength == 0 {
+			return c.BadRequest(web.Map{"error": "request body is empty"})
+		}
+
+		rows, err := csv.ToUserAttributes([]byte(c.Request.Body))
+		if err != nil {
+			return c.BadRequest(web.Map{"error": err.Error()})
+		}
+
+		result := ImportUserAttributesResult{Errors: make([]string, 0)}
+		for _, row := range rows {
+			user, err := findUserByEmailOrReference(c, row.Email, row.Reference)
+			if err != nil {
+				if errors.Cause(err) != app.ErrNotFound {
+					return c.Failure(err)
+				}
+				result.Errors = append(result.Errors, fmt.Sprintf("line %d: user not found", row.Line))
+				result.Skipped++
+				continue
+			}
+
+			action := &actions.SetUserAttributes{UserID: user.ID, Attributes: row.Attributes}
+			validation := action.Validate(c, c.User())
+			if validation.Err != nil {
+				return c.Failure(validation.Err)
+			}
+			if !validation.Ok {
+				for _, item := range validation.Errors {
+					result.Errors = append(result.Errors, fmt.Sprintf("line %d: %s", row.Line, item.Message))
+				}
+				result.Skipped++
+				continue
+			}
+
+			if err := bus.Dispatch(c, &cmd.SetUserAttributes{
+				UserID:     user.ID,
+				Attributes: row.Attributes,
+			}); err != nil {
+				return c.Failure(err)
+			}
+			result.Updated++
+		}
+
+		return c.Ok(result)
+	}
+}
+
+func findUserByEmailOrReference(c *web.Context, email, reference string) (*entity.User, error) {
+	if reference != "" {
+		getByReference := &query.GetUserByProvider{Provider: "reference", UID: reference}
+		err := bus.Dispatch(c, getByReference)
+		if err == nil || errors.Cause(err) != app.ErrNotFound || email == "" {
+			return getByReference.Result, err
+		}
+	}
+
+	if email == "" {
+		return nil, app.ErrNotFound
+	}
+
+	getByEmail := &query.GetUserByEmail{Email: email}
+	err := bus.Dispatch(c, getByEmail)
+	return getByEmail.Result, err
+}
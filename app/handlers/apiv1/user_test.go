@@ -29,6 +29,13 @@ func TestListUsersHandler(t *testing.T) {
 		return nil
 	})
 
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserAttributes) error {
+		q.Result = map[int]map[string]string{
+			2: {"plan": "enterprise"},
+		}
+		return nil
+	})
+
 	server := mock.NewServer()
 
 	status, query := server.
@@ -39,6 +46,8 @@ func TestListUsersHandler(t *testing.T) {
 	Expect(query.IsArray()).IsFalse() // Should be an object, not an array
 	Expect(query.Int32("totalCount")).Equals(2)
 	Expect(query.Contains("users")).IsTrue()
+	Expect(query.Contains("users[0].attributes")).IsFalse()
+	Expect(query.String("users[1].attributes.plan")).Equals("enterprise")
 }
 
 func TestCreateUser_ExistingEmail(t *testing.T) {
@@ -188,3 +197,99 @@ func TestCreateUser_NewUser(t *testing.T) {
 	theOtherUserID := query.Int32("id")
 	Expect(theOtherUserID).Equals(userID)
 }
+
+func TestSetUserAttributesHandler(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByID) error {
+		q.Result = mock.AryaStark
+		return nil
+	})
+
+	var setAttributes *cmd.SetUserAttributes
+	bus.AddHandler(func(ctx context.Context, c *cmd.SetUserAttributes) error {
+		setAttributes = c
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserAttributes) error {
+		q.Result = map[int]map[string]string{
+			mock.AryaStark.ID: {"company": "Stark", "mrr": "1200"},
+		}
+		return nil
+	})
+
+	status, query := mock.NewServer().
+		AsUser(mock.JonSnow).
+		OnTenant(mock.DemoTenant).
+		AddParam("userID", mock.AryaStark.ID).
+		ExecutePostAsJSON(apiv1.SetUserAttributes(), `{ "attributes": { "company": "Stark", "mrr": "1200", "plan": "" } }`)
+
+	Expect(status).Equals(http.StatusOK)
+	Expect(setAttributes.UserID).Equals(mock.AryaStark.ID)
+	Expect(setAttributes.Attributes).Equals(map[string]string{"company": "Stark", "mrr": "1200", "plan": ""})
+	Expect(query.String("attributes.mrr")).Equals("1200")
+}
+
+func TestSetUserAttributesHandler_InvalidKey(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByID) error {
+		q.Result = mock.AryaStark
+		return nil
+	})
+
+	status, _ := mock.NewServer().
+		AsUser(mock.JonSnow).
+		OnTenant(mock.DemoTenant).
+		AddParam("userID", mock.AryaStark.ID).
+		ExecutePost(apiv1.SetUserAttributes(), `{ "attributes": { "Monthly Revenue": "1200" } }`)
+
+	Expect(status).Equals(http.StatusBadRequest)
+}
+
+func TestImportUserAttributesHandler(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByProvider) error {
+		if q.Provider == "reference" && q.UID == "42" {
+			q.Result = mock.JonSnow
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByEmail) error {
+		if q.Email == mock.AryaStark.Email {
+			q.Result = mock.AryaStark
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByID) error {
+		q.Result = mock.AryaStark
+		return nil
+	})
+
+	updated := make(map[int]map[string]string)
+	bus.AddHandler(func(ctx context.Context, c *cmd.SetUserAttributes) error {
+		updated[c.UserID] = c.Attributes
+		return nil
+	})
+
+	status, query := mock.NewServer().
+		AsUser(mock.JonSnow).
+		OnTenant(mock.DemoTenant).
+		ExecutePostAsJSON(apiv1.ImportUserAttributes(), "email,reference,plan,mrr\n"+
+			mock.AryaStark.Email+",,pro,300\n"+
+			",42,enterprise,1200\n"+
+			"unknown@fider.io,,pro,100\n")
+
+	Expect(status).Equals(http.StatusOK)
+	Expect(query.Int32("updated")).Equals(2)
+	Expect(query.Int32("skipped")).Equals(1)
+	Expect(query.Strings("errors")).Equals([]string{"line 4: user not found"})
+	Expect(updated[mock.AryaStark.ID]).Equals(map[string]string{"plan": "pro", "mrr": "300"})
+	Expect(updated[mock.JonSnow.ID]).Equals(map[string]string{"plan": "enterprise", "mrr": "1200"})
+}
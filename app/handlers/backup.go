@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"net/http"
+
 	"github.com/getfider/fider/app/pkg/backup"
 	"github.com/getfider/fider/app/pkg/errors"
 	"github.com/getfider/fider/app/pkg/log"
@@ -10,13 +12,14 @@ import (
 // ExportBackupZip returns a Zip file with all content
 func ExportBackupZip() web.HandlerFunc {
 	return func(c *web.Context) error {
+		c.Response.Header().Set("Content-Type", "application/zip")
+		c.Response.Header().Set("Content-Disposition", "attachment; filename=\"backup.zip\"")
+		c.Response.WriteHeader(http.StatusOK)
 
-		file, err := backup.Create(c)
-		if err != nil {
+		// The archive is streamed, so errors can only be logged once the response has started
+		if err := backup.Write(c, &c.Response); err != nil {
 			log.Error(c, errors.Wrap(err, "failed to create backup"))
-			return c.Failure(err)
 		}
-
-		return c.Attachment("backup.zip", "application/zip", file.Bytes())
+		return nil
 	}
 }
@@ -39,6 +39,14 @@ type Entry struct {
 	Summary    *Content    `xml:"summary"`
 	Content    *Content    `xml:"content"`
 	Categories []*Category `xml:"category,omitempty"`
+	InReplyTo  *InReplyTo  `xml:"http://purl.org/syndication/thread/1.0 in-reply-to,omitempty"`
+}
+
+// InReplyTo links a reply to the entry it replies to, as defined by the Atom Threading Extensions (RFC 4685)
+type InReplyTo struct {
+	Ref  string `xml:"ref,attr"`
+	Href string `xml:"href,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
 }
 
 type Category struct {
@@ -122,6 +130,14 @@ func generatePostContent(c *web.Context, post *entity.Post, options *generatorOp
 	return string(markdown.Full(title+post.Description+footer, true))
 }
 
+// postStatusTerm returns the name of a custom status as is and translates the built-in ones
+func postStatusTerm(c *web.Context, post *entity.Post) string {
+	if post.CustomStatus != nil {
+		return post.CustomStatus.Name
+	}
+	return i18n.T(c, "enum.poststatus."+post.Status.Name())
+}
+
 func appendTags(c *web.Context, categories []*Category, post *entity.Post) ([]*Category, error) {
 	getAssignedTags := &query.GetAssignedTags{Post: post}
 	if err := bus.Dispatch(c, getAssignedTags); err != nil {
@@ -176,7 +192,7 @@ func GlobalFeed() web.HandlerFunc {
 				lastUpdate = post.Response.RespondedAt
 			}
 
-			categories := []*Category{{Term: i18n.T(c, "enum.poststatus."+post.Status.Name())}}
+			categories := []*Category{{Term: postStatusTerm(c, post)}}
 			categories, err := appendTags(c, categories, post)
 			if err != nil {
 				return c.Failure(err)
@@ -246,7 +262,13 @@ func CommentFeed() web.HandlerFunc {
 			return c.Failure(err)
 		}
 		post := getPost.Result
-		comments := getComments.Result
+		comments := make([]*entity.Comment, 0, len(getComments.Result))
+		for _, comment := range getComments.Result {
+			// Feeds are public, so internal notes are left out even when staff is signed in
+			if !comment.IsInternal {
+				comments = append(comments, comment)
+			}
+		}
 		comments = comments[max(0, len(comments)-30):] // get the last 30 comments
 
 		authorName := ""
@@ -266,7 +288,7 @@ func CommentFeed() web.HandlerFunc {
 			Entries: []*Entry{},
 		}
 
-		categories := []*Category{{Term: i18n.T(c, "enum.poststatus."+post.Status.Name())}}
+		categories := []*Category{{Term: postStatusTerm(c, post)}}
 		categories, err = appendTags(c, categories, post)
 		if err != nil {
 			return c.Failure(err)
@@ -299,7 +321,7 @@ func CommentFeed() web.HandlerFunc {
 					{Href: fmt.Sprintf("%s/posts/%d", web.BaseURL(c), post.Number), Type: "text/html", Rel: "alternate"},
 				},
 				Content:    &Content{Type: "html", Body: string(markdown.Full(post.Response.Text, true))},
-				Categories: []*Category{{Term: i18n.T(c, "enum.poststatus."+post.Status.Name())}},
+				Categories: []*Category{{Term: postStatusTerm(c, post)}},
 			})
 		}
 
@@ -312,8 +334,16 @@ func CommentFeed() web.HandlerFunc {
 				lastUpdate = *comment.EditedAt
 			}
 
+			titleKey := "feed.comment.title"
+			var inReplyTo *InReplyTo
+			if comment.ParentID != nil {
+				titleKey = "feed.comment.reply"
+				parentURL := fmt.Sprintf("%s/posts/%d/#comment-%d", web.BaseURL(c), post.Number, *comment.ParentID)
+				inReplyTo = &InReplyTo{Ref: parentURL, Href: parentURL, Type: "text/html"}
+			}
+
 			feed.Entries = append(feed.Entries, &Entry{
-				Title: i18n.T(c, "feed.comment.title", i18n.Params{
+				Title: i18n.T(c, titleKey, i18n.Params{
 					"author": comment.User.Name,
 				}),
 				Author:    &Author{Name: comment.User.Name},
@@ -324,9 +354,10 @@ func CommentFeed() web.HandlerFunc {
 					}
 					return formatTime(*comment.EditedAt)
 				}(),
-				Content: &Content{Type: "html", Body: string(markdown.Full(comment.Content, true))},
-				Id:      fmt.Sprintf("%s/posts/%d/#comment-%d", web.BaseURL(c), post.Number, comment.ID),
-				Link:    []Link{{Href: fmt.Sprintf("%s/posts/%d/#comment-%d", web.BaseURL(c), post.Number, comment.ID), Type: "text/html", Rel: "alternate"}},
+				Content:   &Content{Type: "html", Body: string(markdown.Full(comment.Content, true))},
+				Id:        fmt.Sprintf("%s/posts/%d/#comment-%d", web.BaseURL(c), post.Number, comment.ID),
+				Link:      []Link{{Href: fmt.Sprintf("%s/posts/%d/#comment-%d", web.BaseURL(c), post.Number, comment.ID), Type: "text/html", Rel: "alternate"}},
+				InReplyTo: inReplyTo,
 			})
 		}
 		feed.Updated = formatTime(lastUpdate)
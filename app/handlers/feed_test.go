@@ -134,6 +134,65 @@ func TestCommentFeedHandler(t *testing.T) {
 	compareGeneratorResponse(responseBody, "app/handlers/testdata/comment_feed.atom")
 }
 
+func TestCommentFeedHandler_Reply(t *testing.T) {
+	RegisterT(t)
+
+	post := &entity.Post{
+		ID:          1,
+		Number:      1,
+		Title:       "The Post",
+		Slug:        "the-post",
+		Description: "Description of the post",
+		CreatedAt:   time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC),
+		User:        &entity.User{ID: 1, Name: "Jon Snow"},
+		Status:      enum.PostOpen,
+	}
+
+	parentID := 1
+	comment1 := &entity.Comment{
+		ID:        1,
+		Content:   "This is comment 1",
+		CreatedAt: time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC),
+		User:      &entity.User{ID: 2, Name: "Arya Stark"},
+	}
+
+	comment2 := &entity.Comment{
+		ID:        2,
+		ParentID:  &parentID,
+		Content:   "This is a reply to comment 1",
+		CreatedAt: time.Date(2023, 1, 3, 10, 0, 0, 0, time.UTC),
+		User:      &entity.User{ID: 3, Name: "Sansa Stark"},
+	}
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = post
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCommentsByPost) error {
+		q.Result = []*entity.Comment{comment1, comment2}
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetAssignedTags) error {
+		q.Result = []*entity.Tag{}
+		return nil
+	})
+
+	code, response := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("path", "1.atom").
+		Execute(handlers.CommentFeed())
+
+	Expect(code).Equals(http.StatusOK)
+	responseBody := response.Body.String()
+	Expect(strings.Contains(responseBody, "Comment by Arya Stark")).IsTrue()
+	Expect(strings.Contains(responseBody, "Reply by Sansa Stark")).IsTrue()
+	Expect(strings.Contains(responseBody, `<in-reply-to xmlns="http://purl.org/syndication/thread/1.0" ref="http:///posts/1/#comment-1"`)).IsTrue()
+	Expect(strings.Count(responseBody, "<in-reply-to")).Equals(1)
+}
+
 func TestCommentFeedHandler_NotFound(t *testing.T) {
 	RegisterT(t)
 
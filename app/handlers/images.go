@@ -14,6 +14,7 @@ import (
 	"github.com/getfider/fider/app/models/dto"
 	"github.com/getfider/fider/app/models/query"
 
+	"github.com/getfider/fider/app/pkg/backup"
 	"github.com/getfider/fider/app/pkg/bus"
 	"github.com/getfider/fider/app/pkg/crypto"
 	"github.com/getfider/fider/app/pkg/env"
@@ -119,6 +120,9 @@ func Favicon() web.HandlerFunc {
 		)
 
 		bkey := c.Param("bkey")
+		if backup.IsArchiveKey(bkey) {
+			return c.NotFound()
+		}
 		if bkey != "" {
 			q := &query.GetBlobByKey{Key: bkey}
 			err := bus.Dispatch(c, q)
@@ -166,6 +170,11 @@ func ViewUploadedImage() web.HandlerFunc {
 	return func(c *web.Context) error {
 		bkey := c.Param("bkey")
 
+		// Backup archives are stored with the uploads, but they must never be public
+		if backup.IsArchiveKey(bkey) {
+			return c.NotFound()
+		}
+
 		size, err := c.QueryParamAsInt("size")
 		if err != nil {
 			return c.BadRequest(web.Map{})
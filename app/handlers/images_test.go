@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/dto"
 	"github.com/getfider/fider/app/models/entity"
 	"github.com/getfider/fider/app/models/query"
 	"github.com/getfider/fider/app/pkg/bus"
@@ -102,3 +103,22 @@ func TestUnknownUser_LetterAvatarHandler(t *testing.T) {
 	bytes, _ := io.ReadAll(response.Body)
 	Expect(bytes).Equals(expectedAvatar)
 }
+
+func TestViewUploadedImageHandler_BackupArchive(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetBlobByKey) error {
+		q.Result = &dto.Blob{Content: []byte("zip content"), ContentType: "application/zip"}
+		return nil
+	})
+
+	for _, bkey := range []string{"backups/backup-20261018T030000Z.zip", "./backups/backup-20261018T030000Z.zip"} {
+		code, _ := mock.NewServer().
+			OnTenant(mock.DemoTenant).
+			AddParam("bkey", bkey).
+			WithURL("http://demo.test.fider.io/static/images/" + bkey).
+			Execute(handlers.ViewUploadedImage())
+
+		Expect(code).Equals(http.StatusNotFound)
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/getfider/fider/app/pkg/log"
 	"github.com/getfider/fider/app/pkg/web"
 	webutil "github.com/getfider/fider/app/pkg/web/util"
+	"github.com/getfider/fider/app/tasks"
 )
 
 // OAuthEcho exchanges OAuth Code for a user profile and return directly to the UI, without storing it
@@ -40,7 +41,7 @@ func OAuthEcho() web.HandlerFunc {
 			return c.Redirect("/")
 		}
 
-		rawProfile := &query.GetOAuthRawProfile{Provider: provider, Code: code}
+		rawProfile := &query.GetOAuthRawProfile{Provider: provider, Code: code, Nonce: c.QueryParam("nonce")}
 		err := bus.Dispatch(c, rawProfile)
 		if err != nil {
 			return c.Page(http.StatusOK, web.Props{
@@ -96,7 +97,7 @@ func OAuthToken() web.HandlerFunc {
 			return c.Redirect(redirectURL.String())
 		}
 
-		oauthUser := &query.GetOAuthProfile{Provider: provider, Code: code}
+		oauthUser := &query.GetOAuthProfile{Provider: provider, Code: code, Nonce: c.QueryParam("nonce")}
 		if err := bus.Dispatch(c, oauthUser); err != nil {
 			return c.Failure(err)
 		}
@@ -110,74 +111,20 @@ func OAuthToken() web.HandlerFunc {
 			return c.Failure(err)
 		}
 
-		// Look up the existing Fider user first (by provider UID, then by email).
-		// We need this before the role check so that administrators and collaborators
-		// can always sign in regardless of OAuth role changes.
-		var user *entity.User
-
-		userByProvider := &query.GetUserByProvider{Provider: provider, UID: oauthUser.Result.ID}
-		err = bus.Dispatch(c, userByProvider)
-		user = userByProvider.Result
-
-		if errors.Cause(err) == app.ErrNotFound && oauthUser.Result.Email != "" {
-			userByEmail := &query.GetUserByEmail{Email: oauthUser.Result.Email}
-			err = bus.Dispatch(c, userByEmail)
-			user = userByEmail.Result
-		}
-
-		// Check if user has the required roles for this provider.
-		// Both AllowedRoles and JSONUserRolesPath must be set on the provider for the check to run.
-		// Administrators and collaborators already trusted in Fider are always allowed through,
-		// regardless of their current OAuth roles.
 		var providerRolesPath, providerAllowedRoles string
+		var isTrusted bool
 		if customConfig != nil {
 			providerRolesPath = customConfig.JSONUserRolesPath
 			providerAllowedRoles = customConfig.AllowedRoles
+			isTrusted = customConfig.IsTrusted
 		}
-		isFiderPrivileged := user != nil && (user.Role == enum.RoleAdministrator || user.Role == enum.RoleCollaborator)
-		if !isFiderPrivileged && !hasAllowedRole(oauthUser.Result.Roles, providerRolesPath, providerAllowedRoles) {
-			log.Warnf(c, "User @{UserID} attempted OAuth login but does not have required role. User roles: @{UserRoles}, Allowed roles: @{AllowedRoles}",
-				dto.Props{
-					"UserID":       oauthUser.Result.ID,
-					"UserRoles":    oauthUser.Result.Roles,
-					"AllowedRoles": providerAllowedRoles,
-				})
-			return c.Redirect("/access-denied")
-		}
-		if err != nil {
-			if errors.Cause(err) == app.ErrNotFound {
-				isTrusted := customConfig != nil && customConfig.IsTrusted
-				if c.Tenant().IsPrivate && !isTrusted {
-					return c.Redirect("/not-invited")
-				}
-
-				user = &entity.User{
-					Name:   oauthUser.Result.Name,
-					Tenant: c.Tenant(),
-					Email:  oauthUser.Result.Email,
-					Role:   enum.RoleVisitor,
-					Providers: []*entity.UserProvider{
-						{
-							UID:  oauthUser.Result.ID,
-							Name: provider,
-						},
-					},
-				}
 
-				if err = bus.Dispatch(c, &cmd.RegisterUser{User: user}); err != nil {
-					return c.Failure(err)
-				}
-			} else {
-				return c.Failure(err)
-			}
-		} else if !user.HasProvider(provider) {
-			if err = bus.Dispatch(c, &cmd.RegisterUserProvider{
-				UserID:       user.ID,
-				ProviderName: provider,
-				ProviderUID:  oauthUser.Result.ID,
-			}); err != nil {
-				return c.Failure(err)
-			}
+		user, deniedRedirect, err := signInExternalUser(c, provider, oauthUser.Result, providerRolesPath, providerAllowedRoles, isTrusted)
+		if err != nil {
+			return c.Failure(err)
+		}
+		if deniedRedirect != "" {
+			return c.Redirect(deniedRedirect)
 		}
 
 		webutil.AddAuthUserCookie(c, user)
@@ -186,6 +133,78 @@ func OAuthToken() web.HandlerFunc {
 	}
 }
 
+// signInExternalUser gets the Fider user matching a profile from an external identity provider (OAuth or SAML),
+// registering a new user or linking the provider to an existing one when needed.
+// When the user is not allowed to sign in, the path of the page explaining why is returned instead of a user
+func signInExternalUser(c *web.Context, provider string, profile *dto.OAuthUserProfile, rolesPath, allowedRoles string, isTrusted bool) (*entity.User, string, error) {
+	// Look up the existing Fider user first (by provider UID, then by email).
+	// We need this before the role check so that administrators and collaborators
+	// can always sign in regardless of OAuth role changes.
+	var user *entity.User
+
+	userByProvider := &query.GetUserByProvider{Provider: provider, UID: profile.ID}
+	err := bus.Dispatch(c, userByProvider)
+	user = userByProvider.Result
+
+	if errors.Cause(err) == app.ErrNotFound && profile.Email != "" {
+		userByEmail := &query.GetUserByEmail{Email: profile.Email}
+		err = bus.Dispatch(c, userByEmail)
+		user = userByEmail.Result
+	}
+
+	// Check if user has the required roles for this provider.
+	// Both allowedRoles and rolesPath must be set on the provider for the check to run.
+	// Administrators and collaborators already trusted in Fider are always allowed through,
+	// regardless of their current OAuth roles.
+	isFiderPrivileged := user != nil && (user.Role == enum.RoleAdministrator || user.Role == enum.RoleCollaborator)
+	if !isFiderPrivileged && !hasAllowedRole(profile.Roles, rolesPath, allowedRoles) {
+		log.Warnf(c, "User @{UserID} attempted OAuth login but does not have required role. User roles: @{UserRoles}, Allowed roles: @{AllowedRoles}",
+			dto.Props{
+				"UserID":       profile.ID,
+				"UserRoles":    profile.Roles,
+				"AllowedRoles": allowedRoles,
+			})
+		return nil, "/access-denied", nil
+	}
+	if err != nil {
+		if errors.Cause(err) == app.ErrNotFound {
+			if c.Tenant().IsPrivate && !isTrusted {
+				return nil, "/not-invited", nil
+			}
+
+			user = &entity.User{
+				Name:   profile.Name,
+				Tenant: c.Tenant(),
+				Email:  profile.Email,
+				Role:   enum.RoleVisitor,
+				Providers: []*entity.UserProvider{
+					{
+						UID:  profile.ID,
+						Name: provider,
+					},
+				},
+			}
+
+			if err = bus.Dispatch(c, &cmd.RegisterUser{User: user}); err != nil {
+				return nil, "", err
+			}
+			c.Enqueue(tasks.NotifyAboutNewUser(user))
+		} else {
+			return nil, "", err
+		}
+	} else if !user.HasProvider(provider) {
+		if err = bus.Dispatch(c, &cmd.RegisterUserProvider{
+			UserID:       user.ID,
+			ProviderName: provider,
+			ProviderUID:  profile.ID,
+		}); err != nil {
+			return nil, "", err
+		}
+	}
+
+	return user, "", nil
+}
+
 // getCustomOAuthConfig fetches the custom OAuth provider config for the given provider.
 // Built-in providers (Google, Facebook, GitHub, …) are identified by the absence of a
 // leading "_" and never have a custom config row, so the bus dispatch is skipped for them.
@@ -238,13 +257,16 @@ func OAuthCallback() web.HandlerFunc {
 			var query = redirectURL.Query()
 			query.Set("code", code)
 			query.Set("identifier", claims.Identifier)
+			if claims.Nonce != "" {
+				query.Set("nonce", claims.Nonce)
+			}
 			redirectURL.RawQuery = query.Encode()
 			return c.Redirect(redirectURL.String())
 		}
 
 		//Sign up process
 		if redirectURL.Path == "/signup" {
-			oauthUser := &query.GetOAuthProfile{Provider: provider, Code: code}
+			oauthUser := &query.GetOAuthProfile{Provider: provider, Code: code, Nonce: claims.Nonce}
 			if err := bus.Dispatch(c, oauthUser); err != nil {
 				return c.Failure(err)
 			}
@@ -275,6 +297,9 @@ func OAuthCallback() web.HandlerFunc {
 		query.Set("code", code)
 		query.Set("redirect", redirectURL.RequestURI())
 		query.Set("identifier", claims.Identifier)
+		if claims.Nonce != "" {
+			query.Set("nonce", claims.Nonce)
+		}
 		redirectURL.RawQuery = query.Encode()
 		redirectURL.Path = fmt.Sprintf("/oauth/%s/token", provider)
 		return c.Redirect(redirectURL.String())
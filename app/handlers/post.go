@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/getfider/fider/app/models/entity"
 	"github.com/getfider/fider/app/models/query"
 	"github.com/getfider/fider/app/pkg/bus"
 	"github.com/getfider/fider/app/pkg/csv"
@@ -15,7 +16,10 @@ import (
 // Index is the default home page
 func Index() web.HandlerFunc {
 	return func(c *web.Context) error {
-		c.SetCanonicalURL("")
+		board, err := getBoardFromParam(c)
+		if err != nil {
+			return c.Failure(err)
+		}
 
 		searchPosts := &query.SearchPosts{
 			Query: c.QueryParam("query"),
@@ -23,6 +27,15 @@ func Index() web.HandlerFunc {
 			Limit: c.QueryParam("limit"),
 			Tags:  c.QueryParamAsArray("tags"),
 		}
+		countPerStatus := &query.CountPostPerStatus{}
+
+		if board != nil {
+			c.SetCanonicalURL("/b/" + board.Slug)
+			searchPosts.Board = board.Slug
+			countPerStatus.Board = board.Slug
+		} else {
+			c.SetCanonicalURL("")
+		}
 
 		if myVotesOnly, err := c.QueryParamAsBool("myvotes"); err == nil {
 			searchPosts.MyVotesOnly = myVotesOnly
@@ -55,14 +68,16 @@ func Index() web.HandlerFunc {
 
 		searchPosts.SetStatusesFromStrings(actualStatuses)
 		getAllTags := &query.GetAllTags{}
-		countPerStatus := &query.CountPostPerStatus{}
+		getAllBoards := &query.GetAllBoards{}
 
-		if err := bus.Dispatch(c, searchPosts, getAllTags, countPerStatus); err != nil {
+		if err := bus.Dispatch(c, searchPosts, getAllTags, countPerStatus, getAllBoards); err != nil {
 			return c.Failure(err)
 		}
 
 		description := ""
-		if c.Tenant().WelcomeMessage != "" {
+		if board != nil && board.Description != "" {
+			description = markdown.PlainText(board.Description)
+		} else if c.Tenant().WelcomeMessage != "" {
 			description = markdown.PlainText(c.Tenant().WelcomeMessage)
 		} else {
 			description = "We'd love to hear what you're thinking about. What can we do better? This is the place for you to vote, discuss and share posts."
@@ -73,6 +88,8 @@ func Index() web.HandlerFunc {
 			"posts":            searchPosts.Result,
 			"tags":             getAllTags.Result,
 			"countPerStatus":   countPerStatus.Result,
+			"boards":           getAllBoards.Result,
+			"board":            board,
 		}
 
 		return c.Page(http.StatusOK, web.Props{
@@ -84,6 +101,20 @@ func Index() web.HandlerFunc {
 	}
 }
 
+// getBoardFromParam returns the board of the :board route param, or nil when the route has no board
+func getBoardFromParam(c *web.Context) (*entity.Board, error) {
+	boardSlug := c.Param("board")
+	if boardSlug == "" {
+		return nil, nil
+	}
+
+	getBoard := &query.GetBoardBySlug{Slug: boardSlug}
+	if err := bus.Dispatch(c, getBoard); err != nil {
+		return nil, err
+	}
+	return getBoard.Result, nil
+}
+
 // PostDetails shows details of given Post by id
 func PostDetails() web.HandlerFunc {
 	return func(c *web.Context) error {
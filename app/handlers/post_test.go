@@ -31,6 +31,10 @@ func TestIndexHandler(t *testing.T) {
 		return nil
 	})
 
+	bus.AddHandler(func(ctx context.Context, q *query.GetAllBoards) error {
+		return nil
+	})
+
 	server := mock.NewServer()
 	code, _ := server.OnTenant(mock.DemoTenant).
 		AsUser(mock.JonSnow).
@@ -39,6 +43,57 @@ func TestIndexHandler(t *testing.T) {
 	Expect(code).Equals(http.StatusOK)
 }
 
+func TestIndexHandler_Board(t *testing.T) {
+	RegisterT(t)
+
+	board := &entity.Board{ID: 1, Name: "Mobile", Slug: "mobile"}
+	var searchedBoard, countedBoard string
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetBoardBySlug) error {
+		if q.Slug == board.Slug {
+			q.Result = board
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.CountPostPerStatus) error {
+		countedBoard = q.Board
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetAllTags) error {
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.SearchPosts) error {
+		searchedBoard = q.Board
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetAllBoards) error {
+		q.Result = []*entity.Board{board}
+		return nil
+	})
+
+	server := mock.NewServer()
+	code, _ := server.OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("board", "mobile").
+		Execute(handlers.Index())
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(searchedBoard).Equals("mobile")
+	Expect(countedBoard).Equals("mobile")
+
+	code, _ = mock.NewServer().OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("board", "billing").
+		Execute(handlers.Index())
+
+	Expect(code).Equals(http.StatusNotFound)
+}
+
 func TestDetailsHandler(t *testing.T) {
 	RegisterT(t)
 
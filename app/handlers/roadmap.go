@@ -14,6 +14,14 @@ import (
 // so the client shows the upgrade call-to-action.
 func RoadmapPage() web.HandlerFunc {
 	return func(c *web.Context) error {
+		board, err := getBoardFromParam(c)
+		if err != nil {
+			return c.Failure(err)
+		}
+		if board != nil && !board.HasRoadmap {
+			return c.NotFound()
+		}
+
 		props := web.Props{
 			Page:  "Roadmap/Roadmap.page",
 			Title: "Roadmap",
@@ -26,9 +34,20 @@ func RoadmapPage() web.HandlerFunc {
 			plannedPosts := &query.SearchPosts{View: "planned", Limit: "10"}
 			startedPosts := &query.SearchPosts{View: "started", Limit: "10"}
 			completedPosts := &query.SearchPosts{View: "completed", Limit: "10"}
+
+			// Boards with a separate roadmap only show their posts there
+			for _, search := range []*query.SearchPosts{plannedPosts, startedPosts, completedPosts} {
+				if board != nil {
+					search.Board = board.Slug
+				} else {
+					search.ExcludeRoadmapBoards = true
+				}
+			}
 			getAllTags := &query.GetAllTags{}
+			// Custom statuses are shown in the column they are mapped to
+			getPostStatuses := &query.GetCustomPostStatuses{}
 
-			if err := bus.Dispatch(c, plannedPosts, startedPosts, completedPosts, getAllTags); err != nil {
+			if err := bus.Dispatch(c, plannedPosts, startedPosts, completedPosts, getAllTags, getPostStatuses); err != nil {
 				return c.Failure(err)
 			}
 
@@ -37,9 +56,15 @@ func RoadmapPage() web.HandlerFunc {
 				"startedPosts":   startedPosts.Result,
 				"completedPosts": completedPosts.Result,
 				"tags":           getAllTags.Result,
+				"postStatuses":   getPostStatuses.Result,
+				"board":          board,
 			}
 		}
 
+		if board != nil {
+			props.Title = board.Name + " · Roadmap"
+		}
+
 		return c.Page(http.StatusOK, props)
 	}
 }
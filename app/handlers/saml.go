@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/actions"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/jwt"
+	"github.com/getfider/fider/app/pkg/log"
+	"github.com/getfider/fider/app/pkg/saml"
+	"github.com/getfider/fider/app/pkg/web"
+	webutil "github.com/getfider/fider/app/pkg/web/util"
+)
+
+func samlEntityID(c *web.Context) string {
+	return c.BaseURL() + "/saml/metadata"
+}
+
+func samlACSURL(c *web.Context) string {
+	return c.BaseURL() + "/saml/acs"
+}
+
+// samlAssertionTTL is how long used assertion IDs are remembered, which outlives the RelayState they must match
+const samlAssertionTTL = 15 * time.Minute
+
+// markSAMLAssertionAsUsed returns false if the assertion with given ID was already used to sign in on current tenant
+func markSAMLAssertionAsUsed(c *web.Context, assertionID string) bool {
+	key := fmt.Sprintf("saml-assertion:%d:%s", c.Tenant().ID, assertionID)
+	return c.Engine().Cache().Add(key, true, samlAssertionTTL) == nil
+}
+
+// getEnabledSAMLConfig returns the SAML config of current tenant, or nil if SAML is not configured or disabled
+func getEnabledSAMLConfig(c *web.Context) (*entity.SAMLConfig, error) {
+	getConfig := &query.GetSAMLConfig{}
+	if err := bus.Dispatch(c, getConfig); err != nil {
+		if errors.Cause(err) == app.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if getConfig.Result.Status != enum.OAuthConfigEnabled {
+		return nil, nil
+	}
+	return getConfig.Result, nil
+}
+
+// SAMLMetadata returns the Service Provider metadata to be uploaded to the IdP
+func SAMLMetadata() web.HandlerFunc {
+	return func(c *web.Context) error {
+		metadata, err := saml.ServiceProviderMetadata(samlEntityID(c), samlACSURL(c))
+		if err != nil {
+			return c.Failure(err)
+		}
+		return c.XML(http.StatusOK, string(metadata))
+	}
+}
+
+// SignInBySAML is responsible for redirecting the user to the IdP with an AuthnRequest
+// The ID of the request and the session identifier are kept in the RelayState to be verified when the IdP responds
+func SignInBySAML() web.HandlerFunc {
+	return func(c *web.Context) error {
+		c.Response.Header().Add("X-Robots-Tag", "noindex")
+
+		redirect := c.QueryParam("redirect")
+		if redirect == "" {
+			redirect = c.BaseURL()
+		} else if redirect != c.BaseURL() && !strings.HasPrefix(redirect, c.BaseURL()+"/") {
+			return c.Forbidden()
+		}
+
+		if c.IsAuthenticated() {
+			return c.Redirect(redirect)
+		}
+
+		config, err := getEnabledSAMLConfig(c)
+		if err != nil {
+			return c.Failure(err)
+		}
+		if config == nil {
+			return c.NotFound()
+		}
+
+		request := saml.NewAuthnRequest(config.IdPSSOURL, samlEntityID(c), samlACSURL(c))
+		relayState, err := jwt.Encode(jwt.OAuthStateClaims{
+			Redirect:   redirect,
+			Identifier: c.SessionID(),
+			Code:       request.ID,
+			TenantID:   c.Tenant().ID,
+			Metadata: jwt.Metadata{
+				ExpiresAt: jwt.Time(time.Now().Add(10 * time.Minute)),
+			},
+		})
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		redirectURL, err := request.RedirectURL(relayState)
+		if err != nil {
+			return c.Failure(err)
+		}
+		return c.Redirect(redirectURL)
+	}
+}
+
+// SAMLAssertionConsumer receives the SAML Response posted by the IdP
+// As the session cookie is not sent on this cross-site post, the validated profile is
+// handed over to SAMLToken in a short-lived token, where the session is verified
+func SAMLAssertionConsumer() web.HandlerFunc {
+	return func(c *web.Context) error {
+		if c.Tenant() == nil {
+			return c.NotFound()
+		}
+
+		form, err := url.ParseQuery(c.Request.Body)
+		if err != nil {
+			return c.BadRequest(web.Map{})
+		}
+
+		state, err := jwt.DecodeOAuthStateClaims(form.Get("RelayState"))
+		if err != nil || state.TenantID != c.Tenant().ID {
+			return c.Forbidden()
+		}
+
+		config, err := getEnabledSAMLConfig(c)
+		if err != nil {
+			return c.Failure(err)
+		}
+		if config == nil {
+			return c.NotFound()
+		}
+
+		assertion, err := saml.ParseResponse(form.Get("SAMLResponse"), saml.ResponseOptions{
+			IdPEntityID:  config.IdPEntityID,
+			Certificate:  config.IdPCertificate,
+			SPEntityID:   samlEntityID(c),
+			ACSURL:       samlACSURL(c),
+			InResponseTo: state.Code,
+		})
+		if err != nil {
+			log.Warnf(c, "Rejected SAML Response: @{Error}", dto.Props{"Error": err.Error()})
+			return c.Forbidden()
+		}
+
+		if !markSAMLAssertionAsUsed(c, assertion.ID) {
+			log.Warnf(c, "Rejected replayed SAML Assertion @{AssertionID}", dto.Props{"AssertionID": assertion.ID})
+			return c.Forbidden()
+		}
+
+		claims := jwt.SAMLClaims{
+			NameID:     assertion.NameID,
+			Name:       assertion.NameID,
+			Redirect:   state.Redirect,
+			Identifier: state.Identifier,
+			TenantID:   c.Tenant().ID,
+			Metadata: jwt.Metadata{
+				ExpiresAt: jwt.Time(time.Now().Add(5 * time.Minute)),
+			},
+		}
+
+		if config.EmailAttribute != "" {
+			claims.Email = assertion.Attribute(config.EmailAttribute)
+		} else if strings.Contains(assertion.NameID, "@") {
+			claims.Email = assertion.NameID
+		}
+
+		if name := assertion.Attribute(config.NameAttribute); config.NameAttribute != "" && name != "" {
+			claims.Name = name
+		}
+
+		if config.RolesAttribute != "" {
+			claims.Roles = assertion.Attributes[config.RolesAttribute]
+		}
+
+		token, err := jwt.Encode(claims)
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		// 303 makes the browser follow with a GET instead of posting the SAML Response again
+		c.Response.Header().Set("Cache-Control", "no-cache, no-store")
+		c.Response.Header().Set("Location", c.BaseURL()+"/saml/token?token="+url.QueryEscape(token))
+		c.Response.WriteHeader(http.StatusSeeOther)
+		return nil
+	}
+}
+
+// SAMLToken signs in the user asserted by the IdP
+// The user is then either matched to an existing user on Fider or registered, like users signing in with OAuth
+func SAMLToken() web.HandlerFunc {
+	return func(c *web.Context) error {
+		claims, err := jwt.DecodeSAMLClaims(c.QueryParam("token"))
+		if err != nil || claims.TenantID != c.Tenant().ID {
+			return c.Forbidden()
+		}
+
+		redirect := claims.Redirect
+		if redirect != c.BaseURL() && !strings.HasPrefix(redirect, c.BaseURL()+"/") {
+			redirect = c.BaseURL()
+		}
+
+		if claims.Identifier == "" || claims.Identifier != c.SessionID() {
+			log.Warn(c, "SAML identifier doesn't match with user session ID. Aborting sign in process.")
+			return c.Redirect(redirect)
+		}
+
+		config, err := getEnabledSAMLConfig(c)
+		if err != nil {
+			return c.Failure(err)
+		}
+		if config == nil {
+			return c.NotFound()
+		}
+
+		profile := &dto.OAuthUserProfile{
+			ID:    claims.NameID,
+			Name:  claims.Name,
+			Email: claims.Email,
+			Roles: claims.Roles,
+		}
+
+		user, deniedRedirect, err := signInExternalUser(c, app.SAMLProvider, profile, config.RolesAttribute, config.AllowedRoles, config.IsTrusted)
+		if err != nil {
+			return c.Failure(err)
+		}
+		if deniedRedirect != "" {
+			return c.Redirect(deniedRedirect)
+		}
+
+		webutil.AddAuthUserCookie(c, user)
+
+		return c.Redirect(redirect)
+	}
+}
+
+// GetSAMLConfig returns the SAML config of current tenant
+func GetSAMLConfig() web.HandlerFunc {
+	return func(c *web.Context) error {
+		getConfig := &query.GetSAMLConfig{}
+		if err := bus.Dispatch(c, getConfig); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(getConfig.Result)
+	}
+}
+
+// SaveSAMLConfig is used to configure the SAML Identity Provider of current tenant
+func SaveSAMLConfig() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.SaveSAMLConfig)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		if err := bus.Dispatch(c, &cmd.SaveSAMLConfig{
+			Status:         action.Status,
+			DisplayName:    action.DisplayName,
+			IdPEntityID:    action.IdPEntityID,
+			IdPSSOURL:      action.IdPSSOURL,
+			IdPCertificate: action.IdPCertificate,
+			NameAttribute:  action.NameAttribute,
+			EmailAttribute: action.EmailAttribute,
+			RolesAttribute: action.RolesAttribute,
+			AllowedRoles:   action.AllowedRoles,
+			IsTrusted:      action.IsTrusted,
+		}); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
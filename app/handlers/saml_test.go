@@ -0,0 +1,247 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/handlers"
+	"github.com/getfider/fider/app/middlewares"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/jwt"
+	"github.com/getfider/fider/app/pkg/mock"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+func mockSAMLConfig(config *entity.SAMLConfig) {
+	bus.AddHandler(func(ctx context.Context, q *query.GetSAMLConfig) error {
+		if config == nil {
+			return app.ErrNotFound
+		}
+		q.Result = config
+		return nil
+	})
+}
+
+func newSAMLToken(identifier string, roles []string) string {
+	return newSAMLTokenForTenant(mock.DemoTenant.ID, identifier, roles)
+}
+
+func newSAMLTokenForTenant(tenantID int, identifier string, roles []string) string {
+	token, _ := jwt.Encode(jwt.SAMLClaims{
+		NameID:     "jdoe",
+		Name:       "John Doe",
+		Email:      "john.doe@company.com",
+		Roles:      roles,
+		Redirect:   "http://demo.test.fider.io/hello",
+		Identifier: identifier,
+		TenantID:   tenantID,
+		Metadata: jwt.Metadata{
+			ExpiresAt: jwt.Time(time.Now().Add(5 * time.Minute)),
+		},
+	})
+	return token
+}
+
+func TestSignInBySAMLHandler(t *testing.T) {
+	RegisterT(t)
+
+	mockSAMLConfig(&entity.SAMLConfig{
+		Status:    enum.OAuthConfigEnabled,
+		IdPSSOURL: "https://idp.example.com/sso",
+	})
+
+	server := mock.NewServer()
+	code, response := server.
+		WithURL("http://demo.test.fider.io/saml/login?redirect=http://demo.test.fider.io/hello").
+		OnTenant(mock.DemoTenant).
+		AddCookie(web.CookieSessionName, "MY_SESSION_ID").
+		Use(middlewares.Session()).
+		Execute(handlers.SignInBySAML())
+
+	Expect(code).Equals(http.StatusTemporaryRedirect)
+	location, _ := url.Parse(response.Header().Get("Location"))
+	Expect(location.Host).Equals("idp.example.com")
+	Expect(location.Query().Get("SAMLRequest")).IsNotEmpty()
+
+	state, err := jwt.DecodeOAuthStateClaims(location.Query().Get("RelayState"))
+	Expect(err).IsNil()
+	Expect(state.Redirect).Equals("http://demo.test.fider.io/hello")
+	Expect(state.Identifier).Equals("MY_SESSION_ID")
+	Expect(state.Code).IsNotEmpty()
+}
+
+func TestSignInBySAMLHandler_NotConfigured(t *testing.T) {
+	RegisterT(t)
+
+	mockSAMLConfig(nil)
+
+	server := mock.NewServer()
+	code, _ := server.
+		WithURL("http://demo.test.fider.io/saml/login").
+		OnTenant(mock.DemoTenant).
+		Execute(handlers.SignInBySAML())
+
+	Expect(code).Equals(http.StatusNotFound)
+}
+
+func TestSignInBySAMLHandler_EvilRedirect(t *testing.T) {
+	RegisterT(t)
+
+	server := mock.NewServer()
+	code, _ := server.
+		WithURL("http://demo.test.fider.io/saml/login?redirect=http://evil.com").
+		OnTenant(mock.DemoTenant).
+		Execute(handlers.SignInBySAML())
+
+	Expect(code).Equals(http.StatusForbidden)
+}
+
+func TestSAMLAssertionConsumerHandler_InvalidRelayState(t *testing.T) {
+	RegisterT(t)
+
+	server := mock.NewServer()
+	code, _ := server.
+		WithURL("http://demo.test.fider.io/saml/acs").
+		OnTenant(mock.DemoTenant).
+		ExecutePost(handlers.SAMLAssertionConsumer(), "SAMLResponse=abc&RelayState=invalid")
+
+	Expect(code).Equals(http.StatusForbidden)
+}
+
+func TestSAMLTokenHandler_NewUser(t *testing.T) {
+	RegisterT(t)
+
+	mockSAMLConfig(&entity.SAMLConfig{
+		Status:         enum.OAuthConfigEnabled,
+		RolesAttribute: "groups",
+		AllowedRoles:   "staff",
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByProvider) error {
+		Expect(q.Provider).Equals(app.SAMLProvider)
+		Expect(q.UID).Equals("jdoe")
+		return app.ErrNotFound
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByEmail) error {
+		Expect(q.Email).Equals("john.doe@company.com")
+		return app.ErrNotFound
+	})
+
+	var registeredUser *entity.User
+	bus.AddHandler(func(ctx context.Context, c *cmd.RegisterUser) error {
+		registeredUser = c.User
+		return nil
+	})
+
+	server := mock.NewServer()
+	code, response := server.
+		WithURL("http://demo.test.fider.io/saml/token?token="+newSAMLToken("MY_SESSION_ID", []string{"staff"})).
+		OnTenant(mock.DemoTenant).
+		AddCookie(web.CookieSessionName, "MY_SESSION_ID").
+		Use(middlewares.Session()).
+		Execute(handlers.SAMLToken())
+
+	Expect(code).Equals(http.StatusTemporaryRedirect)
+	Expect(response.Header().Get("Location")).Equals("http://demo.test.fider.io/hello")
+
+	Expect(registeredUser.Name).Equals("John Doe")
+	Expect(registeredUser.Email).Equals("john.doe@company.com")
+	Expect(registeredUser.Providers[0].Name).Equals(app.SAMLProvider)
+	Expect(registeredUser.Providers[0].UID).Equals("jdoe")
+
+	ExpectFiderAuthCookie(response, registeredUser)
+}
+
+func TestSAMLTokenHandler_NotAllowedRole(t *testing.T) {
+	RegisterT(t)
+
+	mockSAMLConfig(&entity.SAMLConfig{
+		Status:         enum.OAuthConfigEnabled,
+		RolesAttribute: "groups",
+		AllowedRoles:   "staff",
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByProvider) error {
+		return app.ErrNotFound
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByEmail) error {
+		return app.ErrNotFound
+	})
+
+	server := mock.NewServer()
+	code, response := server.
+		WithURL("http://demo.test.fider.io/saml/token?token="+newSAMLToken("MY_SESSION_ID", []string{"guests"})).
+		OnTenant(mock.DemoTenant).
+		AddCookie(web.CookieSessionName, "MY_SESSION_ID").
+		Use(middlewares.Session()).
+		Execute(handlers.SAMLToken())
+
+	Expect(code).Equals(http.StatusTemporaryRedirect)
+	Expect(response.Header().Get("Location")).Equals("/access-denied")
+	ExpectFiderAuthCookie(response, nil)
+}
+
+func TestSAMLTokenHandler_InvalidIdentifier(t *testing.T) {
+	RegisterT(t)
+
+	server := mock.NewServer()
+	code, response := server.
+		WithURL("http://demo.test.fider.io/saml/token?token="+newSAMLToken("SOME_OTHER_SESSION_ID", nil)).
+		OnTenant(mock.DemoTenant).
+		AddCookie(web.CookieSessionName, "MY_SESSION_ID").
+		Use(middlewares.Session()).
+		Execute(handlers.SAMLToken())
+
+	Expect(code).Equals(http.StatusTemporaryRedirect)
+	Expect(response.Header().Get("Location")).Equals("http://demo.test.fider.io/hello")
+	ExpectFiderAuthCookie(response, nil)
+}
+
+func TestSAMLTokenHandler_OtherTenant(t *testing.T) {
+	RegisterT(t)
+
+	mockSAMLConfig(&entity.SAMLConfig{Status: enum.OAuthConfigEnabled})
+
+	server := mock.NewServer()
+	code, response := server.
+		WithURL("http://demo.test.fider.io/saml/token?token="+newSAMLTokenForTenant(mock.AvengersTenant.ID, "MY_SESSION_ID", nil)).
+		OnTenant(mock.DemoTenant).
+		AddCookie(web.CookieSessionName, "MY_SESSION_ID").
+		Use(middlewares.Session()).
+		Execute(handlers.SAMLToken())
+
+	Expect(code).Equals(http.StatusForbidden)
+	ExpectFiderAuthCookie(response, nil)
+}
+
+func TestSAMLAssertionConsumerHandler_OtherTenantRelayState(t *testing.T) {
+	RegisterT(t)
+
+	relayState, _ := jwt.Encode(jwt.OAuthStateClaims{
+		Redirect:   "http://demo.test.fider.io",
+		Identifier: "MY_SESSION_ID",
+		Code:       "id-request",
+		TenantID:   mock.AvengersTenant.ID,
+		Metadata: jwt.Metadata{
+			ExpiresAt: jwt.Time(time.Now().Add(10 * time.Minute)),
+		},
+	})
+
+	server := mock.NewServer()
+	code, _ := server.
+		OnTenant(mock.DemoTenant).
+		ExecutePost(handlers.SAMLAssertionConsumer(), "SAMLResponse=abc&RelayState="+url.QueryEscape(relayState))
+
+	Expect(code).Equals(http.StatusForbidden)
+}
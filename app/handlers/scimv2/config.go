@@ -0,0 +1,58 @@
+package scimv2
+
+import (
+	"net/http"
+
+	"github.com/getfider/fider/app/pkg/scim"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+const (
+	defaultCount = 100
+	maxCount     = 500
+)
+
+// ServiceProviderConfig describes which SCIM features are supported
+func ServiceProviderConfig() web.HandlerFunc {
+	return func(c *web.Context) error {
+		return scim.Write(c, http.StatusOK, web.Map{
+			"schemas":        []string{scim.ServiceProviderConfigSchema},
+			"patch":          web.Map{"supported": true},
+			"bulk":           web.Map{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+			"filter":         web.Map{"supported": true, "maxResults": maxCount},
+			"changePassword": web.Map{"supported": false},
+			"sort":           web.Map{"supported": false},
+			"etag":           web.Map{"supported": false},
+			"authenticationSchemes": []web.Map{
+				{
+					"type":        "oauthbearertoken",
+					"name":        "Bearer Token",
+					"description": "Authentication using the SCIM token generated in the site settings",
+					"primary":     true,
+				},
+			},
+			"meta": scim.Meta{
+				ResourceType: "ServiceProviderConfig",
+				Location:     c.BaseURL() + "/scim/v2/ServiceProviderConfig",
+			},
+		})
+	}
+}
+
+// pagination returns the 1-based index of the first resource and the number of resources to return
+func pagination(c *web.Context) (int, int) {
+	startIndex, err := c.QueryParamAsInt("startIndex")
+	if err != nil || startIndex < 1 {
+		startIndex = 1
+	}
+
+	count := defaultCount
+	if c.QueryParam("count") != "" {
+		count, err = c.QueryParamAsInt("count")
+		if err != nil || count < 0 {
+			count = defaultCount
+		}
+	}
+
+	return startIndex, min(count, maxCount)
+}
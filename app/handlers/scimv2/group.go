@@ -0,0 +1,196 @@
+package scimv2
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/scim"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+// Each role is exposed as a group, so identity providers assign roles through group membership
+var groupRoles = []enum.Role{enum.RoleAdministrator, enum.RoleCollaborator, enum.RoleVisitor}
+
+// ListGroups returns the groups matching the filter
+func ListGroups() web.HandlerFunc {
+	return func(c *web.Context) error {
+		roles := groupRoles
+		if filterParam := c.QueryParam("filter"); filterParam != "" {
+			filter, err := scim.ParseFilter(filterParam)
+			if err != nil {
+				return scim.WriteError(c, err)
+			}
+			if filter.Attribute != "displayname" && filter.Attribute != "id" {
+				return scim.WriteError(c, scim.NewError(http.StatusBadRequest, "invalidFilter", "Filtering by '%s' is not supported", filter.Attribute))
+			}
+
+			roles = make([]enum.Role, 0)
+			if role, ok := roleFromGroupID(filter.Value); ok {
+				roles = append(roles, role)
+			}
+		}
+
+		users, err := listGroupMembers(c)
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		startIndex, count := pagination(c)
+		resources := make([]any, 0)
+		for i := startIndex - 1; i < len(roles) && len(resources) < count; i++ {
+			resources = append(resources, toSCIMGroup(c, roles[i], users))
+		}
+
+		return scim.Write(c, http.StatusOK, scim.NewListResponse(resources, len(roles), startIndex))
+	}
+}
+
+// GetGroup returns a single group
+func GetGroup() web.HandlerFunc {
+	return func(c *web.Context) error {
+		role, ok := roleFromGroupID(c.Param("id"))
+		if !ok {
+			return scim.WriteError(c, scim.ErrNotFound)
+		}
+
+		users, err := listGroupMembers(c)
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		return scim.Write(c, http.StatusOK, toSCIMGroup(c, role, users))
+	}
+}
+
+// PatchGroup adds and removes members of a group, which changes their role.
+// Users removed from the collaborator or administrator groups become visitors,
+// and patches that would leave the site without an active administrator are rejected
+func PatchGroup() web.HandlerFunc {
+	return func(c *web.Context) error {
+		role, ok := roleFromGroupID(c.Param("id"))
+		if !ok {
+			return scim.WriteError(c, scim.ErrNotFound)
+		}
+
+		request := &scim.PatchRequest{}
+		if err := json.Unmarshal([]byte(c.Request.Body), request); err != nil {
+			return scim.WriteError(c, scim.NewError(http.StatusBadRequest, "invalidSyntax", "Request body is not a valid PatchOp"))
+		}
+
+		patch, err := request.GroupPatch()
+		if err != nil {
+			return scim.WriteError(c, err)
+		}
+
+		allUsers := &query.GetAllUsers{}
+		if err := bus.Dispatch(c, allUsers); err != nil {
+			return c.Failure(err)
+		}
+
+		roles := make(map[int]enum.Role, len(allUsers.Result))
+		for _, user := range allUsers.Result {
+			roles[user.ID] = user.Role
+		}
+
+		removed := patch.Remove
+		if patch.Replace {
+			for _, user := range allUsers.Result {
+				if user.Role == role && !slices.Contains(patch.Add, strconv.Itoa(user.ID)) {
+					removed = append(removed, strconv.Itoa(user.ID))
+				}
+			}
+		}
+
+		// Changes are applied to roles first, so that the resulting administrators are known before any of them is saved
+		changes := make([]*cmd.ChangeUserRole, 0)
+		for _, id := range removed {
+			user, err := getUser(c, id)
+			if err != nil {
+				return scim.WriteError(c, err)
+			}
+			if roles[user.ID] == role && role != enum.RoleVisitor {
+				roles[user.ID] = enum.RoleVisitor
+				changes = append(changes, &cmd.ChangeUserRole{UserID: user.ID, Role: enum.RoleVisitor})
+			}
+		}
+
+		for _, id := range patch.Add {
+			user, err := getUser(c, id)
+			if err != nil {
+				return scim.WriteError(c, err)
+			}
+			if roles[user.ID] != role {
+				roles[user.ID] = role
+				changes = append(changes, &cmd.ChangeUserRole{UserID: user.ID, Role: role})
+			}
+		}
+
+		if !hasActiveAdministrator(allUsers.Result, roles) {
+			return scim.WriteError(c, scim.NewError(http.StatusBadRequest, "invalidValue", "The site must have at least one active administrator"))
+		}
+
+		for _, change := range changes {
+			if err := bus.Dispatch(c, change); err != nil {
+				return c.Failure(err)
+			}
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// hasActiveAdministrator returns true if any active user would be an administrator with given roles
+func hasActiveAdministrator(users []*entity.User, roles map[int]enum.Role) bool {
+	for _, user := range users {
+		if user.Status == enum.UserActive && roles[user.ID] == enum.RoleAdministrator {
+			return true
+		}
+	}
+	return false
+}
+
+func roleFromGroupID(id string) (enum.Role, bool) {
+	var role enum.Role
+	_ = role.UnmarshalText([]byte(id))
+	return role, role != 0
+}
+
+// listGroupMembers returns every user that isn't deleted, unless members are excluded from the response
+func listGroupMembers(c *web.Context) ([]*entity.User, error) {
+	if c.QueryParam("excludedAttributes") == "members" {
+		return nil, nil
+	}
+
+	allUsers := &query.GetAllUsers{}
+	if err := bus.Dispatch(c, allUsers); err != nil {
+		return nil, err
+	}
+	return allUsers.Result, nil
+}
+
+func toSCIMGroup(c *web.Context, role enum.Role, users []*entity.User) *scim.Group {
+	members := make([]scim.Member, 0)
+	for _, user := range users {
+		if user.Role == role {
+			members = append(members, scim.Member{Value: strconv.Itoa(user.ID), Display: user.Name})
+		}
+	}
+
+	return &scim.Group{
+		Schemas:     []string{scim.GroupSchema},
+		ID:          role.String(),
+		DisplayName: role.String(),
+		Members:     members,
+		Meta: &scim.Meta{
+			ResourceType: "Group",
+			Location:     c.BaseURL() + "/scim/v2/Groups/" + role.String(),
+		},
+	}
+}
@@ -0,0 +1,126 @@
+package scimv2_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/getfider/fider/app/handlers/scimv2"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/mock"
+)
+
+func TestListGroupsHandler(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetAllUsers) error {
+		q.Result = []*entity.User{mock.JonSnow, mock.AryaStark}
+		return nil
+	})
+
+	status, query := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		ExecuteAsJSON(scimv2.ListGroups())
+
+	Expect(status).Equals(http.StatusOK)
+	Expect(query.Int32("totalResults")).Equals(3)
+	Expect(query.ArrayFieldStrings("Resources", "displayName")).Equals([]string{"administrator", "collaborator", "visitor"})
+	Expect(query.String("Resources[0].members[0].value")).Equals("1")
+
+	status, query = mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		WithURL("http://demo.test.fider.io/scim/v2/Groups?filter=displayName+eq+%22visitor%22&excludedAttributes=members").
+		ExecuteAsJSON(scimv2.ListGroups())
+
+	Expect(status).Equals(http.StatusOK)
+	Expect(query.Int32("totalResults")).Equals(1)
+	Expect(query.String("Resources[0].id")).Equals("visitor")
+	Expect(query.Contains("Resources[0].members")).IsFalse()
+}
+
+func TestGetGroupHandler_NotFound(t *testing.T) {
+	RegisterT(t)
+
+	status, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AddParam("id", "owners").
+		Execute(scimv2.GetGroup())
+
+	Expect(status).Equals(http.StatusNotFound)
+}
+
+func TestPatchGroupHandler(t *testing.T) {
+	RegisterT(t)
+	mockUserQueries()
+	bus.AddHandler(func(ctx context.Context, q *query.GetAllUsers) error {
+		q.Result = []*entity.User{mock.JonSnow, mock.AryaStark}
+		return nil
+	})
+
+	changes := make([]*cmd.ChangeUserRole, 0)
+	bus.AddHandler(func(ctx context.Context, c *cmd.ChangeUserRole) error {
+		changes = append(changes, c)
+		return nil
+	})
+
+	status, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AddParam("id", "administrator").
+		ExecutePost(scimv2.PatchGroup(), `{
+			"Operations": [
+				{ "op": "add", "path": "members", "value": [{ "value": "2" }] },
+				{ "op": "remove", "path": "members[value eq \"1\"]" }
+			]
+		}`)
+
+	Expect(status).Equals(http.StatusNoContent)
+	Expect(changes).HasLen(2)
+	Expect(changes[0].UserID).Equals(mock.JonSnow.ID)
+	Expect(changes[0].Role).Equals(enum.RoleVisitor)
+	Expect(changes[1].UserID).Equals(mock.AryaStark.ID)
+	Expect(changes[1].Role).Equals(enum.RoleAdministrator)
+}
+
+func TestPatchGroupHandler_NoAdministratorLeft(t *testing.T) {
+	RegisterT(t)
+	mockUserQueries()
+	bus.AddHandler(func(ctx context.Context, q *query.GetAllUsers) error {
+		q.Result = []*entity.User{mock.JonSnow, mock.AryaStark}
+		return nil
+	})
+
+	changes := make([]*cmd.ChangeUserRole, 0)
+	bus.AddHandler(func(ctx context.Context, c *cmd.ChangeUserRole) error {
+		changes = append(changes, c)
+		return nil
+	})
+
+	status, response := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AddParam("id", "administrator").
+		ExecutePostAsJSON(scimv2.PatchGroup(), `{
+			"Operations": [
+				{ "op": "replace", "path": "members", "value": [] }
+			]
+		}`)
+
+	Expect(status).Equals(http.StatusBadRequest)
+	Expect(response.String("detail")).Equals("The site must have at least one active administrator")
+
+	status, _ = mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AddParam("id", "visitor").
+		ExecutePost(scimv2.PatchGroup(), `{
+			"Operations": [
+				{ "op": "add", "path": "members", "value": [{ "value": "1" }] }
+			]
+		}`)
+
+	Expect(status).Equals(http.StatusBadRequest)
+	Expect(changes).HasLen(0)
+}
@@ -0,0 +1,401 @@
+package scimv2
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/scim"
+	"github.com/getfider/fider/app/pkg/validate"
+	"github.com/getfider/fider/app/pkg/web"
+	"github.com/getfider/fider/app/tasks"
+)
+
+// ListUsers returns the users matching the filter, used by identity providers to find existing users
+func ListUsers() web.HandlerFunc {
+	return func(c *web.Context) error {
+		var users []*entity.User
+		if filterParam := c.QueryParam("filter"); filterParam != "" {
+			filter, err := scim.ParseFilter(filterParam)
+			if err != nil {
+				return scim.WriteError(c, err)
+			}
+
+			users, err = findUsers(c, filter)
+			if err != nil {
+				return scim.WriteError(c, err)
+			}
+		} else {
+			allUsers := &query.GetAllUsers{}
+			if err := bus.Dispatch(c, allUsers); err != nil {
+				return c.Failure(err)
+			}
+			users = allUsers.Result
+		}
+
+		startIndex, count := pagination(c)
+		page := make([]*entity.User, 0)
+		if startIndex <= len(users) {
+			page = users[startIndex-1 : min(len(users), startIndex-1+count)]
+		}
+
+		externalIDs, err := getExternalIDs(c, page...)
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		resources := make([]any, len(page))
+		for i, user := range page {
+			resources[i] = toSCIMUser(c, user, externalIDs[user.ID])
+		}
+
+		return scim.Write(c, http.StatusOK, scim.NewListResponse(resources, len(users), startIndex))
+	}
+}
+
+// GetUser returns a single user
+func GetUser() web.HandlerFunc {
+	return func(c *web.Context) error {
+		user, err := getUser(c, c.Param("id"))
+		if err != nil {
+			return scim.WriteError(c, err)
+		}
+		return writeUser(c, http.StatusOK, user)
+	}
+}
+
+// CreateUser provisions a new user
+func CreateUser() web.HandlerFunc {
+	return func(c *web.Context) error {
+		input := &scim.User{}
+		if err := json.Unmarshal([]byte(c.Request.Body), input); err != nil {
+			return scim.WriteError(c, scim.NewError(http.StatusBadRequest, "invalidSyntax", "Request body is not a valid User"))
+		}
+
+		email := strings.ToLower(input.PrimaryEmail())
+		if email == "" || len(validate.Email(c, email)) > 0 {
+			return scim.WriteError(c, scim.NewError(http.StatusBadRequest, "invalidValue", "userName or emails must have a valid email"))
+		}
+
+		getByEmail := &query.GetUserByEmail{Email: email}
+		err := bus.Dispatch(c, getByEmail)
+		if err == nil {
+			return scim.WriteError(c, scim.NewError(http.StatusConflict, "uniqueness", "A user with email '%s' already exists", email))
+		} else if errors.Cause(err) != app.ErrNotFound {
+			return c.Failure(err)
+		}
+
+		if input.ExternalID != "" {
+			getByExternalID := &query.GetUserByProvider{Provider: app.SCIMProvider, UID: input.ExternalID}
+			err := bus.Dispatch(c, getByExternalID)
+			if err == nil {
+				return scim.WriteError(c, scim.NewError(http.StatusConflict, "uniqueness", "A user with externalId '%s' already exists", input.ExternalID))
+			} else if errors.Cause(err) != app.ErrNotFound {
+				return c.Failure(err)
+			}
+		}
+
+		name := input.FullName()
+		if name == "" {
+			name = strings.Split(email, "@")[0]
+		}
+		if len(name) > 100 {
+			return scim.WriteError(c, scim.NewError(http.StatusBadRequest, "invalidValue", "Name must have less than 100 characters"))
+		}
+
+		user := &entity.User{
+			Tenant: c.Tenant(),
+			Name:   name,
+			Email:  email,
+			Role:   enum.RoleVisitor,
+		}
+		if input.ExternalID != "" {
+			user.Providers = []*entity.UserProvider{{Name: app.SCIMProvider, UID: input.ExternalID}}
+		}
+
+		if err := bus.Dispatch(c, &cmd.RegisterUser{User: user}); err != nil {
+			return c.Failure(err)
+		}
+		c.Enqueue(tasks.NotifyAboutNewUser(user))
+
+		if input.Active != nil && !*input.Active {
+			if err := bus.Dispatch(c, &cmd.BlockUser{UserID: user.ID}); err != nil {
+				return c.Failure(err)
+			}
+			user.Status = enum.UserBlocked
+		}
+
+		return scim.Write(c, http.StatusCreated, toSCIMUser(c, user, input.ExternalID))
+	}
+}
+
+// ReplaceUser replaces the attributes of a user
+func ReplaceUser() web.HandlerFunc {
+	return func(c *web.Context) error {
+		user, err := getUser(c, c.Param("id"))
+		if err != nil {
+			return scim.WriteError(c, err)
+		}
+
+		input := &scim.User{}
+		if err := json.Unmarshal([]byte(c.Request.Body), input); err != nil {
+			return scim.WriteError(c, scim.NewError(http.StatusBadRequest, "invalidSyntax", "Request body is not a valid User"))
+		}
+
+		if err := applyUserPatch(c, user, input.AsPatch()); err != nil {
+			return scim.WriteError(c, err)
+		}
+
+		return writeUser(c, http.StatusOK, user)
+	}
+}
+
+// PatchUser changes some attributes of a user, deactivating a user blocks it
+func PatchUser() web.HandlerFunc {
+	return func(c *web.Context) error {
+		user, err := getUser(c, c.Param("id"))
+		if err != nil {
+			return scim.WriteError(c, err)
+		}
+
+		request := &scim.PatchRequest{}
+		if err := json.Unmarshal([]byte(c.Request.Body), request); err != nil {
+			return scim.WriteError(c, scim.NewError(http.StatusBadRequest, "invalidSyntax", "Request body is not a valid PatchOp"))
+		}
+
+		patch, err := request.UserPatch()
+		if err != nil {
+			return scim.WriteError(c, err)
+		}
+
+		if err := applyUserPatch(c, user, patch); err != nil {
+			return scim.WriteError(c, err)
+		}
+
+		return writeUser(c, http.StatusOK, user)
+	}
+}
+
+// DeleteUser deletes a user the same way users delete their own accounts, keeping their content
+func DeleteUser() web.HandlerFunc {
+	return func(c *web.Context) error {
+		user, err := getUser(c, c.Param("id"))
+		if err != nil {
+			return scim.WriteError(c, err)
+		}
+
+		if err := keepActiveAdministrator(c, user); err != nil {
+			return scim.WriteError(c, err)
+		}
+
+		if err := bus.Dispatch(c, &cmd.DeleteUser{UserID: user.ID}); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func applyUserPatch(c *web.Context, user *entity.User, patch *scim.UserPatch) error {
+	// Checked before anything is changed, so that a rejected patch isn't partially applied
+	if patch.Active != nil && !*patch.Active {
+		if err := keepActiveAdministrator(c, user); err != nil {
+			return err
+		}
+	}
+
+	if patch.Email != nil && strings.ToLower(*patch.Email) != user.Email {
+		email := strings.ToLower(*patch.Email)
+		if len(validate.Email(c, email)) > 0 {
+			return scim.NewError(http.StatusBadRequest, "invalidValue", "'%s' is not a valid email", email)
+		}
+
+		getByEmail := &query.GetUserByEmail{Email: email}
+		err := bus.Dispatch(c, getByEmail)
+		if err == nil && getByEmail.Result.ID != user.ID {
+			return scim.NewError(http.StatusConflict, "uniqueness", "A user with email '%s' already exists", email)
+		} else if err != nil && errors.Cause(err) != app.ErrNotFound {
+			return err
+		}
+
+		if err := bus.Dispatch(c, &cmd.ChangeUserEmail{UserID: user.ID, Email: email}); err != nil {
+			return err
+		}
+		user.Email = email
+	}
+
+	if patch.Name != nil && *patch.Name != "" && *patch.Name != user.Name {
+		if len(*patch.Name) > 100 {
+			return scim.NewError(http.StatusBadRequest, "invalidValue", "Name must have less than 100 characters")
+		}
+		if err := bus.Dispatch(c, &cmd.ChangeUserName{UserID: user.ID, Name: *patch.Name}); err != nil {
+			return err
+		}
+		user.Name = *patch.Name
+	}
+
+	// The external ID links users created before SCIM was enabled, it can't be changed once set
+	if patch.ExternalID != nil && *patch.ExternalID != "" && !user.HasProvider(app.SCIMProvider) {
+		if err := bus.Dispatch(c, &cmd.RegisterUserProvider{
+			UserID:       user.ID,
+			ProviderName: app.SCIMProvider,
+			ProviderUID:  *patch.ExternalID,
+		}); err != nil {
+			return err
+		}
+		user.Providers = append(user.Providers, &entity.UserProvider{Name: app.SCIMProvider, UID: *patch.ExternalID})
+	}
+
+	if patch.Active != nil {
+		if !*patch.Active && user.Status == enum.UserActive {
+			if err := bus.Dispatch(c, &cmd.BlockUser{UserID: user.ID}); err != nil {
+				return err
+			}
+			user.Status = enum.UserBlocked
+		} else if *patch.Active && user.Status == enum.UserBlocked {
+			if err := bus.Dispatch(c, &cmd.UnblockUser{UserID: user.ID}); err != nil {
+				return err
+			}
+			user.Status = enum.UserActive
+		}
+	}
+
+	return nil
+}
+
+// keepActiveAdministrator returns an error if the site would be left without an active administrator once given user is deactivated
+func keepActiveAdministrator(c *web.Context, user *entity.User) error {
+	if user.Status != enum.UserActive || user.Role != enum.RoleAdministrator {
+		return nil
+	}
+
+	allUsers := &query.GetAllUsers{}
+	if err := bus.Dispatch(c, allUsers); err != nil {
+		return err
+	}
+
+	roles := make(map[int]enum.Role, len(allUsers.Result))
+	for _, other := range allUsers.Result {
+		if other.ID != user.ID {
+			roles[other.ID] = other.Role
+		}
+	}
+
+	if !hasActiveAdministrator(allUsers.Result, roles) {
+		return scim.NewError(http.StatusBadRequest, "invalidValue", "The site must have at least one active administrator")
+	}
+	return nil
+}
+
+// findUsers returns the users matching given filter, identity providers search users by userName, email or externalId
+func findUsers(c *web.Context, filter *scim.Filter) ([]*entity.User, error) {
+	var (
+		user *entity.User
+		err  error
+	)
+
+	switch filter.Attribute {
+	case "username", "emails", "emails.value":
+		getByEmail := &query.GetUserByEmail{Email: filter.Value}
+		err = bus.Dispatch(c, getByEmail)
+		user = getByEmail.Result
+	case "externalid":
+		getByExternalID := &query.GetUserByProvider{Provider: app.SCIMProvider, UID: filter.Value}
+		err = bus.Dispatch(c, getByExternalID)
+		user = getByExternalID.Result
+	case "id":
+		user, err = getUser(c, filter.Value)
+	default:
+		return nil, scim.NewError(http.StatusBadRequest, "invalidFilter", "Filtering by '%s' is not supported", filter.Attribute)
+	}
+
+	if err != nil {
+		if errors.Cause(err) == app.ErrNotFound || errors.Cause(err) == scim.ErrNotFound {
+			return []*entity.User{}, nil
+		}
+		return nil, err
+	}
+
+	return []*entity.User{user}, nil
+}
+
+func getUser(c *web.Context, id string) (*entity.User, error) {
+	userID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, scim.ErrNotFound
+	}
+
+	getUser := &query.GetUserByID{UserID: userID}
+	if err := bus.Dispatch(c, getUser); err != nil {
+		if errors.Cause(err) == app.ErrNotFound {
+			return nil, scim.ErrNotFound
+		}
+		return nil, err
+	}
+
+	if getUser.Result.Tenant == nil || getUser.Result.Tenant.ID != c.Tenant().ID {
+		return nil, scim.ErrNotFound
+	}
+
+	return getUser.Result, nil
+}
+
+func getExternalIDs(c *web.Context, users ...*entity.User) (map[int]string, error) {
+	userIDs := make([]int, len(users))
+	for i, user := range users {
+		userIDs[i] = user.ID
+	}
+
+	getUIDs := &query.GetUserProviderUIDs{Provider: app.SCIMProvider, UserIDs: userIDs}
+	if err := bus.Dispatch(c, getUIDs); err != nil {
+		return nil, err
+	}
+	return getUIDs.Result, nil
+}
+
+func writeUser(c *web.Context, statusCode int, user *entity.User) error {
+	externalIDs, err := getExternalIDs(c, user)
+	if err != nil {
+		return c.Failure(err)
+	}
+	return scim.Write(c, statusCode, toSCIMUser(c, user, externalIDs[user.ID]))
+}
+
+func toSCIMUser(c *web.Context, user *entity.User, externalID string) *scim.User {
+	id := strconv.Itoa(user.ID)
+	active := user.Status == enum.UserActive
+
+	userName := user.Email
+	if userName == "" {
+		userName = id
+	}
+
+	result := &scim.User{
+		Schemas:     []string{scim.UserSchema},
+		ID:          id,
+		ExternalID:  externalID,
+		UserName:    userName,
+		Name:        &scim.Name{Formatted: user.Name},
+		DisplayName: user.Name,
+		Active:      &active,
+		Groups:      []scim.Member{{Value: user.Role.String(), Display: user.Role.String()}},
+		Meta: &scim.Meta{
+			ResourceType: "User",
+			Location:     c.BaseURL() + "/scim/v2/Users/" + id,
+		},
+	}
+
+	if user.Email != "" {
+		result.Emails = []scim.Email{{Value: user.Email, Type: "work", Primary: true}}
+	}
+
+	return result
+}
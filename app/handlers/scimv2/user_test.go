@@ -0,0 +1,334 @@
+package scimv2_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/handlers/scimv2"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/mock"
+)
+
+func mockUserQueries() {
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByID) error {
+		for _, user := range []*entity.User{mock.JonSnow, mock.AryaStark} {
+			if user.ID == q.UserID {
+				q.Result = user
+				return nil
+			}
+		}
+		return app.ErrNotFound
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByEmail) error {
+		for _, user := range []*entity.User{mock.JonSnow, mock.AryaStark} {
+			if user.Email == q.Email {
+				q.Result = user
+				return nil
+			}
+		}
+		return app.ErrNotFound
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByProvider) error {
+		if q.Provider == app.SCIMProvider && q.UID == "00u2" {
+			q.Result = mock.AryaStark
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserProviderUIDs) error {
+		q.Result = map[int]string{}
+		for _, userID := range q.UserIDs {
+			if userID == mock.AryaStark.ID {
+				q.Result[userID] = "00u2"
+			}
+		}
+		return nil
+	})
+}
+
+func TestListUsersHandler_FilterByUserName(t *testing.T) {
+	RegisterT(t)
+	mockUserQueries()
+
+	status, query := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		WithURL("http://demo.test.fider.io/scim/v2/Users?filter=userName+eq+%22arya.stark%40got.com%22").
+		ExecuteAsJSON(scimv2.ListUsers())
+
+	Expect(status).Equals(http.StatusOK)
+	Expect(query.Int32("totalResults")).Equals(1)
+	Expect(query.String("Resources[0].id")).Equals("2")
+	Expect(query.String("Resources[0].externalId")).Equals("00u2")
+	Expect(query.String("Resources[0].userName")).Equals("arya.stark@got.com")
+	Expect(query.String("Resources[0].groups[0].value")).Equals("visitor")
+}
+
+func TestListUsersHandler_FilterWithoutMatches(t *testing.T) {
+	RegisterT(t)
+	mockUserQueries()
+
+	status, query := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		WithURL("http://demo.test.fider.io/scim/v2/Users?filter=externalId+eq+%22unknown%22").
+		ExecuteAsJSON(scimv2.ListUsers())
+
+	Expect(status).Equals(http.StatusOK)
+	Expect(query.Int32("totalResults")).Equals(0)
+	Expect(string(query.Raw("Resources"))).Equals("[]")
+}
+
+func TestListUsersHandler_Pagination(t *testing.T) {
+	RegisterT(t)
+	mockUserQueries()
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetAllUsers) error {
+		q.Result = []*entity.User{mock.JonSnow, mock.AryaStark}
+		return nil
+	})
+
+	status, query := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		WithURL("http://demo.test.fider.io/scim/v2/Users?startIndex=2&count=10").
+		ExecuteAsJSON(scimv2.ListUsers())
+
+	Expect(status).Equals(http.StatusOK)
+	Expect(query.Int32("totalResults")).Equals(2)
+	Expect(query.Int32("startIndex")).Equals(2)
+	Expect(query.Int32("itemsPerPage")).Equals(1)
+	Expect(query.String("Resources[0].id")).Equals("2")
+}
+
+func TestListUsersHandler_UnsupportedFilter(t *testing.T) {
+	RegisterT(t)
+
+	status, query := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		WithURL("http://demo.test.fider.io/scim/v2/Users?filter=title+eq+%22Lord%22").
+		ExecuteAsJSON(scimv2.ListUsers())
+
+	Expect(status).Equals(http.StatusBadRequest)
+	Expect(query.String("scimType")).Equals("invalidFilter")
+}
+
+func TestGetUserHandler_OtherTenant(t *testing.T) {
+	RegisterT(t)
+	mockUserQueries()
+
+	status, query := mock.NewServer().
+		OnTenant(mock.AvengersTenant).
+		AddParam("id", mock.AryaStark.ID).
+		ExecuteAsJSON(scimv2.GetUser())
+
+	Expect(status).Equals(http.StatusNotFound)
+	Expect(query.String("status")).Equals("404")
+}
+
+func TestCreateUserHandler(t *testing.T) {
+	RegisterT(t)
+	mockUserQueries()
+
+	var newUser *entity.User
+	bus.AddHandler(func(ctx context.Context, c *cmd.RegisterUser) error {
+		c.User.ID = 3
+		newUser = c.User
+		return nil
+	})
+
+	var blocked *cmd.BlockUser
+	bus.AddHandler(func(ctx context.Context, c *cmd.BlockUser) error {
+		blocked = c
+		return nil
+	})
+
+	status, query := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		WithURL("http://demo.test.fider.io/scim/v2/Users").
+		ExecutePostAsJSON(scimv2.CreateUser(), `{
+			"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"],
+			"externalId": "00u3",
+			"userName": "Sansa.Stark@got.com",
+			"name": { "givenName": "Sansa", "familyName": "Stark" },
+			"active": false
+		}`)
+
+	Expect(status).Equals(http.StatusCreated)
+	Expect(query.String("id")).Equals("3")
+	Expect(query.String("externalId")).Equals("00u3")
+	Expect(query.String("userName")).Equals("sansa.stark@got.com")
+	Expect(query.String("displayName")).Equals("Sansa Stark")
+	Expect(query.String("meta.location")).Equals("http://demo.test.fider.io/scim/v2/Users/3")
+
+	Expect(newUser.Name).Equals("Sansa Stark")
+	Expect(newUser.Email).Equals("sansa.stark@got.com")
+	Expect(newUser.Role).Equals(enum.RoleVisitor)
+	Expect(newUser.Providers[0].Name).Equals(app.SCIMProvider)
+	Expect(newUser.Providers[0].UID).Equals("00u3")
+	Expect(blocked.UserID).Equals(3)
+}
+
+func TestCreateUserHandler_Conflict(t *testing.T) {
+	RegisterT(t)
+	mockUserQueries()
+
+	status, query := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		ExecutePostAsJSON(scimv2.CreateUser(), `{ "userName": "sansa", "emails": [{ "value": "arya.stark@got.com", "primary": true }] }`)
+
+	Expect(status).Equals(http.StatusConflict)
+	Expect(query.String("scimType")).Equals("uniqueness")
+
+	status, query = mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		ExecutePostAsJSON(scimv2.CreateUser(), `{ "userName": "sansa.stark@got.com", "externalId": "00u2" }`)
+
+	Expect(status).Equals(http.StatusConflict)
+	Expect(query.String("scimType")).Equals("uniqueness")
+}
+
+func TestCreateUserHandler_InvalidEmail(t *testing.T) {
+	RegisterT(t)
+
+	status, query := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		ExecutePostAsJSON(scimv2.CreateUser(), `{ "userName": "sansa" }`)
+
+	Expect(status).Equals(http.StatusBadRequest)
+	Expect(query.String("scimType")).Equals("invalidValue")
+}
+
+func TestPatchUserHandler_Deactivate(t *testing.T) {
+	RegisterT(t)
+	mockUserQueries()
+
+	var blocked *cmd.BlockUser
+	bus.AddHandler(func(ctx context.Context, c *cmd.BlockUser) error {
+		blocked = c
+		return nil
+	})
+
+	var renamed *cmd.ChangeUserName
+	bus.AddHandler(func(ctx context.Context, c *cmd.ChangeUserName) error {
+		renamed = c
+		return nil
+	})
+
+	status, query := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AddParam("id", mock.AryaStark.ID).
+		ExecutePostAsJSON(scimv2.PatchUser(), `{
+			"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+			"Operations": [
+				{ "op": "replace", "path": "active", "value": false },
+				{ "op": "replace", "path": "displayName", "value": "No One" }
+			]
+		}`)
+
+	Expect(status).Equals(http.StatusOK)
+	Expect(query.String("displayName")).Equals("No One")
+	Expect(query.Contains("active")).IsTrue()
+	Expect(string(query.Raw("active"))).Equals("false")
+	Expect(blocked.UserID).Equals(mock.AryaStark.ID)
+	Expect(renamed.UserID).Equals(mock.AryaStark.ID)
+	Expect(renamed.Name).Equals("No One")
+}
+
+func TestPatchUserHandler_Reactivate(t *testing.T) {
+	RegisterT(t)
+	mockUserQueries()
+
+	var unblocked *cmd.UnblockUser
+	bus.AddHandler(func(ctx context.Context, c *cmd.UnblockUser) error {
+		unblocked = c
+		return nil
+	})
+
+	server := mock.NewServer()
+	mock.AryaStark.Status = enum.UserBlocked
+
+	status, query := server.
+		OnTenant(mock.DemoTenant).
+		AddParam("id", mock.AryaStark.ID).
+		ExecutePostAsJSON(scimv2.PatchUser(), `{ "Operations": [{ "op": "Replace", "value": { "active": "True" } }] }`)
+
+	Expect(status).Equals(http.StatusOK)
+	Expect(string(query.Raw("active"))).Equals("true")
+	Expect(unblocked.UserID).Equals(mock.AryaStark.ID)
+}
+
+func TestDeleteUserHandler(t *testing.T) {
+	RegisterT(t)
+	mockUserQueries()
+
+	var deleted *cmd.DeleteUser
+	bus.AddHandler(func(ctx context.Context, c *cmd.DeleteUser) error {
+		deleted = c
+		return nil
+	})
+
+	status, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AddParam("id", mock.AryaStark.ID).
+		Execute(scimv2.DeleteUser())
+
+	Expect(status).Equals(http.StatusNoContent)
+	Expect(deleted.UserID).Equals(mock.AryaStark.ID)
+}
+
+func TestPatchUserHandler_DeactivateLastAdministrator(t *testing.T) {
+	RegisterT(t)
+	mockUserQueries()
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetAllUsers) error {
+		q.Result = []*entity.User{mock.JonSnow, mock.AryaStark}
+		return nil
+	})
+
+	blocked := false
+	bus.AddHandler(func(ctx context.Context, c *cmd.BlockUser) error {
+		blocked = true
+		return nil
+	})
+
+	status, query := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AddParam("id", mock.JonSnow.ID).
+		ExecutePostAsJSON(scimv2.PatchUser(), `{ "Operations": [{ "op": "replace", "path": "active", "value": false }] }`)
+
+	Expect(status).Equals(http.StatusBadRequest)
+	Expect(query.String("scimType")).Equals("invalidValue")
+	Expect(blocked).IsFalse()
+}
+
+func TestDeleteUserHandler_LastAdministrator(t *testing.T) {
+	RegisterT(t)
+	mockUserQueries()
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetAllUsers) error {
+		q.Result = []*entity.User{mock.JonSnow, mock.AryaStark}
+		return nil
+	})
+
+	deleted := false
+	bus.AddHandler(func(ctx context.Context, c *cmd.DeleteUser) error {
+		deleted = true
+		return nil
+	})
+
+	status, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AddParam("id", mock.JonSnow.ID).
+		Execute(scimv2.DeleteUser())
+
+	Expect(status).Equals(http.StatusBadRequest)
+	Expect(deleted).IsFalse()
+}
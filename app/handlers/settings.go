@@ -82,11 +82,19 @@ func UserSettings() web.HandlerFunc {
 			return err
 		}
 
+		apiTokens := &query.GetUserAPITokens{}
+		if c.User().IsCollaborator() {
+			if err := bus.Dispatch(c, apiTokens); err != nil {
+				return err
+			}
+		}
+
 		return c.Page(http.StatusOK, web.Props{
 			Page:  "MySettings/MySettings.page",
 			Title: "Settings",
 			Data: web.Map{
 				"userSettings": settings.Result,
+				"apiTokens":    apiTokens.Result,
 			},
 		})
 	}
@@ -125,6 +133,46 @@ func UpdateUserSettings() web.HandlerFunc {
 	}
 }
 
+// CreateAPIToken creates a personal access token for current user
+func CreateAPIToken() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.CreateAPIToken)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		createToken := &cmd.CreateAPIToken{
+			Name:      action.Name,
+			Scopes:    action.Scopes,
+			ExpiresAt: action.ExpiresAt(),
+		}
+		if err := bus.Dispatch(c, createToken); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{
+			"token":    createToken.Token,
+			"apiToken": createToken.Result,
+		})
+	}
+}
+
+// RevokeAPIToken deletes a personal access token of current user
+func RevokeAPIToken() web.HandlerFunc {
+	return func(c *web.Context) error {
+		tokenID, err := c.ParamAsInt("id")
+		if err != nil {
+			return c.NotFound()
+		}
+
+		if err := bus.Dispatch(c, &cmd.RevokeAPIToken{TokenID: tokenID}); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
+
 // ChangeUserRole changes given user role
 func ChangeUserRole() web.HandlerFunc {
 	return func(c *web.Context) error {
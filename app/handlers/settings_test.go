@@ -29,12 +29,73 @@ func TestSettingsHandler(t *testing.T) {
 		return nil
 	})
 
+	listedTokens := false
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserAPITokens) error {
+		listedTokens = true
+		return nil
+	})
+
 	server := mock.NewServer()
 	code, _ := server.
 		AsUser(mock.JonSnow).
 		Execute(handlers.UserSettings())
 
 	Expect(code).Equals(http.StatusOK)
+	Expect(listedTokens).IsTrue()
+}
+
+func TestCreateAPITokenHandler(t *testing.T) {
+	RegisterT(t)
+
+	var newToken *cmd.CreateAPIToken
+	bus.AddHandler(func(ctx context.Context, c *cmd.CreateAPIToken) error {
+		newToken = c
+		c.Token = entity.APITokenPrefix + "secret"
+		c.Result = &entity.APIToken{ID: 1, Name: c.Name, Scopes: c.Scopes, ExpiresAt: c.ExpiresAt}
+		return nil
+	})
+
+	server := mock.NewServer()
+	code, query := server.
+		AsUser(mock.JonSnow).
+		ExecutePostAsJSON(handlers.CreateAPIToken(), `{ "name": "Zapier", "scopes": ["posts:read", "comments:write"], "expiresInDays": 30 }`)
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(query.String("token")).Equals("fdr_secret")
+	Expect(query.Int32("apiToken.id")).Equals(1)
+	Expect(newToken.Name).Equals("Zapier")
+	Expect(newToken.Scopes).Equals([]enum.APIScope{enum.APIScopeReadPosts, enum.APIScopeWriteComments})
+	Expect(*newToken.ExpiresAt).TemporarilySimilar(time.Now().Add(30*24*time.Hour), time.Minute)
+}
+
+func TestCreateAPITokenHandler_Visitor(t *testing.T) {
+	RegisterT(t)
+
+	server := mock.NewServer()
+	code, _ := server.
+		AsUser(mock.AryaStark).
+		ExecutePost(handlers.CreateAPIToken(), `{ "name": "Zapier", "scopes": ["posts:read"] }`)
+
+	Expect(code).Equals(http.StatusForbidden)
+}
+
+func TestRevokeAPITokenHandler(t *testing.T) {
+	RegisterT(t)
+
+	var revoked *cmd.RevokeAPIToken
+	bus.AddHandler(func(ctx context.Context, c *cmd.RevokeAPIToken) error {
+		revoked = c
+		return nil
+	})
+
+	server := mock.NewServer()
+	code, _ := server.
+		AsUser(mock.JonSnow).
+		AddParam("id", 4).
+		Execute(handlers.RevokeAPIToken())
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(revoked.TokenID).Equals(4)
 }
 
 func TestUpdateUserSettingsHandler_EmptyInput(t *testing.T) {
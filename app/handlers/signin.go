@@ -244,6 +244,7 @@ func VerifySignInCode() web.HandlerFunc {
 					if err != nil {
 						return c.Failure(err)
 					}
+					c.Enqueue(tasks.NotifyAboutNewUser(user))
 
 					// Mark code as verified
 					err = bus.Dispatch(c, &cmd.SetKeyAsVerified{Key: result.Key})
@@ -344,6 +345,7 @@ func VerifySignInKey(kind enum.EmailVerificationKind) web.HandlerFunc {
 					if err != nil {
 						return c.Failure(err)
 					}
+					c.Enqueue(tasks.NotifyAboutNewUser(user))
 
 					err = bus.Dispatch(c, &cmd.SetKeyAsVerified{Key: key})
 					if err != nil {
@@ -410,6 +412,7 @@ func CompleteSignInProfile() web.HandlerFunc {
 		if err != nil {
 			return c.Failure(err)
 		}
+		c.Enqueue(tasks.NotifyAboutNewUser(user))
 
 		err = bus.Dispatch(c, &cmd.SetKeyAsVerified{Key: action.Key})
 		if err != nil {
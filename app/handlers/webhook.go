@@ -10,6 +10,7 @@ import (
 	"github.com/getfider/fider/app/models/query"
 	"github.com/getfider/fider/app/pkg/bus"
 	"github.com/getfider/fider/app/pkg/web"
+	"github.com/getfider/fider/app/pkg/webhook"
 )
 
 // ManageWebhooks is the page used by administrators to configure webhooks
@@ -51,7 +52,12 @@ func CreateWebhook() web.HandlerFunc {
 			return c.Failure(err)
 		}
 
-		return c.Ok(web.Map{"id": createWebhook.Result})
+		getWebhook := &query.GetWebhook{ID: createWebhook.Result}
+		if err := bus.Dispatch(c, getWebhook); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{"id": createWebhook.Result, "secret": getWebhook.Result.Secret})
 	}
 }
 
@@ -160,3 +166,64 @@ func GetWebhookProps() web.HandlerFunc {
 		return c.Ok(webhookProps.Result)
 	}
 }
+
+// RotateWebhookSecret replaces the secret used to sign the requests of a webhook
+func RotateWebhookSecret() web.HandlerFunc {
+	return func(c *web.Context) error {
+		id, err := c.ParamAsInt("id")
+		if err != nil {
+			return c.NotFound()
+		}
+
+		getWebhook := &query.GetWebhook{ID: id}
+		if err := bus.Dispatch(c, getWebhook); err != nil {
+			return c.Failure(err)
+		}
+
+		setSecret := &cmd.SetWebhookSecret{ID: id, Secret: webhook.NewSecret()}
+		if err := bus.Dispatch(c, setSecret); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{"secret": setSecret.Secret})
+	}
+}
+
+// ListWebhookDeliveries returns the latest deliveries of a webhook
+func ListWebhookDeliveries() web.HandlerFunc {
+	return func(c *web.Context) error {
+		id, err := c.ParamAsInt("id")
+		if err != nil {
+			return c.NotFound()
+		}
+
+		deliveries := &query.ListWebhookDeliveries{WebhookID: id, Limit: 50}
+		if err := bus.Dispatch(c, deliveries); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(deliveries.Result)
+	}
+}
+
+// RedeliverWebhook sends the payload of a previous delivery of a webhook again
+func RedeliverWebhook() web.HandlerFunc {
+	return func(c *web.Context) error {
+		id, err := c.ParamAsInt("id")
+		if err != nil {
+			return c.NotFound()
+		}
+
+		deliveryID, err := c.ParamAsInt("deliveryId")
+		if err != nil {
+			return c.NotFound()
+		}
+
+		redeliver := &cmd.RedeliverWebhook{ID: id, DeliveryID: deliveryID}
+		if err := bus.Dispatch(c, redeliver); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(redeliver.Result)
+	}
+}
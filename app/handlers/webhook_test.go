@@ -0,0 +1,63 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/handlers"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/mock"
+)
+
+func TestRotateWebhookSecretHandler(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetWebhook) error {
+		if q.ID == 3 {
+			q.Result = &entity.Webhook{ID: 3, Secret: "old-secret"}
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	var setSecret *cmd.SetWebhookSecret
+	bus.AddHandler(func(ctx context.Context, c *cmd.SetWebhookSecret) error {
+		setSecret = c
+		return nil
+	})
+
+	code, json := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("id", 3).
+		ExecutePostAsJSON(handlers.RotateWebhookSecret(), "{}")
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(setSecret).IsNotNil()
+	Expect(setSecret.ID).Equals(3)
+	Expect(setSecret.Secret).HasLen(48)
+	Expect(setSecret.Secret).NotEquals("old-secret")
+	Expect(json.String("secret")).Equals(setSecret.Secret)
+}
+
+func TestRotateWebhookSecretHandler_NotFound(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetWebhook) error {
+		return app.ErrNotFound
+	})
+
+	code, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("id", 4).
+		ExecutePost(handlers.RotateWebhookSecret(), "{}")
+
+	Expect(code).Equals(http.StatusNotFound)
+}
@@ -0,0 +1,41 @@
+package jobs
+
+import (
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/log"
+)
+
+// postSignaturesBatchSize is the number of posts indexed at once, so that a large backlog doesn't hold a single huge batch
+const postSignaturesBatchSize = 500
+
+// BackfillPostSignaturesJobHandler builds the similarity index of posts that are missing from it,
+// such as those created before the index existed
+type BackfillPostSignaturesJobHandler struct {
+}
+
+func (e BackfillPostSignaturesJobHandler) Schedule() string {
+	return "0 */10 * * * *" // every 10 minutes
+}
+
+func (e BackfillPostSignaturesJobHandler) Run(ctx Context) error {
+	total := 0
+	for {
+		c := &cmd.BackfillPostSignatures{Limit: postSignaturesBatchSize}
+		if err := bus.Dispatch(ctx, c); err != nil {
+			return err
+		}
+
+		total += c.Result
+		if c.Result < postSignaturesBatchSize {
+			break
+		}
+	}
+
+	log.Debugf(ctx, "@{Count} post signatures were backfilled", dto.Props{
+		"Count": total,
+	})
+
+	return nil
+}
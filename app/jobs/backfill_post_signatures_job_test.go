@@ -0,0 +1,39 @@
+package jobs_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getfider/fider/app/jobs"
+	"github.com/getfider/fider/app/models/cmd"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+)
+
+func TestBackfillPostSignaturesJob_Schedule_IsCorrect(t *testing.T) {
+	RegisterT(t)
+
+	job := &jobs.BackfillPostSignaturesJobHandler{}
+	Expect(job.Schedule()).Equals("0 */10 * * * *")
+}
+
+func TestBackfillPostSignaturesJob_RunsUntilNothingIsLeft(t *testing.T) {
+	RegisterT(t)
+
+	pending := 1200
+	dispatched := 0
+	bus.AddHandler(func(ctx context.Context, c *cmd.BackfillPostSignatures) error {
+		dispatched++
+		c.Result = min(c.Limit, pending)
+		pending -= c.Result
+		return nil
+	})
+
+	job := &jobs.BackfillPostSignaturesJobHandler{}
+	err := job.Run(jobs.Context{
+		Context: context.Background(),
+	})
+	Expect(err).IsNil()
+	Expect(pending).Equals(0)
+	Expect(dispatched).Equals(3)
+}
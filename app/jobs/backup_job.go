@@ -0,0 +1,73 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/backup"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/env"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/log"
+)
+
+// BackupJobHandler stores a backup archive of every active tenant on the blob storage
+// and deletes the oldest archives, keeping the number of archives defined by BACKUP_RETENTION.
+// A failure on one tenant doesn't prevent the others from being backed up.
+type BackupJobHandler struct {
+}
+
+func (j BackupJobHandler) Schedule() string {
+	return env.Config.Backup.Schedule
+}
+
+func (j BackupJobHandler) Run(ctx Context) error {
+	tenants := &query.GetActiveTenants{}
+	if err := bus.Dispatch(ctx, tenants); err != nil {
+		return errors.Wrap(err, "failed to fetch active tenants")
+	}
+
+	var lastErr error
+	failed := 0
+	for _, tenant := range tenants.Result {
+		// Each tenant has its own savepoint, so a SQL error doesn't abort the job transaction for the next tenants
+		err := withSavepoint(ctx, func() error {
+			return backupTenant(ctx, tenant)
+		})
+		if err != nil {
+			failed++
+			lastErr = errors.Wrap(err, "failed to backup tenant '%d'", tenant.ID)
+			log.Error(ctx, lastErr)
+		}
+	}
+
+	// Returning an error rolls back the job transaction, which would also discard the archives of the other tenants
+	if failed > 0 && failed == len(tenants.Result) {
+		return lastErr
+	}
+	return nil
+}
+
+func backupTenant(ctx Context, tenant *entity.Tenant) error {
+	tenantCtx := context.WithValue(ctx.Context, app.TenantCtxKey, tenant)
+
+	archive, err := backup.Store(tenantCtx)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := backup.Prune(tenantCtx, env.Config.Backup.Retention)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf(ctx, "Backup @{Archive} stored for tenant @{TenantID}, @{Deleted} old archives were deleted", dto.Props{
+		"Archive":  archive.Name,
+		"TenantID": tenant.ID,
+		"Deleted":  deleted,
+	})
+	return nil
+}
@@ -0,0 +1,46 @@
+package jobs_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/jobs"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+)
+
+func TestBackupJob_Schedule_IsCorrect(t *testing.T) {
+	RegisterT(t)
+
+	job := &jobs.BackupJobHandler{}
+	Expect(job.Schedule()).Equals("0 0 3 * * *")
+}
+
+func TestBackupJob_FailedTenant_DoesNotStopOthers(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetActiveTenants) error {
+		q.Result = []*entity.Tenant{{ID: 1}, {ID: 2}}
+		return nil
+	})
+
+	// Archives can't be written without a database, so every tenant fails
+	tenantIDs := make([]int, 0)
+	bus.AddHandler(func(ctx context.Context, c *cmd.StoreBlobStream) error {
+		tenantIDs = append(tenantIDs, ctx.Value(app.TenantCtxKey).(*entity.Tenant).ID)
+		_, err := io.ReadAll(c.Reader)
+		return err
+	})
+
+	job := &jobs.BackupJobHandler{}
+	err := job.Run(jobs.Context{
+		Context: context.Background(),
+	})
+	Expect(err).IsNotNil()
+	Expect(tenantIDs).Equals([]int{1, 2})
+}
@@ -0,0 +1,190 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"net/url"
+	"time"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/i18n"
+	"github.com/getfider/fider/app/pkg/log"
+	"github.com/getfider/fider/app/pkg/markdown"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+// EmailDigestJobHandler sends a single email with all pending notifications to users
+// that prefer a digest. Daily digests are sent every morning and weekly digests on Mondays.
+type EmailDigestJobHandler struct {
+}
+
+func (j EmailDigestJobHandler) Schedule() string {
+	return "0 0 8 * * *" // every day at 08:00
+}
+
+// emailDigestBatchSize is how many users have their pending items loaded at once
+const emailDigestBatchSize = 100
+
+func (j EmailDigestJobHandler) Run(ctx Context) error {
+	frequencies := []enum.EmailDigestFrequency{enum.EmailDigestDaily}
+	if time.Now().Weekday() == time.Monday {
+		frequencies = append(frequencies, enum.EmailDigestWeekly)
+	}
+
+	tenants := make(map[int]*entity.Tenant)
+	sent, failed := 0, 0
+	afterTenantID, afterUserID := 0, 0
+
+	for {
+		pending := &query.ListPendingEmailDigestItems{
+			Frequencies:   frequencies,
+			AfterTenantID: afterTenantID,
+			AfterUserID:   afterUserID,
+			UserLimit:     emailDigestBatchSize,
+		}
+		if err := bus.Dispatch(ctx, pending); err != nil {
+			return errors.Wrap(err, "failed to fetch pending email digest items")
+		}
+		if len(pending.Result) == 0 {
+			break
+		}
+
+		// items are ordered by tenant and user, so each run of consecutive items is a single digest
+		for start := 0; start < len(pending.Result); {
+			end := start
+			for end < len(pending.Result) && pending.Result[end].TenantID == pending.Result[start].TenantID && pending.Result[end].UserID == pending.Result[start].UserID {
+				end++
+			}
+
+			items := pending.Result[start:end]
+			start = end
+			afterTenantID, afterUserID = items[0].TenantID, items[0].UserID
+
+			// Items are deleted as soon as their digest is sent, so a failure on another user doesn't send it again
+			err := withSavepoint(ctx, func() error {
+				isSent, err := processEmailDigest(ctx, tenants, items)
+				if isSent {
+					sent++
+				}
+				return err
+			})
+			if err != nil {
+				failed++
+				log.Error(ctx, errors.Wrap(err, "failed to send email digest to user '%d' of tenant '%d'", items[0].UserID, items[0].TenantID))
+			}
+		}
+	}
+
+	log.Debugf(ctx, "@{Sent} email digests were sent, @{Failed} failed", dto.Props{
+		"Sent":   sent,
+		"Failed": failed,
+	})
+
+	return nil
+}
+
+// processEmailDigest sends the digest of given items, which belong to a single user, and deletes them
+// It returns false when the user or tenant can't receive emails, in which case the items are only deleted
+func processEmailDigest(ctx Context, tenants map[int]*entity.Tenant, items []*entity.EmailDigestItem) (bool, error) {
+	ids := make([]int, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+
+	tenant, ok := tenants[items[0].TenantID]
+	if !ok {
+		getTenant := &query.GetTenantByID{TenantID: items[0].TenantID}
+		if err := bus.Dispatch(ctx, getTenant); err != nil {
+			return false, errors.Wrap(err, "failed to get tenant '%d'", items[0].TenantID)
+		}
+		tenant = getTenant.Result
+		tenants[tenant.ID] = tenant
+	}
+
+	if err := bus.Dispatch(ctx, &cmd.DeleteEmailDigestItems{IDs: ids}); err != nil {
+		return false, errors.Wrap(err, "failed to delete email digest items")
+	}
+
+	if !items[0].CanReceiveEmail || tenant.Status != enum.TenantActive {
+		return false, nil
+	}
+
+	if err := sendEmailDigest(ctx, tenant, items); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func sendEmailDigest(ctx Context, tenant *entity.Tenant, items []*entity.EmailDigestItem) error {
+	// Jobs don't have a request, so the links are built from the base URL the items were created with
+	baseURL := items[len(items)-1].BaseURL
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse email digest base url '%s'", baseURL)
+	}
+
+	tenantCtx := context.WithValue(ctx.Context, app.TenantCtxKey, tenant)
+	tenantCtx = context.WithValue(tenantCtx, app.LocaleCtxKey, tenant.Locale)
+	tenantCtx = context.WithValue(tenantCtx, app.RequestCtxKey, web.Request{URL: u})
+
+	posts := make([]dto.Props, 0)
+	for i := 0; i < len(items); {
+		post := items[i]
+		entries := make([]dto.Props, 0)
+		for ; i < len(items) && items[i].PostNumber == post.PostNumber; i++ {
+			entries = append(entries, dto.Props{
+				"title":   template.HTML(emailDigestEntryTitle(tenantCtx, items[i])),
+				"content": markdown.Full(items[i].Content, false),
+			})
+		}
+
+		posts = append(posts, dto.Props{
+			"link":    template.HTML(fmt.Sprintf("<a href='%s/posts/%d/%s'>%s (#%d)</a>", baseURL, post.PostNumber, post.PostSlug, template.HTMLEscapeString(post.PostTitle), post.PostNumber)),
+			"entries": entries,
+		})
+	}
+
+	to := dto.NewRecipient(items[0].UserName, items[0].UserEmail, dto.Props{})
+	bus.Publish(tenantCtx, &cmd.SendMail{
+		From:         dto.Recipient{Name: tenant.Name},
+		To:           []dto.Recipient{to},
+		TemplateName: "digest",
+		Props: dto.Props{
+			"siteName": tenant.Name,
+			"count":    len(items),
+			"posts":    posts,
+			"change":   fmt.Sprintf("<a href='%s/settings'>%s</a>", baseURL, i18n.T(tenantCtx, "email.subscription.change")),
+			"logo":     web.LogoURL(tenantCtx),
+		},
+	})
+
+	return nil
+}
+
+func emailDigestEntryTitle(ctx context.Context, item *entity.EmailDigestItem) string {
+	userName := template.HTMLEscapeString(item.AuthorName)
+	switch item.Event {
+	case enum.NotificationEventMention.UserSettingsKeyName:
+		return i18n.T(ctx, "email.digest.new_mention", i18n.Params{"userName": userName})
+	case enum.NotificationEventChangeStatus.UserSettingsKeyName:
+		// Custom statuses are stored with the name given by the tenant
+		status := item.Status
+		if enum.IsBuiltInPostStatusName(status) {
+			status = i18n.T(ctx, fmt.Sprintf("enum.poststatus.%s", status))
+		}
+		return i18n.T(ctx, "email.digest.change_status", i18n.Params{
+			"userName": userName,
+			"status":   template.HTMLEscapeString(status),
+		})
+	default:
+		return i18n.T(ctx, "email.digest.new_comment", i18n.Params{"userName": userName})
+	}
+}
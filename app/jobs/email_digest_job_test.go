@@ -0,0 +1,156 @@
+package jobs_test
+
+import (
+	"context"
+	"html/template"
+	"testing"
+	"time"
+
+	"github.com/getfider/fider/app/jobs"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/services/email/emailmock"
+)
+
+// pendingAfter returns the items of the users after the cursor of given query, as the storage does
+func pendingAfter(q *query.ListPendingEmailDigestItems, items []*entity.EmailDigestItem) []*entity.EmailDigestItem {
+	result := make([]*entity.EmailDigestItem, 0)
+	for _, item := range items {
+		if item.TenantID > q.AfterTenantID || (item.TenantID == q.AfterTenantID && item.UserID > q.AfterUserID) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func TestEmailDigestJob_Schedule_IsCorrect(t *testing.T) {
+	RegisterT(t)
+
+	job := &jobs.EmailDigestJobHandler{}
+	Expect(job.Schedule()).Equals("0 0 8 * * *")
+}
+
+func TestEmailDigestJob_ShouldSendOneEmailPerUser(t *testing.T) {
+	RegisterT(t)
+	bus.Init(emailmock.Service{})
+
+	var frequencies []enum.EmailDigestFrequency
+	bus.AddHandler(func(ctx context.Context, q *query.ListPendingEmailDigestItems) error {
+		frequencies = q.Frequencies
+		q.Result = pendingAfter(q, []*entity.EmailDigestItem{
+			{ID: 1, TenantID: 1, UserID: 1, UserName: "Jon Snow", UserEmail: "jon.snow@got.com", CanReceiveEmail: true, Event: enum.NotificationEventNewComment.UserSettingsKeyName, PostNumber: 1, PostTitle: "Add support for TypeScript", PostSlug: "add-support-for-typescript", AuthorName: "Arya Stark", Content: "I agree", BaseURL: "http://demo.test.fider.io"},
+			{ID: 2, TenantID: 1, UserID: 1, UserName: "Jon Snow", UserEmail: "jon.snow@got.com", CanReceiveEmail: true, Event: enum.NotificationEventChangeStatus.UserSettingsKeyName, PostNumber: 1, PostTitle: "Add support for TypeScript", PostSlug: "add-support-for-typescript", AuthorName: "Arya Stark", Content: "Planned for next release.", Status: "planned", BaseURL: "http://demo.test.fider.io"},
+			{ID: 3, TenantID: 1, UserID: 1, UserName: "Jon Snow", UserEmail: "jon.snow@got.com", CanReceiveEmail: true, Event: enum.NotificationEventMention.UserSettingsKeyName, PostNumber: 2, PostTitle: "Dark mode", PostSlug: "dark-mode", AuthorName: "Arya Stark", Content: "What do you think @Jon Snow?", BaseURL: "http://demo.test.fider.io"},
+			{ID: 4, TenantID: 1, UserID: 2, UserName: "Arya Stark", UserEmail: "arya.stark@got.com", CanReceiveEmail: false, Event: enum.NotificationEventNewComment.UserSettingsKeyName, PostNumber: 2, PostTitle: "Dark mode", PostSlug: "dark-mode", AuthorName: "Jon Snow", Content: "Yes", BaseURL: "http://demo.test.fider.io"},
+		})
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetTenantByID) error {
+		q.Result = &entity.Tenant{ID: q.TenantID, Name: "Demonstration", Locale: "en", Status: enum.TenantActive}
+		return nil
+	})
+
+	var deleted []int
+	bus.AddHandler(func(ctx context.Context, c *cmd.DeleteEmailDigestItems) error {
+		deleted = append(deleted, c.IDs...)
+		return nil
+	})
+
+	job := &jobs.EmailDigestJobHandler{}
+	err := job.Run(jobs.Context{
+		Context: context.Background(),
+	})
+	Expect(err).IsNil()
+
+	if time.Now().Weekday() == time.Monday {
+		Expect(frequencies).Equals([]enum.EmailDigestFrequency{enum.EmailDigestDaily, enum.EmailDigestWeekly})
+	} else {
+		Expect(frequencies).Equals([]enum.EmailDigestFrequency{enum.EmailDigestDaily})
+	}
+
+	Expect(deleted).Equals([]int{1, 2, 3, 4})
+	Expect(emailmock.MessageHistory).HasLen(1)
+	Expect(emailmock.MessageHistory[0].TemplateName).Equals("digest")
+	Expect(emailmock.MessageHistory[0].To).Equals([]dto.Recipient{
+		{Name: "Jon Snow", Address: "jon.snow@got.com", Props: dto.Props{}},
+	})
+
+	props := emailmock.MessageHistory[0].Props
+	Expect(props["count"]).Equals(3)
+	Expect(props["change"]).Equals("<a href='http://demo.test.fider.io/settings'>change your notification preferences</a>")
+
+	posts := props["posts"].([]dto.Props)
+	Expect(posts).HasLen(2)
+	Expect(posts[0]["link"]).Equals(template.HTML("<a href='http://demo.test.fider.io/posts/1/add-support-for-typescript'>Add support for TypeScript (#1)</a>"))
+	Expect(posts[0]["entries"]).Equals([]dto.Props{
+		{"title": template.HTML("<strong>Arya Stark</strong> left a comment:"), "content": template.HTML("<p>I agree</p>")},
+		{"title": template.HTML("<strong>Arya Stark</strong> changed the status to <strong>Planned</strong>:"), "content": template.HTML("<p>Planned for next release.</p>")},
+	})
+	Expect(posts[1]["entries"]).HasLen(1)
+}
+
+func TestEmailDigestJob_NothingPending(t *testing.T) {
+	RegisterT(t)
+	bus.Init(emailmock.Service{})
+
+	bus.AddHandler(func(ctx context.Context, q *query.ListPendingEmailDigestItems) error {
+		return nil
+	})
+
+	var deleted []int
+	bus.AddHandler(func(ctx context.Context, c *cmd.DeleteEmailDigestItems) error {
+		deleted = append(deleted, c.IDs...)
+		return nil
+	})
+
+	job := &jobs.EmailDigestJobHandler{}
+	err := job.Run(jobs.Context{
+		Context: context.Background(),
+	})
+	Expect(err).IsNil()
+	Expect(deleted).HasLen(0)
+	Expect(emailmock.MessageHistory).HasLen(0)
+}
+
+func TestEmailDigestJob_FailureOnOneTenant(t *testing.T) {
+	RegisterT(t)
+	bus.Init(emailmock.Service{})
+
+	bus.AddHandler(func(ctx context.Context, q *query.ListPendingEmailDigestItems) error {
+		q.Result = pendingAfter(q, []*entity.EmailDigestItem{
+			{ID: 1, TenantID: 1, UserID: 1, UserName: "Jon Snow", UserEmail: "jon.snow@got.com", CanReceiveEmail: true, Event: enum.NotificationEventNewComment.UserSettingsKeyName, PostNumber: 1, PostTitle: "Dark mode", PostSlug: "dark-mode", AuthorName: "Arya Stark", Content: "I agree", BaseURL: "http://demo.test.fider.io"},
+			{ID: 2, TenantID: 2, UserID: 5, UserName: "Tony Stark", UserEmail: "tony.stark@avengers.com", CanReceiveEmail: true, Event: enum.NotificationEventNewComment.UserSettingsKeyName, PostNumber: 1, PostTitle: "Iron suit", PostSlug: "iron-suit", AuthorName: "Thor", Content: "Yes", BaseURL: "http://avengers.test.fider.io"},
+			{ID: 3, TenantID: 3, UserID: 7, UserName: "Jon Snow", UserEmail: "jon.snow@german.com", CanReceiveEmail: true, Event: enum.NotificationEventNewComment.UserSettingsKeyName, PostNumber: 1, PostTitle: "Dunkelmodus", PostSlug: "dunkelmodus", AuthorName: "Arya Stark", Content: "Ja", BaseURL: "http://german.test.fider.io"},
+		})
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetTenantByID) error {
+		if q.TenantID == 2 {
+			return errors.New("connection reset")
+		}
+		q.Result = &entity.Tenant{ID: q.TenantID, Name: "Demonstration", Locale: "en", Status: enum.TenantActive}
+		return nil
+	})
+
+	var deleted []int
+	bus.AddHandler(func(ctx context.Context, c *cmd.DeleteEmailDigestItems) error {
+		deleted = append(deleted, c.IDs...)
+		return nil
+	})
+
+	job := &jobs.EmailDigestJobHandler{}
+	err := job.Run(jobs.Context{
+		Context: context.Background(),
+	})
+	Expect(err).IsNil()
+	Expect(deleted).Equals([]int{1, 3})
+	Expect(emailmock.MessageHistory).HasLen(2)
+}
@@ -93,6 +93,16 @@ func (j fiderJob) Run() {
 	}
 }
 
+// withSavepoint runs fn within a savepoint of the job transaction, so that a failure on a single tenant or user
+// only undoes its own changes and the job can carry on with the others
+func withSavepoint(ctx Context, fn func() error) error {
+	trx, ok := ctx.Value(app.TransactionCtxKey).(*dbx.Trx)
+	if !ok {
+		return fn()
+	}
+	return trx.Savepoint("job_item", fn)
+}
+
 func newJobContext() (Context, *dbx.Trx, error) {
 	ctx := context.Background()
 	ctx = log.WithProperties(ctx, dto.Props{
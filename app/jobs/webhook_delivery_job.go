@@ -0,0 +1,74 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/log"
+)
+
+// WebhookDeliveryJobHandler retries failed webhook deliveries once their backoff has elapsed
+// and purges the delivery history older than 30 days.
+type WebhookDeliveryJobHandler struct {
+}
+
+const webhookDeliveriesPerRun = 50
+
+func (j WebhookDeliveryJobHandler) Schedule() string {
+	return "30 * * * * *" // every minute at second 30
+}
+
+func (j WebhookDeliveryJobHandler) Run(ctx Context) error {
+	pending := &query.ListPendingWebhookDeliveries{Limit: webhookDeliveriesPerRun}
+	if err := bus.Dispatch(ctx, pending); err != nil {
+		return errors.Wrap(err, "failed to fetch pending webhook deliveries")
+	}
+
+	// A failed delivery is logged and skipped, so that it doesn't prevent the others from being retried nor the purge
+	tenants := make(map[int]*entity.Tenant)
+	for _, delivery := range pending.Result {
+		err := withSavepoint(ctx, func() error {
+			return retryWebhookDelivery(ctx, tenants, delivery)
+		})
+		if err != nil {
+			log.Error(ctx, err)
+		}
+	}
+
+	purge := &cmd.PurgeExpiredWebhookDeliveries{Before: time.Now().AddDate(0, 0, -30)}
+	if err := bus.Dispatch(ctx, purge); err != nil {
+		return errors.Wrap(err, "failed to purge expired webhook deliveries")
+	}
+
+	log.Debugf(ctx, "@{Retried} webhook deliveries were retried and @{RowsDeleted} were deleted", dto.Props{
+		"Retried":     len(pending.Result),
+		"RowsDeleted": purge.NumOfDeletedDeliveries,
+	})
+
+	return nil
+}
+
+func retryWebhookDelivery(ctx Context, tenants map[int]*entity.Tenant, delivery *entity.WebhookDelivery) error {
+	tenant, ok := tenants[delivery.TenantID]
+	if !ok {
+		getTenant := &query.GetTenantByID{TenantID: delivery.TenantID}
+		if err := bus.Dispatch(ctx, getTenant); err != nil {
+			return errors.Wrap(err, "failed to get tenant of webhook delivery '%d'", delivery.ID)
+		}
+		tenant = getTenant.Result
+		tenants[delivery.TenantID] = tenant
+	}
+
+	tenantCtx := context.WithValue(ctx.Context, app.TenantCtxKey, tenant)
+	if err := bus.Dispatch(tenantCtx, &cmd.RetryWebhookDelivery{Delivery: delivery}); err != nil {
+		return errors.Wrap(err, "failed to retry webhook delivery '%d'", delivery.ID)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package jobs_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/jobs"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+)
+
+func TestWebhookDeliveryJob_Schedule_IsCorrect(t *testing.T) {
+	RegisterT(t)
+
+	job := &jobs.WebhookDeliveryJobHandler{}
+	Expect(job.Schedule()).Equals("30 * * * * *")
+}
+
+func TestWebhookDeliveryJob_ShouldRetryPendingDeliveriesWithinTenant(t *testing.T) {
+	RegisterT(t)
+	bus.Init()
+
+	bus.AddHandler(func(ctx context.Context, q *query.ListPendingWebhookDeliveries) error {
+		q.Result = []*entity.WebhookDelivery{
+			{ID: 1, TenantID: 10, WebhookID: 1},
+			{ID: 2, TenantID: 20, WebhookID: 2},
+			{ID: 3, TenantID: 10, WebhookID: 3},
+		}
+		return nil
+	})
+
+	tenantQueries := 0
+	bus.AddHandler(func(ctx context.Context, q *query.GetTenantByID) error {
+		tenantQueries++
+		q.Result = &entity.Tenant{ID: q.TenantID}
+		return nil
+	})
+
+	retried := make(map[int]int)
+	bus.AddHandler(func(ctx context.Context, c *cmd.RetryWebhookDelivery) error {
+		tenant := ctx.Value(app.TenantCtxKey).(*entity.Tenant)
+		retried[c.Delivery.ID] = tenant.ID
+		return nil
+	})
+
+	purged := false
+	bus.AddHandler(func(ctx context.Context, c *cmd.PurgeExpiredWebhookDeliveries) error {
+		purged = true
+		return nil
+	})
+
+	job := &jobs.WebhookDeliveryJobHandler{}
+	err := job.Run(jobs.Context{
+		Context: context.Background(),
+	})
+	Expect(err).IsNil()
+	Expect(tenantQueries).Equals(2)
+	Expect(retried).Equals(map[int]int{1: 10, 2: 20, 3: 10})
+	Expect(purged).IsTrue()
+}
+
+func TestWebhookDeliveryJob_ShouldContinueWhenOneDeliveryFails(t *testing.T) {
+	RegisterT(t)
+	bus.Init()
+
+	bus.AddHandler(func(ctx context.Context, q *query.ListPendingWebhookDeliveries) error {
+		q.Result = []*entity.WebhookDelivery{
+			{ID: 1, TenantID: 10, WebhookID: 1},
+			{ID: 2, TenantID: 10, WebhookID: 2},
+		}
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetTenantByID) error {
+		q.Result = &entity.Tenant{ID: q.TenantID}
+		return nil
+	})
+
+	retried := make([]int, 0)
+	bus.AddHandler(func(ctx context.Context, c *cmd.RetryWebhookDelivery) error {
+		if c.Delivery.ID == 1 {
+			return errors.New("connection refused")
+		}
+		retried = append(retried, c.Delivery.ID)
+		return nil
+	})
+
+	purged := false
+	bus.AddHandler(func(ctx context.Context, c *cmd.PurgeExpiredWebhookDeliveries) error {
+		purged = true
+		return nil
+	})
+
+	job := &jobs.WebhookDeliveryJobHandler{}
+	err := job.Run(jobs.Context{
+		Context: context.Background(),
+	})
+	Expect(err).IsNil()
+	Expect(retried).Equals([]int{2})
+	Expect(purged).IsTrue()
+}
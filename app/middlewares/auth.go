@@ -31,3 +31,16 @@ func IsAuthorized(roles ...enum.Role) web.MiddlewareFunc {
 		}
 	}
 }
+
+// RequireAPIScope blocks requests authenticated by a personal access token without given scope.
+// Requests authenticated by cookies or legacy API keys are not limited by scopes
+func RequireAPIScope(scope enum.APIScope) web.MiddlewareFunc {
+	return func(next web.HandlerFunc) web.HandlerFunc {
+		return func(c *web.Context) error {
+			if token := c.APIToken(); token != nil && !token.HasScope(scope) {
+				return c.Forbidden()
+			}
+			return next(c)
+		}
+	}
+}
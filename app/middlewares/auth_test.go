@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/getfider/fider/app/middlewares"
+	"github.com/getfider/fider/app/models/entity"
 	"github.com/getfider/fider/app/models/enum"
 	. "github.com/getfider/fider/app/pkg/assert"
 	"github.com/getfider/fider/app/pkg/mock"
@@ -59,3 +60,38 @@ func TestIsAuthenticated_WithoutUser(t *testing.T) {
 
 	Expect(status).Equals(http.StatusUnauthorized)
 }
+
+func TestRequireAPIScope_WithoutAPIToken(t *testing.T) {
+	RegisterT(t)
+
+	server := mock.NewServer()
+	server.Use(middlewares.RequireAPIScope(enum.APIScopeAdmin))
+	status, _ := server.AsUser(mock.JonSnow).Execute(func(c *web.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	Expect(status).Equals(http.StatusOK)
+}
+
+func TestRequireAPIScope_WithAPIToken(t *testing.T) {
+	RegisterT(t)
+
+	for scope, expected := range map[enum.APIScope]int{
+		enum.APIScopeWriteComments: http.StatusOK,
+		enum.APIScopeManageTags:    http.StatusForbidden,
+	} {
+		server := mock.NewServer()
+		server.Use(func(next web.HandlerFunc) web.HandlerFunc {
+			return func(c *web.Context) error {
+				c.SetAPIToken(&entity.APIToken{ID: 1, Scopes: []enum.APIScope{enum.APIScopeReadPosts, enum.APIScopeWriteComments}})
+				return next(c)
+			}
+		})
+		server.Use(middlewares.RequireAPIScope(scope))
+		status, _ := server.AsUser(mock.JonSnow).Execute(func(c *web.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+
+		Expect(status).Equals(expected)
+	}
+}
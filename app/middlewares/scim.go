@@ -0,0 +1,40 @@
+package middlewares
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/scim"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+// SCIM blocks requests without the SCIM bearer token of current tenant
+func SCIM() web.MiddlewareFunc {
+	return func(next web.HandlerFunc) web.HandlerFunc {
+		return func(c *web.Context) error {
+			if c.Tenant() == nil {
+				return scim.WriteError(c, scim.ErrNotFound)
+			}
+
+			token := ""
+			authHeader := c.Request.GetHeader("Authorization")
+			if strings.HasPrefix(authHeader, "Bearer ") {
+				token = strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer "))
+			}
+
+			verifyToken := &query.VerifySCIMToken{Token: token}
+			if err := bus.Dispatch(c, verifyToken); err != nil {
+				return c.Failure(err)
+			}
+
+			if !verifyToken.Result {
+				c.Response.Header().Set("WWW-Authenticate", `Bearer realm="SCIM"`)
+				return scim.WriteError(c, scim.NewError(http.StatusUnauthorized, "", "Bearer token is invalid"))
+			}
+
+			return next(c)
+		}
+	}
+}
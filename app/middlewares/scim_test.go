@@ -0,0 +1,56 @@
+package middlewares_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/getfider/fider/app/middlewares"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/mock"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+func TestSCIM_ValidToken(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.VerifySCIMToken) error {
+		q.Result = q.Token == "my-token"
+		return nil
+	})
+
+	server := mock.NewServer()
+	server.Use(middlewares.SCIM())
+	status, _ := server.
+		OnTenant(mock.DemoTenant).
+		AddHeader("Authorization", "Bearer my-token").
+		Execute(func(c *web.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+
+	Expect(status).Equals(http.StatusOK)
+}
+
+func TestSCIM_InvalidToken(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.VerifySCIMToken) error {
+		q.Result = q.Token == "my-token"
+		return nil
+	})
+
+	server := mock.NewServer()
+	server.Use(middlewares.SCIM())
+	status, response := server.
+		OnTenant(mock.DemoTenant).
+		AddHeader("Authorization", "Bearer other-token").
+		Execute(func(c *web.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+
+	Expect(status).Equals(http.StatusUnauthorized)
+	Expect(response.Header().Get("WWW-Authenticate")).Equals(`Bearer realm="SCIM"`)
+	Expect(response.Header().Get("Content-Type")).Equals("application/scim+json")
+}
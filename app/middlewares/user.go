@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/getfider/fider/app/models/cmd"
 	"github.com/getfider/fider/app/models/entity"
 	"github.com/getfider/fider/app/models/enum"
 	"github.com/getfider/fider/app/models/query"
@@ -25,8 +26,9 @@ func User() web.MiddlewareFunc {
 	return func(next web.HandlerFunc) web.HandlerFunc {
 		return func(c *web.Context) error {
 			var (
-				token string
-				user  *entity.User
+				token    string
+				user     *entity.User
+				apiToken *entity.APIToken
 			)
 
 			cookie, err := c.Request.Cookie(web.CookieAuthName)
@@ -81,15 +83,28 @@ func User() web.MiddlewareFunc {
 				parts := strings.Split(authHeader, "Bearer")
 				if len(parts) == 2 {
 					apiKey := strings.TrimSpace(parts[1])
-					getUserByAPIKey := &query.GetUserByAPIKey{APIKey: apiKey}
-					err = bus.Dispatch(c, getUserByAPIKey)
-					if err != nil {
-						if errors.Cause(err) == app.ErrNotFound {
-							return c.HandleValidation(validate.Failed("API Key is invalid"))
+					if strings.HasPrefix(apiKey, entity.APITokenPrefix) {
+						getUserByAPIToken := &query.GetUserByAPIToken{Token: apiKey}
+						err = bus.Dispatch(c, getUserByAPIToken)
+						if err != nil {
+							if errors.Cause(err) == app.ErrNotFound {
+								return c.HandleValidation(validate.Failed("API Token is invalid or has expired"))
+							}
+							return err
 						}
-						return err
+						user = getUserByAPIToken.Result
+						apiToken = getUserByAPIToken.APIToken
+					} else {
+						getUserByAPIKey := &query.GetUserByAPIKey{APIKey: apiKey}
+						err = bus.Dispatch(c, getUserByAPIKey)
+						if err != nil {
+							if errors.Cause(err) == app.ErrNotFound {
+								return c.HandleValidation(validate.Failed("API Key is invalid"))
+							}
+							return err
+						}
+						user = getUserByAPIKey.Result
 					}
-					user = getUserByAPIKey.Result
 
 					if !user.IsCollaborator() {
 						return c.HandleValidation(validate.Failed("API Key is invalid"))
@@ -99,6 +114,9 @@ func User() web.MiddlewareFunc {
 						if !user.IsAdministrator() {
 							return c.HandleValidation(validate.Failed("Only Administrators are allowed to impersonate another user"))
 						}
+						if apiToken != nil && !apiToken.HasScope(enum.APIScopeAdmin) {
+							return c.HandleValidation(validate.Failed("Only API Tokens with the 'admin' scope are allowed to impersonate another user"))
+						}
 						impersonateUserID, err := strconv.Atoi(impersonateUserIDStr)
 						if err != nil {
 							return c.HandleValidation(validate.Failed(fmt.Sprintf("User not found for given impersonate UserID '%s'", impersonateUserIDStr)))
@@ -124,6 +142,13 @@ func User() web.MiddlewareFunc {
 				}
 
 				c.SetUser(user)
+
+				if apiToken != nil {
+					c.SetAPIToken(apiToken)
+					if err := bus.Dispatch(c, &cmd.MarkAPITokenAsUsed{TokenID: apiToken.ID}); err != nil {
+						return err
+					}
+				}
 			}
 
 			return next(c)
@@ -10,6 +10,7 @@ import (
 	"github.com/getfider/fider/app"
 
 	"github.com/getfider/fider/app/middlewares"
+	"github.com/getfider/fider/app/models/cmd"
 	"github.com/getfider/fider/app/models/entity"
 	"github.com/getfider/fider/app/models/enum"
 	"github.com/getfider/fider/app/models/query"
@@ -427,6 +428,90 @@ func TestUser_Impersonation_ValidUser(t *testing.T) {
 	Expect(response.Body.String()).Equals("Arya Stark")
 }
 
+func mockAPITokens(tokens map[string]*entity.APIToken) *[]int {
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByAPIToken) error {
+		if token, ok := tokens[q.Token]; ok {
+			q.Result = mock.JonSnow
+			q.APIToken = token
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	used := make([]int, 0)
+	bus.AddHandler(func(ctx context.Context, c *cmd.MarkAPITokenAsUsed) error {
+		used = append(used, c.TokenID)
+		return nil
+	})
+	return &used
+}
+
+func TestUser_ValidAPIToken(t *testing.T) {
+	RegisterT(t)
+
+	used := mockAPITokens(map[string]*entity.APIToken{
+		"fdr_1234567890": {ID: 7, Name: "Zapier", Scopes: []enum.APIScope{enum.APIScopeReadPosts}},
+	})
+
+	server := mock.NewServer()
+
+	server.Use(middlewares.User())
+	status, response := server.
+		OnTenant(mock.DemoTenant).
+		WithURL("http://example.com/api/v1").
+		AddHeader("Authorization", "Bearer fdr_1234567890").
+		Execute(func(c *web.Context) error {
+			return c.String(http.StatusOK, c.User().Name+" via "+c.APIToken().Name)
+		})
+
+	Expect(status).Equals(http.StatusOK)
+	Expect(response.Body.String()).Equals("Jon Snow via Zapier")
+	Expect(*used).Equals([]int{7})
+}
+
+func TestUser_InvalidAPIToken(t *testing.T) {
+	RegisterT(t)
+
+	mockAPITokens(map[string]*entity.APIToken{})
+
+	server := mock.NewServer()
+
+	server.Use(middlewares.User())
+	status, query := server.
+		OnTenant(mock.DemoTenant).
+		WithURL("http://example.com/api/v1").
+		AddHeader("Authorization", "Bearer fdr_expired").
+		ExecuteAsJSON(func(c *web.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+
+	Expect(status).Equals(http.StatusBadRequest)
+	Expect(query.String("errors[0].message")).Equals("API Token is invalid or has expired")
+}
+
+func TestUser_Impersonation_APITokenWithoutAdminScope(t *testing.T) {
+	RegisterT(t)
+
+	mockAPITokens(map[string]*entity.APIToken{
+		"fdr_1234567890": {ID: 7, Scopes: []enum.APIScope{enum.APIScopeWritePosts}},
+	})
+
+	server := mock.NewServer()
+
+	server.Use(middlewares.User())
+	status, query := server.
+		OnTenant(mock.DemoTenant).
+		WithURL("http://example.com/api/v1").
+		AddHeader("Authorization", "Bearer fdr_1234567890").
+		AddHeader("X-Fider-UserID", strconv.Itoa(mock.AryaStark.ID)).
+		ExecuteAsJSON(func(c *web.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+
+	Expect(status).Equals(http.StatusBadRequest)
+	Expect(query.String("errors[0].message")).Equals("Only API Tokens with the 'admin' scope are allowed to impersonate another user")
+}
+
 // TestUser_SecurityStamp_Match verifies that a token whose security stamp
 // matches the DB value grants access normally.
 func TestUser_SecurityStamp_Match(t *testing.T) {
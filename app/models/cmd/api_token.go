@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+)
+
+// CreateAPIToken creates a personal access token for current user
+type CreateAPIToken struct {
+	Name      string
+	Scopes    []enum.APIScope
+	ExpiresAt *time.Time
+
+	Result *entity.APIToken
+	// Token is the secret value, only available when the token is created
+	Token string
+}
+
+// RevokeAPIToken deletes a personal access token of current user
+type RevokeAPIToken struct {
+	TokenID int
+}
+
+// MarkAPITokenAsUsed sets when a personal access token was last used
+type MarkAPITokenAsUsed struct {
+	TokenID int
+}
@@ -1,11 +1,20 @@
 package cmd
 
+import "io"
+
 type StoreBlob struct {
 	Key         string
 	Content     []byte
 	ContentType string
 }
 
+// StoreBlobStream stores the content read from Reader, so that large files are not held in memory
+type StoreBlobStream struct {
+	Key         string
+	Reader      io.Reader
+	ContentType string
+}
+
 type DeleteBlob struct {
 	Key string
 }
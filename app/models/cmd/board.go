@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"github.com/getfider/fider/app/models/entity"
+)
+
+type AddNewBoard struct {
+	Name                string
+	Description         string
+	DescriptionTemplate string
+	IsPublic            bool
+	HasRoadmap          bool
+	DefaultTags         []*entity.Tag
+
+	Result *entity.Board
+}
+
+type UpdateBoard struct {
+	BoardID             int
+	Name                string
+	Description         string
+	DescriptionTemplate string
+	IsPublic            bool
+	HasRoadmap          bool
+	DefaultTags         []*entity.Tag
+
+	Result *entity.Board
+}
+
+// DeleteBoard deletes a board, its posts are kept without a board
+type DeleteBoard struct {
+	Board *entity.Board
+}
+
+// SetPostBoard moves a post to another board, or out of any board when Board is nil
+type SetPostBoard struct {
+	Post  *entity.Post
+	Board *entity.Board
+}
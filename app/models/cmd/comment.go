@@ -5,8 +5,10 @@ import (
 )
 
 type AddNewComment struct {
-	Post    *entity.Post
-	Content string
+	Post       *entity.Post
+	Content    string
+	IsInternal bool
+	ParentID   int // zero on top-level comments
 
 	Result *entity.Comment
 }
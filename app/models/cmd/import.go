@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+)
+
+// ImportPost adds a post created on another feedback tool, keeping its original date and status
+type ImportPost struct {
+	Title       string
+	Description string
+	User        *entity.User
+	CreatedAt   time.Time
+	Status      enum.PostStatus
+	Response    string
+	RespondedAt time.Time
+
+	Result *entity.Post
+}
+
+// ImportVote adds a vote placed on another feedback tool, keeping its original date
+type ImportVote struct {
+	Post      *entity.Post
+	User      *entity.User
+	CreatedAt time.Time
+}
+
+// ImportComment adds a comment written on another feedback tool, keeping its original date
+type ImportComment struct {
+	Post      *entity.Post
+	User      *entity.User
+	Content   string
+	CreatedAt time.Time
+}
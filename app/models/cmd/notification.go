@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
 )
 
 type MarkAllNotificationsAsRead struct{}
@@ -39,3 +40,19 @@ type SupressEmail struct {
 	//Output
 	NumOfSupressedEmailAddresses int
 }
+
+// AddEmailDigestItem stores a notification to be sent later on the user's email digest
+type AddEmailDigestItem struct {
+	UserID     int
+	Frequency  enum.EmailDigestFrequency
+	Event      enum.NotificationEvent
+	Post       *entity.Post
+	AuthorName string
+	Content    string
+	BaseURL    string
+}
+
+// DeleteEmailDigestItems removes digest items that have already been sent
+type DeleteEmailDigestItems struct {
+	IDs []int
+}
@@ -17,6 +17,8 @@ type SaveCustomOAuthConfig struct {
 	Scope             string
 	ProfileURL        string
 	IsTrusted         bool
+	IsOIDC            bool
+	IssuerURL         string
 	JSONUserIDPath    string
 	JSONUserNamePath  string
 	JSONUserEmailPath string
@@ -35,3 +37,9 @@ type SetTenantProviderStatus struct {
 	Provider  string
 	IsEnabled bool
 }
+
+// ResetOAuthConfig disables every custom OAuth provider of current tenant,
+// restores the default status of built-in providers and allows sign in by email again
+type ResetOAuthConfig struct {
+	Result int
+}
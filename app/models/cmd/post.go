@@ -8,6 +8,7 @@ import (
 type AddNewPost struct {
 	Title       string
 	Description string
+	Board       *entity.Board // the default tags of the board are assigned to the post
 
 	Result *entity.Post
 }
@@ -25,3 +26,32 @@ type SetPostResponse struct {
 	Text   string
 	Status enum.PostStatus
 }
+
+// MergePosts moves votes, subscribers, tags and optionally comments of Post onto Original
+// and marks Post as a duplicate of it
+type MergePosts struct {
+	Post          *entity.Post
+	Original      *entity.Post
+	MergeComments bool
+
+	Result      *entity.Post
+	MovedVoters []*entity.User
+}
+
+// RegeneratePostsSearch detects the language of every post of current tenant again
+// and regenerates the search column and similarity index derived from them
+type RegeneratePostsSearch struct {
+	Result int
+}
+
+// RebuildPostSignatures regenerates the similarity index of every post of current tenant
+type RebuildPostSignatures struct {
+	Result int
+}
+
+// BackfillPostSignatures builds the similarity index of up to Limit posts of any tenant that are missing from it
+type BackfillPostSignatures struct {
+	Limit int
+
+	Result int
+}
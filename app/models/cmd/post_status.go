@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"github.com/getfider/fider/app/models/entity"
+)
+
+type AddCustomPostStatus struct {
+	Name          string
+	Color         string
+	AcceptsVotes  bool
+	RoadmapColumn string
+
+	Result *entity.CustomPostStatus
+}
+
+type UpdateCustomPostStatus struct {
+	Status        *entity.CustomPostStatus
+	Name          string
+	Color         string
+	AcceptsVotes  bool
+	RoadmapColumn string
+
+	Result *entity.CustomPostStatus
+}
+
+type DeleteCustomPostStatus struct {
+	Status *entity.CustomPostStatus
+}
@@ -0,0 +1,14 @@
+package cmd
+
+type SaveSAMLConfig struct {
+	Status         int
+	DisplayName    string
+	IdPEntityID    string
+	IdPSSOURL      string
+	IdPCertificate string
+	NameAttribute  string
+	EmailAttribute string
+	RolesAttribute string
+	AllowedRoles   string
+	IsTrusted      bool
+}
@@ -0,0 +1,6 @@
+package cmd
+
+// RegenerateSCIMToken replaces the bearer token used by identity providers to provision users of current tenant
+type RegenerateSCIMToken struct {
+	Result string
+}
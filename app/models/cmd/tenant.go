@@ -37,6 +37,11 @@ type UpdateTenantSettings struct {
 	Locale              string
 }
 
+type UpdateTenantVotingSettings struct {
+	VoteBudget      int
+	MaxVotesPerPost int
+}
+
 type UpdateTenantAdvancedSettings struct {
 	CustomCSS      string
 	AllowedSchemes string
@@ -46,6 +51,10 @@ type ActivateTenant struct {
 	TenantID int
 }
 
+type DisableTenant struct {
+	TenantID int
+}
+
 type SaveVerificationKey struct {
 	Key      string
 	Code     string
@@ -25,11 +25,21 @@ type RegenerateAPIKey struct {
 type DeleteCurrentUser struct {
 }
 
+// DeleteUser deletes given user the same way users delete their own accounts
+type DeleteUser struct {
+	UserID int
+}
+
 type ChangeUserRole struct {
 	UserID int
 	Role   enum.Role
 }
 
+type ChangeUserName struct {
+	UserID int
+	Name   string
+}
+
 type ChangeUserEmail struct {
 	UserID int
 	Email  string
@@ -39,6 +49,12 @@ type UpdateCurrentUserSettings struct {
 	Settings map[string]string
 }
 
+// SetUserAttributes sets given attributes of a user, attributes with an empty value are removed
+type SetUserAttributes struct {
+	UserID     int
+	Attributes map[string]string
+}
+
 type RegisterUser struct {
 	User *entity.User
 }
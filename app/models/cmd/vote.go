@@ -7,6 +7,8 @@ import (
 type AddVote struct {
 	Post *entity.Post
 	User *entity.User
+	// Weight is the number of votes placed on the post, defaults to 1
+	Weight int
 }
 
 type RemoveVote struct {
@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"time"
+
 	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/entity"
 	"github.com/getfider/fider/app/models/enum"
 	"github.com/getfider/fider/app/pkg/webhook"
 )
@@ -30,3 +33,50 @@ type GetWebhookProps struct {
 
 	Result webhook.Props
 }
+
+type SetWebhookSecret struct {
+	ID     int
+	Secret string
+}
+
+type AddWebhookDelivery struct {
+	WebhookID     int
+	Status        enum.WebhookDeliveryStatus
+	Url           string
+	Content       string
+	StatusCode    int
+	LatencyMs     int
+	Response      string
+	Error         string
+	NextAttemptAt *time.Time
+
+	Result *entity.WebhookDelivery
+}
+
+type UpdateWebhookDelivery struct {
+	ID            int
+	Status        enum.WebhookDeliveryStatus
+	Attempts      int
+	StatusCode    int
+	LatencyMs     int
+	Response      string
+	Error         string
+	NextAttemptAt *time.Time
+}
+
+type PurgeExpiredWebhookDeliveries struct {
+	Before time.Time
+
+	NumOfDeletedDeliveries int
+}
+
+type RetryWebhookDelivery struct {
+	Delivery *entity.WebhookDelivery
+}
+
+type RedeliverWebhook struct {
+	ID         int
+	DeliveryID int
+
+	Result *dto.WebhookTriggerResult
+}
@@ -12,6 +12,8 @@ type WebhookTriggerResult struct {
 	Url        string          `json:"url"`
 	Content    string          `json:"content"`
 	StatusCode int             `json:"status_code"`
+	LatencyMs  int             `json:"latency_ms"`
+	Response   string          `json:"response"`
 	Message    string          `json:"message"`
 	Error      string          `json:"error"`
 }
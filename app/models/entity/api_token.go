@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/getfider/fider/app/models/enum"
+)
+
+// APITokenPrefix identifies personal access tokens, so they can be told apart from legacy API keys
+const APITokenPrefix = "fdr_"
+
+// APIToken is a named personal access token, limited to a set of scopes
+type APIToken struct {
+	ID         int             `json:"id"`
+	Name       string          `json:"name"`
+	Scopes     []enum.APIScope `json:"scopes"`
+	ExpiresAt  *time.Time      `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time      `json:"lastUsedAt,omitempty"`
+	CreatedAt  time.Time       `json:"createdAt"`
+}
+
+// HasScope returns true if the token has been granted given scope, admin tokens have every scope
+func (t *APIToken) HasScope(scope enum.APIScope) bool {
+	for _, s := range t.Scopes {
+		if s == scope || s == enum.APIScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
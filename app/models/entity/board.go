@@ -0,0 +1,22 @@
+package entity
+
+// Board is a separate list of posts of a tenant, e.g. for a product line
+type Board struct {
+	ID                  int      `json:"id"`
+	Name                string   `json:"name"`
+	Slug                string   `json:"slug"`
+	Description         string   `json:"description"`
+	DescriptionTemplate string   `json:"descriptionTemplate"`
+	IsPublic            bool     `json:"isPublic"`
+	HasRoadmap          bool     `json:"hasRoadmap"`
+	DefaultTags         []string `json:"defaultTags"`
+	PostsCount          int      `json:"postsCount"`
+}
+
+// PostBoard is the board a post belongs to
+type PostBoard struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`
+	IsPublic bool   `json:"isPublic"`
+}
@@ -13,6 +13,7 @@ type ReactionCounts struct {
 // Comment represents an user comment on an post
 type Comment struct {
 	ID             int              `json:"id"`
+	PostID         int              `json:"-"`
 	Content        string           `json:"content"`
 	CreatedAt      time.Time        `json:"createdAt"`
 	User           *User            `json:"user"`
@@ -21,4 +22,10 @@ type Comment struct {
 	EditedBy       *User            `json:"editedBy,omitempty"`
 	ReactionCounts []ReactionCounts `json:"reactionCounts,omitempty"`
 	IsApproved     bool             `json:"isApproved"`
+	// IsInternal is true for staff-only notes, which are never shown to visitors
+	IsInternal bool `json:"isInternal"`
+	// ParentID is the ID of the comment this comment replies to, nil on top-level comments
+	ParentID *int `json:"parentId,omitempty"`
+	// Replies are only set when comments are retrieved as threads
+	Replies []*Comment `json:"replies,omitempty"`
 }
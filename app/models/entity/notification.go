@@ -19,3 +19,24 @@ type Notification struct {
 	AvatarType    enum.AvatarType `json:"-" db:"avatar_type"`
 	AvatarURL     string          `json:"avatarURL,omitempty"`
 }
+
+// EmailDigestItem is a notification waiting to be sent on a user's daily or weekly email digest
+// CanReceiveEmail is false when the user has been blocked or their email address is supressed
+type EmailDigestItem struct {
+	ID              int                       `db:"id"`
+	TenantID        int                       `db:"tenant_id"`
+	UserID          int                       `db:"user_id"`
+	UserName        string                    `db:"user_name"`
+	UserEmail       string                    `db:"user_email"`
+	CanReceiveEmail bool                      `db:"can_receive_email"`
+	Frequency       enum.EmailDigestFrequency `db:"frequency"`
+	Event           string                    `db:"event"`
+	PostNumber      int                       `db:"post_number"`
+	PostTitle       string                    `db:"post_title"`
+	PostSlug        string                    `db:"post_slug"`
+	AuthorName      string                    `db:"author_name"`
+	Content         string                    `db:"content"`
+	Status          string                    `db:"status"`
+	BaseURL         string                    `db:"base_url"`
+	CreatedAt       time.Time                 `db:"created_at"`
+}
@@ -24,6 +24,8 @@ type OAuthConfig struct {
 	ProfileURL        string
 	Scope             string
 	IsTrusted         bool
+	IsOIDC            bool
+	IssuerURL         string
 	JSONUserIDPath    string
 	JSONUserNamePath  string
 	JSONUserEmailPath string
@@ -50,6 +52,8 @@ func (o OAuthConfig) MarshalJSON() ([]byte, error) {
 		"profileURL":        o.ProfileURL,
 		"scope":             o.Scope,
 		"isTrusted":         o.IsTrusted,
+		"isOIDC":            o.IsOIDC,
+		"issuerURL":         o.IssuerURL,
 		"jsonUserIDPath":    o.JSONUserIDPath,
 		"jsonUserNamePath":  o.JSONUserNamePath,
 		"jsonUserEmailPath": o.JSONUserEmailPath,
@@ -17,23 +17,50 @@ type Post struct {
 	CreatedAt     time.Time       `json:"createdAt"`
 	User          *User           `json:"user"`
 	HasVoted      bool            `json:"hasVoted"`
+	VoteWeight    int             `json:"voteWeight"`
 	VotesCount    int             `json:"votesCount"`
 	CommentsCount int             `json:"commentsCount"`
 	Status        enum.PostStatus `json:"status"`
 	Response      *PostResponse   `json:"response,omitempty"`
 	Tags          []string        `json:"tags"`
 	IsApproved    bool            `json:"isApproved"`
+	// AttributeTotal is the total of an user attribute among voters, only set when searching by attribute
+	AttributeTotal *float64 `json:"attributeTotal,omitempty"`
+	// CustomStatus is set when the post has a status defined by the tenant
+	CustomStatus *CustomPostStatus `json:"customStatus,omitempty"`
+	// Board is set when the post belongs to a board
+	Board *PostBoard `json:"board,omitempty"`
+	// Snippet is an HTML excerpt with the matching terms highlighted, only set when searching
+	Snippet string `json:"snippet,omitempty"`
 }
 
 // CanBeVoted returns true if this post can have its vote changed
 func (i *Post) CanBeVoted() bool {
+	if i.CustomStatus != nil {
+		return i.CustomStatus.AcceptsVotes
+	}
 	return i.Status != enum.PostCompleted && i.Status != enum.PostDeclined && i.Status != enum.PostDuplicate
 }
 
+// StatusName returns the name of a custom status, or the name of the built-in status otherwise
+func (i *Post) StatusName() string {
+	if i.CustomStatus != nil {
+		return i.CustomStatus.Name
+	}
+	return i.Status.Name()
+}
+
 func (i *Post) Url(baseURL string) string {
 	return fmt.Sprintf("%s/posts/%d/%s", baseURL, i.Number, i.Slug)
 }
 
+// PossibleDuplicate is a pair of posts that are likely to be about the same subject
+type PossibleDuplicate struct {
+	Post       *Post   `json:"post"`
+	Duplicate  *Post   `json:"duplicate"`
+	Similarity float64 `json:"similarity"`
+}
+
 //PostResponse is a staff response to a given post
 type PostResponse struct {
 	Text        string        `json:"text"`
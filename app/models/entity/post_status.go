@@ -0,0 +1,29 @@
+package entity
+
+import "github.com/getfider/fider/app/models/enum"
+
+// Roadmap columns a custom post status can be shown in
+const (
+	RoadmapColumnPlanned   = "planned"
+	RoadmapColumnStarted   = "started"
+	RoadmapColumnCompleted = "completed"
+)
+
+// CustomPostStatus is a post status defined by a tenant in addition to the built-in ones
+type CustomPostStatus struct {
+	Status        enum.PostStatus `json:"status"`
+	Name          string          `json:"name"`
+	Slug          string          `json:"slug"`
+	Color         string          `json:"color"`
+	AcceptsVotes  bool            `json:"acceptsVotes"`
+	RoadmapColumn string          `json:"roadmapColumn"`
+}
+
+// IsValidRoadmapColumn returns true if column is empty or one of the roadmap columns
+func IsValidRoadmapColumn(column string) bool {
+	switch column {
+	case "", RoadmapColumnPlanned, RoadmapColumnStarted, RoadmapColumnCompleted:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// Revision is a version of the content of a post or comment.
+// The first revision is the original content and each edit creates a new one
+type Revision struct {
+	Version int `json:"version"`
+	// Title is only set on revisions of posts
+	Title     string    `json:"title,omitempty"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"createdAt"`
+	CreatedBy *User     `json:"createdBy"`
+}
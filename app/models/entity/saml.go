@@ -0,0 +1,15 @@
+package entity
+
+// SAMLConfig is the configuration of the SAML Identity Provider of a tenant
+type SAMLConfig struct {
+	Status         int    `json:"status"`
+	DisplayName    string `json:"displayName"`
+	IdPEntityID    string `json:"idpEntityID"`
+	IdPSSOURL      string `json:"idpSSOURL"`
+	IdPCertificate string `json:"idpCertificate"`
+	NameAttribute  string `json:"nameAttribute"`
+	EmailAttribute string `json:"emailAttribute"`
+	RolesAttribute string `json:"rolesAttribute"`
+	AllowedRoles   string `json:"allowedRoles"`
+	IsTrusted      bool   `json:"isTrusted"`
+}
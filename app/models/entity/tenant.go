@@ -27,6 +27,8 @@ type Tenant struct {
 	PreventIndexing     bool              `json:"preventIndexing"`
 	IsModerationEnabled bool              `json:"isModerationEnabled"`
 	IsPro               bool              `json:"isPro"`
+	VoteBudget          int               `json:"voteBudget"`
+	MaxVotesPerPost     int               `json:"maxVotesPerPost"`
 	// ScheduledDeletionAt is set when the account owner has requested deletion of the whole
 	// site. The tenant stays active during the grace window; a background job performs the
 	// hard delete once this time passes. Not exposed to clients.
@@ -37,6 +39,19 @@ func (t *Tenant) IsDisabled() bool {
 	return t.Status == enum.TenantDisabled
 }
 
+// IsVoteBudgetEnabled returns true if users have a limited number of votes to spend across posts
+func (t *Tenant) IsVoteBudgetEnabled() bool {
+	return t.VoteBudget > 0
+}
+
+// MaxVoteWeight returns how many votes a user can place on a single post
+func (t *Tenant) MaxVoteWeight() int {
+	if !t.IsVoteBudgetEnabled() || t.MaxVotesPerPost < 1 {
+		return 1
+	}
+	return t.MaxVotesPerPost
+}
+
 // TenantContact is a reference to an administrator account
 type TenantContact struct {
 	Name      string `json:"name"`
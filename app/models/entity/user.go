@@ -2,10 +2,18 @@ package entity
 
 import (
 	"encoding/json"
+	"regexp"
 
 	"github.com/getfider/fider/app/models/enum"
 )
 
+var userAttributeKeyRegex = regexp.MustCompile(`^[a-z0-9_]{1,50}$`)
+
+// IsValidUserAttributeKey returns true if given key can be used as an user attribute
+func IsValidUserAttributeKey(key string) bool {
+	return userAttributeKeyRegex.MatchString(key)
+}
+
 // User represents an user inside our application
 type User struct {
 	ID            int             `json:"id"`
@@ -20,6 +28,8 @@ type User struct {
 	Status        enum.UserStatus `json:"status"`
 	IsTrusted     bool            `json:"isTrusted"`
 	SecurityStamp string          `json:"-"`
+	// Attributes are set by administrators to segment users, e.g. company, plan or MRR
+	Attributes map[string]string `json:"-"`
 }
 
 // HasProvider returns true if current user has registered with given provider
@@ -62,9 +72,11 @@ func (umc UserWithEmail) MarshalJSON() ([]byte, error) {
 	type Alias User // Prevent recursion
 	return json.Marshal(&struct {
 		*Alias
-		Email string `json:"email"`
+		Email      string            `json:"email"`
+		Attributes map[string]string `json:"attributes,omitempty"`
 	}{
-		Alias: (*Alias)(umc.User),
-		Email: umc.Email,
+		Alias:      (*Alias)(umc.User),
+		Email:      umc.Email,
+		Attributes: umc.Attributes,
 	})
 }
@@ -15,5 +15,20 @@ type VoteUser struct {
 //Vote represents a vote given by a user on a post
 type Vote struct {
 	User      *VoteUser `json:"user"`
+	Weight    int       `json:"weight"`
 	CreatedAt time.Time `json:"createdAt"`
 }
+
+// VoteBudget is the number of votes a user can spend across posts when vote budgets are enabled
+type VoteBudget struct {
+	Total int `json:"total"`
+	Spent int `json:"spent"`
+}
+
+// Remaining returns how many votes the user can still spend
+func (b *VoteBudget) Remaining() int {
+	if b.Spent >= b.Total {
+		return 0
+	}
+	return b.Total - b.Spent
+}
@@ -3,6 +3,7 @@ package entity
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"time"
 
 	"github.com/getfider/fider/app/models/enum"
 	"github.com/getfider/fider/app/pkg/errors"
@@ -18,6 +19,7 @@ type Webhook struct {
 	Content     string             `json:"content" db:"content"`
 	HttpMethod  string             `json:"http_method" db:"http_method"`
 	HttpHeaders HttpHeaders        `json:"http_headers" db:"http_headers"`
+	Secret      string             `json:"secret" db:"secret"`
 }
 
 type HttpHeaders map[string]string
@@ -36,3 +38,21 @@ func (h *HttpHeaders) Scan(src any) error {
 	}
 	return json.Unmarshal(headers, &h)
 }
+
+// WebhookDelivery represents a single attempt (and its retries) to deliver a webhook
+type WebhookDelivery struct {
+	ID            int                        `json:"id"`
+	TenantID      int                        `json:"-"`
+	WebhookID     int                        `json:"webhook_id"`
+	Status        enum.WebhookDeliveryStatus `json:"status"`
+	Attempts      int                        `json:"attempts"`
+	Url           string                     `json:"url"`
+	Content       string                     `json:"content"`
+	StatusCode    int                        `json:"status_code"`
+	LatencyMs     int                        `json:"latency_ms"`
+	Response      string                     `json:"response"`
+	Error         string                     `json:"error"`
+	CreatedAt     time.Time                  `json:"created_at"`
+	LastAttemptAt time.Time                  `json:"last_attempt_at"`
+	NextAttemptAt *time.Time                 `json:"next_attempt_at,omitempty"`
+}
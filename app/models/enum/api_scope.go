@@ -0,0 +1,36 @@
+package enum
+
+//APIScope is a permission granted to a personal access token
+type APIScope string
+
+const (
+	//APIScopeReadPosts allows reading posts, comments, votes and tags
+	APIScopeReadPosts APIScope = "posts:read"
+	//APIScopeWritePosts allows creating and updating posts, votes and subscriptions
+	APIScopeWritePosts APIScope = "posts:write"
+	//APIScopeWriteComments allows creating, updating and reacting to comments
+	APIScopeWriteComments APIScope = "comments:write"
+	//APIScopeManageTags allows creating, assigning and deleting tags
+	APIScopeManageTags APIScope = "tags:manage"
+	//APIScopeAdmin allows every operation available to the token owner
+	APIScopeAdmin APIScope = "admin"
+)
+
+//AllAPIScopes is the list of scopes a token can be granted
+var AllAPIScopes = []APIScope{
+	APIScopeReadPosts,
+	APIScopeWritePosts,
+	APIScopeWriteComments,
+	APIScopeManageTags,
+	APIScopeAdmin,
+}
+
+// IsValid returns true if the scope is known
+func (scope APIScope) IsValid() bool {
+	for _, s := range AllAPIScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
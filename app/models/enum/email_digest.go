@@ -0,0 +1,26 @@
+package enum
+
+// EmailDigestFrequency is how often a user wants to receive comment and status change emails
+type EmailDigestFrequency string
+
+const (
+	// EmailDigestImmediate sends one email per event, as soon as it happens
+	EmailDigestImmediate EmailDigestFrequency = "immediate"
+	// EmailDigestDaily groups all events into a single email sent once a day
+	EmailDigestDaily EmailDigestFrequency = "daily"
+	// EmailDigestWeekly groups all events into a single email sent once a week
+	EmailDigestWeekly EmailDigestFrequency = "weekly"
+)
+
+// EmailDigestSettingsKeyName is the user settings key that holds the digest frequency
+const EmailDigestSettingsKeyName = "email_digest"
+
+// IsValid returns true if given frequency is known
+func (f EmailDigestFrequency) IsValid() bool {
+	return f == EmailDigestImmediate || f == EmailDigestDaily || f == EmailDigestWeekly
+}
+
+// IsDigest returns true if events should be grouped instead of sent immediately
+func (f EmailDigestFrequency) IsDigest() bool {
+	return f == EmailDigestDaily || f == EmailDigestWeekly
+}
@@ -1,5 +1,10 @@
 package enum
 
+import (
+	"strconv"
+	"strings"
+)
+
 //PostStatus is the status of a given post
 type PostStatus int
 
@@ -18,7 +23,12 @@ var (
 	PostDuplicate PostStatus = 5
 	//PostDeleted is used when the post is completely removed from the site and should never be shown again
 	PostDeleted PostStatus = 6
+	//PostCustomStatusStart is the first value available to statuses defined by tenants
+	PostCustomStatusStart PostStatus = 100
 )
+
+const customStatusPrefix = "custom_"
+
 var postStatusIDs = map[PostStatus]string{
 	PostOpen:      "open",
 	PostStarted:   "started",
@@ -41,17 +51,42 @@ var postStatusNames = map[string]PostStatus{
 
 // MarshalText returns the Text version of the post status
 func (status PostStatus) MarshalText() ([]byte, error) {
+	if status.IsCustom() {
+		return []byte(status.Name()), nil
+	}
 	return []byte(postStatusIDs[status]), nil
 }
 
 // UnmarshalText parse string into a post status
 func (status *PostStatus) UnmarshalText(text []byte) error {
-	*status = postStatusNames[string(text)]
+	value := string(text)
+	if strings.HasPrefix(value, customStatusPrefix) {
+		number, err := strconv.Atoi(strings.TrimPrefix(value, customStatusPrefix))
+		if err == nil && PostStatus(number).IsCustom() {
+			*status = PostStatus(number)
+			return nil
+		}
+	}
+	*status = postStatusNames[value]
 	return nil
 }
 
+// IsBuiltInPostStatusName returns true if name is the name of a built-in post status
+func IsBuiltInPostStatusName(name string) bool {
+	_, ok := postStatusNames[name]
+	return ok
+}
+
+// IsCustom returns true if the post status is defined by the tenant
+func (status PostStatus) IsCustom() bool {
+	return status >= PostCustomStatusStart
+}
+
 // Name returns the name of a post status
 func (status PostStatus) Name() string {
+	if status.IsCustom() {
+		return customStatusPrefix + strconv.Itoa(int(status))
+	}
 	name, ok := postStatusIDs[status]
 	if ok {
 		return name
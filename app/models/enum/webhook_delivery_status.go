@@ -0,0 +1,45 @@
+package enum
+
+// WebhookDeliveryStatus is the status of a single webhook delivery
+type WebhookDeliveryStatus int
+
+const (
+	// WebhookDeliveryPending means the delivery has failed and is waiting to be retried
+	WebhookDeliveryPending WebhookDeliveryStatus = 1
+	// WebhookDeliverySuccess means the receiver accepted the delivery
+	WebhookDeliverySuccess WebhookDeliveryStatus = 2
+	// WebhookDeliveryFailed means the delivery has failed and will not be retried anymore
+	WebhookDeliveryFailed WebhookDeliveryStatus = 3
+)
+
+var webhookDeliveryStatusIDs = map[WebhookDeliveryStatus]string{
+	WebhookDeliveryPending: "pending",
+	WebhookDeliverySuccess: "success",
+	WebhookDeliveryFailed:  "failed",
+}
+
+var webhookDeliveryStatusName = map[string]WebhookDeliveryStatus{
+	"pending": WebhookDeliveryPending,
+	"success": WebhookDeliverySuccess,
+	"failed":  WebhookDeliveryFailed,
+}
+
+// MarshalText returns the Text version of the webhook delivery status
+func (status WebhookDeliveryStatus) MarshalText() ([]byte, error) {
+	return []byte(webhookDeliveryStatusIDs[status]), nil
+}
+
+// UnmarshalText parse string into a webhook delivery status
+func (status *WebhookDeliveryStatus) UnmarshalText(text []byte) error {
+	*status = webhookDeliveryStatusName[string(text)]
+	return nil
+}
+
+// Name returns the name of a webhook delivery status
+func (status WebhookDeliveryStatus) Name() string {
+	name, ok := webhookDeliveryStatusIDs[status]
+	if ok {
+		return name
+	}
+	return "unknown"
+}
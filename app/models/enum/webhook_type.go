@@ -12,20 +12,52 @@ const (
 	WebhookChangeStatus WebhookType = 3
 	// WebhookDeletePost is triggered on post deletion
 	WebhookDeletePost WebhookType = 4
+	// WebhookAddVote is triggered when a user votes on a post
+	WebhookAddVote WebhookType = 5
+	// WebhookRemoveVote is triggered when a user removes their vote from a post
+	WebhookRemoveVote WebhookType = 6
+	// WebhookEditComment is triggered on comment edition
+	WebhookEditComment WebhookType = 7
+	// WebhookDeleteComment is triggered on comment deletion
+	WebhookDeleteComment WebhookType = 8
+	// WebhookAssignTag is triggered when a tag is assigned to a post
+	WebhookAssignTag WebhookType = 9
+	// WebhookUnassignTag is triggered when a tag is unassigned from a post
+	WebhookUnassignTag WebhookType = 10
+	// WebhookEditPost is triggered on post edition
+	WebhookEditPost WebhookType = 11
+	// WebhookNewUser is triggered when a new user registers
+	WebhookNewUser WebhookType = 12
 )
 
 var webhookTypeIDs = map[WebhookType]string{
-	WebhookNewPost:      "new_post",
-	WebhookNewComment:   "new_comment",
-	WebhookChangeStatus: "change_status",
-	WebhookDeletePost:   "delete_post",
+	WebhookNewPost:       "new_post",
+	WebhookNewComment:    "new_comment",
+	WebhookChangeStatus:  "change_status",
+	WebhookDeletePost:    "delete_post",
+	WebhookAddVote:       "add_vote",
+	WebhookRemoveVote:    "remove_vote",
+	WebhookEditComment:   "edit_comment",
+	WebhookDeleteComment: "delete_comment",
+	WebhookAssignTag:     "assign_tag",
+	WebhookUnassignTag:   "unassign_tag",
+	WebhookEditPost:      "edit_post",
+	WebhookNewUser:       "new_user",
 }
 
 var webhookTypeName = map[string]WebhookType{
-	"new_post":      WebhookNewPost,
-	"new_comment":   WebhookNewComment,
-	"change_status": WebhookChangeStatus,
-	"delete_post":   WebhookDeletePost,
+	"new_post":       WebhookNewPost,
+	"new_comment":    WebhookNewComment,
+	"change_status":  WebhookChangeStatus,
+	"delete_post":    WebhookDeletePost,
+	"add_vote":       WebhookAddVote,
+	"remove_vote":    WebhookRemoveVote,
+	"edit_comment":   WebhookEditComment,
+	"delete_comment": WebhookDeleteComment,
+	"assign_tag":     WebhookAssignTag,
+	"unassign_tag":   WebhookUnassignTag,
+	"edit_post":      WebhookEditPost,
+	"new_user":       WebhookNewUser,
 }
 
 // MarshalText returns the Text version of the webhook type
@@ -47,3 +79,9 @@ func (t WebhookType) Name() string {
 	}
 	return "unknown"
 }
+
+// IsValid returns true if this is a known webhook type
+func (t WebhookType) IsValid() bool {
+	_, ok := webhookTypeIDs[t]
+	return ok
+}
@@ -0,0 +1,16 @@
+package query
+
+import "github.com/getfider/fider/app/models/entity"
+
+// GetUserAPITokens returns the personal access tokens of current user
+type GetUserAPITokens struct {
+	Result []*entity.APIToken
+}
+
+// GetUserByAPIToken returns the owner of a personal access token that is neither revoked nor expired
+type GetUserByAPIToken struct {
+	Token string
+
+	Result   *entity.User
+	APIToken *entity.APIToken
+}
@@ -1,6 +1,10 @@
 package query
 
-import "github.com/getfider/fider/app/models/dto"
+import (
+	"io"
+
+	"github.com/getfider/fider/app/models/dto"
+)
 
 type ListBlobs struct {
 	Prefix string
@@ -13,3 +17,10 @@ type GetBlobByKey struct {
 
 	Result *dto.Blob
 }
+
+// OpenBlobByKey returns a reader of the blob content, which must be closed by the caller
+type OpenBlobByKey struct {
+	Key string
+
+	Result io.ReadCloser
+}
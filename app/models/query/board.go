@@ -0,0 +1,15 @@
+package query
+
+import (
+	"github.com/getfider/fider/app/models/entity"
+)
+
+type GetBoardBySlug struct {
+	Slug string
+
+	Result *entity.Board
+}
+
+type GetAllBoards struct {
+	Result []*entity.Board
+}
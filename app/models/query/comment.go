@@ -11,7 +11,15 @@ type GetCommentByID struct {
 }
 
 type GetCommentsByPost struct {
-	Post *entity.Post
+	Post       *entity.Post
+	Limit      int    // all comments are returned when zero
+	Cursor     string // only comments after the one this cursor points to are returned
+	CountTotal bool
+	// Threaded returns only top-level comments, with their replies nested.
+	// Limit, Cursor and TotalCount then apply to top-level comments only
+	Threaded bool
 
-	Result []*entity.Comment
+	Result     []*entity.Comment
+	NextCursor string // points to the last comment of Result, empty when there are no more comments
+	TotalCount int
 }
@@ -22,6 +22,8 @@ type GetActiveSubscribers struct {
 	Number  int
 	Channel enum.NotificationChannel
 	Event   enum.NotificationEvent
+	// Participants are notified as if they were subscribed to the post, unless they unsubscribed from it
+	Participants []int
 
 	Result []*entity.User
 }
@@ -32,3 +34,22 @@ type GetMentionNotifications struct {
 
 	Result []*entity.MentionNotification
 }
+
+// GetEmailDigestFrequencies returns the digest frequency of given users
+// Users that never changed this setting are not included on the result
+type GetEmailDigestFrequencies struct {
+	UserIDs []int
+
+	Result map[int]enum.EmailDigestFrequency
+}
+
+// ListPendingEmailDigestItems returns digest items of all tenants for given frequencies, ordered by tenant, user and post
+// Items are returned for at most UserLimit users, starting after the user AfterUserID of tenant AfterTenantID
+type ListPendingEmailDigestItems struct {
+	Frequencies   []enum.EmailDigestFrequency
+	AfterTenantID int
+	AfterUserID   int
+	UserLimit     int
+
+	Result []*entity.EmailDigestItem
+}
@@ -28,6 +28,7 @@ type GetOAuthAuthorizationURL struct {
 type GetOAuthProfile struct {
 	Provider string
 	Code     string
+	Nonce    string
 
 	Result *dto.OAuthUserProfile
 }
@@ -35,6 +36,7 @@ type GetOAuthProfile struct {
 type GetOAuthRawProfile struct {
 	Provider string
 	Code     string
+	Nonce    string
 
 	Result string
 }
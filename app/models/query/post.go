@@ -12,6 +12,8 @@ type PostIsReferenced struct {
 }
 
 type CountPostPerStatus struct {
+	Board string // only posts of the board with this slug are counted
+
 	Result map[enum.PostStatus]int
 }
 
@@ -43,8 +45,23 @@ type SearchPosts struct {
 	NoTagsOnly       bool
 	MyPostsOnly      bool
 	ModerationFilter string // "pending", "approved", or empty (all)
+	IncludeComments  bool   // also match comments and staff responses, ranked lower than title and description
+	Board            string // slug of the board posts belong to, empty for posts of all boards
 
-	Result []*entity.Post
+	// ExcludeRoadmapBoards leaves out posts of boards that have their own roadmap
+	ExcludeRoadmapBoards bool
+
+	// Segmentation by voters' attributes, only available to staff members
+	VoterAttributes   map[string]string // posts with at least one voter having these attribute values
+	SortAttribute     string            // sort by the total of this attribute among voters, e.g. mrr
+	MinAttributeTotal float64           // posts with a total of SortAttribute lower than this are filtered out
+
+	Cursor     string // only posts after the one this cursor points to are returned
+	CountTotal bool   // also count all posts matching the criteria, ignoring Limit and Cursor
+
+	Result     []*entity.Post
+	NextCursor string // points to the last post of Result, empty when there are no more posts
+	TotalCount int
 }
 
 type FindSimilarPosts struct {
@@ -53,6 +70,13 @@ type FindSimilarPosts struct {
 	Result []*entity.Post
 }
 
+// GetPossibleDuplicates returns pairs of posts that are likely to be duplicates, most similar first
+type GetPossibleDuplicates struct {
+	MinSimilarity float64 // pairs less similar than this, from 0 to 1, are ignored
+
+	Result []*entity.PossibleDuplicate
+}
+
 type GetAllPosts struct {
 	Result []*entity.Post
 }
@@ -0,0 +1,15 @@
+package query
+
+import (
+	"github.com/getfider/fider/app/models/entity"
+)
+
+type GetCustomPostStatuses struct {
+	Result []*entity.CustomPostStatus
+}
+
+type GetCustomPostStatusBySlug struct {
+	Slug string
+
+	Result *entity.CustomPostStatus
+}
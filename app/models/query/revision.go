@@ -0,0 +1,33 @@
+package query
+
+import "github.com/getfider/fider/app/models/entity"
+
+// ListPostRevisions returns every revision of a post, oldest first.
+// Posts that were never edited have a single revision with their current content
+type ListPostRevisions struct {
+	PostID int
+
+	Result []*entity.Revision
+}
+
+type GetPostRevision struct {
+	PostID  int
+	Version int
+
+	Result *entity.Revision
+}
+
+// ListCommentRevisions returns every revision of a comment, oldest first.
+// Comments that were never edited have a single revision with their current content
+type ListCommentRevisions struct {
+	CommentID int
+
+	Result []*entity.Revision
+}
+
+type GetCommentRevision struct {
+	CommentID int
+	Version   int
+
+	Result *entity.Revision
+}
@@ -0,0 +1,15 @@
+package query
+
+import "time"
+
+// VerifySCIMToken checks if given token is the SCIM bearer token of current tenant
+type VerifySCIMToken struct {
+	Token string
+
+	Result bool
+}
+
+// GetSCIMTokenInfo returns when the SCIM bearer token of current tenant was generated, if any
+type GetSCIMTokenInfo struct {
+	Result *time.Time
+}
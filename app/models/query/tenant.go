@@ -57,6 +57,31 @@ type GetTenantByDomain struct {
 	Result *entity.Tenant
 }
 
+type GetTenantByID struct {
+	TenantID int
+
+	// Output
+	Result *entity.Tenant
+}
+
+// GetActiveTenants returns every active tenant, ordered by id
+type GetActiveTenants struct {
+	// Output
+	Result []*entity.Tenant
+}
+
+// GetAllTenants returns every tenant regardless of its status, ordered by id
+type GetAllTenants struct {
+	// Output
+	Result []*entity.Tenant
+}
+
+// GetPendingVerifications returns the verifications of current tenant that are neither verified nor expired
+type GetPendingVerifications struct {
+	// Output
+	Result []*entity.EmailVerification
+}
+
 type GetPendingSignUpVerification struct {
 	// Output
 	Result *entity.EmailVerification
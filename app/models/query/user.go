@@ -25,6 +25,21 @@ type GetCurrentUserSettings struct {
 	Result map[string]string
 }
 
+// GetUserAttributes returns the attributes of given users, indexed by user id
+type GetUserAttributes struct {
+	UserIDs []int
+
+	Result map[int]map[string]string
+}
+
+// GetUserProviderUIDs returns the UIDs of given users on given provider, indexed by user id
+type GetUserProviderUIDs struct {
+	Provider string
+	UserIDs  []int
+
+	Result map[int]string
+}
+
 type GetUserByID struct {
 	UserID int
 
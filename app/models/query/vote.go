@@ -6,6 +6,15 @@ type ListPostVotes struct {
 	PostID       int
 	Limit        int
 	IncludeEmail bool
+	Cursor       string // only votes after the one this cursor points to are returned
+	CountTotal   bool
 
-	Result []*entity.Vote
+	Result     []*entity.Vote
+	NextCursor string // points to the last vote of Result, empty when there are no more votes
+	TotalCount int
+}
+
+// GetVoteBudget returns the vote budget of current user, votes on closed posts are given back to the budget
+type GetVoteBudget struct {
+	Result *entity.VoteBudget
 }
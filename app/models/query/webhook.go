@@ -47,3 +47,24 @@ type DeleteWebhook struct {
 type MarkWebhookAsFailed struct {
 	ID int
 }
+
+type GetWebhookDelivery struct {
+	WebhookID int
+	ID        int
+
+	Result *entity.WebhookDelivery
+}
+
+type ListWebhookDeliveries struct {
+	WebhookID int
+	Limit     int
+
+	Result []*entity.WebhookDelivery
+}
+
+// ListPendingWebhookDeliveries returns deliveries of all tenants that are due to be retried
+type ListPendingWebhookDeliveries struct {
+	Limit int
+
+	Result []*entity.WebhookDelivery
+}
@@ -2,75 +2,139 @@ package backup
 
 import (
 	"archive/zip"
-	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 
+	"github.com/getfider/fider/app"
 	"github.com/getfider/fider/app/models/query"
 	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/dbx"
 
 	"github.com/getfider/fider/app/pkg/errors"
 )
 
-func Create(ctx context.Context) (*bytes.Buffer, error) {
+const (
+	manifestFileName = "backup.json"
+	// archiveVersion is incremented when the archive layout changes in a way older versions can't restore
+	archiveVersion = 1
+)
+
+// manifest describes the archive, so that it can be validated before being restored
+type manifest struct {
+	Version   int `json:"version"`
+	Migration int `json:"migration"`
+}
+
+// Write streams a zip archive with all the content of current tenant to w, one table or blob at a time
+func Write(ctx context.Context, w io.Writer) error {
+	zipWriter := zip.NewWriter(w)
 
-	buffer := new(bytes.Buffer)
-	zipWriter := zip.NewWriter(buffer)
+	if err := addManifestToZipFile(ctx, zipWriter); err != nil {
+		return err
+	}
 
 	for _, tableName := range []string{
 		"attachments",
+		"board_tags",
+		"boards",
 		"comments",
+		"comment_revisions",
 		"email_verifications",
 		"notifications",
 		"oauth_providers",
 		"posts",
+		"post_revisions",
+		"post_statuses",
 		"post_subscribers",
 		"post_tags",
 		"post_votes",
+		"saml_configs",
 		"tags",
 		"tenants",
 		"user_providers",
 		"users",
 		"user_settings",
+		"user_attributes",
 	} {
 		err := addTableDataToZipFile(ctx, zipWriter, tableName)
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
 
 	listBlobs := &query.ListBlobs{}
 	if err := bus.Dispatch(ctx, listBlobs); err != nil {
-		return nil, err
+		return err
 	}
 
 	for _, bkey := range listBlobs.Result {
+		// Stored archives are not part of the content, otherwise every archive would include the previous ones
+		if IsArchiveKey(bkey) {
+			continue
+		}
+
 		err := addBlobToZipFile(ctx, zipWriter, bkey)
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
 
 	err := zipWriter.Close()
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to close zip file")
+		return errors.Wrap(err, "failed to close zip file")
+	}
+
+	return nil
+}
+
+func addManifestToZipFile(ctx context.Context, zipWriter *zip.Writer) error {
+	trx := ctx.Value(app.TransactionCtxKey).(*dbx.Trx)
+	migration, err := lastMigration(trx)
+	if err != nil {
+		return err
+	}
+
+	content, err := json.Marshal(manifest{Version: archiveVersion, Migration: migration})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal %s", manifestFileName)
 	}
 
-	return buffer, nil
+	fileWriter, err := zipWriter.Create(manifestFileName)
+	if err != nil {
+		return errors.Wrap(err, "failed to create %s in zip file", manifestFileName)
+	}
+	_, err = fileWriter.Write(content)
+	if err != nil {
+		return errors.Wrap(err, "failed to write %s to zip file", manifestFileName)
+	}
+
+	return nil
+}
+
+// lastMigration returns the version of the database schema
+func lastMigration(trx *dbx.Trx) (int, error) {
+	var migration int
+	if err := trx.Scalar(&migration, "SELECT COALESCE(MAX(version), 0) FROM migrations_history"); err != nil {
+		return 0, errors.Wrap(err, "failed to get last migration")
+	}
+	return migration, nil
 }
 
 func addBlobToZipFile(ctx context.Context, zipWriter *zip.Writer, bkey string) error {
-	getBlob := &query.GetBlobByKey{Key: bkey}
-	if err := bus.Dispatch(ctx, getBlob); err != nil {
+	openBlob := &query.OpenBlobByKey{Key: bkey}
+	if err := bus.Dispatch(ctx, openBlob); err != nil {
 		return errors.Wrap(err, "failed to get blob with key %s", bkey)
 	}
+	defer func() { _ = openBlob.Result.Close() }()
 
 	fileName := fmt.Sprintf("blobs/%s", bkey)
 	fileWriter, err := zipWriter.Create(fileName)
 	if err != nil {
 		return errors.Wrap(err, "failed to create %s in zip file", fileName)
 	}
-	_, err = fileWriter.Write(getBlob.Result.Content)
+	_, err = io.Copy(fileWriter, openBlob.Result)
 	if err != nil {
 		return errors.Wrap(err, "failed to write %s to zip file", fileName)
 	}
@@ -79,18 +143,13 @@ func addBlobToZipFile(ctx context.Context, zipWriter *zip.Writer, bkey string) e
 }
 
 func addTableDataToZipFile(ctx context.Context, zipWriter *zip.Writer, tableName string) error {
-	tableData, err := exportTable(ctx, tableName)
-	if err != nil {
-		return errors.Wrap(err, "failed to export %s table", tableName)
-	}
-
 	fileWriter, err := zipWriter.Create(fmt.Sprintf("%s.json", tableName))
 	if err != nil {
 		return errors.Wrap(err, "failed to create %s.json in zip file", tableName)
 	}
-	_, err = fileWriter.Write(tableData)
-	if err != nil {
-		return errors.Wrap(err, "failed to write %s.json to zip file", tableName)
+
+	if err := exportTable(ctx, tableName, fileWriter); err != nil {
+		return errors.Wrap(err, "failed to export %s table", tableName)
 	}
 
 	return nil
@@ -5,14 +5,17 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"io"
 	"strconv"
 
 	"github.com/getfider/fider/app"
 	"github.com/getfider/fider/app/models/entity"
 	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
 )
 
-func exportTable(ctx context.Context, tableName string) ([]byte, error) {
+// exportTable writes the rows of given table as a JSON array, one row at a time
+func exportTable(ctx context.Context, tableName string, w io.Writer) error {
 	trx := ctx.Value(app.TransactionCtxKey).(*dbx.Trx)
 	tenant, _ := ctx.Value(app.TenantCtxKey).(*entity.Tenant)
 	columnName := "tenant_id"
@@ -22,56 +25,78 @@ func exportTable(ctx context.Context, tableName string) ([]byte, error) {
 
 	rows, err := trx.Query(fmt.Sprintf("SELECT * FROM %s WHERE %s = $1", tableName, columnName), tenant.ID)
 	if err != nil {
-		return nil, err
+		return err
 	}
-
-	return json.Marshal(jsonify(rows))
-}
-
-func jsonify(rows *sql.Rows) []map[string]any {
 	defer func() { _ = rows.Close() }()
+
 	columns, err := rows.Columns()
 	if err != nil {
-		panic(err.Error())
+		return errors.Wrap(err, "failed to get columns")
 	}
 
-	allResults := make([]map[string]any, 0)
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
 
-	for rows.Next() {
-		results := make(map[string]any)
-		values := make([]any, len(columns))
-		scanArgs := make([]any, len(values))
-		for i := range values {
-			scanArgs[i] = &values[i]
+	for i := 0; rows.Next(); i++ {
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
 		}
 
-		err = rows.Scan(scanArgs...)
+		row, err := jsonify(rows, columns)
 		if err != nil {
-			panic(err.Error())
+			return err
 		}
 
-		for i, value := range values {
-			switch value := value.(type) {
-			case nil:
-				results[columns[i]] = nil
+		content, err := json.Marshal(row)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal row")
+		}
+		if _, err := w.Write(content); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, "failed to read rows")
+	}
 
-			case []byte:
-				s := string(value)
-				x, err := strconv.Atoi(s)
+	_, err = io.WriteString(w, "]")
+	return err
+}
 
-				if err != nil {
-					results[columns[i]] = s
-				} else {
-					results[columns[i]] = x
-				}
+func jsonify(rows *sql.Rows, columns []string) (map[string]any, error) {
+	values := make([]any, len(columns))
+	scanArgs := make([]any, len(values))
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+
+	if err := rows.Scan(scanArgs...); err != nil {
+		return nil, errors.Wrap(err, "failed to scan row")
+	}
 
-			default:
-				results[columns[i]] = value
+	results := make(map[string]any)
+	for i, value := range values {
+		switch value := value.(type) {
+		case nil:
+			results[columns[i]] = nil
+
+		case []byte:
+			s := string(value)
+			x, err := strconv.Atoi(s)
+
+			if err != nil {
+				results[columns[i]] = s
+			} else {
+				results[columns[i]] = x
 			}
-		}
 
-		allResults = append(allResults, results)
+		default:
+			results[columns[i]] = value
+		}
 	}
 
-	return allResults
+	return results, nil
 }
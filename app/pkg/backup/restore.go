@@ -0,0 +1,433 @@
+package backup
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
+)
+
+var (
+	// ErrInvalidArchive is returned when the file is not a backup archive
+	ErrInvalidArchive = stdErrors.New("file is not a valid backup archive")
+	// ErrUnsupportedVersion is returned when the archive was created by an unsupported version of Fider
+	ErrUnsupportedVersion = stdErrors.New("backup archive version is not supported")
+	// ErrNewerSchema is returned when the archive was created by a newer version of Fider than the one restoring it
+	ErrNewerSchema = stdErrors.New("backup archive was created by a newer version of Fider, upgrade before restoring it")
+	// ErrTenantNotEmpty is returned when restoring into a site that already has posts
+	ErrTenantNotEmpty = stdErrors.New("backups can only be restored into a site without posts")
+)
+
+// restoreTable describes how rows of a table are inserted into another tenant
+type restoreTable struct {
+	name string
+	// id is regenerated on insert and references to it are remapped
+	serial bool
+	// columns referencing the id of another table
+	references map[string]string
+	// rows matching an existing row on these columns are mapped to it instead of being inserted
+	matchOn []string
+	// columns that are not restored
+	ignore []string
+}
+
+// Tables are restored in order, so that referenced rows are inserted first.
+// Email verifications are not restored as they are short-lived
+var restoreTables = []restoreTable{
+	{name: "users", serial: true, matchOn: []string{"email"}},
+	{name: "user_providers", references: map[string]string{"user_id": "users"}},
+	{name: "user_settings", serial: true, references: map[string]string{"user_id": "users"}},
+	{name: "user_attributes", serial: true, references: map[string]string{"user_id": "users"}},
+	{name: "tags", serial: true, matchOn: []string{"slug"}},
+	{name: "post_statuses", serial: true, matchOn: []string{"status"}},
+	{name: "boards", serial: true, matchOn: []string{"slug"}},
+	{name: "board_tags", references: map[string]string{"board_id": "boards", "tag_id": "tags"}},
+	{name: "oauth_providers", serial: true, ignore: []string{"logo_id"}},
+	{name: "saml_configs", serial: true},
+	{name: "posts", serial: true, references: map[string]string{"user_id": "users", "response_user_id": "users", "original_id": "posts", "board_id": "boards"}},
+	{name: "comments", serial: true, references: map[string]string{"post_id": "posts", "user_id": "users", "edited_by_id": "users", "deleted_by_id": "users", "parent_id": "comments"}},
+	{name: "attachments", serial: true, references: map[string]string{"post_id": "posts", "comment_id": "comments", "user_id": "users"}},
+	{name: "post_revisions", serial: true, references: map[string]string{"post_id": "posts", "created_by_id": "users"}},
+	{name: "comment_revisions", serial: true, references: map[string]string{"comment_id": "comments", "created_by_id": "users"}},
+	{name: "post_tags", references: map[string]string{"tag_id": "tags", "post_id": "posts", "created_by_id": "users"}},
+	{name: "post_votes", references: map[string]string{"user_id": "users", "post_id": "posts"}},
+	{name: "post_subscribers", references: map[string]string{"user_id": "users", "post_id": "posts"}},
+	{name: "notifications", serial: true, references: map[string]string{"user_id": "users", "post_id": "posts", "author_id": "users"}},
+}
+
+// Tenant columns that belong to the site being restored into rather than to the backup
+var ignoredTenantColumns = []string{"id", "subdomain", "cname", "created_at", "status", "is_pro", "scheduled_deletion_at"}
+
+// RestoreSummary describes what was restored from an archive
+type RestoreSummary struct {
+	Rows  map[string]int `json:"rows"`
+	Blobs int            `json:"blobs"`
+}
+
+// Restore imports the content of a backup archive into given tenant.
+// IDs are remapped, users with the same email and tags with the same slug are merged
+// and blobs are uploaded to the blob storage of the tenant.
+// Site settings are only restored when restoreSettings is true, which is meant for new tenants
+func Restore(ctx context.Context, archive []byte, tenant *entity.Tenant, restoreSettings bool) (*RestoreSummary, error) {
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, ErrInvalidArchive
+	}
+
+	trx := ctx.Value(app.TransactionCtxKey).(*dbx.Trx)
+	ctx = context.WithValue(ctx, app.TenantCtxKey, tenant)
+
+	files := make(map[string]*zip.File)
+	for _, file := range reader.File {
+		files[file.Name] = file
+	}
+
+	if err := checkManifest(trx, files); err != nil {
+		return nil, err
+	}
+
+	postsCount, err := trx.Count("SELECT COUNT(*) FROM posts WHERE tenant_id = $1", tenant.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count posts")
+	}
+	if postsCount > 0 {
+		return nil, ErrTenantNotEmpty
+	}
+
+	if restoreSettings {
+		if err := restoreTenantSettings(trx, files, tenant); err != nil {
+			return nil, err
+		}
+	}
+
+	summary := &RestoreSummary{Rows: make(map[string]int)}
+	ids := make(map[string]map[int64]int64)
+	for _, table := range restoreTables {
+		count, err := restoreTableRows(trx, files, tenant, table, ids)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to restore %s", table.name)
+		}
+		summary.Rows[table.name] = count
+	}
+
+	// The similarity index is not part of the archive, so it's built from the restored posts
+	if err := bus.Dispatch(ctx, &cmd.RebuildPostSignatures{}); err != nil {
+		return nil, errors.Wrap(err, "failed to rebuild post signatures")
+	}
+
+	for name, file := range files {
+		bkey, ok := strings.CutPrefix(name, "blobs/")
+		if !ok || bkey == "" || file.FileInfo().IsDir() || IsArchiveKey(bkey) {
+			continue
+		}
+
+		content, err := readFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := bus.Dispatch(ctx, &cmd.StoreBlob{
+			Key:         bkey,
+			Content:     content,
+			ContentType: http.DetectContentType(content),
+		}); err != nil {
+			return nil, errors.Wrap(err, "failed to restore blob %s", bkey)
+		}
+		summary.Blobs++
+	}
+
+	return summary, nil
+}
+
+// checkManifest ensures the archive was created by a version of Fider this one can restore
+func checkManifest(trx *dbx.Trx, files map[string]*zip.File) error {
+	file, ok := files[manifestFileName]
+	if !ok {
+		return ErrUnsupportedVersion
+	}
+
+	content, err := readFile(file)
+	if err != nil {
+		return err
+	}
+
+	m := manifest{}
+	if err := json.Unmarshal(content, &m); err != nil {
+		return ErrInvalidArchive
+	}
+
+	if m.Version != archiveVersion {
+		return ErrUnsupportedVersion
+	}
+
+	migration, err := lastMigration(trx)
+	if err != nil {
+		return err
+	}
+
+	if m.Migration > migration {
+		return ErrNewerSchema
+	}
+
+	return nil
+}
+
+func restoreTenantSettings(trx *dbx.Trx, files map[string]*zip.File, tenant *entity.Tenant) error {
+	rows, err := readTable(files, "tenants")
+	if err != nil {
+		return err
+	}
+	if len(rows) != 1 {
+		return ErrInvalidArchive
+	}
+
+	columns, err := restorableColumns(trx, "tenants", rows[0], ignoredTenantColumns)
+	if err != nil {
+		return err
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+
+	assignments := make([]string, len(columns))
+	args := make([]any, len(columns)+1)
+	args[0] = tenant.ID
+	for i, column := range columns {
+		assignments[i] = fmt.Sprintf("%s = $%d", column, i+2)
+		args[i+1] = toArg(rows[0][column])
+	}
+
+	_, err = trx.Execute(fmt.Sprintf("UPDATE tenants SET %s WHERE id = $1", strings.Join(assignments, ", ")), args...)
+	if err != nil {
+		return errors.Wrap(err, "failed to restore site settings")
+	}
+	return nil
+}
+
+func restoreTableRows(trx *dbx.Trx, files map[string]*zip.File, tenant *entity.Tenant, table restoreTable, ids map[string]map[int64]int64) (int, error) {
+	rows, err := readTable(files, table.name)
+	if err != nil || len(rows) == 0 {
+		return 0, err
+	}
+
+	ignore := append([]string{"tenant_id"}, table.ignore...)
+	if table.serial {
+		ignore = append(ignore, "id")
+	}
+
+	columns, err := restorableColumns(trx, table.name, rows[0], ignore)
+	if err != nil {
+		return 0, err
+	}
+
+	if table.serial {
+		ids[table.name] = make(map[int64]int64)
+	}
+
+	// References to rows of the same table are set once all rows are inserted
+	type selfReference struct {
+		id     int64
+		column string
+		value  int64
+	}
+	selfReferences := make([]selfReference, 0)
+
+	count := 0
+	for _, row := range rows {
+		oldID, _ := toInt64(row["id"])
+
+		if len(table.matchOn) > 0 {
+			existingID, err := findMatchingRow(trx, tenant, table, row)
+			if err != nil {
+				return 0, err
+			}
+			if existingID > 0 {
+				ids[table.name][oldID] = existingID
+				continue
+			}
+		}
+
+		args := []any{tenant.ID}
+		placeholders := []string{"$1"}
+		pending := make([]selfReference, 0)
+		skip := false
+		for _, column := range columns {
+			value := toArg(row[column])
+			if referenced, ok := table.references[column]; ok && value != nil {
+				oldValue, _ := toInt64(row[column])
+				if referenced == table.name {
+					pending = append(pending, selfReference{column: column, value: oldValue})
+					value = nil
+				} else if newValue, ok := ids[referenced][oldValue]; ok {
+					value = newValue
+				} else {
+					// The referenced row was not restored, so neither is this one
+					skip = true
+					break
+				}
+			}
+			args = append(args, value)
+			placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+		}
+		if skip {
+			continue
+		}
+
+		command := fmt.Sprintf("INSERT INTO %s (tenant_id, %s) VALUES (%s) ON CONFLICT DO NOTHING",
+			table.name, strings.Join(columns, ", "), strings.Join(placeholders, ", "))
+
+		if !table.serial {
+			result, err := trx.Execute(command, args...)
+			if err != nil {
+				return 0, err
+			}
+			count += int(result)
+			continue
+		}
+
+		var newID int64
+		err := trx.Scalar(&newID, command+" RETURNING id", args...)
+		if errors.Cause(err) == app.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		ids[table.name][oldID] = newID
+		for _, ref := range pending {
+			ref.id = newID
+			selfReferences = append(selfReferences, ref)
+		}
+		count++
+	}
+
+	for _, ref := range selfReferences {
+		if newValue, ok := ids[table.name][ref.value]; ok {
+			command := fmt.Sprintf("UPDATE %s SET %s = $1 WHERE id = $2 AND tenant_id = $3", table.name, ref.column)
+			if _, err := trx.Execute(command, newValue, ref.id, tenant.ID); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return count, nil
+}
+
+// findMatchingRow returns the id of an existing row with the same values on the table's match columns, or 0 when there's none
+func findMatchingRow(trx *dbx.Trx, tenant *entity.Tenant, table restoreTable, row map[string]any) (int64, error) {
+	conditions := []string{"tenant_id = $1"}
+	args := []any{tenant.ID}
+	for _, column := range table.matchOn {
+		value := toArg(row[column])
+		if value == nil || value == "" {
+			return 0, nil
+		}
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+
+	var id int64
+	err := trx.Scalar(&id, fmt.Sprintf("SELECT id FROM %s WHERE %s LIMIT 1", table.name, strings.Join(conditions, " AND ")), args...)
+	if errors.Cause(err) == app.ErrNotFound {
+		return 0, nil
+	}
+	return id, err
+}
+
+// restorableColumns returns the columns of the row that exist in the table, ignoring generated columns
+func restorableColumns(trx *dbx.Trx, tableName string, row map[string]any, ignore []string) ([]string, error) {
+	rows, err := trx.Query(`
+		SELECT column_name FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = $1 AND is_generated = 'NEVER'
+	`, tableName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get columns of %s", tableName)
+	}
+	defer func() { _ = rows.Close() }()
+
+	columns := make([]string, 0)
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, errors.Wrap(err, "failed to scan column of %s", tableName)
+		}
+		if _, ok := row[column]; ok && !slices.Contains(ignore, column) {
+			columns = append(columns, column)
+		}
+	}
+
+	sort.Strings(columns)
+	return columns, nil
+}
+
+func readTable(files map[string]*zip.File, tableName string) ([]map[string]any, error) {
+	file, ok := files[tableName+".json"]
+	if !ok {
+		// Tables created after the backup are restored as empty
+		return nil, nil
+	}
+
+	content, err := readFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]map[string]any, 0)
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	if err := decoder.Decode(&rows); err != nil {
+		return nil, ErrInvalidArchive
+	}
+	return rows, nil
+}
+
+func readFile(file *zip.File) ([]byte, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, ErrInvalidArchive
+	}
+	defer func() { _ = reader.Close() }()
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, ErrInvalidArchive
+	}
+	return content, nil
+}
+
+// toArg converts a JSON value to a query argument
+func toArg(value any) any {
+	switch value := value.(type) {
+	case json.Number:
+		return value.String()
+	case map[string]any, []any:
+		content, _ := json.Marshal(value)
+		return string(content)
+	default:
+		return value
+	}
+}
+
+func toInt64(value any) (int64, bool) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	result, err := number.Int64()
+	return result, err == nil
+}
@@ -0,0 +1,31 @@
+package backup
+
+import (
+	"testing"
+
+	. "github.com/getfider/fider/app/pkg/assert"
+)
+
+func TestRestoreTables_ReferencedTablesAreRestoredFirst(t *testing.T) {
+	RegisterT(t)
+
+	restored := make(map[string]bool)
+	for _, table := range restoreTables {
+		for column, referenced := range table.references {
+			if referenced != table.name && !restored[referenced] {
+				t.Errorf("%s.%s references %s, which is restored later", table.name, column, referenced)
+			}
+		}
+		restored[table.name] = true
+	}
+}
+
+func TestRestoreTables_MatchedTablesAreSerial(t *testing.T) {
+	RegisterT(t)
+
+	for _, table := range restoreTables {
+		if len(table.matchOn) > 0 {
+			Expect(table.serial).IsTrue()
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/services/blob"
+)
+
+// BlobPrefix is the reserved prefix of the blob storage where archives of a tenant are stored
+const BlobPrefix = "backups/"
+
+// IsArchiveKey returns true when given blob key is within the reserved prefix of archives.
+// The key is cleaned first, because blob storages resolve keys such as './backups/file' to the same path
+func IsArchiveKey(bkey string) bool {
+	return strings.HasPrefix(path.Clean(bkey), BlobPrefix)
+}
+
+const archiveTimeLayout = "20060102T150405Z"
+
+// Archive is a backup archive stored on the blob storage
+type Archive struct {
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Store streams a new archive of current tenant to the blob storage, without holding it in memory
+func Store(ctx context.Context) (*Archive, error) {
+	now := time.Now().UTC().Truncate(time.Second)
+	archive := &Archive{
+		Name:      fmt.Sprintf("backup-%s.zip", now.Format(archiveTimeLayout)),
+		CreatedAt: now,
+	}
+
+	reader, writer := io.Pipe()
+	written := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err := errors.Panicked(r)
+				_ = writer.CloseWithError(err)
+				written <- err
+			}
+		}()
+
+		err := Write(ctx, writer)
+		_ = writer.CloseWithError(err)
+		written <- err
+	}()
+
+	err := bus.Dispatch(ctx, &cmd.StoreBlobStream{
+		Key:         BlobPrefix + archive.Name,
+		Reader:      reader,
+		ContentType: "application/zip",
+	})
+
+	// Unblocks the writer when the storage stopped reading before the end of the archive
+	_ = reader.CloseWithError(err)
+	if writeErr := <-written; writeErr != nil {
+		return nil, errors.Wrap(writeErr, "failed to write archive '%s'", archive.Name)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to store archive '%s'", archive.Name)
+	}
+
+	return archive, nil
+}
+
+// List returns the archives of current tenant, newest first
+func List(ctx context.Context) ([]*Archive, error) {
+	listBlobs := &query.ListBlobs{Prefix: BlobPrefix}
+	if err := bus.Dispatch(ctx, listBlobs); err != nil {
+		return nil, errors.Wrap(err, "failed to list archives")
+	}
+
+	archives := make([]*Archive, 0)
+	for _, bkey := range listBlobs.Result {
+		if archive, ok := parseArchiveName(strings.TrimPrefix(bkey, BlobPrefix)); ok {
+			archives = append(archives, archive)
+		}
+	}
+
+	sort.Slice(archives, func(i, j int) bool {
+		return archives[i].CreatedAt.After(archives[j].CreatedAt)
+	})
+	return archives, nil
+}
+
+// Prune deletes the archives of current tenant, except for the newest ones. Returns the number of deleted archives
+func Prune(ctx context.Context, keep int) (int, error) {
+	archives, err := List(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for i := keep; i < len(archives); i++ {
+		if err := bus.Dispatch(ctx, &cmd.DeleteBlob{Key: BlobPrefix + archives[i].Name}); err != nil {
+			return deleted, errors.Wrap(err, "failed to delete archive '%s'", archives[i].Name)
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// Open returns a reader of the archive with given name, which must be closed by the caller
+func Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	if _, ok := parseArchiveName(name); !ok {
+		return nil, blob.ErrNotFound
+	}
+
+	openBlob := &query.OpenBlobByKey{Key: BlobPrefix + name}
+	if err := bus.Dispatch(ctx, openBlob); err != nil {
+		return nil, err
+	}
+	return openBlob.Result, nil
+}
+
+func parseArchiveName(name string) (*Archive, bool) {
+	date, ok := strings.CutPrefix(name, "backup-")
+	if !ok {
+		return nil, false
+	}
+	date, ok = strings.CutSuffix(date, ".zip")
+	if !ok {
+		return nil, false
+	}
+
+	createdAt, err := time.Parse(archiveTimeLayout, date)
+	if err != nil {
+		return nil, false
+	}
+	return &Archive{Name: name, CreatedAt: createdAt}, true
+}
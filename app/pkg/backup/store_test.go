@@ -0,0 +1,78 @@
+package backup
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/services/blob"
+)
+
+func TestIsArchiveKey(t *testing.T) {
+	RegisterT(t)
+
+	Expect(IsArchiveKey("backups/backup-20261018T030000Z.zip")).IsTrue()
+	Expect(IsArchiveKey("./backups/backup-20261018T030000Z.zip")).IsTrue()
+	Expect(IsArchiveKey("backups//backup-20261018T030000Z.zip")).IsTrue()
+	Expect(IsArchiveKey("attachments/backups/file.png")).IsFalse()
+	Expect(IsArchiveKey("logos/backups.png")).IsFalse()
+}
+
+func TestListAndPrune(t *testing.T) {
+	RegisterT(t)
+
+	keys := []string{
+		"backups/backup-20261016T030000Z.zip",
+		"backups/backup-20261018T030000Z.zip",
+		"backups/.tmp-123456",
+		"backups/backup-20261017T030000Z.zip",
+	}
+	bus.AddHandler(func(ctx context.Context, q *query.ListBlobs) error {
+		Expect(q.Prefix).Equals(BlobPrefix)
+		q.Result = keys
+		return nil
+	})
+
+	deleted := make([]string, 0)
+	bus.AddHandler(func(ctx context.Context, c *cmd.DeleteBlob) error {
+		deleted = append(deleted, c.Key)
+		return nil
+	})
+
+	archives, err := List(context.Background())
+	Expect(err).IsNil()
+	Expect(archives).HasLen(3)
+	Expect(archives[0].Name).Equals("backup-20261018T030000Z.zip")
+	Expect(archives[0].CreatedAt).Equals(time.Date(2026, 10, 18, 3, 0, 0, 0, time.UTC))
+	Expect(archives[2].Name).Equals("backup-20261016T030000Z.zip")
+
+	count, err := Prune(context.Background(), 1)
+	Expect(err).IsNil()
+	Expect(count).Equals(2)
+	Expect(deleted).Equals([]string{"backups/backup-20261017T030000Z.zip", "backups/backup-20261016T030000Z.zip"})
+}
+
+func TestOpen(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.OpenBlobByKey) error {
+		Expect(q.Key).Equals("backups/backup-20261018T030000Z.zip")
+		q.Result = io.NopCloser(strings.NewReader("zip"))
+		return nil
+	})
+
+	reader, err := Open(context.Background(), "backup-20261018T030000Z.zip")
+	Expect(err).IsNil()
+	content, _ := io.ReadAll(reader)
+	Expect(string(content)).Equals("zip")
+
+	_, err = Open(context.Background(), "../logos/file.png")
+	Expect(errors.Cause(err)).Equals(blob.ErrNotFound)
+}
@@ -0,0 +1,14 @@
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+)
+
+//HMACSHA256 returns the hex encoded HMAC-SHA256 of a given string using given key
+func HMACSHA256(key, input string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(input))
+	return fmt.Sprintf("%x", mac.Sum(nil))
+}
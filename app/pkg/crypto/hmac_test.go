@@ -0,0 +1,16 @@
+package crypto_test
+
+import (
+	"testing"
+
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/crypto"
+)
+
+func TestHMACSHA256(t *testing.T) {
+	RegisterT(t)
+
+	hash := crypto.HMACSHA256("key", "The quick brown fox jumps over the lazy dog")
+
+	Expect(hash).Equals("f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8")
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/pkg/errors"
 )
 
 //FromPosts return a byte array of CSV file containing all posts
@@ -62,7 +63,7 @@ func FromPosts(posts []*entity.Post) ([]byte, error) {
 			post.User.Name,
 			strconv.Itoa(post.VotesCount),
 			strconv.Itoa(post.CommentsCount),
-			post.Status.Name(),
+			post.StatusName(),
 			respondedBy,
 			respondedAt,
 			response,
@@ -83,3 +84,65 @@ func FromPosts(posts []*entity.Post) ([]byte, error) {
 
 	return buffer.Bytes(), nil
 }
+
+//UserAttributesRow holds the attributes of an user identified by email or reference
+type UserAttributesRow struct {
+	Line       int
+	Email      string
+	Reference  string
+	Attributes map[string]string
+}
+
+//ToUserAttributes reads a CSV file with a header row, users are identified by the email or reference columns
+//and every other column is an attribute
+func ToUserAttributes(data []byte) ([]*UserAttributesRow, error) {
+	reader := gocsv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read CSV file")
+	}
+
+	if len(records) == 0 {
+		return nil, errors.New("CSV file is empty")
+	}
+
+	emailColumn, referenceColumn := -1, -1
+	header := make([]string, len(records[0]))
+	for i, column := range records[0] {
+		header[i] = strings.ToLower(strings.TrimSpace(column))
+		switch header[i] {
+		case "email":
+			emailColumn = i
+		case "reference":
+			referenceColumn = i
+		}
+	}
+
+	if emailColumn == -1 && referenceColumn == -1 {
+		return nil, errors.New("CSV file must have an 'email' or 'reference' column")
+	}
+
+	rows := make([]*UserAttributesRow, 0, len(records)-1)
+	for i, record := range records[1:] {
+		row := &UserAttributesRow{
+			Line:       i + 2,
+			Attributes: make(map[string]string),
+		}
+		for j, value := range record {
+			value = strings.TrimSpace(value)
+			switch j {
+			case emailColumn:
+				row.Email = value
+			case referenceColumn:
+				row.Reference = value
+			default:
+				row.Attributes[header[j]] = value
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
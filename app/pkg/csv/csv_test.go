@@ -110,3 +110,28 @@ var duplicatePost = &entity.Post{
 	},
 	Tags: []string{"this-tag-has,comma"},
 }
+
+func TestToUserAttributes(t *testing.T) {
+	RegisterT(t)
+
+	rows, err := csv.ToUserAttributes([]byte("Email,Reference,plan,mrr\njon.snow@got.com,,pro,300\n,42, enterprise ,1200\n"))
+	Expect(err).IsNil()
+	Expect(rows).HasLen(2)
+	Expect(rows[0].Line).Equals(2)
+	Expect(rows[0].Email).Equals("jon.snow@got.com")
+	Expect(rows[0].Reference).Equals("")
+	Expect(rows[0].Attributes).Equals(map[string]string{"plan": "pro", "mrr": "300"})
+	Expect(rows[1].Line).Equals(3)
+	Expect(rows[1].Reference).Equals("42")
+	Expect(rows[1].Attributes).Equals(map[string]string{"plan": "enterprise", "mrr": "1200"})
+}
+
+func TestToUserAttributes_Invalid(t *testing.T) {
+	RegisterT(t)
+
+	_, err := csv.ToUserAttributes([]byte(""))
+	Expect(err).IsNotNil()
+
+	_, err = csv.ToUserAttributes([]byte("name,plan\nJon,pro\n"))
+	Expect(err).IsNotNil()
+}
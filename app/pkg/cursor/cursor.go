@@ -0,0 +1,36 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalid is returned when a cursor can't be decoded
+var ErrInvalid = errors.New("cursor is invalid")
+
+// Encode returns an opaque cursor pointing to the item with given sort key and id
+func Encode(key string, id int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key + "|" + strconv.Itoa(id)))
+}
+
+// Decode returns the sort key and id of the item given cursor points to
+func Decode(cursor string) (string, int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", 0, ErrInvalid
+	}
+
+	idx := strings.LastIndex(string(raw), "|")
+	if idx < 0 {
+		return "", 0, ErrInvalid
+	}
+
+	id, err := strconv.Atoi(string(raw[idx+1:]))
+	if err != nil {
+		return "", 0, ErrInvalid
+	}
+
+	return string(raw[:idx]), id, nil
+}
@@ -0,0 +1,29 @@
+package cursor_test
+
+import (
+	"testing"
+
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/cursor"
+)
+
+func TestCursor_EncodeDecode(t *testing.T) {
+	RegisterT(t)
+
+	for _, key := range []string{"", "42", "0.0123", "2026-10-18T10:12:55.123456Z", "a|b"} {
+		value := cursor.Encode(key, 17)
+		decodedKey, decodedID, err := cursor.Decode(value)
+		Expect(err).IsNil()
+		Expect(decodedKey).Equals(key)
+		Expect(decodedID).Equals(17)
+	}
+}
+
+func TestCursor_DecodeInvalid(t *testing.T) {
+	RegisterT(t)
+
+	for _, value := range []string{"@@@", cursor.Encode("abc", 1)[:3], "MTIz"} {
+		_, _, err := cursor.Decode(value)
+		Expect(err).Equals(cursor.ErrInvalid)
+	}
+}
@@ -310,6 +310,24 @@ func (trx *Trx) MustRollback() {
 	}
 }
 
+// Savepoint runs fn within a savepoint of current transaction
+// When fn fails, only the changes made by fn are undone and the transaction can still be used
+func (trx *Trx) Savepoint(name string, fn func() error) error {
+	if _, err := trx.Execute("SAVEPOINT " + name); err != nil {
+		return err
+	}
+
+	if err := fn(); err != nil {
+		if _, rollbackErr := trx.Execute("ROLLBACK TO SAVEPOINT " + name); rollbackErr != nil {
+			return rollbackErr
+		}
+		return err
+	}
+
+	_, err := trx.Execute("RELEASE SAVEPOINT " + name)
+	return err
+}
+
 func wrap(err error, format string, a ...any) error {
 	if pqErr, ok := err.(*pq.Error); ok {
 		if pqErr.Code == "57014" { //query canceled
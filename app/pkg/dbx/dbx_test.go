@@ -278,3 +278,41 @@ func TestByteArray(t *testing.T) {
 	Expect(theFile.Content).Equals(fileContent)
 	Expect(theFile.Size).Equals(len(theFile.Content))
 }
+
+func TestSavepoint_Failure(t *testing.T) {
+	RegisterT(t)
+
+	trx, _ := dbx.BeginTx(context.Background())
+	defer trx.MustRollback()
+
+	err := trx.Savepoint("rename", func() error {
+		_, err := trx.Execute("UPDATE users SET name = 'Renamed' WHERE id = 1")
+		Expect(err).IsNil()
+		_, err = trx.Execute("SELECT * FROM unknown_table")
+		return err
+	})
+	Expect(err).IsNotNil()
+
+	var name string
+	err = trx.Scalar(&name, "SELECT name FROM users WHERE id = 1")
+	Expect(err).IsNil()
+	Expect(name).NotEquals("Renamed")
+}
+
+func TestSavepoint_Success(t *testing.T) {
+	RegisterT(t)
+
+	trx, _ := dbx.BeginTx(context.Background())
+	defer trx.MustRollback()
+
+	err := trx.Savepoint("rename", func() error {
+		_, err := trx.Execute("UPDATE users SET name = 'Renamed' WHERE id = 1")
+		return err
+	})
+	Expect(err).IsNil()
+
+	var name string
+	err = trx.Scalar(&name, "SELECT name FROM users WHERE id = 1")
+	Expect(err).IsNil()
+	Expect(name).Equals("Renamed")
+}
@@ -2,12 +2,15 @@ package dbx
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	stdErrors "errors"
 	"os"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/getfider/fider/app/models/dto"
 	"github.com/getfider/fider/app/pkg/env"
@@ -18,38 +21,58 @@ import (
 // ErrNoChanges means that the migration process didn't change execute any file
 var ErrNoChanges = stdErrors.New("nothing to migrate")
 
+// ErrMigrationModified means that the file of an already applied migration was edited afterwards
+var ErrMigrationModified = stdErrors.New("applied migration was modified")
+
+// downSuffix is the suffix of the files that revert the migration with the same version
+const downSuffix = ".down.sql"
+
+// Status of a migration file, as reported by GetMigrationStatus
+const (
+	MigrationApplied = "applied"
+	MigrationPending = "pending"
+	// MigrationSkipped is a migration older than the last applied one, which is never applied
+	MigrationSkipped = "skipped"
+	// MigrationModified is an applied migration whose file was edited afterwards
+	MigrationModified = "modified"
+	// MigrationMissing is an applied migration whose file no longer exists
+	MigrationMissing = "missing"
+)
+
+// MigrationStatus describes a migration and whether it was applied
+type MigrationStatus struct {
+	Version    int
+	FileName   string
+	Checksum   string // of the file on disk, empty when it's missing
+	Status     string
+	Reversible bool
+	AppliedAt  *time.Time
+}
+
+type migrationFile struct {
+	version      int
+	fileName     string
+	downFileName string
+	checksum     string
+}
+
+type appliedMigration struct {
+	version  int
+	fileName string
+	checksum sql.NullString
+	date     time.Time
+}
+
 // Migrate the database to latest version
 func Migrate(ctx context.Context, path string) error {
 	log.Info(ctx, "Running migrations...")
-	dir, err := os.Open(env.Path(path))
-	if err != nil {
-		return errors.Wrap(err, "failed to open dir '%s'", path)
-	}
-
-	files, err := dir.Readdir(0)
+	files, err := readMigrationFiles(path)
 	if err != nil {
-		return errors.Wrap(err, "failed to read files from dir '%s'", path)
-	}
-
-	versions := make([]int, len(files))
-	versionFiles := make(map[int]string, len(files))
-	for i, file := range files {
-		fileName := file.Name()
-		parts := strings.Split(fileName, "_")
-		if len(parts[0]) != 12 {
-			return errors.New("migration file must have exactly 12 chars for version: '%s' is invalid.", fileName)
-		}
-
-		versions[i], err = strconv.Atoi(parts[0])
-		versionFiles[versions[i]] = fileName
-		if err != nil {
-			return errors.Wrap(err, "failed to convert '%s' to number", parts[0])
-		}
+		return err
 	}
-	sort.Ints(versions)
 
 	log.Infof(ctx, "Found total of @{Total} migration files.", dto.Props{
-		"Total": len(versions),
+		"Total": len(files),
 	})
 
 	lastVersion, err := getLastMigration()
@@ -61,19 +84,25 @@ func Migrate(ctx context.Context, path string) error {
 		"Version": lastVersion,
 	})
 
+	if err := verifyAppliedMigrations(files); err != nil {
+		return err
+	}
+
 	totalMigrationsExecuted := 0
 
 	// Apply all migrations
-	for _, version := range versions {
-		if version > lastVersion {
-			fileName := versionFiles[version]
+	for _, file := range files {
+		if file.version > lastVersion {
 			log.Infof(ctx, "Running Version: @{Version} (@{FileName})", dto.Props{
-				"Version":  version,
-				"FileName": fileName,
+				"Version":  file.version,
+				"FileName": file.fileName,
+			})
+			err := runMigration(ctx, path, file.fileName, func(trx *Trx) error {
+				_, err := trx.Execute("INSERT INTO migrations_history (version, filename, checksum) VALUES ($1, $2, $3)", file.version, file.fileName, file.checksum)
+				return err
 			})
-			err := runMigration(ctx, version, path, fileName)
 			if err != nil {
-				return errors.Wrap(err, "failed to run migration '%s'", fileName)
+				return errors.Wrap(err, "failed to run migration '%s'", file.fileName)
 			}
 			totalMigrationsExecuted++
 		}
@@ -89,7 +118,225 @@ func Migrate(ctx context.Context, path string) error {
 	return nil
 }
 
-func runMigration(ctx context.Context, version int, path, fileName string) error {
+// MigrateDown reverts the last count applied migrations, newest first.
+// Nothing is reverted when any of them doesn't have a down file
+func MigrateDown(ctx context.Context, path string, count int) error {
+	if count <= 0 {
+		return errors.New("number of migrations to revert must be greater than zero")
+	}
+
+	files, err := readMigrationFiles(path)
+	if err != nil {
+		return err
+	}
+
+	if err := ensureMigrationsHistory(); err != nil {
+		return errors.Wrap(err, "failed to create migrations history")
+	}
+
+	applied, err := getAppliedMigrations()
+	if err != nil {
+		return errors.Wrap(err, "failed to get applied migrations")
+	}
+
+	if count > len(applied) {
+		return errors.New("cannot revert %d migrations, only %d were applied", count, len(applied))
+	}
+
+	byVersion := make(map[int]*migrationFile, len(files))
+	for _, file := range files {
+		byVersion[file.version] = file
+	}
+
+	targets := make([]*migrationFile, count)
+	for i := 0; i < count; i++ {
+		migration := applied[len(applied)-1-i]
+		file, ok := byVersion[migration.version]
+		if !ok || file.downFileName == "" {
+			return errors.New("migration '%s' cannot be reverted because it doesn't have a down file", migration.fileName)
+		}
+		targets[i] = file
+	}
+
+	for _, file := range targets {
+		log.Infof(ctx, "Reverting Version: @{Version} (@{FileName})", dto.Props{
+			"Version":  file.version,
+			"FileName": file.downFileName,
+		})
+		err := runMigration(ctx, path, file.downFileName, func(trx *Trx) error {
+			_, err := trx.Execute("DELETE FROM migrations_history WHERE version = $1", file.version)
+			return err
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to revert migration '%s'", file.fileName)
+		}
+	}
+
+	log.Infof(ctx, "@{Count} migrations have been reverted.", dto.Props{
+		"Count": count,
+	})
+	return nil
+}
+
+// GetMigrationStatus returns every migration file and every applied migration, ordered by version
+func GetMigrationStatus(ctx context.Context, path string) ([]*MigrationStatus, error) {
+	files, err := readMigrationFiles(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lastVersion, err := getLastMigration()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get last migration record")
+	}
+
+	applied, err := getAppliedMigrations()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get applied migrations")
+	}
+
+	appliedByVersion := make(map[int]*appliedMigration, len(applied))
+	for _, migration := range applied {
+		appliedByVersion[migration.version] = migration
+	}
+
+	result := make([]*MigrationStatus, 0, len(files))
+	for _, file := range files {
+		status := &MigrationStatus{
+			Version:    file.version,
+			FileName:   file.fileName,
+			Checksum:   file.checksum,
+			Reversible: file.downFileName != "",
+			Status:     MigrationPending,
+		}
+
+		if migration, ok := appliedByVersion[file.version]; ok {
+			status.Status = MigrationApplied
+			status.AppliedAt = &migration.date
+			if migration.checksum.Valid && migration.checksum.String != file.checksum {
+				status.Status = MigrationModified
+			}
+			delete(appliedByVersion, file.version)
+		} else if file.version <= lastVersion {
+			status.Status = MigrationSkipped
+		}
+
+		result = append(result, status)
+	}
+
+	for _, migration := range appliedByVersion {
+		result = append(result, &MigrationStatus{
+			Version:   migration.version,
+			FileName:  migration.fileName,
+			Status:    MigrationMissing,
+			AppliedAt: &migration.date,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+	return result, nil
+}
+
+// readMigrationFiles returns the migration files on given path ordered by version, along with their down files
+func readMigrationFiles(path string) ([]*migrationFile, error) {
+	dir, err := os.Open(env.Path(path))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open dir '%s'", path)
+	}
+	defer dir.Close()
+
+	entries, err := dir.Readdir(0)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read files from dir '%s'", path)
+	}
+
+	files := make(map[int]*migrationFile, len(entries))
+	downFiles := make(map[int]string)
+	for _, entry := range entries {
+		fileName := entry.Name()
+		parts := strings.Split(fileName, "_")
+		if len(parts[0]) != 12 {
+			return nil, errors.New("migration file must have exactly 12 chars for version: '%s' is invalid.", fileName)
+		}
+
+		version, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to convert '%s' to number", parts[0])
+		}
+
+		if strings.HasSuffix(fileName, downSuffix) {
+			downFiles[version] = fileName
+			continue
+		}
+
+		if existing, ok := files[version]; ok {
+			return nil, errors.New("migration files '%s' and '%s' have the same version", existing.fileName, fileName)
+		}
+
+		content, err := os.ReadFile(env.Path(path, fileName))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read file '%s'", fileName)
+		}
+		sum := sha256.Sum256(content)
+		files[version] = &migrationFile{version: version, fileName: fileName, checksum: hex.EncodeToString(sum[:])}
+	}
+
+	result := make([]*migrationFile, 0, len(files))
+	for version, file := range files {
+		file.downFileName = downFiles[version]
+		delete(downFiles, version)
+		result = append(result, file)
+	}
+
+	if len(downFiles) > 0 {
+		orphans := make([]string, 0, len(downFiles))
+		for _, fileName := range downFiles {
+			orphans = append(orphans, fileName)
+		}
+		sort.Strings(orphans)
+		return nil, errors.New("down migrations without a matching migration file: %s", strings.Join(orphans, ", "))
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].version < result[j].version
+	})
+	return result, nil
+}
+
+// verifyAppliedMigrations fails when an applied migration file was edited.
+// Migrations applied before checksums were recorded trust the current file and store its checksum
+func verifyAppliedMigrations(files []*migrationFile) error {
+	applied, err := getAppliedMigrations()
+	if err != nil {
+		return errors.Wrap(err, "failed to get applied migrations")
+	}
+
+	byVersion := make(map[int]*migrationFile, len(files))
+	for _, file := range files {
+		byVersion[file.version] = file
+	}
+
+	for _, migration := range applied {
+		file, ok := byVersion[migration.version]
+		if !ok {
+			continue
+		}
+
+		if !migration.checksum.Valid {
+			if _, err := conn.Exec("UPDATE migrations_history SET checksum = $2 WHERE version = $1", file.version, file.checksum); err != nil {
+				return errors.Wrap(err, "failed to store checksum of migration '%s'", file.fileName)
+			}
+		} else if migration.checksum.String != file.checksum {
+			return errors.Wrap(ErrMigrationModified, "migration '%s' was edited after being applied", file.fileName)
+		}
+	}
+
+	return nil
+}
+
+func runMigration(ctx context.Context, path, fileName string, record func(trx *Trx) error) error {
 	filePath := env.Path(path + "/" + fileName)
 	content, err := os.ReadFile(filePath)
 	if err != nil {
@@ -103,30 +350,59 @@ func runMigration(ctx context.Context, version int, path, fileName string) error
 
 	_, err = trx.tx.Exec(string(content))
 	if err != nil {
+		trx.MustRollback()
 		return err
 	}
 
-	_, err = trx.Execute("INSERT INTO migrations_history (version, filename) VALUES ($1, $2)", version, fileName)
-	if err != nil {
+	if err := record(trx); err != nil {
+		trx.MustRollback()
 		return err
 	}
 
 	return trx.Commit()
 }
 
-func getLastMigration() (int, error) {
+func ensureMigrationsHistory() error {
 	_, err := conn.Exec(`CREATE TABLE IF NOT EXISTS migrations_history (
 		version     BIGINT PRIMARY KEY,
 		filename    VARCHAR(100) null,
 		date	 			TIMESTAMPTZ NOT NULL DEFAULT NOW()
 	)`)
 	if err != nil {
+		return err
+	}
+
+	_, err = conn.Exec("ALTER TABLE migrations_history ADD COLUMN IF NOT EXISTS checksum VARCHAR(64) NULL")
+	return err
+}
+
+// getAppliedMigrations returns the migrations recorded on migrations_history, ordered by version
+func getAppliedMigrations() ([]*appliedMigration, error) {
+	rows, err := conn.Query("SELECT version, COALESCE(filename, ''), checksum, date FROM migrations_history ORDER BY version")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make([]*appliedMigration, 0)
+	for rows.Next() {
+		migration := &appliedMigration{}
+		if err := rows.Scan(&migration.version, &migration.fileName, &migration.checksum, &migration.date); err != nil {
+			return nil, err
+		}
+		applied = append(applied, migration)
+	}
+	return applied, rows.Err()
+}
+
+func getLastMigration() (int, error) {
+	if err := ensureMigrationsHistory(); err != nil {
 		return 0, err
 	}
 
 	var lastVersion sql.NullInt64
 	row := conn.QueryRow("SELECT MAX(version) FROM migrations_history LIMIT 1")
-	err = row.Scan(&lastVersion)
+	err := row.Scan(&lastVersion)
 	if err != nil {
 		return 0, err
 	}
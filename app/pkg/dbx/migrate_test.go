@@ -6,6 +6,7 @@ import (
 
 	. "github.com/getfider/fider/app/pkg/assert"
 	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
 )
 
 func setupMigrationTest(t *testing.T) {
@@ -16,6 +17,7 @@ func setupMigrationTest(t *testing.T) {
 	_, _ = trx.Execute("DELETE FROM migrations_history WHERE version >= 210001010000")
 	_, _ = trx.Execute("DROP TABLE IF EXISTS dummy")
 	_, _ = trx.Execute("DROP TABLE IF EXISTS foo")
+	_, _ = trx.Execute("DROP TABLE IF EXISTS bar")
 	trx.MustCommit()
 }
 
@@ -52,3 +54,113 @@ func TestMigrate_Failure(t *testing.T) {
 	_, err = trx.Execute("SELECT description FROM dummy")
 	Expect(err).IsNotNil()
 }
+
+func TestMigrate_Down(t *testing.T) {
+	setupMigrationTest(t)
+	ctx := context.Background()
+
+	err := dbx.Migrate(ctx, "/app/pkg/dbx/testdata/migration_reversible")
+	Expect(err).IsNil()
+
+	err = dbx.MigrateDown(ctx, "/app/pkg/dbx/testdata/migration_reversible", 1)
+	Expect(err).IsNil()
+
+	trx, _ := dbx.BeginTx(ctx)
+	var count int
+	err = trx.Scalar(&count, "SELECT COUNT(*) FROM bar")
+	Expect(err).IsNil()
+	Expect(count).Equals(0)
+	trx.MustRollback()
+
+	err = dbx.MigrateDown(ctx, "/app/pkg/dbx/testdata/migration_reversible", 1)
+	Expect(err).IsNil()
+
+	trx, _ = dbx.BeginTx(ctx)
+	_, err = trx.Execute("SELECT name FROM bar")
+	Expect(err).IsNotNil()
+	trx.MustRollback()
+
+	// Reverted migrations are applied again
+	err = dbx.Migrate(ctx, "/app/pkg/dbx/testdata/migration_reversible")
+	Expect(err).IsNil()
+
+	trx, _ = dbx.BeginTx(ctx)
+	err = trx.Scalar(&count, "SELECT COUNT(*) FROM bar")
+	Expect(err).IsNil()
+	Expect(count).Equals(2)
+	trx.MustRollback()
+}
+
+func TestMigrate_Down_WithoutDownFile(t *testing.T) {
+	setupMigrationTest(t)
+	ctx := context.Background()
+
+	err := dbx.Migrate(ctx, "/app/pkg/dbx/testdata/migration_success")
+	Expect(err).IsNil()
+
+	err = dbx.MigrateDown(ctx, "/app/pkg/dbx/testdata/migration_success", 1)
+	Expect(err).IsNotNil()
+
+	trx, _ := dbx.BeginTx(ctx)
+	var count int
+	err = trx.Scalar(&count, "SELECT COUNT(*) FROM dummy")
+	Expect(err).IsNil()
+	Expect(count).Equals(2)
+	trx.MustRollback()
+}
+
+// testMigrationStatus returns the status of the test migrations only, as the ones of the database schema are missing from the test folders
+func testMigrationStatus(ctx context.Context, path string) []*dbx.MigrationStatus {
+	migrations, err := dbx.GetMigrationStatus(ctx, path)
+	Expect(err).IsNil()
+
+	result := make([]*dbx.MigrationStatus, 0)
+	for _, migration := range migrations {
+		if migration.Version >= 210001010000 {
+			result = append(result, migration)
+		}
+	}
+	return result
+}
+
+func TestMigrationStatus(t *testing.T) {
+	setupMigrationTest(t)
+	ctx := context.Background()
+
+	err := dbx.Migrate(ctx, "/app/pkg/dbx/testdata/migration_reversible")
+	Expect(err).IsNil()
+
+	err = dbx.MigrateDown(ctx, "/app/pkg/dbx/testdata/migration_reversible", 1)
+	Expect(err).IsNil()
+
+	migrations := testMigrationStatus(ctx, "/app/pkg/dbx/testdata/migration_reversible")
+	Expect(migrations).HasLen(2)
+	Expect(migrations[0].FileName).Equals("210001010000_create_bar.sql")
+	Expect(migrations[0].Status).Equals(dbx.MigrationApplied)
+	Expect(migrations[0].Reversible).IsTrue()
+	Expect(migrations[0].Checksum).HasLen(64)
+	Expect(migrations[0].AppliedAt).IsNotNil()
+	Expect(migrations[1].FileName).Equals("210001010001_insert_bar.sql")
+	Expect(migrations[1].Status).Equals(dbx.MigrationPending)
+	Expect(migrations[1].AppliedAt).IsNil()
+}
+
+func TestMigrate_ModifiedMigration(t *testing.T) {
+	setupMigrationTest(t)
+	ctx := context.Background()
+
+	err := dbx.Migrate(ctx, "/app/pkg/dbx/testdata/migration_reversible")
+	Expect(err).IsNil()
+
+	trx, _ := dbx.BeginTx(ctx)
+	_, err = trx.Execute("UPDATE migrations_history SET checksum = 'edited' WHERE version = 210001010000")
+	Expect(err).IsNil()
+	trx.MustCommit()
+
+	migrations := testMigrationStatus(ctx, "/app/pkg/dbx/testdata/migration_reversible")
+	Expect(migrations[0].Status).Equals(dbx.MigrationModified)
+	Expect(migrations[1].Status).Equals(dbx.MigrationApplied)
+
+	err = dbx.Migrate(ctx, "/app/pkg/dbx/testdata/migration_reversible")
+	Expect(errors.Cause(err)).Equals(dbx.ErrMigrationModified)
+}
@@ -0,0 +1,4 @@
+create table bar (
+  id    int not null,
+  name  varchar(200) not null
+);
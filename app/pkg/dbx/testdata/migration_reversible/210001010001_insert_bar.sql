@@ -0,0 +1,2 @@
+insert into bar (id, name) values (1, 'Bar 1');
+insert into bar (id, name) values (2, 'Bar 2');
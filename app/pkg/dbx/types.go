@@ -43,3 +43,16 @@ func (r NullTime) MarshalJSON() ([]byte, error) {
 	}
 	return json.Marshal(nil)
 }
+
+// NullFloat representa a nullable float
+type NullFloat struct {
+	sql.NullFloat64
+}
+
+// MarshalJSON interface redefinition
+func (r NullFloat) MarshalJSON() ([]byte, error) {
+	if r.Valid {
+		return json.Marshal(r.Float64)
+	}
+	return json.Marshal(nil)
+}
@@ -0,0 +1,71 @@
+package diff
+
+import "strings"
+
+// Op is the kind of change of a line
+type Op string
+
+const (
+	Equal  Op = "equal"
+	Insert Op = "insert"
+	Delete Op = "delete"
+)
+
+// Line is a line of a diff
+type Line struct {
+	Op   Op     `json:"op"`
+	Text string `json:"text"`
+}
+
+// Lines returns the line by line changes to turn text a into text b,
+// based on the longest common subsequence of lines.
+// Deleted lines come before inserted lines on each change
+func Lines(a, b string) []Line {
+	x := split(a)
+	y := split(b)
+
+	// lcs[i][j] is the length of the longest common subsequence of x[i:] and y[j:]
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	result := make([]Line, 0, max(len(x), len(y)))
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		if x[i] == y[j] {
+			result = append(result, Line{Op: Equal, Text: x[i]})
+			i++
+			j++
+		} else if lcs[i+1][j] >= lcs[i][j+1] {
+			result = append(result, Line{Op: Delete, Text: x[i]})
+			i++
+		} else {
+			result = append(result, Line{Op: Insert, Text: y[j]})
+			j++
+		}
+	}
+	for ; i < len(x); i++ {
+		result = append(result, Line{Op: Delete, Text: x[i]})
+	}
+	for ; j < len(y); j++ {
+		result = append(result, Line{Op: Insert, Text: y[j]})
+	}
+	return result
+}
+
+func split(text string) []string {
+	if text == "" {
+		return []string{}
+	}
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
@@ -0,0 +1,42 @@
+package diff_test
+
+import (
+	"testing"
+
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/diff"
+)
+
+func TestLines_Unchanged(t *testing.T) {
+	RegisterT(t)
+
+	Expect(diff.Lines("Hello\nWorld", "Hello\nWorld")).Equals([]diff.Line{
+		{Op: diff.Equal, Text: "Hello"},
+		{Op: diff.Equal, Text: "World"},
+	})
+}
+
+func TestLines_Changes(t *testing.T) {
+	RegisterT(t)
+
+	Expect(diff.Lines("Add dark mode\nIt hurts my eyes\nThanks", "Add dark mode\nIt is easier to read\nThanks\nPS: light mode too")).Equals([]diff.Line{
+		{Op: diff.Equal, Text: "Add dark mode"},
+		{Op: diff.Delete, Text: "It hurts my eyes"},
+		{Op: diff.Insert, Text: "It is easier to read"},
+		{Op: diff.Equal, Text: "Thanks"},
+		{Op: diff.Insert, Text: "PS: light mode too"},
+	})
+}
+
+func TestLines_Empty(t *testing.T) {
+	RegisterT(t)
+
+	Expect(diff.Lines("", "")).HasLen(0)
+	Expect(diff.Lines("", "New\r\nLines")).Equals([]diff.Line{
+		{Op: diff.Insert, Text: "New"},
+		{Op: diff.Insert, Text: "Lines"},
+	})
+	Expect(diff.Lines("Old", "")).Equals([]diff.Line{
+		{Op: diff.Delete, Text: "Old"},
+	})
+}
@@ -145,8 +145,17 @@ type config struct {
 		Message string `env:"MAINTENANCE_MESSAGE"`
 		Until   string `env:"MAINTENANCE_UNTIL"`
 	}
+	Worker struct {
+		Type        string `env:"WORKER,default=memory"` // possible values: memory or database
+		MaxAttempts int    `env:"WORKER_MAX_ATTEMPTS,default=5,strict"`
+	}
+	Backup struct {
+		Schedule  string `env:"BACKUP_SCHEDULE,default=0 0 3 * * *"` // every day at 03:00
+		Retention int    `env:"BACKUP_RETENTION,default=7,strict"`   // number of archives kept per site, 0 disables scheduled backups
+	}
 	Webhook struct {
 		DisableOnFailure bool `env:"WEBHOOK_DISABLE_ON_FAILURE,default=true"`
+		MaxAttempts      int  `env:"WEBHOOK_MAX_ATTEMPTS,default=8,strict"`
 	}
 	GoogleAnalytics  string `env:"GOOGLE_ANALYTICS"`
 	SearchNoiseWords string `env:"SEARCH_NOISE_WORDS,default=add|support|for|implement|create|make|allow|enable|provide|some|also|include|very|make|and|for|to|a|able|function|feature|app"`
@@ -0,0 +1,165 @@
+package importer
+
+import (
+	"bytes"
+	gocsv "encoding/csv"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Column names used by feedback tools when exporting posts, mapped to the fields of a post
+var csvColumns = map[string]string{
+	"title":                  "title",
+	"post title":             "title",
+	"idea title":             "title",
+	"suggestion title":       "title",
+	"subject":                "title",
+	"description":            "description",
+	"details":                "description",
+	"body":                   "description",
+	"content":                "description",
+	"text":                   "description",
+	"suggestion description": "description",
+	"author email":           "authorEmail",
+	"creator email":          "authorEmail",
+	"user email":             "authorEmail",
+	"submitter email":        "authorEmail",
+	"email":                  "authorEmail",
+	"author name":            "authorName",
+	"author":                 "authorName",
+	"creator name":           "authorName",
+	"user name":              "authorName",
+	"submitter name":         "authorName",
+	"created at":             "createdAt",
+	"created":                "createdAt",
+	"created date":           "createdAt",
+	"creation date":          "createdAt",
+	"submitted at":           "createdAt",
+	"date":                   "createdAt",
+	"status":                 "status",
+	"state":                  "status",
+	"response":               "response",
+	"official response":      "response",
+	"admin response":         "response",
+	"status message":         "response",
+	"responded at":           "respondedAt",
+	"response date":          "respondedAt",
+	"status changed at":      "respondedAt",
+	"tags":                   "tags",
+	"labels":                 "tags",
+	"categories":             "tags",
+	"category":               "tags",
+	"voters":                 "voters",
+	"voter emails":           "voters",
+	"votes emails":           "voters",
+}
+
+var csvDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"01/02/2006 15:04:05",
+	"01/02/2006 15:04",
+	"01/02/2006",
+}
+
+var tagsSeparator = regexp.MustCompile(`[,;|]`)
+var votersSeparator = regexp.MustCompile(`[\s,;|]+`)
+
+// parseCSV reads a CSV file with a header row and one post per row.
+// Dates without a time zone are read as UTC and comments can't be imported from CSV files
+func parseCSV(content []byte) (*Data, error) {
+	reader := gocsv.NewReader(bytes.NewReader(content))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV file: %w", err)
+	}
+
+	if len(records) == 0 {
+		return nil, fmt.Errorf("CSV file is empty")
+	}
+
+	fields := make(map[int]string)
+	hasTitle := false
+	for i, column := range records[0] {
+		column = strings.ToLower(strings.TrimSpace(strings.NewReplacer("_", " ", "-", " ").Replace(column)))
+		if field, ok := csvColumns[column]; ok {
+			fields[i] = field
+			hasTitle = hasTitle || field == "title"
+		}
+	}
+
+	if !hasTitle {
+		return nil, fmt.Errorf("CSV file must have a 'title' column")
+	}
+
+	data := &Data{Posts: make([]*Post, 0, len(records)-1)}
+	for i, record := range records[1:] {
+		line := i + 2
+		post := &Post{}
+		for j, value := range record {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+
+			switch fields[j] {
+			case "title":
+				post.Title = value
+			case "description":
+				post.Description = value
+			case "authorEmail":
+				post.Author.Email = value
+			case "authorName":
+				post.Author.Name = value
+			case "status":
+				post.Status = value
+			case "response":
+				post.Response = value
+			case "createdAt":
+				if post.CreatedAt, err = parseDate(value); err != nil {
+					return nil, fmt.Errorf("line %d: '%s' is not a valid date", line, value)
+				}
+			case "respondedAt":
+				respondedAt, err := parseDate(value)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: '%s' is not a valid date", line, value)
+				}
+				post.RespondedAt = &respondedAt
+			case "tags":
+				for _, tag := range tagsSeparator.Split(value, -1) {
+					if tag = strings.TrimSpace(tag); tag != "" {
+						post.Tags = append(post.Tags, tag)
+					}
+				}
+			case "voters":
+				for _, email := range votersSeparator.Split(value, -1) {
+					if email != "" {
+						post.Votes = append(post.Votes, &Vote{Author: Author{Email: email}})
+					}
+				}
+			}
+		}
+		data.Posts = append(data.Posts, post)
+	}
+
+	return data, nil
+}
+
+func parseDate(value string) (time.Time, error) {
+	for _, layout := range csvDateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, errors.New("invalid date")
+}
@@ -0,0 +1,358 @@
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/validate"
+	"github.com/gosimple/slug"
+)
+
+// Formats of the files that can be imported
+const (
+	// FormatFider is the generic JSON format described in docs/IMPORTING_POSTS.md
+	FormatFider = "fider"
+	// FormatCSV is a CSV file with one post per row, as exported by most feedback tools
+	FormatCSV = "csv"
+)
+
+// Color of the tags created during an import
+const tagColor = "CCCCCC"
+
+// Data is the content of an import file, every format is converted to it
+type Data struct {
+	Posts []*Post `json:"posts"`
+}
+
+// Post is a post and everything that happened to it on another feedback tool
+type Post struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Author      Author     `json:"author"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	Status      string     `json:"status"`
+	Response    string     `json:"response"`
+	RespondedAt *time.Time `json:"respondedAt"`
+	Tags        []string   `json:"tags"`
+	Votes       []*Vote    `json:"votes"`
+	Comments    []*Comment `json:"comments"`
+}
+
+// Author is the user who created a post, vote or comment. Users are identified by their email
+type Author struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// Vote is a vote placed on a post
+type Vote struct {
+	Author    Author     `json:"author"`
+	CreatedAt *time.Time `json:"createdAt"`
+}
+
+// Comment is a comment written on a post
+type Comment struct {
+	Author    Author     `json:"author"`
+	Content   string     `json:"content"`
+	CreatedAt *time.Time `json:"createdAt"`
+}
+
+// Summary describes what is imported, or would be imported on a dry run
+type Summary struct {
+	DryRun   bool     `json:"dryRun"`
+	Posts    int      `json:"posts"`
+	Votes    int      `json:"votes"`
+	Comments int      `json:"comments"`
+	NewUsers int      `json:"newUsers"`
+	NewTags  int      `json:"newTags"`
+	Errors   []string `json:"errors"`
+}
+
+// Parse converts the content of a file in given format to import data
+func Parse(format string, content []byte) (*Data, error) {
+	switch format {
+	case FormatFider:
+		data := &Data{}
+		if err := json.Unmarshal(content, data); err != nil {
+			return nil, fmt.Errorf("failed to read JSON file: %w", err)
+		}
+		return data, nil
+	case FormatCSV:
+		return parseCSV(content)
+	}
+	return nil, fmt.Errorf("format '%s' is not supported", format)
+}
+
+// Run imports the data into current tenant, in chronological order. Users are created without being invited
+// and posts or comments without an author email are attributed to the user running the import.
+// Nothing is imported on a dry run or when the data has errors, but the summary is returned
+func Run(ctx context.Context, data *Data, dryRun bool) (*Summary, error) {
+	summary := &Summary{DryRun: dryRun, Errors: make([]string, 0)}
+
+	getStatuses := &query.GetCustomPostStatuses{}
+	getTags := &query.GetAllTags{}
+	if err := bus.Dispatch(ctx, getStatuses, getTags); err != nil {
+		return nil, err
+	}
+
+	// Tags are matched by slug, as two names with the same slug can't be stored
+	tags := make(map[string]*entity.Tag)
+	for _, tag := range getTags.Result {
+		tags[tag.Slug] = tag
+	}
+
+	statuses := make(map[*Post]enum.PostStatus, len(data.Posts))
+	emails := make(map[string]string)
+	newTags := make(map[string]bool)
+	for _, post := range data.Posts {
+		label := fmt.Sprintf("post '%s'", post.Title)
+		addError := func(format string, a ...any) {
+			summary.Errors = append(summary.Errors, label+": "+fmt.Sprintf(format, a...))
+		}
+
+		post.Title = strings.TrimSpace(post.Title)
+		if post.Title == "" {
+			addError("title is required")
+		} else if len(post.Title) > 100 {
+			addError("title must have less than 100 characters")
+		}
+		if post.CreatedAt.IsZero() {
+			addError("creation date is required")
+		}
+
+		status, ok := parseStatus(post.Status, getStatuses.Result)
+		if !ok {
+			addError("status '%s' is not supported", post.Status)
+		}
+		statuses[post] = status
+
+		authors := []Author{post.Author}
+		for _, vote := range post.Votes {
+			if vote.Author.Email == "" {
+				addError("votes must have an author email")
+			}
+			authors = append(authors, vote.Author)
+		}
+		for _, comment := range post.Comments {
+			if strings.TrimSpace(comment.Content) == "" {
+				addError("comments must have a content")
+			}
+			authors = append(authors, comment.Author)
+		}
+
+		for _, author := range authors {
+			email := strings.ToLower(strings.TrimSpace(author.Email))
+			if email == "" {
+				continue
+			}
+			if messages := validate.Email(ctx, email); len(messages) > 0 {
+				addError("%s", strings.Join(messages, " "))
+				continue
+			}
+			if emails[email] == "" {
+				emails[email] = strings.TrimSpace(author.Name)
+			}
+		}
+
+		for _, name := range post.Tags {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			if len(name) > 30 {
+				addError("tag '%s' must have less than 30 characters", name)
+				continue
+			}
+			if _, ok := tags[slug.Make(name)]; !ok {
+				newTags[slug.Make(name)] = true
+			}
+		}
+
+		summary.Posts++
+		summary.Votes += len(post.Votes)
+		summary.Comments += len(post.Comments)
+	}
+	summary.NewTags = len(newTags)
+
+	users := make(map[string]*entity.User)
+	for email := range emails {
+		getUser := &query.GetUserByEmail{Email: email}
+		err := bus.Dispatch(ctx, getUser)
+		if err == nil {
+			users[email] = getUser.Result
+		} else if errors.Cause(err) == app.ErrNotFound {
+			summary.NewUsers++
+		} else {
+			return nil, err
+		}
+	}
+
+	if dryRun || len(summary.Errors) > 0 {
+		return summary, nil
+	}
+
+	posts := make([]*Post, len(data.Posts))
+	copy(posts, data.Posts)
+	sort.SliceStable(posts, func(i, j int) bool {
+		return posts[i].CreatedAt.Before(posts[j].CreatedAt)
+	})
+
+	findUser := func(author Author) (*entity.User, error) {
+		email := strings.ToLower(strings.TrimSpace(author.Email))
+		if email == "" {
+			return ctx.Value(app.UserCtxKey).(*entity.User), nil
+		}
+		if user, ok := users[email]; ok {
+			return user, nil
+		}
+
+		name := emails[email]
+		if name == "" {
+			name, _, _ = strings.Cut(email, "@")
+		}
+		user := &entity.User{Name: name, Email: email, Role: enum.RoleVisitor}
+		if err := bus.Dispatch(ctx, &cmd.RegisterUser{User: user}); err != nil {
+			return nil, err
+		}
+		users[email] = user
+		return user, nil
+	}
+
+	for _, post := range posts {
+		author, err := findUser(post.Author)
+		if err != nil {
+			return nil, err
+		}
+
+		respondedAt := post.CreatedAt
+		if post.RespondedAt != nil {
+			respondedAt = *post.RespondedAt
+		}
+
+		importPost := &cmd.ImportPost{
+			Title:       post.Title,
+			Description: post.Description,
+			User:        author,
+			CreatedAt:   post.CreatedAt,
+			Status:      statuses[post],
+			Response:    post.Response,
+			RespondedAt: respondedAt,
+		}
+		if err := bus.Dispatch(ctx, importPost); err != nil {
+			return nil, err
+		}
+
+		for _, name := range post.Tags {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			tag, ok := tags[slug.Make(name)]
+			if !ok {
+				addTag := &cmd.AddNewTag{Name: name, Color: tagColor, IsPublic: true}
+				if err := bus.Dispatch(ctx, addTag); err != nil {
+					return nil, err
+				}
+				tag = addTag.Result
+				tags[slug.Make(name)] = tag
+			}
+			if err := bus.Dispatch(ctx, &cmd.AssignTag{Tag: tag, Post: importPost.Result}); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, vote := range post.Votes {
+			user, err := findUser(vote.Author)
+			if err != nil {
+				return nil, err
+			}
+			if err := bus.Dispatch(ctx, &cmd.ImportVote{
+				Post:      importPost.Result,
+				User:      user,
+				CreatedAt: dateOrDefault(vote.CreatedAt, post.CreatedAt),
+			}); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, comment := range post.Comments {
+			user, err := findUser(comment.Author)
+			if err != nil {
+				return nil, err
+			}
+			if err := bus.Dispatch(ctx, &cmd.ImportComment{
+				Post:      importPost.Result,
+				User:      user,
+				Content:   comment.Content,
+				CreatedAt: dateOrDefault(comment.CreatedAt, post.CreatedAt),
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return summary, nil
+}
+
+func dateOrDefault(date *time.Time, defaultDate time.Time) time.Time {
+	if date == nil || date.IsZero() {
+		return defaultDate
+	}
+	return *date
+}
+
+// Status names used by other feedback tools
+var statusAliases = map[string]enum.PostStatus{
+	"":              enum.PostOpen,
+	"open":          enum.PostOpen,
+	"new":           enum.PostOpen,
+	"pending":       enum.PostOpen,
+	"submitted":     enum.PostOpen,
+	"under review":  enum.PostOpen,
+	"in review":     enum.PostOpen,
+	"considering":   enum.PostOpen,
+	"planned":       enum.PostPlanned,
+	"accepted":      enum.PostPlanned,
+	"next":          enum.PostPlanned,
+	"started":       enum.PostStarted,
+	"in progress":   enum.PostStarted,
+	"working on it": enum.PostStarted,
+	"completed":     enum.PostCompleted,
+	"complete":      enum.PostCompleted,
+	"done":          enum.PostCompleted,
+	"shipped":       enum.PostCompleted,
+	"released":      enum.PostCompleted,
+	"implemented":   enum.PostCompleted,
+	"declined":      enum.PostDeclined,
+	"rejected":      enum.PostDeclined,
+	"closed":        enum.PostDeclined,
+	"not planned":   enum.PostDeclined,
+	"won't do":      enum.PostDeclined,
+}
+
+// parseStatus returns the post status with given name, custom statuses are matched by name or slug
+func parseStatus(name string, customStatuses []*entity.CustomPostStatus) (enum.PostStatus, bool) {
+	name = strings.ToLower(strings.TrimSpace(strings.NewReplacer("_", " ", "-", " ").Replace(name)))
+	if status, ok := statusAliases[name]; ok {
+		return status, true
+	}
+
+	for _, status := range customStatuses {
+		if strings.EqualFold(status.Name, name) || strings.EqualFold(strings.ReplaceAll(status.Slug, "-", " "), name) {
+			return status.Status, true
+		}
+	}
+
+	return enum.PostOpen, false
+}
@@ -0,0 +1,254 @@
+package importer_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/importer"
+)
+
+var admin = &entity.User{ID: 1, Name: "Jon Snow", Email: "jon.snow@got.com", Role: enum.RoleAdministrator}
+
+var jsonExport = `{
+	"posts": [
+		{
+			"title": "Dark mode",
+			"description": "Please add a dark mode",
+			"author": { "name": "Arya Stark", "email": "arya.stark@got.com" },
+			"createdAt": "2018-03-01T10:00:00Z",
+			"status": "completed",
+			"response": "Shipped!",
+			"respondedAt": "2019-01-01T10:00:00Z",
+			"tags": ["ui", "Bug"],
+			"votes": [
+				{ "author": { "email": "sansa.stark@got.com" }, "createdAt": "2018-03-02T10:00:00Z" },
+				{ "author": { "email": "arya.stark@got.com" } }
+			],
+			"comments": [
+				{ "author": { "name": "Sansa", "email": "sansa.stark@got.com" }, "content": "Yes please!", "createdAt": "2018-03-03T10:00:00Z" }
+			]
+		},
+		{
+			"title": "Export to PDF",
+			"createdAt": "2017-01-01T10:00:00Z"
+		}
+	]
+}`
+
+func setupBus(existingEmails ...string) {
+	bus.AddHandler(func(ctx context.Context, q *query.GetCustomPostStatuses) error {
+		q.Result = []*entity.CustomPostStatus{{Status: enum.PostCustomStatusStart, Name: "Under Consideration", Slug: "under-consideration"}}
+		return nil
+	})
+	bus.AddHandler(func(ctx context.Context, q *query.GetAllTags) error {
+		q.Result = []*entity.Tag{{ID: 1, Name: "bug", Slug: "bug"}}
+		return nil
+	})
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByEmail) error {
+		for i, email := range existingEmails {
+			if email == q.Email {
+				q.Result = &entity.User{ID: 100 + i, Email: email}
+				return nil
+			}
+		}
+		return app.ErrNotFound
+	})
+}
+
+func TestParse_Fider(t *testing.T) {
+	RegisterT(t)
+
+	data, err := importer.Parse(importer.FormatFider, []byte(jsonExport))
+	Expect(err).IsNil()
+	Expect(data.Posts).HasLen(2)
+	Expect(data.Posts[0].Title).Equals("Dark mode")
+	Expect(data.Posts[0].Author.Email).Equals("arya.stark@got.com")
+	Expect(data.Posts[0].CreatedAt).Equals(time.Date(2018, 3, 1, 10, 0, 0, 0, time.UTC))
+	Expect(data.Posts[0].Votes).HasLen(2)
+	Expect(data.Posts[0].Votes[1].CreatedAt).IsNil()
+	Expect(data.Posts[0].Comments).HasLen(1)
+}
+
+func TestParse_CSV(t *testing.T) {
+	RegisterT(t)
+
+	data, err := importer.Parse(importer.FormatCSV, []byte(`Title,Details,Author Email,Author Name,Created At,Status,Tags,Voters,Ignored
+Dark mode,Please add a dark mode,arya.stark@got.com,Arya Stark,2018-03-01 10:00:00,In Progress,"ui; bug","sansa.stark@got.com, jon.snow@got.com",x
+Export to PDF,,,,01/15/2017,,,,`))
+	Expect(err).IsNil()
+	Expect(data.Posts).HasLen(2)
+
+	Expect(data.Posts[0].Title).Equals("Dark mode")
+	Expect(data.Posts[0].Description).Equals("Please add a dark mode")
+	Expect(data.Posts[0].Author).Equals(importer.Author{Name: "Arya Stark", Email: "arya.stark@got.com"})
+	Expect(data.Posts[0].CreatedAt).Equals(time.Date(2018, 3, 1, 10, 0, 0, 0, time.UTC))
+	Expect(data.Posts[0].Status).Equals("In Progress")
+	Expect(data.Posts[0].Tags).Equals([]string{"ui", "bug"})
+	Expect(data.Posts[0].Votes).HasLen(2)
+	Expect(data.Posts[0].Votes[1].Author.Email).Equals("jon.snow@got.com")
+
+	Expect(data.Posts[1].Title).Equals("Export to PDF")
+	Expect(data.Posts[1].CreatedAt).Equals(time.Date(2017, 1, 15, 0, 0, 0, 0, time.UTC))
+	Expect(data.Posts[1].Votes).HasLen(0)
+}
+
+func TestParse_CSV_Invalid(t *testing.T) {
+	RegisterT(t)
+
+	_, err := importer.Parse(importer.FormatCSV, []byte("Description\nSomething"))
+	Expect(err).IsNotNil()
+
+	_, err = importer.Parse(importer.FormatCSV, []byte("Title,Created\nSomething,yesterday"))
+	Expect(err).IsNotNil()
+
+	_, err = importer.Parse("unknown", []byte("{}"))
+	Expect(err).IsNotNil()
+}
+
+func TestRun_DryRun(t *testing.T) {
+	RegisterT(t)
+	setupBus("arya.stark@got.com")
+
+	imported := false
+	bus.AddHandler(func(ctx context.Context, c *cmd.ImportPost) error {
+		imported = true
+		return nil
+	})
+
+	data, _ := importer.Parse(importer.FormatFider, []byte(jsonExport))
+	summary, err := importer.Run(context.Background(), data, true)
+	Expect(err).IsNil()
+	Expect(imported).IsFalse()
+	Expect(summary.DryRun).IsTrue()
+	Expect(summary.Posts).Equals(2)
+	Expect(summary.Votes).Equals(2)
+	Expect(summary.Comments).Equals(1)
+	Expect(summary.NewUsers).Equals(1)
+	Expect(summary.NewTags).Equals(1)
+	Expect(summary.Errors).HasLen(0)
+}
+
+func TestRun_InvalidData(t *testing.T) {
+	RegisterT(t)
+	setupBus()
+
+	data := &importer.Data{Posts: []*importer.Post{
+		{Title: "", CreatedAt: time.Now()},
+		{Title: "Dark mode", Status: "wishful thinking"},
+		{Title: "Export to PDF", CreatedAt: time.Now(), Votes: []*importer.Vote{{}}, Author: importer.Author{Email: "not an email"}},
+	}}
+
+	summary, err := importer.Run(context.Background(), data, false)
+	Expect(err).IsNil()
+	Expect(summary.Errors).HasLen(5)
+	Expect(summary.Errors[0]).Equals("post '': title is required")
+	Expect(summary.Errors[1]).Equals("post 'Dark mode': creation date is required")
+	Expect(summary.Errors[2]).Equals("post 'Dark mode': status 'wishful thinking' is not supported")
+}
+
+func TestRun_Tags(t *testing.T) {
+	RegisterT(t)
+	setupBus()
+
+	data := &importer.Data{Posts: []*importer.Post{
+		{Title: "Dark mode", CreatedAt: time.Now(), Tags: []string{"User Interface", "user-interface", "BUG!"}},
+		{Title: "Export to PDF", CreatedAt: time.Now(), Tags: []string{"a tag name that is way too long to be stored"}},
+	}}
+
+	summary, err := importer.Run(context.Background(), data, true)
+	Expect(err).IsNil()
+	Expect(summary.NewTags).Equals(1)
+	Expect(summary.Errors).Equals([]string{
+		"post 'Export to PDF': tag 'a tag name that is way too long to be stored' must have less than 30 characters",
+	})
+}
+
+func TestRun_Import(t *testing.T) {
+	RegisterT(t)
+	setupBus("arya.stark@got.com")
+
+	registered := make([]*entity.User, 0)
+	bus.AddHandler(func(ctx context.Context, c *cmd.RegisterUser) error {
+		c.User.ID = 200 + len(registered)
+		registered = append(registered, c.User)
+		return nil
+	})
+
+	posts := make([]*cmd.ImportPost, 0)
+	bus.AddHandler(func(ctx context.Context, c *cmd.ImportPost) error {
+		c.Result = &entity.Post{ID: len(posts) + 1, Title: c.Title}
+		posts = append(posts, c)
+		return nil
+	})
+
+	newTags := make([]*cmd.AddNewTag, 0)
+	bus.AddHandler(func(ctx context.Context, c *cmd.AddNewTag) error {
+		c.Result = &entity.Tag{ID: 2, Name: c.Name}
+		newTags = append(newTags, c)
+		return nil
+	})
+
+	assigned := make([]*cmd.AssignTag, 0)
+	bus.AddHandler(func(ctx context.Context, c *cmd.AssignTag) error {
+		assigned = append(assigned, c)
+		return nil
+	})
+
+	votes := make([]*cmd.ImportVote, 0)
+	bus.AddHandler(func(ctx context.Context, c *cmd.ImportVote) error {
+		votes = append(votes, c)
+		return nil
+	})
+
+	comments := make([]*cmd.ImportComment, 0)
+	bus.AddHandler(func(ctx context.Context, c *cmd.ImportComment) error {
+		comments = append(comments, c)
+		return nil
+	})
+
+	ctx := context.WithValue(context.Background(), app.UserCtxKey, admin)
+	data, _ := importer.Parse(importer.FormatFider, []byte(jsonExport))
+	summary, err := importer.Run(ctx, data, false)
+	Expect(err).IsNil()
+	Expect(summary.DryRun).IsFalse()
+	Expect(summary.Errors).HasLen(0)
+
+	Expect(registered).HasLen(1)
+	Expect(registered[0].Email).Equals("sansa.stark@got.com")
+	Expect(registered[0].Name).Equals("Sansa")
+	Expect(registered[0].Role).Equals(enum.RoleVisitor)
+
+	// Posts are imported in chronological order
+	Expect(posts).HasLen(2)
+	Expect(posts[0].Title).Equals("Export to PDF")
+	Expect(posts[0].User).Equals(admin)
+	Expect(posts[0].Status).Equals(enum.PostOpen)
+	Expect(posts[1].Title).Equals("Dark mode")
+	Expect(posts[1].User.ID).Equals(100)
+	Expect(posts[1].Status).Equals(enum.PostCompleted)
+	Expect(posts[1].Response).Equals("Shipped!")
+	Expect(posts[1].RespondedAt).Equals(time.Date(2019, 1, 1, 10, 0, 0, 0, time.UTC))
+
+	Expect(newTags).HasLen(1)
+	Expect(newTags[0].Name).Equals("ui")
+	Expect(assigned).HasLen(2)
+	Expect(assigned[1].Tag.Slug).Equals("bug")
+
+	Expect(votes).HasLen(2)
+	Expect(votes[0].User.ID).Equals(200)
+	Expect(votes[0].CreatedAt).Equals(time.Date(2018, 3, 2, 10, 0, 0, 0, time.UTC))
+	Expect(votes[1].User.ID).Equals(100)
+	Expect(votes[1].CreatedAt).Equals(time.Date(2018, 3, 1, 10, 0, 0, 0, time.UTC))
+
+	Expect(comments).HasLen(1)
+	Expect(comments[0].Content).Equals("Yes please!")
+	Expect(comments[0].User.ID).Equals(200)
+}
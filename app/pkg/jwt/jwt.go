@@ -49,6 +49,20 @@ type OAuthStateClaims struct {
 	Redirect   string `json:"oauthstate/redirect"`
 	Identifier string `json:"oauthstate/identifier"`
 	Code       string `json:"oauthstate/code"`
+	Nonce      string `json:"oauthstate/nonce,omitempty"`
+	TenantID   int    `json:"oauthstate/tenant_id,omitempty"`
+	Metadata
+}
+
+// SAMLClaims represents what goes into temporary JWT tokens used to complete a SAML sign in
+type SAMLClaims struct {
+	NameID     string   `json:"saml/name_id"`
+	Name       string   `json:"saml/name"`
+	Email      string   `json:"saml/email"`
+	Roles      []string `json:"saml/roles"`
+	Redirect   string   `json:"saml/redirect"`
+	Identifier string   `json:"saml/identifier"`
+	TenantID   int      `json:"saml/tenant_id"`
 	Metadata
 }
 
@@ -92,6 +106,16 @@ func DecodeOAuthStateClaims(token string) (*OAuthStateClaims, error) {
 	return claims, nil
 }
 
+// DecodeSAMLClaims extract SAMLClaims from given JWT token
+func DecodeSAMLClaims(token string) (*SAMLClaims, error) {
+	claims := &SAMLClaims{}
+	err := decode(token, claims)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode SAML claims")
+	}
+	return claims, nil
+}
+
 func decode(token string, claims jwtgo.Claims) error {
 	jwtToken, err := jwtgo.ParseWithClaims(token, claims, func(t *jwtgo.Token) (any, error) {
 		if _, ok := t.Method.(*jwtgo.SigningMethodHMAC); !ok {
@@ -0,0 +1,168 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/getfider/fider/app/pkg/errors"
+	jwtgo "github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	// cacheDuration is how long discovery documents and key sets are kept before being downloaded again
+	cacheDuration = 1 * time.Hour
+	// minRefreshInterval limits how often a key set is downloaded again because of an unknown key ID,
+	// so that tokens with made-up key IDs can't be used to flood the provider
+	minRefreshInterval = 1 * time.Minute
+	// clockSkew is how much the clocks of Fider and the provider are allowed to differ
+	clockSkew = 2 * time.Minute
+)
+
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// Fetcher downloads the document at given URL
+type Fetcher func(ctx context.Context, url string) ([]byte, error)
+
+type cachedDiscovery struct {
+	discovery *Discovery
+	fetchedAt time.Time
+}
+
+type cachedKeySet struct {
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+// Client discovers OpenID Providers and verifies their ID tokens, caching discovery documents and key sets
+type Client struct {
+	fetch Fetcher
+	now   func() time.Time
+
+	mu          sync.Mutex
+	discoveries map[string]*cachedDiscovery
+	keySets     map[string]*cachedKeySet
+}
+
+// NewClient creates a new Client that downloads documents using given fetcher
+func NewClient(fetch Fetcher) *Client {
+	return &Client{
+		fetch:       fetch,
+		now:         time.Now,
+		discoveries: make(map[string]*cachedDiscovery),
+		keySets:     make(map[string]*cachedKeySet),
+	}
+}
+
+// Discover returns the OpenID Provider metadata of given issuer
+func (c *Client) Discover(ctx context.Context, issuer string) (*Discovery, error) {
+	issuer = NormalizeIssuer(issuer)
+
+	c.mu.Lock()
+	cached, ok := c.discoveries[issuer]
+	c.mu.Unlock()
+	if ok && c.now().Sub(cached.fetchedAt) < cacheDuration {
+		return cached.discovery, nil
+	}
+
+	body, err := c.fetch(ctx, DiscoveryURL(issuer))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to download OpenID configuration of '%s'", issuer)
+	}
+
+	discovery, err := ParseDiscovery(issuer, body)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.discoveries[issuer] = &cachedDiscovery{discovery: discovery, fetchedAt: c.now()}
+	c.mu.Unlock()
+	return discovery, nil
+}
+
+// publicKey returns the key with given ID from the key set at jwksURI.
+// The key set is downloaded again when the key is unknown, as providers rotate their keys
+func (c *Client) publicKey(ctx context.Context, jwksURI, kid string) (crypto.PublicKey, error) {
+	c.mu.Lock()
+	cached, ok := c.keySets[jwksURI]
+	c.mu.Unlock()
+
+	if ok {
+		age := c.now().Sub(cached.fetchedAt)
+		if key, found := cached.keys[kid]; found && age < cacheDuration {
+			return key, nil
+		}
+		if age < minRefreshInterval {
+			return nil, errors.New("unknown key '%s'", kid)
+		}
+	}
+
+	body, err := c.fetch(ctx, jwksURI)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to download JWKS")
+	}
+
+	keys, err := ParseJWKS(body)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.keySets[jwksURI] = &cachedKeySet{keys: keys, fetchedAt: c.now()}
+	c.mu.Unlock()
+
+	if key, found := keys[kid]; found {
+		return key, nil
+	}
+
+	// Providers with a single key are allowed to omit the key ID
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, nil
+		}
+	}
+
+	return nil, errors.New("unknown key '%s'", kid)
+}
+
+// VerifyIDToken checks the signature and claims of an ID token issued by given provider to clientID for the request with given nonce
+// and returns its claims
+func (c *Client) VerifyIDToken(ctx context.Context, discovery *Discovery, rawToken, clientID, nonce string) (map[string]any, error) {
+	parser := jwtgo.NewParser(jwtgo.WithValidMethods(signingMethods), jwtgo.WithoutClaimsValidation())
+	claims := jwtgo.MapClaims{}
+	_, err := parser.ParseWithClaims(rawToken, claims, func(token *jwtgo.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return c.publicKey(ctx, discovery.JWKSURI, kid)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid ID token")
+	}
+
+	now := c.now()
+	if !claims.VerifyIssuer(discovery.Issuer, true) {
+		return nil, errors.New("ID token was not issued by '%s'", discovery.Issuer)
+	}
+	if !claims.VerifyAudience(clientID, true) {
+		return nil, errors.New("ID token is not intended for client '%s'", clientID)
+	}
+	if azp, ok := claims["azp"].(string); ok && azp != clientID {
+		return nil, errors.New("ID token was authorized for another client")
+	}
+	if !claims.VerifyExpiresAt(now.Add(-clockSkew).Unix(), true) {
+		return nil, errors.New("ID token has expired")
+	}
+	if !claims.VerifyIssuedAt(now.Add(clockSkew).Unix(), false) || !claims.VerifyNotBefore(now.Add(clockSkew).Unix(), false) {
+		return nil, errors.New("ID token is not valid yet")
+	}
+	if tokenNonce, _ := claims["nonce"].(string); nonce == "" || tokenNonce != nonce {
+		return nil, errors.New("ID token nonce does not match the request")
+	}
+	if sub := fmt.Sprint(claims["sub"]); claims["sub"] == nil || sub == "" {
+		return nil, errors.New("ID token has no subject")
+	}
+
+	return claims, nil
+}
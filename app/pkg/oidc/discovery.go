@@ -0,0 +1,49 @@
+package oidc
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/getfider/fider/app/pkg/errors"
+)
+
+const discoveryPath = "/.well-known/openid-configuration"
+
+// Discovery is the subset of the OpenID Provider metadata Fider needs to sign users in
+type Discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// NormalizeIssuer returns the issuer for given issuer or discovery URL, without trailing slash
+func NormalizeIssuer(issuer string) string {
+	issuer = strings.TrimSpace(issuer)
+	issuer = strings.TrimSuffix(issuer, discoveryPath)
+	return strings.TrimSuffix(issuer, "/")
+}
+
+// DiscoveryURL returns the URL of the OpenID Provider metadata document of given issuer
+func DiscoveryURL(issuer string) string {
+	return NormalizeIssuer(issuer) + discoveryPath
+}
+
+// ParseDiscovery parses the OpenID Provider metadata document and checks it belongs to given issuer
+func ParseDiscovery(issuer string, body []byte) (*Discovery, error) {
+	discovery := &Discovery{}
+	if err := json.Unmarshal(body, discovery); err != nil {
+		return nil, errors.Wrap(err, "failed to parse OpenID configuration")
+	}
+
+	if NormalizeIssuer(discovery.Issuer) != NormalizeIssuer(issuer) {
+		return nil, errors.New("OpenID configuration issuer '%s' does not match '%s'", discovery.Issuer, issuer)
+	}
+
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, errors.New("OpenID configuration must have authorization_endpoint, token_endpoint and jwks_uri")
+	}
+
+	return discovery, nil
+}
@@ -0,0 +1,235 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/errors"
+	jwtgo "github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	testIssuer   = "https://idp.example.com"
+	testJWKSURI  = "https://idp.example.com/keys"
+	testClientID = "fider"
+	testNonce    = "nonce-123"
+)
+
+// testProvider is a local stand-in for an OpenID Provider
+type testProvider struct {
+	keys      map[string]*rsa.PrivateKey
+	downloads map[string]int
+}
+
+func newTestProvider(kids ...string) *testProvider {
+	p := &testProvider{keys: make(map[string]*rsa.PrivateKey), downloads: make(map[string]int)}
+	for _, kid := range kids {
+		p.rotate(kid)
+	}
+	return p
+}
+
+func (p *testProvider) rotate(kid string) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	p.keys[kid] = key
+}
+
+func (p *testProvider) fetch(ctx context.Context, url string) ([]byte, error) {
+	p.downloads[url]++
+	switch url {
+	case testIssuer + "/.well-known/openid-configuration":
+		return json.Marshal(Discovery{
+			Issuer:                testIssuer,
+			AuthorizationEndpoint: testIssuer + "/authorize",
+			TokenEndpoint:         testIssuer + "/token",
+			JWKSURI:               testJWKSURI,
+		})
+	case testJWKSURI:
+		keys := make([]map[string]string, 0)
+		for kid, key := range p.keys {
+			keys = append(keys, map[string]string{
+				"kid": kid,
+				"kty": "RSA",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+		return json.Marshal(map[string]any{"keys": keys})
+	}
+	return nil, errors.New("not found")
+}
+
+func (p *testProvider) idToken(kid string, claims jwtgo.MapClaims) string {
+	token := jwtgo.NewWithClaims(jwtgo.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, _ := token.SignedString(p.keys[kid])
+	return signed
+}
+
+func validClaims() jwtgo.MapClaims {
+	return jwtgo.MapClaims{
+		"iss":   testIssuer,
+		"aud":   testClientID,
+		"sub":   "user-1",
+		"nonce": testNonce,
+		"exp":   time.Now().Add(5 * time.Minute).Unix(),
+		"iat":   time.Now().Unix(),
+		"email": "jon.snow@got.com",
+	}
+}
+
+func TestClient_Discover(t *testing.T) {
+	RegisterT(t)
+
+	provider := newTestProvider("k1")
+	client := NewClient(provider.fetch)
+
+	discovery, err := client.Discover(context.Background(), "https://idp.example.com/.well-known/openid-configuration")
+	Expect(err).IsNil()
+	Expect(discovery.TokenEndpoint).Equals("https://idp.example.com/token")
+
+	_, err = client.Discover(context.Background(), "https://idp.example.com/")
+	Expect(err).IsNil()
+	Expect(provider.downloads[DiscoveryURL(testIssuer)]).Equals(1)
+}
+
+func TestParseDiscovery_WrongIssuer(t *testing.T) {
+	RegisterT(t)
+
+	_, err := ParseDiscovery(testIssuer, []byte(`{"issuer":"https://evil.com","authorization_endpoint":"a","token_endpoint":"b","jwks_uri":"c"}`))
+	Expect(err).IsNotNil()
+
+	_, err = ParseDiscovery(testIssuer, []byte(`{"issuer":"https://idp.example.com"}`))
+	Expect(err).IsNotNil()
+}
+
+func TestClient_VerifyIDToken(t *testing.T) {
+	RegisterT(t)
+
+	provider := newTestProvider("k1")
+	client := NewClient(provider.fetch)
+	discovery, _ := client.Discover(context.Background(), testIssuer)
+
+	claims, err := client.VerifyIDToken(context.Background(), discovery, provider.idToken("k1", validClaims()), testClientID, testNonce)
+	Expect(err).IsNil()
+	Expect(claims["sub"]).Equals("user-1")
+	Expect(claims["email"]).Equals("jon.snow@got.com")
+
+	_, err = client.VerifyIDToken(context.Background(), discovery, provider.idToken("k1", validClaims()), testClientID, testNonce)
+	Expect(err).IsNil()
+	Expect(provider.downloads[testJWKSURI]).Equals(1)
+}
+
+func TestClient_VerifyIDToken_InvalidClaims(t *testing.T) {
+	RegisterT(t)
+
+	provider := newTestProvider("k1")
+	client := NewClient(provider.fetch)
+	discovery, _ := client.Discover(context.Background(), testIssuer)
+
+	for key, value := range map[string]any{
+		"iss":   "https://evil.com",
+		"aud":   "another-client",
+		"azp":   "another-client",
+		"exp":   time.Now().Add(-5 * time.Minute).Unix(),
+		"nbf":   time.Now().Add(5 * time.Minute).Unix(),
+		"nonce": "another-nonce",
+		"sub":   "",
+	} {
+		claims := validClaims()
+		claims[key] = value
+		_, err := client.VerifyIDToken(context.Background(), discovery, provider.idToken("k1", claims), testClientID, testNonce)
+		Expect(err).IsNotNil()
+	}
+
+	claims := validClaims()
+	delete(claims, "exp")
+	_, err := client.VerifyIDToken(context.Background(), discovery, provider.idToken("k1", claims), testClientID, testNonce)
+	Expect(err).IsNotNil()
+}
+
+func TestClient_VerifyIDToken_InvalidSignature(t *testing.T) {
+	RegisterT(t)
+
+	provider := newTestProvider("k1")
+	client := NewClient(provider.fetch)
+	discovery, _ := client.Discover(context.Background(), testIssuer)
+
+	other := newTestProvider("k1")
+	_, err := client.VerifyIDToken(context.Background(), discovery, other.idToken("k1", validClaims()), testClientID, testNonce)
+	Expect(err).IsNotNil()
+
+	hmacToken, _ := jwtgo.NewWithClaims(jwtgo.SigningMethodHS256, validClaims()).SignedString([]byte("secret"))
+	_, err = client.VerifyIDToken(context.Background(), discovery, hmacToken, testClientID, testNonce)
+	Expect(err).IsNotNil()
+}
+
+func TestClient_VerifyIDToken_KeyRotation(t *testing.T) {
+	RegisterT(t)
+
+	now := time.Now()
+	provider := newTestProvider("k1")
+	client := NewClient(provider.fetch)
+	client.now = func() time.Time { return now }
+	discovery, _ := client.Discover(context.Background(), testIssuer)
+
+	_, err := client.VerifyIDToken(context.Background(), discovery, provider.idToken("k1", validClaims()), testClientID, testNonce)
+	Expect(err).IsNil()
+
+	// Unknown keys don't trigger a download right after the previous one
+	provider.rotate("k2")
+	_, err = client.VerifyIDToken(context.Background(), discovery, provider.idToken("k2", validClaims()), testClientID, testNonce)
+	Expect(err).IsNotNil()
+	Expect(provider.downloads[testJWKSURI]).Equals(1)
+
+	now = now.Add(2 * time.Minute)
+	_, err = client.VerifyIDToken(context.Background(), discovery, provider.idToken("k2", validClaims()), testClientID, testNonce)
+	Expect(err).IsNil()
+	Expect(provider.downloads[testJWKSURI]).Equals(2)
+}
+
+func TestParseJWKS(t *testing.T) {
+	RegisterT(t)
+
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	body, _ := json.Marshal(map[string]any{
+		"keys": []map[string]string{
+			{
+				"kid": "ec",
+				"kty": "EC",
+				"crv": "P-256",
+				"x":   base64.RawURLEncoding.EncodeToString(ecKey.X.Bytes()),
+				"y":   base64.RawURLEncoding.EncodeToString(ecKey.Y.Bytes()),
+			},
+			{"kid": "enc", "kty": "RSA", "use": "enc", "n": "AQAB", "e": "AQAB"},
+			{"kid": "okp", "kty": "OKP"},
+		},
+	})
+
+	keys, err := ParseJWKS(body)
+	Expect(err).IsNil()
+	Expect(keys).HasLen(1)
+	Expect(keys["ec"].(*ecdsa.PublicKey).Equal(&ecKey.PublicKey)).IsTrue()
+}
+
+func TestPKCE(t *testing.T) {
+	RegisterT(t)
+
+	verifier := NewCodeVerifier("secret", "seed")
+	Expect(verifier).Equals(NewCodeVerifier("secret", "seed"))
+	Expect(verifier).NotEquals(NewCodeVerifier("secret", "another-seed"))
+	Expect(verifier).NotEquals(NewCodeVerifier("another-secret", "seed"))
+	Expect(len(verifier) >= 43).IsTrue()
+
+	Expect(CodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gXk9gW3Ts")).Equals("vuze529tsUX4ueH8PeS8YMi0zn93zdpier-0z956u3o")
+}
@@ -0,0 +1,22 @@
+package oidc
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// NewCodeVerifier derives the PKCE code verifier of an authorization request from a server secret and a per-request seed.
+// The seed travels in the state parameter, so the verifier can be recomputed when the code is exchanged
+// without storing it, while someone intercepting the code and the state still can't compute it
+func NewCodeVerifier(secret, seed string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("pkce:" + seed))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// CodeChallenge returns the S256 code challenge of given code verifier
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,140 @@
+package saml
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"encoding/xml"
+	"strings"
+
+	"github.com/getfider/fider/app/pkg/errors"
+)
+
+const (
+	nsMetadata  = "urn:oasis:names:tc:SAML:2.0:metadata"
+	nsAssertion = "urn:oasis:names:tc:SAML:2.0:assertion"
+	nsProtocol  = "urn:oasis:names:tc:SAML:2.0:protocol"
+	nsSignature = "http://www.w3.org/2000/09/xmldsig#"
+
+	bindingHTTPRedirect = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"
+	bindingHTTPPost     = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+
+	nameIDFormatUnspecified = "urn:oasis:names:tc:SAML:1.1:nameid-format:unspecified"
+	statusSuccess           = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	confirmationBearer      = "urn:oasis:names:tc:SAML:2.0:cm:bearer"
+)
+
+// IdPMetadata is what Fider needs to know about an Identity Provider to sign users in
+type IdPMetadata struct {
+	EntityID    string
+	SSOURL      string
+	Certificate string
+}
+
+type entityDescriptor struct {
+	XMLName          xml.Name          `xml:"urn:oasis:names:tc:SAML:2.0:metadata EntityDescriptor"`
+	EntityID         string            `xml:"entityID,attr"`
+	IDPSSODescriptor *idpSSODescriptor `xml:"urn:oasis:names:tc:SAML:2.0:metadata IDPSSODescriptor"`
+}
+
+type idpSSODescriptor struct {
+	KeyDescriptors []struct {
+		Use         string `xml:"use,attr"`
+		Certificate string `xml:"http://www.w3.org/2000/09/xmldsig# KeyInfo>X509Data>X509Certificate"`
+	} `xml:"urn:oasis:names:tc:SAML:2.0:metadata KeyDescriptor"`
+	SingleSignOnServices []struct {
+		Binding  string `xml:"Binding,attr"`
+		Location string `xml:"Location,attr"`
+	} `xml:"urn:oasis:names:tc:SAML:2.0:metadata SingleSignOnService"`
+}
+
+// ParseIdPMetadata extracts the entity ID, the HTTP-Redirect SSO URL and the signing certificate from IdP metadata
+func ParseIdPMetadata(data []byte) (*IdPMetadata, error) {
+	descriptor := &entityDescriptor{}
+	if err := xml.Unmarshal(data, descriptor); err != nil {
+		return nil, errors.Wrap(err, "failed to parse IdP metadata")
+	}
+
+	if descriptor.EntityID == "" || descriptor.IDPSSODescriptor == nil {
+		return nil, errors.New("IdP metadata must have an entityID and an IDPSSODescriptor")
+	}
+
+	metadata := &IdPMetadata{EntityID: descriptor.EntityID}
+	for _, service := range descriptor.IDPSSODescriptor.SingleSignOnServices {
+		if service.Binding == bindingHTTPRedirect {
+			metadata.SSOURL = service.Location
+			break
+		}
+	}
+
+	for _, key := range descriptor.IDPSSODescriptor.KeyDescriptors {
+		if key.Use == "" || key.Use == "signing" {
+			metadata.Certificate = strings.Join(strings.Fields(key.Certificate), "")
+			break
+		}
+	}
+
+	if metadata.SSOURL == "" {
+		return nil, errors.New("IdP metadata has no SingleSignOnService with HTTP-Redirect binding")
+	}
+
+	if _, err := ParseCertificate(metadata.Certificate); err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
+// ParseCertificate parses a certificate either in PEM format or as base64 encoded DER, as found in metadata files
+func ParseCertificate(certificate string) (*x509.Certificate, error) {
+	var der []byte
+	if block, _ := pem.Decode([]byte(certificate)); block != nil {
+		der = block.Bytes
+	} else {
+		decoded, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(certificate), ""))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode IdP certificate")
+		}
+		der = decoded
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse IdP certificate")
+	}
+	return cert, nil
+}
+
+type spEntityDescriptor struct {
+	XMLName         xml.Name        `xml:"urn:oasis:names:tc:SAML:2.0:metadata EntityDescriptor"`
+	EntityID        string          `xml:"entityID,attr"`
+	SPSSODescriptor spSSODescriptor `xml:"SPSSODescriptor"`
+}
+
+type spSSODescriptor struct {
+	AuthnRequestsSigned        bool   `xml:"AuthnRequestsSigned,attr"`
+	WantAssertionsSigned       bool   `xml:"WantAssertionsSigned,attr"`
+	ProtocolSupportEnumeration string `xml:"protocolSupportEnumeration,attr"`
+	NameIDFormat               string `xml:"NameIDFormat"`
+	AssertionConsumerService   struct {
+		Binding  string `xml:"Binding,attr"`
+		Location string `xml:"Location,attr"`
+		Index    int    `xml:"index,attr"`
+	} `xml:"AssertionConsumerService"`
+}
+
+// ServiceProviderMetadata returns the metadata document describing Fider as a Service Provider
+func ServiceProviderMetadata(entityID, acsURL string) ([]byte, error) {
+	descriptor := spEntityDescriptor{EntityID: entityID}
+	descriptor.SPSSODescriptor.WantAssertionsSigned = true
+	descriptor.SPSSODescriptor.ProtocolSupportEnumeration = nsProtocol
+	descriptor.SPSSODescriptor.NameIDFormat = nameIDFormatUnspecified
+	descriptor.SPSSODescriptor.AssertionConsumerService.Binding = bindingHTTPPost
+	descriptor.SPSSODescriptor.AssertionConsumerService.Location = acsURL
+
+	data, err := xml.MarshalIndent(descriptor, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate SP metadata")
+	}
+	return append([]byte(xml.Header), data...), nil
+}
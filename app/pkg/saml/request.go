@@ -0,0 +1,78 @@
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"net/url"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/rand"
+)
+
+// AuthnRequest is an authentication request sent to the IdP using the HTTP-Redirect binding
+type AuthnRequest struct {
+	ID         string
+	SSOURL     string
+	SPEntityID string
+	ACSURL     string
+}
+
+// NewAuthnRequest creates an AuthnRequest asking the IdP to post its response to acsURL.
+// The ID of the request should be kept to be matched against InResponseTo of the response
+func NewAuthnRequest(ssoURL, spEntityID, acsURL string) *AuthnRequest {
+	return &AuthnRequest{
+		ID:         "id-" + rand.String(32),
+		SSOURL:     ssoURL,
+		SPEntityID: spEntityID,
+		ACSURL:     acsURL,
+	}
+}
+
+// RedirectURL returns the IdP URL the user should be redirected to, carrying the deflated request and relayState
+func (r *AuthnRequest) RedirectURL(relayState string) (string, error) {
+	doc := etree.NewDocument()
+	request := doc.CreateElement("samlp:AuthnRequest")
+	request.CreateAttr("xmlns:samlp", nsProtocol)
+	request.CreateAttr("xmlns:saml", nsAssertion)
+	request.CreateAttr("ID", r.ID)
+	request.CreateAttr("Version", "2.0")
+	request.CreateAttr("IssueInstant", time.Now().UTC().Format(time.RFC3339))
+	request.CreateAttr("Destination", r.SSOURL)
+	request.CreateAttr("AssertionConsumerServiceURL", r.ACSURL)
+	request.CreateAttr("ProtocolBinding", bindingHTTPPost)
+	request.CreateElement("saml:Issuer").SetText(r.SPEntityID)
+	policy := request.CreateElement("samlp:NameIDPolicy")
+	policy.CreateAttr("Format", nameIDFormatUnspecified)
+	policy.CreateAttr("AllowCreate", "true")
+
+	raw, err := doc.WriteToBytes()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to write AuthnRequest")
+	}
+
+	var compressed bytes.Buffer
+	writer, _ := flate.NewWriter(&compressed, flate.DefaultCompression)
+	if _, err := writer.Write(raw); err != nil {
+		return "", errors.Wrap(err, "failed to compress AuthnRequest")
+	}
+	if err := writer.Close(); err != nil {
+		return "", errors.Wrap(err, "failed to compress AuthnRequest")
+	}
+
+	redirectURL, err := url.Parse(r.SSOURL)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse IdP SSO URL")
+	}
+
+	query := redirectURL.Query()
+	query.Set("SAMLRequest", base64.StdEncoding.EncodeToString(compressed.Bytes()))
+	if relayState != "" {
+		query.Set("RelayState", relayState)
+	}
+	redirectURL.RawQuery = query.Encode()
+
+	return redirectURL.String(), nil
+}
@@ -0,0 +1,283 @@
+package saml
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/getfider/fider/app/pkg/errors"
+	dsig "github.com/russellhaering/goxmldsig"
+)
+
+// clockSkew is how much the clocks of Fider and the IdP are allowed to differ
+const clockSkew = 3 * time.Minute
+
+// Assertion is what the IdP asserts about the user signing in
+type Assertion struct {
+	ID         string
+	NameID     string
+	Attributes map[string][]string
+}
+
+// Attribute returns the first value of the attribute with given name, or an empty string
+func (a *Assertion) Attribute(name string) string {
+	if values := a.Attributes[name]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// ResponseOptions are the expectations a SAML Response must meet to be accepted
+type ResponseOptions struct {
+	IdPEntityID  string
+	Certificate  string
+	SPEntityID   string
+	ACSURL       string
+	InResponseTo string
+	Now          time.Time
+}
+
+// ParseResponse validates a base64 encoded SAML Response posted by the IdP and returns its assertion.
+// Either the Response or its Assertion must be signed by the IdP certificate, and only the signed content is read
+func ParseResponse(encoded string, opts ResponseOptions) (*Assertion, error) {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode SAML Response")
+	}
+
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(raw); err != nil {
+		return nil, errors.Wrap(err, "failed to parse SAML Response")
+	}
+
+	response := doc.Root()
+	if response == nil || !is(response, nsProtocol, "Response") {
+		return nil, errors.New("SAML Response root element must be samlp:Response")
+	}
+
+	cert, err := ParseCertificate(opts.Certificate)
+	if err != nil {
+		return nil, err
+	}
+
+	validation := dsig.NewDefaultValidationContext(&dsig.MemoryX509CertificateStore{
+		Roots: []*x509.Certificate{cert},
+	})
+	validation.Clock = dsig.NewFakeClockAt(opts.Now)
+
+	var assertion *etree.Element
+	if children(response, nsSignature, "Signature") != nil {
+		signed, err := validation.Validate(response)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid SAML Response signature")
+		}
+		response = signed
+		assertion, err = single(response, nsAssertion, "Assertion")
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		unsigned, err := single(response, nsAssertion, "Assertion")
+		if err != nil {
+			return nil, err
+		}
+		assertion, err = validation.Validate(withNamespaces(unsigned))
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid SAML Assertion signature")
+		}
+	}
+
+	if err := validateResponse(response, opts); err != nil {
+		return nil, err
+	}
+
+	return readAssertion(assertion, opts)
+}
+
+func validateResponse(response *etree.Element, opts ResponseOptions) error {
+	if destination := response.SelectAttrValue("Destination", ""); destination != "" && destination != opts.ACSURL {
+		return errors.New("SAML Response destination '%s' does not match '%s'", destination, opts.ACSURL)
+	}
+
+	if inResponseTo := response.SelectAttrValue("InResponseTo", ""); inResponseTo != "" && inResponseTo != opts.InResponseTo {
+		return errors.New("SAML Response is not for the request that was sent")
+	}
+
+	status, _ := single(response, nsProtocol, "Status")
+	if status == nil {
+		return errors.New("SAML Response has no status")
+	}
+	code, _ := single(status, nsProtocol, "StatusCode")
+	if code == nil || code.SelectAttrValue("Value", "") != statusSuccess {
+		return errors.New("SAML Response status is not success")
+	}
+
+	return nil
+}
+
+func readAssertion(assertion *etree.Element, opts ResponseOptions) (*Assertion, error) {
+	issuer, _ := single(assertion, nsAssertion, "Issuer")
+	if issuer == nil || (opts.IdPEntityID != "" && strings.TrimSpace(issuer.Text()) != opts.IdPEntityID) {
+		return nil, errors.New("SAML Assertion was not issued by '%s'", opts.IdPEntityID)
+	}
+
+	if conditions, _ := single(assertion, nsAssertion, "Conditions"); conditions != nil {
+		if err := validateTimeWindow(conditions, opts.Now); err != nil {
+			return nil, err
+		}
+
+		for _, restriction := range children(conditions, nsAssertion, "AudienceRestriction") {
+			found := false
+			for _, audience := range children(restriction, nsAssertion, "Audience") {
+				found = found || strings.TrimSpace(audience.Text()) == opts.SPEntityID
+			}
+			if !found {
+				return nil, errors.New("SAML Assertion is not intended for '%s'", opts.SPEntityID)
+			}
+		}
+	}
+
+	subject, err := single(assertion, nsAssertion, "Subject")
+	if err != nil {
+		return nil, err
+	}
+
+	nameID, err := single(subject, nsAssertion, "NameID")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateSubjectConfirmation(subject, opts); err != nil {
+		return nil, err
+	}
+
+	result := &Assertion{
+		ID:         assertion.SelectAttrValue("ID", ""),
+		NameID:     strings.TrimSpace(nameID.Text()),
+		Attributes: make(map[string][]string),
+	}
+
+	if result.ID == "" {
+		return nil, errors.New("SAML Assertion has no ID")
+	}
+
+	if result.NameID == "" {
+		return nil, errors.New("SAML Assertion has an empty NameID")
+	}
+
+	for _, statement := range children(assertion, nsAssertion, "AttributeStatement") {
+		for _, attribute := range children(statement, nsAssertion, "Attribute") {
+			values := make([]string, 0)
+			for _, value := range children(attribute, nsAssertion, "AttributeValue") {
+				values = append(values, strings.TrimSpace(value.Text()))
+			}
+
+			for _, key := range []string{"Name", "FriendlyName"} {
+				if name := attribute.SelectAttrValue(key, ""); name != "" {
+					result.Attributes[name] = append(result.Attributes[name], values...)
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func validateSubjectConfirmation(subject *etree.Element, opts ResponseOptions) error {
+	for _, confirmation := range children(subject, nsAssertion, "SubjectConfirmation") {
+		if confirmation.SelectAttrValue("Method", "") != confirmationBearer {
+			continue
+		}
+
+		data, _ := single(confirmation, nsAssertion, "SubjectConfirmationData")
+		if data == nil {
+			continue
+		}
+
+		if recipient := data.SelectAttrValue("Recipient", ""); recipient != "" && recipient != opts.ACSURL {
+			continue
+		}
+
+		if inResponseTo := data.SelectAttrValue("InResponseTo", ""); inResponseTo != "" && inResponseTo != opts.InResponseTo {
+			continue
+		}
+
+		if validateTimeWindow(data, opts.Now) != nil {
+			continue
+		}
+
+		return nil
+	}
+
+	return errors.New("SAML Assertion has no valid bearer subject confirmation")
+}
+
+func validateTimeWindow(el *etree.Element, now time.Time) error {
+	if value := el.SelectAttrValue("NotBefore", ""); value != "" {
+		notBefore, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse NotBefore")
+		}
+		if now.Add(clockSkew).Before(notBefore) {
+			return errors.New("SAML Assertion is not valid yet")
+		}
+	}
+
+	if value := el.SelectAttrValue("NotOnOrAfter", ""); value != "" {
+		notOnOrAfter, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse NotOnOrAfter")
+		}
+		if !now.Add(-clockSkew).Before(notOnOrAfter) {
+			return errors.New("SAML Assertion has expired")
+		}
+	}
+
+	return nil
+}
+
+func is(el *etree.Element, namespace, tag string) bool {
+	return el.Tag == tag && el.NamespaceURI() == namespace
+}
+
+func children(el *etree.Element, namespace, tag string) []*etree.Element {
+	var result []*etree.Element
+	for _, child := range el.ChildElements() {
+		if is(child, namespace, tag) {
+			result = append(result, child)
+		}
+	}
+	return result
+}
+
+// single returns the only child with given name, as duplicates are a sign of a tampered document
+func single(el *etree.Element, namespace, tag string) (*etree.Element, error) {
+	found := children(el, namespace, tag)
+	if len(found) != 1 {
+		return nil, errors.New("expected exactly one %s in %s, found %d", tag, el.Tag, len(found))
+	}
+	return found[0], nil
+}
+
+// withNamespaces returns a detached copy of el holding the namespace declarations of its ancestors,
+// so that it can be canonicalized on its own when its signature is verified
+func withNamespaces(el *etree.Element) *etree.Element {
+	detached := el.Copy()
+	for parent := el.Parent(); parent != nil; parent = parent.Parent() {
+		for _, attr := range parent.Attr {
+			if attr.Space != "xmlns" && !(attr.Space == "" && attr.Key == "xmlns") {
+				continue
+			}
+			if detached.SelectAttr(attr.FullKey()) == nil {
+				detached.CreateAttr(attr.FullKey(), attr.Value)
+			}
+		}
+	}
+	return detached
+}
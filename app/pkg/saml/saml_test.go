@@ -0,0 +1,292 @@
+package saml_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math/big"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beevik/etree"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/saml"
+	dsig "github.com/russellhaering/goxmldsig"
+)
+
+const (
+	spEntityID  = "https://demo.test.fider.io/saml/metadata"
+	acsURL      = "https://demo.test.fider.io/saml/acs"
+	idpEntityID = "https://idp.example.com"
+	requestID   = "id-1234"
+)
+
+// testIdP is a local stand-in for an Identity Provider
+type testIdP struct {
+	key  *rsa.PrivateKey
+	cert []byte
+}
+
+func newTestIdP() *testIdP {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "idp.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	cert, _ := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	return &testIdP{key: key, cert: cert}
+}
+
+func (idp *testIdP) certificate() string {
+	return base64.StdEncoding.EncodeToString(idp.cert)
+}
+
+func (idp *testIdP) metadata() string {
+	return fmt.Sprintf(`<?xml version="1.0"?>
+<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" entityID="%s">
+  <md:IDPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+    <md:KeyDescriptor use="signing">
+      <ds:KeyInfo xmlns:ds="http://www.w3.org/2000/09/xmldsig#">
+        <ds:X509Data>
+          <ds:X509Certificate>
+            %s
+          </ds:X509Certificate>
+        </ds:X509Data>
+      </ds:KeyInfo>
+    </md:KeyDescriptor>
+    <md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="https://idp.example.com/sso/post"/>
+    <md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://idp.example.com/sso/redirect"/>
+  </md:IDPSSODescriptor>
+</md:EntityDescriptor>`, idpEntityID, idp.certificate())
+}
+
+type responseOpts struct {
+	audience     string
+	recipient    string
+	notOnOrAfter time.Time
+	signResponse bool
+	unsigned     bool
+}
+
+func (idp *testIdP) assertion(opts responseOpts) *etree.Element {
+	now := time.Now().UTC()
+	doc := etree.NewDocument()
+	err := doc.ReadFromString(fmt.Sprintf(`<saml:Assertion xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" ID="id-assertion" Version="2.0" IssueInstant="%[1]s">
+  <saml:Issuer>%[2]s</saml:Issuer>
+  <saml:Subject>
+    <saml:NameID>jon.snow@got.com</saml:NameID>
+    <saml:SubjectConfirmation Method="urn:oasis:names:tc:SAML:2.0:cm:bearer">
+      <saml:SubjectConfirmationData Recipient="%[3]s" InResponseTo="%[4]s" NotOnOrAfter="%[5]s"/>
+    </saml:SubjectConfirmation>
+  </saml:Subject>
+  <saml:Conditions NotBefore="%[1]s" NotOnOrAfter="%[5]s">
+    <saml:AudienceRestriction>
+      <saml:Audience>%[6]s</saml:Audience>
+    </saml:AudienceRestriction>
+  </saml:Conditions>
+  <saml:AttributeStatement>
+    <saml:Attribute Name="urn:oid:2.5.4.3" FriendlyName="cn">
+      <saml:AttributeValue>Jon Snow</saml:AttributeValue>
+    </saml:Attribute>
+    <saml:Attribute Name="groups">
+      <saml:AttributeValue>nightswatch</saml:AttributeValue>
+      <saml:AttributeValue>starks</saml:AttributeValue>
+    </saml:Attribute>
+  </saml:AttributeStatement>
+</saml:Assertion>`, now.Format(time.RFC3339), idpEntityID, opts.recipient, requestID, opts.notOnOrAfter.Format(time.RFC3339), opts.audience))
+	if err != nil {
+		panic(err)
+	}
+	return doc.Root()
+}
+
+func (idp *testIdP) sign(el *etree.Element) *etree.Element {
+	ctx, _ := dsig.NewSigningContext(idp.key, [][]byte{idp.cert})
+	ctx.Canonicalizer = dsig.MakeC14N10ExclusiveCanonicalizerWithPrefixList("")
+	signed, err := ctx.SignEnveloped(el)
+	if err != nil {
+		panic(err)
+	}
+	return signed
+}
+
+func (idp *testIdP) response(opts responseOpts) string {
+	if opts.audience == "" {
+		opts.audience = spEntityID
+	}
+	if opts.recipient == "" {
+		opts.recipient = acsURL
+	}
+	if opts.notOnOrAfter.IsZero() {
+		opts.notOnOrAfter = time.Now().Add(5 * time.Minute).UTC()
+	}
+
+	assertion := idp.assertion(opts)
+	if !opts.unsigned && !opts.signResponse {
+		assertion = idp.sign(assertion)
+	}
+
+	response := etree.NewElement("samlp:Response")
+	response.CreateAttr("xmlns:samlp", "urn:oasis:names:tc:SAML:2.0:protocol")
+	response.CreateAttr("ID", "id-response")
+	response.CreateAttr("Version", "2.0")
+	response.CreateAttr("Destination", acsURL)
+	response.CreateAttr("InResponseTo", requestID)
+	issuer := response.CreateElement("saml:Issuer")
+	issuer.CreateAttr("xmlns:saml", "urn:oasis:names:tc:SAML:2.0:assertion")
+	issuer.SetText(idpEntityID)
+	status := response.CreateElement("samlp:Status")
+	status.CreateElement("samlp:StatusCode").CreateAttr("Value", "urn:oasis:names:tc:SAML:2.0:status:Success")
+	response.AddChild(assertion)
+
+	if opts.signResponse {
+		response = idp.sign(response)
+	}
+
+	doc := etree.NewDocument()
+	doc.SetRoot(response)
+	raw, _ := doc.WriteToBytes()
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+func (idp *testIdP) options() saml.ResponseOptions {
+	return saml.ResponseOptions{
+		IdPEntityID:  idpEntityID,
+		Certificate:  idp.certificate(),
+		SPEntityID:   spEntityID,
+		ACSURL:       acsURL,
+		InResponseTo: requestID,
+	}
+}
+
+func TestParseIdPMetadata(t *testing.T) {
+	RegisterT(t)
+
+	idp := newTestIdP()
+	metadata, err := saml.ParseIdPMetadata([]byte(idp.metadata()))
+	Expect(err).IsNil()
+	Expect(metadata.EntityID).Equals(idpEntityID)
+	Expect(metadata.SSOURL).Equals("https://idp.example.com/sso/redirect")
+	Expect(metadata.Certificate).Equals(idp.certificate())
+}
+
+func TestParseIdPMetadata_Invalid(t *testing.T) {
+	RegisterT(t)
+
+	for _, metadata := range []string{
+		"",
+		"<html></html>",
+		`<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" entityID="https://idp.example.com"></md:EntityDescriptor>`,
+		strings.Replace(newTestIdP().metadata(), "HTTP-Redirect", "SOAP", 1),
+	} {
+		_, err := saml.ParseIdPMetadata([]byte(metadata))
+		Expect(err).IsNotNil()
+	}
+}
+
+func TestServiceProviderMetadata(t *testing.T) {
+	RegisterT(t)
+
+	metadata, err := saml.ServiceProviderMetadata(spEntityID, acsURL)
+	Expect(err).IsNil()
+	Expect(string(metadata)).ContainsSubstring(`entityID="https://demo.test.fider.io/saml/metadata"`)
+	Expect(string(metadata)).ContainsSubstring(`Location="https://demo.test.fider.io/saml/acs"`)
+}
+
+func TestNewAuthnRequest(t *testing.T) {
+	RegisterT(t)
+
+	request := saml.NewAuthnRequest("https://idp.example.com/sso/redirect?tenant=1", spEntityID, acsURL)
+	Expect(request.ID).HasLen(35)
+
+	rawURL, err := request.RedirectURL("some-state")
+	Expect(err).IsNil()
+	redirectURL, err := url.Parse(rawURL)
+	Expect(err).IsNil()
+	Expect(redirectURL.Host).Equals("idp.example.com")
+	Expect(redirectURL.Query().Get("tenant")).Equals("1")
+	Expect(redirectURL.Query().Get("RelayState")).Equals("some-state")
+
+	compressed, err := base64.StdEncoding.DecodeString(redirectURL.Query().Get("SAMLRequest"))
+	Expect(err).IsNil()
+	raw, err := io.ReadAll(flate.NewReader(bytes.NewReader(compressed)))
+	Expect(err).IsNil()
+	Expect(string(raw)).ContainsSubstring(fmt.Sprintf(`ID="%s"`, request.ID))
+	Expect(string(raw)).ContainsSubstring(`AssertionConsumerServiceURL="https://demo.test.fider.io/saml/acs"`)
+	Expect(string(raw)).ContainsSubstring(spEntityID)
+}
+
+func TestParseResponse_SignedAssertion(t *testing.T) {
+	RegisterT(t)
+
+	idp := newTestIdP()
+	assertion, err := saml.ParseResponse(idp.response(responseOpts{}), idp.options())
+	Expect(err).IsNil()
+	Expect(assertion.ID).Equals("id-assertion")
+	Expect(assertion.NameID).Equals("jon.snow@got.com")
+	Expect(assertion.Attribute("cn")).Equals("Jon Snow")
+	Expect(assertion.Attribute("urn:oid:2.5.4.3")).Equals("Jon Snow")
+	Expect(assertion.Attributes["groups"]).Equals([]string{"nightswatch", "starks"})
+	Expect(assertion.Attribute("unknown")).Equals("")
+}
+
+func TestParseResponse_SignedResponse(t *testing.T) {
+	RegisterT(t)
+
+	idp := newTestIdP()
+	assertion, err := saml.ParseResponse(idp.response(responseOpts{signResponse: true}), idp.options())
+	Expect(err).IsNil()
+	Expect(assertion.NameID).Equals("jon.snow@got.com")
+}
+
+func TestParseResponse_Invalid(t *testing.T) {
+	RegisterT(t)
+
+	idp := newTestIdP()
+	otherIdP := newTestIdP()
+
+	for _, response := range []string{
+		"not base64!",
+		base64.StdEncoding.EncodeToString([]byte("<html></html>")),
+		idp.response(responseOpts{unsigned: true}),
+		otherIdP.response(responseOpts{}),
+		idp.response(responseOpts{audience: "https://other.fider.io/saml/metadata"}),
+		idp.response(responseOpts{recipient: "https://other.fider.io/saml/acs"}),
+		idp.response(responseOpts{notOnOrAfter: time.Now().Add(-10 * time.Minute).UTC()}),
+	} {
+		_, err := saml.ParseResponse(response, idp.options())
+		Expect(err).IsNotNil()
+	}
+}
+
+func TestParseResponse_Tampered(t *testing.T) {
+	RegisterT(t)
+
+	idp := newTestIdP()
+	raw, _ := base64.StdEncoding.DecodeString(idp.response(responseOpts{}))
+	tampered := strings.Replace(string(raw), "jon.snow@got.com", "arya.stark@got.com", 1)
+
+	_, err := saml.ParseResponse(base64.StdEncoding.EncodeToString([]byte(tampered)), idp.options())
+	Expect(err).IsNotNil()
+}
+
+func TestParseResponse_WrongRequest(t *testing.T) {
+	RegisterT(t)
+
+	idp := newTestIdP()
+	opts := idp.options()
+	opts.InResponseTo = "id-5678"
+
+	_, err := saml.ParseResponse(idp.response(responseOpts{}), opts)
+	Expect(err).IsNotNil()
+}
@@ -0,0 +1,43 @@
+package scim
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var filterRegex = regexp.MustCompile(`(?i)^\s*([a-z][a-z0-9_.:]*)\s+eq\s+("(?:[^"\\]|\\.)*")\s*$`)
+
+// Filter is an equality filter on a single attribute, which is what identity providers use to find users and groups
+type Filter struct {
+	Attribute string
+	Value     string
+}
+
+// ParseFilter parses a filter such as `userName eq "jon.snow@got.com"`. Attribute names are returned in lower case
+func ParseFilter(filter string) (*Filter, error) {
+	matches := filterRegex.FindStringSubmatch(filter)
+	if matches == nil {
+		return nil, NewError(http.StatusBadRequest, "invalidFilter", "Only filters in the form 'attribute eq \"value\"' are supported")
+	}
+
+	value, err := strconv.Unquote(matches[2])
+	if err != nil {
+		return nil, NewError(http.StatusBadRequest, "invalidFilter", "Invalid filter value %s", matches[2])
+	}
+
+	return &Filter{
+		Attribute: normalizeAttribute(matches[1]),
+		Value:     value,
+	}, nil
+}
+
+// normalizeAttribute returns given attribute path in lower case and without the schema URN
+func normalizeAttribute(path string) string {
+	path = strings.ToLower(strings.TrimSpace(path))
+	for _, schema := range []string{UserSchema, GroupSchema} {
+		path = strings.TrimPrefix(path, strings.ToLower(schema)+":")
+	}
+	return path
+}
@@ -0,0 +1,221 @@
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var valueFilterRegex = regexp.MustCompile(`\[(.*)\]`)
+
+// PatchRequest is a list of changes to apply to a resource
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+// PatchOperation is a single change of a PatchRequest
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+// UserPatch is the set of user attributes changed by a request, nil values are left unchanged
+type UserPatch struct {
+	Active     *bool
+	Name       *string
+	Email      *string
+	ExternalID *string
+}
+
+// GroupPatch is the set of member changes of a group
+type GroupPatch struct {
+	// Replace is true when the members of the group are replaced by Add
+	Replace bool
+	Add     []string
+	Remove  []string
+}
+
+func (op PatchOperation) kind() (string, error) {
+	kind := strings.ToLower(strings.TrimSpace(op.Op))
+	if kind != "add" && kind != "replace" && kind != "remove" {
+		return "", NewError(http.StatusBadRequest, "invalidSyntax", "Unsupported operation '%s'", op.Op)
+	}
+	return kind, nil
+}
+
+// UserPatch returns the user attributes changed by the request.
+// Attributes Fider doesn't store and removals are ignored, as users must always have a name and an email
+func (r *PatchRequest) UserPatch() (*UserPatch, error) {
+	patch := &UserPatch{}
+	for _, op := range r.Operations {
+		kind, err := op.kind()
+		if err != nil {
+			return nil, err
+		}
+		if kind == "remove" {
+			continue
+		}
+
+		if op.Path != "" {
+			if err := patch.set(op.Path, op.Value); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		values := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(op.Value, &values); err != nil {
+			return nil, NewError(http.StatusBadRequest, "invalidValue", "Operations without path must have an object value")
+		}
+		for path, value := range values {
+			if err := patch.set(path, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return patch, nil
+}
+
+func (p *UserPatch) set(path string, value json.RawMessage) error {
+	path = valueFilterRegex.ReplaceAllString(normalizeAttribute(path), "")
+	switch path {
+	case "active":
+		active, err := parseBool(value)
+		if err != nil {
+			return err
+		}
+		p.Active = &active
+	case "displayname", "name.formatted":
+		name, err := parseString(value)
+		if err != nil {
+			return err
+		}
+		if name != "" {
+			p.Name = &name
+		}
+	case "name":
+		name := &Name{}
+		if err := json.Unmarshal(value, name); err != nil {
+			return NewError(http.StatusBadRequest, "invalidValue", "Invalid value for 'name'")
+		}
+		// displayName takes precedence over the structured name
+		if formatted := name.String(); formatted != "" && p.Name == nil {
+			p.Name = &formatted
+		}
+	case "username", "emails.value":
+		email, err := parseString(value)
+		if err != nil {
+			return err
+		}
+		if strings.Contains(email, "@") {
+			p.Email = &email
+		}
+	case "emails":
+		user := &User{}
+		if err := json.Unmarshal(value, &user.Emails); err != nil {
+			return NewError(http.StatusBadRequest, "invalidValue", "Invalid value for 'emails'")
+		}
+		if email := user.PrimaryEmail(); email != "" {
+			p.Email = &email
+		}
+	case "externalid":
+		externalID, err := parseString(value)
+		if err != nil {
+			return err
+		}
+		p.ExternalID = &externalID
+	}
+	return nil
+}
+
+// GroupPatch returns the member changes of the request, other group attributes can't be changed
+func (r *PatchRequest) GroupPatch() (*GroupPatch, error) {
+	patch := &GroupPatch{}
+	for _, op := range r.Operations {
+		kind, err := op.kind()
+		if err != nil {
+			return nil, err
+		}
+
+		path := normalizeAttribute(op.Path)
+		if path == "" && kind != "remove" {
+			values := make(map[string]json.RawMessage)
+			if err := json.Unmarshal(op.Value, &values); err != nil {
+				return nil, NewError(http.StatusBadRequest, "invalidValue", "Operations without path must have an object value")
+			}
+			for key, value := range values {
+				if normalizeAttribute(key) == "members" {
+					path, op.Value = "members", value
+				}
+			}
+		}
+
+		// e.g. members[value eq "2"]
+		if kind == "remove" && strings.HasPrefix(path, "members[") {
+			start, end := strings.Index(op.Path, "["), strings.LastIndex(op.Path, "]")
+			if end < start {
+				return nil, NewError(http.StatusBadRequest, "invalidPath", "Invalid path '%s'", op.Path)
+			}
+			filter, err := ParseFilter(op.Path[start+1 : end])
+			if err != nil {
+				return nil, err
+			}
+			patch.Remove = append(patch.Remove, filter.Value)
+			continue
+		}
+
+		if path != "members" {
+			continue
+		}
+
+		var members []Member
+		if len(op.Value) > 0 {
+			if err := json.Unmarshal(op.Value, &members); err != nil {
+				return nil, NewError(http.StatusBadRequest, "invalidValue", "Invalid value for 'members'")
+			}
+		}
+
+		ids := make([]string, 0, len(members))
+		for _, member := range members {
+			ids = append(ids, member.Value)
+		}
+
+		switch kind {
+		case "add":
+			patch.Add = append(patch.Add, ids...)
+		case "remove":
+			patch.Remove = append(patch.Remove, ids...)
+		case "replace":
+			patch.Replace = true
+			patch.Add = ids
+			patch.Remove = nil
+		}
+	}
+	return patch, nil
+}
+
+func parseString(value json.RawMessage) (string, error) {
+	var str string
+	if err := json.Unmarshal(value, &str); err != nil {
+		return "", NewError(http.StatusBadRequest, "invalidValue", "Expected a string, got %s", string(value))
+	}
+	return strings.TrimSpace(str), nil
+}
+
+// parseBool accepts booleans and their string representation, as some identity providers send "False"
+func parseBool(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+	if str, err := parseString(value); err == nil {
+		if b, err := strconv.ParseBool(strings.ToLower(str)); err == nil {
+			return b, nil
+		}
+	}
+	return false, NewError(http.StatusBadRequest, "invalidValue", "Expected a boolean, got %s", string(value))
+}
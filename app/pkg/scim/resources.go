@@ -0,0 +1,174 @@
+package scim
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ContentType is the media type of SCIM requests and responses
+const ContentType = "application/scim+json"
+
+// Schema URNs used by SCIM 2.0 resources and messages
+const (
+	UserSchema                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	GroupSchema                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	ListResponseSchema          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	PatchOpSchema               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	ErrorSchema                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	ServiceProviderConfigSchema = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+)
+
+// Name is the structured name of a user
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// Email is one of the email addresses of a user
+type Email struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// Meta is the metadata of a resource
+type Meta struct {
+	ResourceType string `json:"resourceType"`
+	Location     string `json:"location,omitempty"`
+}
+
+// Member is a reference to a user or a group
+type Member struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+}
+
+// User is the SCIM representation of a user
+type User struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	ExternalID  string   `json:"externalId,omitempty"`
+	UserName    string   `json:"userName"`
+	Name        *Name    `json:"name,omitempty"`
+	DisplayName string   `json:"displayName,omitempty"`
+	Emails      []Email  `json:"emails,omitempty"`
+	Active      *bool    `json:"active,omitempty"`
+	Groups      []Member `json:"groups,omitempty"`
+	Meta        *Meta    `json:"meta,omitempty"`
+}
+
+// PrimaryEmail returns the primary email of the user, falling back to the first email and then to the user name if it is an email
+func (u *User) PrimaryEmail() string {
+	for _, email := range u.Emails {
+		if email.Primary && email.Value != "" {
+			return strings.TrimSpace(email.Value)
+		}
+	}
+	for _, email := range u.Emails {
+		if email.Value != "" {
+			return strings.TrimSpace(email.Value)
+		}
+	}
+	if strings.Contains(u.UserName, "@") {
+		return strings.TrimSpace(u.UserName)
+	}
+	return ""
+}
+
+// FullName returns the name to display for the user
+func (u *User) FullName() string {
+	if name := strings.TrimSpace(u.DisplayName); name != "" {
+		return name
+	}
+	if u.Name != nil {
+		return u.Name.String()
+	}
+	return ""
+}
+
+// String returns the formatted name, or the given and family names combined
+func (n *Name) String() string {
+	if formatted := strings.TrimSpace(n.Formatted); formatted != "" {
+		return formatted
+	}
+	return strings.TrimSpace(strings.TrimSpace(n.GivenName) + " " + strings.TrimSpace(n.FamilyName))
+}
+
+// AsPatch returns the changes needed to replace a user with this one
+func (u *User) AsPatch() *UserPatch {
+	patch := &UserPatch{Active: u.Active}
+	if name := u.FullName(); name != "" {
+		patch.Name = &name
+	}
+	if email := u.PrimaryEmail(); email != "" {
+		patch.Email = &email
+	}
+	if u.ExternalID != "" {
+		patch.ExternalID = &u.ExternalID
+	}
+	return patch
+}
+
+// Group is the SCIM representation of a group
+type Group struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id"`
+	DisplayName string   `json:"displayName"`
+	Members     []Member `json:"members,omitempty"`
+	Meta        *Meta    `json:"meta,omitempty"`
+}
+
+// ListResponse is a page of resources
+type ListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []any    `json:"Resources"`
+}
+
+// NewListResponse creates a page of resources starting at given 1-based index
+func NewListResponse(resources []any, totalResults, startIndex int) *ListResponse {
+	if resources == nil {
+		resources = make([]any, 0)
+	}
+	return &ListResponse{
+		Schemas:      []string{ListResponseSchema},
+		TotalResults: totalResults,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}
+}
+
+// Error is a SCIM error response
+type Error struct {
+	Schemas    []string `json:"schemas"`
+	Status     string   `json:"status"`
+	ScimType   string   `json:"scimType,omitempty"`
+	Detail     string   `json:"detail"`
+	StatusCode int      `json:"-"`
+}
+
+// NewError creates a SCIM error with given HTTP status, SCIM error type and detail message
+func NewError(statusCode int, scimType, detail string, args ...any) *Error {
+	if len(args) > 0 {
+		detail = fmt.Sprintf(detail, args...)
+	}
+	return &Error{
+		Schemas:    []string{ErrorSchema},
+		Status:     fmt.Sprint(statusCode),
+		ScimType:   scimType,
+		Detail:     detail,
+		StatusCode: statusCode,
+	}
+}
+
+// ErrNotFound is returned when the resource doesn't exist
+var ErrNotFound = NewError(http.StatusNotFound, "", "Resource not found")
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("SCIM %s: %s", e.Status, e.Detail)
+}
@@ -0,0 +1,25 @@
+package scim
+
+import (
+	"encoding/json"
+
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+// Write sends given resource as a SCIM response
+func Write(c *web.Context, statusCode int, resource any) error {
+	body, err := json.Marshal(resource)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal SCIM response")
+	}
+	return c.Blob(statusCode, ContentType, body)
+}
+
+// WriteError sends given error as a SCIM error response, errors that are not SCIM errors are handled as failures
+func WriteError(c *web.Context, err error) error {
+	if scimErr, ok := errors.Cause(err).(*Error); ok {
+		return Write(c, scimErr.StatusCode, scimErr)
+	}
+	return c.Failure(err)
+}
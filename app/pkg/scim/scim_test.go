@@ -0,0 +1,135 @@
+package scim_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/scim"
+)
+
+func TestParseFilter(t *testing.T) {
+	RegisterT(t)
+
+	filter, err := scim.ParseFilter(`userName eq "jon.snow@got.com"`)
+	Expect(err).IsNil()
+	Expect(filter.Attribute).Equals("username")
+	Expect(filter.Value).Equals("jon.snow@got.com")
+
+	filter, err = scim.ParseFilter(`urn:ietf:params:scim:schemas:core:2.0:User:externalId EQ "00u\"1"`)
+	Expect(err).IsNil()
+	Expect(filter.Attribute).Equals("externalid")
+	Expect(filter.Value).Equals(`00u"1`)
+
+	for _, invalid := range []string{
+		`userName co "jon"`,
+		`userName eq jon`,
+		`userName eq "jon" and active eq true`,
+		``,
+	} {
+		_, err := scim.ParseFilter(invalid)
+		Expect(err).IsNotNil()
+		Expect(err.(*scim.Error).ScimType).Equals("invalidFilter")
+	}
+}
+
+func TestUser_PrimaryEmailAndName(t *testing.T) {
+	RegisterT(t)
+
+	user := &scim.User{
+		UserName: "jon",
+		Emails: []scim.Email{
+			{Value: "jon@work.com", Type: "work"},
+			{Value: "jon@home.com", Type: "home", Primary: true},
+		},
+		Name: &scim.Name{GivenName: "Jon", FamilyName: "Snow"},
+	}
+	Expect(user.PrimaryEmail()).Equals("jon@home.com")
+	Expect(user.FullName()).Equals("Jon Snow")
+
+	user = &scim.User{UserName: "jon.snow@got.com", DisplayName: "Lord Snow", Name: &scim.Name{Formatted: "Jon Snow"}}
+	Expect(user.PrimaryEmail()).Equals("jon.snow@got.com")
+	Expect(user.FullName()).Equals("Lord Snow")
+
+	user = &scim.User{UserName: "jon"}
+	Expect(user.PrimaryEmail()).Equals("")
+	Expect(user.FullName()).Equals("")
+}
+
+func parsePatch(body string) *scim.PatchRequest {
+	request := &scim.PatchRequest{}
+	if err := json.Unmarshal([]byte(body), request); err != nil {
+		panic(err)
+	}
+	return request
+}
+
+func TestPatchRequest_UserPatch(t *testing.T) {
+	RegisterT(t)
+
+	patch, err := parsePatch(`{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+		"Operations": [
+			{ "op": "replace", "path": "active", "value": false },
+			{ "op": "replace", "path": "emails[type eq \"work\"].value", "value": "jon@got.com" },
+			{ "op": "remove", "path": "title" },
+			{ "op": "add", "path": "nickName", "value": "Lord Snow" }
+		]
+	}`).UserPatch()
+	Expect(err).IsNil()
+	Expect(*patch.Active).IsFalse()
+	Expect(*patch.Email).Equals("jon@got.com")
+	Expect(patch.Name).IsNil()
+	Expect(patch.ExternalID).IsNil()
+
+	patch, err = parsePatch(`{
+		"Operations": [
+			{ "op": "Replace", "value": { "active": "True", "displayName": "Jon Snow", "externalId": "00u1" } }
+		]
+	}`).UserPatch()
+	Expect(err).IsNil()
+	Expect(*patch.Active).IsTrue()
+	Expect(*patch.Name).Equals("Jon Snow")
+	Expect(*patch.ExternalID).Equals("00u1")
+	Expect(patch.Email).IsNil()
+
+	_, err = parsePatch(`{ "Operations": [{ "op": "replace", "path": "active", "value": "maybe" }] }`).UserPatch()
+	Expect(err).IsNotNil()
+
+	_, err = parsePatch(`{ "Operations": [{ "op": "move", "path": "active", "value": true }] }`).UserPatch()
+	Expect(err).IsNotNil()
+}
+
+func TestPatchRequest_GroupPatch(t *testing.T) {
+	RegisterT(t)
+
+	patch, err := parsePatch(`{
+		"Operations": [
+			{ "op": "add", "path": "members", "value": [{ "value": "1" }, { "value": "2" }] },
+			{ "op": "remove", "path": "members[value eq \"3\"]" },
+			{ "op": "remove", "path": "members", "value": [{ "value": "4" }] },
+			{ "op": "replace", "path": "displayName", "value": "Admins" }
+		]
+	}`).GroupPatch()
+	Expect(err).IsNil()
+	Expect(patch.Replace).IsFalse()
+	Expect(patch.Add).Equals([]string{"1", "2"})
+	Expect(patch.Remove).Equals([]string{"3", "4"})
+
+	patch, err = parsePatch(`{
+		"Operations": [
+			{ "op": "replace", "value": { "members": [{ "value": "5" }] } }
+		]
+	}`).GroupPatch()
+	Expect(err).IsNil()
+	Expect(patch.Replace).IsTrue()
+	Expect(patch.Add).Equals([]string{"5"})
+}
+
+func TestNewListResponse(t *testing.T) {
+	RegisterT(t)
+
+	list := scim.NewListResponse(nil, 0, 1)
+	body, _ := json.Marshal(list)
+	Expect(string(body)).Equals(`{"schemas":["urn:ietf:params:scim:api:messages:2.0:ListResponse"],"totalResults":0,"startIndex":1,"itemsPerPage":0,"Resources":[]}`)
+}
@@ -0,0 +1,137 @@
+package similarity
+
+import (
+	"hash/fnv"
+	"strings"
+	"unicode"
+)
+
+const (
+	// SignatureSize is the number of hash functions of a MinHash signature
+	SignatureSize = 64
+	// bandRows is the number of signature values hashed together into each band.
+	// With 16 bands of 4 rows, texts that are 50% similar share a band 65% of the time, and those 20% similar only 2% of the time
+	bandRows = 4
+	// minWordLength is the minimum number of characters for a word to be considered
+	minWordLength = 3
+)
+
+// suffixes are removed from words so that different forms of the same word have the same shingles
+var suffixes = []string{"ations", "ation", "ings", "ing", "ies", "ed", "es", "ly", "s"}
+
+// Signature is the MinHash signature of a text, which estimates how similar two texts are without comparing them
+type Signature []int64
+
+// Compute returns the signature of text, ignoring the given noise words.
+// Returns nil when text has no meaningful words
+func Compute(text string, noiseWords []string) Signature {
+	shingles := shingles(text, noiseWords)
+	if len(shingles) == 0 {
+		return nil
+	}
+
+	signature := make(Signature, SignatureSize)
+	for i := range signature {
+		signature[i] = -1
+	}
+
+	for shingle := range shingles {
+		for i := range signature {
+			value := int64(mix(shingle^seed(i)) >> 33)
+			if signature[i] < 0 || value < signature[i] {
+				signature[i] = value
+			}
+		}
+	}
+	return signature
+}
+
+// Similarity estimates the Jaccard similarity between the shingles of two texts, from 0 to 1
+func (s Signature) Similarity(other Signature) float64 {
+	if len(s) != SignatureSize || len(other) != SignatureSize {
+		return 0
+	}
+
+	equal := 0
+	for i := range s {
+		if s[i] == other[i] {
+			equal++
+		}
+	}
+	return float64(equal) / SignatureSize
+}
+
+// Bands returns the locality-sensitive hashes of this signature.
+// Texts sharing at least one band are likely to be similar, so they are candidates to be compared
+func (s Signature) Bands() []int64 {
+	if len(s) != SignatureSize {
+		return []int64{}
+	}
+
+	bands := make([]int64, 0, SignatureSize/bandRows)
+	for band := 0; band < SignatureSize; band += bandRows {
+		hash := mix(uint64(band))
+		for _, value := range s[band : band+bandRows] {
+			hash = mix(hash ^ uint64(value))
+		}
+		bands = append(bands, int64(hash>>1))
+	}
+	return bands
+}
+
+// shingles returns the hashes of the stemmed words of text and their character trigrams,
+// so that texts using different forms or slightly misspelled words still share most of them
+func shingles(text string, noiseWords []string) map[uint64]struct{} {
+	noise := make(map[string]bool, len(noiseWords))
+	for _, word := range noiseWords {
+		noise[strings.ToLower(word)] = true
+	}
+
+	result := make(map[uint64]struct{})
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	for _, word := range words {
+		if noise[word] || len([]rune(word)) < minWordLength {
+			continue
+		}
+
+		word = stem(word)
+		result[hash("w:"+word)] = struct{}{}
+
+		runes := []rune(" " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			result[hash("t:"+string(runes[i:i+3]))] = struct{}{}
+		}
+	}
+	return result
+}
+
+func stem(word string) string {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(word, suffix) && len([]rune(word))-len([]rune(suffix)) >= minWordLength {
+			return strings.TrimSuffix(word, suffix)
+		}
+	}
+	return word
+}
+
+func hash(value string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(value))
+	return h.Sum64()
+}
+
+func seed(i int) uint64 {
+	return mix(uint64(i) + 0x9e3779b97f4a7c15)
+}
+
+// mix is the finalizer of SplitMix64, which spreads the bits of x evenly
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,58 @@
+package similarity_test
+
+import (
+	"testing"
+
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/similarity"
+)
+
+var noiseWords = []string{"add", "support", "for", "please"}
+
+func TestSimilarity_Compute(t *testing.T) {
+	RegisterT(t)
+
+	signature := similarity.Compute("Dark mode for the dashboard", noiseWords)
+	Expect(signature).HasLen(similarity.SignatureSize)
+	Expect(signature.Similarity(similarity.Compute("DARK MODE, for the dashboard!", noiseWords))).Equals(1.0)
+
+	Expect(similarity.Compute("", noiseWords)).IsNil()
+	Expect(similarity.Compute("add support for it", noiseWords)).IsNil()
+}
+
+func TestSimilarity_Paraphrases(t *testing.T) {
+	RegisterT(t)
+
+	original := similarity.Compute("Export posts to CSV files", noiseWords)
+	paraphrase := similarity.Compute("Please add support for exporting a post to a CSV file", noiseWords)
+	unrelated := similarity.Compute("Sign in with a Microsoft account", noiseWords)
+
+	Expect(original.Similarity(paraphrase) > 0.6).IsTrue()
+	Expect(original.Similarity(unrelated) < 0.2).IsTrue()
+}
+
+func TestSimilarity_Bands(t *testing.T) {
+	RegisterT(t)
+
+	original := similarity.Compute("Export posts to CSV files", noiseWords)
+	paraphrase := similarity.Compute("Please add support for exporting a post to a CSV file", noiseWords)
+
+	Expect(original.Bands()).HasLen(similarity.SignatureSize / 4)
+	Expect(original.Bands()).Equals(similarity.Compute("export posts to csv files", nil).Bands())
+
+	shared := 0
+	bands := make(map[int64]bool)
+	for _, band := range original.Bands() {
+		bands[band] = true
+	}
+	for _, band := range paraphrase.Bands() {
+		if bands[band] {
+			shared++
+		}
+	}
+	Expect(shared > 0).IsTrue()
+
+	var empty similarity.Signature
+	Expect(empty.Bands()).HasLen(0)
+	Expect(empty.Similarity(original)).Equals(0.0)
+}
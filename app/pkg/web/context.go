@@ -349,6 +349,20 @@ func (c *Context) SetUser(user *entity.User) {
 	c.Set(app.UserCtxKey, user)
 }
 
+// APIToken returns the personal access token used to authenticate current request, if any
+func (c *Context) APIToken() *entity.APIToken {
+	token, ok := c.Value(app.APITokenCtxKey).(*entity.APIToken)
+	if ok {
+		return token
+	}
+	return nil
+}
+
+// SetAPIToken update HTTP context with the personal access token used to authenticate current request
+func (c *Context) SetAPIToken(token *entity.APIToken) {
+	c.Set(app.APITokenCtxKey, token)
+}
+
 // AddCookie adds a cookie
 func (c *Context) AddCookie(name, value string, expires time.Time) *http.Cookie {
 	cookie := &http.Cookie{
@@ -6,9 +6,11 @@ import (
 	"io"
 	stdLog "log"
 	"net/http"
+	"net/url"
 	"os"
 	"path"
 	"runtime"
+	"slices"
 	"strconv"
 	"time"
 
@@ -86,13 +88,35 @@ func New() *Engine {
 		renderer:    NewRenderer(),
 		binder:      NewDefaultBinder(),
 		middlewares: make([]MiddlewareFunc, 0),
-		worker:      worker.New(),
+		worker:      newWorker(),
 		cache:       cache.New(5*time.Minute, 10*time.Minute),
 	}
 
 	return router
 }
 
+// newWorker returns the background worker selected by WORKER environment variable
+func newWorker() worker.Worker {
+	if env.Config.Worker.Type == "database" {
+		return worker.NewDatabaseWorker(requestMapper{})
+	}
+	return worker.New()
+}
+
+// requestMapper allows the database worker to persist the request of a task's origin context
+type requestMapper struct{}
+
+func (requestMapper) ToURL(request any) *url.URL {
+	if r, ok := request.(Request); ok {
+		return r.URL
+	}
+	return nil
+}
+
+func (requestMapper) FromURL(u *url.URL) any {
+	return Request{URL: u}
+}
+
 // Start the server.
 func (e *Engine) Start(address string) {
 	log.Info(e, "Application is starting")
@@ -241,6 +265,11 @@ func (e *Engine) Put(path string, handler HandlerFunc) {
 	e.mux.Handle("PUT", path, e.handle(e.middlewares, handler))
 }
 
+// Patch handles HTTP PATCH requests
+func (g *Group) Patch(path string, handler HandlerFunc) {
+	g.engine.mux.Handle("PATCH", path, g.engine.handle(g.middlewares, handler))
+}
+
 // Delete handles HTTP DELETE requests
 func (e *Engine) Delete(path string, handler HandlerFunc) {
 	e.mux.Handle("DELETE", path, e.handle(e.middlewares, handler))
@@ -278,9 +307,10 @@ type Group struct {
 
 // Group creates a new route group
 func (g *Group) Group() *Group {
+	// Middlewares are copied so that sibling groups can't overwrite each other's stack
 	g2 := &Group{
 		engine:      g.engine,
-		middlewares: g.middlewares,
+		middlewares: slices.Clone(g.middlewares),
 	}
 	return g2
 }
@@ -54,6 +54,9 @@ func (p Props) SetPost(post *entity.Post, keyPrefix, baseURL string, includeAllF
 			p[keyPrefix+"_votes"] = post.VotesCount
 			p[keyPrefix+"_comments"] = post.CommentsCount
 			p[keyPrefix+"_status"] = post.Status.Name()
+			if post.CustomStatus != nil {
+				p[keyPrefix+"_status_name"] = post.CustomStatus.Name
+			}
 			p[keyPrefix+"_tags"] = post.Tags
 			p[keyPrefix+"_response"] = postResponse != nil
 
@@ -77,3 +80,26 @@ func (p Props) SetPost(post *entity.Post, keyPrefix, baseURL string, includeAllF
 	}
 	return p
 }
+
+// SetComment describe the comment prefixed by "keyPrefix"
+func (p Props) SetComment(comment *entity.Comment, keyPrefix string) Props {
+	if comment != nil {
+		p[keyPrefix] = entity.CommentString(comment.Content).SanitizeMentions()
+		p[keyPrefix+"_id"] = comment.ID
+		p[keyPrefix+"_created_at"] = comment.CreatedAt
+		p.SetUser(comment.User, keyPrefix+"_author")
+	}
+	return p
+}
+
+// SetTag describe the tag prefixed by "keyPrefix"
+func (p Props) SetTag(tag *entity.Tag, keyPrefix string) Props {
+	if tag != nil {
+		p[keyPrefix+"_id"] = tag.ID
+		p[keyPrefix+"_name"] = tag.Name
+		p[keyPrefix+"_slug"] = tag.Slug
+		p[keyPrefix+"_color"] = tag.Color
+		p[keyPrefix+"_is_public"] = tag.IsPublic
+	}
+	return p
+}
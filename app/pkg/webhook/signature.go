@@ -0,0 +1,26 @@
+package webhook
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/getfider/fider/app/pkg/crypto"
+	"github.com/getfider/fider/app/pkg/rand"
+)
+
+// SignatureHeader is the HTTP header holding the signature of a webhook request
+const SignatureHeader = "X-Fider-Signature"
+
+// NewSecret generates a new random secret used to sign webhook requests
+func NewSecret() string {
+	return rand.String(48)
+}
+
+// Sign returns the value of the signature header for given body sent at given time.
+// The signature is the HMAC-SHA256 of "<timestamp>.<body>" using the webhook secret,
+// so that receivers can verify both the origin and the freshness of the request.
+func Sign(secret string, timestamp time.Time, body string) string {
+	unix := timestamp.Unix()
+	signature := crypto.HMACSHA256(secret, fmt.Sprintf("%d.%s", unix, body))
+	return fmt.Sprintf("t=%d,v1=%s", unix, signature)
+}
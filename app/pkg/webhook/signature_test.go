@@ -0,0 +1,29 @@
+package webhook_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/crypto"
+	"github.com/getfider/fider/app/pkg/webhook"
+)
+
+func TestSign(t *testing.T) {
+	RegisterT(t)
+
+	timestamp := time.Unix(1700000000, 0)
+	signature := webhook.Sign("my-secret", timestamp, `{"id":1}`)
+
+	Expect(signature).Equals("t=1700000000,v1=" + crypto.HMACSHA256("my-secret", `1700000000.{"id":1}`))
+	Expect(webhook.Sign("other-secret", timestamp, `{"id":1}`)).NotEquals(signature)
+	Expect(webhook.Sign("my-secret", timestamp.Add(time.Second), `{"id":1}`)).NotEquals(signature)
+}
+
+func TestNewSecret(t *testing.T) {
+	RegisterT(t)
+
+	secret := webhook.NewSecret()
+	Expect(secret).HasLen(48)
+	Expect(webhook.NewSecret()).NotEquals(secret)
+}
@@ -0,0 +1,358 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/env"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/log"
+	"github.com/getfider/fider/app/pkg/rand"
+)
+
+// Status of a task persisted by the DatabaseWorker
+const (
+	taskStatusPending = 1
+	taskStatusRunning = 2
+	taskStatusDead    = 3
+)
+
+var (
+	pollInterval = 1 * time.Second
+	//tasks locked for longer than this are considered abandoned by a stopped instance and are picked up again
+	staleTaskTimeout = 10 * time.Minute
+)
+
+// RequestMapper converts the request stored on the origin context to and from its URL,
+// which is the only part of the request that is persisted along with the task
+type RequestMapper interface {
+	ToURL(request any) *url.URL
+	FromURL(u *url.URL) any
+}
+
+// origin is the persisted snapshot of the context a task was enqueued from
+type origin struct {
+	Tenant    *entity.Tenant
+	User      *entity.User
+	Locale    string
+	URL       string
+	SessionID string
+}
+
+// DatabaseWorker is a worker that persists tasks on the database,
+// so that they survive restarts and can be consumed by multiple instances
+type DatabaseWorker struct {
+	context.Context
+	instanceID string
+	requests   RequestMapper
+	middleware MiddlewareFunc
+	len        int64
+	wake       chan struct{}
+	done       chan struct{}
+	stop       sync.Once
+	sync.RWMutex
+}
+
+// NewDatabaseWorker creates a new DatabaseWorker
+func NewDatabaseWorker(requests RequestMapper) *DatabaseWorker {
+	ctx := context.Background()
+
+	ctx = log.WithProperties(ctx, dto.Props{
+		log.PropertyKeyContextID: rand.String(32),
+		log.PropertyKeyTag:       "DBW",
+	})
+
+	return &DatabaseWorker{
+		Context:    ctx,
+		instanceID: rand.String(12),
+		requests:   requests,
+		wake:       make(chan struct{}, 1),
+		done:       make(chan struct{}),
+		middleware: func(next Job) Job {
+			return next
+		},
+	}
+}
+
+// Run initializes the worker loop
+func (w *DatabaseWorker) Run(workerID string) {
+	log.Infof(w, "Starting database worker @{WorkerID:magenta}.", dto.Props{
+		"WorkerID": workerID,
+	})
+
+	lockedBy := w.instanceID + "/" + workerID
+	for {
+		select {
+		case <-w.done:
+			return
+		default:
+		}
+
+		found, err := w.runNext(workerID, lockedBy)
+		if err != nil {
+			log.Error(w, err)
+		}
+
+		if !found {
+			select {
+			case <-w.done:
+				return
+			case <-w.wake:
+			case <-time.After(pollInterval):
+			}
+		}
+	}
+}
+
+// Shutdown current worker. Pending tasks are kept on the database to be processed after restart
+func (w *DatabaseWorker) Shutdown(ctx context.Context) error {
+	w.stop.Do(func() {
+		close(w.done)
+	})
+	return waitForQueue(ctx, w, w.Length)
+}
+
+// Enqueue a task on current worker
+// Tasks that can't be persisted are executed in-process as a fallback
+func (w *DatabaseWorker) Enqueue(task Task) {
+	if err := w.insert(task); err != nil {
+		log.Error(w, errors.Wrap(err, "failed to persist task '%s', running it in-process", task.Name))
+		w.increment(1)
+		go func() {
+			defer w.increment(-1)
+			_ = w.middleware(task.Job)(NewContext(w, "in-process", task))
+		}()
+		return
+	}
+
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Length returns the number of tasks currently running on this instance
+func (w *DatabaseWorker) Length() int64 {
+	w.RLock()
+	defer w.RUnlock()
+	return w.len
+}
+
+// Use this to inject worker dependencies
+func (w *DatabaseWorker) Use(middleware MiddlewareFunc) {
+	w.middleware = middleware
+}
+
+func (w *DatabaseWorker) increment(delta int64) {
+	w.Lock()
+	w.len = w.len + delta
+	w.Unlock()
+}
+
+func (w *DatabaseWorker) insert(task Task) error {
+	if !task.IsPersistable() {
+		return errors.New("task '%s' was not created by a registered constructor", task.Name)
+	}
+
+	args, err := EncodeArgs(task)
+	if err != nil {
+		return err
+	}
+
+	snapshot, tenantID, err := w.encodeOrigin(task.OriginContext)
+	if err != nil {
+		return err
+	}
+
+	trx, err := dbx.BeginTx(w)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	_, err = trx.Execute(`
+		INSERT INTO worker_tasks (tenant_id, name, handler, args, origin, status, attempts, run_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, 0, $7, $7)
+	`, tenantID, task.Name, task.Handler, args, snapshot, taskStatusPending, now)
+	if err != nil {
+		trx.MustRollback()
+		return errors.Wrap(err, "failed to insert task")
+	}
+
+	return trx.Commit()
+}
+
+// runNext claims and executes the next pending task, returns false if there was nothing to run
+func (w *DatabaseWorker) runNext(workerID, lockedBy string) (bool, error) {
+	trx, err := dbx.BeginTx(w)
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+	rows, err := trx.Query(`
+		UPDATE worker_tasks
+		SET status = $1, attempts = attempts + 1, locked_by = $2, locked_at = $3
+		WHERE id = (
+			SELECT id FROM worker_tasks
+			WHERE (status = $4 AND run_at <= $3) OR (status = $1 AND locked_at <= $5)
+			ORDER BY run_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, name, handler, args, origin, attempts
+	`, taskStatusRunning, lockedBy, now, taskStatusPending, now.Add(-staleTaskTimeout))
+	if err != nil {
+		trx.MustRollback()
+		return false, errors.Wrap(err, "failed to claim next task")
+	}
+
+	var (
+		id             int64
+		name, handler  string
+		args, snapshot []byte
+		attempts       int
+		found          = rows.Next()
+	)
+	if found {
+		err = rows.Scan(&id, &name, &handler, &args, &snapshot, &attempts)
+	}
+	rows.Close()
+	if err != nil {
+		trx.MustRollback()
+		return false, errors.Wrap(err, "failed to scan claimed task")
+	}
+
+	if err := trx.Commit(); err != nil {
+		return false, err
+	}
+
+	if !found {
+		return false, nil
+	}
+
+	w.increment(1)
+	defer w.increment(-1)
+
+	task, err := DecodeTask(handler, args)
+	if err == nil {
+		task.OriginContext, err = w.decodeOrigin(snapshot)
+	}
+	if err != nil {
+		return true, w.finish(id, name, env.Config.Worker.MaxAttempts, errors.Wrap(err, "failed to decode task"))
+	}
+
+	err = w.middleware(task.Job)(NewContext(w, workerID, task))
+	return true, w.finish(id, name, attempts, err)
+}
+
+// finish removes a successful task or schedules it for retry, moving it to dead-letter once attempts are exhausted
+func (w *DatabaseWorker) finish(id int64, name string, attempts int, taskErr error) error {
+	trx, err := dbx.BeginTx(w)
+	if err != nil {
+		return err
+	}
+
+	if taskErr == nil {
+		_, err = trx.Execute("DELETE FROM worker_tasks WHERE id = $1", id)
+	} else if attempts >= env.Config.Worker.MaxAttempts {
+		log.Errorf(w, "Task '@{TaskName:magenta}' failed after @{Attempts} attempts and was moved to dead-letter", dto.Props{
+			"TaskName": name,
+			"Attempts": attempts,
+		})
+		_, err = trx.Execute(`
+			UPDATE worker_tasks SET status = $2, last_error = $3, locked_by = NULL, locked_at = NULL WHERE id = $1
+		`, id, taskStatusDead, taskErr.Error())
+	} else {
+		_, err = trx.Execute(`
+			UPDATE worker_tasks SET status = $2, last_error = $3, run_at = $4, locked_by = NULL, locked_at = NULL WHERE id = $1
+		`, id, taskStatusPending, taskErr.Error(), time.Now().Add(retryDelay(attempts)))
+	}
+
+	if err != nil {
+		trx.MustRollback()
+		return errors.Wrap(err, "failed to update task '%d'", id)
+	}
+
+	return trx.Commit()
+}
+
+// retryDelay doubles the delay for each attempt, starting at 30 seconds
+func retryDelay(attempts int) time.Duration {
+	shift := attempts - 1
+	if shift < 0 {
+		shift = 0
+	}
+	if shift > 8 {
+		shift = 8
+	}
+	return (30 * time.Second) << uint(shift)
+}
+
+func (w *DatabaseWorker) encodeOrigin(ctx context.Context) ([]byte, any, error) {
+	snapshot := origin{}
+	var tenantID any
+
+	if ctx != nil {
+		snapshot.Tenant, _ = ctx.Value(app.TenantCtxKey).(*entity.Tenant)
+		snapshot.User, _ = ctx.Value(app.UserCtxKey).(*entity.User)
+		snapshot.Locale, _ = ctx.Value(app.LocaleCtxKey).(string)
+		snapshot.SessionID, _ = log.GetProperty(ctx, log.PropertyKeySessionID).(string)
+		if request := ctx.Value(app.RequestCtxKey); request != nil {
+			if u := w.requests.ToURL(request); u != nil {
+				snapshot.URL = u.String()
+			}
+		}
+	}
+
+	if snapshot.Tenant != nil {
+		tenantID = snapshot.Tenant.ID
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(snapshot); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to encode task origin")
+	}
+	return buf.Bytes(), tenantID, nil
+}
+
+func (w *DatabaseWorker) decodeOrigin(data []byte) (context.Context, error) {
+	snapshot := origin{}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&snapshot); err != nil {
+		return nil, errors.Wrap(err, "failed to decode task origin")
+	}
+
+	ctx := context.Background()
+	props := dto.Props{}
+	if snapshot.SessionID != "" {
+		props[log.PropertyKeySessionID] = snapshot.SessionID
+	}
+	if snapshot.Tenant != nil {
+		ctx = context.WithValue(ctx, app.TenantCtxKey, snapshot.Tenant)
+		props[log.PropertyKeyTenantID] = snapshot.Tenant.ID
+	}
+	if snapshot.User != nil {
+		ctx = context.WithValue(ctx, app.UserCtxKey, snapshot.User)
+		props[log.PropertyKeyUserID] = snapshot.User.ID
+	}
+	if snapshot.Locale != "" {
+		ctx = context.WithValue(ctx, app.LocaleCtxKey, snapshot.Locale)
+	}
+	if snapshot.URL != "" {
+		u, err := url.Parse(snapshot.URL)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse task origin url '%s'", snapshot.URL)
+		}
+		ctx = context.WithValue(ctx, app.RequestCtxKey, w.requests.FromURL(u))
+	}
+
+	return log.WithProperties(ctx, props), nil
+}
@@ -0,0 +1,165 @@
+package worker
+
+import (
+	"bytes"
+	"encoding/gob"
+	"reflect"
+	"runtime"
+	"sync"
+
+	"github.com/getfider/fider/app/pkg/errors"
+)
+
+var taskType = reflect.TypeOf(Task{})
+
+var registry = struct {
+	sync.RWMutex
+	constructors map[string]reflect.Value
+}{
+	constructors: make(map[string]reflect.Value),
+}
+
+// interfaceArg wraps arguments declared as interfaces, as gob can only encode those when wrapped
+type interfaceArg struct {
+	Value any
+}
+
+func constructorName(constructor any) string {
+	return runtime.FuncForPC(reflect.ValueOf(constructor).Pointer()).Name()
+}
+
+// RegisterTask makes given task constructor known to workers that persist tasks.
+// Persisted tasks are rebuilt by calling the constructor again with the decoded arguments
+func RegisterTask(constructor any) {
+	fn := reflect.ValueOf(constructor)
+	if fn.Kind() != reflect.Func || fn.Type().NumOut() != 1 || fn.Type().Out(0) != taskType {
+		panic(errors.New("task constructor '%T' must be a function that returns a worker.Task", constructor))
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+	registry.constructors[constructorName(constructor)] = fn
+}
+
+// WithArgs records the constructor and arguments used to create current task, so it can be persisted
+func (t Task) WithArgs(constructor any, args ...any) Task {
+	t.Handler = constructorName(constructor)
+	t.Args = args
+	return t
+}
+
+// IsPersistable returns true if task was created by a registered constructor
+func (t Task) IsPersistable() bool {
+	if t.Handler == "" {
+		return false
+	}
+	registry.RLock()
+	defer registry.RUnlock()
+	_, ok := registry.constructors[t.Handler]
+	return ok
+}
+
+func getConstructor(handler string) (reflect.Value, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+	fn, ok := registry.constructors[handler]
+	if !ok {
+		return reflect.Value{}, errors.New("task constructor '%s' is not registered", handler)
+	}
+	return fn, nil
+}
+
+// EncodeArgs serializes the arguments of given task
+func EncodeArgs(task Task) ([]byte, error) {
+	fn, err := getConstructor(task.Handler)
+	if err != nil {
+		return nil, err
+	}
+
+	fnType := fn.Type()
+	if fnType.NumIn() != len(task.Args) {
+		return nil, errors.New("task '%s' expects %d arguments, got %d", task.Handler, fnType.NumIn(), len(task.Args))
+	}
+
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	for i, arg := range task.Args {
+		paramType := fnType.In(i)
+		value := reflect.ValueOf(arg)
+		if arg != nil && !value.Type().AssignableTo(paramType) {
+			return nil, errors.New("argument %d of task '%s' must be '%s', got '%s'", i, task.Handler, paramType, value.Type())
+		}
+
+		present := arg != nil && !isNil(value)
+		if err := enc.Encode(present); err != nil {
+			return nil, errors.Wrap(err, "failed to encode argument %d of task '%s'", i, task.Handler)
+		}
+		if !present {
+			continue
+		}
+
+		if paramType.Kind() == reflect.Interface {
+			err = enc.Encode(&interfaceArg{Value: arg})
+		} else {
+			err = enc.EncodeValue(value)
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to encode argument %d of task '%s'", i, task.Handler)
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// DecodeTask rebuilds a task from its constructor name and serialized arguments
+func DecodeTask(handler string, data []byte) (Task, error) {
+	fn, err := getConstructor(handler)
+	if err != nil {
+		return Task{}, err
+	}
+
+	fnType := fn.Type()
+	dec := gob.NewDecoder(bytes.NewReader(data))
+	args := make([]reflect.Value, fnType.NumIn())
+	for i := range args {
+		paramType := fnType.In(i)
+
+		var present bool
+		if err := dec.Decode(&present); err != nil {
+			return Task{}, errors.Wrap(err, "failed to decode argument %d of task '%s'", i, handler)
+		}
+		if !present {
+			args[i] = reflect.Zero(paramType)
+			continue
+		}
+
+		if paramType.Kind() == reflect.Interface {
+			wrapped := &interfaceArg{}
+			if err := dec.Decode(wrapped); err != nil {
+				return Task{}, errors.Wrap(err, "failed to decode argument %d of task '%s'", i, handler)
+			}
+			value := reflect.ValueOf(wrapped.Value)
+			if !value.IsValid() || !value.Type().AssignableTo(paramType) {
+				return Task{}, errors.New("argument %d of task '%s' must be '%s'", i, handler, paramType)
+			}
+			args[i] = value
+			continue
+		}
+
+		value := reflect.New(paramType)
+		if err := dec.DecodeValue(value); err != nil {
+			return Task{}, errors.Wrap(err, "failed to decode argument %d of task '%s'", i, handler)
+		}
+		args[i] = value.Elem()
+	}
+
+	return fn.Call(args)[0].Interface().(Task), nil
+}
+
+func isNil(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return value.IsNil()
+	}
+	return false
+}
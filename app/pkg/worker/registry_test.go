@@ -0,0 +1,102 @@
+package worker_test
+
+import (
+	"encoding/gob"
+	"fmt"
+	"testing"
+
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/worker"
+)
+
+type greeter interface {
+	Greet() string
+}
+
+type english struct {
+	Name string
+}
+
+func (e *english) Greet() string {
+	return "Hello " + e.Name
+}
+
+type message struct {
+	Text string
+}
+
+func greetTask(g greeter, msg *message, times int, tags []string) worker.Task {
+	name := fmt.Sprintf("%d/%v/%v", times, msg == nil, tags)
+	if g != nil {
+		name = g.Greet() + "/" + name
+	}
+	if msg != nil {
+		name = msg.Text + "/" + name
+	}
+	return worker.Task{Name: name, Job: func(c *worker.Context) error {
+		return nil
+	}}.WithArgs(greetTask, g, msg, times, tags)
+}
+
+func unregisteredTask() worker.Task {
+	return worker.Task{Name: "Unregistered"}.WithArgs(unregisteredTask)
+}
+
+func init() {
+	gob.Register(&english{})
+	worker.RegisterTask(greetTask)
+}
+
+func TestRegistry_EncodeAndDecode(t *testing.T) {
+	RegisterT(t)
+
+	task := greetTask(&english{Name: "Jon"}, &message{Text: "Hi"}, 3, []string{"a", "b"})
+	Expect(task.IsPersistable()).IsTrue()
+
+	data, err := worker.EncodeArgs(task)
+	Expect(err).IsNil()
+
+	decoded, err := worker.DecodeTask(task.Handler, data)
+	Expect(err).IsNil()
+	Expect(decoded.Name).Equals("Hi/Hello Jon/3/false/[a b]")
+	Expect(decoded.Handler).Equals(task.Handler)
+}
+
+func TestRegistry_EncodeAndDecode_NilArgs(t *testing.T) {
+	RegisterT(t)
+
+	task := greetTask(nil, nil, 0, nil)
+
+	data, err := worker.EncodeArgs(task)
+	Expect(err).IsNil()
+
+	decoded, err := worker.DecodeTask(task.Handler, data)
+	Expect(err).IsNil()
+	Expect(decoded.Name).Equals("0/true/[]")
+}
+
+func TestRegistry_UnregisteredTask(t *testing.T) {
+	RegisterT(t)
+
+	task := unregisteredTask()
+	Expect(task.IsPersistable()).IsFalse()
+	Expect(dummyTask.IsPersistable()).IsFalse()
+
+	_, err := worker.EncodeArgs(task)
+	Expect(err).IsNotNil()
+
+	_, err = worker.DecodeTask(task.Handler, []byte{})
+	Expect(err).IsNotNil()
+}
+
+func TestRegistry_WrongArgs(t *testing.T) {
+	RegisterT(t)
+
+	task := worker.Task{Name: "Wrong"}.WithArgs(greetTask, "not a greeter", nil, 1, nil)
+	_, err := worker.EncodeArgs(task)
+	Expect(err).IsNotNil()
+
+	task = worker.Task{Name: "Missing"}.WithArgs(greetTask, nil)
+	_, err = worker.EncodeArgs(task)
+	Expect(err).IsNotNil()
+}
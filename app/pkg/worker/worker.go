@@ -18,10 +18,13 @@ type MiddlewareFunc func(Job) Job
 type Job func(c *Context) error
 
 //Task represents the Name and Job to be run on background
+//Handler and Args are only set for tasks that can be persisted, see WithArgs
 type Task struct {
 	OriginContext context.Context
 	Name          string
 	Job           Job
+	Handler       string
+	Args          []any
 }
 
 //Worker is a process that runs tasks
@@ -79,16 +82,21 @@ func (w *BackgroundWorker) Run(workerID string) {
 
 //Shutdown current worker
 func (w *BackgroundWorker) Shutdown(ctx context.Context) error {
-	if w.Length() > 0 {
+	return waitForQueue(ctx, w, w.Length)
+}
+
+//waitForQueue blocks until given length reaches zero or ctx is done
+func waitForQueue(ctx context.Context, logCtx context.Context, length func() int64) error {
+	if length() > 0 {
 		ticker := time.NewTicker(500 * time.Millisecond)
 		defer ticker.Stop()
 		for {
-			count := w.Length()
+			count := length()
 			if count == 0 {
 				return nil
 			}
 
-			log.Infof(w, "Waiting for work queue: @{Count}", dto.Props{
+			log.Infof(logCtx, "Waiting for work queue: @{Count}", dto.Props{
 				"Count": count,
 			})
 
@@ -1,9 +1,13 @@
 package blob_test
 
 import (
+	"bytes"
 	"context"
+	stdErrors "errors"
+	"io"
 	"os"
 	"testing"
+	"testing/iotest"
 
 	"github.com/aws/aws-sdk-go/aws"
 	awss3 "github.com/aws/aws-sdk-go/service/s3"
@@ -63,6 +67,7 @@ var tests = []struct {
 	test blobTestCase
 }{
 	{"AllOperations", AllOperations},
+	{"StreamOperations", StreamOperations},
 	{"DeleteUnkownFile", DeleteUnkownFile},
 	{"KeyFormats", KeyFormats},
 	{"PathTraversalOnRead", PathTraversalOnRead},
@@ -135,6 +140,50 @@ func AllOperations(ctx context.Context) {
 	}
 }
 
+func StreamOperations(ctx context.Context) {
+	ctxWithTenant1 := context.WithValue(ctx, app.TenantCtxKey, tenant1)
+
+	key := "backups/file2.png"
+	content, _ := os.ReadFile(env.Path("/app/services/blob/testdata/file2.png"))
+	err := bus.Dispatch(ctxWithTenant1, &cmd.StoreBlobStream{
+		Key:         key,
+		Reader:      bytes.NewReader(content),
+		ContentType: "image/png",
+	})
+	Expect(err).IsNil()
+
+	q := &query.OpenBlobByKey{Key: key}
+	err = bus.Dispatch(ctxWithTenant1, q)
+	Expect(err).IsNil()
+	stored, err := io.ReadAll(q.Result)
+	Expect(err).IsNil()
+	Expect(q.Result.Close()).IsNil()
+	Expect(stored).Equals(content)
+
+	list := &query.ListBlobs{Prefix: "backups/"}
+	err = bus.Dispatch(ctxWithTenant1, list)
+	Expect(err).IsNil()
+	Expect(list.Result).Equals([]string{key})
+
+	err = bus.Dispatch(ctxWithTenant1, &cmd.StoreBlobStream{
+		Key:    "backups/failed.png",
+		Reader: iotest.ErrReader(stdErrors.New("stream failed")),
+	})
+	Expect(err).IsNotNil()
+
+	err = bus.Dispatch(ctxWithTenant1, list)
+	Expect(err).IsNil()
+	Expect(list.Result).Equals([]string{key})
+
+	err = bus.Dispatch(ctxWithTenant1, &cmd.DeleteBlob{Key: key})
+	Expect(err).IsNil()
+
+	q = &query.OpenBlobByKey{Key: key}
+	err = bus.Dispatch(ctxWithTenant1, q)
+	Expect(q.Result).IsNil()
+	Expect(errors.Cause(err)).Equals(blob.ErrNotFound)
+}
+
 func DeleteUnkownFile(ctx context.Context) {
 	err := bus.Dispatch(ctx, &cmd.DeleteBlob{
 		Key: "path/somefile.txt",
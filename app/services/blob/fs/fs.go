@@ -2,6 +2,7 @@ package fs
 
 import (
 	"context"
+	"io"
 	"net/http"
 	"os"
 	"path"
@@ -45,7 +46,9 @@ func (s Service) Enabled() bool {
 func (s Service) Init() {
 	bus.AddHandler(listBlobs)
 	bus.AddHandler(getBlobByKey)
+	bus.AddHandler(openBlobByKey)
 	bus.AddHandler(storeBlob)
+	bus.AddHandler(storeBlobStream)
 	bus.AddHandler(deleteBlob)
 }
 
@@ -99,6 +102,23 @@ func getBlobByKey(ctx context.Context, q *query.GetBlobByKey) error {
 	return nil
 }
 
+func openBlobByKey(ctx context.Context, q *query.OpenBlobByKey) error {
+	if err := blob.ValidateKey(q.Key); err != nil {
+		return errors.Wrap(err, "failed to validate blob key '%s'", q.Key)
+	}
+
+	file, err := os.Open(keyFullPath(ctx, q.Key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return blob.ErrNotFound
+		}
+		return errors.Wrap(err, "failed to open '%s' from FileSystem", q.Key)
+	}
+
+	q.Result = file
+	return nil
+}
+
 func storeBlob(ctx context.Context, c *cmd.StoreBlob) error {
 	if err := blob.ValidateKey(c.Key); err != nil {
 		return errors.Wrap(err, "failed to validate blob key '%s'", c.Key)
@@ -119,6 +139,42 @@ func storeBlob(ctx context.Context, c *cmd.StoreBlob) error {
 	return nil
 }
 
+func storeBlobStream(ctx context.Context, c *cmd.StoreBlobStream) error {
+	if err := blob.ValidateKey(c.Key); err != nil {
+		return errors.Wrap(err, "failed to validate blob key '%s'", c.Key)
+	}
+
+	fullPath := keyFullPath(ctx, c.Key)
+	if err := os.MkdirAll(filepath.Dir(fullPath), perm); err != nil {
+		return errors.Wrap(err, "failed to create folder '%s' on FileSystem", fullPath)
+	}
+
+	// Content is written to a temporary file first, so that a failed stream doesn't leave a partial file behind
+	file, err := os.CreateTemp(filepath.Dir(fullPath), ".tmp-*")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary file for '%s' on FileSystem", c.Key)
+	}
+	defer func() { _ = os.Remove(file.Name()) }()
+
+	_, err = io.Copy(file, c.Reader)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to write file '%s' on FileSystem", fullPath)
+	}
+
+	if err := os.Chmod(file.Name(), perm); err != nil {
+		return errors.Wrap(err, "failed to set permissions of file '%s' on FileSystem", fullPath)
+	}
+
+	if err := os.Rename(file.Name(), fullPath); err != nil {
+		return errors.Wrap(err, "failed to create file '%s' on FileSystem", fullPath)
+	}
+
+	return nil
+}
+
 func deleteBlob(ctx context.Context, c *cmd.DeleteBlob) error {
 	fullPath := keyFullPath(ctx, c.Key)
 	err := os.Remove(fullPath)
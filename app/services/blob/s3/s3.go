@@ -15,6 +15,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/getfider/fider/app"
 
 	"github.com/getfider/fider/app/models/cmd"
@@ -69,7 +70,9 @@ func (s Service) Init() {
 
 	bus.AddHandler(listBlobs)
 	bus.AddHandler(getBlobByKey)
+	bus.AddHandler(openBlobByKey)
 	bus.AddHandler(storeBlob)
+	bus.AddHandler(storeBlobStream)
 	bus.AddHandler(deleteBlob)
 }
 
@@ -136,6 +139,26 @@ func getBlobByKey(ctx context.Context, q *query.GetBlobByKey) error {
 	return nil
 }
 
+func openBlobByKey(ctx context.Context, q *query.OpenBlobByKey) error {
+	if err := blob.ValidateKey(q.Key); err != nil {
+		return wrap(err, "failed to validate blob key '%s'", q.Key)
+	}
+
+	resp, err := DefaultClient.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(env.Config.BlobStorage.S3.BucketName),
+		Key:    aws.String(keyFullPathURL(ctx, q.Key)),
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return wrap(blob.ErrNotFound, "unable to find blob '%s' on S3", q.Key)
+		}
+		return wrap(err, "failed to get blob '%s' from S3", q.Key)
+	}
+
+	q.Result = resp.Body
+	return nil
+}
+
 func storeBlob(ctx context.Context, c *cmd.StoreBlob) error {
 	if err := blob.ValidateKey(c.Key); err != nil {
 		return wrap(err, "failed to validate blob key '%s'", c.Key)
@@ -155,6 +178,26 @@ func storeBlob(ctx context.Context, c *cmd.StoreBlob) error {
 	return nil
 }
 
+// storeBlobStream uses a multipart upload, so only a few parts are held in memory at any time
+func storeBlobStream(ctx context.Context, c *cmd.StoreBlobStream) error {
+	if err := blob.ValidateKey(c.Key); err != nil {
+		return wrap(err, "failed to validate blob key '%s'", c.Key)
+	}
+
+	uploader := s3manager.NewUploaderWithClient(DefaultClient)
+	_, err := uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket:      aws.String(env.Config.BlobStorage.S3.BucketName),
+		Key:         aws.String(keyFullPathURL(ctx, c.Key)),
+		ContentType: aws.String(c.ContentType),
+		ACL:         aws.String(s3.ObjectCannedACLPrivate),
+		Body:        c.Reader,
+	})
+	if err != nil {
+		return wrap(err, "failed to upload blob '%s' to S3", c.Key)
+	}
+	return nil
+}
+
 func deleteBlob(ctx context.Context, c *cmd.DeleteBlob) error {
 	_, err := DefaultClient.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(env.Config.BlobStorage.S3.BucketName),
@@ -1,8 +1,10 @@
 package sql
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"io"
 	"sort"
 	"time"
 
@@ -42,11 +44,17 @@ func (s Service) Enabled() bool {
 func (s Service) Init() {
 	bus.AddHandler(listBlobs)
 	bus.AddHandler(getBlobByKey)
+	bus.AddHandler(openBlobByKey)
 	bus.AddHandler(storeBlob)
+	bus.AddHandler(storeBlobStream)
 	bus.AddHandler(deleteBlob)
 }
 
+// chunkSize is the size of the chunks streamed blobs are stored in, so that they are never held in memory as a whole
+const chunkSize = 1024 * 1024
+
 type dbBlob struct {
+	ID          int    `db:"id"`
 	Key         string `db:"key"`
 	ContentType string `db:"content_type"`
 	Size        int64  `db:"size"`
@@ -95,7 +103,11 @@ func getBlobByKey(ctx context.Context, q *query.GetBlobByKey) error {
 		defer trx.MustCommit()
 
 		b := dbBlob{}
-		err = trx.Get(&b, "SELECT file, content_type, size FROM blobs WHERE key = $1 AND (tenant_id = $2 OR ($2 IS NULL AND tenant_id IS NULL))", q.Key, tenantID)
+		err = trx.Get(&b, `
+			SELECT b.file || COALESCE((SELECT string_agg(c.data, ''::bytea ORDER BY c.seq) FROM blob_chunks c WHERE c.blob_id = b.id), ''::bytea) AS file,
+				b.content_type, b.size
+			FROM blobs b
+			WHERE b.key = $1 AND (b.tenant_id = $2 OR ($2 IS NULL AND b.tenant_id IS NULL))`, q.Key, tenantID)
 		if err != nil {
 			if err == app.ErrNotFound {
 				return blob.ErrNotFound
@@ -112,6 +124,86 @@ func getBlobByKey(ctx context.Context, q *query.GetBlobByKey) error {
 	})
 }
 
+// Blobs stored from a stream are read one chunk at a time, others are held in a single column and read into memory
+func openBlobByKey(ctx context.Context, q *query.OpenBlobByKey) error {
+	if err := blob.ValidateKey(q.Key); err != nil {
+		return errors.Wrap(err, "failed to validate blob key '%s'", q.Key)
+	}
+
+	blob.EnsureAuthorizedPrefix(ctx, q.Key)
+
+	return using(ctx, func(tenantID sql.NullInt64) error {
+		trx, err := dbx.BeginTx(ctx)
+		if err != nil {
+			return errors.Wrap(err, "failed to open transaction")
+		}
+		defer trx.MustCommit()
+
+		b := dbBlob{}
+		err = trx.Get(&b, "SELECT id, file FROM blobs WHERE key = $1 AND (tenant_id = $2 OR ($2 IS NULL AND tenant_id IS NULL))", q.Key, tenantID)
+		if err != nil {
+			if err == app.ErrNotFound {
+				return blob.ErrNotFound
+			}
+			return errors.Wrap(err, "failed to get blob with key '%s'", q.Key)
+		}
+
+		q.Result = io.NopCloser(io.MultiReader(bytes.NewReader(b.Content), &chunkReader{ctx: ctx, blobID: b.ID}))
+		return nil
+	})
+}
+
+// chunkReader reads the chunks of a blob in order, loading a single chunk at a time
+type chunkReader struct {
+	ctx    context.Context
+	blobID int
+	seq    int
+	buffer []byte
+	done   bool
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buffer) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.next(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.buffer)
+	r.buffer = r.buffer[n:]
+	return n, nil
+}
+
+func (r *chunkReader) next() error {
+	trx, err := dbx.BeginTx(r.ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to open transaction")
+	}
+	defer trx.MustCommit()
+
+	chunks := []*dbChunk{}
+	err = trx.Select(&chunks, "SELECT data FROM blob_chunks WHERE blob_id = $1 AND seq = $2", r.blobID, r.seq)
+	if err != nil {
+		return errors.Wrap(err, "failed to read chunk %d of blob with id '%d'", r.seq, r.blobID)
+	}
+
+	if len(chunks) == 0 {
+		r.done = true
+		return nil
+	}
+
+	r.buffer = chunks[0].Data
+	r.seq++
+	return nil
+}
+
+type dbChunk struct {
+	Data []byte `db:"data"`
+}
+
 func storeBlob(ctx context.Context, c *cmd.StoreBlob) error {
 	blob.EnsureAuthorizedPrefix(ctx, c.Key)
 
@@ -126,13 +218,7 @@ func storeBlob(ctx context.Context, c *cmd.StoreBlob) error {
 		}
 		defer trx.MustCommit()
 
-		now := time.Now()
-		_, err = trx.Execute(`
-		INSERT INTO blobs (tenant_id, key, size, content_type, file, created_at, modified_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (tenant_id, key)
-		DO UPDATE SET size = $3, content_type = $4, file = $5, modified_at = $7
-		`, tenantID, c.Key, int64(len(c.Content)), c.ContentType, c.Content, now, now)
-		if err != nil {
+		if _, err := upsertBlob(trx, tenantID, c.Key, int64(len(c.Content)), c.ContentType, c.Content); err != nil {
 			return errors.Wrap(err, "failed to store blob with key '%s'", c.Key)
 		}
 
@@ -144,6 +230,77 @@ func storeBlob(ctx context.Context, c *cmd.StoreBlob) error {
 	})
 }
 
+// upsertBlob stores given content on the blobs table, removing the chunks of a previous blob with the same key
+func upsertBlob(trx *dbx.Trx, tenantID sql.NullInt64, key string, size int64, contentType string, content []byte) (int, error) {
+	var id int
+	now := time.Now()
+	err := trx.Scalar(&id, `
+	INSERT INTO blobs (tenant_id, key, size, content_type, file, created_at, modified_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (tenant_id, key)
+	DO UPDATE SET size = $3, content_type = $4, file = $5, modified_at = $7
+	RETURNING id
+	`, tenantID, key, size, contentType, content, now, now)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := trx.Execute("DELETE FROM blob_chunks WHERE blob_id = $1", id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// storeBlobStream stores the content in chunks within a single transaction, so a failed stream leaves no blob behind
+func storeBlobStream(ctx context.Context, c *cmd.StoreBlobStream) error {
+	blob.EnsureAuthorizedPrefix(ctx, c.Key)
+
+	if err := blob.ValidateKey(c.Key); err != nil {
+		return errors.Wrap(err, "failed to validate blob key '%s'", c.Key)
+	}
+
+	return using(ctx, func(tenantID sql.NullInt64) error {
+		trx, err := dbx.BeginTx(ctx)
+		if err != nil {
+			return errors.Wrap(err, "failed to open transaction")
+		}
+
+		if err := storeChunks(trx, tenantID, c); err != nil {
+			trx.MustRollback()
+			return errors.Wrap(err, "failed to store blob with key '%s'", c.Key)
+		}
+
+		return trx.Commit()
+	})
+}
+
+func storeChunks(trx *dbx.Trx, tenantID sql.NullInt64, c *cmd.StoreBlobStream) error {
+	id, err := upsertBlob(trx, tenantID, c.Key, 0, c.ContentType, []byte{})
+	if err != nil {
+		return err
+	}
+
+	size := int64(0)
+	buffer := make([]byte, chunkSize)
+	for seq := 0; ; seq++ {
+		n, err := io.ReadFull(c.Reader, buffer)
+		if n > 0 {
+			if _, err := trx.Execute("INSERT INTO blob_chunks (blob_id, seq, data) VALUES ($1, $2, $3)", id, seq, buffer[:n]); err != nil {
+				return err
+			}
+			size += int64(n)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = trx.Execute("UPDATE blobs SET size = $1 WHERE id = $2", size, id)
+	return err
+}
+
 func deleteBlob(ctx context.Context, c *cmd.DeleteBlob) error {
 	blob.EnsureAuthorizedPrefix(ctx, c.Key)
 	
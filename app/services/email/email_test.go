@@ -2,6 +2,7 @@ package email_test
 
 import (
 	"context"
+	"html/template"
 	"testing"
 
 	"github.com/getfider/fider/app/models/dto"
@@ -91,6 +92,30 @@ Hello World Fider!
 </html>`)
 }
 
+func TestRenderMessage_Digest(t *testing.T) {
+	RegisterT(t)
+
+	message := email.RenderMessage(context.Background(), "digest", email.NoReply, dto.Props{
+		"siteName": "Demonstration",
+		"count":    2,
+		"posts": []dto.Props{
+			{
+				"link": template.HTML("<a href='http://demo.test.fider.io/posts/1/dark-mode'>Dark mode (#1)</a>"),
+				"entries": []dto.Props{
+					{"title": template.HTML("<strong>Jon Snow</strong> left a comment:"), "content": template.HTML("<p>I agree</p>")},
+					{"title": template.HTML("<strong>Arya Stark</strong> left a comment:"), "content": template.HTML("<p>Me too</p>")},
+				},
+			},
+		},
+		"change": "<a href='http://demo.test.fider.io/settings'>change your notification preferences</a>",
+	})
+	Expect(message.Subject).Equals("[Demonstration] 2 new updates on the posts you follow")
+	Expect(message.Body).ContainsSubstring("<a href='http://demo.test.fider.io/posts/1/dark-mode'>Dark mode (#1)</a>")
+	Expect(message.Body).ContainsSubstring("<strong>Jon Snow</strong> left a comment:")
+	Expect(message.Body).ContainsSubstring("<p>Me too</p>")
+	Expect(message.Body).ContainsSubstring("You are receiving this digest because you are subscribed to these posts.")
+}
+
 func TestCanSendTo(t *testing.T) {
 	RegisterT(t)
 
@@ -19,6 +19,8 @@ import (
 	"github.com/getfider/fider/app/pkg/errors"
 	"github.com/getfider/fider/app/pkg/jsonq"
 	"github.com/getfider/fider/app/pkg/jwt"
+	"github.com/getfider/fider/app/pkg/oidc"
+	"github.com/getfider/fider/app/pkg/rand"
 	"github.com/getfider/fider/app/pkg/validate"
 	"github.com/getfider/fider/app/pkg/web"
 	"golang.org/x/oauth2"
@@ -116,10 +118,15 @@ func parseOAuthRawProfile(ctx context.Context, c *cmd.ParseOAuthRawProfile) erro
 		return err
 	}
 
+	idPath, namePath, emailPath := config.JSONUserIDPath, config.JSONUserNamePath, config.JSONUserEmailPath
+	if config.IsOIDC {
+		idPath, namePath, emailPath = oidcUserIDPath, oidcUserNamePath, oidcUserEmailPath
+	}
+
 	query := jsonq.New(c.Body)
 
 	// Extract and combine name parts
-	name := extractCompositeName(query, config.JSONUserNamePath)
+	name := extractCompositeName(query, namePath)
 
 	// Extract roles if path is configured
 	var roles []string
@@ -128,12 +135,19 @@ func parseOAuthRawProfile(ctx context.Context, c *cmd.ParseOAuthRawProfile) erro
 	}
 
 	profile := &dto.OAuthUserProfile{
-		ID:    strings.TrimSpace(query.String(config.JSONUserIDPath)),
+		ID:    strings.TrimSpace(query.String(idPath)),
 		Name:  name,
-		Email: strings.ToLower(strings.TrimSpace(query.String(config.JSONUserEmailPath))),
+		Email: strings.ToLower(strings.TrimSpace(query.String(emailPath))),
 		Roles: roles,
 	}
 
+	// Unverified emails can't be trusted to match existing users
+	if config.IsOIDC {
+		if verified := strings.Trim(string(query.Raw("email_verified")), `"`); verified == "false" {
+			profile.Email = ""
+		}
+	}
+
 	if profile.ID == "" {
 		return app.ErrUserIDRequired
 	}
@@ -258,18 +272,36 @@ func getOAuthAuthorizationURL(ctx context.Context, q *query.GetOAuthAuthorizatio
 		return err
 	}
 
+	authorizeURL := config.AuthorizeURL
+	nonce := ""
+	if config.IsOIDC {
+		discovery, err := oidcClient.Discover(ctx, config.IssuerURL)
+		if err != nil {
+			return err
+		}
+		authorizeURL = discovery.AuthorizationEndpoint
+		nonce = rand.String(32)
+	}
+
 	oauthBaseURL := web.OAuthBaseURL(ctx)
-	authURL, _ := url.Parse(config.AuthorizeURL)
+	authURL, _ := url.Parse(authorizeURL)
 	parameters := getProviderInitialParams(authURL)
 	parameters.Add("client_id", config.ClientID)
 	parameters.Add("scope", config.Scope)
 	parameters.Add("redirect_uri", fmt.Sprintf("%s/oauth/%s/callback", oauthBaseURL, q.Provider))
 	parameters.Add("response_type", "code")
 
+	if config.IsOIDC {
+		parameters.Add("nonce", nonce)
+		parameters.Add("code_challenge", oidc.CodeChallenge(oidcCodeVerifier(nonce)))
+		parameters.Add("code_challenge_method", "S256")
+	}
+
 	state, err := jwt.Encode(jwt.OAuthStateClaims{
 		Redirect:   q.Redirect,
 		Identifier: q.Identifier,
 		Code:       q.Code,
+		Nonce:      nonce,
 	})
 
 	if err != nil {
@@ -293,7 +325,7 @@ func getOAuthProfile(ctx context.Context, q *query.GetOAuthProfile) error {
 		return errors.New("Provider %s is disabled", q.Provider)
 	}
 
-	rawProfile := &query.GetOAuthRawProfile{Provider: q.Provider, Code: q.Code}
+	rawProfile := &query.GetOAuthRawProfile{Provider: q.Provider, Code: q.Code, Nonce: q.Nonce}
 	err = bus.Dispatch(ctx, rawProfile)
 	if err != nil {
 		return err
@@ -315,6 +347,10 @@ func getOAuthRawProfile(ctx context.Context, q *query.GetOAuthRawProfile) error
 		return err
 	}
 
+	if config.IsOIDC {
+		return getOIDCRawProfile(ctx, config, q)
+	}
+
 	// Guard against SSRF: TokenURL triggers a server-side request during the token exchange.
 	if msgs := validate.WebhookURL(config.TokenURL); len(msgs) > 0 {
 		return errors.New("Token URL is not allowed: %s", strings.Join(msgs, "; "))
@@ -385,6 +421,21 @@ func listActiveOAuthProviders(ctx context.Context, q *query.ListActiveOAuthProvi
 			list = append(list, p)
 		}
 	}
+
+	samlConfig := &query.GetSAMLConfig{}
+	err = bus.Dispatch(ctx, samlConfig)
+	if err != nil && errors.Cause(err) != app.ErrNotFound {
+		return errors.Wrap(err, "failed to get SAML config")
+	}
+	if err == nil && samlConfig.Result.Status == enum.OAuthConfigEnabled {
+		list = append(list, &dto.OAuthProviderOption{
+			Provider:    app.SAMLProvider,
+			DisplayName: samlConfig.Result.DisplayName,
+			URL:         "/saml/login",
+			IsEnabled:   true,
+		})
+	}
+
 	q.Result = list
 	return nil
 }
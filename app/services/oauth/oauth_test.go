@@ -15,8 +15,10 @@ import (
 	"github.com/getfider/fider/app/models/query"
 
 	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/env"
 	"github.com/getfider/fider/app/pkg/errors"
 	"github.com/getfider/fider/app/pkg/jwt"
+	"github.com/getfider/fider/app/pkg/oidc"
 	"github.com/getfider/fider/app/pkg/web"
 
 	. "github.com/getfider/fider/app/pkg/assert"
@@ -696,3 +698,119 @@ func TestGetOAuthRawProfile_BlocksInternalTokenURL(t *testing.T) {
 	Expect(err).IsNotNil()
 	Expect(rawProfile.Result).Equals("")
 }
+
+func TestGetAuthURL_OIDC(t *testing.T) {
+	RegisterT(t)
+	bus.Init(&oauth.Service{})
+
+	env.Config.AllowPrivateNetworkTargets = true
+	defer func() { env.Config.AllowPrivateNetworkTargets = false }()
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCustomOAuthConfigByProvider) error {
+		q.Result = &entity.OAuthConfig{
+			Provider:  q.Provider,
+			ClientID:  "OIDC_CL_ID",
+			Scope:     "openid profile email",
+			IsOIDC:    true,
+			IssuerURL: "https://oidc.example.org/",
+		}
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, c *cmd.HTTPRequest) error {
+		Expect(c.URL).Equals("https://oidc.example.org/.well-known/openid-configuration")
+		c.ResponseStatusCode = 200
+		c.ResponseBody = []byte(`{
+			"issuer": "https://oidc.example.org",
+			"authorization_endpoint": "https://oidc.example.org/authorize",
+			"token_endpoint": "https://oidc.example.org/token",
+			"jwks_uri": "https://oidc.example.org/keys"
+		}`)
+		return nil
+	})
+
+	ctx := newGetContext("http://login.test.fider.io:3000")
+	authURL := &query.GetOAuthAuthorizationURL{
+		Provider:   "_oidc",
+		Redirect:   "http://example.org",
+		Identifier: "456",
+	}
+
+	err := bus.Dispatch(ctx, authURL)
+	Expect(err).IsNil()
+
+	u, _ := url.Parse(authURL.Result)
+	Expect(u.Host).Equals("oidc.example.org")
+	Expect(u.Path).Equals("/authorize")
+
+	params := u.Query()
+	claims, err := jwt.DecodeOAuthStateClaims(params.Get("state"))
+	Expect(err).IsNil()
+	Expect(claims.Identifier).Equals("456")
+	Expect(claims.Nonce).HasLen(32)
+	Expect(params.Get("nonce")).Equals(claims.Nonce)
+	Expect(params.Get("scope")).Equals("openid profile email")
+	Expect(params.Get("code_challenge_method")).Equals("S256")
+	Expect(params.Get("code_challenge")).Equals(oidc.CodeChallenge(oidc.NewCodeVerifier(env.Config.JWTSecret, claims.Nonce)))
+}
+
+func TestParseProfileResponse_OIDC(t *testing.T) {
+	RegisterT(t)
+	bus.Init(&oauth.Service{})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCustomOAuthConfigByProvider) error {
+		q.Result = &entity.OAuthConfig{
+			Provider:          q.Provider,
+			IsOIDC:            true,
+			JSONUserRolesPath: "groups",
+		}
+		return nil
+	})
+
+	ctx := newGetContext("http://login.test.fider.io:3000")
+	profile := &cmd.ParseOAuthRawProfile{
+		Provider: "_oidc",
+		Body:     `{ "sub": "00u1", "preferred_username": "jon.snow", "email": "Jon.Snow@got.com", "email_verified": true, "groups": ["fider-users"] }`,
+	}
+
+	err := bus.Dispatch(ctx, profile)
+	Expect(err).IsNil()
+	Expect(profile.Result.ID).Equals("00u1")
+	Expect(profile.Result.Name).Equals("jon.snow")
+	Expect(profile.Result.Email).Equals("jon.snow@got.com")
+	Expect(profile.Result.Roles).Equals([]string{"fider-users"})
+
+	profile = &cmd.ParseOAuthRawProfile{
+		Provider: "_oidc",
+		Body:     `{ "sub": "00u1", "name": "Jon Snow", "email": "jon.snow@got.com", "email_verified": false }`,
+	}
+
+	err = bus.Dispatch(ctx, profile)
+	Expect(err).IsNil()
+	Expect(profile.Result.Name).Equals("Jon Snow")
+	Expect(profile.Result.Email).Equals("")
+}
+
+func TestGetOAuthRawProfile_OIDC_BlocksInternalIssuer(t *testing.T) {
+	RegisterT(t)
+	bus.Init(&oauth.Service{})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCustomOAuthConfigByProvider) error {
+		q.Result = &entity.OAuthConfig{
+			Provider:  q.Provider,
+			Status:    enum.OAuthConfigEnabled,
+			ClientID:  "client",
+			IsOIDC:    true,
+			IssuerURL: "http://169.254.169.254/latest",
+		}
+		return nil
+	})
+
+	ctx := newGetContext("http://login.test.fider.io:3000")
+	rawProfile := &query.GetOAuthRawProfile{Provider: "_oidc", Code: "any-code", Nonce: "any-nonce"}
+
+	// No handler for cmd.HTTPRequest is registered, so dispatching a request would panic
+	err := bus.Dispatch(ctx, rawProfile)
+	Expect(err).IsNotNil()
+	Expect(rawProfile.Result).Equals("")
+}
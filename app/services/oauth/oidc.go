@@ -0,0 +1,109 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/env"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/oidc"
+	"github.com/getfider/fider/app/pkg/validate"
+	"github.com/getfider/fider/app/pkg/web"
+	"golang.org/x/oauth2"
+)
+
+// OpenID Connect providers publish standard claims, so custom JSON paths are not needed
+const (
+	oidcUserIDPath    = "sub"
+	oidcUserNamePath  = "name, preferred_username, nickname"
+	oidcUserEmailPath = "email"
+)
+
+// oidcClient is shared by all tenants, discovery documents and key sets are public and cached by URL
+var oidcClient = oidc.NewClient(fetchOIDCDocument)
+
+// fetchOIDCDocument downloads discovery documents and key sets of OpenID Providers
+func fetchOIDCDocument(ctx context.Context, url string) ([]byte, error) {
+	// Guard against SSRF: the issuer URL is user-configurable and its documents are fetched server-side.
+	if msgs := validate.WebhookURL(url); len(msgs) > 0 {
+		return nil, errors.New("URL '%s' is not allowed: %s", url, strings.Join(msgs, "; "))
+	}
+
+	req := &cmd.HTTPRequest{
+		URL:    url,
+		Method: "GET",
+		Headers: map[string]string{
+			"Accept": "application/json",
+		},
+	}
+
+	if err := bus.Dispatch(ctx, req); err != nil {
+		return nil, err
+	}
+
+	if req.ResponseStatusCode != 200 {
+		return nil, errors.New("Failed to request '%s'. Status Code: %d", url, req.ResponseStatusCode)
+	}
+
+	return req.ResponseBody, nil
+}
+
+// oidcCodeVerifier returns the PKCE code verifier of the authorization request with given nonce
+func oidcCodeVerifier(nonce string) string {
+	return oidc.NewCodeVerifier(env.Config.JWTSecret, nonce)
+}
+
+// getOIDCRawProfile exchanges the code using PKCE and returns the claims of the verified ID token
+func getOIDCRawProfile(ctx context.Context, config *entity.OAuthConfig, q *query.GetOAuthRawProfile) error {
+	if q.Nonce == "" {
+		return errors.New("Nonce is required to sign in with OpenID Connect")
+	}
+
+	discovery, err := oidcClient.Discover(ctx, config.IssuerURL)
+	if err != nil {
+		return err
+	}
+
+	// Guard against SSRF: the token endpoint comes from the discovery document and is requested server-side.
+	if msgs := validate.WebhookURL(discovery.TokenEndpoint); len(msgs) > 0 {
+		return errors.New("Token URL is not allowed: %s", strings.Join(msgs, "; "))
+	}
+
+	oauthBaseURL := web.OAuthBaseURL(ctx)
+	oauthToken, err := (&oauth2.Config{
+		ClientID:     config.ClientID,
+		ClientSecret: config.ClientSecret,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  discovery.AuthorizationEndpoint,
+			TokenURL: discovery.TokenEndpoint,
+		},
+		RedirectURL: fmt.Sprintf("%s/oauth/%s/callback", oauthBaseURL, q.Provider),
+	}).Exchange(ctx, q.Code, oauth2.VerifierOption(oidcCodeVerifier(q.Nonce)))
+	if err != nil {
+		return err
+	}
+
+	rawIDToken, _ := oauthToken.Extra("id_token").(string)
+	if rawIDToken == "" {
+		return errors.New("Token response of '%s' has no ID token", config.IssuerURL)
+	}
+
+	claims, err := oidcClient.VerifyIDToken(ctx, discovery, rawIDToken, config.ClientID, q.Nonce)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(claims)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal ID token claims")
+	}
+
+	q.Result = string(body)
+	return nil
+}
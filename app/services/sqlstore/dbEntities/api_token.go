@@ -0,0 +1,43 @@
+package dbEntities
+
+import (
+	"time"
+
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/lib/pq"
+)
+
+type APIToken struct {
+	ID         int            `db:"id"`
+	UserID     int            `db:"user_id"`
+	Name       string         `db:"name"`
+	Scopes     pq.StringArray `db:"scopes"`
+	ExpiresAt  dbx.NullTime   `db:"expires_at"`
+	LastUsedAt dbx.NullTime   `db:"last_used_at"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
+
+func (t *APIToken) ToModel() *entity.APIToken {
+	token := &entity.APIToken{
+		ID:        t.ID,
+		Name:      t.Name,
+		Scopes:    make([]enum.APIScope, len(t.Scopes)),
+		CreatedAt: t.CreatedAt,
+	}
+
+	for i, scope := range t.Scopes {
+		token.Scopes[i] = enum.APIScope(scope)
+	}
+
+	if t.ExpiresAt.Valid {
+		token.ExpiresAt = &t.ExpiresAt.Time
+	}
+
+	if t.LastUsedAt.Valid {
+		token.LastUsedAt = &t.LastUsedAt.Time
+	}
+
+	return token
+}
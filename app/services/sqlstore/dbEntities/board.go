@@ -0,0 +1,32 @@
+package dbEntities
+
+import (
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/lib/pq"
+)
+
+type Board struct {
+	ID                  int            `db:"id"`
+	Name                string         `db:"name"`
+	Slug                string         `db:"slug"`
+	Description         string         `db:"description"`
+	DescriptionTemplate string         `db:"description_template"`
+	IsPublic            bool           `db:"is_public"`
+	HasRoadmap          bool           `db:"has_roadmap"`
+	DefaultTags         pq.StringArray `db:"default_tags"`
+	PostsCount          int            `db:"posts_count"`
+}
+
+func (b *Board) ToModel() *entity.Board {
+	return &entity.Board{
+		ID:                  b.ID,
+		Name:                b.Name,
+		Slug:                b.Slug,
+		Description:         b.Description,
+		DescriptionTemplate: b.DescriptionTemplate,
+		IsPublic:            b.IsPublic,
+		HasRoadmap:          b.HasRoadmap,
+		DefaultTags:         b.DefaultTags,
+		PostsCount:          b.PostsCount,
+	}
+}
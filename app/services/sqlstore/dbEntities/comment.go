@@ -11,6 +11,7 @@ import (
 
 type Comment struct {
 	ID             int            `db:"id"`
+	PostID         int            `db:"post_id"`
 	Content        string         `db:"content"`
 	CreatedAt      time.Time      `db:"created_at"`
 	User           *User          `db:"user"`
@@ -19,22 +20,31 @@ type Comment struct {
 	EditedBy       *User          `db:"edited_by"`
 	ReactionCounts dbx.NullString `db:"reaction_counts"`
 	IsApproved     bool           `db:"is_approved"`
+	IsInternal     bool           `db:"is_internal"`
+	ParentID       dbx.NullInt    `db:"parent_id"`
 }
 
 func (c *Comment) ToModel(ctx context.Context) *entity.Comment {
 	comment := &entity.Comment{
 		ID:          c.ID,
+		PostID:      c.PostID,
 		Content:     c.Content,
 		CreatedAt:   c.CreatedAt,
 		User:        c.User.ToModel(ctx),
 		Attachments: c.Attachments,
 		IsApproved:  c.IsApproved,
+		IsInternal:  c.IsInternal,
 	}
 	if c.EditedAt.Valid {
 		comment.EditedBy = c.EditedBy.ToModel(ctx)
 		comment.EditedAt = &c.EditedAt.Time
 	}
 
+	if c.ParentID.Valid {
+		parentID := int(c.ParentID.Int64)
+		comment.ParentID = &parentID
+	}
+
 	if c.ReactionCounts.Valid {
 		_ = json.Unmarshal([]byte(c.ReactionCounts.String), &comment.ReactionCounts)
 	}
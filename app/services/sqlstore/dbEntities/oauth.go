@@ -13,6 +13,8 @@ type OAuthConfig struct {
 	LogoBlobKey       string         `db:"logo_bkey"`
 	Status            int            `db:"status"`
 	IsTrusted         bool           `db:"is_trusted"`
+	IsOIDC            bool           `db:"is_oidc"`
+	IssuerURL         string         `db:"issuer_url"`
 	ClientID          string         `db:"client_id"`
 	ClientSecret      string         `db:"client_secret"`
 	AuthorizeURL      string         `db:"authorize_url"`
@@ -42,6 +44,8 @@ func (m *OAuthConfig) ToModel() *entity.OAuthConfig {
 		DisplayName:       m.DisplayName,
 		Status:            m.Status,
 		IsTrusted:         m.IsTrusted,
+		IsOIDC:            m.IsOIDC,
+		IssuerURL:         m.IssuerURL,
 		LogoBlobKey:       m.LogoBlobKey,
 		ClientID:          m.ClientID,
 		ClientSecret:      m.ClientSecret,
@@ -20,6 +20,7 @@ type Post struct {
 	Search         []byte         `db:"search"`
 	User           *User          `db:"user"`
 	HasVoted       bool           `db:"has_voted"`
+	VoteWeight     int            `db:"vote_weight"`
 	VotesCount     int            `db:"votes_count"`
 	CommentsCount  int            `db:"comments_count"`
 	RecentVotes    int            `db:"recent_votes_count"`
@@ -34,6 +35,14 @@ type Post struct {
 	OriginalStatus dbx.NullInt    `db:"original_status"`
 	Tags           pq.StringArray `db:"tags"`
 	IsApproved     bool           `db:"is_approved"`
+	AttributeTotal dbx.NullFloat  `db:"attribute_total"`
+	CustomStatus   *PostStatus    `db:"custom_status"`
+	SortKey        string         `db:"sort_key"`
+	Snippet        dbx.NullString `db:"snippet"`
+	BoardID        dbx.NullInt    `db:"board_id"`
+	BoardName      dbx.NullString `db:"board_name"`
+	BoardSlug      dbx.NullString `db:"board_slug"`
+	BoardIsPublic  bool           `db:"board_is_public"`
 }
 
 func (i *Post) ToModel(ctx context.Context) *entity.Post {
@@ -46,11 +55,26 @@ func (i *Post) ToModel(ctx context.Context) *entity.Post {
 		CreatedAt:     i.CreatedAt,
 		User:          i.User.ToModel(ctx),
 		HasVoted:      i.HasVoted,
+		VoteWeight:    i.VoteWeight,
 		VotesCount:    i.VotesCount,
 		CommentsCount: i.CommentsCount,
 		Status:        enum.PostStatus(i.Status),
 		Tags:          i.Tags,
 		IsApproved:    i.IsApproved,
+		CustomStatus:  i.CustomStatus.ToModel(),
+	}
+
+	if i.AttributeTotal.Valid {
+		post.AttributeTotal = &i.AttributeTotal.Float64
+	}
+
+	if i.BoardID.Valid {
+		post.Board = &entity.PostBoard{
+			ID:       int(i.BoardID.Int64),
+			Name:     i.BoardName.String,
+			Slug:     i.BoardSlug.String,
+			IsPublic: i.BoardIsPublic,
+		}
 	}
 
 	if i.Response.Valid {
@@ -0,0 +1,32 @@
+package dbEntities
+
+import (
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/pkg/dbx"
+)
+
+// PostStatus is nullable as it is also used for posts that don't have a custom status
+type PostStatus struct {
+	Status        dbx.NullInt    `db:"status"`
+	Name          dbx.NullString `db:"name"`
+	Slug          dbx.NullString `db:"slug"`
+	Color         dbx.NullString `db:"color"`
+	AcceptsVotes  bool           `db:"accepts_votes"`
+	RoadmapColumn dbx.NullString `db:"roadmap_column"`
+}
+
+func (s *PostStatus) ToModel() *entity.CustomPostStatus {
+	if s == nil || !s.Status.Valid {
+		return nil
+	}
+
+	return &entity.CustomPostStatus{
+		Status:        enum.PostStatus(s.Status.Int64),
+		Name:          s.Name.String,
+		Slug:          s.Slug.String,
+		Color:         s.Color.String,
+		AcceptsVotes:  s.AcceptsVotes,
+		RoadmapColumn: s.RoadmapColumn.String,
+	}
+}
@@ -0,0 +1,26 @@
+package dbEntities
+
+import (
+	"context"
+	"time"
+
+	"github.com/getfider/fider/app/models/entity"
+)
+
+type Revision struct {
+	Version   int       `db:"version"`
+	Title     string    `db:"title"`
+	Content   string    `db:"content"`
+	CreatedAt time.Time `db:"created_at"`
+	CreatedBy *User     `db:"created_by"`
+}
+
+func (r *Revision) ToModel(ctx context.Context) *entity.Revision {
+	return &entity.Revision{
+		Version:   r.Version,
+		Title:     r.Title,
+		Content:   r.Content,
+		CreatedAt: r.CreatedAt,
+		CreatedBy: r.CreatedBy.ToModel(ctx),
+	}
+}
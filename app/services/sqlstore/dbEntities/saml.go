@@ -0,0 +1,31 @@
+package dbEntities
+
+import "github.com/getfider/fider/app/models/entity"
+
+type SAMLConfig struct {
+	Status         int    `db:"status"`
+	DisplayName    string `db:"display_name"`
+	IdPEntityID    string `db:"idp_entity_id"`
+	IdPSSOURL      string `db:"idp_sso_url"`
+	IdPCertificate string `db:"idp_certificate"`
+	NameAttribute  string `db:"name_attribute"`
+	EmailAttribute string `db:"email_attribute"`
+	RolesAttribute string `db:"roles_attribute"`
+	AllowedRoles   string `db:"allowed_roles"`
+	IsTrusted      bool   `db:"is_trusted"`
+}
+
+func (m *SAMLConfig) ToModel() *entity.SAMLConfig {
+	return &entity.SAMLConfig{
+		Status:         m.Status,
+		DisplayName:    m.DisplayName,
+		IdPEntityID:    m.IdPEntityID,
+		IdPSSOURL:      m.IdPSSOURL,
+		IdPCertificate: m.IdPCertificate,
+		NameAttribute:  m.NameAttribute,
+		EmailAttribute: m.EmailAttribute,
+		RolesAttribute: m.RolesAttribute,
+		AllowedRoles:   m.AllowedRoles,
+		IsTrusted:      m.IsTrusted,
+	}
+}
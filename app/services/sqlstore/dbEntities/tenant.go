@@ -29,6 +29,8 @@ type Tenant struct {
 	IsPro                 bool         `db:"is_pro"`
 	HasPaddleSubscription bool         `db:"has_paddle_subscription"`
 	ScheduledDeletionAt   dbx.NullTime `db:"scheduled_deletion_at"`
+	VoteBudget            int          `db:"vote_budget"`
+	MaxVotesPerPost       int          `db:"max_votes_per_post"`
 }
 
 func (t *Tenant) ToModel() *entity.Tenant {
@@ -63,6 +65,8 @@ func (t *Tenant) ToModel() *entity.Tenant {
 		PreventIndexing:     t.PreventIndexing,
 		IsModerationEnabled: isPro && t.IsModerationEnabled,
 		IsPro:               isPro,
+		VoteBudget:          t.VoteBudget,
+		MaxVotesPerPost:     t.MaxVotesPerPost,
 	}
 
 	if t.ScheduledDeletionAt.Valid {
@@ -31,11 +31,22 @@ type UserProvider struct {
 	UID  sql.NullString `db:"provider_uid"`
 }
 
+type UserProviderUID struct {
+	UserID int    `db:"user_id"`
+	UID    string `db:"provider_uid"`
+}
+
 type UserSetting struct {
 	Key   string `db:"key"`
 	Value string `db:"value"`
 }
 
+type UserAttribute struct {
+	UserID int    `db:"user_id"`
+	Key    string `db:"key"`
+	Value  string `db:"value"`
+}
+
 func (u *User) ToModel(ctx context.Context) *entity.User {
 	if u == nil {
 		return nil
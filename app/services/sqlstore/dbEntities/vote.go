@@ -16,12 +16,14 @@ type Vote struct {
 		AvatarType    int64  `db:"avatar_type"`
 		AvatarBlobKey string `db:"avatar_bkey"`
 	} `db:"user"`
+	Weight    int       `db:"weight"`
 	CreatedAt time.Time `db:"created_at"`
 }
 
 func (v *Vote) ToModel(ctx context.Context) *entity.Vote {
 	vote := &entity.Vote{
 		CreatedAt: v.CreatedAt,
+		Weight:    v.Weight,
 		User: &entity.VoteUser{
 			ID:        v.User.ID,
 			Name:      v.User.Name,
@@ -0,0 +1,48 @@
+package dbEntities
+
+import (
+	"time"
+
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/pkg/dbx"
+)
+
+type WebhookDelivery struct {
+	ID            int          `db:"id"`
+	TenantID      int          `db:"tenant_id"`
+	WebhookID     int          `db:"webhook_id"`
+	Status        int          `db:"status"`
+	Attempts      int          `db:"attempts"`
+	Url           string       `db:"url"`
+	Content       string       `db:"content"`
+	StatusCode    int          `db:"status_code"`
+	LatencyMs     int          `db:"latency_ms"`
+	Response      string       `db:"response"`
+	Error         string       `db:"error"`
+	CreatedAt     time.Time    `db:"created_at"`
+	LastAttemptAt time.Time    `db:"last_attempt_at"`
+	NextAttemptAt dbx.NullTime `db:"next_attempt_at"`
+}
+
+func (d *WebhookDelivery) ToModel() *entity.WebhookDelivery {
+	delivery := &entity.WebhookDelivery{
+		ID:            d.ID,
+		TenantID:      d.TenantID,
+		WebhookID:     d.WebhookID,
+		Status:        enum.WebhookDeliveryStatus(d.Status),
+		Attempts:      d.Attempts,
+		Url:           d.Url,
+		Content:       d.Content,
+		StatusCode:    d.StatusCode,
+		LatencyMs:     d.LatencyMs,
+		Response:      d.Response,
+		Error:         d.Error,
+		CreatedAt:     d.CreatedAt,
+		LastAttemptAt: d.LastAttemptAt,
+	}
+	if d.NextAttemptAt.Valid {
+		delivery.NextAttemptAt = &d.NextAttemptAt.Time
+	}
+	return delivery
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/crypto"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/rand"
+	"github.com/getfider/fider/app/services/sqlstore/dbEntities"
+	"github.com/lib/pq"
+)
+
+func createAPIToken(ctx context.Context, c *cmd.CreateAPIToken) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		token := entity.APITokenPrefix + rand.String(48)
+		now := time.Now()
+
+		// Only the hash is stored, the token is shown once to its owner
+		var id int
+		if err := trx.Scalar(&id, `
+			INSERT INTO api_tokens (tenant_id, user_id, name, token_hash, scopes, expires_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id
+		`, tenant.ID, user.ID, c.Name, crypto.SHA512(token), pq.Array(c.Scopes), c.ExpiresAt, now); err != nil {
+			return errors.Wrap(err, "failed to create API token")
+		}
+
+		c.Token = token
+		c.Result = &entity.APIToken{
+			ID:        id,
+			Name:      c.Name,
+			Scopes:    c.Scopes,
+			ExpiresAt: c.ExpiresAt,
+			CreatedAt: now,
+		}
+		return nil
+	})
+}
+
+func revokeAPIToken(ctx context.Context, c *cmd.RevokeAPIToken) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		if _, err := trx.Execute(
+			"DELETE FROM api_tokens WHERE id = $1 AND user_id = $2 AND tenant_id = $3",
+			c.TokenID, user.ID, tenant.ID,
+		); err != nil {
+			return errors.Wrap(err, "failed to revoke API token '%d'", c.TokenID)
+		}
+		return nil
+	})
+}
+
+func markAPITokenAsUsed(ctx context.Context, c *cmd.MarkAPITokenAsUsed) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		now := time.Now()
+
+		// Tokens used by automations can be used many times a second, there's no need to update every time
+		if _, err := trx.Execute(`
+			UPDATE api_tokens SET last_used_at = $3
+			WHERE id = $1 AND tenant_id = $2 AND (last_used_at IS NULL OR last_used_at < $4)
+		`, c.TokenID, tenant.ID, now, now.Add(-1*time.Minute)); err != nil {
+			return errors.Wrap(err, "failed to mark API token '%d' as used", c.TokenID)
+		}
+		return nil
+	})
+}
+
+func getUserAPITokens(ctx context.Context, q *query.GetUserAPITokens) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		var tokens []*dbEntities.APIToken
+		if err := trx.Select(&tokens, `
+			SELECT id, user_id, name, scopes, expires_at, last_used_at, created_at
+			FROM api_tokens
+			WHERE tenant_id = $1 AND user_id = $2
+			ORDER BY created_at DESC
+		`, tenant.ID, user.ID); err != nil {
+			return errors.Wrap(err, "failed to get API tokens")
+		}
+
+		q.Result = make([]*entity.APIToken, len(tokens))
+		for i, token := range tokens {
+			q.Result[i] = token.ToModel()
+		}
+		return nil
+	})
+}
+
+func getUserByAPIToken(ctx context.Context, q *query.GetUserByAPIToken) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		token := dbEntities.APIToken{}
+		if err := trx.Get(&token, `
+			SELECT id, user_id, name, scopes, expires_at, last_used_at, created_at
+			FROM api_tokens
+			WHERE tenant_id = $1 AND token_hash = $2 AND (expires_at IS NULL OR expires_at > $3)
+		`, tenant.ID, crypto.SHA512(q.Token), time.Now()); err != nil {
+			return errors.Wrap(err, "failed to get API token")
+		}
+
+		result, err := queryUser(ctx, trx, "id = $1 AND tenant_id = $2", token.UserID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get user of API token '%d'", token.ID)
+		}
+
+		q.Result = result
+		q.APIToken = token.ToModel()
+		return nil
+	})
+}
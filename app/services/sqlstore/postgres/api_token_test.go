@@ -0,0 +1,94 @@
+package postgres_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+)
+
+func TestAPIToken_CreateAndGetUser(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	expiresAt := time.Now().Add(24 * time.Hour)
+	createToken := &cmd.CreateAPIToken{
+		Name:      "Zapier",
+		Scopes:    []enum.APIScope{enum.APIScopeReadPosts, enum.APIScopeWriteComments},
+		ExpiresAt: &expiresAt,
+	}
+	err := bus.Dispatch(jonSnowCtx, createToken)
+	Expect(err).IsNil()
+	Expect(createToken.Token).ContainsSubstring("fdr_")
+	Expect(createToken.Result.ID).NotEquals(0)
+
+	getUser := &query.GetUserByAPIToken{Token: createToken.Token}
+	err = bus.Dispatch(demoTenantCtx, getUser)
+	Expect(err).IsNil()
+	Expect(getUser.Result.ID).Equals(jonSnow.ID)
+	Expect(getUser.APIToken.Name).Equals("Zapier")
+	Expect(getUser.APIToken.Scopes).Equals([]enum.APIScope{enum.APIScopeReadPosts, enum.APIScopeWriteComments})
+	Expect(getUser.APIToken.LastUsedAt).IsNil()
+
+	err = bus.Dispatch(demoTenantCtx, &cmd.MarkAPITokenAsUsed{TokenID: createToken.Result.ID})
+	Expect(err).IsNil()
+
+	getTokens := &query.GetUserAPITokens{}
+	err = bus.Dispatch(jonSnowCtx, getTokens)
+	Expect(err).IsNil()
+	Expect(getTokens.Result).HasLen(1)
+	Expect(getTokens.Result[0].LastUsedAt).IsNotNil()
+	Expect(*getTokens.Result[0].ExpiresAt).TemporarilySimilar(expiresAt, time.Second)
+
+	getUser = &query.GetUserByAPIToken{Token: createToken.Token}
+	err = bus.Dispatch(avengersTenantCtx, getUser)
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+}
+
+func TestAPIToken_Expired(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	expiresAt := time.Now().Add(-1 * time.Minute)
+	createToken := &cmd.CreateAPIToken{
+		Name:      "Old bot",
+		Scopes:    []enum.APIScope{enum.APIScopeAdmin},
+		ExpiresAt: &expiresAt,
+	}
+	err := bus.Dispatch(jonSnowCtx, createToken)
+	Expect(err).IsNil()
+
+	getUser := &query.GetUserByAPIToken{Token: createToken.Token}
+	err = bus.Dispatch(demoTenantCtx, getUser)
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+}
+
+func TestAPIToken_Revoke(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	createToken := &cmd.CreateAPIToken{Name: "Zapier", Scopes: []enum.APIScope{enum.APIScopeReadPosts}}
+	err := bus.Dispatch(jonSnowCtx, createToken)
+	Expect(err).IsNil()
+
+	// Users can only revoke their own tokens
+	err = bus.Dispatch(aryaStarkCtx, &cmd.RevokeAPIToken{TokenID: createToken.Result.ID})
+	Expect(err).IsNil()
+
+	getUser := &query.GetUserByAPIToken{Token: createToken.Token}
+	err = bus.Dispatch(demoTenantCtx, getUser)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.RevokeAPIToken{TokenID: createToken.Result.ID})
+	Expect(err).IsNil()
+
+	getUser = &query.GetUserByAPIToken{Token: createToken.Token}
+	err = bus.Dispatch(demoTenantCtx, getUser)
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+}
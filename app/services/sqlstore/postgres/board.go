@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/services/sqlstore/dbEntities"
+	"github.com/gosimple/slug"
+)
+
+const sqlSelectBoards = `
+	SELECT b.id, b.name, b.slug, b.description, b.description_template, b.is_public, b.has_roadmap,
+				 ARRAY_REMOVE(ARRAY_AGG(t.slug ORDER BY t.name), NULL) AS default_tags,
+				 (SELECT COUNT(*) FROM posts p WHERE p.board_id = b.id AND p.tenant_id = b.tenant_id) AS posts_count
+	FROM boards b
+	LEFT JOIN board_tags bt
+	ON bt.board_id = b.id
+	AND bt.tenant_id = b.tenant_id
+	LEFT JOIN tags t
+	ON t.id = bt.tag_id
+	AND t.tenant_id = bt.tenant_id
+	%s
+	WHERE b.tenant_id = $1 %s
+	GROUP BY b.id
+	ORDER BY b.name
+`
+
+// queryBoards returns the boards matching condition that are visible to user
+func queryBoards(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User, condition string, args ...any) ([]*entity.Board, error) {
+	tagCondition := `AND t.is_public = true`
+	if user != nil && user.IsCollaborator() {
+		tagCondition = ``
+	} else {
+		condition += ` AND b.is_public = true`
+	}
+
+	boards := []*dbEntities.Board{}
+	err := trx.Select(&boards, fmt.Sprintf(sqlSelectBoards, tagCondition, condition), append([]any{tenant.ID}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*entity.Board, len(boards))
+	for i, board := range boards {
+		result[i] = board.ToModel()
+	}
+	return result, nil
+}
+
+func queryBoardBySlug(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User, boardSlug string) (*entity.Board, error) {
+	boards, err := queryBoards(trx, tenant, user, "AND b.slug = $2", boardSlug)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get board with slug '%s'", boardSlug)
+	}
+	if len(boards) == 0 {
+		return nil, app.ErrNotFound
+	}
+	return boards[0], nil
+}
+
+func getBoardBySlug(ctx context.Context, q *query.GetBoardBySlug) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		board, err := queryBoardBySlug(trx, tenant, user, q.Slug)
+		q.Result = board
+		return err
+	})
+}
+
+func getAllBoards(ctx context.Context, q *query.GetAllBoards) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		boards, err := queryBoards(trx, tenant, user, "")
+		if err != nil {
+			return errors.Wrap(err, "failed to get all boards")
+		}
+
+		q.Result = boards
+		return nil
+	})
+}
+
+func addNewBoard(ctx context.Context, c *cmd.AddNewBoard) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		var id int
+		newSlug := slug.Make(c.Name)
+		err := trx.Get(&id, `
+			INSERT INTO boards (tenant_id, name, slug, description, description_template, is_public, has_roadmap, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING id
+		`, tenant.ID, c.Name, newSlug, c.Description, c.DescriptionTemplate, c.IsPublic, c.HasRoadmap, time.Now())
+		if err != nil {
+			return errors.Wrap(err, "failed to add new board")
+		}
+
+		if err := setBoardDefaultTags(trx, tenant, id, c.DefaultTags); err != nil {
+			return err
+		}
+
+		board, err := queryBoardBySlug(trx, tenant, user, newSlug)
+		c.Result = board
+		return err
+	})
+}
+
+func updateBoard(ctx context.Context, c *cmd.UpdateBoard) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		newSlug := slug.Make(c.Name)
+		_, err := trx.Execute(`
+			UPDATE boards SET name = $1, slug = $2, description = $3, description_template = $4, is_public = $5, has_roadmap = $6
+			WHERE id = $7 AND tenant_id = $8
+		`, c.Name, newSlug, c.Description, c.DescriptionTemplate, c.IsPublic, c.HasRoadmap, c.BoardID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to update board")
+		}
+
+		if err := setBoardDefaultTags(trx, tenant, c.BoardID, c.DefaultTags); err != nil {
+			return err
+		}
+
+		board, err := queryBoardBySlug(trx, tenant, user, newSlug)
+		c.Result = board
+		return err
+	})
+}
+
+func setBoardDefaultTags(trx *dbx.Trx, tenant *entity.Tenant, boardID int, tags []*entity.Tag) error {
+	_, err := trx.Execute("DELETE FROM board_tags WHERE board_id = $1 AND tenant_id = $2", boardID, tenant.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to remove default tags of board with id '%d'", boardID)
+	}
+
+	for _, tag := range tags {
+		_, err := trx.Execute("INSERT INTO board_tags (tenant_id, board_id, tag_id) VALUES ($1, $2, $3)", tenant.ID, boardID, tag.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to add default tag '%s' to board with id '%d'", tag.Slug, boardID)
+		}
+	}
+	return nil
+}
+
+func deleteBoard(ctx context.Context, c *cmd.DeleteBoard) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		_, err := trx.Execute("UPDATE posts SET board_id = NULL WHERE board_id = $1 AND tenant_id = $2", c.Board.ID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to remove posts from board with id '%d'", c.Board.ID)
+		}
+
+		_, err = trx.Execute("DELETE FROM board_tags WHERE board_id = $1 AND tenant_id = $2", c.Board.ID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to remove default tags of board with id '%d'", c.Board.ID)
+		}
+
+		_, err = trx.Execute("DELETE FROM boards WHERE id = $1 AND tenant_id = $2", c.Board.ID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to delete board with id '%d'", c.Board.ID)
+		}
+		return nil
+	})
+}
+
+func setPostBoard(ctx context.Context, c *cmd.SetPostBoard) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		boardID := 0
+		if c.Board != nil {
+			boardID = c.Board.ID
+		}
+
+		_, err := trx.Execute("UPDATE posts SET board_id = NULLIF($1, 0) WHERE id = $2 AND tenant_id = $3", boardID, c.Post.ID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to set board of post with id '%d'", c.Post.ID)
+		}
+		return nil
+	})
+}
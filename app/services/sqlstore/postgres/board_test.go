@@ -0,0 +1,136 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+)
+
+func TestBoardStorage_AddUpdateAndGet(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	addNewTag := &cmd.AddNewTag{Name: "iOS", Color: "FF0000", IsPublic: true}
+	err := bus.Dispatch(jonSnowCtx, addNewTag)
+	Expect(err).IsNil()
+
+	addNewBoard := &cmd.AddNewBoard{Name: "Mobile Apps", Description: "Our apps", DescriptionTemplate: "Device:", IsPublic: true, HasRoadmap: true, DefaultTags: []*entity.Tag{addNewTag.Result}}
+	err = bus.Dispatch(jonSnowCtx, addNewBoard)
+	Expect(err).IsNil()
+	Expect(addNewBoard.Result.ID).NotEquals(0)
+	Expect(addNewBoard.Result.Slug).Equals("mobile-apps")
+	Expect(addNewBoard.Result.DescriptionTemplate).Equals("Device:")
+	Expect(addNewBoard.Result.HasRoadmap).IsTrue()
+	Expect(addNewBoard.Result.DefaultTags).Equals([]string{"ios"})
+
+	updateBoard := &cmd.UpdateBoard{BoardID: addNewBoard.Result.ID, Name: "Mobile", IsPublic: true}
+	err = bus.Dispatch(jonSnowCtx, updateBoard)
+	Expect(err).IsNil()
+	Expect(updateBoard.Result.Slug).Equals("mobile")
+	Expect(updateBoard.Result.HasRoadmap).IsFalse()
+	Expect(updateBoard.Result.DefaultTags).HasLen(0)
+
+	getBoard := &query.GetBoardBySlug{Slug: "mobile"}
+	err = bus.Dispatch(demoTenantCtx, getBoard)
+	Expect(err).IsNil()
+	Expect(getBoard.Result.ID).Equals(addNewBoard.Result.ID)
+
+	getBoard = &query.GetBoardBySlug{Slug: "mobile-apps"}
+	err = bus.Dispatch(demoTenantCtx, getBoard)
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+}
+
+func TestBoardStorage_PostsInBoard(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	addNewTag := &cmd.AddNewTag{Name: "Payments", Color: "00FF00", IsPublic: true}
+	err := bus.Dispatch(jonSnowCtx, addNewTag)
+	Expect(err).IsNil()
+
+	billing := &cmd.AddNewBoard{Name: "Billing", IsPublic: true, DefaultTags: []*entity.Tag{addNewTag.Result}}
+	err = bus.Dispatch(jonSnowCtx, billing)
+	Expect(err).IsNil()
+
+	newPost := &cmd.AddNewPost{Title: "Pay with PayPal", Description: "Please", Board: billing.Result}
+	err = bus.Dispatch(aryaStarkCtx, newPost)
+	Expect(err).IsNil()
+
+	otherPost := &cmd.AddNewPost{Title: "Dark mode", Description: "Please"}
+	err = bus.Dispatch(aryaStarkCtx, otherPost)
+	Expect(err).IsNil()
+
+	getPost := &query.GetPostByID{PostID: newPost.Result.ID}
+	err = bus.Dispatch(aryaStarkCtx, getPost)
+	Expect(err).IsNil()
+	Expect(getPost.Result.Board.Slug).Equals("billing")
+	Expect(getPost.Result.Tags).Equals([]string{"payments"})
+
+	searchPosts := &query.SearchPosts{Board: "billing"}
+	err = bus.Dispatch(aryaStarkCtx, searchPosts)
+	Expect(err).IsNil()
+	Expect(searchPosts.Result).HasLen(1)
+	Expect(searchPosts.Result[0].ID).Equals(newPost.Result.ID)
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.SetPostBoard{Post: otherPost.Result, Board: billing.Result})
+	Expect(err).IsNil()
+
+	searchPosts = &query.SearchPosts{Board: "billing"}
+	err = bus.Dispatch(aryaStarkCtx, searchPosts)
+	Expect(err).IsNil()
+	Expect(searchPosts.Result).HasLen(2)
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.DeleteBoard{Board: billing.Result})
+	Expect(err).IsNil()
+
+	getPost = &query.GetPostByID{PostID: newPost.Result.ID}
+	err = bus.Dispatch(aryaStarkCtx, getPost)
+	Expect(err).IsNil()
+	Expect(getPost.Result.Board).IsNil()
+}
+
+func TestBoardStorage_PrivateBoard(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	internal := &cmd.AddNewBoard{Name: "Internal", IsPublic: false}
+	err := bus.Dispatch(jonSnowCtx, internal)
+	Expect(err).IsNil()
+
+	newPost := &cmd.AddNewPost{Title: "Faster deploys", Description: "Please", Board: internal.Result}
+	err = bus.Dispatch(jonSnowCtx, newPost)
+	Expect(err).IsNil()
+
+	allBoards := &query.GetAllBoards{}
+	err = bus.Dispatch(aryaStarkCtx, allBoards)
+	Expect(err).IsNil()
+	Expect(allBoards.Result).HasLen(0)
+
+	err = bus.Dispatch(aryaStarkCtx, &query.GetBoardBySlug{Slug: "internal"})
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+
+	searchPosts := &query.SearchPosts{}
+	err = bus.Dispatch(aryaStarkCtx, searchPosts)
+	Expect(err).IsNil()
+	Expect(searchPosts.Result).HasLen(0)
+
+	err = bus.Dispatch(aryaStarkCtx, &query.GetPostByID{PostID: newPost.Result.ID})
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+
+	allBoards = &query.GetAllBoards{}
+	err = bus.Dispatch(jonSnowCtx, allBoards)
+	Expect(err).IsNil()
+	Expect(allBoards.Result).HasLen(1)
+	Expect(allBoards.Result[0].PostsCount).Equals(1)
+
+	searchPosts = &query.SearchPosts{}
+	err = bus.Dispatch(jonSnowCtx, searchPosts)
+	Expect(err).IsNil()
+	Expect(searchPosts.Result).HasLen(1)
+}
@@ -3,14 +3,17 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/getfider/fider/app/models/cmd"
 	"github.com/getfider/fider/app/models/entity"
 	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/cursor"
 	"github.com/getfider/fider/app/pkg/dbx"
 	"github.com/getfider/fider/app/pkg/errors"
 	"github.com/getfider/fider/app/services/sqlstore/dbEntities"
+	"github.com/lib/pq"
 )
 
 func addNewComment(ctx context.Context, c *cmd.AddNewComment) error {
@@ -18,10 +21,10 @@ func addNewComment(ctx context.Context, c *cmd.AddNewComment) error {
 		isApproved := !tenant.IsModerationEnabled || !user.RequiresModeration()
 		var id int
 		if err := trx.Get(&id, `
-			INSERT INTO comments (tenant_id, post_id, content, user_id, created_at, is_approved) 
-			VALUES ($1, $2, $3, $4, $5, $6) 
+			INSERT INTO comments (tenant_id, post_id, content, user_id, created_at, is_approved, is_internal, parent_id) 
+			VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, 0)) 
 			RETURNING id
-		`, tenant.ID, c.Post.ID, c.Content, user.ID, time.Now(), isApproved); err != nil {
+		`, tenant.ID, c.Post.ID, c.Content, user.ID, time.Now(), isApproved, c.IsInternal, c.ParentID); err != nil {
 			return errors.Wrap(err, "failed add new comment")
 		}
 
@@ -69,7 +72,19 @@ func toggleCommentReaction(ctx context.Context, c *cmd.ToggleCommentReaction) er
 
 func updateComment(ctx context.Context, c *cmd.UpdateComment) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
-		_, err := trx.Execute(`
+		var content string
+		err := trx.Scalar(&content, "SELECT content FROM comments WHERE id = $1 AND tenant_id = $2", c.CommentID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get content of comment '%d'", c.CommentID)
+		}
+
+		if content != c.Content {
+			if err := addCommentRevision(trx, tenant, user, c.CommentID, c.Content); err != nil {
+				return err
+			}
+		}
+
+		_, err = trx.Execute(`
 			UPDATE comments SET content = $1, edited_at = $2, edited_by_id = $3 
 			WHERE id = $4 AND tenant_id = $5`, c.Content, time.Now(), user.ID, c.CommentID, tenant.ID)
 		if err != nil {
@@ -96,10 +111,13 @@ func getCommentByID(ctx context.Context, q *query.GetCommentByID) error {
 		q.Result = nil
 
 		query := fmt.Sprintf(`SELECT c.id, 
+							c.post_id,
 							c.content, 
 							c.created_at, 
 							c.edited_at, 
 							c.is_approved,
+							c.is_internal,
+							c.parent_id,
 							u.id AS user_id, 
 							u.name AS user_name,
 							u.email AS user_email,
@@ -140,17 +158,98 @@ func getCommentByID(ctx context.Context, q *query.GetCommentByID) error {
 
 func buildApprovalFilter(user *entity.User) string {
 	if user != nil && user.IsCollaborator() {
-		// Admins and collaborators can see all comments
+		// Admins and collaborators can see all comments, including internal notes
 		return ""
 	} else if user != nil {
 		// Regular users can see approved comments + their own unapproved comments
-		return fmt.Sprintf(" AND (c.is_approved = true OR c.user_id = %d)", user.ID)
+		return fmt.Sprintf(" AND c.is_internal = false AND (c.is_approved = true OR c.user_id = %d)", user.ID)
 	} else {
 		// Anonymous users can only see approved comments
-		return " AND c.is_approved = true"
+		return " AND c.is_internal = false AND c.is_approved = true"
 	}
 }
 
+// commentsOfPostQuery selects the comments of post $1 on tenant $2, with the reactions of user $3.
+// Conditions, order and limit are appended with fmt.Sprintf
+const commentsOfPostQuery = `
+	WITH agg_attachments AS ( 
+			SELECT 
+					c.id as comment_id, 
+					ARRAY_REMOVE(ARRAY_AGG(at.attachment_bkey), NULL) as attachment_bkeys
+			FROM attachments at
+			INNER JOIN comments c
+			ON at.tenant_id = c.tenant_id
+			AND at.post_id = c.post_id
+			AND at.comment_id = c.id
+			WHERE at.post_id = $1
+			AND at.tenant_id = $2
+			AND at.comment_id IS NOT NULL
+			GROUP BY c.id 
+	),
+	agg_reactions AS (
+		SELECT 
+			comment_id,
+			json_agg(json_build_object(
+				'emoji', emoji,
+				'count', count,
+				'includesMe', CASE WHEN $3 = ANY(user_ids) THEN true ELSE false END
+			) ORDER BY count DESC) as reaction_counts
+		FROM (
+			SELECT 
+				comment_id, 
+				emoji, 
+				COUNT(*) as count,
+				array_agg(user_id) as user_ids
+			FROM reactions
+			WHERE comment_id IN (SELECT id FROM comments WHERE post_id = $1)
+			GROUP BY comment_id, emoji
+		) r
+		GROUP BY comment_id
+	)
+	SELECT c.id, 
+			c.post_id,
+			c.content, 
+			c.created_at, 
+			c.edited_at, 
+			c.is_approved,
+			c.is_internal,
+			c.parent_id,
+			u.id AS user_id, 
+			u.name AS user_name,
+			u.email AS user_email,
+			u.role AS user_role, 
+			u.status AS user_status, 
+			u.avatar_type AS user_avatar_type, 
+			u.avatar_bkey AS user_avatar_bkey, 
+			e.id AS edited_by_id, 
+			e.name AS edited_by_name,
+			e.email AS edited_by_email,
+			e.role AS edited_by_role,
+			e.status AS edited_by_status,
+			e.avatar_type AS edited_by_avatar_type, 
+			e.avatar_bkey AS edited_by_avatar_bkey,
+			at.attachment_bkeys,
+			ar.reaction_counts
+	FROM comments c
+	INNER JOIN posts p
+	ON p.id = c.post_id
+	AND p.tenant_id = c.tenant_id
+	INNER JOIN users u
+	ON u.id = c.user_id
+	AND u.tenant_id = c.tenant_id
+	LEFT JOIN users e
+	ON e.id = c.edited_by_id
+	AND e.tenant_id = c.tenant_id
+	LEFT JOIN agg_attachments at
+	ON at.comment_id = c.id
+	LEFT JOIN agg_reactions ar
+	ON ar.comment_id = c.id
+	WHERE p.id = $1
+	AND p.tenant_id = $2
+	AND c.deleted_at IS NULL%s
+	ORDER BY %s
+	LIMIT %s`
+
 func getCommentsByPost(ctx context.Context, q *query.GetCommentsByPost) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
 		q.Result = make([]*entity.Comment, 0)
@@ -164,90 +263,115 @@ func getCommentsByPost(ctx context.Context, q *query.GetCommentsByPost) error {
 		// Build approval filter based on user permissions
 		approvalFilter := buildApprovalFilter(user)
 
-		query := fmt.Sprintf(`
-			WITH agg_attachments AS ( 
-					SELECT 
-							c.id as comment_id, 
-							ARRAY_REMOVE(ARRAY_AGG(at.attachment_bkey), NULL) as attachment_bkeys
-					FROM attachments at
-					INNER JOIN comments c
-					ON at.tenant_id = c.tenant_id
-					AND at.post_id = c.post_id
-					AND at.comment_id = c.id
-					WHERE at.post_id = $1
-					AND at.tenant_id = $2
-					AND at.comment_id IS NOT NULL
-					GROUP BY c.id 
-			),
-			agg_reactions AS (
-				SELECT 
-					comment_id,
-					json_agg(json_build_object(
-						'emoji', emoji,
-						'count', count,
-						'includesMe', CASE WHEN $3 = ANY(user_ids) THEN true ELSE false END
-					) ORDER BY count DESC) as reaction_counts
-				FROM (
-					SELECT 
-						comment_id, 
-						emoji, 
-						COUNT(*) as count,
-						array_agg(user_id) as user_ids
-					FROM reactions
-					WHERE comment_id IN (SELECT id FROM comments WHERE post_id = $1)
-					GROUP BY comment_id, emoji
-				) r
-				GROUP BY comment_id
-			)
-			SELECT c.id, 
-					c.content, 
-					c.created_at, 
-					c.edited_at, 
-					c.is_approved,
-					u.id AS user_id, 
-					u.name AS user_name,
-					u.email AS user_email,
-					u.role AS user_role, 
-					u.status AS user_status, 
-					u.avatar_type AS user_avatar_type, 
-					u.avatar_bkey AS user_avatar_bkey, 
-					e.id AS edited_by_id, 
-					e.name AS edited_by_name,
-					e.email AS edited_by_email,
-					e.role AS edited_by_role,
-					e.status AS edited_by_status,
-					e.avatar_type AS edited_by_avatar_type, 
-					e.avatar_bkey AS edited_by_avatar_bkey,
-					at.attachment_bkeys,
-					ar.reaction_counts
-			FROM comments c
-			INNER JOIN posts p
-			ON p.id = c.post_id
-			AND p.tenant_id = c.tenant_id
-			INNER JOIN users u
-			ON u.id = c.user_id
-			AND u.tenant_id = c.tenant_id
-			LEFT JOIN users e
-			ON e.id = c.edited_by_id
-			AND e.tenant_id = c.tenant_id
-			LEFT JOIN agg_attachments at
-			ON at.comment_id = c.id
-			LEFT JOIN agg_reactions ar
-			ON ar.comment_id = c.id
-			WHERE p.id = $1
-			AND p.tenant_id = $2
-			AND c.deleted_at IS NULL%s
-			ORDER BY c.created_at DESC`, approvalFilter)
-
-		err := trx.Select(&comments, query, q.Post.ID, tenant.ID, userId)
+		// Threads start at top-level comments, and at replies whose parent can't be seen so that they are not lost
+		threadCondition := ""
+		if q.Threaded {
+			threadCondition = fmt.Sprintf(`
+				AND (c.parent_id IS NULL OR c.parent_id NOT IN (
+					SELECT c.id FROM comments c
+					WHERE c.post_id = $1
+					AND c.tenant_id = $2
+					AND c.deleted_at IS NULL%s
+				))`, approvalFilter)
+		}
+
+		if q.CountTotal {
+			err := trx.Scalar(&q.TotalCount, fmt.Sprintf(`
+				SELECT COUNT(*) FROM comments c
+				WHERE c.post_id = $1
+				AND c.tenant_id = $2
+				AND c.deleted_at IS NULL%s%s`, approvalFilter, threadCondition), q.Post.ID, tenant.ID)
+			if err != nil {
+				return errors.Wrap(err, "failed to count comments of post with id '%d'", q.Post.ID)
+			}
+		}
+
+		// Comments are sorted by creation date and then by id, so that cursors point to a stable position
+		params := []any{q.Post.ID, tenant.ID, userId}
+		cursorCondition := ""
+		if q.Cursor != "" {
+			key, id, err := cursor.Decode(q.Cursor)
+			if err != nil {
+				return err
+			}
+			cursorCondition = " AND (c.created_at, c.id) < ($4::timestamptz, $5)"
+			params = append(params, key, id)
+		}
+
+		// One more comment than requested is fetched to know if there's a next page
+		limit := "ALL"
+		if q.Limit > 0 {
+			limit = strconv.Itoa(q.Limit + 1)
+		}
+
+		query := fmt.Sprintf(commentsOfPostQuery, approvalFilter+threadCondition+cursorCondition, "c.created_at DESC, c.id DESC", limit)
+		err := trx.Select(&comments, query, params...)
 		if err != nil {
 			return errors.Wrap(err, "failed get comments of post with id '%d'", q.Post.ID)
 		}
 
+		if q.Limit > 0 && len(comments) > q.Limit {
+			comments = comments[:q.Limit]
+			last := comments[len(comments)-1]
+			q.NextCursor = cursor.Encode(last.CreatedAt.Format(time.RFC3339Nano), last.ID)
+		}
+
 		q.Result = make([]*entity.Comment, len(comments))
 		for i, comment := range comments {
 			q.Result[i] = comment.ToModel(ctx)
 		}
+
+		if q.Threaded && len(q.Result) > 0 {
+			return nestReplies(ctx, trx, tenant, q, userId, approvalFilter)
+		}
 		return nil
 	})
 }
+
+// nestReplies adds the replies of each comment of q.Result, and of their replies, oldest first.
+// Replies are only reachable through comments that current user can see
+func nestReplies(ctx context.Context, trx *dbx.Trx, tenant *entity.Tenant, q *query.GetCommentsByPost, userId int, approvalFilter string) error {
+	ids := make([]int, len(q.Result))
+	threads := make(map[int]*entity.Comment)
+	for i, comment := range q.Result {
+		ids[i] = comment.ID
+		threads[comment.ID] = comment
+	}
+
+	repliesCondition := fmt.Sprintf(`
+		AND c.id IN (
+			WITH RECURSIVE replies AS (
+				SELECT c.id FROM comments c
+				WHERE c.parent_id = ANY($4)
+				AND c.tenant_id = $2
+				AND c.deleted_at IS NULL%s
+				UNION ALL
+				SELECT c.id FROM comments c
+				INNER JOIN replies r
+				ON c.parent_id = r.id
+				WHERE c.tenant_id = $2
+				AND c.deleted_at IS NULL%s
+			)
+			SELECT id FROM replies
+		)`, approvalFilter, approvalFilter)
+
+	replies := []*dbEntities.Comment{}
+	query := fmt.Sprintf(commentsOfPostQuery, approvalFilter+repliesCondition, "c.created_at, c.id", "ALL")
+	err := trx.Select(&replies, query, q.Post.ID, tenant.ID, userId, pq.Array(ids))
+	if err != nil {
+		return errors.Wrap(err, "failed get replies of post with id '%d'", q.Post.ID)
+	}
+
+	models := make([]*entity.Comment, len(replies))
+	for i, reply := range replies {
+		models[i] = reply.ToModel(ctx)
+		threads[models[i].ID] = models[i]
+	}
+
+	for _, reply := range models {
+		if parent, ok := threads[*reply.ParentID]; ok {
+			parent.Replies = append(parent.Replies, reply)
+		}
+	}
+	return nil
+}
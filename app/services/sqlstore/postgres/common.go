@@ -3,13 +3,19 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"html"
 	"net/url"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/getfider/fider/app/models/entity"
 	"github.com/getfider/fider/app/models/enum"
 	"github.com/getfider/fider/app/models/query"
 	"github.com/getfider/fider/app/pkg/web"
+	"github.com/lib/pq"
 )
 
 // allowedTextRunes matches any character that is NOT a Unicode letter (\p{L}), number (\p{N}), space, or pipe.
@@ -36,19 +42,39 @@ func MapLocaleToTSConfig(locale string) string {
 	return enum.MapLocaleToTSConfig(locale)
 }
 
-func getViewData(query query.SearchPosts, tagsPlaceholder int) (string, []enum.PostStatus, string) {
+const (
+	snippetStartSel = "\x02"
+	snippetStopSel  = "\x03"
+)
+
+// searchSnippetOptions are the ts_headline options used for search snippets,
+// the markers are control characters so that they can't be confused with the text itself
+var searchSnippetOptions = fmt.Sprintf("StartSel=%s, StopSel=%s, MaxWords=30, MinWords=10, MaxFragments=2, FragmentDelimiter=\" … \"", snippetStartSel, snippetStopSel)
+
+var snippetReplacer = strings.NewReplacer(snippetStartSel, "<mark>", snippetStopSel, "</mark>")
+
+// formatSearchSnippet escapes a snippet returned by ts_headline and highlights its matching terms with <mark>
+func formatSearchSnippet(snippet string) string {
+	return snippetReplacer.Replace(html.EscapeString(strings.TrimSpace(snippet)))
+}
+
+// getViewData returns the condition, statuses and sort of the view. Trending scores decay over time, so they are computed at given time
+func getViewData(query query.SearchPosts, tagsPlaceholder int, customStatuses []*entity.CustomPostStatus, now time.Time) (string, []enum.PostStatus, string) {
 	var (
 		condition string
 		sort      string
 	)
 	statusFilters := query.Statuses
 	if len(statusFilters) == 0 {
-		// Use a sensible default list of status filters
+		// Use a sensible default list of status filters, custom statuses are listed while they accept votes
 		statusFilters = []enum.PostStatus{
 			enum.PostOpen,
 			enum.PostStarted,
 			enum.PostPlanned,
 		}
+		statusFilters = appendCustomStatuses(statusFilters, customStatuses, func(status *entity.CustomPostStatus) bool {
+			return status.AcceptsVotes
+		})
 	}
 
 	if query.MyVotesOnly {
@@ -68,19 +94,19 @@ func getViewData(query query.SearchPosts, tagsPlaceholder int) (string, []enum.P
 		sort = "id"
 	case "planned":
 		// Deprecated: Use status filters instead
-		sort = "response_date"
-		statusFilters = []enum.PostStatus{enum.PostPlanned}
+		sort = "EXTRACT(EPOCH FROM response_date)"
+		statusFilters = appendCustomStatusesInColumn([]enum.PostStatus{enum.PostPlanned}, customStatuses, entity.RoadmapColumnPlanned)
 	case "started":
 		// Deprecated: Use status filters instead
-		sort = "response_date"
-		statusFilters = []enum.PostStatus{enum.PostStarted}
+		sort = "EXTRACT(EPOCH FROM response_date)"
+		statusFilters = appendCustomStatusesInColumn([]enum.PostStatus{enum.PostStarted}, customStatuses, entity.RoadmapColumnStarted)
 	case "completed":
 		// Deprecated: Use status filters instead
-		sort = "response_date"
-		statusFilters = []enum.PostStatus{enum.PostCompleted}
+		sort = "EXTRACT(EPOCH FROM response_date)"
+		statusFilters = appendCustomStatusesInColumn([]enum.PostStatus{enum.PostCompleted}, customStatuses, entity.RoadmapColumnCompleted)
 	case "declined":
 		// Deprecated: Use status filters instead
-		sort = "response_date"
+		sort = "EXTRACT(EPOCH FROM response_date)"
 		statusFilters = []enum.PostStatus{enum.PostDeclined}
 	case "all":
 		sort = "id"
@@ -91,10 +117,13 @@ func getViewData(query query.SearchPosts, tagsPlaceholder int) (string, []enum.P
 			enum.PostCompleted,
 			enum.PostDeclined,
 		}
+		statusFilters = appendCustomStatuses(statusFilters, customStatuses, func(status *entity.CustomPostStatus) bool {
+			return true
+		})
 	case "trending":
 		fallthrough
 	default:
-		sort = "((COALESCE(recent_votes_count, 0)*5 + COALESCE(recent_comments_count, 0) *3)-1) / pow((EXTRACT(EPOCH FROM current_timestamp - created_at)/3600) + 2, 1.4)"
+		sort = fmt.Sprintf("((COALESCE(recent_votes_count, 0)*5 + COALESCE(recent_comments_count, 0) *3)-1) / pow((EXTRACT(EPOCH FROM '%s'::timestamptz - created_at)/3600) + 2, 1.4)", now.UTC().Format(time.RFC3339Nano))
 	}
 
 	if query.NoTagsOnly {
@@ -110,6 +139,68 @@ func getViewData(query query.SearchPosts, tagsPlaceholder int) (string, []enum.P
 	return condition, statusFilters, sort
 }
 
+// appendCustomStatuses appends the custom statuses matching filter to statuses
+func appendCustomStatuses(statuses []enum.PostStatus, customStatuses []*entity.CustomPostStatus, filter func(status *entity.CustomPostStatus) bool) []enum.PostStatus {
+	for _, status := range customStatuses {
+		if filter(status) {
+			statuses = append(statuses, status.Status)
+		}
+	}
+	return statuses
+}
+
+// appendCustomStatusesInColumn appends the custom statuses shown in given roadmap column to statuses
+func appendCustomStatusesInColumn(statuses []enum.PostStatus, customStatuses []*entity.CustomPostStatus, column string) []enum.PostStatus {
+	return appendCustomStatuses(statuses, customStatuses, func(status *entity.CustomPostStatus) bool {
+		return status.RoadmapColumn == column
+	})
+}
+
+// getAttributeData returns the condition and the voters' attribute total expression used to segment posts.
+// Attribute keys are validated before being embedded and values are quoted, as positional parameters are already taken by the view
+func getAttributeData(query query.SearchPosts) (string, string) {
+	condition := ""
+	keys := make([]string, 0, len(query.VoterAttributes))
+	for key := range query.VoterAttributes {
+		if entity.IsValidUserAttributeKey(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		condition += fmt.Sprintf(` AND EXISTS (
+			SELECT 1 FROM post_votes pv
+			INNER JOIN user_attributes ua
+			ON ua.user_id = pv.user_id
+			AND ua.tenant_id = pv.tenant_id
+			WHERE pv.post_id = q.id
+			AND ua.key = '%s'
+			AND ua.value = %s
+		)`, key, pq.QuoteLiteral(query.VoterAttributes[key]))
+	}
+
+	if !entity.IsValidUserAttributeKey(query.SortAttribute) {
+		return condition, ""
+	}
+
+	total := fmt.Sprintf(`(
+		SELECT COALESCE(SUM(CASE WHEN ua.value ~ '^-?[0-9]+(\.[0-9]+)?$' THEN ua.value::numeric ELSE 0 END), 0)
+		FROM post_votes pv
+		INNER JOIN user_attributes ua
+		ON ua.user_id = pv.user_id
+		AND ua.tenant_id = pv.tenant_id
+		WHERE pv.post_id = q.id
+		AND ua.key = '%s'
+	)`, query.SortAttribute)
+
+	if query.MinAttributeTotal > 0 {
+		condition += fmt.Sprintf(" AND %s >= %s", total, strconv.FormatFloat(query.MinAttributeTotal, 'f', -1, 64))
+	}
+
+	return condition, total
+}
+
 func buildAvatarURL(ctx context.Context, avatarType enum.AvatarType, id int, name, avatarBlobKey string) string {
 	if name == "" {
 		name = "-"
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/gosimple/slug"
+)
+
+func importPost(ctx context.Context, c *cmd.ImportPost) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		var number int
+		if err := trx.Scalar(&number, "SELECT COALESCE(MAX(number), 0) + 1 FROM posts WHERE tenant_id = $1", tenant.ID); err != nil {
+			return errors.Wrap(err, "failed to get next post number")
+		}
+
+		// Other tools allow many posts with the same title, while slugs are unique on Fider
+		postSlug := slug.Make(c.Title)
+		exists, err := trx.Exists("SELECT 1 FROM posts WHERE tenant_id = $1 AND slug = $2", tenant.ID, postSlug)
+		if err != nil {
+			return errors.Wrap(err, "failed to check if slug exists")
+		}
+		if exists {
+			postSlug = fmt.Sprintf("%s-%d", postSlug, number)
+		}
+
+		var response, respondedAt, responseUserID any
+		if c.Status != enum.PostOpen {
+			response, respondedAt, responseUserID = c.Response, c.RespondedAt, user.ID
+		}
+
+		var id int
+		err = trx.Get(&id, `
+			INSERT INTO posts (title, slug, number, description, tenant_id, user_id, created_at, status, is_approved, language, response, response_date, response_user_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, true, $9, $10, $11, $12)
+			RETURNING id
+		`, c.Title, postSlug, number, c.Description, tenant.ID, c.User.ID, c.CreatedAt, c.Status,
+			detectPostLanguage(c.Title, c.Description), response, respondedAt, responseUserID)
+		if err != nil {
+			return errors.Wrap(err, "failed to import post")
+		}
+
+		q := &query.GetPostByID{PostID: id}
+		if err := getPostByID(ctx, q); err != nil {
+			return err
+		}
+		c.Result = q.Result
+
+		if err := savePostSignature(trx, tenant, id, c.Title, c.Description); err != nil {
+			return err
+		}
+
+		return internalAddSubscriber(trx, q.Result, tenant, c.User, false)
+	})
+}
+
+func importVote(ctx context.Context, c *cmd.ImportVote) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		_, err := trx.Execute(`
+			INSERT INTO post_votes (tenant_id, user_id, post_id, created_at, weight)
+			VALUES ($1, $2, $3, $4, 1)
+			ON CONFLICT DO NOTHING
+		`, tenant.ID, c.User.ID, c.Post.ID, c.CreatedAt)
+		if err != nil {
+			return errors.Wrap(err, "failed to import vote")
+		}
+		return nil
+	})
+}
+
+func importComment(ctx context.Context, c *cmd.ImportComment) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		_, err := trx.Execute(`
+			INSERT INTO comments (tenant_id, post_id, content, user_id, created_at, is_approved, is_internal)
+			VALUES ($1, $2, $3, $4, $5, true, false)
+		`, tenant.ID, c.Post.ID, c.Content, c.User.ID, c.CreatedAt)
+		if err != nil {
+			return errors.Wrap(err, "failed to import comment")
+		}
+		return nil
+	})
+}
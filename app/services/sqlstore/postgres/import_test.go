@@ -0,0 +1,63 @@
+package postgres_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+)
+
+func TestImportStorage_PostVotesAndComments(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	createdAt := time.Date(2018, 3, 1, 10, 0, 0, 0, time.UTC)
+	importPost := &cmd.ImportPost{
+		Title:       "My new post",
+		Description: "with this description",
+		User:        aryaStark,
+		CreatedAt:   createdAt,
+		Status:      enum.PostCompleted,
+		Response:    "Shipped!",
+		RespondedAt: createdAt.Add(24 * time.Hour),
+	}
+	err := bus.Dispatch(jonSnowCtx, importPost)
+	Expect(err).IsNil()
+	Expect(importPost.Result.Slug).Equals("my-new-post")
+	Expect(importPost.Result.CreatedAt).TemporarilySimilar(createdAt, time.Second)
+	Expect(importPost.Result.Status).Equals(enum.PostCompleted)
+	Expect(importPost.Result.Response.Text).Equals("Shipped!")
+	Expect(importPost.Result.Response.User.ID).Equals(jonSnow.ID)
+
+	// Slugs are unique, so posts with the same title get the number as suffix
+	duplicate := &cmd.ImportPost{Title: "My new post", User: sansaStark, CreatedAt: createdAt, Status: enum.PostOpen}
+	err = bus.Dispatch(jonSnowCtx, duplicate)
+	Expect(err).IsNil()
+	Expect(duplicate.Result.Slug).Equals("my-new-post-2")
+	Expect(duplicate.Result.Response).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx,
+		&cmd.ImportVote{Post: importPost.Result, User: sansaStark, CreatedAt: createdAt.Add(time.Hour)},
+		&cmd.ImportVote{Post: importPost.Result, User: sansaStark, CreatedAt: createdAt.Add(time.Hour)},
+		&cmd.ImportComment{Post: importPost.Result, User: sansaStark, Content: "Yes please!", CreatedAt: createdAt.Add(2 * time.Hour)},
+	)
+	Expect(err).IsNil()
+
+	listVotes := &query.ListPostVotes{PostID: importPost.Result.ID, IncludeEmail: true}
+	err = bus.Dispatch(jonSnowCtx, listVotes)
+	Expect(err).IsNil()
+	Expect(listVotes.Result).HasLen(1)
+	Expect(listVotes.Result[0].User.Email).Equals(sansaStark.Email)
+	Expect(listVotes.Result[0].CreatedAt).TemporarilySimilar(createdAt.Add(time.Hour), time.Second)
+
+	comments := &query.GetCommentsByPost{Post: importPost.Result}
+	err = bus.Dispatch(jonSnowCtx, comments)
+	Expect(err).IsNil()
+	Expect(comments.Result).HasLen(1)
+	Expect(comments.Result[0].Content).Equals("Yes please!")
+	Expect(comments.Result[0].CreatedAt).TemporarilySimilar(createdAt.Add(2*time.Hour), time.Second)
+}
@@ -227,7 +227,7 @@ func getActiveSubscribers(ctx context.Context, q *query.GetActiveSubscribers) er
 				WHERE u.tenant_id = $4
 				AND u.status = $8
 				%s
-				AND ( sub.status = $2 OR (sub.status IS NULL AND NOT u.role = ANY($7)) )
+				AND ( sub.status = $2 OR (sub.status IS NULL AND (NOT u.role = ANY($7) OR u.id = ANY($9))) )
 				AND (
 					(set.value IS NULL AND u.role = ANY($5))
 					OR CAST(set.value AS integer) & $6 > 0
@@ -241,6 +241,7 @@ func getActiveSubscribers(ctx context.Context, q *query.GetActiveSubscribers) er
 				q.Channel,
 				pq.Array(q.Event.RequiresSubscriptionUserRoles),
 				enum.UserActive,
+				pq.Array(q.Participants),
 			)
 		}
 
@@ -284,3 +285,104 @@ func supressEmail(ctx context.Context, c *cmd.SupressEmail) error {
 		return nil
 	})
 }
+
+func getEmailDigestFrequencies(ctx context.Context, q *query.GetEmailDigestFrequencies) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		q.Result = make(map[int]enum.EmailDigestFrequency)
+		if len(q.UserIDs) == 0 {
+			return nil
+		}
+
+		var settings []*struct {
+			UserID int    `db:"user_id"`
+			Value  string `db:"value"`
+		}
+		err := trx.Select(&settings, `
+			SELECT user_id, value FROM user_settings
+			WHERE tenant_id = $1 AND key = $2 AND user_id = ANY($3)`,
+			tenant.ID, enum.EmailDigestSettingsKeyName, pq.Array(q.UserIDs),
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to get email digest frequencies")
+		}
+
+		for _, s := range settings {
+			q.Result[s.UserID] = enum.EmailDigestFrequency(s.Value)
+		}
+		return nil
+	})
+}
+
+func addEmailDigestItem(ctx context.Context, c *cmd.AddEmailDigestItem) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		_, err := trx.Execute(`
+			INSERT INTO email_digest_items (tenant_id, user_id, frequency, event, post_number, post_title, post_slug, author_name, content, status, base_url, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+			tenant.ID, c.UserID, c.Frequency, c.Event.UserSettingsKeyName, c.Post.Number, c.Post.Title, c.Post.Slug,
+			c.AuthorName, c.Content, c.Post.StatusName(), c.BaseURL, time.Now(),
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to add email digest item")
+		}
+		return nil
+	})
+}
+
+func listPendingEmailDigestItems(ctx context.Context, q *query.ListPendingEmailDigestItems) error {
+	return using(ctx, func(trx *dbx.Trx, _ *entity.Tenant, _ *entity.User) error {
+		q.Result = make([]*entity.EmailDigestItem, 0)
+
+		frequencies := make([]string, len(q.Frequencies))
+		for i, f := range q.Frequencies {
+			frequencies[i] = string(f)
+		}
+
+		userLimit := q.UserLimit
+		if userLimit <= 0 {
+			userLimit = 100
+		}
+
+		// All items of a user are on the same batch, as they are sent on a single digest
+		err := trx.Select(&q.Result, `
+			WITH batch AS (
+				SELECT DISTINCT tenant_id, user_id
+				FROM email_digest_items
+				WHERE frequency = ANY($1)
+				AND (tenant_id, user_id) > ($3, $4)
+				ORDER BY tenant_id, user_id
+				LIMIT $5
+			)
+			SELECT i.id, i.tenant_id, i.user_id, u.name AS user_name, u.email AS user_email,
+						 (u.status = $2 AND u.email_supressed_at IS NULL) AS can_receive_email,
+						 i.frequency, i.event, i.post_number, i.post_title, i.post_slug, i.author_name, i.content, i.status, i.base_url, i.created_at
+			FROM email_digest_items i
+			INNER JOIN batch b
+			ON b.tenant_id = i.tenant_id
+			AND b.user_id = i.user_id
+			INNER JOIN users u
+			ON u.id = i.user_id
+			AND u.tenant_id = i.tenant_id
+			WHERE i.frequency = ANY($1)
+			ORDER BY i.tenant_id, i.user_id, i.post_number, i.created_at`,
+			pq.Array(frequencies), enum.UserActive, q.AfterTenantID, q.AfterUserID, userLimit,
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to list pending email digest items")
+		}
+		return nil
+	})
+}
+
+func deleteEmailDigestItems(ctx context.Context, c *cmd.DeleteEmailDigestItems) error {
+	return using(ctx, func(trx *dbx.Trx, _ *entity.Tenant, _ *entity.User) error {
+		if len(c.IDs) == 0 {
+			return nil
+		}
+
+		_, err := trx.Execute("DELETE FROM email_digest_items WHERE id = ANY($1)", pq.Array(c.IDs))
+		if err != nil {
+			return errors.Wrap(err, "failed to delete email digest items")
+		}
+		return nil
+	})
+}
@@ -25,7 +25,7 @@ func getCustomOAuthConfigByProvider(ctx context.Context, q *query.GetCustomOAuth
 					 client_id, client_secret, authorize_url,
 					 profile_url, token_url, scope, json_user_id_path,
 					 json_user_name_path, json_user_email_path, json_user_roles_path,
-					 allowed_roles
+					 allowed_roles, is_oidc, issuer_url
 		FROM oauth_providers
 		WHERE tenant_id = $1 AND provider = $2
 		`, tenant.ID, q.Provider)
@@ -51,7 +51,7 @@ func listCustomOAuthConfig(ctx context.Context, q *query.ListCustomOAuthConfig)
 						 client_id, client_secret, authorize_url,
 						 profile_url, token_url, scope, json_user_id_path,
 						 json_user_name_path, json_user_email_path, json_user_roles_path,
-						 allowed_roles
+						 allowed_roles, is_oidc, issuer_url
 			FROM oauth_providers
 			WHERE tenant_id = $1
 			ORDER BY id`, tenant.ID)
@@ -81,15 +81,17 @@ func saveCustomOAuthConfig(ctx context.Context, c *cmd.SaveCustomOAuthConfig) er
 				tenant_id, provider, display_name, status, is_trusted,
 				client_id, client_secret, authorize_url,
 				profile_url, token_url, scope, json_user_id_path,
-				json_user_name_path, json_user_email_path, json_user_roles_path, allowed_roles, logo_bkey
-			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+				json_user_name_path, json_user_email_path, json_user_roles_path, allowed_roles, logo_bkey,
+				is_oidc, issuer_url
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
 			RETURNING id`
 
 			err = trx.Get(&c.ID, query, tenant.ID, c.Provider,
 				c.DisplayName, c.Status, c.IsTrusted, c.ClientID, c.ClientSecret,
 				c.AuthorizeURL, c.ProfileURL, c.TokenURL,
 				c.Scope, c.JSONUserIDPath, c.JSONUserNamePath,
-				c.JSONUserEmailPath, c.JSONUserRolesPath, c.AllowedRoles, c.Logo.BlobKey)
+				c.JSONUserEmailPath, c.JSONUserRolesPath, c.AllowedRoles, c.Logo.BlobKey,
+				c.IsOIDC, c.IssuerURL)
 		} else {
 			// Detect if role-related fields are being changed. If the new configuration
 			// is active (both allowed_roles and json_user_roles_path are non-empty) and
@@ -111,14 +113,16 @@ func saveCustomOAuthConfig(ctx context.Context, c *cmd.SaveCustomOAuthConfig) er
 				SET display_name = $3, status = $4, client_id = $5, client_secret = $6, 
 					authorize_url = $7, profile_url = $8, token_url = $9, scope = $10,
 					json_user_id_path = $11, json_user_name_path = $12, json_user_email_path = $13,
-					json_user_roles_path = $14, allowed_roles = $15, logo_bkey = $16, is_trusted = $17
+					json_user_roles_path = $14, allowed_roles = $15, logo_bkey = $16, is_trusted = $17,
+					is_oidc = $18, issuer_url = $19
 			WHERE tenant_id = $1 AND id = $2`
 
 			_, err = trx.Execute(query, tenant.ID, c.ID,
 				c.DisplayName, c.Status, c.ClientID, c.ClientSecret,
 				c.AuthorizeURL, c.ProfileURL, c.TokenURL,
 				c.Scope, c.JSONUserIDPath, c.JSONUserNamePath,
-				c.JSONUserEmailPath, c.JSONUserRolesPath, c.AllowedRoles, c.Logo.BlobKey, c.IsTrusted)
+				c.JSONUserEmailPath, c.JSONUserRolesPath, c.AllowedRoles, c.Logo.BlobKey, c.IsTrusted,
+				c.IsOIDC, c.IssuerURL)
 
 			if err == nil && (prevAllowedRoles != c.AllowedRoles || prevJSONUserRolesPath != c.JSONUserRolesPath) {
 				// Only rotate security stamps if the new configuration still enforces
@@ -5,6 +5,7 @@ import (
 
 	"github.com/getfider/fider/app/models/cmd"
 	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/query"
 	"github.com/getfider/fider/app/pkg/bus"
 	. "github.com/getfider/fider/app/pkg/assert"
 )
@@ -251,3 +252,30 @@ func TestOAuthConfig_SecurityStamp_InactiveToInactive(t *testing.T) {
 
 
 
+
+func TestOAuthConfig_OIDC(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	saveCmd := newOAuthConfig("groups", "")
+	saveCmd.IsOIDC = true
+	saveCmd.IssuerURL = "https://login.example.org"
+	err := bus.Dispatch(jonSnowCtx, saveCmd)
+	Expect(err).IsNil()
+
+	getConfig := &query.GetCustomOAuthConfigByProvider{Provider: "_TEST_ROLES"}
+	err = bus.Dispatch(jonSnowCtx, getConfig)
+	Expect(err).IsNil()
+	Expect(getConfig.Result.IsOIDC).IsTrue()
+	Expect(getConfig.Result.IssuerURL).Equals("https://login.example.org")
+
+	saveCmd.IsOIDC = false
+	saveCmd.IssuerURL = ""
+	err = bus.Dispatch(jonSnowCtx, saveCmd)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, getConfig)
+	Expect(err).IsNil()
+	Expect(getConfig.Result.IsOIDC).IsFalse()
+	Expect(getConfig.Result.IssuerURL).Equals("")
+}
@@ -15,15 +15,24 @@ import (
 	"github.com/gosimple/slug"
 	"github.com/lib/pq"
 
-	"github.com/getfider/fider/app/pkg/bus"
 	"github.com/getfider/fider/app/pkg/env"
 
 	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/pkg/cursor"
 	"github.com/getfider/fider/app/pkg/dbx"
 	"github.com/getfider/fider/app/pkg/errors"
 	"github.com/getfider/fider/app/services/sqlstore/dbEntities"
 )
 
+const (
+	// minTrigramQueryLength is the minimum number of characters for a query to also match similar titles
+	minTrigramQueryLength = 4
+	// trigramSimilarityThreshold is how similar a title must be to a query to be matched despite typos
+	trigramSimilarityThreshold = 0.4
+	// maxSimilarPosts is the maximum number of posts suggested as similar to a query
+	maxSimilarPosts = 5
+)
+
 var (
 	sqlSelectPostsWhere = `	WITH
 													agg_tags AS (
@@ -49,13 +58,14 @@ var (
 															AND posts.tenant_id = comments.tenant_id
 															WHERE posts.tenant_id = $1
 															AND comments.deleted_at IS NULL
+															AND comments.is_internal = false
 															GROUP BY post_id
 													),
 													agg_votes AS (
 															SELECT
 															post_id,
-																	COUNT(CASE WHEN post_votes.created_at > CURRENT_DATE - INTERVAL '30 days'  THEN 1 END) as recent,
-																	COUNT(*) as all
+																	SUM(CASE WHEN post_votes.created_at > CURRENT_DATE - INTERVAL '30 days'  THEN post_votes.weight ELSE 0 END) as recent,
+																	SUM(post_votes.weight) as all
 															FROM post_votes
 															INNER JOIN posts
 															ON posts.id = post_votes.post_id
@@ -96,8 +106,19 @@ var (
 																d.slug AS original_slug,
 																d.status AS original_status,
 																COALESCE(agg_t.tags, ARRAY[]::text[]) AS tags,
-																COALESCE(%s, false) AS has_voted,
-																p.is_approved
+																COALESCE(%[2]s, 0) > 0 AS has_voted,
+																COALESCE(%[2]s, 0) AS vote_weight,
+																p.is_approved,
+																ps.status AS custom_status_status,
+																ps.name AS custom_status_name,
+																ps.slug AS custom_status_slug,
+																ps.color AS custom_status_color,
+																COALESCE(ps.accepts_votes, false) AS custom_status_accepts_votes,
+																ps.roadmap_column AS custom_status_roadmap_column,
+																p.board_id,
+																b.name AS board_name,
+																b.slug AS board_slug,
+																COALESCE(b.is_public, true) AS board_is_public
 													FROM posts p
 													INNER JOIN users u
 													ON u.id = p.user_id
@@ -114,6 +135,12 @@ var (
 													ON agg_s.post_id = p.id
 													LEFT JOIN agg_tags agg_t
 													ON agg_t.post_id = p.id
+													LEFT JOIN post_statuses ps
+													ON ps.status = p.status
+													AND ps.tenant_id = $1
+													LEFT JOIN boards b
+													ON b.id = p.board_id
+													AND b.tenant_id = $1
 													WHERE p.status != ` + strconv.Itoa(int(enum.PostDeleted)) + ` AND %s`
 )
 
@@ -143,6 +170,15 @@ func setPostResponse(ctx context.Context, c *cmd.SetPostResponse) error {
 			return errors.New("Use MarkAsDuplicate to change an post status to Duplicate")
 		}
 
+		var customStatus *entity.CustomPostStatus
+		if c.Status.IsCustom() {
+			var err error
+			customStatus, err = queryCustomPostStatus(trx, tenant, c.Status)
+			if err != nil {
+				return err
+			}
+		}
+
 		respondedAt := time.Now()
 		if c.Post.Status == c.Status && c.Post.Response != nil {
 			respondedAt = c.Post.Response.RespondedAt
@@ -158,6 +194,7 @@ func setPostResponse(ctx context.Context, c *cmd.SetPostResponse) error {
 		}
 
 		c.Post.Status = c.Status
+		c.Post.CustomStatus = customStatus
 		c.Post.Response = &entity.PostResponse{
 			Text:        c.Text,
 			RespondedAt: respondedAt,
@@ -174,16 +211,18 @@ func markPostAsDuplicate(ctx context.Context, c *cmd.MarkPostAsDuplicate) error
 			respondedAt = c.Post.Response.RespondedAt
 		}
 
-		var users []*dbEntities.User
-		err := trx.Select(&users, "SELECT user_id AS id FROM post_votes WHERE post_id = $1 AND tenant_id = $2", c.Post.ID, tenant.ID)
-		if err != nil {
-			return errors.Wrap(err, "failed to get votes of post with id '%d'", c.Post.ID)
-		}
-
-		for _, u := range users {
-			err := bus.Dispatch(ctx, &cmd.AddVote{Post: c.Original, User: u.ToModel(ctx)})
+		// Votes already placed on the original are kept as they are. They are copied directly rather than through cmd.AddVote,
+		// as the vote budget would still count them on the duplicate and they were already paid for
+		var err error
+		if c.Original.CanBeVoted() {
+			_, err = trx.Execute(`
+				INSERT INTO post_votes (tenant_id, user_id, post_id, created_at, weight)
+				SELECT tenant_id, user_id, $3, created_at, weight
+				FROM post_votes
+				WHERE post_id = $1 AND tenant_id = $2
+				ON CONFLICT DO NOTHING`, c.Post.ID, tenant.ID, c.Original.ID)
 			if err != nil {
-				return err
+				return errors.Wrap(err, "failed to copy votes to post with id '%d'", c.Original.ID)
 			}
 		}
 
@@ -197,6 +236,7 @@ func markPostAsDuplicate(ctx context.Context, c *cmd.MarkPostAsDuplicate) error
 		}
 
 		c.Post.Status = enum.PostDuplicate
+		c.Post.CustomStatus = nil
 		c.Post.Response = &entity.PostResponse{
 			RespondedAt: respondedAt,
 			User:        user,
@@ -211,6 +251,109 @@ func markPostAsDuplicate(ctx context.Context, c *cmd.MarkPostAsDuplicate) error
 	})
 }
 
+func mergePosts(ctx context.Context, c *cmd.MergePosts) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		// Voters of the duplicate that haven't voted on the original yet, these are the ones to be notified
+		var voters []*dbEntities.User
+		err := trx.Select(&voters, `
+			SELECT u.id, u.name, u.email, u.tenant_id, u.role, u.status
+			FROM post_votes pv
+			INNER JOIN users u
+			ON u.id = pv.user_id
+			AND u.tenant_id = pv.tenant_id
+			WHERE pv.post_id = $1
+			AND pv.tenant_id = $2
+			AND NOT EXISTS (
+				SELECT 1 FROM post_votes o
+				WHERE o.post_id = $3
+				AND o.user_id = pv.user_id
+				AND o.tenant_id = pv.tenant_id
+			)
+			ORDER BY u.id`, c.Post.ID, tenant.ID, c.Original.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get votes of post with id '%d'", c.Post.ID)
+		}
+
+		_, err = trx.Execute(`
+			INSERT INTO post_votes (tenant_id, user_id, post_id, created_at, weight)
+			SELECT tenant_id, user_id, $3, created_at, weight
+			FROM post_votes
+			WHERE post_id = $1 AND tenant_id = $2
+			ON CONFLICT DO NOTHING`, c.Post.ID, tenant.ID, c.Original.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to move votes to post with id '%d'", c.Original.ID)
+		}
+
+		_, err = trx.Execute(`DELETE FROM post_votes WHERE post_id = $1 AND tenant_id = $2`, c.Post.ID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to remove votes from post with id '%d'", c.Post.ID)
+		}
+
+		// Existing subscriptions on the original take precedence, including those that were explicitly cancelled
+		_, err = trx.Execute(`
+			INSERT INTO post_subscribers (tenant_id, user_id, post_id, created_at, updated_at, status)
+			SELECT tenant_id, user_id, $3, created_at, $4, status
+			FROM post_subscribers
+			WHERE post_id = $1 AND tenant_id = $2
+			ON CONFLICT (user_id, post_id) DO NOTHING`, c.Post.ID, tenant.ID, c.Original.ID, time.Now())
+		if err != nil {
+			return errors.Wrap(err, "failed to move subscribers to post with id '%d'", c.Original.ID)
+		}
+
+		_, err = trx.Execute(`DELETE FROM post_subscribers WHERE post_id = $1 AND tenant_id = $2`, c.Post.ID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to remove subscribers from post with id '%d'", c.Post.ID)
+		}
+
+		_, err = trx.Execute(`
+			INSERT INTO post_tags (tag_id, post_id, created_at, created_by_id, tenant_id)
+			SELECT tag_id, $3, created_at, created_by_id, tenant_id
+			FROM post_tags
+			WHERE post_id = $1 AND tenant_id = $2
+			ON CONFLICT DO NOTHING`, c.Post.ID, tenant.ID, c.Original.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to move tags to post with id '%d'", c.Original.ID)
+		}
+
+		_, err = trx.Execute(`DELETE FROM post_tags WHERE post_id = $1 AND tenant_id = $2`, c.Post.ID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to remove tags from post with id '%d'", c.Post.ID)
+		}
+
+		if c.MergeComments {
+			_, err = trx.Execute(`UPDATE comments SET post_id = $3 WHERE post_id = $1 AND tenant_id = $2`, c.Post.ID, tenant.ID, c.Original.ID)
+			if err != nil {
+				return errors.Wrap(err, "failed to move comments to post with id '%d'", c.Original.ID)
+			}
+
+			_, err = trx.Execute(`
+				UPDATE attachments SET post_id = $3
+				WHERE post_id = $1 AND tenant_id = $2 AND comment_id IS NOT NULL`, c.Post.ID, tenant.ID, c.Original.ID)
+			if err != nil {
+				return errors.Wrap(err, "failed to move comment attachments to post with id '%d'", c.Original.ID)
+			}
+		}
+
+		// Votes have already been moved, so this only changes the status and links the original post
+		if err := markPostAsDuplicate(ctx, &cmd.MarkPostAsDuplicate{Post: c.Post, Original: c.Original}); err != nil {
+			return err
+		}
+
+		// Counters are aggregated when the post is read, so reloading it is enough to get them up to date
+		q := &query.GetPostByID{PostID: c.Original.ID}
+		if err := getPostByID(ctx, q); err != nil {
+			return err
+		}
+		c.Result = q.Result
+
+		c.MovedVoters = make([]*entity.User, len(voters))
+		for i, voter := range voters {
+			c.MovedVoters[i] = voter.ToModel(ctx)
+		}
+		return nil
+	})
+}
+
 func countPostPerStatus(ctx context.Context, q *query.CountPostPerStatus) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
 
@@ -221,7 +364,15 @@ func countPostPerStatus(ctx context.Context, q *query.CountPostPerStatus) error
 
 		q.Result = make(map[enum.PostStatus]int)
 		stats := []*dbStatusCount{}
-		err := trx.Select(&stats, "SELECT status, COUNT(*) AS count FROM posts WHERE tenant_id = $1 GROUP BY status", tenant.ID)
+		err := trx.Select(&stats, `
+			SELECT p.status, COUNT(*) AS count
+			FROM posts p
+			LEFT JOIN boards b
+			ON b.id = p.board_id
+			AND b.tenant_id = p.tenant_id
+			WHERE p.tenant_id = $1 AND ($2 = '' OR b.slug = $2)`+boardVisibilityFilter(user)+`
+			GROUP BY p.status
+		`, tenant.ID, q.Board)
 		if err != nil {
 			return errors.Wrap(err, "failed to count posts per status")
 		}
@@ -240,20 +391,41 @@ func addNewPost(ctx context.Context, c *cmd.AddNewPost) error {
 		// Detect language using lingua-go
 		lang := detectPostLanguage(c.Title, c.Description)
 
+		boardID := 0
+		if c.Board != nil {
+			boardID = c.Board.ID
+		}
+
 		err := trx.Get(&id,
-			`INSERT INTO posts (title, slug, number, description, tenant_id, user_id, created_at, status, is_approved, language)
-			 VALUES ($1, $2, (SELECT COALESCE(MAX(number), 0) + 1 FROM posts p WHERE p.tenant_id = $4), $3, $4, $5, $6, 0, $7, $8)
-			 RETURNING id`, c.Title, slug.Make(c.Title), c.Description, tenant.ID, user.ID, time.Now(), isApproved, lang)
+			`INSERT INTO posts (title, slug, number, description, tenant_id, user_id, created_at, status, is_approved, language, board_id)
+			 VALUES ($1, $2, (SELECT COALESCE(MAX(number), 0) + 1 FROM posts p WHERE p.tenant_id = $4), $3, $4, $5, $6, 0, $7, $8, NULLIF($9, 0))
+			 RETURNING id`, c.Title, slug.Make(c.Title), c.Description, tenant.ID, user.ID, time.Now(), isApproved, lang, boardID)
 		if err != nil {
 			return errors.Wrap(err, "failed add new post")
 		}
 
+		if c.Board != nil {
+			_, err = trx.Execute(`
+				INSERT INTO post_tags (tag_id, post_id, created_at, created_by_id, tenant_id)
+				SELECT bt.tag_id, $1, $2, $3, $4
+				FROM board_tags bt
+				WHERE bt.board_id = $5 AND bt.tenant_id = $4
+			`, id, time.Now(), user.ID, tenant.ID, c.Board.ID)
+			if err != nil {
+				return errors.Wrap(err, "failed to assign default tags of board '%s'", c.Board.Slug)
+			}
+		}
+
 		q := &query.GetPostByID{PostID: id}
 		if err := getPostByID(ctx, q); err != nil {
 			return err
 		}
 		c.Result = q.Result
 
+		if err := savePostSignature(trx, tenant, id, c.Title, c.Description); err != nil {
+			return err
+		}
+
 		if err := internalAddSubscriber(trx, q.Result, tenant, user, false); err != nil {
 			return err
 		}
@@ -264,6 +436,12 @@ func addNewPost(ctx context.Context, c *cmd.AddNewPost) error {
 
 func updatePost(ctx context.Context, c *cmd.UpdatePost) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		if c.Post.Title != c.Title || c.Post.Description != c.Description {
+			if err := addPostRevision(trx, tenant, user, c.Post.ID, c.Title, c.Description); err != nil {
+				return err
+			}
+		}
+
 		// Detect language using lingua-go
 		lang := detectPostLanguage(c.Title, c.Description)
 		_, err := trx.Execute(`UPDATE posts SET title = $1, slug = $2, description = $3, language = $4
@@ -273,6 +451,10 @@ func updatePost(ctx context.Context, c *cmd.UpdatePost) error {
 			return errors.Wrap(err, "failed update post")
 		}
 
+		if err := savePostSignature(trx, tenant, c.Post.ID, c.Title, c.Description); err != nil {
+			return err
+		}
+
 		q := &query.GetPostByID{PostID: c.Post.ID}
 		if err := getPostByID(ctx, q); err != nil {
 			return err
@@ -282,6 +464,35 @@ func updatePost(ctx context.Context, c *cmd.UpdatePost) error {
 	})
 }
 
+func regeneratePostsSearch(ctx context.Context, c *cmd.RegeneratePostsSearch) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		type dbPostText struct {
+			ID          int    `db:"id"`
+			Title       string `db:"title"`
+			Description string `db:"description"`
+		}
+
+		posts := []*dbPostText{}
+		if err := trx.Select(&posts, "SELECT id, title, description FROM posts WHERE tenant_id = $1 ORDER BY id", tenant.ID); err != nil {
+			return errors.Wrap(err, "failed to get posts to regenerate search")
+		}
+
+		// The search column is generated, so updating the language of a post recomputes it
+		for _, post := range posts {
+			lang := detectPostLanguage(post.Title, post.Description)
+			if _, err := trx.Execute("UPDATE posts SET language = $1 WHERE id = $2 AND tenant_id = $3", lang, post.ID, tenant.ID); err != nil {
+				return errors.Wrap(err, "failed to regenerate search of post with id '%d'", post.ID)
+			}
+			if err := savePostSignature(trx, tenant, post.ID, post.Title, post.Description); err != nil {
+				return err
+			}
+		}
+
+		c.Result = len(posts)
+		return nil
+	})
+}
+
 // detectPostLanguage uses lingua-go to detect the language of a post and maps it to a PostgreSQL tsvector config or 'simple'.
 // All language mappings are centralized in app/models/enum/locale.go
 func detectPostLanguage(title, description string) string {
@@ -362,53 +573,66 @@ func preprocessSearchQuery(query string) string {
 
 func findSimilarPosts(ctx context.Context, q *query.FindSimilarPosts) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
-		innerQuery := buildPostQuery(user, "p.tenant_id = $1 AND p.status = ANY($2)", "")
+		statuses, err := similarPostStatuses(trx, tenant)
+		if err != nil {
+			return err
+		}
 
-		filteredQuery := preprocessSearchQuery(q.Query)
+		// Posts found on the similarity index come first, as it also matches paraphrases of the query
+		ids, err := findIndexedSimilarPostIDs(trx, tenant, statuses, q.Query, maxSimilarPosts)
+		if err != nil {
+			return err
+		}
 
-		var (
-			posts []*dbEntities.Post
-			err   error
-		)
+		q.Result, err = getPostsByIDs(ctx, trx, tenant, user, statuses, ids)
+		if err != nil {
+			return err
+		}
 
-		if filteredQuery == "" {
-			q.Result = make([]*entity.Post, 0)
-		} else {
-			tsConfig := MapLocaleToTSConfig(tenant.Locale)
-
-			// Build tsquery with AND operator between words and prefix matching on each word
-			// The search column already contains both language-specific and simple tsvectors
-			tsQueryExpr := fmt.Sprintf("to_tsquery('%s', regexp_replace(regexp_replace($3, '\\\\s+', ':* & ', 'g'), '$', ':*'))", tsConfig)
-			tsQuerySimple := "to_tsquery('simple', regexp_replace(regexp_replace($3, '\\\\s+', ':* & ', 'g'), '$', ':*'))"
-
-			// Use ts_rank_cd (cover density ranking) for better relevance scoring
-			// Query against the generated search column which combines language-specific and simple tsvectors
-			score := fmt.Sprintf("ts_rank_cd(q.search, %s) + ts_rank_cd(q.search, %s)", tsQueryExpr, tsQuerySimple)
-
-			// Match against the pre-computed search column
-			whereParts := fmt.Sprintf(`q.search @@ %s OR q.search @@ %s`, tsQueryExpr, tsQuerySimple)
-
-			sql := fmt.Sprintf(`
-				SELECT * FROM (%s) AS q
-				WHERE %s
-				ORDER BY %s DESC
-				LIMIT 5
-			`, innerQuery, whereParts, score)
-			err = trx.Select(&posts, sql, tenant.ID, pq.Array([]enum.PostStatus{
-				enum.PostOpen,
-				enum.PostStarted,
-				enum.PostPlanned,
-				enum.PostCompleted,
-				enum.PostDeclined,
-			}), ToTSQuery(SanitizeString(q.Query)))
+		filteredQuery := preprocessSearchQuery(q.Query)
+		if filteredQuery == "" || len(q.Result) >= maxSimilarPosts {
+			return nil
 		}
+
+		innerQuery := buildPostQuery(user, "p.tenant_id = $1 AND p.status = ANY($2)", "")
+		tsConfig := MapLocaleToTSConfig(tenant.Locale)
+
+		// Build tsquery with AND operator between words and prefix matching on each word
+		// The search column already contains both language-specific and simple tsvectors
+		tsQueryExpr := fmt.Sprintf("to_tsquery('%s', regexp_replace(regexp_replace($3, '\\\\s+', ':* & ', 'g'), '$', ':*'))", tsConfig)
+		tsQuerySimple := "to_tsquery('simple', regexp_replace(regexp_replace($3, '\\\\s+', ':* & ', 'g'), '$', ':*'))"
+
+		// Use ts_rank_cd (cover density ranking) for better relevance scoring
+		// Query against the generated search column which combines language-specific and simple tsvectors
+		score := fmt.Sprintf("ts_rank_cd(q.search, %s) + ts_rank_cd(q.search, %s)", tsQueryExpr, tsQuerySimple)
+
+		// Match against the pre-computed search column
+		whereParts := fmt.Sprintf(`q.search @@ %s OR q.search @@ %s`, tsQueryExpr, tsQuerySimple)
+
+		sql := fmt.Sprintf(`
+			SELECT * FROM (%s) AS q
+			WHERE %s
+			ORDER BY %s DESC
+			LIMIT %d
+		`, innerQuery, whereParts, score, maxSimilarPosts)
+
+		var posts []*dbEntities.Post
+		err = trx.Select(&posts, sql, tenant.ID, pq.Array(statuses), ToTSQuery(SanitizeString(q.Query)))
 		if err != nil {
 			return errors.Wrap(err, "failed to find similar posts")
 		}
 
-		q.Result = make([]*entity.Post, len(posts))
-		for i, post := range posts {
-			q.Result[i] = post.ToModel(ctx)
+		found := make(map[int]bool, len(q.Result))
+		for _, post := range q.Result {
+			found[post.ID] = true
+		}
+		for _, post := range posts {
+			if len(q.Result) >= maxSimilarPosts {
+				break
+			}
+			if !found[post.ID] {
+				q.Result = append(q.Result, post.ToModel(ctx))
+			}
 		}
 		return nil
 	})
@@ -426,6 +650,11 @@ func searchPosts(ctx context.Context, q *query.SearchPosts) error {
 			q.Statuses = []enum.PostStatus{}
 		}
 
+		customStatuses, err := queryCustomPostStatuses(trx, tenant)
+		if err != nil {
+			return err
+		}
+
 		if q.Limit != "all" {
 			if _, err := strconv.Atoi(q.Limit); err != nil {
 				q.Limit = "30"
@@ -433,9 +662,34 @@ func searchPosts(ctx context.Context, q *query.SearchPosts) error {
 		}
 
 		var (
-			posts []*dbEntities.Post
-			err   error
+			condition       string
+			statuses        []enum.PostStatus
+			sort            string
+			searchFrom      = "(%s) AS q"
+			searchPredicate = "1 = 1"
+			snippetColumn   string
+			params          []any
+		)
+		// Cursors carry the time the first page was listed at, so that every page is sorted the same way
+		now := time.Now()
+		var (
+			cursorKey string
+			cursorID  int
 		)
+		if q.Cursor != "" {
+			key, id, err := cursor.Decode(q.Cursor)
+			if err != nil {
+				return err
+			}
+			cursorKey, cursorID = key, id
+			if score, listedAt, ok := strings.Cut(key, "@"); ok {
+				if now, err = time.Parse(time.RFC3339Nano, listedAt); err != nil {
+					return cursor.ErrInvalid
+				}
+				cursorKey = score
+			}
+		}
+
 		if q.Query != "" {
 			tsQuery := ToTSQuery(SanitizeString(q.Query))
 			if tsQuery == "" {
@@ -443,65 +697,146 @@ func searchPosts(ctx context.Context, q *query.SearchPosts) error {
 				return nil
 			}
 
-			tsConfig := MapLocaleToTSConfig(tenant.Locale)
+			match := buildSearchMatch(q, user, MapLocaleToTSConfig(tenant.Locale))
+			sort = match.score
+			searchFrom = match.from
+			searchPredicate = match.predicate
+			snippetColumn = ", " + match.snippet + " AS snippet"
+			condition, statuses, _ = getViewData(*q, 5, customStatuses, now)
+			params = []any{tenant.ID, pq.Array(statuses), tsQuery, strings.ToLower(strings.TrimSpace(SanitizeString(q.Query)))}
+		} else {
+			condition, statuses, sort = getViewData(*q, 3, customStatuses, now)
+			params = []any{tenant.ID, pq.Array(statuses)}
+		}
 
-			tsQueryExpr := fmt.Sprintf("to_tsquery('%s', regexp_replace(regexp_replace($3, '\\\\s+', ':* & ', 'g'), '$', ':*'))", tsConfig)
-			tsQuerySimple := "to_tsquery('simple', regexp_replace(regexp_replace($3, '\\\\s+', ':* & ', 'g'), '$', ':*'))"
+		if q.MyPostsOnly && user != nil {
+			condition += " AND user_id = " + strconv.Itoa(user.ID)
+		}
 
-			score := fmt.Sprintf("ts_rank_cd(q.search, %s) + ts_rank_cd(q.search, %s)", tsQueryExpr, tsQuerySimple)
+		attributeCondition, attributeTotal := getAttributeData(*q)
+		condition += attributeCondition
+		if attributeTotal != "" && q.Query == "" {
+			sort = attributeTotal
+		}
 
-			searchPredicate := fmt.Sprintf(`q.search @@ %s OR q.search @@ %s`, tsQueryExpr, tsQuerySimple)
+		if len(q.Tags) > 0 && !q.NoTagsOnly {
+			params = append(params, pq.Array(q.Tags))
+		}
 
-			condition, statuses, _ := getViewData(*q, 4)
+		if q.Board != "" {
+			params = append(params, q.Board)
+			condition += fmt.Sprintf(" AND board_slug = $%d", len(params))
+		}
 
-			if q.MyPostsOnly && user != nil {
-				condition += " AND user_id = " + strconv.Itoa(user.ID)
-			}
+		if q.ExcludeRoadmapBoards {
+			condition += " AND NOT EXISTS (SELECT 1 FROM boards rb WHERE rb.id = q.board_id AND rb.tenant_id = $1 AND rb.has_roadmap = true)"
+		}
 
-			sql := fmt.Sprintf(`
-				SELECT * FROM (%s) AS q
-				WHERE (%s) %s
-				ORDER BY %s DESC
-				LIMIT %s
-			`, innerQuery, searchPredicate, condition, score, q.Limit)
+		from := fmt.Sprintf(searchFrom, innerQuery)
+		filteredQuery := fmt.Sprintf(`SELECT q.*%s%s, COALESCE((%s)::numeric, 0) AS sort_key FROM %s WHERE (%s) %s`,
+			attributeTotalColumn(attributeTotal), snippetColumn, sort, from, searchPredicate, condition)
 
-			params := []interface{}{tenant.ID, pq.Array(statuses), tsQuery}
-			if len(q.Tags) > 0 && !q.NoTagsOnly {
-				params = append(params, pq.Array(q.Tags))
+		if q.CountTotal {
+			countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE (%s) %s`, from, searchPredicate, condition)
+			if err := trx.Scalar(&q.TotalCount, countQuery, params...); err != nil {
+				return errors.Wrap(err, "failed to count posts")
 			}
-			err = trx.Select(&posts, sql, params...)
-		} else {
-			condition, statuses, sort := getViewData(*q, 3)
+		}
 
-			if q.MyPostsOnly && user != nil {
-				condition += " AND user_id = " + strconv.Itoa(user.ID)
-			}
+		// Posts are sorted by the view's sort key and then by id, so that cursors point to a stable position
+		cursorCondition := ""
+		if q.Cursor != "" {
+			cursorCondition = fmt.Sprintf("WHERE (s.sort_key, s.id) < ($%d::numeric, $%d)", len(params)+1, len(params)+2)
+			params = append(params, cursorKey, cursorID)
+		}
 
-			sql := fmt.Sprintf(`
-				SELECT * FROM (%s) AS q
-				WHERE 1 = 1 %s
-				ORDER BY %s DESC
-				LIMIT %s
-			`, innerQuery, condition, sort, q.Limit)
-			params := []interface{}{tenant.ID, pq.Array(statuses)}
-			if len(q.Tags) > 0 && !q.NoTagsOnly {
-				params = append(params, pq.Array(q.Tags))
-			}
-			err = trx.Select(&posts, sql, params...)
+		// One more post than requested is fetched to know if there's a next page
+		pageSize, _ := strconv.Atoi(q.Limit)
+		limit := "ALL"
+		if q.Limit != "all" {
+			limit = strconv.Itoa(pageSize + 1)
 		}
 
-		if err != nil {
+		sql := fmt.Sprintf(`
+			SELECT s.* FROM (%s) AS s
+			%s
+			ORDER BY s.sort_key DESC, s.id DESC
+			LIMIT %s
+		`, filteredQuery, cursorCondition, limit)
+
+		var posts []*dbEntities.Post
+		if err := trx.Select(&posts, sql, params...); err != nil {
 			return errors.Wrap(err, "failed to search posts")
 		}
 
+		if q.Limit != "all" && pageSize > 0 && len(posts) > pageSize {
+			posts = posts[:pageSize]
+			last := posts[len(posts)-1]
+			q.NextCursor = cursor.Encode(last.SortKey+"@"+now.UTC().Format(time.RFC3339Nano), last.ID)
+		}
+
 		q.Result = make([]*entity.Post, len(posts))
 		for i, post := range posts {
 			q.Result[i] = post.ToModel(ctx)
+			if post.Snippet.Valid {
+				q.Result[i].Snippet = formatSearchSnippet(post.Snippet.String)
+			}
 		}
 		return nil
 	})
 }
 
+type searchMatch struct {
+	from      string
+	predicate string
+	score     string
+	snippet   string
+}
+
+// buildSearchMatch returns how posts are matched and ranked by a search query, which is expected on $3.
+// Full text matches on title and description rank first, followed by matches on comments and staff responses
+// when q.IncludeComments is set, while titles similar to the raw query on $4 are the fallback for misspellings
+func buildSearchMatch(q *query.SearchPosts, user *entity.User, tsConfig string) *searchMatch {
+	tsQueryExpr := fmt.Sprintf("to_tsquery('%s', regexp_replace(regexp_replace($3, '\\\\s+', ':* & ', 'g'), '$', ':*'))", tsConfig)
+	tsQuerySimple := "to_tsquery('simple', regexp_replace(regexp_replace($3, '\\\\s+', ':* & ', 'g'), '$', ':*'))"
+	matches := func(vector string) string {
+		return fmt.Sprintf("(%[1]s @@ %[2]s OR %[1]s @@ %[3]s)", vector, tsQueryExpr, tsQuerySimple)
+	}
+
+	match := &searchMatch{
+		from:      "(%s) AS q",
+		predicate: matches("q.search"),
+		score:     fmt.Sprintf("ts_rank_cd(q.search, %s) + ts_rank_cd(q.search, %s)", tsQueryExpr, tsQuerySimple),
+	}
+
+	// Short queries are too similar to most titles to be a useful fallback
+	match.predicate += fmt.Sprintf(" OR (char_length($4) >= %d AND word_similarity($4, q.title) >= %v)", minTrigramQueryLength, trigramSimilarityThreshold)
+	match.score += " + word_similarity($4, q.title) * 0.1"
+
+	snippetText := "q.description"
+	if q.IncludeComments {
+		internalFilter := " AND c.is_internal = false"
+		if user != nil && user.IsCollaborator() {
+			internalFilter = ""
+		}
+
+		// Comments are matched by their precomputed search column, which only uses the 'simple' config
+		matchingComments := fmt.Sprintf(`FROM comments c
+			WHERE c.post_id = q.id AND c.tenant_id = $1 AND c.deleted_at IS NULL AND c.is_approved = true%s AND c.search @@ %s`, internalFilter, tsQuerySimple)
+		response := fmt.Sprintf("setweight(to_tsvector('%[1]s', COALESCE(q.response, '')) || to_tsvector('simple', COALESCE(q.response, '')), 'D')", tsConfig)
+		description := fmt.Sprintf("(to_tsvector('%s', q.description) || to_tsvector('simple', q.description))", tsConfig)
+
+		match.predicate += fmt.Sprintf(" OR %s OR EXISTS (SELECT 1 %s)", matches(response), matchingComments)
+		match.score += fmt.Sprintf(" + ts_rank_cd(%[1]s, %[2]s) + ts_rank_cd(%[1]s, %[3]s)", response, tsQueryExpr, tsQuerySimple)
+		match.score += fmt.Sprintf(" + COALESCE((SELECT MAX(ts_rank_cd(c.search, %s)) %s), 0)", tsQuerySimple, matchingComments)
+		snippetText = fmt.Sprintf("CASE WHEN %s THEN q.description WHEN %s THEN q.response ELSE COALESCE((SELECT c.content %s ORDER BY c.id LIMIT 1), q.description) END",
+			matches(description), matches(response), matchingComments)
+	}
+
+	match.snippet = fmt.Sprintf("ts_headline('%s', %s, %s || %s, '%s')", tsConfig, snippetText, tsQueryExpr, tsQuerySimple, searchSnippetOptions)
+	return match
+}
+
 func getAllPosts(ctx context.Context, q *query.GetAllPosts) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
 		searchQuery := &query.SearchPosts{View: "all", Limit: "all"}
@@ -530,7 +865,7 @@ func buildPostQuery(user *entity.User, filter string, moderationFilter string) s
 	}
 	hasVotedSubQuery := "null"
 	if user != nil {
-		hasVotedSubQuery = fmt.Sprintf("(SELECT true FROM post_votes WHERE post_id = p.id AND user_id = %d)", user.ID)
+		hasVotedSubQuery = fmt.Sprintf("(SELECT weight FROM post_votes WHERE post_id = p.id AND user_id = %d)", user.ID)
 	}
 
 	// Add approval filtering based on moderation filter and user permissions
@@ -555,10 +890,26 @@ func buildPostQuery(user *entity.User, filter string, moderationFilter string) s
 		approvalFilter = " AND p.is_approved = true"
 	}
 
-	combinedFilter := filter + approvalFilter
+	combinedFilter := filter + approvalFilter + boardVisibilityFilter(user)
 	return fmt.Sprintf(sqlSelectPostsWhere, tagCondition, hasVotedSubQuery, combinedFilter)
 }
 
+// boardVisibilityFilter hides posts of private boards from users who are not staff members
+func boardVisibilityFilter(user *entity.User) string {
+	if user != nil && user.IsCollaborator() {
+		return ""
+	}
+	return " AND (b.id IS NULL OR b.is_public = true)"
+}
+
+// attributeTotalColumn returns the extra column selected when posts are segmented by an attribute
+func attributeTotalColumn(total string) string {
+	if total == "" {
+		return ""
+	}
+	return ", " + total + " AS attribute_total"
+}
+
 // buildSinglePostQuery is used for fetching individual posts (by ID, slug, or number)
 // Collaborators can view any post for moderation purposes
 func buildSinglePostQuery(user *entity.User, filter string) string {
@@ -568,7 +919,7 @@ func buildSinglePostQuery(user *entity.User, filter string) string {
 	}
 	hasVotedSubQuery := "null"
 	if user != nil {
-		hasVotedSubQuery = fmt.Sprintf("(SELECT true FROM post_votes WHERE post_id = p.id AND user_id = %d)", user.ID)
+		hasVotedSubQuery = fmt.Sprintf("(SELECT weight FROM post_votes WHERE post_id = p.id AND user_id = %d)", user.ID)
 	}
 
 	// Approval filtering for single post views
@@ -584,6 +935,6 @@ func buildSinglePostQuery(user *entity.User, filter string) string {
 		approvalFilter = " AND p.is_approved = true"
 	}
 
-	combinedFilter := filter + approvalFilter
+	combinedFilter := filter + approvalFilter + boardVisibilityFilter(user)
 	return fmt.Sprintf(sqlSelectPostsWhere, tagCondition, hasVotedSubQuery, combinedFilter)
 }
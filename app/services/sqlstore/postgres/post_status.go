@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/services/sqlstore/dbEntities"
+	"github.com/gosimple/slug"
+)
+
+func getCustomPostStatuses(ctx context.Context, q *query.GetCustomPostStatuses) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		statuses, err := queryCustomPostStatuses(trx, tenant)
+		if err != nil {
+			return err
+		}
+
+		q.Result = statuses
+		return nil
+	})
+}
+
+func getCustomPostStatusBySlug(ctx context.Context, q *query.GetCustomPostStatusBySlug) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		status, err := queryCustomPostStatusBySlug(trx, tenant, q.Slug)
+		q.Result = status
+		return err
+	})
+}
+
+func addCustomPostStatus(ctx context.Context, c *cmd.AddCustomPostStatus) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		c.Result = nil
+		newSlug := slug.Make(c.Name)
+
+		// Custom statuses are numbered after the built-in ones and values are never reused within a tenant
+		_, err := trx.Execute(`
+			INSERT INTO post_statuses (tenant_id, status, name, slug, color, accepts_votes, roadmap_column, created_at)
+			SELECT $1, GREATEST(COALESCE(MAX(status) + 1, $2), $2), $3, $4, $5, $6, $7, $8
+			FROM post_statuses
+			WHERE tenant_id = $1
+		`, tenant.ID, enum.PostCustomStatusStart, c.Name, newSlug, c.Color, c.AcceptsVotes, c.RoadmapColumn, time.Now())
+		if err != nil {
+			return errors.Wrap(err, "failed to add new post status")
+		}
+
+		status, err := queryCustomPostStatusBySlug(trx, tenant, newSlug)
+		c.Result = status
+		return err
+	})
+}
+
+func updateCustomPostStatus(ctx context.Context, c *cmd.UpdateCustomPostStatus) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		c.Result = nil
+		newSlug := slug.Make(c.Name)
+
+		_, err := trx.Execute(`
+			UPDATE post_statuses SET name = $1, slug = $2, color = $3, accepts_votes = $4, roadmap_column = $5
+			WHERE status = $6 AND tenant_id = $7
+		`, c.Name, newSlug, c.Color, c.AcceptsVotes, c.RoadmapColumn, c.Status.Status, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to update post status")
+		}
+
+		status, err := queryCustomPostStatusBySlug(trx, tenant, newSlug)
+		c.Result = status
+		return err
+	})
+}
+
+func deleteCustomPostStatus(ctx context.Context, c *cmd.DeleteCustomPostStatus) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		_, err := trx.Execute(`DELETE FROM post_statuses WHERE status = $1 AND tenant_id = $2`, c.Status.Status, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to delete post status '%s'", c.Status.Slug)
+		}
+		return nil
+	})
+}
+
+func queryCustomPostStatusBySlug(trx *dbx.Trx, tenant *entity.Tenant, slug string) (*entity.CustomPostStatus, error) {
+	status := dbEntities.PostStatus{}
+
+	err := trx.Get(&status, `
+		SELECT status, name, slug, color, accepts_votes, roadmap_column
+		FROM post_statuses
+		WHERE tenant_id = $1 AND slug = $2
+	`, tenant.ID, slug)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get post status with slug '%s'", slug)
+	}
+
+	return status.ToModel(), nil
+}
+
+func queryCustomPostStatus(trx *dbx.Trx, tenant *entity.Tenant, status enum.PostStatus) (*entity.CustomPostStatus, error) {
+	customStatus := dbEntities.PostStatus{}
+
+	err := trx.Get(&customStatus, `
+		SELECT status, name, slug, color, accepts_votes, roadmap_column
+		FROM post_statuses
+		WHERE tenant_id = $1 AND status = $2
+	`, tenant.ID, status)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get post status '%d'", status)
+	}
+
+	return customStatus.ToModel(), nil
+}
+
+func queryCustomPostStatuses(trx *dbx.Trx, tenant *entity.Tenant) ([]*entity.CustomPostStatus, error) {
+	statuses := []*dbEntities.PostStatus{}
+	err := trx.Select(&statuses, `
+		SELECT status, name, slug, color, accepts_votes, roadmap_column
+		FROM post_statuses
+		WHERE tenant_id = $1
+		ORDER BY status
+	`, tenant.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get post statuses")
+	}
+
+	result := make([]*entity.CustomPostStatus, len(statuses))
+	for i, status := range statuses {
+		result[i] = status.ToModel()
+	}
+	return result, nil
+}
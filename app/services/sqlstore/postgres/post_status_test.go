@@ -0,0 +1,80 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+)
+
+func TestPostStatusStorage_AddUpdateAndGet(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	addStatus := &cmd.AddCustomPostStatus{Name: "Under review", Color: "FF0000", AcceptsVotes: true, RoadmapColumn: "planned"}
+	err := bus.Dispatch(demoTenantCtx, addStatus)
+	Expect(err).IsNil()
+	Expect(addStatus.Result.Status).Equals(enum.PostCustomStatusStart)
+	Expect(addStatus.Result.Slug).Equals("under-review")
+
+	addOther := &cmd.AddCustomPostStatus{Name: "In beta", Color: "00FF00", AcceptsVotes: false, RoadmapColumn: "started"}
+	err = bus.Dispatch(demoTenantCtx, addOther)
+	Expect(err).IsNil()
+	Expect(addOther.Result.Status).Equals(enum.PostCustomStatusStart + 1)
+
+	updateStatus := &cmd.UpdateCustomPostStatus{Status: addOther.Result, Name: "Beta", Color: "0000FF", AcceptsVotes: true, RoadmapColumn: ""}
+	err = bus.Dispatch(demoTenantCtx, updateStatus)
+	Expect(err).IsNil()
+
+	getStatus := &query.GetCustomPostStatusBySlug{Slug: "beta"}
+	getAll := &query.GetCustomPostStatuses{}
+	err = bus.Dispatch(demoTenantCtx, getStatus, getAll)
+	Expect(err).IsNil()
+	Expect(getStatus.Result.Status).Equals(addOther.Result.Status)
+	Expect(getStatus.Result.Color).Equals("0000FF")
+	Expect(getStatus.Result.AcceptsVotes).IsTrue()
+	Expect(getStatus.Result.RoadmapColumn).Equals("")
+	Expect(getAll.Result).HasLen(2)
+
+	err = bus.Dispatch(demoTenantCtx, &cmd.DeleteCustomPostStatus{Status: getStatus.Result})
+	Expect(err).IsNil()
+
+	getStatus = &query.GetCustomPostStatusBySlug{Slug: "beta"}
+	err = bus.Dispatch(demoTenantCtx, getStatus)
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+}
+
+func TestPostStatusStorage_SetResponseAndSearch(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	addStatus := &cmd.AddCustomPostStatus{Name: "In beta", Color: "00FF00", AcceptsVotes: false, RoadmapColumn: "started"}
+	err := bus.Dispatch(demoTenantCtx, addStatus)
+	Expect(err).IsNil()
+
+	newPost := &cmd.AddNewPost{Title: "My new post", Description: "with this description"}
+	err = bus.Dispatch(jonSnowCtx, newPost)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.SetPostResponse{Post: newPost.Result, Text: "Try it out", Status: addStatus.Result.Status})
+	Expect(err).IsNil()
+
+	getPost := &query.GetPostByID{PostID: newPost.Result.ID}
+	err = bus.Dispatch(jonSnowCtx, getPost)
+	Expect(err).IsNil()
+	Expect(getPost.Result.Status).Equals(addStatus.Result.Status)
+	Expect(getPost.Result.CustomStatus.Name).Equals("In beta")
+	Expect(getPost.Result.StatusName()).Equals("In beta")
+	Expect(getPost.Result.CanBeVoted()).IsFalse()
+
+	startedPosts := &query.SearchPosts{View: "started"}
+	err = bus.Dispatch(jonSnowCtx, startedPosts)
+	Expect(err).IsNil()
+	Expect(startedPosts.Result).HasLen(1)
+	Expect(startedPosts.Result[0].ID).Equals(newPost.Result.ID)
+}
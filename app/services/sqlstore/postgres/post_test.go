@@ -240,6 +240,58 @@ func TestPostStorage_SearchEnglishPosts_DescriptionMatch(t *testing.T) {
 	Expect(slugs["export-functionality"]).IsTrue()
 }
 
+func TestPostStorage_Search_Typo(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	newPost := &cmd.AddNewPost{Title: "Notifications for mentions", Description: "Send me an email"}
+	err := bus.Dispatch(jonSnowCtx, newPost)
+	Expect(err).IsNil()
+
+	search := &query.SearchPosts{Query: "notifcations"}
+	err = bus.Dispatch(demoTenantCtx, search)
+	Expect(err).IsNil()
+	Expect(search.Result).HasLen(1)
+	Expect(search.Result[0].ID).Equals(newPost.Result.ID)
+
+	search = &query.SearchPosts{Query: "nope"}
+	err = bus.Dispatch(demoTenantCtx, search)
+	Expect(err).IsNil()
+	Expect(search.Result).HasLen(0)
+}
+
+func TestPostStorage_Search_IncludeComments(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	titleMatch := &cmd.AddNewPost{Title: "Dark theme", Description: "Please add a dark theme"}
+	err := bus.Dispatch(jonSnowCtx, titleMatch)
+	Expect(err).IsNil()
+
+	commentMatch := &cmd.AddNewPost{Title: "Better colors", Description: "The colors are too bright"}
+	err = bus.Dispatch(jonSnowCtx, commentMatch)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(aryaStarkCtx, &cmd.AddNewComment{Post: commentMatch.Result, Content: "A dark theme would fix it"})
+	Expect(err).IsNil()
+
+	search := &query.SearchPosts{Query: "dark"}
+	err = bus.Dispatch(demoTenantCtx, search)
+	Expect(err).IsNil()
+	Expect(search.Result).HasLen(1)
+	Expect(search.Result[0].ID).Equals(titleMatch.Result.ID)
+	Expect(search.Result[0].Snippet).Equals("Please add a <mark>dark</mark> theme")
+
+	search = &query.SearchPosts{Query: "dark", IncludeComments: true, CountTotal: true}
+	err = bus.Dispatch(demoTenantCtx, search)
+	Expect(err).IsNil()
+	Expect(search.TotalCount).Equals(2)
+	Expect(search.Result).HasLen(2)
+	Expect(search.Result[0].ID).Equals(titleMatch.Result.ID)
+	Expect(search.Result[1].ID).Equals(commentMatch.Result.ID)
+	Expect(search.Result[1].Snippet).Equals("A <mark>dark</mark> theme would fix it")
+}
+
 func TestPostStorage_AddAndGet(t *testing.T) {
 	SetupDatabaseTest(t)
 	defer TeardownDatabaseTest()
@@ -311,6 +363,43 @@ func TestPostStorage_AddAndReturnComments(t *testing.T) {
 	Expect(commentsByPost.Result[1].User.Name).Equals("Jon Snow")
 }
 
+func TestPostStorage_InternalNotesOnlyVisibleToStaff(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	newPost := &cmd.AddNewPost{Title: "My new post", Description: "with this description"}
+	err := bus.Dispatch(jonSnowCtx, newPost)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(aryaStarkCtx, &cmd.AddNewComment{Post: newPost.Result, Content: "Public comment"})
+	Expect(err).IsNil()
+
+	addNote := &cmd.AddNewComment{Post: newPost.Result, Content: "Internal note", IsInternal: true}
+	err = bus.Dispatch(jonSnowCtx, addNote)
+	Expect(err).IsNil()
+	Expect(addNote.Result.IsInternal).IsTrue()
+
+	staffComments := &query.GetCommentsByPost{Post: newPost.Result}
+	err = bus.Dispatch(jonSnowCtx, staffComments)
+	Expect(err).IsNil()
+	Expect(staffComments.Result).HasLen(2)
+
+	visitorComments := &query.GetCommentsByPost{Post: newPost.Result}
+	err = bus.Dispatch(aryaStarkCtx, visitorComments)
+	Expect(err).IsNil()
+	Expect(visitorComments.Result).HasLen(1)
+	Expect(visitorComments.Result[0].Content).Equals("Public comment")
+
+	noteByID := &query.GetCommentByID{CommentID: addNote.Result.ID}
+	err = bus.Dispatch(aryaStarkCtx, noteByID)
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+
+	postByID := &query.GetPostByID{PostID: newPost.Result.ID}
+	err = bus.Dispatch(jonSnowCtx, postByID)
+	Expect(err).IsNil()
+	Expect(postByID.Result.CommentsCount).Equals(1)
+}
+
 func TestPostStorage_AddGetUpdateComment(t *testing.T) {
 	SetupDatabaseTest(t)
 	defer TeardownDatabaseTest()
@@ -465,6 +554,111 @@ func TestPostStorage_AddVote_Twice(t *testing.T) {
 	Expect(getPost.Result.VotesCount).Equals(1)
 }
 
+func TestPostStorage_AddVote_Weighted(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	newPost := &cmd.AddNewPost{Title: "My new post", Description: "with this description"}
+	err := bus.Dispatch(jonSnowCtx, newPost)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(
+		jonSnowCtx,
+		&cmd.AddVote{Post: newPost.Result, User: jonSnow, Weight: 3},
+		&cmd.AddVote{Post: newPost.Result, User: aryaStark, Weight: 2},
+	)
+	Expect(err).IsNil()
+
+	getPost := &query.GetPostByID{PostID: newPost.Result.ID}
+	err = bus.Dispatch(jonSnowCtx, getPost)
+	Expect(err).IsNil()
+	Expect(getPost.Result.HasVoted).IsTrue()
+	Expect(getPost.Result.VoteWeight).Equals(3)
+	Expect(getPost.Result.VotesCount).Equals(5)
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.AddVote{Post: newPost.Result, User: jonSnow, Weight: 1})
+	Expect(err).IsNil()
+
+	getPost = &query.GetPostByID{PostID: newPost.Result.ID}
+	err = bus.Dispatch(jonSnowCtx, getPost)
+	Expect(err).IsNil()
+	Expect(getPost.Result.VoteWeight).Equals(1)
+	Expect(getPost.Result.VotesCount).Equals(3)
+}
+
+func TestVoteStorage_GetVoteBudget(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	post1 := &cmd.AddNewPost{Title: "My first post", Description: "with this description"}
+	post2 := &cmd.AddNewPost{Title: "My second post", Description: "with this description"}
+	bus.MustDispatch(jonSnowCtx, post1, post2)
+
+	bus.MustDispatch(
+		jonSnowCtx,
+		&cmd.AddVote{Post: post1.Result, User: jonSnow, Weight: 3},
+		&cmd.AddVote{Post: post2.Result, User: jonSnow, Weight: 2},
+		&cmd.AddVote{Post: post2.Result, User: aryaStark, Weight: 4},
+	)
+
+	getBudget := &query.GetVoteBudget{}
+	err := bus.Dispatch(jonSnowCtx, getBudget)
+	Expect(err).IsNil()
+	Expect(getBudget.Result.Spent).Equals(5)
+
+	// votes on closed posts are given back
+	bus.MustDispatch(jonSnowCtx, &cmd.SetPostResponse{Post: post1.Result, Text: "Done", Status: enum.PostCompleted})
+
+	getBudget = &query.GetVoteBudget{}
+	err = bus.Dispatch(jonSnowCtx, getBudget)
+	Expect(err).IsNil()
+	Expect(getBudget.Result.Spent).Equals(2)
+}
+
+func TestVoteStorage_AddVote_OverBudget(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	post1 := &cmd.AddNewPost{Title: "My first post", Description: "with this description"}
+	post2 := &cmd.AddNewPost{Title: "My second post", Description: "with this description"}
+	bus.MustDispatch(jonSnowCtx, post1, post2)
+
+	demoTenant.VoteBudget = 5
+	bus.MustDispatch(jonSnowCtx, &cmd.AddVote{Post: post1.Result, User: jonSnow, Weight: 3})
+
+	err := bus.Dispatch(jonSnowCtx, &cmd.AddVote{Post: post2.Result, User: jonSnow, Weight: 3})
+	Expect(errors.Cause(err)).Equals(app.ErrVoteBudgetExceeded)
+
+	// votes already placed on the same post are given back
+	err = bus.Dispatch(jonSnowCtx, &cmd.AddVote{Post: post1.Result, User: jonSnow, Weight: 5})
+	Expect(err).IsNil()
+
+	getBudget := &query.GetVoteBudget{}
+	err = bus.Dispatch(jonSnowCtx, getBudget)
+	Expect(err).IsNil()
+	Expect(getBudget.Result.Spent).Equals(5)
+}
+
+func TestPostStorage_MarkAsDuplicate_VoterAtBudget(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	original := &cmd.AddNewPost{Title: "My first post", Description: "with this description"}
+	duplicate := &cmd.AddNewPost{Title: "My second post", Description: "with this description"}
+	bus.MustDispatch(jonSnowCtx, original, duplicate)
+
+	demoTenant.VoteBudget = 5
+	bus.MustDispatch(jonSnowCtx, &cmd.AddVote{Post: duplicate.Result, User: aryaStark, Weight: 5})
+
+	err := bus.Dispatch(jonSnowCtx, &cmd.MarkPostAsDuplicate{Post: duplicate.Result, Original: original.Result})
+	Expect(err).IsNil()
+
+	getOriginal := &query.GetPostByID{PostID: original.Result.ID}
+	bus.MustDispatch(jonSnowCtx, getOriginal)
+	Expect(getOriginal.Result.VotesCount).Equals(1)
+	Expect(getOriginal.Result.VoteWeight).Equals(5)
+}
+
 func TestPostStorage_RemoveVote(t *testing.T) {
 	SetupDatabaseTest(t)
 	defer TeardownDatabaseTest()
@@ -603,6 +797,79 @@ func TestPostStorage_SetResponse_AsDuplicate(t *testing.T) {
 	Expect(getPost2.Result.Response.Original.Status).Equals(newPost1.Result.Status)
 }
 
+func TestPostStorage_MergePosts(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	original := &cmd.AddNewPost{Title: "My new post", Description: "with this description"}
+	duplicate := &cmd.AddNewPost{Title: "My other post", Description: "with similar description"}
+	bus.MustDispatch(jonSnowCtx, original)
+	bus.MustDispatch(aryaStarkCtx, duplicate)
+
+	addBug := &cmd.AddNewTag{Name: "Bug", Color: "FF0000", IsPublic: true}
+	bus.MustDispatch(jonSnowCtx, addBug)
+
+	addComment := &cmd.AddNewComment{Post: duplicate.Result, Content: "Me too!"}
+	bus.MustDispatch(sansaStarkCtx, addComment)
+
+	bus.MustDispatch(jonSnowCtx,
+		&cmd.AddVote{Post: original.Result, User: jonSnow},
+		&cmd.AddVote{Post: duplicate.Result, User: jonSnow},
+		&cmd.AddVote{Post: duplicate.Result, User: aryaStark},
+		&cmd.AddVote{Post: duplicate.Result, User: sansaStark},
+		&cmd.AssignTag{Tag: addBug.Result, Post: duplicate.Result},
+	)
+
+	mergePosts := &cmd.MergePosts{Post: duplicate.Result, Original: original.Result, MergeComments: true}
+	err := bus.Dispatch(jonSnowCtx, mergePosts)
+	Expect(err).IsNil()
+
+	Expect(mergePosts.Result.ID).Equals(original.Result.ID)
+	Expect(mergePosts.Result.VotesCount).Equals(3)
+	Expect(mergePosts.Result.CommentsCount).Equals(1)
+	Expect(mergePosts.Result.Tags).Equals([]string{"bug"})
+	Expect(mergePosts.MovedVoters).HasLen(2)
+	Expect(mergePosts.MovedVoters[0].ID).Equals(aryaStark.ID)
+	Expect(mergePosts.MovedVoters[1].ID).Equals(sansaStark.ID)
+
+	getDuplicate := &query.GetPostByID{PostID: duplicate.Result.ID}
+	bus.MustDispatch(jonSnowCtx, getDuplicate)
+	Expect(getDuplicate.Result.Status).Equals(enum.PostDuplicate)
+	Expect(getDuplicate.Result.VotesCount).Equals(0)
+	Expect(getDuplicate.Result.CommentsCount).Equals(0)
+	Expect(getDuplicate.Result.Response.Original.Number).Equals(original.Result.Number)
+	Expect(getDuplicate.Result.Tags).HasLen(0)
+
+	subscribed := &query.UserSubscribedTo{PostID: original.Result.ID}
+	bus.MustDispatch(aryaStarkCtx, subscribed)
+	Expect(subscribed.Result).IsTrue()
+
+	subscribers, err := trx.Count("SELECT * FROM post_subscribers WHERE post_id = $1", duplicate.Result.ID)
+	Expect(err).IsNil()
+	Expect(subscribers).Equals(0)
+}
+
+func TestPostStorage_MergePosts_WithoutComments(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	original := &cmd.AddNewPost{Title: "My new post", Description: "with this description"}
+	duplicate := &cmd.AddNewPost{Title: "My other post", Description: "with similar description"}
+	bus.MustDispatch(jonSnowCtx, original)
+	bus.MustDispatch(aryaStarkCtx, duplicate)
+	bus.MustDispatch(sansaStarkCtx, &cmd.AddNewComment{Post: duplicate.Result, Content: "Me too!"})
+
+	mergePosts := &cmd.MergePosts{Post: duplicate.Result, Original: original.Result}
+	err := bus.Dispatch(jonSnowCtx, mergePosts)
+	Expect(err).IsNil()
+	Expect(mergePosts.Result.CommentsCount).Equals(0)
+	Expect(mergePosts.MovedVoters).HasLen(0)
+
+	getDuplicate := &query.GetPostByID{PostID: duplicate.Result.ID}
+	bus.MustDispatch(jonSnowCtx, getDuplicate)
+	Expect(getDuplicate.Result.CommentsCount).Equals(1)
+}
+
 func TestPostStorage_SetResponse_AsDeleted(t *testing.T) {
 	SetupDatabaseTest(t)
 	defer TeardownDatabaseTest()
@@ -1140,3 +1407,219 @@ func TestViewReactions_AnonymousUser(t *testing.T) {
 	Expect(commentByID.Result[0].ReactionCounts[0].Count).Equals(1)
 	Expect(commentByID.Result[0].ReactionCounts[0].IncludesMe).IsFalse()
 }
+
+func TestPostStorage_Search_ByVoterAttributes(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	post1 := &cmd.AddNewPost{Title: "My first post", Description: "with this description"}
+	post2 := &cmd.AddNewPost{Title: "My second post", Description: "with this description"}
+	bus.MustDispatch(jonSnowCtx, post1, post2)
+
+	bus.MustDispatch(demoTenantCtx,
+		&cmd.SetUserAttributes{UserID: jonSnow.ID, Attributes: map[string]string{"plan": "pro", "mrr": "300"}},
+		&cmd.SetUserAttributes{UserID: aryaStark.ID, Attributes: map[string]string{"plan": "enterprise", "mrr": "1200"}},
+	)
+
+	bus.MustDispatch(jonSnowCtx,
+		&cmd.AddVote{Post: post1.Result, User: jonSnow},
+		&cmd.AddVote{Post: post2.Result, User: jonSnow},
+		&cmd.AddVote{Post: post2.Result, User: aryaStark},
+	)
+
+	searchPosts := &query.SearchPosts{SortAttribute: "mrr"}
+	err := bus.Dispatch(jonSnowCtx, searchPosts)
+	Expect(err).IsNil()
+	Expect(searchPosts.Result).HasLen(2)
+	Expect(searchPosts.Result[0].ID).Equals(post2.Result.ID)
+	Expect(*searchPosts.Result[0].AttributeTotal).Equals(float64(1500))
+	Expect(searchPosts.Result[1].ID).Equals(post1.Result.ID)
+	Expect(*searchPosts.Result[1].AttributeTotal).Equals(float64(300))
+
+	searchPosts = &query.SearchPosts{VoterAttributes: map[string]string{"plan": "enterprise"}}
+	err = bus.Dispatch(jonSnowCtx, searchPosts)
+	Expect(err).IsNil()
+	Expect(searchPosts.Result).HasLen(1)
+	Expect(searchPosts.Result[0].ID).Equals(post2.Result.ID)
+
+	searchPosts = &query.SearchPosts{SortAttribute: "mrr", MinAttributeTotal: 1000}
+	err = bus.Dispatch(jonSnowCtx, searchPosts)
+	Expect(err).IsNil()
+	Expect(searchPosts.Result).HasLen(1)
+	Expect(searchPosts.Result[0].ID).Equals(post2.Result.ID)
+}
+
+func TestPostStorage_Search_Pagination(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	post1 := &cmd.AddNewPost{Title: "My first post", Description: "with this description"}
+	post2 := &cmd.AddNewPost{Title: "My second post", Description: "with this description"}
+	post3 := &cmd.AddNewPost{Title: "My third post", Description: "with this description"}
+	bus.MustDispatch(jonSnowCtx, post1, post2, post3)
+
+	firstPage := &query.SearchPosts{View: "recent", Limit: "2", CountTotal: true}
+	err := bus.Dispatch(jonSnowCtx, firstPage)
+	Expect(err).IsNil()
+	Expect(firstPage.TotalCount).Equals(3)
+	Expect(firstPage.Result).HasLen(2)
+	Expect(firstPage.Result[0].ID).Equals(post3.Result.ID)
+	Expect(firstPage.Result[1].ID).Equals(post2.Result.ID)
+	Expect(firstPage.NextCursor).IsNotEmpty()
+
+	secondPage := &query.SearchPosts{View: "recent", Limit: "2", Cursor: firstPage.NextCursor, CountTotal: true}
+	err = bus.Dispatch(jonSnowCtx, secondPage)
+	Expect(err).IsNil()
+	Expect(secondPage.TotalCount).Equals(3)
+	Expect(secondPage.Result).HasLen(1)
+	Expect(secondPage.Result[0].ID).Equals(post1.Result.ID)
+	Expect(secondPage.NextCursor).Equals("")
+}
+
+func TestPostStorage_Search_PaginationWithTies(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	post1 := &cmd.AddNewPost{Title: "My first post", Description: "with this description"}
+	post2 := &cmd.AddNewPost{Title: "My second post", Description: "with this description"}
+	bus.MustDispatch(jonSnowCtx, post1, post2)
+
+	firstPage := &query.SearchPosts{View: "most-wanted", Limit: "1"}
+	err := bus.Dispatch(jonSnowCtx, firstPage)
+	Expect(err).IsNil()
+	Expect(firstPage.Result).HasLen(1)
+	Expect(firstPage.Result[0].ID).Equals(post2.Result.ID)
+
+	secondPage := &query.SearchPosts{View: "most-wanted", Limit: "1", Cursor: firstPage.NextCursor}
+	err = bus.Dispatch(jonSnowCtx, secondPage)
+	Expect(err).IsNil()
+	Expect(secondPage.Result).HasLen(1)
+	Expect(secondPage.Result[0].ID).Equals(post1.Result.ID)
+}
+
+func TestPostStorage_Search_TrendingPagination(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	post1 := &cmd.AddNewPost{Title: "My first post", Description: "with this description"}
+	post2 := &cmd.AddNewPost{Title: "My second post", Description: "with this description"}
+	post3 := &cmd.AddNewPost{Title: "My third post", Description: "with this description"}
+	bus.MustDispatch(jonSnowCtx, post1, post2, post3)
+
+	firstPage := &query.SearchPosts{View: "trending", Limit: "2"}
+	err := bus.Dispatch(jonSnowCtx, firstPage)
+	Expect(err).IsNil()
+	Expect(firstPage.Result).HasLen(2)
+
+	// Scores decay while the pages are listed, the second page is still scored as the first one was
+	time.Sleep(10 * time.Millisecond)
+	secondPage := &query.SearchPosts{View: "trending", Limit: "2", Cursor: firstPage.NextCursor}
+	err = bus.Dispatch(jonSnowCtx, secondPage)
+	Expect(err).IsNil()
+	Expect(secondPage.Result).HasLen(1)
+	Expect(secondPage.Result[0].ID).NotEquals(firstPage.Result[0].ID)
+	Expect(secondPage.Result[0].ID).NotEquals(firstPage.Result[1].ID)
+}
+
+func TestPostStorage_ListVotesOfPost_Pagination(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	newPost := &cmd.AddNewPost{Title: "My new post", Description: "with this description"}
+	bus.MustDispatch(jonSnowCtx, newPost)
+	bus.MustDispatch(jonSnowCtx, &cmd.AddVote{Post: newPost.Result, User: jonSnow})
+	bus.MustDispatch(jonSnowCtx, &cmd.AddVote{Post: newPost.Result, User: aryaStark})
+
+	firstPage := &query.ListPostVotes{PostID: newPost.Result.ID, Limit: 1, CountTotal: true}
+	err := bus.Dispatch(jonSnowCtx, firstPage)
+	Expect(err).IsNil()
+	Expect(firstPage.TotalCount).Equals(2)
+	Expect(firstPage.Result).HasLen(1)
+	Expect(firstPage.Result[0].User.Name).Equals("Jon Snow")
+
+	secondPage := &query.ListPostVotes{PostID: newPost.Result.ID, Limit: 1, Cursor: firstPage.NextCursor}
+	err = bus.Dispatch(jonSnowCtx, secondPage)
+	Expect(err).IsNil()
+	Expect(secondPage.Result).HasLen(1)
+	Expect(secondPage.Result[0].User.Name).Equals("Arya Stark")
+	Expect(secondPage.NextCursor).Equals("")
+}
+
+func TestPostStorage_GetCommentsByPost_Pagination(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	newPost := &cmd.AddNewPost{Title: "My new post", Description: "with this description"}
+	bus.MustDispatch(jonSnowCtx, newPost)
+	bus.MustDispatch(jonSnowCtx, &cmd.AddNewComment{Post: newPost.Result, Content: "First comment"})
+	bus.MustDispatch(jonSnowCtx, &cmd.AddNewComment{Post: newPost.Result, Content: "Second comment"})
+	bus.MustDispatch(jonSnowCtx, &cmd.AddNewComment{Post: newPost.Result, Content: "Third comment"})
+
+	firstPage := &query.GetCommentsByPost{Post: newPost.Result, Limit: 2, CountTotal: true}
+	err := bus.Dispatch(jonSnowCtx, firstPage)
+	Expect(err).IsNil()
+	Expect(firstPage.TotalCount).Equals(3)
+	Expect(firstPage.Result).HasLen(2)
+	Expect(firstPage.Result[0].Content).Equals("Third comment")
+	Expect(firstPage.Result[1].Content).Equals("Second comment")
+
+	secondPage := &query.GetCommentsByPost{Post: newPost.Result, Limit: 2, Cursor: firstPage.NextCursor}
+	err = bus.Dispatch(jonSnowCtx, secondPage)
+	Expect(err).IsNil()
+	Expect(secondPage.Result).HasLen(1)
+	Expect(secondPage.Result[0].Content).Equals("First comment")
+	Expect(secondPage.NextCursor).Equals("")
+}
+
+func TestPostStorage_GetCommentsByPost_Threaded(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	newPost := &cmd.AddNewPost{Title: "My new post", Description: "with this description"}
+	bus.MustDispatch(jonSnowCtx, newPost)
+
+	first := &cmd.AddNewComment{Post: newPost.Result, Content: "First comment"}
+	bus.MustDispatch(jonSnowCtx, first)
+	reply := &cmd.AddNewComment{Post: newPost.Result, Content: "Reply to first", ParentID: first.Result.ID}
+	bus.MustDispatch(aryaStarkCtx, reply)
+	bus.MustDispatch(jonSnowCtx, &cmd.AddNewComment{Post: newPost.Result, Content: "Reply to reply", ParentID: reply.Result.ID})
+	bus.MustDispatch(jonSnowCtx, &cmd.AddNewComment{Post: newPost.Result, Content: "Second comment"})
+
+	threaded := &query.GetCommentsByPost{Post: newPost.Result, Threaded: true, CountTotal: true}
+	err := bus.Dispatch(jonSnowCtx, threaded)
+	Expect(err).IsNil()
+	Expect(threaded.TotalCount).Equals(2)
+	Expect(threaded.Result).HasLen(2)
+	Expect(threaded.Result[0].Content).Equals("Second comment")
+	Expect(threaded.Result[0].Replies).HasLen(0)
+	Expect(threaded.Result[1].Content).Equals("First comment")
+	Expect(threaded.Result[1].Replies).HasLen(1)
+	Expect(threaded.Result[1].Replies[0].Content).Equals("Reply to first")
+	Expect(*threaded.Result[1].Replies[0].ParentID).Equals(first.Result.ID)
+	Expect(threaded.Result[1].Replies[0].Replies).HasLen(1)
+	Expect(threaded.Result[1].Replies[0].Replies[0].Content).Equals("Reply to reply")
+
+	flat := &query.GetCommentsByPost{Post: newPost.Result}
+	err = bus.Dispatch(jonSnowCtx, flat)
+	Expect(err).IsNil()
+	Expect(flat.Result).HasLen(4)
+}
+
+func TestPostStorage_RegeneratePostsSearch(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	bus.MustDispatch(jonSnowCtx, &cmd.AddNewPost{Title: "Add dark mode", Description: "The light theme hurts my eyes at night"})
+	bus.MustDispatch(jonSnowCtx, &cmd.AddNewPost{Title: "Exportar para PDF", Description: "Gostaria de exportar as ideias para PDF"})
+
+	regenerate := &cmd.RegeneratePostsSearch{}
+	err := bus.Dispatch(jonSnowCtx, regenerate)
+	Expect(err).IsNil()
+	Expect(regenerate.Result).Equals(2)
+
+	posts := &query.SearchPosts{Query: "dark"}
+	err = bus.Dispatch(jonSnowCtx, posts)
+	Expect(err).IsNil()
+	Expect(posts.Result).HasLen(1)
+	Expect(posts.Result[0].Title).Equals("Add dark mode")
+}
@@ -43,6 +43,10 @@ func (s Service) Init() {
 	bus.AddHandler(removeSubscriber)
 	bus.AddHandler(supressEmail)
 	bus.AddHandler(getActiveSubscribers)
+	bus.AddHandler(getEmailDigestFrequencies)
+	bus.AddHandler(addEmailDigestItem)
+	bus.AddHandler(listPendingEmailDigestItems)
+	bus.AddHandler(deleteEmailDigestItems)
 
 	bus.AddHandler(getTagBySlug)
 	bus.AddHandler(getAssignedTags)
@@ -53,20 +57,42 @@ func (s Service) Init() {
 	bus.AddHandler(assignTag)
 	bus.AddHandler(unassignTag)
 
+	bus.AddHandler(getBoardBySlug)
+	bus.AddHandler(getAllBoards)
+	bus.AddHandler(addNewBoard)
+	bus.AddHandler(updateBoard)
+	bus.AddHandler(deleteBoard)
+	bus.AddHandler(setPostBoard)
+
+	bus.AddHandler(getCustomPostStatuses)
+	bus.AddHandler(getCustomPostStatusBySlug)
+	bus.AddHandler(addCustomPostStatus)
+	bus.AddHandler(updateCustomPostStatus)
+	bus.AddHandler(deleteCustomPostStatus)
+
 	bus.AddHandler(addVote)
 	bus.AddHandler(removeVote)
+	bus.AddHandler(getVoteBudget)
 	bus.AddHandler(listPostVotes)
 
 	bus.AddHandler(addNewPost)
+	bus.AddHandler(importPost)
+	bus.AddHandler(importVote)
+	bus.AddHandler(importComment)
 	bus.AddHandler(updatePost)
+	bus.AddHandler(regeneratePostsSearch)
+	bus.AddHandler(rebuildPostSignatures)
+	bus.AddHandler(backfillPostSignatures)
 	bus.AddHandler(getPostByID)
 	bus.AddHandler(getPostBySlug)
 	bus.AddHandler(getPostByNumber)
 	bus.AddHandler(searchPosts)
 	bus.AddHandler(findSimilarPosts)
+	bus.AddHandler(getPossibleDuplicates)
 	bus.AddHandler(getAllPosts)
 	bus.AddHandler(countPostPerStatus)
 	bus.AddHandler(markPostAsDuplicate)
+	bus.AddHandler(mergePosts)
 	bus.AddHandler(setPostResponse)
 	bus.AddHandler(postIsReferenced)
 
@@ -82,6 +108,11 @@ func (s Service) Init() {
 	bus.AddHandler(getCommentByID)
 	bus.AddHandler(getCommentsByPost)
 
+	bus.AddHandler(listPostRevisions)
+	bus.AddHandler(getPostRevision)
+	bus.AddHandler(listCommentRevisions)
+	bus.AddHandler(getCommentRevision)
+
 	bus.AddHandler(countUsers)
 	bus.AddHandler(blockUser)
 	bus.AddHandler(unblockUser)
@@ -89,10 +120,15 @@ func (s Service) Init() {
 	bus.AddHandler(regenerateAPIKey)
 	bus.AddHandler(userSubscribedTo)
 	bus.AddHandler(deleteCurrentUser)
+	bus.AddHandler(deleteUser)
+	bus.AddHandler(changeUserName)
 	bus.AddHandler(changeUserEmail)
 	bus.AddHandler(changeUserRole)
 	bus.AddHandler(updateCurrentUserSettings)
 	bus.AddHandler(getCurrentUserSettings)
+	bus.AddHandler(setUserAttributes)
+	bus.AddHandler(getUserAttributes)
+	bus.AddHandler(getUserProviderUIDs)
 	bus.AddHandler(registerUser)
 	bus.AddHandler(registerUserProvider)
 	bus.AddHandler(updateCurrentUser)
@@ -108,12 +144,18 @@ func (s Service) Init() {
 	bus.AddHandler(createTenant)
 	bus.AddHandler(getFirstTenant)
 	bus.AddHandler(getTenantByDomain)
+	bus.AddHandler(getTenantByID)
+	bus.AddHandler(getActiveTenants)
 	bus.AddHandler(activateTenant)
+	bus.AddHandler(disableTenant)
+	bus.AddHandler(getAllTenants)
+	bus.AddHandler(getPendingVerifications)
 	bus.AddHandler(isSubdomainAvailable)
 	bus.AddHandler(isCNAMEAvailable)
 	bus.AddHandler(updateTenantSettings)
 	bus.AddHandler(updateTenantPrivacySettings)
 	bus.AddHandler(updateTenantEmailAuthAllowedSettings)
+	bus.AddHandler(updateTenantVotingSettings)
 	bus.AddHandler(updateTenantAdvancedSettings)
 	bus.AddHandler(scheduleTenantDeletion)
 	bus.AddHandler(cancelTenantDeletion)
@@ -137,6 +179,19 @@ func (s Service) Init() {
 	bus.AddHandler(saveCustomOAuthConfig)
 	bus.AddHandler(getTenantProviderStatus)
 	bus.AddHandler(setTenantProviderStatus)
+	bus.AddHandler(resetOAuthConfig)
+	bus.AddHandler(getSAMLConfig)
+	bus.AddHandler(saveSAMLConfig)
+
+	bus.AddHandler(regenerateSCIMToken)
+	bus.AddHandler(verifySCIMToken)
+	bus.AddHandler(getSCIMTokenInfo)
+
+	bus.AddHandler(createAPIToken)
+	bus.AddHandler(revokeAPIToken)
+	bus.AddHandler(markAPITokenAsUsed)
+	bus.AddHandler(getUserAPITokens)
+	bus.AddHandler(getUserByAPIToken)
 
 	bus.AddHandler(getWebhook)
 	bus.AddHandler(listAllWebhooks)
@@ -145,6 +200,13 @@ func (s Service) Init() {
 	bus.AddHandler(createEditWebhook)
 	bus.AddHandler(deleteWebhook)
 	bus.AddHandler(markWebhookAsFailed)
+	bus.AddHandler(setWebhookSecret)
+	bus.AddHandler(addWebhookDelivery)
+	bus.AddHandler(updateWebhookDelivery)
+	bus.AddHandler(getWebhookDelivery)
+	bus.AddHandler(listWebhookDeliveries)
+	bus.AddHandler(listPendingWebhookDeliveries)
+	bus.AddHandler(purgeExpiredWebhookDeliveries)
 
 	bus.AddHandler(activateBillingSubscription)
 	bus.AddHandler(cancelBillingSubscription)
@@ -0,0 +1,177 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/services/sqlstore/dbEntities"
+)
+
+// Revisions are only stored once the content is edited,
+// so content that was never edited is returned as its first revision
+const postRevisionsQuery = `
+	WITH revisions AS (
+		SELECT version, title, description AS content, created_at, created_by_id
+		FROM post_revisions
+		WHERE post_id = $1 AND tenant_id = $2
+		UNION ALL
+		SELECT 1, title, COALESCE(description, ''), created_at, user_id
+		FROM posts p
+		WHERE id = $1 AND tenant_id = $2
+		AND NOT EXISTS (SELECT 1 FROM post_revisions r WHERE r.post_id = p.id AND r.tenant_id = p.tenant_id)
+	)
+	SELECT r.version, r.title, r.content, r.created_at,
+		u.id AS created_by_id,
+		u.name AS created_by_name,
+		u.email AS created_by_email,
+		u.role AS created_by_role,
+		u.status AS created_by_status,
+		u.avatar_type AS created_by_avatar_type,
+		u.avatar_bkey AS created_by_avatar_bkey
+	FROM revisions r
+	INNER JOIN users u
+	ON u.id = r.created_by_id
+	AND u.tenant_id = $2
+	%s
+	ORDER BY r.version`
+
+const commentRevisionsQuery = `
+	WITH revisions AS (
+		SELECT version, content, created_at, created_by_id
+		FROM comment_revisions
+		WHERE comment_id = $1 AND tenant_id = $2
+		UNION ALL
+		SELECT 1, content, COALESCE(edited_at, created_at), COALESCE(edited_by_id, user_id)
+		FROM comments c
+		WHERE id = $1 AND tenant_id = $2
+		AND NOT EXISTS (SELECT 1 FROM comment_revisions r WHERE r.comment_id = c.id AND r.tenant_id = c.tenant_id)
+	)
+	SELECT r.version, r.content, r.created_at,
+		u.id AS created_by_id,
+		u.name AS created_by_name,
+		u.email AS created_by_email,
+		u.role AS created_by_role,
+		u.status AS created_by_status,
+		u.avatar_type AS created_by_avatar_type,
+		u.avatar_bkey AS created_by_avatar_bkey
+	FROM revisions r
+	INNER JOIN users u
+	ON u.id = r.created_by_id
+	AND u.tenant_id = $2
+	%s
+	ORDER BY r.version`
+
+func listPostRevisions(ctx context.Context, q *query.ListPostRevisions) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		revisions := []*dbEntities.Revision{}
+		err := trx.Select(&revisions, fmt.Sprintf(postRevisionsQuery, ""), q.PostID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get revisions of post '%d'", q.PostID)
+		}
+
+		q.Result = make([]*entity.Revision, len(revisions))
+		for i, revision := range revisions {
+			q.Result[i] = revision.ToModel(ctx)
+		}
+		return nil
+	})
+}
+
+func getPostRevision(ctx context.Context, q *query.GetPostRevision) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		revision := dbEntities.Revision{}
+		err := trx.Get(&revision, fmt.Sprintf(postRevisionsQuery, "WHERE r.version = $3"), q.PostID, tenant.ID, q.Version)
+		if err != nil {
+			return errors.Wrap(err, "failed to get revision '%d' of post '%d'", q.Version, q.PostID)
+		}
+
+		q.Result = revision.ToModel(ctx)
+		return nil
+	})
+}
+
+func listCommentRevisions(ctx context.Context, q *query.ListCommentRevisions) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		revisions := []*dbEntities.Revision{}
+		err := trx.Select(&revisions, fmt.Sprintf(commentRevisionsQuery, ""), q.CommentID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get revisions of comment '%d'", q.CommentID)
+		}
+
+		q.Result = make([]*entity.Revision, len(revisions))
+		for i, revision := range revisions {
+			q.Result[i] = revision.ToModel(ctx)
+		}
+		return nil
+	})
+}
+
+func getCommentRevision(ctx context.Context, q *query.GetCommentRevision) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		revision := dbEntities.Revision{}
+		err := trx.Get(&revision, fmt.Sprintf(commentRevisionsQuery, "WHERE r.version = $3"), q.CommentID, tenant.ID, q.Version)
+		if err != nil {
+			return errors.Wrap(err, "failed to get revision '%d' of comment '%d'", q.Version, q.CommentID)
+		}
+
+		q.Result = revision.ToModel(ctx)
+		return nil
+	})
+}
+
+// addPostRevision stores the new content of a post as a revision.
+// On the first edit, the current content is stored first as the original revision
+func addPostRevision(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User, postID int, title, description string) error {
+	_, err := trx.Execute(`
+		INSERT INTO post_revisions (tenant_id, post_id, version, title, description, created_at, created_by_id)
+		SELECT tenant_id, id, 1, title, COALESCE(description, ''), created_at, user_id
+		FROM posts p
+		WHERE id = $1 AND tenant_id = $2
+		AND NOT EXISTS (SELECT 1 FROM post_revisions r WHERE r.post_id = p.id AND r.tenant_id = p.tenant_id)
+	`, postID, tenant.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to add original revision of post '%d'", postID)
+	}
+
+	_, err = trx.Execute(`
+		INSERT INTO post_revisions (tenant_id, post_id, version, title, description, created_at, created_by_id)
+		SELECT $1, $2, MAX(version) + 1, $3, $4, $5, $6
+		FROM post_revisions
+		WHERE post_id = $2 AND tenant_id = $1
+	`, tenant.ID, postID, title, description, time.Now(), user.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to add revision of post '%d'", postID)
+	}
+	return nil
+}
+
+// addCommentRevision stores the new content of a comment as a revision.
+// On the first edit, the current content is stored first as the original revision
+func addCommentRevision(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User, commentID int, content string) error {
+	_, err := trx.Execute(`
+		INSERT INTO comment_revisions (tenant_id, comment_id, version, content, created_at, created_by_id)
+		SELECT tenant_id, id, 1, content, COALESCE(edited_at, created_at), COALESCE(edited_by_id, user_id)
+		FROM comments c
+		WHERE id = $1 AND tenant_id = $2
+		AND NOT EXISTS (SELECT 1 FROM comment_revisions r WHERE r.comment_id = c.id AND r.tenant_id = c.tenant_id)
+	`, commentID, tenant.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to add original revision of comment '%d'", commentID)
+	}
+
+	_, err = trx.Execute(`
+		INSERT INTO comment_revisions (tenant_id, comment_id, version, content, created_at, created_by_id)
+		SELECT $1, $2, MAX(version) + 1, $3, $4, $5
+		FROM comment_revisions
+		WHERE comment_id = $2 AND tenant_id = $1
+	`, tenant.ID, commentID, content, time.Now(), user.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to add revision of comment '%d'", commentID)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+)
+
+func TestRevisionStorage_PostWithoutEdits(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	newPost := &cmd.AddNewPost{Title: "My new post", Description: "with this description"}
+	err := bus.Dispatch(aryaStarkCtx, newPost)
+	Expect(err).IsNil()
+
+	listRevisions := &query.ListPostRevisions{PostID: newPost.Result.ID}
+	err = bus.Dispatch(jonSnowCtx, listRevisions)
+	Expect(err).IsNil()
+	Expect(listRevisions.Result).HasLen(1)
+	Expect(listRevisions.Result[0].Version).Equals(1)
+	Expect(listRevisions.Result[0].Title).Equals("My new post")
+	Expect(listRevisions.Result[0].Content).Equals("with this description")
+	Expect(listRevisions.Result[0].CreatedBy.ID).Equals(aryaStark.ID)
+}
+
+func TestRevisionStorage_PostEdits(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	newPost := &cmd.AddNewPost{Title: "My new post", Description: "with this description"}
+	err := bus.Dispatch(aryaStarkCtx, newPost)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(aryaStarkCtx, &cmd.UpdatePost{Post: newPost.Result, Title: "The new title", Description: "With the new description"})
+	Expect(err).IsNil()
+
+	getPost := &query.GetPostByID{PostID: newPost.Result.ID}
+	err = bus.Dispatch(jonSnowCtx, getPost)
+	Expect(err).IsNil()
+
+	// Saving without changes doesn't create a revision
+	err = bus.Dispatch(jonSnowCtx, &cmd.UpdatePost{Post: getPost.Result, Title: "The new title", Description: "With the new description"})
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.UpdatePost{Post: getPost.Result, Title: "The new title", Description: "Edited by staff"})
+	Expect(err).IsNil()
+
+	listRevisions := &query.ListPostRevisions{PostID: newPost.Result.ID}
+	err = bus.Dispatch(jonSnowCtx, listRevisions)
+	Expect(err).IsNil()
+	Expect(listRevisions.Result).HasLen(3)
+	Expect(listRevisions.Result[0].Title).Equals("My new post")
+	Expect(listRevisions.Result[0].CreatedBy.ID).Equals(aryaStark.ID)
+	Expect(listRevisions.Result[1].Version).Equals(2)
+	Expect(listRevisions.Result[1].Title).Equals("The new title")
+	Expect(listRevisions.Result[1].CreatedBy.ID).Equals(aryaStark.ID)
+	Expect(listRevisions.Result[2].Version).Equals(3)
+	Expect(listRevisions.Result[2].Content).Equals("Edited by staff")
+	Expect(listRevisions.Result[2].CreatedBy.ID).Equals(jonSnow.ID)
+
+	getRevision := &query.GetPostRevision{PostID: newPost.Result.ID, Version: 2}
+	err = bus.Dispatch(jonSnowCtx, getRevision)
+	Expect(err).IsNil()
+	Expect(getRevision.Result.Content).Equals("With the new description")
+
+	getRevision = &query.GetPostRevision{PostID: newPost.Result.ID, Version: 4}
+	err = bus.Dispatch(jonSnowCtx, getRevision)
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+}
+
+func TestRevisionStorage_CommentEdits(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	newPost := &cmd.AddNewPost{Title: "My new post", Description: "with this description"}
+	err := bus.Dispatch(jonSnowCtx, newPost)
+	Expect(err).IsNil()
+
+	addNewComment := &cmd.AddNewComment{Post: newPost.Result, Content: "Comment #1"}
+	err = bus.Dispatch(aryaStarkCtx, addNewComment)
+	Expect(err).IsNil()
+
+	listRevisions := &query.ListCommentRevisions{CommentID: addNewComment.Result.ID}
+	err = bus.Dispatch(jonSnowCtx, listRevisions)
+	Expect(err).IsNil()
+	Expect(listRevisions.Result).HasLen(1)
+	Expect(listRevisions.Result[0].Content).Equals("Comment #1")
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.UpdateComment{CommentID: addNewComment.Result.ID, Content: "Comment #1 with edit"})
+	Expect(err).IsNil()
+
+	listRevisions = &query.ListCommentRevisions{CommentID: addNewComment.Result.ID}
+	err = bus.Dispatch(jonSnowCtx, listRevisions)
+	Expect(err).IsNil()
+	Expect(listRevisions.Result).HasLen(2)
+	Expect(listRevisions.Result[0].Content).Equals("Comment #1")
+	Expect(listRevisions.Result[0].CreatedBy.ID).Equals(aryaStark.ID)
+	Expect(listRevisions.Result[1].Content).Equals("Comment #1 with edit")
+	Expect(listRevisions.Result[1].CreatedBy.ID).Equals(jonSnow.ID)
+
+	getRevision := &query.GetCommentRevision{CommentID: addNewComment.Result.ID, Version: 1}
+	err = bus.Dispatch(jonSnowCtx, getRevision)
+	Expect(err).IsNil()
+	Expect(getRevision.Result.Content).Equals("Comment #1")
+}
@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/services/sqlstore/dbEntities"
+)
+
+func getSAMLConfig(ctx context.Context, q *query.GetSAMLConfig) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		if tenant == nil {
+			return app.ErrNotFound
+		}
+
+		config := &dbEntities.SAMLConfig{}
+		err := trx.Get(config, `
+		SELECT status, display_name, idp_entity_id, idp_sso_url, idp_certificate,
+					 name_attribute, email_attribute, roles_attribute, allowed_roles, is_trusted
+		FROM saml_configs
+		WHERE tenant_id = $1
+		`, tenant.ID)
+		if err != nil {
+			return err
+		}
+
+		q.Result = config.ToModel()
+		return nil
+	})
+}
+
+func saveSAMLConfig(ctx context.Context, c *cmd.SaveSAMLConfig) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		_, err := trx.Execute(`
+			INSERT INTO saml_configs (
+				tenant_id, status, display_name, idp_entity_id, idp_sso_url, idp_certificate,
+				name_attribute, email_attribute, roles_attribute, allowed_roles, is_trusted, created_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			ON CONFLICT (tenant_id) DO UPDATE
+			SET status = $2, display_name = $3, idp_entity_id = $4, idp_sso_url = $5, idp_certificate = $6,
+					name_attribute = $7, email_attribute = $8, roles_attribute = $9, allowed_roles = $10, is_trusted = $11
+		`, tenant.ID, c.Status, c.DisplayName, c.IdPEntityID, c.IdPSSOURL, c.IdPCertificate,
+			c.NameAttribute, c.EmailAttribute, c.RolesAttribute, c.AllowedRoles, c.IsTrusted, time.Now())
+		if err != nil {
+			return errors.Wrap(err, "failed to save SAML config")
+		}
+		return nil
+	})
+}
@@ -0,0 +1,59 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+)
+
+func TestSAMLConfig_SaveAndGet(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	getConfig := &query.GetSAMLConfig{}
+	err := bus.Dispatch(demoTenantCtx, getConfig)
+	Expect(err).Equals(app.ErrNotFound)
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.SaveSAMLConfig{
+		Status:         enum.OAuthConfigEnabled,
+		DisplayName:    "Okta",
+		IdPEntityID:    "https://idp.example.com",
+		IdPSSOURL:      "https://idp.example.com/sso",
+		IdPCertificate: "MIIC...",
+		EmailAttribute: "email",
+	})
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.SaveSAMLConfig{
+		Status:         enum.OAuthConfigDisabled,
+		DisplayName:    "Company SSO",
+		IdPEntityID:    "https://idp.example.com",
+		IdPSSOURL:      "https://idp.example.com/sso",
+		IdPCertificate: "MIIC...",
+		NameAttribute:  "cn",
+		RolesAttribute: "groups",
+		AllowedRoles:   "staff",
+		IsTrusted:      true,
+	})
+	Expect(err).IsNil()
+
+	getConfig = &query.GetSAMLConfig{}
+	err = bus.Dispatch(demoTenantCtx, getConfig)
+	Expect(err).IsNil()
+	Expect(getConfig.Result.Status).Equals(enum.OAuthConfigDisabled)
+	Expect(getConfig.Result.DisplayName).Equals("Company SSO")
+	Expect(getConfig.Result.EmailAttribute).Equals("")
+	Expect(getConfig.Result.NameAttribute).Equals("cn")
+	Expect(getConfig.Result.RolesAttribute).Equals("groups")
+	Expect(getConfig.Result.AllowedRoles).Equals("staff")
+	Expect(getConfig.Result.IsTrusted).IsTrue()
+
+	getConfig = &query.GetSAMLConfig{}
+	err = bus.Dispatch(avengersTenantCtx, getConfig)
+	Expect(err).Equals(app.ErrNotFound)
+}
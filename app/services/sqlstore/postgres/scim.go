@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/crypto"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/rand"
+)
+
+func regenerateSCIMToken(ctx context.Context, c *cmd.RegenerateSCIMToken) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		token := rand.String(64)
+
+		// Only the hash is stored, the token is shown once to the administrator
+		if _, err := trx.Execute(`
+			INSERT INTO scim_tokens (tenant_id, token_hash, created_at)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (tenant_id) DO UPDATE SET token_hash = $2, created_at = $3
+		`, tenant.ID, crypto.SHA512(token), time.Now()); err != nil {
+			return errors.Wrap(err, "failed to regenerate SCIM token")
+		}
+
+		c.Result = token
+		return nil
+	})
+}
+
+func verifySCIMToken(ctx context.Context, q *query.VerifySCIMToken) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		q.Result = false
+		if tenant == nil || q.Token == "" {
+			return nil
+		}
+
+		exists, err := trx.Exists(
+			"SELECT 1 FROM scim_tokens WHERE tenant_id = $1 AND token_hash = $2",
+			tenant.ID, crypto.SHA512(q.Token),
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to verify SCIM token")
+		}
+
+		q.Result = exists
+		return nil
+	})
+}
+
+func getSCIMTokenInfo(ctx context.Context, q *query.GetSCIMTokenInfo) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		var createdAt time.Time
+		err := trx.Scalar(&createdAt, "SELECT created_at FROM scim_tokens WHERE tenant_id = $1", tenant.ID)
+		if err != nil {
+			if errors.Cause(err) == app.ErrNotFound {
+				return nil
+			}
+			return errors.Wrap(err, "failed to get SCIM token")
+		}
+
+		q.Result = &createdAt
+		return nil
+	})
+}
@@ -0,0 +1,55 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+)
+
+func TestSCIMToken_RegenerateAndVerify(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	getInfo := &query.GetSCIMTokenInfo{}
+	err := bus.Dispatch(demoTenantCtx, getInfo)
+	Expect(err).IsNil()
+	Expect(getInfo.Result).IsNil()
+
+	verify := &query.VerifySCIMToken{Token: ""}
+	err = bus.Dispatch(demoTenantCtx, verify)
+	Expect(err).IsNil()
+	Expect(verify.Result).IsFalse()
+
+	regenerate := &cmd.RegenerateSCIMToken{}
+	err = bus.Dispatch(jonSnowCtx, regenerate)
+	Expect(err).IsNil()
+	Expect(regenerate.Result).HasLen(64)
+	first := regenerate.Result
+
+	regenerate = &cmd.RegenerateSCIMToken{}
+	err = bus.Dispatch(jonSnowCtx, regenerate)
+	Expect(err).IsNil()
+
+	verify = &query.VerifySCIMToken{Token: regenerate.Result}
+	err = bus.Dispatch(demoTenantCtx, verify)
+	Expect(err).IsNil()
+	Expect(verify.Result).IsTrue()
+
+	verify = &query.VerifySCIMToken{Token: first}
+	err = bus.Dispatch(demoTenantCtx, verify)
+	Expect(err).IsNil()
+	Expect(verify.Result).IsFalse()
+
+	verify = &query.VerifySCIMToken{Token: regenerate.Result}
+	err = bus.Dispatch(avengersTenantCtx, verify)
+	Expect(err).IsNil()
+	Expect(verify.Result).IsFalse()
+
+	getInfo = &query.GetSCIMTokenInfo{}
+	err = bus.Dispatch(demoTenantCtx, getInfo)
+	Expect(err).IsNil()
+	Expect(getInfo.Result).IsNotNil()
+}
@@ -0,0 +1,262 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/env"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/similarity"
+	"github.com/getfider/fider/app/services/sqlstore/dbEntities"
+	"github.com/lib/pq"
+)
+
+const (
+	// minSimilarPostSimilarity is how similar a post must be to a query to be suggested as similar
+	minSimilarPostSimilarity = 0.3
+	// maxPossibleDuplicates is the maximum number of pairs listed on the possible duplicates report
+	maxPossibleDuplicates = 100
+)
+
+// sqlMinHashSimilarity is the SQL equivalent of similarity.Signature.Similarity, so that candidates are scored without loading their signatures
+const sqlMinHashSimilarity = "(SELECT COUNT(*) FROM generate_subscripts(%[1]s, 1) AS g(i) WHERE (%[1]s)[i] = (%[2]s)[i])::float / %[3]d"
+
+type dbSimilarPost struct {
+	PostID     int     `db:"post_id"`
+	Similarity float64 `db:"similarity"`
+}
+
+type dbPossibleDuplicate struct {
+	PostID      int     `db:"post_id"`
+	DuplicateID int     `db:"duplicate_id"`
+	Similarity  float64 `db:"similarity"`
+}
+
+// savePostSignature updates the similarity index of a post, which must be called whenever its title or description change.
+// Posts without meaningful words get an empty signature, so that they are never similar to anything nor backfilled again
+func savePostSignature(trx *dbx.Trx, tenant *entity.Tenant, postID int, title, description string) error {
+	signature := similarity.Compute(title+" "+description, env.SearchNoiseWords())
+	if signature == nil {
+		signature = similarity.Signature{}
+	}
+
+	_, err := trx.Execute(`
+		INSERT INTO post_signatures (tenant_id, post_id, minhash, bands, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (tenant_id, post_id) DO UPDATE
+		SET minhash = EXCLUDED.minhash, bands = EXCLUDED.bands, updated_at = EXCLUDED.updated_at
+	`, tenant.ID, postID, pq.Array([]int64(signature)), pq.Array(signature.Bands()), time.Now())
+	if err != nil {
+		return errors.Wrap(err, "failed to save signature of post with id '%d'", postID)
+	}
+	return nil
+}
+
+type dbPostText struct {
+	TenantID    int    `db:"tenant_id"`
+	ID          int    `db:"id"`
+	Title       string `db:"title"`
+	Description string `db:"description"`
+}
+
+func rebuildPostSignatures(ctx context.Context, c *cmd.RebuildPostSignatures) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		posts := []*dbPostText{}
+		if err := trx.Select(&posts, "SELECT tenant_id, id, title, description FROM posts WHERE tenant_id = $1 ORDER BY id", tenant.ID); err != nil {
+			return errors.Wrap(err, "failed to get posts to rebuild signatures")
+		}
+
+		for _, post := range posts {
+			if err := savePostSignature(trx, tenant, post.ID, post.Title, post.Description); err != nil {
+				return err
+			}
+		}
+
+		c.Result = len(posts)
+		return nil
+	})
+}
+
+func backfillPostSignatures(ctx context.Context, c *cmd.BackfillPostSignatures) error {
+	return using(ctx, func(trx *dbx.Trx, _ *entity.Tenant, _ *entity.User) error {
+		posts := []*dbPostText{}
+		err := trx.Select(&posts, `
+			SELECT p.tenant_id, p.id, p.title, p.description
+			FROM posts p
+			WHERE NOT EXISTS (SELECT 1 FROM post_signatures s WHERE s.post_id = p.id AND s.tenant_id = p.tenant_id)
+			ORDER BY p.tenant_id, p.id
+			LIMIT $1
+		`, c.Limit)
+		if err != nil {
+			return errors.Wrap(err, "failed to get posts without signature")
+		}
+
+		for _, post := range posts {
+			if err := savePostSignature(trx, &entity.Tenant{ID: post.TenantID}, post.ID, post.Title, post.Description); err != nil {
+				return err
+			}
+		}
+
+		c.Result = len(posts)
+		return nil
+	})
+}
+
+// similarPostStatuses returns the statuses of posts that can be suggested as similar or duplicates
+func similarPostStatuses(trx *dbx.Trx, tenant *entity.Tenant) ([]enum.PostStatus, error) {
+	statuses := []enum.PostStatus{
+		enum.PostOpen,
+		enum.PostStarted,
+		enum.PostPlanned,
+		enum.PostCompleted,
+		enum.PostDeclined,
+	}
+	customStatuses, err := queryCustomPostStatuses(trx, tenant)
+	if err != nil {
+		return nil, err
+	}
+	return appendCustomStatuses(statuses, customStatuses, func(status *entity.CustomPostStatus) bool {
+		return true
+	}), nil
+}
+
+// findIndexedSimilarPostIDs returns the ids of the posts most similar to text according to the similarity index, most similar first
+func findIndexedSimilarPostIDs(trx *dbx.Trx, tenant *entity.Tenant, statuses []enum.PostStatus, text string, limit int) ([]int, error) {
+	signature := similarity.Compute(text, env.SearchNoiseWords())
+	if signature == nil {
+		return []int{}, nil
+	}
+
+	similarPosts := []*dbSimilarPost{}
+	err := trx.Select(&similarPosts, fmt.Sprintf(`
+		SELECT post_id, similarity
+		FROM (
+			SELECT s.post_id, %s AS similarity
+			FROM post_signatures s
+			INNER JOIN posts p
+			ON p.id = s.post_id
+			AND p.tenant_id = s.tenant_id
+			WHERE s.tenant_id = $1 AND s.bands && $2 AND p.status = ANY($3)
+		) AS candidates
+		WHERE similarity >= $5
+		ORDER BY similarity DESC, post_id DESC
+		LIMIT $6
+	`, fmt.Sprintf(sqlMinHashSimilarity, "s.minhash", "$4::bigint[]", similarity.SignatureSize)),
+		tenant.ID, pq.Array(signature.Bands()), pq.Array(statuses), pq.Array([]int64(signature)), minSimilarPostSimilarity, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get similar post candidates")
+	}
+
+	ids := make([]int, 0, len(similarPosts))
+	for _, similarPost := range similarPosts {
+		ids = append(ids, similarPost.PostID)
+	}
+	return ids, nil
+}
+
+// getPostsByIDs returns the posts with given ids that are visible to user and have one of given statuses, in the same order as ids
+func getPostsByIDs(ctx context.Context, trx *dbx.Trx, tenant *entity.Tenant, user *entity.User, statuses []enum.PostStatus, ids []int) ([]*entity.Post, error) {
+	if len(ids) == 0 {
+		return []*entity.Post{}, nil
+	}
+
+	innerQuery := buildPostQuery(user, "p.tenant_id = $1 AND p.status = ANY($2)", "")
+	posts := []*dbEntities.Post{}
+	err := trx.Select(&posts, fmt.Sprintf("SELECT * FROM (%s) AS q WHERE q.id = ANY($3)", innerQuery), tenant.ID, pq.Array(statuses), pq.Array(ids))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get posts by ids")
+	}
+
+	byID := make(map[int]*entity.Post, len(posts))
+	for _, post := range posts {
+		byID[post.ID] = post.ToModel(ctx)
+	}
+
+	result := make([]*entity.Post, 0, len(posts))
+	for _, id := range ids {
+		if post, ok := byID[id]; ok {
+			result = append(result, post)
+		}
+	}
+	return result, nil
+}
+
+func getPossibleDuplicates(ctx context.Context, q *query.GetPossibleDuplicates) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		statuses, err := similarPostStatuses(trx, tenant)
+		if err != nil {
+			return err
+		}
+
+		// Only posts sharing at least one band are compared, which avoids comparing every pair of posts
+		pairs := []*dbPossibleDuplicate{}
+		err = trx.Select(&pairs, fmt.Sprintf(`
+			WITH bands AS (
+				SELECT s.post_id, UNNEST(s.bands) AS band
+				FROM post_signatures s
+				INNER JOIN posts p
+				ON p.id = s.post_id
+				AND p.tenant_id = s.tenant_id
+				WHERE s.tenant_id = $1 AND p.status = ANY($2) AND p.is_approved = true
+			), pairs AS (
+				SELECT DISTINCT a.post_id, b.post_id AS duplicate_id
+				FROM bands a
+				INNER JOIN bands b
+				ON b.band = a.band
+				AND b.post_id > a.post_id
+			), scored AS (
+				SELECT pairs.post_id, pairs.duplicate_id, %s AS similarity
+				FROM pairs
+				INNER JOIN post_signatures sa
+				ON sa.post_id = pairs.post_id
+				AND sa.tenant_id = $1
+				INNER JOIN post_signatures sb
+				ON sb.post_id = pairs.duplicate_id
+				AND sb.tenant_id = $1
+			)
+			SELECT post_id, duplicate_id, similarity
+			FROM scored
+			WHERE similarity >= $3
+			ORDER BY similarity DESC, duplicate_id DESC, post_id DESC
+			LIMIT $4
+		`, fmt.Sprintf(sqlMinHashSimilarity, "sa.minhash", "sb.minhash", similarity.SignatureSize)),
+			tenant.ID, pq.Array(statuses), q.MinSimilarity, maxPossibleDuplicates)
+		if err != nil {
+			return errors.Wrap(err, "failed to get possible duplicates")
+		}
+
+		ids := make([]int, 0, len(pairs)*2)
+		for _, pair := range pairs {
+			ids = append(ids, pair.PostID, pair.DuplicateID)
+		}
+
+		posts, err := getPostsByIDs(ctx, trx, tenant, user, statuses, ids)
+		if err != nil {
+			return err
+		}
+
+		byID := make(map[int]*entity.Post, len(posts))
+		for _, post := range posts {
+			byID[post.ID] = post
+		}
+
+		q.Result = make([]*entity.PossibleDuplicate, 0, len(pairs))
+		for _, pair := range pairs {
+			post, duplicate := byID[pair.PostID], byID[pair.DuplicateID]
+			if post != nil && duplicate != nil {
+				q.Result = append(q.Result, &entity.PossibleDuplicate{
+					Post:       post,
+					Duplicate:  duplicate,
+					Similarity: pair.Similarity,
+				})
+			}
+		}
+		return nil
+	})
+}
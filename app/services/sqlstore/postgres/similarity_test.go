@@ -0,0 +1,66 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+)
+
+func TestSimilarity_FindSimilarPosts_Paraphrase(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	exportPost := &cmd.AddNewPost{Title: "Export posts to CSV files", Description: "We need exports for our reports"}
+	err := bus.Dispatch(jonSnowCtx, exportPost)
+	Expect(err).IsNil()
+
+	otherPost := &cmd.AddNewPost{Title: "Sign in with Microsoft", Description: "Our company uses Microsoft accounts"}
+	err = bus.Dispatch(jonSnowCtx, otherPost)
+	Expect(err).IsNil()
+
+	similarPosts := &query.FindSimilarPosts{Query: "exporting a post to a CSV file"}
+	err = bus.Dispatch(demoTenantCtx, similarPosts)
+	Expect(err).IsNil()
+	Expect(similarPosts.Result).HasLen(1)
+	Expect(similarPosts.Result[0].ID).Equals(exportPost.Result.ID)
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.UpdatePost{Post: otherPost.Result, Title: "Export posts to Excel", Description: "Spreadsheets please"})
+	Expect(err).IsNil()
+
+	similarPosts = &query.FindSimilarPosts{Query: "exporting posts"}
+	err = bus.Dispatch(demoTenantCtx, similarPosts)
+	Expect(err).IsNil()
+	Expect(similarPosts.Result).HasLen(2)
+}
+
+func TestSimilarity_GetPossibleDuplicates(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	darkMode := &cmd.AddNewPost{Title: "Dark mode", Description: "Add a dark mode to the dashboard"}
+	err := bus.Dispatch(jonSnowCtx, darkMode)
+	Expect(err).IsNil()
+
+	darkTheme := &cmd.AddNewPost{Title: "Dashboard dark mode", Description: "Please support dark mode on the dashboard"}
+	err = bus.Dispatch(aryaStarkCtx, darkTheme)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(aryaStarkCtx, &cmd.AddNewPost{Title: "Sign in with Microsoft", Description: "Our company uses Microsoft accounts"})
+	Expect(err).IsNil()
+
+	duplicates := &query.GetPossibleDuplicates{MinSimilarity: 0.5}
+	err = bus.Dispatch(jonSnowCtx, duplicates)
+	Expect(err).IsNil()
+	Expect(duplicates.Result).HasLen(1)
+	Expect(duplicates.Result[0].Post.ID).Equals(darkMode.Result.ID)
+	Expect(duplicates.Result[0].Duplicate.ID).Equals(darkTheme.Result.ID)
+	Expect(duplicates.Result[0].Similarity >= 0.5).IsTrue()
+
+	duplicates = &query.GetPossibleDuplicates{MinSimilarity: 1}
+	err = bus.Dispatch(jonSnowCtx, duplicates)
+	Expect(err).IsNil()
+	Expect(duplicates.Result).HasLen(0)
+}
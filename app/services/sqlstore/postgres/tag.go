@@ -113,6 +113,11 @@ func deleteTag(ctx context.Context, c *cmd.DeleteTag) error {
 			return errors.Wrap(err, "failed to remove tag with id '%d' from all posts", c.Tag.ID)
 		}
 
+		_, err = trx.Execute(`DELETE FROM board_tags WHERE tag_id = $1 AND tenant_id = $2`, c.Tag.ID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to remove tag with id '%d' from all boards", c.Tag.ID)
+		}
+
 		_, err = trx.Execute(`DELETE FROM tags WHERE id = $1 AND tenant_id = $2`, c.Tag.ID, tenant.ID)
 		if err != nil {
 			return errors.Wrap(err, "failed to delete tag with id '%d'", c.Tag.ID)
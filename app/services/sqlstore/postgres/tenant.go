@@ -74,6 +74,19 @@ func updateTenantEmailAuthAllowedSettings(ctx context.Context, c *cmd.UpdateTena
 	})
 }
 
+func updateTenantVotingSettings(ctx context.Context, c *cmd.UpdateTenantVotingSettings) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		_, err := trx.Execute("UPDATE tenants SET vote_budget = $1, max_votes_per_post = $2 WHERE id = $3", c.VoteBudget, c.MaxVotesPerPost, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed update tenant voting settings")
+		}
+
+		tenant.VoteBudget = c.VoteBudget
+		tenant.MaxVotesPerPost = c.MaxVotesPerPost
+		return nil
+	})
+}
+
 func updateTenantSettings(ctx context.Context, c *cmd.UpdateTenantSettings) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
 		if c.Logo.Remove {
@@ -127,6 +140,17 @@ func activateTenant(ctx context.Context, c *cmd.ActivateTenant) error {
 	})
 }
 
+func disableTenant(ctx context.Context, c *cmd.DisableTenant) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		query := "UPDATE tenants SET status = $1 WHERE id = $2"
+		_, err := trx.Execute(query, enum.TenantDisabled, c.TenantID)
+		if err != nil {
+			return errors.Wrap(err, "failed to disable tenant with id '%d'", c.TenantID)
+		}
+		return nil
+	})
+}
+
 func getVerificationByKey(ctx context.Context, q *query.GetVerificationByKey) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
 		verification := dbEntities.EmailVerification{}
@@ -260,7 +284,7 @@ func getFirstTenant(ctx context.Context, q *query.GetFirstTenant) error {
 		tenant := dbEntities.Tenant{}
 
 	err := trx.Get(&tenant, `
-		SELECT t.id, t.name, t.subdomain, t.cname, t.invitation, t.locale, t.welcome_message, t.welcome_header, t.description_template, t.status, t.is_private, t.logo_bkey, t.custom_css, t.allowed_schemes, t.is_email_auth_allowed, t.is_feed_enabled, t.is_moderation_enabled, t.prevent_indexing, t.is_pro, t.scheduled_deletion_at,
+		SELECT t.id, t.name, t.subdomain, t.cname, t.invitation, t.locale, t.welcome_message, t.welcome_header, t.description_template, t.status, t.is_private, t.logo_bkey, t.custom_css, t.allowed_schemes, t.is_email_auth_allowed, t.is_feed_enabled, t.is_moderation_enabled, t.prevent_indexing, t.is_pro, t.scheduled_deletion_at, t.vote_budget, t.max_votes_per_post,
 			(b.paddle_subscription_id IS NOT NULL AND b.stripe_subscription_id IS NULL) AS has_paddle_subscription
 		FROM tenants t
 		LEFT JOIN tenants_billing b ON b.tenant_id = t.id
@@ -280,7 +304,7 @@ func getTenantByDomain(ctx context.Context, q *query.GetTenantByDomain) error {
 		tenant := dbEntities.Tenant{}
 
 	err := trx.Get(&tenant, `
-		SELECT t.id, t.name, t.subdomain, t.cname, t.invitation, t.locale, t.welcome_message, t.welcome_header, t.description_template, t.status, t.is_private, t.logo_bkey, t.custom_css, t.allowed_schemes, t.is_email_auth_allowed, t.is_feed_enabled, t.is_moderation_enabled, t.prevent_indexing, t.is_pro, t.scheduled_deletion_at,
+		SELECT t.id, t.name, t.subdomain, t.cname, t.invitation, t.locale, t.welcome_message, t.welcome_header, t.description_template, t.status, t.is_private, t.logo_bkey, t.custom_css, t.allowed_schemes, t.is_email_auth_allowed, t.is_feed_enabled, t.is_moderation_enabled, t.prevent_indexing, t.is_pro, t.scheduled_deletion_at, t.vote_budget, t.max_votes_per_post,
 			(b.paddle_subscription_id IS NOT NULL AND b.stripe_subscription_id IS NULL) AS has_paddle_subscription
 		FROM tenants t
 		LEFT JOIN tenants_billing b ON b.tenant_id = t.id
@@ -296,6 +320,73 @@ func getTenantByDomain(ctx context.Context, q *query.GetTenantByDomain) error {
 	})
 }
 
+func getTenantByID(ctx context.Context, q *query.GetTenantByID) error {
+	return using(ctx, func(trx *dbx.Trx, _ *entity.Tenant, _ *entity.User) error {
+		tenant := dbEntities.Tenant{}
+
+		err := trx.Get(&tenant, `
+			SELECT t.id, t.name, t.subdomain, t.cname, t.invitation, t.locale, t.welcome_message, t.welcome_header, t.description_template, t.status, t.is_private, t.logo_bkey, t.custom_css, t.allowed_schemes, t.is_email_auth_allowed, t.is_feed_enabled, t.is_moderation_enabled, t.prevent_indexing, t.is_pro, t.scheduled_deletion_at, t.vote_budget, t.max_votes_per_post,
+				(b.paddle_subscription_id IS NOT NULL AND b.stripe_subscription_id IS NULL) AS has_paddle_subscription
+			FROM tenants t
+			LEFT JOIN tenants_billing b ON b.tenant_id = t.id
+			WHERE t.id = $1
+		`, q.TenantID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get tenant with id '%d'", q.TenantID)
+		}
+
+		q.Result = tenant.ToModel()
+		return nil
+	})
+}
+
+func getActiveTenants(ctx context.Context, q *query.GetActiveTenants) error {
+	return using(ctx, func(trx *dbx.Trx, _ *entity.Tenant, _ *entity.User) error {
+		tenants := []*dbEntities.Tenant{}
+
+		err := trx.Select(&tenants, `
+			SELECT t.id, t.name, t.subdomain, t.cname, t.invitation, t.locale, t.welcome_message, t.welcome_header, t.description_template, t.status, t.is_private, t.logo_bkey, t.custom_css, t.allowed_schemes, t.is_email_auth_allowed, t.is_feed_enabled, t.is_moderation_enabled, t.prevent_indexing, t.is_pro, t.scheduled_deletion_at, t.vote_budget, t.max_votes_per_post,
+				(b.paddle_subscription_id IS NOT NULL AND b.stripe_subscription_id IS NULL) AS has_paddle_subscription
+			FROM tenants t
+			LEFT JOIN tenants_billing b ON b.tenant_id = t.id
+			WHERE t.status = $1
+			ORDER BY t.id
+		`, enum.TenantActive)
+		if err != nil {
+			return errors.Wrap(err, "failed to get active tenants")
+		}
+
+		q.Result = make([]*entity.Tenant, len(tenants))
+		for i, tenant := range tenants {
+			q.Result[i] = tenant.ToModel()
+		}
+		return nil
+	})
+}
+
+func getAllTenants(ctx context.Context, q *query.GetAllTenants) error {
+	return using(ctx, func(trx *dbx.Trx, _ *entity.Tenant, _ *entity.User) error {
+		tenants := []*dbEntities.Tenant{}
+
+		err := trx.Select(&tenants, `
+			SELECT t.id, t.name, t.subdomain, t.cname, t.invitation, t.locale, t.welcome_message, t.welcome_header, t.description_template, t.status, t.is_private, t.logo_bkey, t.custom_css, t.allowed_schemes, t.is_email_auth_allowed, t.is_feed_enabled, t.is_moderation_enabled, t.prevent_indexing, t.is_pro, t.scheduled_deletion_at, t.vote_budget, t.max_votes_per_post,
+				(b.paddle_subscription_id IS NOT NULL AND b.stripe_subscription_id IS NULL) AS has_paddle_subscription
+			FROM tenants t
+			LEFT JOIN tenants_billing b ON b.tenant_id = t.id
+			ORDER BY t.id
+		`)
+		if err != nil {
+			return errors.Wrap(err, "failed to get all tenants")
+		}
+
+		q.Result = make([]*entity.Tenant, len(tenants))
+		for i, tenant := range tenants {
+			q.Result[i] = tenant.ToModel()
+		}
+		return nil
+	})
+}
+
 func getPendingSignUpVerification(ctx context.Context, q *query.GetPendingSignUpVerification) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
 		verification := dbEntities.EmailVerification{}
@@ -315,6 +406,27 @@ func getPendingSignUpVerification(ctx context.Context, q *query.GetPendingSignUp
 	})
 }
 
+func getPendingVerifications(ctx context.Context, q *query.GetPendingVerifications) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		verifications := []*dbEntities.EmailVerification{}
+
+		query := `SELECT id, email, name, key, code, created_at, verified_at, expires_at, kind, user_id, attempts
+		          FROM email_verifications
+		          WHERE tenant_id = $1 AND verified_at IS NULL AND expires_at > $2
+		          ORDER BY created_at`
+		err := trx.Select(&verifications, query, tenant.ID, time.Now())
+		if err != nil {
+			return errors.Wrap(err, "failed to get pending verifications for tenant '%d'", tenant.ID)
+		}
+
+		q.Result = make([]*entity.EmailVerification, len(verifications))
+		for i, verification := range verifications {
+			q.Result[i] = verification.ToModel()
+		}
+		return nil
+	})
+}
+
 func invalidatePreviousSignUpKeys(ctx context.Context, c *cmd.InvalidatePreviousSignUpKeys) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
 		query := `UPDATE email_verifications 
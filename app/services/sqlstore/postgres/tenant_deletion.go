@@ -117,19 +117,32 @@ var tenantScopedTables = []string{
 	"attachments",
 	"mention_notifications",
 	"notifications",
+	"email_digest_items",
 	"post_subscribers",
 	"post_votes",
 	"post_tags",
+	"board_tags",
+	"comment_revisions",
+	"post_revisions",
+	"post_signatures",
 	"comments",
 	"posts",
+	"boards",
 	"tags",
+	"post_statuses",
 	"email_verifications",
 	"user_providers",
 	"user_settings",
+	"user_attributes",
+	"api_tokens",
+	"webhook_deliveries",
 	"webhooks",
+	"worker_tasks",
 	"events",
 	"blobs",
 	"oauth_providers",
+	"saml_configs",
+	"scim_tokens",
 	"tenant_providers",
 	"users",
 	"tenants_billing",
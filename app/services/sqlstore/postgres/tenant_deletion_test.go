@@ -67,7 +67,7 @@ func TestTenantDeletion_DeletesEverythingForTenantOnly(t *testing.T) {
 		"users", "posts", "comments", "post_votes", "post_tags", "tags",
 		"post_subscribers", "notifications", "attachments", "user_settings",
 		"user_providers", "email_verifications", "events", "blobs", "webhooks",
-		"oauth_providers", "tenant_providers", "tenants_billing",
+		"oauth_providers", "saml_configs", "tenant_providers", "tenants_billing",
 	} {
 		Expect(countTenantRows(table, demoTenant.ID)).Equals(0)
 	}
@@ -6,6 +6,7 @@ import (
 	"github.com/getfider/fider/app"
 	"github.com/getfider/fider/app/models/cmd"
 	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
 	"github.com/getfider/fider/app/models/query"
 	"github.com/getfider/fider/app/pkg/dbx"
 	"github.com/getfider/fider/app/pkg/errors"
@@ -58,6 +59,29 @@ func getTenantProviderStatus(ctx context.Context, q *query.GetTenantProviderStat
 	})
 }
 
+func resetOAuthConfig(ctx context.Context, c *cmd.ResetOAuthConfig) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		disabled, err := trx.Execute(
+			"UPDATE oauth_providers SET status = $1 WHERE tenant_id = $2 AND status != $1",
+			enum.OAuthConfigDisabled, tenant.ID,
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to disable custom oauth providers")
+		}
+
+		if _, err := trx.Execute("DELETE FROM tenant_providers WHERE tenant_id = $1", tenant.ID); err != nil {
+			return errors.Wrap(err, "failed to reset tenant provider status")
+		}
+
+		if _, err := trx.Execute("UPDATE tenants SET is_email_auth_allowed = true WHERE id = $1", tenant.ID); err != nil {
+			return errors.Wrap(err, "failed to allow email auth")
+		}
+
+		c.Result = int(disabled)
+		return nil
+	})
+}
+
 func setTenantProviderStatus(ctx context.Context, c *cmd.SetTenantProviderStatus) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
 		if tenant == nil {
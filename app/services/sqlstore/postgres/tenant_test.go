@@ -51,6 +51,33 @@ func TestTenantStorage_Add_Activate(t *testing.T) {
 	Expect(getByDomain.Result.IsPrivate).IsFalse()
 }
 
+func TestTenantStorage_GetActiveTenants(t *testing.T) {
+	ctx := SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	getActive := &query.GetActiveTenants{}
+	err := bus.Dispatch(ctx, getActive)
+	Expect(err).IsNil()
+	count := len(getActive.Result)
+	Expect(getActive.Result[0].ID).Equals(demoTenant.ID)
+
+	createTenant := &cmd.CreateTenant{Name: "My Domain Inc.", Subdomain: "mydomain", Status: enum.TenantPending}
+	err = bus.Dispatch(ctx, createTenant)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(ctx, getActive)
+	Expect(err).IsNil()
+	Expect(getActive.Result).HasLen(count)
+
+	err = bus.Dispatch(ctx, &cmd.ActivateTenant{TenantID: createTenant.Result.ID})
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(ctx, getActive)
+	Expect(err).IsNil()
+	Expect(getActive.Result).HasLen(count + 1)
+	Expect(getActive.Result[count].Subdomain).Equals("mydomain")
+}
+
 func TestTenantStorage_SingleTenant_Add(t *testing.T) {
 	ctx := SetupDatabaseTest(t)
 	defer TeardownDatabaseTest()
@@ -438,3 +465,109 @@ func TestTenantStorage_Save_Get_ListOAuthConfig(t *testing.T) {
 	Expect(customConfigs.Result[0].JSONUserNamePath).Equals("New user.name")
 	Expect(customConfigs.Result[0].JSONUserEmailPath).Equals("New user.email")
 }
+
+func TestTenantStorage_Disable_GetAllTenants(t *testing.T) {
+	ctx := SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	getAll := &query.GetAllTenants{}
+	err := bus.Dispatch(ctx, getAll)
+	Expect(err).IsNil()
+	count := len(getAll.Result)
+
+	createTenant := &cmd.CreateTenant{Name: "My Domain Inc.", Subdomain: "mydomain", Status: enum.TenantActive}
+	err = bus.Dispatch(ctx, createTenant)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(ctx, &cmd.DisableTenant{TenantID: createTenant.Result.ID})
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(ctx, getAll)
+	Expect(err).IsNil()
+	Expect(getAll.Result).HasLen(count + 1)
+	Expect(getAll.Result[count].Subdomain).Equals("mydomain")
+	Expect(getAll.Result[count].Status).Equals(enum.TenantDisabled)
+}
+
+func TestTenantStorage_GetPendingVerifications(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	err := bus.Dispatch(demoTenantCtx, &cmd.SaveVerificationKey{
+		Key:      "pendingkey",
+		Duration: 15 * time.Minute,
+		Request:  &actions.CreateTenant{Email: "jon.snow@got.com", Name: "Jon Snow"},
+	})
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(demoTenantCtx, &cmd.SaveVerificationKey{
+		Key:      "verifiedkey",
+		Duration: 15 * time.Minute,
+		Request:  &actions.CreateTenant{Email: "arya.stark@got.com", Name: "Arya Stark"},
+	})
+	Expect(err).IsNil()
+	err = bus.Dispatch(demoTenantCtx, &cmd.SetKeyAsVerified{Key: "verifiedkey"})
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(demoTenantCtx, &cmd.SaveVerificationKey{
+		Key:      "expiredkey",
+		Duration: -1 * time.Minute,
+		Request:  &actions.CreateTenant{Email: "sansa.stark@got.com", Name: "Sansa Stark"},
+	})
+	Expect(err).IsNil()
+
+	getPending := &query.GetPendingVerifications{}
+	err = bus.Dispatch(demoTenantCtx, getPending)
+	Expect(err).IsNil()
+	Expect(getPending.Result).HasLen(1)
+	Expect(getPending.Result[0].Key).Equals("pendingkey")
+	Expect(getPending.Result[0].Email).Equals("jon.snow@got.com")
+
+	getPending = &query.GetPendingVerifications{}
+	err = bus.Dispatch(avengersTenantCtx, getPending)
+	Expect(err).IsNil()
+	Expect(getPending.Result).HasLen(0)
+}
+
+func TestTenantStorage_ResetOAuthConfig(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	err := bus.Dispatch(demoTenantCtx, &cmd.SaveCustomOAuthConfig{
+		Logo:         &dto.ImageUpload{},
+		Provider:     "_TEST",
+		DisplayName:  "My Provider",
+		Status:       enum.OAuthConfigEnabled,
+		ClientID:     "823187ahjjfdha8fds7yfdashfjkdsa",
+		ClientSecret: "jijads78d76cn347768x3t4668q275",
+		AuthorizeURL: "http://provider/oauth/authorize",
+		TokenURL:     "http://provider/oauth/token",
+	})
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(demoTenantCtx, &cmd.SetTenantProviderStatus{Provider: "_TEST", IsEnabled: false})
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(demoTenantCtx, &cmd.UpdateTenantEmailAuthAllowedSettings{IsEmailAuthAllowed: false})
+	Expect(err).IsNil()
+
+	resetOAuth := &cmd.ResetOAuthConfig{}
+	err = bus.Dispatch(demoTenantCtx, resetOAuth)
+	Expect(err).IsNil()
+	Expect(resetOAuth.Result).Equals(1)
+
+	getConfig := &query.GetCustomOAuthConfigByProvider{Provider: "_TEST"}
+	err = bus.Dispatch(demoTenantCtx, getConfig)
+	Expect(err).IsNil()
+	Expect(getConfig.Result.Status).Equals(enum.OAuthConfigDisabled)
+
+	getStatus := &query.GetTenantProviderStatus{Provider: "_TEST"}
+	err = bus.Dispatch(demoTenantCtx, getStatus)
+	Expect(err).IsNil()
+	Expect(getStatus.Result.IsEnabled).IsTrue()
+
+	getTenant := &query.GetTenantByDomain{Domain: "demo"}
+	err = bus.Dispatch(demoTenantCtx, getTenant)
+	Expect(err).IsNil()
+	Expect(getTenant.Result.IsEmailAuthAllowed).IsTrue()
+}
@@ -75,37 +75,50 @@ func untrustUser(ctx context.Context, c *cmd.UntrustUser) error {
 
 func deleteCurrentUser(ctx context.Context, c *cmd.DeleteCurrentUser) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		return deleteUserRecords(trx, tenant, user.ID)
+	})
+}
+
+func deleteUser(ctx context.Context, c *cmd.DeleteUser) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		return deleteUserRecords(trx, tenant, c.UserID)
+	})
+}
+
+// deleteUserRecords anonymizes given user and removes its personal records, while keeping its content
+func deleteUserRecords(trx *dbx.Trx, tenant *entity.Tenant, userID int) error {
+	if _, err := trx.Execute(
+		"UPDATE users SET role = $3, status = $4, name = '', email = '', api_key = null, api_key_date = null WHERE id = $1 AND tenant_id = $2",
+		userID, tenant.ID, enum.RoleVisitor, enum.UserDeleted,
+	); err != nil {
+		return errors.Wrap(err, "failed to delete user with id '%d'", userID)
+	}
+
+	var tables = []struct {
+		name       string
+		userColumn string
+	}{
+		{"user_providers", "user_id"},
+		{"user_settings", "user_id"},
+		{"user_attributes", "user_id"},
+		{"api_tokens", "user_id"},
+		{"notifications", "user_id"},
+		{"notifications", "author_id"},
+		{"post_votes", "user_id"},
+		{"post_subscribers", "user_id"},
+		{"email_verifications", "user_id"},
+	}
+
+	for _, table := range tables {
 		if _, err := trx.Execute(
-			"UPDATE users SET role = $3, status = $4, name = '', email = '', api_key = null, api_key_date = null WHERE id = $1 AND tenant_id = $2",
-			user.ID, tenant.ID, enum.RoleVisitor, enum.UserDeleted,
+			fmt.Sprintf("DELETE FROM %s WHERE %s = $1 AND tenant_id = $2", table.name, table.userColumn),
+			userID, tenant.ID,
 		); err != nil {
-			return errors.Wrap(err, "failed to delete current user")
-		}
-
-		var tables = []struct {
-			name       string
-			userColumn string
-		}{
-			{"user_providers", "user_id"},
-			{"user_settings", "user_id"},
-			{"notifications", "user_id"},
-			{"notifications", "author_id"},
-			{"post_votes", "user_id"},
-			{"post_subscribers", "user_id"},
-			{"email_verifications", "user_id"},
-		}
-
-		for _, table := range tables {
-			if _, err := trx.Execute(
-				fmt.Sprintf("DELETE FROM %s WHERE %s = $1 AND tenant_id = $2", table.name, table.userColumn),
-				user.ID, tenant.ID,
-			); err != nil {
-				return errors.Wrap(err, "failed to delete current user's %s records", table)
-			}
+			return errors.Wrap(err, "failed to delete %s records of user with id '%d'", table.name, userID)
 		}
+	}
 
-		return nil
-	})
+	return nil
 }
 
 func regenerateAPIKey(ctx context.Context, c *cmd.RegenerateAPIKey) error {
@@ -182,6 +195,17 @@ func changeUserRole(ctx context.Context, c *cmd.ChangeUserRole) error {
 	})
 }
 
+func changeUserName(ctx context.Context, c *cmd.ChangeUserName) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		cmd := "UPDATE users SET name = $3 WHERE id = $1 AND tenant_id = $2"
+		_, err := trx.Execute(cmd, c.UserID, tenant.ID, c.Name)
+		if err != nil {
+			return errors.Wrap(err, "failed to update user's name")
+		}
+		return nil
+	})
+}
+
 func changeUserEmail(ctx context.Context, c *cmd.ChangeUserEmail) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
 		cmd := "UPDATE users SET email = $3, email_supressed_at = NULL WHERE id = $1 AND tenant_id = $2"
@@ -213,6 +237,86 @@ func updateCurrentUserSettings(ctx context.Context, c *cmd.UpdateCurrentUserSett
 	})
 }
 
+func setUserAttributes(ctx context.Context, c *cmd.SetUserAttributes) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		now := time.Now()
+		for key, value := range c.Attributes {
+			var err error
+			if value == "" {
+				_, err = trx.Execute(
+					"DELETE FROM user_attributes WHERE tenant_id = $1 AND user_id = $2 AND key = $3",
+					tenant.ID, c.UserID, key,
+				)
+			} else {
+				_, err = trx.Execute(`
+					INSERT INTO user_attributes (tenant_id, user_id, key, value, updated_at)
+					VALUES ($1, $2, $3, $4, $5)
+					ON CONFLICT (tenant_id, user_id, key) DO UPDATE SET value = $4, updated_at = $5
+				`, tenant.ID, c.UserID, key, value, now)
+			}
+			if err != nil {
+				return errors.Wrap(err, "failed to set attribute '%s' of user with id '%d'", key, c.UserID)
+			}
+		}
+
+		return nil
+	})
+}
+
+func getUserAttributes(ctx context.Context, q *query.GetUserAttributes) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		q.Result = make(map[int]map[string]string)
+		if len(q.UserIDs) == 0 {
+			return nil
+		}
+
+		var attributes []*dbEntities.UserAttribute
+		err := trx.Select(&attributes, `
+			SELECT user_id, key, value
+			FROM user_attributes
+			WHERE tenant_id = $1 AND user_id = ANY($2)
+			ORDER BY user_id, key
+		`, tenant.ID, pq.Array(q.UserIDs))
+		if err != nil {
+			return errors.Wrap(err, "failed to get user attributes")
+		}
+
+		for _, a := range attributes {
+			if q.Result[a.UserID] == nil {
+				q.Result[a.UserID] = make(map[string]string)
+			}
+			q.Result[a.UserID][a.Key] = a.Value
+		}
+
+		return nil
+	})
+}
+
+func getUserProviderUIDs(ctx context.Context, q *query.GetUserProviderUIDs) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		q.Result = make(map[int]string)
+		if len(q.UserIDs) == 0 {
+			return nil
+		}
+
+		var providers []*dbEntities.UserProviderUID
+		err := trx.Select(&providers, `
+			SELECT user_id, provider_uid
+			FROM user_providers
+			WHERE tenant_id = $1 AND provider = $2 AND user_id = ANY($3)
+		`, tenant.ID, q.Provider, pq.Array(q.UserIDs))
+		if err != nil {
+			return errors.Wrap(err, "failed to get '%s' provider of users", q.Provider)
+		}
+
+		for _, p := range providers {
+			q.Result[p.UserID] = p.UID
+		}
+
+		return nil
+	})
+}
+
 func getCurrentUserSettings(ctx context.Context, q *query.GetCurrentUserSettings) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
 		q.Result = make(map[string]string)
@@ -230,6 +334,7 @@ func getCurrentUserSettings(ctx context.Context, q *query.GetCurrentUserSettings
 				}
 			}
 		}
+		q.Result[enum.EmailDigestSettingsKeyName] = string(enum.EmailDigestImmediate)
 
 		for _, s := range settings {
 			q.Result[s.Key] = s.Value
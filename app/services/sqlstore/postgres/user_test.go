@@ -420,3 +420,25 @@ func TestUserStorage_BlockUser(t *testing.T) {
 	Expect(err).IsNil()
 	Expect(getUser.Result.Status).Equals(enum.UserActive)
 }
+
+func TestUserStorage_SetGetUserAttributes(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	err := bus.Dispatch(demoTenantCtx,
+		&cmd.SetUserAttributes{UserID: jonSnow.ID, Attributes: map[string]string{"plan": "pro", "mrr": "300"}},
+		&cmd.SetUserAttributes{UserID: aryaStark.ID, Attributes: map[string]string{"plan": "enterprise"}},
+	)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(demoTenantCtx, &cmd.SetUserAttributes{UserID: jonSnow.ID, Attributes: map[string]string{"plan": "enterprise", "mrr": ""}})
+	Expect(err).IsNil()
+
+	getAttributes := &query.GetUserAttributes{UserIDs: []int{jonSnow.ID, aryaStark.ID}}
+	err = bus.Dispatch(demoTenantCtx, getAttributes)
+	Expect(err).IsNil()
+	Expect(getAttributes.Result).Equals(map[int]map[string]string{
+		jonSnow.ID:   {"plan": "enterprise"},
+		aryaStark.ID: {"plan": "enterprise"},
+	})
+}
@@ -5,12 +5,16 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/getfider/fider/app"
 	"github.com/getfider/fider/app/models/cmd"
 	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
 	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/cursor"
 	"github.com/getfider/fider/app/pkg/dbx"
 	"github.com/getfider/fider/app/pkg/errors"
 	"github.com/getfider/fider/app/services/sqlstore/dbEntities"
+	"github.com/lib/pq"
 )
 
 func addVote(ctx context.Context, c *cmd.AddVote) error {
@@ -19,9 +23,32 @@ func addVote(ctx context.Context, c *cmd.AddVote) error {
 			return nil
 		}
 
+		weight := c.Weight
+		if weight < 1 {
+			weight = 1
+		}
+
+		if tenant.IsVoteBudgetEnabled() {
+			// Locking the user serializes concurrent votes of the same user, so the budget is checked against committed votes
+			var userID int
+			if err := trx.Scalar(&userID, "SELECT id FROM users WHERE id = $1 AND tenant_id = $2 FOR UPDATE", c.User.ID, tenant.ID); err != nil {
+				return errors.Wrap(err, "failed to lock user with id '%d'", c.User.ID)
+			}
+
+			// Votes already placed on this post are given back before spending the new weight
+			spent, err := sumSpentVotes(trx, tenant, c.User.ID, c.Post.ID)
+			if err != nil {
+				return err
+			}
+			if spent+weight > tenant.VoteBudget {
+				return app.ErrVoteBudgetExceeded
+			}
+		}
+
 		_, err := trx.Execute(
-			`INSERT INTO post_votes (tenant_id, user_id, post_id, created_at) VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING`,
-			tenant.ID, c.User.ID, c.Post.ID, time.Now(),
+			`INSERT INTO post_votes (tenant_id, user_id, post_id, created_at, weight) VALUES ($1, $2, $3, $4, $5)
+			 ON CONFLICT (user_id, post_id) DO UPDATE SET weight = EXCLUDED.weight`,
+			tenant.ID, c.User.ID, c.Post.ID, time.Now(), weight,
 		)
 
 		if err != nil {
@@ -50,9 +77,18 @@ func removeVote(ctx context.Context, c *cmd.RemoveVote) error {
 func listPostVotes(ctx context.Context, q *query.ListPostVotes) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
 		q.Result = make([]*entity.Vote, 0)
+
+		if q.CountTotal {
+			err := trx.Scalar(&q.TotalCount, "SELECT COUNT(*) FROM post_votes WHERE post_id = $1 AND tenant_id = $2", q.PostID, tenant.ID)
+			if err != nil {
+				return errors.Wrap(err, "failed to count votes of post")
+			}
+		}
+
+		// One more vote than requested is fetched to know if there's a next page
 		sqlLimit := "ALL"
 		if q.Limit > 0 {
-			sqlLimit = strconv.Itoa(q.Limit)
+			sqlLimit = strconv.Itoa(q.Limit + 1)
 		}
 
 		emailColumn := "''"
@@ -60,10 +96,23 @@ func listPostVotes(ctx context.Context, q *query.ListPostVotes) error {
 			emailColumn = "u.email"
 		}
 
+		// Votes are sorted by creation date and then by user, so that cursors point to a stable position
+		params := []any{q.PostID, tenant.ID}
+		cursorCondition := ""
+		if q.Cursor != "" {
+			key, userID, err := cursor.Decode(q.Cursor)
+			if err != nil {
+				return err
+			}
+			cursorCondition = "AND (pv.created_at, pv.user_id) > ($3::timestamptz, $4)"
+			params = append(params, key, userID)
+		}
+
 		votes := []*dbEntities.Vote{}
 		err := trx.Select(&votes, `
 		SELECT 
 			pv.created_at, 
+			pv.weight,
 			u.id AS user_id,
 			u.name AS user_name,
 			`+emailColumn+` AS user_email,
@@ -75,12 +124,19 @@ func listPostVotes(ctx context.Context, q *query.ListPostVotes) error {
 		AND u.tenant_id = pv.tenant_id 
 		WHERE pv.post_id = $1  
 		AND pv.tenant_id = $2
-		ORDER BY pv.created_at
-		LIMIT `+sqlLimit, q.PostID, tenant.ID)
+		`+cursorCondition+`
+		ORDER BY pv.created_at, pv.user_id
+		LIMIT `+sqlLimit, params...)
 		if err != nil {
 			return errors.Wrap(err, "failed to get votes of post")
 		}
 
+		if q.Limit > 0 && len(votes) > q.Limit {
+			votes = votes[:q.Limit]
+			last := votes[len(votes)-1]
+			q.NextCursor = cursor.Encode(last.CreatedAt.Format(time.RFC3339Nano), last.User.ID)
+		}
+
 		q.Result = make([]*entity.Vote, len(votes))
 		for i, vote := range votes {
 			q.Result[i] = vote.ToModel(ctx)
@@ -89,3 +145,40 @@ func listPostVotes(ctx context.Context, q *query.ListPostVotes) error {
 		return nil
 	})
 }
+
+func getVoteBudget(ctx context.Context, q *query.GetVoteBudget) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		q.Result = &entity.VoteBudget{Total: tenant.VoteBudget}
+		if user == nil {
+			return nil
+		}
+
+		spent, err := sumSpentVotes(trx, tenant, user.ID, 0)
+		q.Result.Spent = spent
+		return err
+	})
+}
+
+// sumSpentVotes returns how many votes given user has placed on posts that accept votes, leaving out the post with exceptPostID
+func sumSpentVotes(trx *dbx.Trx, tenant *entity.Tenant, userID, exceptPostID int) (int, error) {
+	var spent int
+	err := trx.Scalar(&spent, `
+		SELECT COALESCE(SUM(pv.weight), 0)
+		FROM post_votes pv
+		INNER JOIN posts p
+		ON p.id = pv.post_id
+		AND p.tenant_id = pv.tenant_id
+		WHERE pv.user_id = $1
+		AND pv.tenant_id = $2
+		AND pv.post_id <> $4
+		AND (p.status = ANY($3) OR EXISTS (
+			SELECT 1 FROM post_statuses ps
+			WHERE ps.tenant_id = p.tenant_id
+			AND ps.status = p.status
+			AND ps.accepts_votes = true
+		))`, userID, tenant.ID, pq.Array([]enum.PostStatus{enum.PostOpen, enum.PostPlanned, enum.PostStarted}), exceptPostID)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get vote budget of user with id '%d'", userID)
+	}
+	return spent, nil
+}
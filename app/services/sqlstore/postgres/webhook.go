@@ -2,17 +2,25 @@ package postgres
 
 import (
 	"context"
+	"time"
+
+	"github.com/getfider/fider/app/models/cmd"
 	"github.com/getfider/fider/app/models/entity"
 	"github.com/getfider/fider/app/models/enum"
 	"github.com/getfider/fider/app/models/query"
 	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/webhook"
+	"github.com/getfider/fider/app/services/sqlstore/dbEntities"
 )
 
+const webhookDeliveryFields = `id, tenant_id, webhook_id, status, attempts, url, content, status_code, latency_ms, response, error, created_at, last_attempt_at, next_attempt_at`
+
 func getWebhook(ctx context.Context, q *query.GetWebhook) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
 		webhook := &entity.Webhook{}
 		err := trx.Get(webhook, `
-			SELECT id, name, type, status, url, content, http_method, http_headers 
+			SELECT id, name, type, status, url, content, http_method, http_headers, secret 
 			FROM webhooks 
 			WHERE tenant_id = $1 AND id = $2`, tenant.ID, q.ID)
 		if err != nil {
@@ -28,7 +36,7 @@ func listAllWebhooks(ctx context.Context, q *query.ListAllWebhooks) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
 		webhooks := []*entity.Webhook{}
 		err := trx.Select(&webhooks, `
-			SELECT id, name, type, status, url, content, http_method, http_headers 
+			SELECT id, name, type, status, url, content, http_method, http_headers, secret 
 			FROM webhooks 
 			WHERE tenant_id = $1 
 			ORDER BY id`, tenant.ID)
@@ -45,7 +53,7 @@ func listAllWebhooksByType(ctx context.Context, q *query.ListAllWebhooksByType)
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
 		webhooks := []*entity.Webhook{}
 		err := trx.Select(&webhooks, `
-			SELECT id, name, type, status, url, content, http_method, http_headers 
+			SELECT id, name, type, status, url, content, http_method, http_headers, secret 
 			FROM webhooks 
 			WHERE tenant_id = $1 AND type = $2 
 			ORDER BY id`, tenant.ID, q.Type)
@@ -62,7 +70,7 @@ func listActiveWebhooksByType(ctx context.Context, q *query.ListActiveWebhooksBy
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
 		webhooks := []*entity.Webhook{}
 		err := trx.Select(&webhooks, `
-			SELECT id, name, type, status, url, content, http_method, http_headers 
+			SELECT id, name, type, status, url, content, http_method, http_headers, secret 
 			FROM webhooks 
 			WHERE tenant_id = $1 AND type = $2 AND status = $3 
 			ORDER BY id`, tenant.ID, q.Type, enum.WebhookEnabled)
@@ -82,9 +90,9 @@ func createEditWebhook(ctx context.Context, q *query.CreateEditWebhook) error {
 
 		if q.ID == 0 {
 			err = trx.Get(&id, `
-				INSERT INTO webhooks (name, type, status, url, content, http_method, http_headers, tenant_id) 
-				VALUES ($1, $2, $3, $4, $5, $6, $7, $8) 
-				RETURNING id`, q.Name, q.Type, q.Status, q.Url, q.Content, q.HttpMethod, q.HttpHeaders, tenant.ID)
+				INSERT INTO webhooks (name, type, status, url, content, http_method, http_headers, secret, tenant_id) 
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) 
+				RETURNING id`, q.Name, q.Type, q.Status, q.Url, q.Content, q.HttpMethod, q.HttpHeaders, webhook.NewSecret(), tenant.ID)
 		} else {
 			_, err = trx.Execute(`
 				UPDATE webhooks 
@@ -118,3 +126,119 @@ func markWebhookAsFailed(ctx context.Context, q *query.MarkWebhookAsFailed) erro
 		return err
 	})
 }
+
+func setWebhookSecret(ctx context.Context, c *cmd.SetWebhookSecret) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		_, err := trx.Execute(`
+			UPDATE webhooks 
+			SET secret = $3 
+			WHERE tenant_id = $1 AND id = $2`, tenant.ID, c.ID, c.Secret)
+		if err != nil {
+			return errors.Wrap(err, "failed to set secret of webhook '%d'", c.ID)
+		}
+		return nil
+	})
+}
+
+func addWebhookDelivery(ctx context.Context, c *cmd.AddWebhookDelivery) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		delivery := dbEntities.WebhookDelivery{}
+		err := trx.Get(&delivery, `
+			INSERT INTO webhook_deliveries (tenant_id, webhook_id, status, attempts, url, content, status_code, latency_ms, response, error, created_at, last_attempt_at, next_attempt_at)
+			VALUES ($1, $2, $3, 1, $4, $5, $6, $7, $8, $9, $10, $10, $11)
+			RETURNING `+webhookDeliveryFields,
+			tenant.ID, c.WebhookID, c.Status, c.Url, c.Content, c.StatusCode, c.LatencyMs, c.Response, c.Error, time.Now(), c.NextAttemptAt)
+		if err != nil {
+			return errors.Wrap(err, "failed to add delivery of webhook '%d'", c.WebhookID)
+		}
+
+		c.Result = delivery.ToModel()
+		return nil
+	})
+}
+
+func updateWebhookDelivery(ctx context.Context, c *cmd.UpdateWebhookDelivery) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		_, err := trx.Execute(`
+			UPDATE webhook_deliveries
+			SET status = $3, attempts = $4, status_code = $5, latency_ms = $6, response = $7, error = $8, last_attempt_at = $9, next_attempt_at = $10
+			WHERE tenant_id = $1 AND id = $2`,
+			tenant.ID, c.ID, c.Status, c.Attempts, c.StatusCode, c.LatencyMs, c.Response, c.Error, time.Now(), c.NextAttemptAt)
+		if err != nil {
+			return errors.Wrap(err, "failed to update webhook delivery '%d'", c.ID)
+		}
+		return nil
+	})
+}
+
+func getWebhookDelivery(ctx context.Context, q *query.GetWebhookDelivery) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		delivery := dbEntities.WebhookDelivery{}
+		err := trx.Get(&delivery, `
+			SELECT `+webhookDeliveryFields+`
+			FROM webhook_deliveries
+			WHERE tenant_id = $1 AND webhook_id = $2 AND id = $3`, tenant.ID, q.WebhookID, q.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get webhook delivery '%d'", q.ID)
+		}
+
+		q.Result = delivery.ToModel()
+		return nil
+	})
+}
+
+func listWebhookDeliveries(ctx context.Context, q *query.ListWebhookDeliveries) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		deliveries := []*dbEntities.WebhookDelivery{}
+		err := trx.Select(&deliveries, `
+			SELECT `+webhookDeliveryFields+`
+			FROM webhook_deliveries
+			WHERE tenant_id = $1 AND webhook_id = $2
+			ORDER BY id DESC
+			LIMIT $3`, tenant.ID, q.WebhookID, q.Limit)
+		if err != nil {
+			return errors.Wrap(err, "failed to list deliveries of webhook '%d'", q.WebhookID)
+		}
+
+		q.Result = make([]*entity.WebhookDelivery, len(deliveries))
+		for i, delivery := range deliveries {
+			q.Result[i] = delivery.ToModel()
+		}
+		return nil
+	})
+}
+
+func listPendingWebhookDeliveries(ctx context.Context, q *query.ListPendingWebhookDeliveries) error {
+	return using(ctx, func(trx *dbx.Trx, _ *entity.Tenant, _ *entity.User) error {
+		deliveries := []*dbEntities.WebhookDelivery{}
+		err := trx.Select(&deliveries, `
+			SELECT `+webhookDeliveryFields+`
+			FROM webhook_deliveries
+			WHERE status = $1 AND next_attempt_at <= $2
+			ORDER BY next_attempt_at
+			LIMIT $3`, enum.WebhookDeliveryPending, time.Now(), q.Limit)
+		if err != nil {
+			return errors.Wrap(err, "failed to list pending webhook deliveries")
+		}
+
+		q.Result = make([]*entity.WebhookDelivery, len(deliveries))
+		for i, delivery := range deliveries {
+			q.Result[i] = delivery.ToModel()
+		}
+		return nil
+	})
+}
+
+func purgeExpiredWebhookDeliveries(ctx context.Context, c *cmd.PurgeExpiredWebhookDeliveries) error {
+	return using(ctx, func(trx *dbx.Trx, _ *entity.Tenant, _ *entity.User) error {
+		count, err := trx.Execute(`
+			DELETE FROM webhook_deliveries
+			WHERE created_at <= $1 AND status <> $2`, c.Before, enum.WebhookDeliveryPending)
+		if err != nil {
+			return errors.Wrap(err, "failed to delete expired webhook deliveries")
+		}
+
+		c.NumOfDeletedDeliveries = int(count)
+		return nil
+	})
+}
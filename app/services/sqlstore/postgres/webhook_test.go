@@ -0,0 +1,117 @@
+package postgres_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+)
+
+func createTestWebhook() int {
+	createWebhook := &query.CreateEditWebhook{
+		Name:       "New Post",
+		Type:       enum.WebhookNewPost,
+		Status:     enum.WebhookEnabled,
+		Url:        "https://example.com/hook",
+		Content:    "{}",
+		HttpMethod: "POST",
+	}
+	err := bus.Dispatch(demoTenantCtx, createWebhook)
+	Expect(err).IsNil()
+	return createWebhook.Result
+}
+
+func TestWebhookStorage_AddAndListDeliveries(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	webhookID := createTestWebhook()
+
+	first := &cmd.AddWebhookDelivery{
+		WebhookID:  webhookID,
+		Status:     enum.WebhookDeliverySuccess,
+		Url:        "https://example.com/hook",
+		Content:    `{"id": 1}`,
+		StatusCode: 200,
+		LatencyMs:  120,
+		Response:   "OK",
+	}
+	err := bus.Dispatch(demoTenantCtx, first)
+	Expect(err).IsNil()
+	Expect(first.Result.ID).NotEquals(0)
+	Expect(first.Result.Attempts).Equals(1)
+	Expect(first.Result.NextAttemptAt).IsNil()
+
+	second := &cmd.AddWebhookDelivery{
+		WebhookID:  webhookID,
+		Status:     enum.WebhookDeliveryFailed,
+		Url:        "https://example.com/hook",
+		Content:    `{"id": 2}`,
+		StatusCode: 500,
+		Error:      "500 Internal Server Error",
+	}
+	err = bus.Dispatch(demoTenantCtx, second)
+	Expect(err).IsNil()
+
+	list := &query.ListWebhookDeliveries{WebhookID: webhookID, Limit: 10}
+	err = bus.Dispatch(demoTenantCtx, list)
+	Expect(err).IsNil()
+	Expect(list.Result).HasLen(2)
+	Expect(list.Result[0].ID).Equals(second.Result.ID)
+	Expect(list.Result[0].Status).Equals(enum.WebhookDeliveryFailed)
+	Expect(list.Result[0].Error).Equals("500 Internal Server Error")
+	Expect(list.Result[1].ID).Equals(first.Result.ID)
+	Expect(list.Result[1].LatencyMs).Equals(120)
+	Expect(list.Result[1].Response).Equals("OK")
+
+	otherTenantList := &query.ListWebhookDeliveries{WebhookID: webhookID, Limit: 10}
+	err = bus.Dispatch(avengersTenantCtx, otherTenantList)
+	Expect(err).IsNil()
+	Expect(otherTenantList.Result).HasLen(0)
+}
+
+func TestWebhookStorage_PendingDeliveries(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	webhookID := createTestWebhook()
+
+	past := time.Now().Add(-1 * time.Minute)
+	future := time.Now().Add(1 * time.Hour)
+
+	due := &cmd.AddWebhookDelivery{WebhookID: webhookID, Status: enum.WebhookDeliveryPending, Url: "https://example.com/hook", Content: "{}", NextAttemptAt: &past}
+	notDue := &cmd.AddWebhookDelivery{WebhookID: webhookID, Status: enum.WebhookDeliveryPending, Url: "https://example.com/hook", Content: "{}", NextAttemptAt: &future}
+	err := bus.Dispatch(demoTenantCtx, due, notDue)
+	Expect(err).IsNil()
+
+	pending := &query.ListPendingWebhookDeliveries{Limit: 10}
+	err = bus.Dispatch(demoTenantCtx, pending)
+	Expect(err).IsNil()
+	Expect(pending.Result).HasLen(1)
+	Expect(pending.Result[0].ID).Equals(due.Result.ID)
+	Expect(pending.Result[0].TenantID).Equals(demoTenant.ID)
+
+	err = bus.Dispatch(demoTenantCtx, &cmd.UpdateWebhookDelivery{
+		ID:         due.Result.ID,
+		Status:     enum.WebhookDeliverySuccess,
+		Attempts:   2,
+		StatusCode: 200,
+	})
+	Expect(err).IsNil()
+
+	get := &query.GetWebhookDelivery{WebhookID: webhookID, ID: due.Result.ID}
+	err = bus.Dispatch(demoTenantCtx, get)
+	Expect(err).IsNil()
+	Expect(get.Result.Status).Equals(enum.WebhookDeliverySuccess)
+	Expect(get.Result.Attempts).Equals(2)
+	Expect(get.Result.NextAttemptAt).IsNil()
+
+	pending = &query.ListPendingWebhookDeliveries{Limit: 10}
+	err = bus.Dispatch(demoTenantCtx, pending)
+	Expect(err).IsNil()
+	Expect(pending.Result).HasLen(0)
+}
@@ -41,6 +41,33 @@ You can use ` + "`" + `{{ markdown .post_description }}` + "`" + ` to parse __Ma
 	Tags: []string{"tag1", "tag2"},
 }
 
+var dummyComment = &entity.Comment{
+	ID:        12,
+	Content:   "An example **comment** on a post.",
+	CreatedAt: time.Date(2021, time.May, 8, 9, 12, 45, 0, time.UTC),
+	User: &entity.User{
+		ID:    8,
+		Name:  "Jane Doe",
+		Email: "jane.doe@example.com",
+		Role:  3,
+	},
+}
+
+var dummyTag = &entity.Tag{
+	ID:       4,
+	Name:     "Feature Request",
+	Slug:     "feature-request",
+	Color:    "1A7F37",
+	IsPublic: true,
+}
+
+var dummyUser = &entity.User{
+	ID:    9,
+	Name:  "John Doe",
+	Email: "john.doe@example.com",
+	Role:  3,
+}
+
 func dummyTriggerProps(c context.Context, webhookType enum.WebhookType) webhook.Props {
 	props := webhook.Props{}
 	author := c.Value(app.UserCtxKey).(*entity.User)
@@ -53,10 +80,9 @@ func dummyTriggerProps(c context.Context, webhookType enum.WebhookType) webhook.
 	switch webhookType {
 	case enum.WebhookNewPost:
 		props.SetPost(dummyPost, "post", baseURL, false, false)
-	case enum.WebhookNewComment:
+	case enum.WebhookNewComment, enum.WebhookEditComment, enum.WebhookDeleteComment:
 		props.SetPost(dummyPost, "post", baseURL, true, true)
-		props["comment"] = "An example **comment** on a post."
-		props["comment_id"] = 12
+		props.SetComment(dummyComment, "comment")
 	case enum.WebhookChangeStatus:
 		props.SetPost(dummyPost, "post", baseURL, true, true)
 		props["post_old_status"] = enum.PostOpen.Name()
@@ -64,6 +90,14 @@ func dummyTriggerProps(c context.Context, webhookType enum.WebhookType) webhook.
 		props.SetPost(dummyPost, "post", baseURL, true, true)
 		props["post_status"] = enum.PostDeleted.Name()
 		props["post_response_text"] = "The reason _why_ this post was deleted."
+	case enum.WebhookAddVote, enum.WebhookRemoveVote, enum.WebhookEditPost:
+		props.SetPost(dummyPost, "post", baseURL, true, true)
+	case enum.WebhookAssignTag, enum.WebhookUnassignTag:
+		props.SetPost(dummyPost, "post", baseURL, true, true)
+		props.SetTag(dummyTag, "tag")
+	case enum.WebhookNewUser:
+		dummyUser.AvatarURL = logoURL
+		props.SetUser(dummyUser, "user")
 	}
 	return props
 }
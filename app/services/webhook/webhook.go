@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/getfider/fider/app/models/cmd"
 	"github.com/getfider/fider/app/models/dto"
 	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
 	"github.com/getfider/fider/app/models/query"
 	"github.com/getfider/fider/app/pkg/bus"
 	"github.com/getfider/fider/app/pkg/env"
@@ -18,6 +20,8 @@ import (
 	"github.com/getfider/fider/app/pkg/webhook"
 )
 
+const maxResponseExcerptLength = 1000
+
 func init() {
 	bus.Register(Service{})
 }
@@ -39,6 +43,8 @@ func (s Service) Enabled() bool {
 func (s Service) Init() {
 	bus.AddHandler(testWebhook)
 	bus.AddHandler(triggerWebhooks)
+	bus.AddHandler(retryWebhookDelivery)
+	bus.AddHandler(redeliverWebhook)
 	bus.AddHandler(previewWebhook)
 	bus.AddHandler(getWebhookProps)
 }
@@ -50,9 +56,9 @@ func testWebhook(ctx context.Context, c *cmd.TestWebhook) error {
 		return err
 	}
 
-	c.Result, err = triggerWebhook(ctx, webhook_.Result, dummyTriggerProps(ctx, webhook_.Result.Type))
-	if err != nil {
-		return err
+	c.Result = triggerWebhook(ctx, webhook_.Result, dummyTriggerProps(ctx, webhook_.Result.Type))
+	if !c.Result.Success {
+		return disableOnFailure(ctx, webhook_.Result)
 	}
 
 	return nil
@@ -66,56 +72,171 @@ func triggerWebhooks(ctx context.Context, c *cmd.TriggerWebhooks) error {
 	}
 
 	for _, webhook_ := range webhooks.Result {
-		_, err = triggerWebhook(ctx, webhook_, c.Props)
-		if err != nil {
+		result, ok := renderWebhook(ctx, webhook_, c.Props)
+		if !ok {
+			// A template that cannot be rendered will never succeed, so there is nothing to retry
+			if err := addWebhookDelivery(ctx, result, enum.WebhookDeliveryFailed, nil); err != nil {
+				return err
+			}
+			if err := disableOnFailure(ctx, webhook_); err != nil {
+				return err
+			}
+			continue
+		}
+
+		sendWebhook(ctx, result)
+		status, nextAttemptAt := nextDeliveryState(result, 1)
+		if err := addWebhookDelivery(ctx, result, status, nextAttemptAt); err != nil {
 			return err
 		}
+		if status == enum.WebhookDeliveryFailed {
+			if err := disableOnFailure(ctx, webhook_); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
 }
 
-func triggerWebhook(ctx context.Context, webhook *entity.Webhook, props webhook.Props) (*dto.WebhookTriggerResult, error) {
+func retryWebhookDelivery(ctx context.Context, c *cmd.RetryWebhookDelivery) error {
+	webhook_ := &query.GetWebhook{ID: c.Delivery.WebhookID}
+	if err := bus.Dispatch(ctx, webhook_); err != nil {
+		return err
+	}
+
+	result := &dto.WebhookTriggerResult{
+		Webhook: webhook_.Result,
+		Url:     c.Delivery.Url,
+		Content: c.Delivery.Content,
+	}
+
+	if webhook_.Result.Status != enum.WebhookEnabled {
+		// The webhook has been disabled since the first attempt, so stop retrying it
+		return bus.Dispatch(ctx, &cmd.UpdateWebhookDelivery{
+			ID:         c.Delivery.ID,
+			Status:     enum.WebhookDeliveryFailed,
+			Attempts:   c.Delivery.Attempts,
+			StatusCode: c.Delivery.StatusCode,
+			LatencyMs:  c.Delivery.LatencyMs,
+			Response:   c.Delivery.Response,
+			Error:      "Webhook is no longer enabled",
+		})
+	}
+
+	attempts := c.Delivery.Attempts + 1
+	sendWebhook(ctx, result)
+	status, nextAttemptAt := nextDeliveryState(result, attempts)
+	err := bus.Dispatch(ctx, &cmd.UpdateWebhookDelivery{
+		ID:            c.Delivery.ID,
+		Status:        status,
+		Attempts:      attempts,
+		StatusCode:    result.StatusCode,
+		LatencyMs:     result.LatencyMs,
+		Response:      result.Response,
+		Error:         result.Error,
+		NextAttemptAt: nextAttemptAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	if status == enum.WebhookDeliveryFailed {
+		return disableOnFailure(ctx, webhook_.Result)
+	}
+	return nil
+}
+
+func redeliverWebhook(ctx context.Context, c *cmd.RedeliverWebhook) error {
+	webhook_ := &query.GetWebhook{ID: c.ID}
+	if err := bus.Dispatch(ctx, webhook_); err != nil {
+		return err
+	}
+
+	delivery := &query.GetWebhookDelivery{WebhookID: c.ID, ID: c.DeliveryID}
+	if err := bus.Dispatch(ctx, delivery); err != nil {
+		return err
+	}
+
+	c.Result = &dto.WebhookTriggerResult{
+		Webhook: webhook_.Result,
+		Url:     delivery.Result.Url,
+		Content: delivery.Result.Content,
+	}
+
+	sendWebhook(ctx, c.Result)
+	status, nextAttemptAt := nextDeliveryState(c.Result, 1)
+	return addWebhookDelivery(ctx, c.Result, status, nextAttemptAt)
+}
+
+func triggerWebhook(ctx context.Context, webhook *entity.Webhook, props webhook.Props) *dto.WebhookTriggerResult {
+	result, ok := renderWebhook(ctx, webhook, props)
+	if !ok {
+		return result
+	}
+	return sendWebhook(ctx, result)
+}
+
+func renderWebhook(ctx context.Context, webhook *entity.Webhook, props webhook.Props) (*dto.WebhookTriggerResult, bool) {
 	result := &dto.WebhookTriggerResult{Webhook: webhook, Props: props}
 	var err error
 
 	fullName := fmt.Sprintf("%d-%s", webhook.ID, webhook.Name)
 	result.Url, err = executeTemplate(fmt.Sprintf("%s-url", fullName), webhook.Url, props)
 	if err != nil {
-		return resultWithError(ctx, "Could not parse webhook URL template", err.Error(), result)
+		return resultWithError(ctx, "Could not parse webhook URL template", err.Error(), result), false
+	}
+	result.Content, err = executeTemplate(fmt.Sprintf("%s-content", fullName), webhook.Content, props)
+	if err != nil {
+		return resultWithError(ctx, "Could not parse webhook content template", err.Error(), result), false
 	}
+
+	return result, true
+}
+
+func sendWebhook(ctx context.Context, result *dto.WebhookTriggerResult) *dto.WebhookTriggerResult {
+	webhook_ := result.Webhook
 	if msgs := validate.WebhookURL(result.Url); len(msgs) > 0 {
 		return resultWithError(ctx, "Webhook URL targets a blocked address", strings.Join(msgs, "; "), result)
 	}
-	result.Content, err = executeTemplate(fmt.Sprintf("%s-content", fullName), webhook.Content, props)
-	if err != nil {
-		return resultWithError(ctx, "Could not parse webhook content template", err.Error(), result)
+
+	headers := make(map[string]string, len(webhook_.HttpHeaders)+1)
+	for key, value := range webhook_.HttpHeaders {
+		headers[key] = value
+	}
+	if webhook_.Secret != "" {
+		headers[webhook.SignatureHeader] = webhook.Sign(webhook_.Secret, time.Now(), result.Content)
 	}
 
 	httpRequest := &cmd.HTTPRequest{
 		URL:       result.Url,
 		Body:      strings.NewReader(result.Content),
-		Method:    webhook.HttpMethod,
-		Headers:   webhook.HttpHeaders,
+		Method:    webhook_.HttpMethod,
+		Headers:   headers,
 		BasicAuth: nil,
 	}
-	err = bus.Dispatch(ctx, httpRequest)
+	start := time.Now()
+	err := bus.Dispatch(ctx, httpRequest)
+	result.LatencyMs = int(time.Since(start).Milliseconds())
 	if err != nil {
 		return resultWithError(ctx, "Could not execute webhook HTTP request", err.Error(), result)
 	}
 	result.StatusCode = httpRequest.ResponseStatusCode
+	result.Response = responseExcerpt(httpRequest.ResponseBody)
 	if result.StatusCode >= http.StatusBadRequest {
 		fullResponse := fmt.Sprintf("%d %s:\n%s", result.StatusCode, http.StatusText(result.StatusCode), httpRequest.ResponseBody)
 		return resultWithError(ctx, "Webhook HTTP request returned an error response code", fullResponse, result)
 	}
 
 	result.Success = true
+	result.Message = ""
+	result.Error = ""
 	log.Infof(ctx, "Webhook #@{ID:yellow} @{Name:blue} finished with @{Code:magenta}", dto.Props{
-		"ID":   webhook.ID,
-		"Name": webhook.Name,
+		"ID":   webhook_.ID,
+		"Name": webhook_.Name,
 		"Code": result.StatusCode,
 	})
-	return result, nil
+	return result
 }
 
 func previewWebhook(ctx context.Context, c *cmd.PreviewWebhook) error {
@@ -153,7 +274,7 @@ func executeTemplate(name, text string, props webhook.Props) (string, error) {
 	return replacedText, nil
 }
 
-func resultWithError(ctx context.Context, message, error string, result *dto.WebhookTriggerResult) (*dto.WebhookTriggerResult, error) {
+func resultWithError(ctx context.Context, message, error string, result *dto.WebhookTriggerResult) *dto.WebhookTriggerResult {
 	result.Success = false
 	result.Message = message
 	result.Error = error
@@ -165,15 +286,56 @@ func resultWithError(ctx context.Context, message, error string, result *dto.Web
 		"Error":   error,
 	})
 
-	if env.Config.Webhook.DisableOnFailure {
-		webhooks := &query.MarkWebhookAsFailed{ID: result.Webhook.ID}
-		err := bus.Dispatch(ctx, webhooks)
-		if err != nil {
-			return nil, err
-		}
+	return result
+}
+
+func disableOnFailure(ctx context.Context, webhook *entity.Webhook) error {
+	if !env.Config.Webhook.DisableOnFailure {
+		return nil
 	}
+	return bus.Dispatch(ctx, &query.MarkWebhookAsFailed{ID: webhook.ID})
+}
 
-	return result, nil
+func addWebhookDelivery(ctx context.Context, result *dto.WebhookTriggerResult, status enum.WebhookDeliveryStatus, nextAttemptAt *time.Time) error {
+	return bus.Dispatch(ctx, &cmd.AddWebhookDelivery{
+		WebhookID:     result.Webhook.ID,
+		Status:        status,
+		Url:           result.Url,
+		Content:       result.Content,
+		StatusCode:    result.StatusCode,
+		LatencyMs:     result.LatencyMs,
+		Response:      result.Response,
+		Error:         result.Error,
+		NextAttemptAt: nextAttemptAt,
+	})
+}
+
+// nextDeliveryState returns the status of a delivery after given number of attempts
+// and, if it should be retried, when the next attempt is due
+func nextDeliveryState(result *dto.WebhookTriggerResult, attempts int) (enum.WebhookDeliveryStatus, *time.Time) {
+	if result.Success {
+		return enum.WebhookDeliverySuccess, nil
+	}
+	if attempts >= env.Config.Webhook.MaxAttempts {
+		return enum.WebhookDeliveryFailed, nil
+	}
+	next := time.Now().Add(retryDelay(attempts))
+	return enum.WebhookDeliveryPending, &next
+}
+
+// retryDelay doubles the wait after each failed attempt, starting at one minute
+func retryDelay(attempts int) time.Duration {
+	if attempts > 10 {
+		attempts = 10
+	}
+	return time.Minute << (attempts - 1)
+}
+
+func responseExcerpt(body []byte) string {
+	if len(body) > maxResponseExcerptLength {
+		body = body[:maxResponseExcerptLength]
+	}
+	return strings.ToValidUTF8(string(body), "")
 }
 
 func getWebhookProps(ctx context.Context, c *cmd.GetWebhookProps) error {
@@ -0,0 +1,214 @@
+package webhook_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/env"
+	"github.com/getfider/fider/app/pkg/webhook"
+	webhookService "github.com/getfider/fider/app/services/webhook"
+)
+
+var newPostWebhook = &entity.Webhook{
+	ID:         1,
+	Name:       "New Post",
+	Type:       enum.WebhookNewPost,
+	Status:     enum.WebhookEnabled,
+	Url:        "https://8.8.8.8/hooks/{{ .post_number }}",
+	Content:    `{"title": "{{ .post_title }}"}`,
+	HttpMethod: "POST",
+	HttpHeaders: entity.HttpHeaders{
+		"Authorization": "Bearer token",
+	},
+	Secret: "s3cr3t",
+}
+
+func setupWebhookTest(statusCode int) (*[]*cmd.AddWebhookDelivery, *[]*cmd.UpdateWebhookDelivery, *[]int) {
+	bus.Init(webhookService.Service{})
+
+	bus.AddHandler(func(ctx context.Context, q *query.ListActiveWebhooksByType) error {
+		q.Result = []*entity.Webhook{newPostWebhook}
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetWebhook) error {
+		q.Result = newPostWebhook
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, c *cmd.HTTPRequest) error {
+		c.ResponseStatusCode = statusCode
+		c.ResponseBody = []byte("Response from receiver")
+		return nil
+	})
+
+	added := make([]*cmd.AddWebhookDelivery, 0)
+	bus.AddHandler(func(ctx context.Context, c *cmd.AddWebhookDelivery) error {
+		added = append(added, c)
+		c.Result = &entity.WebhookDelivery{ID: len(added), WebhookID: c.WebhookID}
+		return nil
+	})
+
+	updated := make([]*cmd.UpdateWebhookDelivery, 0)
+	bus.AddHandler(func(ctx context.Context, c *cmd.UpdateWebhookDelivery) error {
+		updated = append(updated, c)
+		return nil
+	})
+
+	failed := make([]int, 0)
+	bus.AddHandler(func(ctx context.Context, q *query.MarkWebhookAsFailed) error {
+		failed = append(failed, q.ID)
+		return nil
+	})
+
+	return &added, &updated, &failed
+}
+
+func TestTriggerWebhooks_Success_RecordsDelivery(t *testing.T) {
+	RegisterT(t)
+	added, _, failed := setupWebhookTest(http.StatusOK)
+
+	err := bus.Dispatch(context.Background(), &cmd.TriggerWebhooks{
+		Type:  enum.WebhookNewPost,
+		Props: webhook.Props{"post_number": 4, "post_title": "Add support for TypeScript"},
+	})
+	Expect(err).IsNil()
+
+	Expect(*added).HasLen(1)
+	Expect((*added)[0].WebhookID).Equals(1)
+	Expect((*added)[0].Status).Equals(enum.WebhookDeliverySuccess)
+	Expect((*added)[0].Url).Equals("https://8.8.8.8/hooks/4")
+	Expect((*added)[0].Content).Equals(`{"title": "Add support for TypeScript"}`)
+	Expect((*added)[0].StatusCode).Equals(http.StatusOK)
+	Expect((*added)[0].Response).Equals("Response from receiver")
+	Expect((*added)[0].NextAttemptAt).IsNil()
+	Expect(*failed).HasLen(0)
+}
+
+func TestTriggerWebhooks_Failure_SchedulesRetry(t *testing.T) {
+	RegisterT(t)
+	added, _, failed := setupWebhookTest(http.StatusServiceUnavailable)
+
+	err := bus.Dispatch(context.Background(), &cmd.TriggerWebhooks{
+		Type:  enum.WebhookNewPost,
+		Props: webhook.Props{"post_number": 4, "post_title": "Add support for TypeScript"},
+	})
+	Expect(err).IsNil()
+
+	Expect(*added).HasLen(1)
+	Expect((*added)[0].Status).Equals(enum.WebhookDeliveryPending)
+	Expect((*added)[0].StatusCode).Equals(http.StatusServiceUnavailable)
+	Expect((*added)[0].NextAttemptAt).IsNotNil()
+	Expect((*added)[0].NextAttemptAt.After(time.Now().Add(50 * time.Second))).IsTrue()
+	Expect(*failed).HasLen(0)
+}
+
+func TestRetryWebhookDelivery_Success(t *testing.T) {
+	RegisterT(t)
+	_, updated, failed := setupWebhookTest(http.StatusOK)
+
+	err := bus.Dispatch(context.Background(), &cmd.RetryWebhookDelivery{
+		Delivery: &entity.WebhookDelivery{
+			ID:        5,
+			WebhookID: 1,
+			Status:    enum.WebhookDeliveryPending,
+			Attempts:  2,
+			Url:       "https://8.8.8.8/hooks/4",
+			Content:   "{}",
+		},
+	})
+	Expect(err).IsNil()
+
+	Expect(*updated).HasLen(1)
+	Expect((*updated)[0].ID).Equals(5)
+	Expect((*updated)[0].Status).Equals(enum.WebhookDeliverySuccess)
+	Expect((*updated)[0].Attempts).Equals(3)
+	Expect((*updated)[0].NextAttemptAt).IsNil()
+	Expect(*failed).HasLen(0)
+}
+
+func TestRetryWebhookDelivery_LastAttempt_DisablesWebhook(t *testing.T) {
+	RegisterT(t)
+	_, updated, failed := setupWebhookTest(http.StatusInternalServerError)
+
+	err := bus.Dispatch(context.Background(), &cmd.RetryWebhookDelivery{
+		Delivery: &entity.WebhookDelivery{
+			ID:        5,
+			WebhookID: 1,
+			Status:    enum.WebhookDeliveryPending,
+			Attempts:  env.Config.Webhook.MaxAttempts - 1,
+			Url:       "https://8.8.8.8/hooks/4",
+			Content:   "{}",
+		},
+	})
+	Expect(err).IsNil()
+
+	Expect(*updated).HasLen(1)
+	Expect((*updated)[0].Status).Equals(enum.WebhookDeliveryFailed)
+	Expect((*updated)[0].Attempts).Equals(env.Config.Webhook.MaxAttempts)
+	Expect((*updated)[0].NextAttemptAt).IsNil()
+	Expect(*failed).HasLen(1)
+	Expect((*failed)[0]).Equals(1)
+}
+
+func TestRedeliverWebhook(t *testing.T) {
+	RegisterT(t)
+	added, _, _ := setupWebhookTest(http.StatusOK)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetWebhookDelivery) error {
+		q.Result = &entity.WebhookDelivery{
+			ID:        q.ID,
+			WebhookID: q.WebhookID,
+			Status:    enum.WebhookDeliveryFailed,
+			Url:       "https://8.8.8.8/hooks/4",
+			Content:   `{"title": "Old"}`,
+		}
+		return nil
+	})
+
+	redeliver := &cmd.RedeliverWebhook{ID: 1, DeliveryID: 5}
+	err := bus.Dispatch(context.Background(), redeliver)
+	Expect(err).IsNil()
+	Expect(redeliver.Result.Success).IsTrue()
+
+	Expect(*added).HasLen(1)
+	Expect((*added)[0].Status).Equals(enum.WebhookDeliverySuccess)
+	Expect((*added)[0].Content).Equals(`{"title": "Old"}`)
+}
+
+func TestTriggerWebhooks_SignsRequest(t *testing.T) {
+	RegisterT(t)
+	setupWebhookTest(http.StatusOK)
+
+	var request *cmd.HTTPRequest
+	bus.AddHandler(func(ctx context.Context, c *cmd.HTTPRequest) error {
+		request = c
+		c.ResponseStatusCode = http.StatusOK
+		return nil
+	})
+
+	before := time.Now()
+	err := bus.Dispatch(context.Background(), &cmd.TriggerWebhooks{
+		Type:  enum.WebhookNewPost,
+		Props: webhook.Props{"post_number": 4, "post_title": "Add support for TypeScript"},
+	})
+	Expect(err).IsNil()
+
+	Expect(request).IsNotNil()
+	Expect(request.Headers["Authorization"]).Equals("Bearer token")
+
+	signature := request.Headers[webhook.SignatureHeader]
+	content := `{"title": "Add support for TypeScript"}`
+	Expect(signature == webhook.Sign("s3cr3t", before, content) || signature == webhook.Sign("s3cr3t", time.Now(), content)).IsTrue()
+
+	// Headers configured on the webhook must not be modified by the signature
+	Expect(newPostWebhook.HttpHeaders).HasLen(1)
+}
@@ -35,5 +35,5 @@ func SendChangeEmailConfirmation(action *actions.ChangeUserEmail) worker.Task {
 		})
 
 		return nil
-	})
+	}).WithArgs(SendChangeEmailConfirmation, action)
 }
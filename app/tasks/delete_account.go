@@ -31,5 +31,5 @@ func SendDeleteAccountScheduledEmail(owner *entity.User, tenantName string, sche
 		})
 
 		return nil
-	})
+	}).WithArgs(SendDeleteAccountScheduledEmail, owner, tenantName, scheduledAt, baseURL, cancelKey)
 }
@@ -92,5 +92,5 @@ func NotifyAboutDeletedPost(post *entity.Post, deleteCommentAdded bool) worker.T
 		})
 
 		return nil
-	})
+	}).WithArgs(NotifyAboutDeletedPost, post, deleteCommentAdded)
 }
@@ -0,0 +1,55 @@
+package tasks
+
+import (
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/web"
+	"github.com/getfider/fider/app/pkg/worker"
+)
+
+// deferToDigest stores the notification for users that prefer a daily or weekly digest
+// and returns the users that should receive the email right away
+func deferToDigest(c *worker.Context, users []*entity.User, event enum.NotificationEvent, post *entity.Post, content string) ([]*entity.User, error) {
+	if len(users) == 0 {
+		return users, nil
+	}
+
+	userIDs := make([]int, len(users))
+	for i, user := range users {
+		userIDs[i] = user.ID
+	}
+
+	frequencies := &query.GetEmailDigestFrequencies{UserIDs: userIDs}
+	if err := bus.Dispatch(c, frequencies); err != nil {
+		return nil, err
+	}
+
+	author := c.User()
+	baseURL := web.BaseURL(c)
+	immediate := make([]*entity.User, 0, len(users))
+	for _, user := range users {
+		frequency := frequencies.Result[user.ID]
+		if !frequency.IsDigest() {
+			immediate = append(immediate, user)
+			continue
+		}
+
+		err := bus.Dispatch(c, &cmd.AddEmailDigestItem{
+			UserID:     user.ID,
+			Frequency:  frequency,
+			Event:      event,
+			Post:       post,
+			AuthorName: author.Name,
+			Content:    content,
+			BaseURL:    baseURL,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return immediate, nil
+}
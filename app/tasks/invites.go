@@ -49,5 +49,5 @@ func SendInvites(subject, message string, invitations []*actions.UserInvitation)
 		})
 
 		return nil
-	})
+	}).WithArgs(SendInvites, subject, message, invitations)
 }
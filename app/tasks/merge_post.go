@@ -0,0 +1,98 @@
+package tasks
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/i18n"
+	"github.com/getfider/fider/app/pkg/web"
+	"github.com/getfider/fider/app/pkg/worker"
+)
+
+// NotifyAboutMergedPost sends a notification (web and email) to users whose votes were moved to the original post, respecting their notification settings
+func NotifyAboutMergedPost(post *entity.Post, original *entity.Post, voters []*entity.User) worker.Task {
+	return describe("Notify about merged post", func(c *worker.Context) error {
+		author := c.User()
+		title := fmt.Sprintf("**%s** merged **%s** into **%s**", author.Name, post.Title, original.Title)
+		link := fmt.Sprintf("/posts/%d/%s", original.Number, original.Slug)
+
+		voterIDs := make([]int, len(voters))
+		for i, voter := range voters {
+			voterIDs[i] = voter.ID
+		}
+
+		// Web notification
+		users, err := getActiveSubscribers(c, original, enum.NotificationChannelWeb, enum.NotificationEventChangeStatus, voterIDs...)
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		for _, user := range movedVoters(users, voterIDs, author) {
+			err = bus.Dispatch(c, &cmd.AddNewNotification{
+				User:   user,
+				Title:  title,
+				Link:   link,
+				PostID: original.ID,
+			})
+			if err != nil {
+				return c.Failure(err)
+			}
+		}
+
+		// Email notification
+		users, err = getActiveSubscribers(c, original, enum.NotificationChannelEmail, enum.NotificationEventChangeStatus, voterIDs...)
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		recipients, err := deferToDigest(c, movedVoters(users, voterIDs, author), enum.NotificationEventChangeStatus, original, title)
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		if len(recipients) == 0 {
+			return nil
+		}
+
+		to := make([]dto.Recipient, 0)
+		for _, user := range recipients {
+			to = append(to, dto.NewRecipient(user.Name, user.Email, dto.Props{}))
+		}
+
+		baseURL := web.BaseURL(c)
+		props := dto.Props{
+			"title":    post.Title,
+			"original": original.Title,
+			"postLink": linkWithText(fmt.Sprintf("#%d", original.Number), baseURL, "/posts/%d/%s", original.Number, original.Slug),
+			"siteName": c.Tenant().Name,
+			"view":     linkWithText(i18n.T(c, "email.subscription.view"), baseURL, "/posts/%d/%s", original.Number, original.Slug),
+			"change":   linkWithText(i18n.T(c, "email.subscription.change"), baseURL, "/settings"),
+			"logo":     web.LogoURL(c),
+		}
+
+		bus.Publish(c, &cmd.SendMail{
+			From:         dto.Recipient{Name: author.Name},
+			To:           to,
+			TemplateName: "merge_post",
+			Props:        props,
+		})
+
+		return nil
+	}).WithArgs(NotifyAboutMergedPost, post, original, voters)
+}
+
+// movedVoters keeps the subscribers whose votes were moved, leaving out the author of the merge
+func movedVoters(users []*entity.User, voterIDs []int, author *entity.User) []*entity.User {
+	result := make([]*entity.User, 0)
+	for _, user := range users {
+		if user.ID != author.ID && slices.Contains(voterIDs, user.ID) {
+			result = append(result, user)
+		}
+	}
+	return result
+}
@@ -0,0 +1,147 @@
+package tasks_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/mock"
+	"github.com/getfider/fider/app/services/email/emailmock"
+	"github.com/getfider/fider/app/tasks"
+)
+
+func TestNotifyAboutMergedPostTask(t *testing.T) {
+	RegisterT(t)
+	bus.Init(emailmock.Service{})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetEmailDigestFrequencies) error {
+		return nil
+	})
+
+	var participants []int
+	bus.AddHandler(func(ctx context.Context, q *query.GetActiveSubscribers) error {
+		participants = q.Participants
+		q.Result = []*entity.User{mock.JonSnow, mock.AryaStark}
+		return nil
+	})
+
+	notifications := make([]*cmd.AddNewNotification, 0)
+	bus.AddHandler(func(ctx context.Context, c *cmd.AddNewNotification) error {
+		notifications = append(notifications, c)
+		return nil
+	})
+
+	worker := mock.NewWorker()
+	post := &entity.Post{
+		ID:     2,
+		Number: 2,
+		Title:  "Support TypeScript",
+		Slug:   "support-typescript",
+		Status: enum.PostDuplicate,
+	}
+	original := &entity.Post{
+		ID:     1,
+		Number: 1,
+		Title:  "Add support for TypeScript",
+		Slug:   "add-support-for-typescript",
+		Status: enum.PostOpen,
+	}
+
+	task := tasks.NotifyAboutMergedPost(post, original, []*entity.User{mock.JonSnow, mock.AryaStark})
+
+	err := worker.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		WithBaseURL("http://domain.com").
+		Execute(task)
+
+	Expect(err).IsNil()
+	Expect(participants).Equals([]int{mock.JonSnow.ID, mock.AryaStark.ID})
+	Expect(emailmock.MessageHistory).HasLen(1)
+	Expect(emailmock.MessageHistory[0].TemplateName).Equals("merge_post")
+	Expect(emailmock.MessageHistory[0].Tenant).Equals(mock.DemoTenant)
+	Expect(emailmock.MessageHistory[0].Props).Equals(dto.Props{
+		"title":    "Support TypeScript",
+		"original": "Add support for TypeScript",
+		"postLink": "<a href='http://domain.com/posts/1/add-support-for-typescript'>#1</a>",
+		"siteName": "Demonstration",
+		"view":     "<a href='http://domain.com/posts/1/add-support-for-typescript'>view it on your browser</a>",
+		"change":   "<a href='http://domain.com/settings'>change your notification preferences</a>",
+		"logo":     "https://login.fider.io/static/assets/logo.png",
+	})
+	Expect(emailmock.MessageHistory[0].To).HasLen(1)
+	Expect(emailmock.MessageHistory[0].To[0]).Equals(dto.Recipient{
+		Name:    "Arya Stark",
+		Address: "arya.stark@got.com",
+		Props:   dto.Props{},
+	})
+
+	Expect(notifications).HasLen(1)
+	Expect(notifications[0].PostID).Equals(original.ID)
+	Expect(notifications[0].Link).Equals("/posts/1/add-support-for-typescript")
+	Expect(notifications[0].Title).Equals("**Jon Snow** merged **Support TypeScript** into **Add support for TypeScript**")
+	Expect(notifications[0].User).Equals(mock.AryaStark)
+}
+
+func TestNotifyAboutMergedPostTask_OnlyMovedVoters(t *testing.T) {
+	RegisterT(t)
+	bus.Init(emailmock.Service{})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetEmailDigestFrequencies) error {
+		return nil
+	})
+
+	// Jon Snow is subscribed to the original, but only the vote of Arya Stark was moved
+	bus.AddHandler(func(ctx context.Context, q *query.GetActiveSubscribers) error {
+		q.Result = []*entity.User{mock.JonSnow}
+		return nil
+	})
+
+	notifications := make([]*cmd.AddNewNotification, 0)
+	bus.AddHandler(func(ctx context.Context, c *cmd.AddNewNotification) error {
+		notifications = append(notifications, c)
+		return nil
+	})
+
+	worker := mock.NewWorker()
+	post := &entity.Post{ID: 2, Number: 2, Title: "Support TypeScript", Slug: "support-typescript"}
+	original := &entity.Post{ID: 1, Number: 1, Title: "Add support for TypeScript", Slug: "add-support-for-typescript"}
+
+	err := worker.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.AryaStark).
+		WithBaseURL("http://domain.com").
+		Execute(tasks.NotifyAboutMergedPost(post, original, []*entity.User{mock.AryaStark}))
+
+	Expect(err).IsNil()
+	Expect(notifications).HasLen(0)
+	Expect(emailmock.MessageHistory).HasLen(0)
+}
+
+func TestNotifyAboutMergedPostTask_NoVoters(t *testing.T) {
+	RegisterT(t)
+	bus.Init(emailmock.Service{})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetActiveSubscribers) error {
+		return nil
+	})
+
+	worker := mock.NewWorker()
+	post := &entity.Post{ID: 2, Number: 2, Title: "Support TypeScript", Slug: "support-typescript"}
+	original := &entity.Post{ID: 1, Number: 1, Title: "Add support for TypeScript", Slug: "add-support-for-typescript"}
+
+	err := worker.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		WithBaseURL("http://domain.com").
+		Execute(tasks.NotifyAboutMergedPost(post, original, []*entity.User{}))
+
+	Expect(err).IsNil()
+	Expect(emailmock.MessageHistory).HasLen(0)
+}
@@ -4,12 +4,14 @@ import (
 	"fmt"
 	"slices"
 
+	"github.com/getfider/fider/app"
 	"github.com/getfider/fider/app/models/cmd"
 	"github.com/getfider/fider/app/models/dto"
 	"github.com/getfider/fider/app/models/entity"
 	"github.com/getfider/fider/app/models/enum"
 	"github.com/getfider/fider/app/models/query"
 	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
 	"github.com/getfider/fider/app/pkg/i18n"
 	"github.com/getfider/fider/app/pkg/log"
 	"github.com/getfider/fider/app/pkg/markdown"
@@ -27,20 +29,40 @@ func NotifyAboutNewComment(comment *entity.Comment, post *entity.Post) worker.Ta
 		mentions := contentString.ParseMentions()
 		var mentionNotifications []*entity.MentionNotification
 
+		// The author of the comment being replied to is notified even without subscribing to the post
+		repliedTo, err := getRepliedToUser(c, comment)
+		if err != nil {
+			return c.Failure(err)
+		}
+		participants := make([]int, 0)
+		if repliedTo != nil {
+			participants = append(participants, repliedTo.ID)
+		}
+		isRepliedTo := func(user *entity.User) bool {
+			return repliedTo != nil && repliedTo.ID == user.ID
+		}
+
 		// Web notification
-		users, err := getActiveSubscribers(c, post, enum.NotificationChannelWeb, enum.NotificationEventNewComment)
+		users, err := getActiveSubscribers(c, post, enum.NotificationChannelWeb, enum.NotificationEventNewComment, participants...)
 		if err != nil {
 			return c.Failure(err)
 		}
+		users = commentAudience(comment, users)
 
 		author := c.User()
 		title := fmt.Sprintf("**%s** left a comment on **%s**", author.Name, post.Title)
+		replyTitle := fmt.Sprintf("**%s** replied to your comment on **%s**", author.Name, post.Title)
 		link := fmt.Sprintf("/posts/%d/%s", post.Number, post.Slug)
 		for _, user := range users {
 			if user.ID != author.ID {
+				userTitle := title
+				if isRepliedTo(user) {
+					userTitle = replyTitle
+				}
+
 				err = bus.Dispatch(c, &cmd.AddNewNotification{
 					User:   user,
-					Title:  title,
+					Title:  userTitle,
 					Link:   link,
 					PostID: post.ID,
 				})
@@ -59,6 +81,7 @@ func NotifyAboutNewComment(comment *entity.Comment, post *entity.Post) worker.Ta
 			if err != nil {
 				return c.Failure(err)
 			}
+			users = commentAudience(comment, users)
 
 			// Get the existing mentions that have been sent for this comment
 			mN := &query.GetMentionNotifications{
@@ -106,28 +129,58 @@ func NotifyAboutNewComment(comment *entity.Comment, post *entity.Post) worker.Ta
 		}
 
 		// Standard email notitifications
-		users, err = getActiveSubscribers(c, post, enum.NotificationChannelEmail, enum.NotificationEventNewComment)
+		users, err = getActiveSubscribers(c, post, enum.NotificationChannelEmail, enum.NotificationEventNewComment, participants...)
 		if err != nil {
 			return c.Failure(err)
 		}
+		users = commentAudience(comment, users)
 
-		to := make([]dto.Recipient, 0)
+		recipients := make([]*entity.User, 0)
+		replyRecipients := make([]*entity.User, 0)
 		for _, user := range users {
-			if user.ID != author.ID {
-				to = append(to, dto.NewRecipient(user.Name, user.Email, dto.Props{}))
+			if user.ID == author.ID {
+				continue
+			}
+			if isRepliedTo(user) {
+				replyRecipients = append(replyRecipients, user)
+			} else {
+				recipients = append(recipients, user)
 			}
 		}
 
+		recipients, err = deferToDigest(c, recipients, enum.NotificationEventNewComment, post, contentString.SanitizeMentions())
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		to := make([]dto.Recipient, 0)
+		for _, user := range recipients {
+			to = append(to, dto.NewRecipient(user.Name, user.Email, dto.Props{}))
+		}
+
 		sendEmailNotifications(c, post, to, contentString.SanitizeMentions(), enum.NotificationEventNewComment, "new_comment")
 
-		// Mentions
+		replyRecipients, err = deferToDigest(c, replyRecipients, enum.NotificationEventNewComment, post, contentString.SanitizeMentions())
+		if err != nil {
+			return c.Failure(err)
+		}
+
 		to = make([]dto.Recipient, 0)
+		for _, user := range replyRecipients {
+			to = append(to, dto.NewRecipient(user.Name, user.Email, dto.Props{}))
+		}
+
+		sendEmailMessage(c, post, to, contentString.SanitizeMentions(), "email.new_reply.text", "new_comment")
+
+		// Mentions
+		recipients = make([]*entity.User, 0)
 		if mentions != nil {
 
 			users, err = getActiveSubscribers(c, post, enum.NotificationChannelEmail, enum.NotificationEventMention)
 			if err != nil {
 				return c.Failure(err)
 			}
+			users = commentAudience(comment, users)
 
 			for _, mention := range mentions {
 				for _, u := range users {
@@ -136,7 +189,7 @@ func NotifyAboutNewComment(comment *entity.Comment, post *entity.Post) worker.Ta
 						func(n *entity.MentionNotification) bool {
 							return n.UserID == u.ID
 						}) {
-						to = append(to, dto.NewRecipient(u.Name, u.Email, dto.Props{}))
+						recipients = append(recipients, u)
 
 						// Also send the notification log
 						err = bus.Dispatch(c, &cmd.AddMentionNotification{
@@ -152,13 +205,28 @@ func NotifyAboutNewComment(comment *entity.Comment, post *entity.Post) worker.Ta
 
 		}
 
+		recipients, err = deferToDigest(c, recipients, enum.NotificationEventMention, post, contentString.SanitizeMentions())
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		to = make([]dto.Recipient, 0)
+		for _, user := range recipients {
+			to = append(to, dto.NewRecipient(user.Name, user.Email, dto.Props{}))
+		}
+
 		sendEmailNotifications(c, post, to, contentString.SanitizeMentions(), enum.NotificationEventMention, "new_comment")
 
+		// Internal notes are never sent outside of Fider
+		if comment.IsInternal {
+			return nil
+		}
+
 		tenant := c.Tenant()
 		baseURL, logoURL := web.BaseURL(c), web.LogoURL(c)
 
-		webhookProps := webhook.Props{"comment": contentString.SanitizeMentions()}
-		webhookProps["comment_id"] = comment.ID
+		webhookProps := webhook.Props{}
+		webhookProps.SetComment(comment, "comment")
 		webhookProps.SetPost(post, "post", baseURL, true, true)
 		webhookProps.SetUser(author, "author")
 		webhookProps.SetTenant(tenant, "tenant", baseURL, logoURL)
@@ -172,7 +240,7 @@ func NotifyAboutNewComment(comment *entity.Comment, post *entity.Post) worker.Ta
 		}
 
 		return nil
-	})
+	}).WithArgs(NotifyAboutNewComment, comment, post)
 }
 
 func NotifyAboutUpdatedComment(post *entity.Post, comment *entity.Comment) worker.Task {
@@ -197,6 +265,7 @@ func NotifyAboutUpdatedComment(post *entity.Post, comment *entity.Comment) worke
 			if err != nil {
 				return c.Failure(err)
 			}
+			users = commentAudience(comment, users)
 
 			// Get the existing mentions that have been sent for this comment
 			mN := &query.GetMentionNotifications{
@@ -241,13 +310,14 @@ func NotifyAboutUpdatedComment(post *entity.Post, comment *entity.Comment) worke
 
 		}
 
-		to := make([]dto.Recipient, 0)
+		recipients := make([]*entity.User, 0)
 		if mentions != nil {
 
 			users, err := getActiveSubscribers(c, post, enum.NotificationChannelEmail, enum.NotificationEventMention)
 			if err != nil {
 				return c.Failure(err)
 			}
+			users = commentAudience(comment, users)
 
 			for _, mention := range mentions {
 				// Check if the user is in the list of mention subscribers (users)
@@ -256,7 +326,7 @@ func NotifyAboutUpdatedComment(post *entity.Post, comment *entity.Comment) worke
 						func(n *entity.MentionNotification) bool {
 							return n.UserID == u.ID
 						}) {
-						to = append(to, dto.NewRecipient(u.Name, u.Email, dto.Props{}))
+						recipients = append(recipients, u)
 
 						// Also send the notification log
 						if !mentionNotificationSent {
@@ -273,13 +343,97 @@ func NotifyAboutUpdatedComment(post *entity.Post, comment *entity.Comment) worke
 			}
 		}
 
+		recipients, err := deferToDigest(c, recipients, enum.NotificationEventMention, post, contentString.SanitizeMentions())
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		to := make([]dto.Recipient, 0)
+		for _, user := range recipients {
+			to = append(to, dto.NewRecipient(user.Name, user.Email, dto.Props{}))
+		}
+
 		sendEmailNotifications(c, post, to, contentString.SanitizeMentions(), enum.NotificationEventMention, "new_comment")
 
+		if comment.IsInternal {
+			return nil
+		}
+
+		webhookProps := webhook.Props{}
+		webhookProps.SetComment(comment, "comment")
+		webhookProps.SetPost(post, "post", web.BaseURL(c), true, true)
+
+		if err := triggerWebhooks(c, enum.WebhookEditComment, webhookProps); err != nil {
+			return c.Failure(err)
+		}
+
 		return nil
-	})
+	}).WithArgs(NotifyAboutUpdatedComment, post, comment)
+}
+
+// NotifyAboutDeletedComment triggers webhooks when a comment is deleted
+func NotifyAboutDeletedComment(post *entity.Post, comment *entity.Comment) worker.Task {
+	return describe("Notify about deleted comment", func(c *worker.Context) error {
+		if comment.IsInternal {
+			return nil
+		}
+
+		webhookProps := webhook.Props{}
+		webhookProps.SetComment(comment, "comment")
+		webhookProps.SetPost(post, "post", web.BaseURL(c), true, true)
+
+		if err := triggerWebhooks(c, enum.WebhookDeleteComment, webhookProps); err != nil {
+			return c.Failure(err)
+		}
+
+		return nil
+	}).WithArgs(NotifyAboutDeletedComment, post, comment)
+}
+
+// getRepliedToUser returns the author of the comment given comment replies to,
+// or nil when it's not a reply or the replied comment was deleted
+func getRepliedToUser(c *worker.Context, comment *entity.Comment) (*entity.User, error) {
+	if comment.ParentID == nil {
+		return nil, nil
+	}
+
+	getParent := &query.GetCommentByID{CommentID: *comment.ParentID}
+	err := bus.Dispatch(c, getParent)
+	if errors.Cause(err) == app.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return getParent.Result.User, nil
+}
+
+// commentAudience removes the users who can't see given comment, as internal notes are only visible to staff
+func commentAudience(comment *entity.Comment, users []*entity.User) []*entity.User {
+	if !comment.IsInternal {
+		return users
+	}
+
+	staff := make([]*entity.User, 0)
+	for _, user := range users {
+		if user.IsCollaborator() {
+			staff = append(staff, user)
+		}
+	}
+	return staff
 }
 
 func sendEmailNotifications(c *worker.Context, post *entity.Post, to []dto.Recipient, comment string, event enum.NotificationEvent, templateName string) {
+	messaleLocaleString := "email.new_comment.text"
+	if event.UserSettingsKeyName == enum.NotificationEventMention.UserSettingsKeyName {
+		messaleLocaleString = "email.new_mention.text"
+	}
+
+	sendEmailMessage(c, post, to, comment, messaleLocaleString, templateName)
+}
+
+// sendEmailMessage sends an email about a post to given recipients, with the message of given locale string
+func sendEmailMessage(c *worker.Context, post *entity.Post, to []dto.Recipient, comment string, messageLocaleString string, templateName string) {
 	// Short circuit if there is no one to notify
 	if len(to) == 0 {
 		return
@@ -288,14 +442,10 @@ func sendEmailNotifications(c *worker.Context, post *entity.Post, to []dto.Recip
 	author := c.User()
 	tenant := c.Tenant()
 	baseURL, logoURL := web.BaseURL(c), web.LogoURL(c)
-	messaleLocaleString := "email.new_comment.text"
-	if event.UserSettingsKeyName == enum.NotificationEventMention.UserSettingsKeyName {
-		messaleLocaleString = "email.new_mention.text"
-	}
 
 	mailProps := dto.Props{
 		"title":               post.Title,
-		"messageLocaleString": messaleLocaleString,
+		"messageLocaleString": messageLocaleString,
 		"siteName":            tenant.Name,
 		"userName":            author.Name,
 		"content":             markdown.Full(comment, false),
@@ -25,6 +25,10 @@ func TestNotifyAboutNewCommentTask(t *testing.T) {
 	RegisterT(t)
 	bus.Init(emailmock.Service{})
 
+	bus.AddHandler(func(ctx context.Context, q *query.GetEmailDigestFrequencies) error {
+		return nil
+	})
+
 	var addNewNotification *cmd.AddNewNotification
 	bus.AddHandler(func(ctx context.Context, c *cmd.AddNewNotification) error {
 		addNewNotification = c
@@ -136,10 +140,157 @@ func TestNotifyAboutNewCommentTask(t *testing.T) {
 	Expect(addNotificationLogs).HasLen(0)
 }
 
+func TestNotifyAboutNewCommentTask_InternalNote(t *testing.T) {
+	RegisterT(t)
+	bus.Init(emailmock.Service{})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetEmailDigestFrequencies) error {
+		return nil
+	})
+
+	addNewNotifications := make([]*cmd.AddNewNotification, 0)
+	bus.AddHandler(func(ctx context.Context, c *cmd.AddNewNotification) error {
+		addNewNotifications = append(addNewNotifications, c)
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetMentionNotifications) error {
+		q.Result = []*entity.MentionNotification{}
+		return nil
+	})
+
+	sansaStark := &entity.User{ID: 3, Name: "Sansa Stark", Email: "sansa.stark@got.com", Role: enum.RoleCollaborator}
+	bus.AddHandler(func(ctx context.Context, q *query.GetActiveSubscribers) error {
+		if q.Event.UserSettingsKeyName == "event_notification_new_comment" {
+			q.Result = []*entity.User{mock.AryaStark, sansaStark}
+		} else {
+			q.Result = []*entity.User{}
+		}
+		return nil
+	})
+
+	var triggerWebhooks *cmd.TriggerWebhooks
+	bus.AddHandler(func(ctx context.Context, c *cmd.TriggerWebhooks) error {
+		triggerWebhooks = c
+		return nil
+	})
+
+	worker := mock.NewWorker()
+	post := &entity.Post{
+		ID:     1,
+		Number: 1,
+		Title:  "Add support for TypeScript",
+		Slug:   "add-support-for-typescript",
+		User:   mock.AryaStark,
+	}
+	task := tasks.NotifyAboutNewComment(&entity.Comment{Content: "Customer is on the enterprise plan", IsInternal: true}, post)
+
+	err := worker.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		WithBaseURL("http://domain.com").
+		Execute(task)
+
+	Expect(err).IsNil()
+	Expect(emailmock.MessageHistory).HasLen(1)
+	Expect(emailmock.MessageHistory[0].To).HasLen(1)
+	Expect(emailmock.MessageHistory[0].To[0].Address).Equals("sansa.stark@got.com")
+
+	Expect(addNewNotifications).HasLen(1)
+	Expect(addNewNotifications[0].User).Equals(sansaStark)
+
+	Expect(triggerWebhooks).IsNil()
+}
+
+func TestNotifyAboutNewCommentTask_Reply(t *testing.T) {
+	RegisterT(t)
+	bus.Init(emailmock.Service{})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetEmailDigestFrequencies) error {
+		return nil
+	})
+
+	addNewNotifications := make([]*cmd.AddNewNotification, 0)
+	bus.AddHandler(func(ctx context.Context, c *cmd.AddNewNotification) error {
+		addNewNotifications = append(addNewNotifications, c)
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCommentByID) error {
+		q.Result = &entity.Comment{ID: q.CommentID, Content: "Please add TypeScript", User: mock.AryaStark}
+		return nil
+	})
+
+	// Arya is not subscribed to the post, but she's notified because she's a participant
+	sansaStark := &entity.User{ID: 3, Name: "Sansa Stark", Email: "sansa.stark@got.com", Role: enum.RoleCollaborator}
+	var getSubscribers *query.GetActiveSubscribers
+	bus.AddHandler(func(ctx context.Context, q *query.GetActiveSubscribers) error {
+		if q.Event.UserSettingsKeyName != "event_notification_new_comment" {
+			return nil
+		}
+
+		getSubscribers = q
+		q.Result = []*entity.User{sansaStark}
+		for _, id := range q.Participants {
+			if id == mock.AryaStark.ID {
+				q.Result = append(q.Result, mock.AryaStark)
+			}
+		}
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetMentionNotifications) error {
+		q.Result = []*entity.MentionNotification{}
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, c *cmd.TriggerWebhooks) error {
+		return nil
+	})
+
+	worker := mock.NewWorker()
+	post := &entity.Post{
+		ID:     1,
+		Number: 1,
+		Title:  "Add support for TypeScript",
+		Slug:   "add-support-for-typescript",
+		User:   mock.AryaStark,
+	}
+	parentID := 5
+	task := tasks.NotifyAboutNewComment(&entity.Comment{ID: 6, Content: "It's planned", ParentID: &parentID}, post)
+
+	err := worker.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		WithBaseURL("http://domain.com").
+		Execute(task)
+
+	Expect(err).IsNil()
+	Expect(getSubscribers.Participants).Equals([]int{mock.AryaStark.ID})
+
+	Expect(addNewNotifications).HasLen(2)
+	Expect(addNewNotifications[0].User).Equals(sansaStark)
+	Expect(addNewNotifications[0].Title).Equals("**Jon Snow** left a comment on **Add support for TypeScript**")
+	Expect(addNewNotifications[1].User).Equals(mock.AryaStark)
+	Expect(addNewNotifications[1].Title).Equals("**Jon Snow** replied to your comment on **Add support for TypeScript**")
+
+	Expect(emailmock.MessageHistory).HasLen(2)
+	Expect(emailmock.MessageHistory[0].Props["messageLocaleString"]).Equals("email.new_comment.text")
+	Expect(emailmock.MessageHistory[0].To).HasLen(1)
+	Expect(emailmock.MessageHistory[0].To[0].Address).Equals("sansa.stark@got.com")
+	Expect(emailmock.MessageHistory[1].Props["messageLocaleString"]).Equals("email.new_reply.text")
+	Expect(emailmock.MessageHistory[1].To).HasLen(1)
+	Expect(emailmock.MessageHistory[1].To[0].Address).Equals("arya.stark@got.com")
+}
+
 func TestNotifyAboutNewCommentTask_WithMention(t *testing.T) {
 	RegisterT(t)
 	bus.Init(emailmock.Service{})
 
+	bus.AddHandler(func(ctx context.Context, q *query.GetEmailDigestFrequencies) error {
+		return nil
+	})
+
 	var addNewNotification *cmd.AddNewNotification
 	bus.AddHandler(func(ctx context.Context, c *cmd.AddNewNotification) error {
 		addNewNotification = c
@@ -261,6 +412,10 @@ func TestNotifyAboutUpdatedComment(t *testing.T) {
 	RegisterT(t)
 	bus.Init(emailmock.Service{})
 
+	bus.AddHandler(func(ctx context.Context, q *query.GetEmailDigestFrequencies) error {
+		return nil
+	})
+
 	var addNewNotification *cmd.AddNewNotification
 	bus.AddHandler(func(ctx context.Context, c *cmd.AddNewNotification) error {
 		addNewNotification = c
@@ -285,6 +440,12 @@ func TestNotifyAboutUpdatedComment(t *testing.T) {
 		return nil
 	})
 
+	var triggerWebhooks *cmd.TriggerWebhooks
+	bus.AddHandler(func(ctx context.Context, c *cmd.TriggerWebhooks) error {
+		triggerWebhooks = c
+		return nil
+	})
+
 	worker := mock.NewWorker()
 	post := &entity.Post{
 		ID:          1,
@@ -346,12 +507,28 @@ func TestNotifyAboutUpdatedComment(t *testing.T) {
 	Expect(addNotificationLogs[0].UserID).Equals(mock.JonSnow.ID)
 	Expect(addNotificationLogs[0].CommentID).Equals(1)
 
+	Expect(triggerWebhooks).IsNotNil()
+	Expect(triggerWebhooks.Type).Equals(enum.WebhookEditComment)
+	Expect(triggerWebhooks.Props).ContainsProps(webhook.Props{
+		"comment":             "I agree with @Jon Snow but not @Arya Stark",
+		"comment_id":          comment.ID,
+		"comment_author_id":   mock.AryaStark.ID,
+		"comment_author_name": mock.AryaStark.Name,
+		"post_id":             post.ID,
+		"post_number":         post.Number,
+		"author_id":           mock.AryaStark.ID,
+		"tenant_id":           mock.DemoTenant.ID,
+	})
 }
 
 func TestNotifyAboutUpdatedComment_UserAlreadyMentioned(t *testing.T) {
 	RegisterT(t)
 	bus.Init(emailmock.Service{})
 
+	bus.AddHandler(func(ctx context.Context, q *query.GetEmailDigestFrequencies) error {
+		return nil
+	})
+
 	var addNewNotification *cmd.AddNewNotification
 	bus.AddHandler(func(ctx context.Context, c *cmd.AddNewNotification) error {
 		addNewNotification = c
@@ -376,6 +553,12 @@ func TestNotifyAboutUpdatedComment_UserAlreadyMentioned(t *testing.T) {
 		return nil
 	})
 
+	var triggerWebhooks *cmd.TriggerWebhooks
+	bus.AddHandler(func(ctx context.Context, c *cmd.TriggerWebhooks) error {
+		triggerWebhooks = c
+		return nil
+	})
+
 	worker := mock.NewWorker()
 	post := &entity.Post{
 		ID:          1,
@@ -405,4 +588,63 @@ func TestNotifyAboutUpdatedComment_UserAlreadyMentioned(t *testing.T) {
 	Expect(emailmock.MessageHistory).HasLen(0)
 
 	Expect(addNewNotification).IsNil()
+
+	Expect(triggerWebhooks).IsNotNil()
+	Expect(triggerWebhooks.Type).Equals(enum.WebhookEditComment)
+}
+
+func TestNotifyAboutDeletedComment(t *testing.T) {
+	RegisterT(t)
+	bus.Init(emailmock.Service{})
+
+	var triggerWebhooks *cmd.TriggerWebhooks
+	bus.AddHandler(func(ctx context.Context, c *cmd.TriggerWebhooks) error {
+		triggerWebhooks = c
+		return nil
+	})
+
+	worker := mock.NewWorker()
+	post := &entity.Post{
+		ID:          1,
+		Number:      1,
+		Title:       "Add support for TypeScript",
+		Slug:        "add-support-for-typescript",
+		Description: "TypeScript is great, please add support for it",
+		User:        mock.JonSnow,
+	}
+
+	comment := &entity.Comment{
+		ID:        2,
+		Content:   "I agree",
+		CreatedAt: time.Now(),
+		User:      mock.AryaStark,
+	}
+
+	task := tasks.NotifyAboutDeletedComment(post, comment)
+
+	err := worker.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		WithBaseURL("http://domain.com").
+		Execute(task)
+
+	Expect(err).IsNil()
+	Expect(emailmock.MessageHistory).HasLen(0)
+
+	Expect(triggerWebhooks).IsNotNil()
+	Expect(triggerWebhooks.Type).Equals(enum.WebhookDeleteComment)
+	Expect(triggerWebhooks.Props).ContainsProps(webhook.Props{
+		"comment":              "I agree",
+		"comment_id":           comment.ID,
+		"comment_author_id":    mock.AryaStark.ID,
+		"comment_author_name":  mock.AryaStark.Name,
+		"comment_author_email": mock.AryaStark.Email,
+		"post_id":              post.ID,
+		"post_number":          post.Number,
+		"post_url":             "http://domain.com/posts/1/add-support-for-typescript",
+		"author_id":            mock.JonSnow.ID,
+		"author_name":          mock.JonSnow.Name,
+		"tenant_id":            mock.DemoTenant.ID,
+		"tenant_url":           "http://domain.com",
+	})
 }
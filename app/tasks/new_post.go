@@ -179,7 +179,7 @@ func NotifyAboutNewPost(post *entity.Post) worker.Task {
 		}
 
 		return nil
-	})
+	}).WithArgs(NotifyAboutNewPost, post)
 }
 
 // NotifyAboutUpdatedPost sends notifications about mentions in an updated post
@@ -280,6 +280,13 @@ func NotifyAboutUpdatedPost(post *entity.Post) worker.Task {
 		// Send email notifications for mentions
 		sendEmailNotifications(c, post, to, contentString.SanitizeMentions(), enum.NotificationEventMention, "new_comment")
 
+		webhookProps := webhook.Props{}
+		webhookProps.SetPost(post, "post", web.BaseURL(c), true, true)
+
+		if err := triggerWebhooks(c, enum.WebhookEditPost, webhookProps); err != nil {
+			return c.Failure(err)
+		}
+
 		return nil
-	})
+	}).WithArgs(NotifyAboutUpdatedPost, post)
 }
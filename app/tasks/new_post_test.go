@@ -110,6 +110,50 @@ func TestNotifyAboutNewPostTask(t *testing.T) {
 	})
 }
 
+func TestNotifyAboutNewPostTask_PrivateBoard(t *testing.T) {
+	RegisterT(t)
+	bus.Init(emailmock.Service{})
+
+	sansaStark := &entity.User{ID: 4, Name: "Sansa Stark", Email: "sansa.stark@got.com", Role: enum.RoleCollaborator}
+
+	notified := make([]*entity.User, 0)
+	bus.AddHandler(func(ctx context.Context, c *cmd.AddNewNotification) error {
+		notified = append(notified, c.User)
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetActiveSubscribers) error {
+		q.Result = []*entity.User{mock.AryaStark, sansaStark}
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, c *cmd.TriggerWebhooks) error {
+		return nil
+	})
+
+	post := &entity.Post{
+		ID:          1,
+		Number:      1,
+		Title:       "Faster deploys",
+		Slug:        "faster-deploys",
+		Description: "Deploys take too long",
+		Board:       &entity.PostBoard{ID: 1, Name: "Internal", Slug: "internal", IsPublic: false},
+	}
+
+	err := mock.NewWorker().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		WithBaseURL("http://domain.com").
+		Execute(tasks.NotifyAboutNewPost(post))
+
+	Expect(err).IsNil()
+	Expect(notified).HasLen(1)
+	Expect(notified[0]).Equals(sansaStark)
+	Expect(emailmock.MessageHistory).HasLen(1)
+	Expect(emailmock.MessageHistory[0].To).HasLen(1)
+	Expect(emailmock.MessageHistory[0].To[0].Address).Equals("sansa.stark@got.com")
+}
+
 func TestNotifyAboutNewPostTask_WithMention(t *testing.T) {
 	RegisterT(t)
 	bus.Init(emailmock.Service{})
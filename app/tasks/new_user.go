@@ -0,0 +1,22 @@
+package tasks
+
+import (
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/pkg/webhook"
+	"github.com/getfider/fider/app/pkg/worker"
+)
+
+// NotifyAboutNewUser triggers webhooks when a new user registers
+func NotifyAboutNewUser(user *entity.User) worker.Task {
+	return describe("Notify about new user", func(c *worker.Context) error {
+		webhookProps := webhook.Props{}
+		webhookProps.SetUser(user, "user")
+
+		if err := triggerWebhooks(c, enum.WebhookNewUser, webhookProps); err != nil {
+			return c.Failure(err)
+		}
+
+		return nil
+	}).WithArgs(NotifyAboutNewUser, user)
+}
@@ -0,0 +1,48 @@
+package tasks_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/enum"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/mock"
+	"github.com/getfider/fider/app/pkg/webhook"
+	"github.com/getfider/fider/app/services/email/emailmock"
+	"github.com/getfider/fider/app/tasks"
+)
+
+func TestNotifyAboutNewUserTask(t *testing.T) {
+	RegisterT(t)
+	bus.Init(emailmock.Service{})
+
+	var triggerWebhooks *cmd.TriggerWebhooks
+	bus.AddHandler(func(ctx context.Context, c *cmd.TriggerWebhooks) error {
+		triggerWebhooks = c
+		return nil
+	})
+
+	worker := mock.NewWorker()
+	task := tasks.NotifyAboutNewUser(mock.AryaStark)
+
+	err := worker.
+		OnTenant(mock.DemoTenant).
+		WithBaseURL("http://domain.com").
+		Execute(task)
+
+	Expect(err).IsNil()
+	Expect(triggerWebhooks).IsNotNil()
+	Expect(triggerWebhooks.Type).Equals(enum.WebhookNewUser)
+	Expect(triggerWebhooks.Props).ContainsProps(webhook.Props{
+		"user_id":          mock.AryaStark.ID,
+		"user_name":        mock.AryaStark.Name,
+		"user_email":       mock.AryaStark.Email,
+		"user_role":        mock.AryaStark.Role.String(),
+		"tenant_id":        mock.DemoTenant.ID,
+		"tenant_subdomain": mock.DemoTenant.Subdomain,
+	})
+	_, hasAuthor := triggerWebhooks.Props["author_id"]
+	Expect(hasAuthor).IsFalse()
+}
@@ -27,5 +27,5 @@ func SendSignInEmail(email, linkKey, code string) worker.Task {
 		})
 
 		return nil
-	})
+	}).WithArgs(SendSignInEmail, email, linkKey, code)
 }
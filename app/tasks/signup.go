@@ -32,5 +32,32 @@ func SendSignUpEmail(data SignUpEmailData, baseURL string) worker.Task {
 		})
 
 		return nil
-	})
+	}).WithArgs(SendSignUpEmail, snapshotSignUpEmailData(data), baseURL)
+}
+
+// signUpEmailData is a copy of SignUpEmailData that can be persisted along with the task
+type signUpEmailData struct {
+	Email           string
+	Name            string
+	VerificationKey string
+}
+
+func snapshotSignUpEmailData(data SignUpEmailData) *signUpEmailData {
+	return &signUpEmailData{
+		Email:           data.GetEmail(),
+		Name:            data.GetName(),
+		VerificationKey: data.GetVerificationKey(),
+	}
+}
+
+func (d *signUpEmailData) GetEmail() string {
+	return d.Email
+}
+
+func (d *signUpEmailData) GetName() string {
+	return d.Name
+}
+
+func (d *signUpEmailData) GetVerificationKey() string {
+	return d.VerificationKey
 }
@@ -25,6 +25,10 @@ func TestNotifyAboutStatusChangeTask(t *testing.T) {
 	RegisterT(t)
 	bus.Init(emailmock.Service{})
 
+	bus.AddHandler(func(ctx context.Context, q *query.GetEmailDigestFrequencies) error {
+		return nil
+	})
+
 	var addNewNotification *cmd.AddNewNotification
 	bus.AddHandler(func(ctx context.Context, c *cmd.AddNewNotification) error {
 		addNewNotification = c
@@ -138,6 +142,10 @@ func TestNotifyAboutStatusChangeTask_Duplicate(t *testing.T) {
 	RegisterT(t)
 	bus.Init(emailmock.Service{})
 
+	bus.AddHandler(func(ctx context.Context, q *query.GetEmailDigestFrequencies) error {
+		return nil
+	})
+
 	var addNewNotification *cmd.AddNewNotification
 	bus.AddHandler(func(ctx context.Context, c *cmd.AddNewNotification) error {
 		addNewNotification = c
@@ -255,3 +263,65 @@ func TestNotifyAboutStatusChangeTask_Duplicate(t *testing.T) {
 		"tenant_url":                    "http://domain.com",
 	})
 }
+
+func TestNotifyAboutMergedStatusChangeTask(t *testing.T) {
+	RegisterT(t)
+	bus.Init(emailmock.Service{})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetEmailDigestFrequencies) error {
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, c *cmd.AddNewNotification) error {
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, c *cmd.TriggerWebhooks) error {
+		return nil
+	})
+
+	// The subscribers of the merged post have been moved to the original
+	subscribersOf := make([]int, 0)
+	bus.AddHandler(func(ctx context.Context, q *query.GetActiveSubscribers) error {
+		subscribersOf = append(subscribersOf, q.Number)
+		q.Result = []*entity.User{
+			mock.AryaStark,
+		}
+		return nil
+	})
+
+	worker := mock.NewWorker()
+	original := &entity.Post{ID: 1, Number: 1, Title: "Add support for TypeScript", Slug: "add-support-for-typescript", Status: enum.PostOpen}
+	post := &entity.Post{
+		ID:     2,
+		Number: 2,
+		Title:  "I need TypeScript",
+		Slug:   "i-need-typescript",
+		User:   mock.AryaStark,
+		Status: enum.PostDuplicate,
+		Response: &entity.PostResponse{
+			RespondedAt: time.Now(),
+			User:        mock.JonSnow,
+			Original: &entity.OriginalPost{
+				Number: original.Number,
+				Title:  original.Title,
+				Slug:   original.Slug,
+				Status: original.Status,
+			},
+		},
+	}
+
+	err := worker.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		WithBaseURL("http://domain.com").
+		Execute(tasks.NotifyAboutMergedStatusChange(post, original, enum.PostOpen))
+
+	Expect(err).IsNil()
+	Expect(subscribersOf).Equals([]int{1, 1})
+	Expect(emailmock.MessageHistory).HasLen(1)
+	Expect(emailmock.MessageHistory[0].TemplateName).Equals("change_status")
+	Expect(emailmock.MessageHistory[0].Props["title"]).Equals("I need TypeScript")
+	Expect(emailmock.MessageHistory[0].To).HasLen(1)
+	Expect(emailmock.MessageHistory[0].To[0].Address).Equals("arya.stark@got.com")
+}
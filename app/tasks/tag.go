@@ -0,0 +1,35 @@
+package tasks
+
+import (
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/pkg/web"
+	"github.com/getfider/fider/app/pkg/webhook"
+	"github.com/getfider/fider/app/pkg/worker"
+)
+
+// NotifyAboutAssignedTag triggers webhooks when a tag is assigned to a post
+func NotifyAboutAssignedTag(post *entity.Post, tag *entity.Tag) worker.Task {
+	return describe("Notify about assigned tag", func(c *worker.Context) error {
+		return notifyAboutTag(c, post, tag, enum.WebhookAssignTag)
+	}).WithArgs(NotifyAboutAssignedTag, post, tag)
+}
+
+// NotifyAboutUnassignedTag triggers webhooks when a tag is unassigned from a post
+func NotifyAboutUnassignedTag(post *entity.Post, tag *entity.Tag) worker.Task {
+	return describe("Notify about unassigned tag", func(c *worker.Context) error {
+		return notifyAboutTag(c, post, tag, enum.WebhookUnassignTag)
+	}).WithArgs(NotifyAboutUnassignedTag, post, tag)
+}
+
+func notifyAboutTag(c *worker.Context, post *entity.Post, tag *entity.Tag, webhookType enum.WebhookType) error {
+	webhookProps := webhook.Props{}
+	webhookProps.SetPost(post, "post", web.BaseURL(c), true, true)
+	webhookProps.SetTag(tag, "tag")
+
+	if err := triggerWebhooks(c, webhookType, webhookProps); err != nil {
+		return c.Failure(err)
+	}
+
+	return nil
+}
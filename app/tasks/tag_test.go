@@ -0,0 +1,91 @@
+package tasks_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/mock"
+	"github.com/getfider/fider/app/pkg/webhook"
+	"github.com/getfider/fider/app/services/email/emailmock"
+	"github.com/getfider/fider/app/tasks"
+)
+
+func TestNotifyAboutAssignedTagTask(t *testing.T) {
+	RegisterT(t)
+	bus.Init(emailmock.Service{})
+
+	var triggerWebhooks *cmd.TriggerWebhooks
+	bus.AddHandler(func(ctx context.Context, c *cmd.TriggerWebhooks) error {
+		triggerWebhooks = c
+		return nil
+	})
+
+	worker := mock.NewWorker()
+	post := &entity.Post{
+		ID:     1,
+		Number: 1,
+		Title:  "Add support for TypeScript",
+		Slug:   "add-support-for-typescript",
+		User:   mock.AryaStark,
+	}
+	tag := &entity.Tag{ID: 3, Name: "Bug", Slug: "bug", Color: "FF0000", IsPublic: true}
+
+	task := tasks.NotifyAboutAssignedTag(post, tag)
+
+	err := worker.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		WithBaseURL("http://domain.com").
+		Execute(task)
+
+	Expect(err).IsNil()
+	Expect(triggerWebhooks).IsNotNil()
+	Expect(triggerWebhooks.Type).Equals(enum.WebhookAssignTag)
+	Expect(triggerWebhooks.Props).ContainsProps(webhook.Props{
+		"tag_id":        3,
+		"tag_name":      "Bug",
+		"tag_slug":      "bug",
+		"tag_color":     "FF0000",
+		"tag_is_public": true,
+		"post_id":       post.ID,
+		"post_title":    post.Title,
+		"author_id":     mock.JonSnow.ID,
+		"tenant_id":     mock.DemoTenant.ID,
+	})
+}
+
+func TestNotifyAboutUnassignedTagTask(t *testing.T) {
+	RegisterT(t)
+	bus.Init(emailmock.Service{})
+
+	var triggerWebhooks *cmd.TriggerWebhooks
+	bus.AddHandler(func(ctx context.Context, c *cmd.TriggerWebhooks) error {
+		triggerWebhooks = c
+		return nil
+	})
+
+	worker := mock.NewWorker()
+	post := &entity.Post{ID: 1, Number: 1, Title: "Add support for TypeScript", Slug: "add-support-for-typescript", User: mock.AryaStark}
+	tag := &entity.Tag{ID: 3, Name: "Bug", Slug: "bug", Color: "FF0000", IsPublic: true}
+
+	task := tasks.NotifyAboutUnassignedTag(post, tag)
+
+	err := worker.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		WithBaseURL("http://domain.com").
+		Execute(task)
+
+	Expect(err).IsNil()
+	Expect(triggerWebhooks).IsNotNil()
+	Expect(triggerWebhooks.Type).Equals(enum.WebhookUnassignTag)
+	Expect(triggerWebhooks.Props).ContainsProps(webhook.Props{
+		"tag_id":  3,
+		"post_id": post.ID,
+	})
+}
@@ -2,15 +2,48 @@ package tasks
 
 import (
 	"context"
+	"encoding/gob"
 	"fmt"
 
+	"github.com/getfider/fider/app/models/cmd"
 	"github.com/getfider/fider/app/models/entity"
 	"github.com/getfider/fider/app/models/enum"
 	"github.com/getfider/fider/app/models/query"
 	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/web"
+	"github.com/getfider/fider/app/pkg/webhook"
 	"github.com/getfider/fider/app/pkg/worker"
 )
 
+func init() {
+	gob.Register(&signUpEmailData{})
+
+	// Tasks are rebuilt from these constructors when they are persisted by the database worker
+	worker.RegisterTask(SendChangeEmailConfirmation)
+	worker.RegisterTask(SendDeleteAccountScheduledEmail)
+	worker.RegisterTask(NotifyAboutDeletedPost)
+	worker.RegisterTask(SendInvites)
+	worker.RegisterTask(NotifyAboutMergedPost)
+	worker.RegisterTask(NotifyAboutNewComment)
+	worker.RegisterTask(NotifyAboutUpdatedComment)
+	worker.RegisterTask(NotifyAboutDeletedComment)
+	worker.RegisterTask(NotifyAboutNewPost)
+	worker.RegisterTask(NotifyAboutUpdatedPost)
+	worker.RegisterTask(NotifyAboutNewUser)
+	worker.RegisterTask(SendSignInEmail)
+	worker.RegisterTask(SendSignUpEmail)
+	worker.RegisterTask(NotifyAboutStatusChange)
+	worker.RegisterTask(NotifyAboutMergedStatusChange)
+	worker.RegisterTask(NotifyAboutAssignedTag)
+	worker.RegisterTask(NotifyAboutUnassignedTag)
+	worker.RegisterTask(UserListCreateCompany)
+	worker.RegisterTask(UserListUpdateCompany)
+	worker.RegisterTask(UserListUpdateUser)
+	worker.RegisterTask(UserListAddOrRemoveUser)
+	worker.RegisterTask(NotifyAboutNewVote)
+	worker.RegisterTask(NotifyAboutRemovedVote)
+}
+
 func describe(name string, job worker.Job) worker.Task {
 	return worker.Task{Name: name, Job: job}
 }
@@ -23,12 +56,39 @@ func linkWithText(text, baseURL, path string, args ...any) string {
 	return fmt.Sprintf("<a href='%s%s'>%s</a>", baseURL, fmt.Sprintf(path, args...), text)
 }
 
-func getActiveSubscribers(ctx context.Context, post *entity.Post, channel enum.NotificationChannel, event enum.NotificationEvent) ([]*entity.User, error) {
+func getActiveSubscribers(ctx context.Context, post *entity.Post, channel enum.NotificationChannel, event enum.NotificationEvent, participants ...int) ([]*entity.User, error) {
 	q := &query.GetActiveSubscribers{
-		Number:  post.Number,
-		Channel: channel,
-		Event:   event,
+		Number:       post.Number,
+		Channel:      channel,
+		Event:        event,
+		Participants: participants,
 	}
 	err := bus.Dispatch(ctx, q)
-	return q.Result, err
+	return postAudience(post, q.Result), err
+}
+
+// postAudience removes the users who can't see given post, as posts of private boards are only visible to staff
+func postAudience(post *entity.Post, users []*entity.User) []*entity.User {
+	if post.Board == nil || post.Board.IsPublic {
+		return users
+	}
+
+	staff := make([]*entity.User, 0)
+	for _, user := range users {
+		if user.IsCollaborator() {
+			staff = append(staff, user)
+		}
+	}
+	return staff
+}
+
+// triggerWebhooks adds the current author and tenant to given props and triggers all active webhooks of given type
+func triggerWebhooks(c *worker.Context, webhookType enum.WebhookType, props webhook.Props) error {
+	props.SetUser(c.User(), "author")
+	props.SetTenant(c.Tenant(), "tenant", web.BaseURL(c), web.LogoURL(c))
+
+	return bus.Dispatch(c, &cmd.TriggerWebhooks{
+		Type:  webhookType,
+		Props: props,
+	})
 }
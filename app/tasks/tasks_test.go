@@ -0,0 +1,89 @@
+package tasks_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/getfider/fider/app/actions"
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/mock"
+	"github.com/getfider/fider/app/pkg/worker"
+	"github.com/getfider/fider/app/tasks"
+)
+
+func TestTasksArePersistable(t *testing.T) {
+	RegisterT(t)
+
+	now := time.Now()
+	post := &entity.Post{
+		ID:          1,
+		Number:      1,
+		Title:       "Add support for TypeScript",
+		Slug:        "add-support-for-typescript",
+		Description: "TypeScript is great, please add support for it",
+		Status:      enum.PostOpen,
+		User:        mock.AryaStark,
+		Tags:        []string{"bug"},
+		CreatedAt:   now,
+	}
+	comment := &entity.Comment{ID: 1, Content: "I agree", User: mock.JonSnow, CreatedAt: now, EditedAt: &now}
+	tag := &entity.Tag{ID: 1, Name: "Bug", Slug: "bug", Color: "FF0000", IsPublic: true}
+
+	all := []worker.Task{
+		tasks.SendChangeEmailConfirmation(&actions.ChangeUserEmail{Email: "jon@got.com", VerificationKey: "1234", Requestor: mock.JonSnow}),
+		tasks.SendDeleteAccountScheduledEmail(mock.JonSnow, "Demonstration", now, "http://demo.test.fider.io", "cancel"),
+		tasks.NotifyAboutDeletedPost(post, true),
+		tasks.SendInvites("Share your ideas", "Please visit %invite%", []*actions.UserInvitation{{Email: "a@b.com", VerificationKey: "1234"}}),
+		tasks.NotifyAboutMergedPost(post, post, []*entity.User{mock.JonSnow, mock.AryaStark}),
+		tasks.NotifyAboutNewComment(comment, post),
+		tasks.NotifyAboutUpdatedComment(post, comment),
+		tasks.NotifyAboutDeletedComment(post, comment),
+		tasks.NotifyAboutNewPost(post),
+		tasks.NotifyAboutUpdatedPost(post),
+		tasks.NotifyAboutNewUser(mock.AryaStark),
+		tasks.SendSignInEmail("jon@got.com", "1234", "567890"),
+		tasks.SendSignUpEmail(&actions.CreateTenant{Name: "Jon", Email: "jon@got.com", VerificationKey: "1234"}, "http://demo.test.fider.io"),
+		tasks.NotifyAboutStatusChange(post, enum.PostPlanned),
+		tasks.NotifyAboutMergedStatusChange(post, post, enum.PostDuplicate),
+		tasks.NotifyAboutAssignedTag(post, tag),
+		tasks.NotifyAboutUnassignedTag(post, tag),
+		tasks.UserListCreateCompany(*mock.DemoTenant, *mock.JonSnow),
+		tasks.UserListUpdateCompany(&dto.UserListUpdateCompany{TenantID: 1, Name: "Demonstration", Plan: enum.PlanPro}),
+		tasks.UserListUpdateUser(1, "Jon Snow", "jon@got.com"),
+		tasks.UserListAddOrRemoveUser(1, enum.RoleAdministrator),
+		tasks.NotifyAboutNewVote(post),
+		tasks.NotifyAboutRemovedVote(post),
+	}
+
+	for _, task := range all {
+		Expect(task.IsPersistable()).IsTrue()
+
+		data, err := worker.EncodeArgs(task)
+		Expect(err).IsNil()
+
+		decoded, err := worker.DecodeTask(task.Handler, data)
+		Expect(err).IsNil()
+		Expect(decoded.Name).Equals(task.Name)
+		Expect(decoded.Args).HasLen(len(task.Args))
+	}
+}
+
+func TestSendSignUpEmail_PersistsDataSnapshot(t *testing.T) {
+	RegisterT(t)
+
+	task := tasks.SendSignUpEmail(&actions.CreateTenant{Name: "Jon", Email: "jon@got.com", VerificationKey: "1234"}, "http://demo.test.fider.io")
+	data, err := worker.EncodeArgs(task)
+	Expect(err).IsNil()
+
+	decoded, err := worker.DecodeTask(task.Handler, data)
+	Expect(err).IsNil()
+
+	snapshot := decoded.Args[0].(tasks.SignUpEmailData)
+	Expect(snapshot.GetName()).Equals("Jon")
+	Expect(snapshot.GetEmail()).Equals("jon@got.com")
+	Expect(snapshot.GetVerificationKey()).Equals("1234")
+	Expect(decoded.Args[1]).Equals("http://demo.test.fider.io")
+}
@@ -37,7 +37,7 @@ func UserListCreateCompany(tenant entity.Tenant, user entity.User) worker.Task {
 			return c.Failure(err)
 		}
 		return nil
-	})
+	}).WithArgs(UserListCreateCompany, tenant, user)
 }
 
 func UserListUpdateCompany(action *dto.UserListUpdateCompany) worker.Task {
@@ -53,7 +53,7 @@ func UserListUpdateCompany(action *dto.UserListUpdateCompany) worker.Task {
 			return c.Failure(err)
 		}
 		return nil
-	})
+	}).WithArgs(UserListUpdateCompany, action)
 }
 
 func UserListUpdateUser(id int, name string, email string) worker.Task {
@@ -69,7 +69,7 @@ func UserListUpdateUser(id int, name string, email string) worker.Task {
 			return c.Failure(err)
 		}
 		return nil
-	})
+	}).WithArgs(UserListUpdateUser, id, name, email)
 }
 
 func UserListAddOrRemoveUser(userID int, role enum.Role) worker.Task {
@@ -84,5 +84,5 @@ func UserListAddOrRemoveUser(userID int, role enum.Role) worker.Task {
 			return c.Failure(err)
 		}
 		return nil
-	})
+	}).WithArgs(UserListAddOrRemoveUser, userID, role)
 }
@@ -0,0 +1,42 @@
+package tasks
+
+import (
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/web"
+	"github.com/getfider/fider/app/pkg/webhook"
+	"github.com/getfider/fider/app/pkg/worker"
+)
+
+// NotifyAboutNewVote triggers webhooks when current user votes on a post
+func NotifyAboutNewVote(post *entity.Post) worker.Task {
+	return describe("Notify about new vote", func(c *worker.Context) error {
+		return notifyAboutVote(c, post, enum.WebhookAddVote)
+	}).WithArgs(NotifyAboutNewVote, post)
+}
+
+// NotifyAboutRemovedVote triggers webhooks when current user removes their vote from a post
+func NotifyAboutRemovedVote(post *entity.Post) worker.Task {
+	return describe("Notify about removed vote", func(c *worker.Context) error {
+		return notifyAboutVote(c, post, enum.WebhookRemoveVote)
+	}).WithArgs(NotifyAboutRemovedVote, post)
+}
+
+func notifyAboutVote(c *worker.Context, post *entity.Post, webhookType enum.WebhookType) error {
+	// Reload the post so that the payload has an up to date votes count
+	getPost := &query.GetPostByID{PostID: post.ID}
+	if err := bus.Dispatch(c, getPost); err != nil {
+		return c.Failure(err)
+	}
+
+	webhookProps := webhook.Props{}
+	webhookProps.SetPost(getPost.Result, "post", web.BaseURL(c), true, true)
+
+	if err := triggerWebhooks(c, webhookType, webhookProps); err != nil {
+		return c.Failure(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package tasks_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/mock"
+	"github.com/getfider/fider/app/pkg/webhook"
+	"github.com/getfider/fider/app/services/email/emailmock"
+	"github.com/getfider/fider/app/tasks"
+)
+
+func TestNotifyAboutNewVoteTask(t *testing.T) {
+	RegisterT(t)
+	bus.Init(emailmock.Service{})
+
+	post := &entity.Post{
+		ID:     1,
+		Number: 1,
+		Title:  "Add support for TypeScript",
+		Slug:   "add-support-for-typescript",
+		User:   mock.JonSnow,
+	}
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByID) error {
+		if q.PostID == post.ID {
+			q.Result = &entity.Post{
+				ID:         post.ID,
+				Number:     post.Number,
+				Title:      post.Title,
+				Slug:       post.Slug,
+				User:       post.User,
+				VotesCount: 5,
+			}
+		}
+		return nil
+	})
+
+	var triggerWebhooks *cmd.TriggerWebhooks
+	bus.AddHandler(func(ctx context.Context, c *cmd.TriggerWebhooks) error {
+		triggerWebhooks = c
+		return nil
+	})
+
+	worker := mock.NewWorker()
+	task := tasks.NotifyAboutNewVote(post)
+
+	err := worker.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.AryaStark).
+		WithBaseURL("http://domain.com").
+		Execute(task)
+
+	Expect(err).IsNil()
+	Expect(emailmock.MessageHistory).HasLen(0)
+
+	Expect(triggerWebhooks).IsNotNil()
+	Expect(triggerWebhooks.Type).Equals(enum.WebhookAddVote)
+	Expect(triggerWebhooks.Props).ContainsProps(webhook.Props{
+		"post_id":          post.ID,
+		"post_number":      post.Number,
+		"post_votes":       5,
+		"post_url":         "http://domain.com/posts/1/add-support-for-typescript",
+		"post_author_id":   mock.JonSnow.ID,
+		"author_id":        mock.AryaStark.ID,
+		"author_name":      mock.AryaStark.Name,
+		"tenant_id":        mock.DemoTenant.ID,
+		"tenant_subdomain": mock.DemoTenant.Subdomain,
+	})
+}
+
+func TestNotifyAboutRemovedVoteTask(t *testing.T) {
+	RegisterT(t)
+	bus.Init(emailmock.Service{})
+
+	post := &entity.Post{
+		ID:     1,
+		Number: 1,
+		Title:  "Add support for TypeScript",
+		Slug:   "add-support-for-typescript",
+		User:   mock.JonSnow,
+	}
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByID) error {
+		q.Result = post
+		return nil
+	})
+
+	var triggerWebhooks *cmd.TriggerWebhooks
+	bus.AddHandler(func(ctx context.Context, c *cmd.TriggerWebhooks) error {
+		triggerWebhooks = c
+		return nil
+	})
+
+	worker := mock.NewWorker()
+	task := tasks.NotifyAboutRemovedVote(post)
+
+	err := worker.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.AryaStark).
+		WithBaseURL("http://domain.com").
+		Execute(task)
+
+	Expect(err).IsNil()
+	Expect(triggerWebhooks).IsNotNil()
+	Expect(triggerWebhooks.Type).Equals(enum.WebhookRemoveVote)
+	Expect(triggerWebhooks.Props).ContainsProps(webhook.Props{
+		"post_id":   post.ID,
+		"author_id": mock.AryaStark.ID,
+	})
+}
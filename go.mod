@@ -6,6 +6,7 @@ go 1.25.0
 
 require (
 	github.com/aws/aws-sdk-go v1.41.14
+	github.com/beevik/etree v1.7.0
 	github.com/cosmtrek/air v1.27.3
 	github.com/goenning/imagic v0.0.1
 	github.com/goenning/letteravatar v0.0.0-20180605200324-553181ed4055
@@ -24,6 +25,7 @@ require (
 	github.com/prometheus/client_golang v1.23.2
 	github.com/prometheus/client_model v0.6.2
 	github.com/robfig/cron v1.2.0
+	github.com/russellhaering/goxmldsig v1.6.1
 	github.com/stripe/stripe-go/v83 v83.2.1
 	golang.org/x/crypto v0.48.0
 	golang.org/x/net v0.50.0
@@ -138,6 +140,7 @@ require (
 	github.com/jingyugao/rowserrcheck v1.1.1 // indirect
 	github.com/jjti/go-spancheck v0.6.5 // indirect
 	github.com/jmespath/go-jmespath v0.4.0 // indirect
+	github.com/jonboulle/clockwork v0.5.0 // indirect
 	github.com/julz/importas v0.2.0 // indirect
 	github.com/karamaru-alpha/copyloopvar v1.2.2 // indirect
 	github.com/kisielk/errcheck v1.9.0 // indirect
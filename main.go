@@ -12,7 +12,11 @@ func main() {
 	if len(args) > 0 && args[0] == "ping" {
 		os.Exit(cmd.RunPing())
 	} else if len(args) > 0 && args[0] == "migrate" {
-		os.Exit(cmd.RunMigrate())
+		os.Exit(cmd.RunMigrate(args[1:]))
+	} else if len(args) > 0 && args[0] == "restore" {
+		os.Exit(cmd.RunRestore(args[1:]))
+	} else if len(args) > 0 && args[0] == "admin" {
+		os.Exit(cmd.RunAdmin(args[1:]))
 	} else {
 		os.Exit(cmd.RunServer())
 	}
@@ -0,0 +1,5 @@
+ALTER TABLE webhooks ADD COLUMN secret VARCHAR(100) NULL;
+
+UPDATE webhooks SET secret = replace(gen_random_uuid()::text || gen_random_uuid()::text, '-', '');
+
+ALTER TABLE webhooks ALTER COLUMN secret SET NOT NULL;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS worker_tasks (
+  id BIGSERIAL PRIMARY KEY,
+  tenant_id INT NULL,
+  name TEXT NOT NULL,
+  handler TEXT NOT NULL,
+  args BYTEA NOT NULL,
+  origin BYTEA NOT NULL,
+  status SMALLINT NOT NULL,
+  attempts INT NOT NULL DEFAULT 0,
+  last_error TEXT NOT NULL DEFAULT '',
+  locked_by TEXT NULL,
+  locked_at TIMESTAMPTZ NULL,
+  run_at TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL,
+  FOREIGN KEY (tenant_id) REFERENCES tenants (id)
+);
+
+CREATE INDEX worker_tasks_run_at_idx ON worker_tasks (run_at) WHERE status = 1;
+CREATE INDEX worker_tasks_locked_at_idx ON worker_tasks (locked_at) WHERE status = 2;
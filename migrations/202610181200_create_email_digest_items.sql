@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS email_digest_items (
+  id SERIAL PRIMARY KEY,
+  tenant_id INT NOT NULL,
+  user_id INT NOT NULL,
+  frequency VARCHAR(20) NOT NULL,
+  event VARCHAR(50) NOT NULL,
+  post_number INT NOT NULL,
+  post_title TEXT NOT NULL,
+  post_slug TEXT NOT NULL,
+  author_name TEXT NOT NULL,
+  content TEXT NOT NULL,
+  status VARCHAR(50) NOT NULL,
+  base_url TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL,
+  FOREIGN KEY (tenant_id) REFERENCES tenants (id),
+  FOREIGN KEY (user_id, tenant_id) REFERENCES users (id, tenant_id)
+);
+
+CREATE INDEX email_digest_items_frequency_idx ON email_digest_items (frequency, tenant_id, user_id);
@@ -0,0 +1,4 @@
+ALTER TABLE post_votes DROP COLUMN IF EXISTS weight;
+
+ALTER TABLE tenants DROP COLUMN IF EXISTS vote_budget;
+ALTER TABLE tenants DROP COLUMN IF EXISTS max_votes_per_post;
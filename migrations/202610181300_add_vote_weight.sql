@@ -0,0 +1,4 @@
+ALTER TABLE post_votes ADD COLUMN weight INT NOT NULL DEFAULT 1;
+
+ALTER TABLE tenants ADD COLUMN vote_budget INT NOT NULL DEFAULT 0;
+ALTER TABLE tenants ADD COLUMN max_votes_per_post INT NOT NULL DEFAULT 1;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS user_attributes (
+  id SERIAL PRIMARY KEY,
+  tenant_id INT NOT NULL,
+  user_id INT NOT NULL,
+  key VARCHAR(50) NOT NULL,
+  value VARCHAR(200) NOT NULL,
+  updated_at TIMESTAMPTZ NOT NULL,
+  FOREIGN KEY (tenant_id) REFERENCES tenants (id),
+  FOREIGN KEY (user_id, tenant_id) REFERENCES users (id, tenant_id)
+);
+
+CREATE UNIQUE INDEX user_attributes_user_key_idx ON user_attributes (tenant_id, user_id, key);
+CREATE INDEX user_attributes_key_idx ON user_attributes (tenant_id, key);
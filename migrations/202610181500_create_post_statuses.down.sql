@@ -0,0 +1,15 @@
+-- Custom statuses are unknown to earlier versions, so their posts go back to the closest built-in status
+UPDATE posts p
+SET status = CASE ps.roadmap_column
+  WHEN 'planned' THEN 4
+  WHEN 'started' THEN 1
+  WHEN 'completed' THEN 2
+  ELSE 0
+END
+FROM post_statuses ps
+WHERE ps.tenant_id = p.tenant_id
+AND ps.status = p.status;
+
+UPDATE posts SET status = 0 WHERE status >= 100;
+
+DROP TABLE IF EXISTS post_statuses;
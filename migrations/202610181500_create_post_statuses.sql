@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS post_statuses (
+  id SERIAL PRIMARY KEY,
+  tenant_id INT NOT NULL,
+  status INT NOT NULL,
+  name VARCHAR(30) NOT NULL,
+  slug VARCHAR(30) NOT NULL,
+  color VARCHAR(6) NOT NULL,
+  accepts_votes BOOLEAN NOT NULL DEFAULT TRUE,
+  roadmap_column VARCHAR(20) NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL,
+  FOREIGN KEY (tenant_id) REFERENCES tenants (id)
+);
+
+CREATE UNIQUE INDEX post_statuses_tenant_status_idx ON post_statuses (tenant_id, status);
+CREATE UNIQUE INDEX post_statuses_tenant_slug_idx ON post_statuses (tenant_id, slug);
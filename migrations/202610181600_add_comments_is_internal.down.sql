@@ -0,0 +1,10 @@
+-- Earlier versions would show internal notes as public comments, so they are moved out of the comments table
+CREATE TABLE IF NOT EXISTS internal_comments_archive AS
+SELECT * FROM comments WHERE is_internal = TRUE;
+
+DELETE FROM reactions WHERE comment_id IN (SELECT id FROM comments WHERE is_internal = TRUE);
+DELETE FROM attachments WHERE comment_id IN (SELECT id FROM comments WHERE is_internal = TRUE);
+DELETE FROM mention_notifications WHERE comment_id IN (SELECT id FROM comments WHERE is_internal = TRUE);
+DELETE FROM comments WHERE is_internal = TRUE;
+
+ALTER TABLE comments DROP COLUMN IF EXISTS is_internal;
@@ -0,0 +1 @@
+ALTER TABLE comments ADD is_internal BOOLEAN NOT NULL DEFAULT FALSE;
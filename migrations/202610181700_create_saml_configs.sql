@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS saml_configs (
+  id SERIAL PRIMARY KEY,
+  tenant_id INT NOT NULL,
+  status INT NOT NULL,
+  display_name VARCHAR(50) NOT NULL,
+  idp_entity_id VARCHAR(300) NOT NULL,
+  idp_sso_url VARCHAR(300) NOT NULL,
+  idp_certificate TEXT NOT NULL,
+  name_attribute VARCHAR(100) NOT NULL DEFAULT '',
+  email_attribute VARCHAR(100) NOT NULL DEFAULT '',
+  roles_attribute VARCHAR(100) NOT NULL DEFAULT '',
+  allowed_roles VARCHAR(500) NOT NULL DEFAULT '',
+  is_trusted BOOLEAN NOT NULL DEFAULT FALSE,
+  created_at TIMESTAMPTZ NOT NULL,
+  FOREIGN KEY (tenant_id) REFERENCES tenants (id)
+);
+
+CREATE UNIQUE INDEX saml_configs_tenant_idx ON saml_configs (tenant_id);
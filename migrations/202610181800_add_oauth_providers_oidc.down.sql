@@ -0,0 +1,2 @@
+ALTER TABLE oauth_providers DROP COLUMN IF EXISTS is_oidc;
+ALTER TABLE oauth_providers DROP COLUMN IF EXISTS issuer_url;
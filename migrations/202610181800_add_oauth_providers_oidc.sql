@@ -0,0 +1,2 @@
+ALTER TABLE oauth_providers ADD is_oidc BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE oauth_providers ADD issuer_url VARCHAR(300) NOT NULL DEFAULT '';
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS scim_tokens (
+  tenant_id INT NOT NULL,
+  token_hash VARCHAR(128) NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL,
+  PRIMARY KEY (tenant_id),
+  FOREIGN KEY (tenant_id) REFERENCES tenants (id)
+);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS api_tokens (
+  id SERIAL PRIMARY KEY,
+  tenant_id INT NOT NULL,
+  user_id INT NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  token_hash VARCHAR(128) NOT NULL,
+  scopes VARCHAR(50)[] NOT NULL,
+  expires_at TIMESTAMPTZ NULL,
+  last_used_at TIMESTAMPTZ NULL,
+  created_at TIMESTAMPTZ NOT NULL,
+  FOREIGN KEY (tenant_id) REFERENCES tenants (id),
+  FOREIGN KEY (user_id, tenant_id) REFERENCES users (id, tenant_id)
+);
+
+CREATE UNIQUE INDEX api_tokens_token_hash_key ON api_tokens (token_hash);
+CREATE INDEX api_tokens_tenant_id_user_id_idx ON api_tokens (tenant_id, user_id);
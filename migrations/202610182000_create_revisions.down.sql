@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS comment_revisions;
+DROP TABLE IF EXISTS post_revisions;
+
+DROP INDEX IF EXISTS comment_id_tenant_id_key;
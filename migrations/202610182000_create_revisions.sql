@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS post_revisions (
+  id SERIAL PRIMARY KEY,
+  tenant_id INT NOT NULL,
+  post_id INT NOT NULL,
+  version INT NOT NULL,
+  title VARCHAR(100) NOT NULL,
+  description TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL,
+  created_by_id INT NOT NULL,
+  FOREIGN KEY (tenant_id) REFERENCES tenants (id),
+  FOREIGN KEY (post_id, tenant_id) REFERENCES posts (id, tenant_id),
+  FOREIGN KEY (created_by_id, tenant_id) REFERENCES users (id, tenant_id)
+);
+
+CREATE UNIQUE INDEX post_revisions_tenant_id_post_id_version_key ON post_revisions (tenant_id, post_id, version);
+
+CREATE UNIQUE INDEX IF NOT EXISTS comment_id_tenant_id_key ON comments (tenant_id, id);
+
+CREATE TABLE IF NOT EXISTS comment_revisions (
+  id SERIAL PRIMARY KEY,
+  tenant_id INT NOT NULL,
+  comment_id INT NOT NULL,
+  version INT NOT NULL,
+  content TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL,
+  created_by_id INT NOT NULL,
+  FOREIGN KEY (tenant_id) REFERENCES tenants (id),
+  FOREIGN KEY (comment_id, tenant_id) REFERENCES comments (id, tenant_id),
+  FOREIGN KEY (created_by_id, tenant_id) REFERENCES users (id, tenant_id)
+);
+
+CREATE UNIQUE INDEX comment_revisions_tenant_id_comment_id_version_key ON comment_revisions (tenant_id, comment_id, version);
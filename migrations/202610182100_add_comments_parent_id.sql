@@ -0,0 +1,8 @@
+ALTER TABLE comments ADD parent_id INT NULL;
+
+ALTER TABLE comments
+   ADD CONSTRAINT comments_parent_id_fkey
+   FOREIGN KEY (parent_id, tenant_id)
+   REFERENCES comments (id, tenant_id);
+
+CREATE INDEX comments_parent_id_idx ON comments (tenant_id, parent_id);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS comments_search_idx;
+ALTER TABLE comments DROP COLUMN IF EXISTS search;
+
+DO $$
+BEGIN
+  IF obj_description((SELECT oid FROM pg_extension WHERE extname = 'pg_trgm'), 'pg_extension') = 'created by fider migration 202610182200' THEN
+    DROP EXTENSION pg_trgm;
+  END IF;
+END
+$$;
@@ -0,0 +1,14 @@
+-- The extension is only marked as ours when this migration creates it, so that reverting it keeps an extension that was already there
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'pg_trgm') THEN
+    CREATE EXTENSION pg_trgm;
+    COMMENT ON EXTENSION pg_trgm IS 'created by fider migration 202610182200';
+  END IF;
+END
+$$;
+
+-- Comments are searched along with posts, so their text vector is kept up to date instead of being computed on each search
+ALTER TABLE comments ADD search tsvector GENERATED ALWAYS AS (to_tsvector('simple'::regconfig, content)) STORED;
+
+CREATE INDEX IF NOT EXISTS comments_search_idx ON comments USING GIN (search);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS post_signatures (
+  tenant_id INT NOT NULL,
+  post_id INT NOT NULL,
+  minhash BIGINT[] NOT NULL,
+  bands BIGINT[] NOT NULL,
+  updated_at TIMESTAMPTZ NOT NULL,
+  PRIMARY KEY (tenant_id, post_id),
+  FOREIGN KEY (tenant_id) REFERENCES tenants (id),
+  FOREIGN KEY (post_id, tenant_id) REFERENCES posts (id, tenant_id)
+);
+
+CREATE INDEX post_signatures_bands_idx ON post_signatures USING GIN (bands);
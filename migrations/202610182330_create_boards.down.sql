@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS posts_tenant_id_board_id_idx;
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS posts_board_id_fkey;
+ALTER TABLE posts DROP COLUMN IF EXISTS board_id;
+
+DROP TABLE IF EXISTS board_tags;
+DROP TABLE IF EXISTS boards;
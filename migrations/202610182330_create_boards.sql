@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS boards (
+  id SERIAL PRIMARY KEY,
+  tenant_id INT NOT NULL,
+  name VARCHAR(60) NOT NULL,
+  slug VARCHAR(60) NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  description_template TEXT NOT NULL DEFAULT '',
+  is_public BOOLEAN NOT NULL DEFAULT true,
+  has_roadmap BOOLEAN NOT NULL DEFAULT false,
+  created_at TIMESTAMPTZ NOT NULL,
+  FOREIGN KEY (tenant_id) REFERENCES tenants (id)
+);
+
+CREATE UNIQUE INDEX board_slug_tenant_key ON boards (tenant_id, slug);
+CREATE UNIQUE INDEX board_id_tenant_id_key ON boards (tenant_id, id);
+
+CREATE TABLE IF NOT EXISTS board_tags (
+  tenant_id INT NOT NULL,
+  board_id INT NOT NULL,
+  tag_id INT NOT NULL,
+  PRIMARY KEY (tenant_id, board_id, tag_id),
+  FOREIGN KEY (board_id, tenant_id) REFERENCES boards (id, tenant_id),
+  FOREIGN KEY (tag_id, tenant_id) REFERENCES tags (id, tenant_id)
+);
+
+ALTER TABLE posts ADD board_id INT NULL;
+ALTER TABLE posts ADD CONSTRAINT posts_board_id_fkey FOREIGN KEY (board_id, tenant_id) REFERENCES boards (id, tenant_id);
+
+CREATE INDEX posts_tenant_id_board_id_idx ON posts (tenant_id, board_id);
@@ -0,0 +1,11 @@
+-- Chunked blobs are joined back into a single column, which is where earlier versions read them from
+UPDATE blobs b
+SET file = c.data
+FROM (
+  SELECT blob_id, string_agg(data, ''::bytea ORDER BY seq) AS data
+  FROM blob_chunks
+  GROUP BY blob_id
+) c
+WHERE c.blob_id = b.id;
+
+DROP TABLE IF EXISTS blob_chunks;
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS blob_chunks (
+  blob_id INT NOT NULL,
+  seq     INT NOT NULL,
+  data    BYTEA NOT NULL,
+  PRIMARY KEY (blob_id, seq),
+  FOREIGN KEY (blob_id) REFERENCES blobs (id) ON DELETE CASCADE
+);
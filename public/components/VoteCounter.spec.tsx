@@ -28,6 +28,7 @@ beforeEach(() => {
       avatarURL: "/static/avatars/letter/5/John",
     },
     hasVoted: false,
+    voteWeight: 0,
     response: null,
     votesCount: 5,
     commentsCount: 2,
@@ -16,6 +16,8 @@ export interface Tenant {
   isFeedEnabled: boolean
   isModerationEnabled: boolean
   isPro: boolean
+  voteBudget: number
+  maxVotesPerPost: number
 }
 
 export enum TenantStatus {
@@ -79,3 +81,14 @@ export interface CurrentUser {
   isCollaborator: boolean
   isTrusted: boolean
 }
+
+export type APIScope = "posts:read" | "posts:write" | "comments:write" | "tags:manage" | "admin"
+
+export interface APIToken {
+  id: number
+  name: string
+  scopes: APIScope[]
+  expiresAt?: string
+  lastUsedAt?: string
+  createdAt: string
+}
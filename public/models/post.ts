@@ -10,11 +10,44 @@ export interface Post {
   status: string
   user: User
   hasVoted: boolean
+  voteWeight: number
+  attributeTotal?: number
   response: PostResponse | null
   votesCount: number
   commentsCount: number
   tags: string[]
   isApproved: boolean
+  customStatus?: CustomPostStatus
+  board?: PostBoard
+  snippet?: string
+}
+
+export interface PostBoard {
+  id: number
+  name: string
+  slug: string
+  isPublic: boolean
+}
+
+export interface Board {
+  id: number
+  name: string
+  slug: string
+  description: string
+  descriptionTemplate: string
+  isPublic: boolean
+  hasRoadmap: boolean
+  defaultTags: string[]
+  postsCount: number
+}
+
+export interface CustomPostStatus {
+  status: string
+  name: string
+  slug: string
+  color: string
+  acceptsVotes: boolean
+  roadmapColumn: "" | "planned" | "started" | "completed"
 }
 
 export class PostStatus {
@@ -34,6 +67,9 @@ export class PostStatus {
         return status
       }
     }
+    if (value.startsWith("custom_")) {
+      return new PostStatus(value, value, true, false, false)
+    }
     throw new Error(`PostStatus not found for value ${value}.`)
   }
 
@@ -68,6 +104,7 @@ export interface Comment {
   editedAt?: string
   editedBy?: User
   isApproved: boolean
+  isInternal: boolean
 }
 
 export interface Tag {
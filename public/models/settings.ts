@@ -50,6 +50,31 @@ export interface OAuthConfig {
   jsonUserRolesPath: string
   allowedRoles: string
   isTrusted: boolean
+  isOIDC: boolean
+  issuerURL: string
+}
+
+export interface SAMLConfig {
+  status: number
+  displayName: string
+  idpEntityID: string
+  idpSSOURL: string
+  idpCertificate: string
+  nameAttribute: string
+  emailAttribute: string
+  rolesAttribute: string
+  allowedRoles: string
+  isTrusted: boolean
+}
+
+export interface SAMLServiceProvider {
+  entityID: string
+  acsURL: string
+}
+
+export interface SCIMSettings {
+  baseURL: string
+  tokenCreatedAt?: string
 }
 
 export interface ImageUpload {
@@ -12,6 +12,7 @@ export interface WebhookData {
 
 export interface Webhook extends WebhookData {
   id: number
+  secret: string
 }
 
 export enum WebhookType {
@@ -19,6 +20,14 @@ export enum WebhookType {
   NEW_COMMENT = "new_comment",
   CHANGE_STATUS = "change_status",
   DELETE_POST = "delete_post",
+  ADD_VOTE = "add_vote",
+  REMOVE_VOTE = "remove_vote",
+  EDIT_COMMENT = "edit_comment",
+  DELETE_COMMENT = "delete_comment",
+  ASSIGN_TAG = "assign_tag",
+  UNASSIGN_TAG = "unassign_tag",
+  EDIT_POST = "edit_post",
+  NEW_USER = "new_user",
 }
 
 export enum WebhookStatus {
@@ -36,10 +45,34 @@ export interface WebhookTriggerResult {
   url: string
   content: string
   status_code: number
+  latency_ms: number
+  response: string
   message: string
   error: string
 }
 
+export enum WebhookDeliveryStatus {
+  PENDING = "pending",
+  SUCCESS = "success",
+  FAILED = "failed",
+}
+
+export interface WebhookDelivery {
+  id: number
+  webhook_id: number
+  status: WebhookDeliveryStatus
+  attempts: number
+  url: string
+  content: string
+  status_code: number
+  latency_ms: number
+  response: string
+  error: string
+  created_at: string
+  last_attempt_at: string
+  next_attempt_at?: string
+}
+
 export interface WebhookPreviewResult {
   url: PreviewedField
   content: PreviewedField
@@ -17,6 +17,8 @@ export const OAuthForm: React.FC<OAuthFormProps> = (props) => {
   const [displayName, setDisplayName] = useState((props.config && props.config.displayName) || "")
   const [enabled, setEnabled] = useState((props.config && props.config.status === OAuthConfigStatus.Enabled) || false)
   const [isTrusted, setTrusted] = useState((props.config && props.config.isTrusted) || false)
+  const [isOIDC, setOIDC] = useState((props.config && props.config.isOIDC) || false)
+  const [issuerURL, setIssuerURL] = useState((props.config && props.config.issuerURL) || "")
   const [clientID, setClientID] = useState((props.config && props.config.clientID) || "")
   const [clientSecret, setClientSecret] = useState((props.config && props.config.clientSecret) || "")
   const [clientSecretEnabled, setClientSecretEnabled] = useState(!props.config)
@@ -39,6 +41,8 @@ export const OAuthForm: React.FC<OAuthFormProps> = (props) => {
       provider,
       status: enabled ? OAuthConfigStatus.Enabled : OAuthConfigStatus.Disabled,
       isTrusted,
+      isOIDC,
+      issuerURL,
       displayName,
       clientID,
       clientSecret: clientSecretEnabled ? clientSecret : "",
@@ -121,92 +125,138 @@ export const OAuthForm: React.FC<OAuthFormProps> = (props) => {
             ) : undefined
           }
         />
-        <Input
-          field="authorizeURL"
-          label="Authorize URL"
-          maxLength={300}
-          value={authorizeURL}
-          disabled={!fider.session.user.isAdministrator}
-          onChange={setAuthorizeURL}
-        />
-        <Input field="tokenURL" label="Token URL" maxLength={300} value={tokenURL} disabled={!fider.session.user.isAdministrator} onChange={setTokenURL} />
-
-        <Input field="scope" label="Scope" maxLength={100} value={scope} disabled={!fider.session.user.isAdministrator} onChange={setScope}>
-          <p className="text-muted">
-            It is recommended to only request the minimum scopes we need to fetch the user <strong>id</strong>, <strong>name</strong> and <strong>email</strong>
-            . Multiple scopes must be separated by space.
+        <Field label="OpenID Connect">
+          <Toggle field="isOIDC" active={isOIDC} onToggle={setOIDC} label={isOIDC ? "Yes" : "No"} />
+          <p className="text-muted mt-1">
+            If enabled, endpoints and signing keys are discovered from the provider&apos;s OpenID configuration, the ID token is validated and user
+            profiles are read from its standard claims.
           </p>
-        </Input>
-
-        <h3 className="text-title mt-8 mb-2">User Profile</h3>
-        <p className="text-muted">This section is used to configure how Fider will fetch user after the authentication process.</p>
-
-        <Input
-          field="profileURL"
-          label="Profile API URL"
-          maxLength={300}
-          value={profileURL}
-          disabled={!fider.session.user.isAdministrator}
-          onChange={setProfileURL}
-        >
-          <p className="text-muted">The URL to fetch the authenticated user info. If empty, Fider will try to parse the user info from the Access Token.</p>
-        </Input>
-
-        <h3 className="text-title mt-8 mb-2">JSON Path</h3>
-        <p>
-          Find out more about{" "}
-          <a rel="noopener" className="text-link" target="_blank" href="https://docs.fider.io/configuring-oauth#configuring-the-json-paths">
-            configuring the JSON Paths
-          </a>
-          .
-        </p>
+        </Field>
 
-        <div className="grid grid-cols-4 gap-4">
-          <Input
-            field="jsonUserIDPath"
-            label="ID"
-            maxLength={100}
-            value={jsonUserIDPath}
-            disabled={!fider.session.user.isAdministrator}
-            onChange={setJSONUserIDPath}
-          >
-            <p className="text-muted">Make sure it&apos;s unique. </p>
-          </Input>
+        {isOIDC ? (
           <Input
-            field="jsonUserNamePath"
-            label="Name"
-            maxLength={100}
-            value={jsonUserNamePath}
+            field="issuerURL"
+            label="Issuer URL"
+            maxLength={300}
+            value={issuerURL}
             disabled={!fider.session.user.isAdministrator}
-            onChange={setJSONUserNamePath}
+            onChange={setIssuerURL}
           >
             <p className="text-muted">
-              Optional, but <strong>highly</strong> recommended.
+              The URL where <strong>/.well-known/openid-configuration</strong> is published, e.g. <strong>https://accounts.example.com</strong>.
             </p>
           </Input>
-          <Input
-            field="jsonUserEmailPath"
-            label="Email"
-            maxLength={100}
-            value={jsonUserEmailPath}
-            disabled={!fider.session.user.isAdministrator}
-            onChange={setJSONUserEmailPath}
-          >
+        ) : (
+          <>
+            <Input
+              field="authorizeURL"
+              label="Authorize URL"
+              maxLength={300}
+              value={authorizeURL}
+              disabled={!fider.session.user.isAdministrator}
+              onChange={setAuthorizeURL}
+            />
+            <Input field="tokenURL" label="Token URL" maxLength={300} value={tokenURL} disabled={!fider.session.user.isAdministrator} onChange={setTokenURL} />
+          </>
+        )}
+
+        <Input field="scope" label="Scope" maxLength={100} value={scope} disabled={!fider.session.user.isAdministrator} onChange={setScope}>
+          <p className="text-muted">
+            It is recommended to only request the minimum scopes we need to fetch the user <strong>id</strong>, <strong>name</strong> and <strong>email</strong>
+            . Multiple scopes must be separated by space.{isOIDC && <> It must include <strong>openid</strong>.</>}
+          </p>
+        </Input>
+
+        {isOIDC ? (
+          <>
+            <h3 className="text-title mt-8 mb-2">Claims</h3>
             <p className="text-muted">
-              Optional, but <strong>highly</strong> recommended.
+              The user id, name and email are read from the <strong>sub</strong>, <strong>name</strong> and <strong>email</strong> claims of the ID token.
             </p>
-          </Input>
-          <Input
-            field="jsonUserRolesPath"
-            label="Roles"
-            maxLength={100}
-            value={jsonUserRolesPath}
-            disabled={!fider.session.user.isAdministrator}
-            onChange={setJSONUserRolesPath}
-          >
-            <p className="text-muted">Optional. JSON path to extract roles from the provider profile.</p>
-          </Input>
-        </div>
+            <Input
+              field="jsonUserRolesPath"
+              label="Roles"
+              maxLength={100}
+              value={jsonUserRolesPath}
+              disabled={!fider.session.user.isAdministrator}
+              onChange={setJSONUserRolesPath}
+            >
+              <p className="text-muted">Optional. JSON path to extract roles from the ID token claims, e.g. <strong>groups</strong>.</p>
+            </Input>
+          </>
+        ) : (
+          <>
+            <h3 className="text-title mt-8 mb-2">User Profile</h3>
+            <p className="text-muted">This section is used to configure how Fider will fetch user after the authentication process.</p>
+
+            <Input
+              field="profileURL"
+              label="Profile API URL"
+              maxLength={300}
+              value={profileURL}
+              disabled={!fider.session.user.isAdministrator}
+              onChange={setProfileURL}
+            >
+              <p className="text-muted">The URL to fetch the authenticated user info. If empty, Fider will try to parse the user info from the Access Token.</p>
+            </Input>
+
+            <h3 className="text-title mt-8 mb-2">JSON Path</h3>
+            <p>
+              Find out more about{" "}
+              <a rel="noopener" className="text-link" target="_blank" href="https://docs.fider.io/configuring-oauth#configuring-the-json-paths">
+                configuring the JSON Paths
+              </a>
+              .
+            </p>
+
+            <div className="grid grid-cols-4 gap-4">
+              <Input
+                field="jsonUserIDPath"
+                label="ID"
+                maxLength={100}
+                value={jsonUserIDPath}
+                disabled={!fider.session.user.isAdministrator}
+                onChange={setJSONUserIDPath}
+              >
+                <p className="text-muted">Make sure it&apos;s unique. </p>
+              </Input>
+              <Input
+                field="jsonUserNamePath"
+                label="Name"
+                maxLength={100}
+                value={jsonUserNamePath}
+                disabled={!fider.session.user.isAdministrator}
+                onChange={setJSONUserNamePath}
+              >
+                <p className="text-muted">
+                  Optional, but <strong>highly</strong> recommended.
+                </p>
+              </Input>
+              <Input
+                field="jsonUserEmailPath"
+                label="Email"
+                maxLength={100}
+                value={jsonUserEmailPath}
+                disabled={!fider.session.user.isAdministrator}
+                onChange={setJSONUserEmailPath}
+              >
+                <p className="text-muted">
+                  Optional, but <strong>highly</strong> recommended.
+                </p>
+              </Input>
+              <Input
+                field="jsonUserRolesPath"
+                label="Roles"
+                maxLength={100}
+                value={jsonUserRolesPath}
+                disabled={!fider.session.user.isAdministrator}
+                onChange={setJSONUserRolesPath}
+              >
+                <p className="text-muted">Optional. JSON path to extract roles from the provider profile.</p>
+              </Input>
+            </div>
+          </>
+        )}
 
         <Input
           field="allowedRoles"
@@ -0,0 +1,158 @@
+import React, { useState } from "react"
+import { SAMLConfig, SAMLServiceProvider, OAuthConfigStatus } from "@fider/models"
+import { Failure, actions } from "@fider/services"
+import { Form, Button, Input, TextArea, Field, Toggle } from "@fider/components"
+import { useFider } from "@fider/hooks"
+import { HStack } from "@fider/components/layout"
+
+interface SAMLFormProps {
+  config?: SAMLConfig
+  serviceProvider: SAMLServiceProvider
+  onCancel: () => void
+  cantDisable: boolean
+}
+
+export const SAMLForm: React.FC<SAMLFormProps> = (props) => {
+  const fider = useFider()
+  const [metadata, setMetadata] = useState("")
+  const [displayName, setDisplayName] = useState((props.config && props.config.displayName) || "")
+  const [enabled, setEnabled] = useState((props.config && props.config.status === OAuthConfigStatus.Enabled) || false)
+  const [isTrusted, setTrusted] = useState((props.config && props.config.isTrusted) || false)
+  const [idpEntityID, setIdPEntityID] = useState((props.config && props.config.idpEntityID) || "")
+  const [idpSSOURL, setIdPSSOURL] = useState((props.config && props.config.idpSSOURL) || "")
+  const [idpCertificate, setIdPCertificate] = useState((props.config && props.config.idpCertificate) || "")
+  const [nameAttribute, setNameAttribute] = useState((props.config && props.config.nameAttribute) || "")
+  const [emailAttribute, setEmailAttribute] = useState((props.config && props.config.emailAttribute) || "")
+  const [rolesAttribute, setRolesAttribute] = useState((props.config && props.config.rolesAttribute) || "")
+  const [allowedRoles, setAllowedRoles] = useState((props.config && props.config.allowedRoles) || "")
+  const [error, setError] = useState<Failure | undefined>()
+
+  const handleSave = async () => {
+    const result = await actions.saveSAMLConfig({
+      metadata,
+      status: enabled ? OAuthConfigStatus.Enabled : OAuthConfigStatus.Disabled,
+      displayName,
+      idpEntityID,
+      idpSSOURL,
+      idpCertificate,
+      nameAttribute,
+      emailAttribute,
+      rolesAttribute,
+      allowedRoles,
+      isTrusted,
+    })
+    if (result.ok) {
+      location.reload()
+    } else {
+      setError(result.error)
+    }
+  }
+
+  const handleMetadataFile = async (e: React.ChangeEvent<HTMLInputElement>) => {
+    const file = e.target.files?.[0]
+    if (file) {
+      setMetadata(await file.text())
+    }
+  }
+
+  const isAdministrator = fider.session.user.isAdministrator
+  const usesMetadata = metadata !== ""
+
+  return (
+    <>
+      <h2 className="text-title mb-2">SAML Identity Provider</h2>
+      <Form error={error}>
+        <Input field="displayName" label="Display Name" maxLength={50} value={displayName} disabled={!isAdministrator} onChange={setDisplayName}>
+          <p className="text-muted">The text of the sign in button, e.g. Company SSO.</p>
+        </Input>
+
+        <h3 className="text-title mt-8 mb-2">Service Provider</h3>
+        <p className="text-muted">Use these values to register this site as a Service Provider on your Identity Provider.</p>
+        <p className="text-muted">
+          <strong>Entity ID / Metadata URL:</strong> {props.serviceProvider.entityID} <br />
+          <strong>Assertion Consumer Service URL:</strong> {props.serviceProvider.acsURL}
+        </p>
+
+        <h3 className="text-title mt-8 mb-2">Identity Provider</h3>
+        <TextArea field="metadata" label="IdP Metadata" value={metadata} disabled={!isAdministrator} minRows={4} onChange={setMetadata}>
+          <p className="text-muted">
+            Paste or{" "}
+            <label className="text-link">
+              upload
+              <input type="file" accept=".xml,application/xml,text/xml" className="hidden" disabled={!isAdministrator} onChange={handleMetadataFile} />
+            </label>{" "}
+            the metadata XML of your Identity Provider to fill the settings below.
+          </p>
+        </TextArea>
+
+        <Input
+          field="idpEntityID"
+          label="IdP Entity ID"
+          maxLength={300}
+          value={idpEntityID}
+          disabled={!isAdministrator || usesMetadata}
+          onChange={setIdPEntityID}
+        />
+        <Input
+          field="idpSSOURL"
+          label="IdP SSO URL"
+          maxLength={300}
+          value={idpSSOURL}
+          disabled={!isAdministrator || usesMetadata}
+          onChange={setIdPSSOURL}
+        >
+          <p className="text-muted">The URL of the HTTP-Redirect Single Sign-On service.</p>
+        </Input>
+        <TextArea
+          field="idpCertificate"
+          label="IdP Signing Certificate"
+          value={idpCertificate}
+          disabled={!isAdministrator || usesMetadata}
+          minRows={4}
+          onChange={setIdPCertificate}
+        />
+
+        <h3 className="text-title mt-8 mb-2">Attribute Mapping</h3>
+        <p className="text-muted">The names of the SAML attributes holding the user profile. The NameID is used to identify users.</p>
+        <div className="grid grid-cols-3 gap-4">
+          <Input field="nameAttribute" label="Name" maxLength={100} value={nameAttribute} disabled={!isAdministrator} onChange={setNameAttribute}>
+            <p className="text-muted">Optional, NameID is used if empty.</p>
+          </Input>
+          <Input field="emailAttribute" label="Email" maxLength={100} value={emailAttribute} disabled={!isAdministrator} onChange={setEmailAttribute}>
+            <p className="text-muted">Optional, NameID is used if it&apos;s an email.</p>
+          </Input>
+          <Input field="rolesAttribute" label="Roles" maxLength={100} value={rolesAttribute} disabled={!isAdministrator} onChange={setRolesAttribute}>
+            <p className="text-muted">Optional.</p>
+          </Input>
+        </div>
+
+        <Input field="allowedRoles" label="Allowed Roles" maxLength={500} value={allowedRoles} disabled={!isAdministrator} onChange={setAllowedRoles}>
+          <p className="text-muted">
+            Optional. Comma-separated list of roles allowed to sign in. Only enforced when a Roles attribute is also configured. Leave empty to allow all roles.
+          </p>
+        </Input>
+
+        <Field label="Trusted Source">
+          <Toggle field="isTrusted" active={isTrusted} onToggle={setTrusted} label={isTrusted ? "Yes" : "No"} />
+          <p className="text-muted mt-1">If enabled, users authenticated by this Identity Provider can get access to this private site without being invited.</p>
+        </Field>
+
+        <Field label="Status">
+          <Toggle field="status" disabled={props.cantDisable} active={enabled} onToggle={setEnabled} label={enabled ? "Enabled" : "Disabled"} />
+          <p className="text-muted mt-1">
+            {enabled ? "Users will be able to sign in with this Identity Provider." : "Users won't be able to sign in with this Identity Provider."}
+          </p>
+        </Field>
+
+        <HStack className="mt-2">
+          <Button variant="primary" onClick={handleSave}>
+            Save
+          </Button>
+          <Button variant="tertiary" onClick={props.onCancel}>
+            Cancel
+          </Button>
+        </HStack>
+      </Form>
+    </>
+  )
+}
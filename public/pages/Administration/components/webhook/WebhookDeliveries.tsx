@@ -0,0 +1,111 @@
+import "./WebhookDeliveries.scss"
+
+import React, { useEffect, useState } from "react"
+import { Webhook, WebhookDelivery, WebhookDeliveryStatus } from "@fider/models"
+import { Button, Loader, Moment } from "@fider/components"
+import { actions, notify } from "@fider/services"
+import { useFider } from "@fider/hooks"
+import { HStack, VStack } from "@fider/components/layout"
+
+interface WebhookDeliveriesProps {
+  webhook: Webhook
+}
+
+const getStatusText = (delivery: WebhookDelivery) => {
+  switch (delivery.status) {
+    case WebhookDeliveryStatus.SUCCESS:
+      return "Delivered"
+    case WebhookDeliveryStatus.PENDING:
+      return "Retrying"
+    case WebhookDeliveryStatus.FAILED:
+      return "Failed"
+  }
+}
+
+export const WebhookDeliveries = (props: WebhookDeliveriesProps) => {
+  const fider = useFider()
+  const [deliveries, setDeliveries] = useState<WebhookDelivery[] | undefined>(undefined)
+  const [expanded, setExpanded] = useState<number | undefined>(undefined)
+
+  const loadDeliveries = async () => {
+    const result = await actions.getWebhookDeliveries(props.webhook.id)
+    if (result.ok) {
+      setDeliveries(result.data)
+    }
+  }
+
+  useEffect(() => {
+    loadDeliveries()
+  }, [props.webhook.id])
+
+  const redeliver = async (delivery: WebhookDelivery) => {
+    const result = await actions.redeliverWebhook(props.webhook.id, delivery.id)
+    if (result.ok && result.data.success) {
+      notify.success("Successfully redelivered webhook")
+    } else if (result.ok) {
+      notify.error(result.data.message)
+    }
+    await loadDeliveries()
+  }
+
+  if (!deliveries) {
+    return <Loader />
+  }
+
+  if (deliveries.length === 0) {
+    return <p className="text-muted">This webhook has not been triggered yet.</p>
+  }
+
+  return (
+    <VStack className="c-webhook-deliveries" spacing={2} divide>
+      {deliveries.map((d) => (
+        <VStack key={d.id} spacing={2}>
+          <HStack justify="between">
+            <HStack className="clickable" onClick={() => setExpanded(expanded === d.id ? undefined : d.id)}>
+              <span className={`c-webhook-deliveries__status c-webhook-deliveries__status--${d.status}`}>{getStatusText(d)}</span>
+              <span className="text-muted">#{d.id}</span>
+              {d.status_code > 0 && <span>HTTP {d.status_code}</span>}
+              <span className="text-muted">{d.latency_ms}ms</span>
+              <span className="text-muted">
+                {d.attempts} {d.attempts === 1 ? "attempt" : "attempts"}
+              </span>
+              <Moment locale={fider.currentLocale} date={d.last_attempt_at} />
+              {d.next_attempt_at && (
+                <span className="text-muted">
+                  next retry <Moment locale={fider.currentLocale} date={d.next_attempt_at} format="short" />
+                </span>
+              )}
+            </HStack>
+            <Button size="small" onClick={() => redeliver(d)}>
+              Redeliver
+            </Button>
+          </HStack>
+          {expanded === d.id && (
+            <VStack spacing={2}>
+              <div>
+                <b>URL</b>
+                <pre>{d.url}</pre>
+              </div>
+              <div>
+                <b>Content</b>
+                <pre>{d.content}</pre>
+              </div>
+              {d.response && (
+                <div>
+                  <b>Response</b>
+                  <pre>{d.response}</pre>
+                </div>
+              )}
+              {d.error && (
+                <div>
+                  <b>Error</b>
+                  <pre>{d.error}</pre>
+                </div>
+              )}
+            </VStack>
+          )}
+        </VStack>
+      ))}
+    </VStack>
+  )
+}
@@ -2,7 +2,7 @@ import "./WebhookForm.scss"
 
 import React, { useEffect, useState } from "react"
 import { Button, Field, Form, Input, Loader, Message, Select, SelectOption, TextArea, Toggle } from "@fider/components"
-import { actions, Failure } from "@fider/services"
+import { actions, Failure, notify } from "@fider/services"
 import { HStack, VStack } from "@fider/components/layout"
 import { Webhook, WebhookData, WebhookPreviewResult, WebhookStatus, WebhookType } from "@fider/models"
 import { HoverInfo } from "@fider/components/common/HoverInfo"
@@ -88,6 +88,7 @@ export const WebhookForm = (props: WebhookFormProps) => {
   const [content, setContent] = useState(props.webhook?.content || "")
   const [httpMethod, setHttpMethod] = useState(props.webhook?.http_method || "POST")
   const [httpHeaders, _setHttpHeaders] = useState(props.webhook?.http_headers || {})
+  const [secret, setSecret] = useState(props.webhook?.secret || "")
   const [typing, setTyping] = useState<NodeJS.Timeout | undefined>()
   const [preview, setPreview] = useState<WebhookPreviewResult | null | undefined>()
   const [isModalOpen, setIsModalOpen] = useState(false)
@@ -140,6 +141,16 @@ export const WebhookForm = (props: WebhookFormProps) => {
     })
   }
 
+  const rotateSecret = async () => {
+    if (!props.webhook) return
+    const result = await actions.rotateWebhookSecret(props.webhook.id)
+    if (result.ok) {
+      props.webhook.secret = result.data.secret
+      setSecret(result.data.secret)
+      notify.success("The signing secret has been rotated")
+    }
+  }
+
   const showModal = () => setIsModalOpen(true)
   const hideModal = () => setIsModalOpen(false)
 
@@ -164,6 +175,14 @@ export const WebhookForm = (props: WebhookFormProps) => {
             { label: "New Comment", value: WebhookType.NEW_COMMENT },
             { label: "Change Status", value: WebhookType.CHANGE_STATUS },
             { label: "Delete Post", value: WebhookType.DELETE_POST },
+            { label: "Edit Post", value: WebhookType.EDIT_POST },
+            { label: "Edit Comment", value: WebhookType.EDIT_COMMENT },
+            { label: "Delete Comment", value: WebhookType.DELETE_COMMENT },
+            { label: "Add Vote", value: WebhookType.ADD_VOTE },
+            { label: "Remove Vote", value: WebhookType.REMOVE_VOTE },
+            { label: "Assign Tag", value: WebhookType.ASSIGN_TAG },
+            { label: "Unassign Tag", value: WebhookType.UNASSIGN_TAG },
+            { label: "New User", value: WebhookType.NEW_USER },
           ]}
           onChange={setType}
         />
@@ -197,6 +216,20 @@ export const WebhookForm = (props: WebhookFormProps) => {
             <HttpHeader onEdit={setHttpHeader} allHeaders={allHeaders} />
           </VStack>
         </Field>
+        {props.webhook && (
+          <Input
+            field="secret"
+            label="Signing secret"
+            afterLabel={<HoverInfo text="Every request has a X-Fider-Signature header with the HMAC-SHA256 of '<timestamp>.<body>' using this secret" />}
+            value={secret}
+            disabled
+            suffix={
+              <Button variant="secondary" onClick={rotateSecret}>
+                Rotate
+              </Button>
+            }
+          />
+        )}
         {(url || content) && (
           <Field label="Preview" className="c-webhook-form__preview">
             {preview === null ? (
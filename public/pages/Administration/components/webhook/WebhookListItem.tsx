@@ -11,8 +11,10 @@ import IconPlay from "@fider/assets/images/heroicons-play.svg"
 import IconCheckCircle from "@fider/assets/images/heroicons-check-circle.svg"
 import IconXCircle from "@fider/assets/images/heroicons-x-circle.svg"
 import IconExclamation from "@fider/assets/images/heroicons-exclamation.svg"
+import IconClock from "@fider/assets/images/heroicons-clock.svg"
 import { HStack, VStack } from "@fider/components/layout"
 import { WebhookFailInfo } from "./WebhookFailInfo"
+import { WebhookDeliveries } from "./WebhookDeliveries"
 
 interface WebhookListItemProps {
   webhook: Webhook
@@ -49,6 +51,7 @@ const WebhookIcon = (props: WebhookIconProps) => {
 
 export const WebhookListItem = (props: WebhookListItemProps) => {
   const [deleting, setDeleting] = useState(false)
+  const [showDeliveries, setShowDeliveries] = useState(false)
   const [triggerResult, setTriggerResult] = useState<WebhookTriggerResult | undefined>(undefined)
   const [isFailInfoModalOpen, setIsFailInfoModalOpen] = useState(false)
 
@@ -73,6 +76,22 @@ export const WebhookListItem = (props: WebhookListItemProps) => {
         return "Delete Post"
       case WebhookType.NEW_POST:
         return "New Post"
+      case WebhookType.ADD_VOTE:
+        return "Add Vote"
+      case WebhookType.REMOVE_VOTE:
+        return "Remove Vote"
+      case WebhookType.EDIT_COMMENT:
+        return "Edit Comment"
+      case WebhookType.DELETE_COMMENT:
+        return "Delete Comment"
+      case WebhookType.ASSIGN_TAG:
+        return "Assign Tag"
+      case WebhookType.UNASSIGN_TAG:
+        return "Unassign Tag"
+      case WebhookType.EDIT_POST:
+        return "Edit Post"
+      case WebhookType.NEW_USER:
+        return "New User"
     }
   }
 
@@ -110,32 +129,39 @@ export const WebhookListItem = (props: WebhookListItemProps) => {
 
   const renderViewMode = () => {
     return (
-      <HStack justify="between">
-        <HStack>
-          <WebhookIcon status={props.webhook.status} />
-          <h3 className="text-body nowrap">
-            <span className="text-muted">#{props.webhook.id}</span>
-            <span className="text-bold px-2">{getWebhookType(props.webhook.type)}</span> - {props.webhook.name}
-          </h3>
-          {triggerResult?.success === false && (
-            <WebhookFailInfo result={triggerResult} isModalOpen={isFailInfoModalOpen} onModalOpen={showFailInfoModal} onModalClose={hideFailInfoModal} />
-          )}
+      <VStack spacing={4}>
+        <HStack justify="between">
+          <HStack>
+            <WebhookIcon status={props.webhook.status} />
+            <h3 className="text-body nowrap">
+              <span className="text-muted">#{props.webhook.id}</span>
+              <span className="text-bold px-2">{getWebhookType(props.webhook.type)}</span> - {props.webhook.name}
+            </h3>
+            {triggerResult?.success === false && (
+              <WebhookFailInfo result={triggerResult} isModalOpen={isFailInfoModalOpen} onModalOpen={showFailInfoModal} onModalClose={hideFailInfoModal} />
+            )}
+          </HStack>
+          <HStack>
+            <Button size="small" onClick={() => setShowDeliveries(!showDeliveries)}>
+              <Icon sprite={IconClock} />
+              <span>History</span>
+            </Button>
+            <Button size="small" onClick={testWebhook}>
+              <Icon sprite={IconPlay} />
+              <span>Test</span>
+            </Button>
+            <Button size="small" onClick={() => props.editWebhook(props.webhook)}>
+              <Icon sprite={IconPencilAlt} />
+              <span>Edit</span>
+            </Button>
+            <Button size="small" onClick={() => setDeleting(true)}>
+              <Icon sprite={IconX} />
+              <span>Delete</span>
+            </Button>
+          </HStack>
         </HStack>
-        <HStack>
-          <Button size="small" onClick={testWebhook}>
-            <Icon sprite={IconPlay} />
-            <span>Test</span>
-          </Button>
-          <Button size="small" onClick={() => props.editWebhook(props.webhook)}>
-            <Icon sprite={IconPencilAlt} />
-            <span>Edit</span>
-          </Button>
-          <Button size="small" onClick={() => setDeleting(true)}>
-            <Icon sprite={IconX} />
-            <span>Delete</span>
-          </Button>
-        </HStack>
-      </HStack>
+        {showDeliveries && <WebhookDeliveries webhook={props.webhook} />}
+      </VStack>
     )
   }
 
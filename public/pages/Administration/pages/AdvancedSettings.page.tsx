@@ -1,6 +1,6 @@
 import React from "react"
 
-import { TextArea, Form, Button } from "@fider/components"
+import { TextArea, Input, Form, Button } from "@fider/components"
 import { Failure, actions, Fider } from "@fider/services"
 import { AdminBasePage } from "../components/AdminBasePage"
 
@@ -12,6 +12,8 @@ interface AdvancedSettingsPageProps {
 interface AdvancedSettingsPageState {
   customCSS: string
   allowedSchemes: string
+  voteBudget: string
+  maxVotesPerPost: string
   error?: Failure
 }
 
@@ -27,6 +29,8 @@ export default class AdvancedSettingsPage extends AdminBasePage<AdvancedSettings
     this.state = {
       customCSS: this.props.customCSS,
       allowedSchemes: this.props.allowedSchemes,
+      voteBudget: Fider.session.tenant.voteBudget.toString(),
+      maxVotesPerPost: Fider.session.tenant.maxVotesPerPost.toString(),
     }
   }
 
@@ -38,8 +42,19 @@ export default class AdvancedSettingsPage extends AdminBasePage<AdvancedSettings
     this.setState({ allowedSchemes })
   }
 
+  private setVoteBudget = (voteBudget: string): void => {
+    this.setState({ voteBudget })
+  }
+
+  private setMaxVotesPerPost = (maxVotesPerPost: string): void => {
+    this.setState({ maxVotesPerPost })
+  }
+
   private handleSave = async (): Promise<void> => {
-    const result = await actions.updateTenantAdvancedSettings(this.state.customCSS, this.state.allowedSchemes)
+    let result = await actions.updateTenantAdvancedSettings(this.state.customCSS, this.state.allowedSchemes)
+    if (result.ok) {
+      result = await actions.updateTenantVotingSettings(parseInt(this.state.voteBudget, 10) || 0, parseInt(this.state.maxVotesPerPost, 10) || 0)
+    }
     if (result.ok) {
       location.reload()
     } else {
@@ -97,6 +112,32 @@ export default class AdvancedSettingsPage extends AdminBasePage<AdvancedSettings
           </TextArea>
         )}
 
+        <Input
+          field="voteBudget"
+          label="Vote Budget"
+          inputMode="numeric"
+          disabled={!Fider.session.user.isAdministrator}
+          value={this.state.voteBudget}
+          onChange={this.setVoteBudget}
+        >
+          <p className="text-muted">
+            Number of votes each user can spend across open posts. Votes are given back once a post is completed, declined or marked as duplicate.
+            <br />
+            Use <code>0</code> to let users vote once on every post.
+          </p>
+        </Input>
+
+        <Input
+          field="maxVotesPerPost"
+          label="Maximum Votes per Post"
+          inputMode="numeric"
+          disabled={!Fider.session.user.isAdministrator}
+          value={this.state.maxVotesPerPost}
+          onChange={this.setMaxVotesPerPost}
+        >
+          <p className="text-muted">How many votes of their budget a user can place on a single post.</p>
+        </Input>
+
         {Fider.session.user.isAdministrator && (
           <div className="field">
             <Button variant="primary" onClick={this.handleSave}>
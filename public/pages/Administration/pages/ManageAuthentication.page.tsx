@@ -1,8 +1,9 @@
 import React from "react"
 
 import { Button, OAuthProviderLogo, Icon, Field, Toggle, Form } from "@fider/components"
-import { OAuthConfig, OAuthProviderOption } from "@fider/models"
+import { OAuthConfig, OAuthConfigStatus, OAuthProviderOption, SAMLConfig, SAMLServiceProvider, SCIMSettings } from "@fider/models"
 import { OAuthForm } from "../components/OAuthForm"
+import { SAMLForm } from "../components/SAMLForm"
 import { actions, notify, Fider, Failure } from "@fider/services"
 import { AdminBasePage } from "../components/AdminBasePage"
 
@@ -13,13 +14,18 @@ import { HStack, VStack } from "@fider/components/layout"
 
 interface ManageAuthenticationPageProps {
   providers: OAuthProviderOption[]
+  samlConfig?: SAMLConfig
+  samlServiceProvider: SAMLServiceProvider
+  scim: SCIMSettings
 }
 
 interface ManageAuthenticationPageState {
   isAdding: boolean
+  isEditingSAML: boolean
   isEmailAuthAllowed: boolean
   canDisableEmailAuth: boolean
   editing?: OAuthConfig
+  scimToken?: string
   error?: Failure
 }
 
@@ -33,8 +39,10 @@ export default class ManageAuthenticationPage extends AdminBasePage<ManageAuthen
     super(props)
     this.state = {
       isAdding: false,
+      isEditingSAML: false,
       isEmailAuthAllowed: Fider.session.tenant.isEmailAuthAllowed,
-      canDisableEmailAuth: props.providers.map((o) => o.isEnabled).reduce((a, b) => a || b, false),
+      canDisableEmailAuth:
+        props.providers.map((o) => o.isEnabled).reduce((a, b) => a || b, false) || props.samlConfig?.status === OAuthConfigStatus.Enabled,
     }
   }
 
@@ -56,8 +64,19 @@ export default class ManageAuthenticationPage extends AdminBasePage<ManageAuthen
     window.open(`/oauth/${provider}?redirect=${redirect}`, "oauth-test", "width=1100,height=600,status=no,menubar=no")
   }
 
+  private editSAML = async () => {
+    this.setState({ isEditingSAML: true, isAdding: false, editing: undefined })
+  }
+
   private cancel = async () => {
-    this.setState({ isAdding: false, editing: undefined })
+    this.setState({ isAdding: false, isEditingSAML: false, editing: undefined })
+  }
+
+  private regenerateSCIMToken = async () => {
+    const result = await actions.regenerateSCIMToken()
+    if (result.ok) {
+      this.setState({ scimToken: result.data.token })
+    }
   }
 
   private toggleEmailAuth = async (active: boolean) => {
@@ -97,8 +116,23 @@ export default class ManageAuthenticationPage extends AdminBasePage<ManageAuthen
         enabledProvidersCount++
       }
     }
+    const isSAMLEnabled = this.props.samlConfig?.status === OAuthConfigStatus.Enabled
+    if (isSAMLEnabled) {
+      enabledProvidersCount++
+    }
     const cantDisable = !this.state.isEmailAuthAllowed && enabledProvidersCount == 1
 
+    if (this.state.isEditingSAML) {
+      return (
+        <SAMLForm
+          cantDisable={cantDisable && isSAMLEnabled}
+          config={this.props.samlConfig}
+          serviceProvider={this.props.samlServiceProvider}
+          onCancel={this.cancel}
+        />
+      )
+    }
+
     if (this.state.isAdding) {
       return <OAuthForm cantDisable={cantDisable} onCancel={this.cancel} />
     }
@@ -196,6 +230,60 @@ export default class ManageAuthenticationPage extends AdminBasePage<ManageAuthen
             </div>
           </VStack>
         </div>
+        <div>
+          <h2 className="text-display">SAML Single Sign-On</h2>
+          <p>You can use this section to let users sign in through an Identity Provider that supports SAML 2.0, such as Okta, Microsoft Entra ID or ADFS.</p>
+          <HStack justify="between">
+            <VStack spacing={1}>
+              {this.props.samlConfig ? (
+                <>
+                  <strong>{this.props.samlConfig.displayName}</strong>
+                  <div className="text-xs block">{isSAMLEnabled ? enabled : disabled}</div>
+                </>
+              ) : (
+                <span className="text-muted">Not configured</span>
+              )}
+              <span className="text-muted">
+                <strong>Entity ID / Metadata URL:</strong> {this.props.samlServiceProvider.entityID} <br />
+                <strong>Assertion Consumer Service URL:</strong> {this.props.samlServiceProvider.acsURL}
+              </span>
+            </VStack>
+            {Fider.session.user.isAdministrator && (
+              <Button onClick={this.editSAML} size="small">
+                <Icon sprite={IconPencilAlt} />
+                <span>{this.props.samlConfig ? "Edit" : "Configure"}</span>
+              </Button>
+            )}
+          </HStack>
+        </div>
+        <div>
+          <h2 className="text-display">SCIM User Provisioning</h2>
+          <p>
+            Identity Providers that support SCIM 2.0 can create, update and deactivate users automatically. Groups are mapped to the Administrator,
+            Collaborator and Visitor roles.
+          </p>
+          <VStack spacing={2}>
+            <span className="text-muted">
+              <strong>SCIM Base URL:</strong> {this.props.scim.baseURL} <br />
+              <strong>Token:</strong> {this.props.scim.tokenCreatedAt ? `Generated on ${new Date(this.props.scim.tokenCreatedAt).toLocaleString()}` : "Not generated"}
+            </span>
+            {Fider.session.user.isAdministrator && (
+              <div>
+                <Button size="small" onClick={this.regenerateSCIMToken}>
+                  {this.props.scim.tokenCreatedAt ? "Regenerate Token" : "Generate Token"}
+                </Button>
+              </div>
+            )}
+            {this.state.scimToken && (
+              <>
+                <p className="text-muted">
+                  Your new SCIM Token is: <code>{this.state.scimToken}</code>
+                </p>
+                <p className="text-muted">It is only shown once. Previous tokens stop working immediately.</p>
+              </>
+            )}
+          </VStack>
+        </div>
       </VStack>
     )
   }
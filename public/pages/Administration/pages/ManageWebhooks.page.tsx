@@ -55,7 +55,7 @@ const ManageWebhooksPage = (props: ManageWebhooksPageProps) => {
     const result = await actions.createWebhook(data)
     if (result.ok) {
       setIsAdding(false)
-      setAllWebhooks(allWebhooks.concat({ id: result.data.id, ...data }).sort(webhookSorter))
+      setAllWebhooks(allWebhooks.concat({ id: result.data.id, secret: result.data.secret, ...data }).sort(webhookSorter))
     } else {
       return result.error
     }
@@ -0,0 +1,61 @@
+import React from "react"
+
+import { Post } from "@fider/models"
+import { AdminBasePage } from "@fider/pages/Administration/components/AdminBasePage"
+import { HStack, VStack } from "@fider/components/layout"
+
+interface PossibleDuplicate {
+  post: Post
+  duplicate: Post
+  similarity: number
+}
+
+interface PossibleDuplicatesPageProps {
+  duplicates: PossibleDuplicate[]
+  minSimilarity: number
+}
+
+const PostLink = (props: { post: Post }) => {
+  return (
+    <VStack spacing={0}>
+      <a className="text-link" href={`/posts/${props.post.number}/${props.post.slug}`}>
+        #{props.post.number} {props.post.title}
+      </a>
+      <span className="text-muted text-sm">
+        {props.post.votesCount} votes · {props.post.commentsCount} comments
+      </span>
+    </VStack>
+  )
+}
+
+export default class PossibleDuplicatesPage extends AdminBasePage<PossibleDuplicatesPageProps, any> {
+  public id = "p-admin-duplicates"
+  public name = "duplicates"
+  public title = "Possible Duplicates"
+  public subtitle = "Review posts that are likely to be about the same subject"
+
+  public content() {
+    const minSimilarity = Math.round(this.props.minSimilarity * 100)
+
+    return (
+      <VStack spacing={4}>
+        <p className="text-muted">
+          Pairs of posts with at least {minSimilarity}% of their words in common. Open a post to merge its duplicate into it.
+        </p>
+        {this.props.duplicates.length === 0 ? (
+          <p>No possible duplicates were found.</p>
+        ) : (
+          <VStack spacing={2} divide={true}>
+            {this.props.duplicates.map((item) => (
+              <HStack key={`${item.post.id}-${item.duplicate.id}`} spacing={4} className="py-2">
+                <strong>{Math.round(item.similarity * 100)}%</strong>
+                <PostLink post={item.post} />
+                <PostLink post={item.duplicate} />
+              </HStack>
+            ))}
+          </VStack>
+        )}
+      </VStack>
+    )
+  }
+}
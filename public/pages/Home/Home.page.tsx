@@ -4,7 +4,7 @@ import IconPlusCircle from "@fider/assets/images/heroicons-pluscircle.svg"
 import IconArrowLeft from "@fider/assets/images/heroicons-arrowleft.svg"
 
 import React, { useEffect, useState, useRef } from "react"
-import { Post, Tag, PostStatus } from "@fider/models"
+import { Post, Tag, PostStatus, Board } from "@fider/models"
 import { Markdown, Hint, PoweredByFider, Icon, Header, Button } from "@fider/components"
 import { PostsContainer } from "./components/PostsContainer"
 import { useFider, usePostOverlay } from "@fider/hooks"
@@ -20,6 +20,8 @@ export interface HomePageProps {
   tags: Tag[]
   searchNoiseWords: string[]
   countPerStatus: { [key: string]: number }
+  boards: Board[]
+  board?: Board
 }
 
 export interface HomePageState {
@@ -47,13 +49,34 @@ const Lonely = () => {
   )
 }
 
+const BoardsNav = (props: { boards: Board[]; board?: Board }) => {
+  if (props.boards.length === 0) {
+    return null
+  }
+
+  const className = (active: boolean) => `text-link${active ? " text-semibold" : ""}`
+
+  return (
+    <HStack spacing={4} className="flex-wrap mb-4">
+      <a href="/" className={className(!props.board)}>
+        <Trans id="home.boards.all">All</Trans>
+      </a>
+      {props.boards.map((b) => (
+        <a key={b.id} href={`/b/${b.slug}`} className={className(props.board?.id === b.id)}>
+          {b.name}
+        </a>
+      ))}
+    </HStack>
+  )
+}
+
 const HomePage = (props: HomePageProps) => {
   const fider = useFider()
   const postsContainerRef = useRef<PostsContainer>(null)
   const [isShareFeedbackOpen, setIsShareFeedbackOpen] = useState(isPostPending())
 
   const { selectedPostId, handlePostClick, handleCloseOverlay, setIsPostDirty } = usePostOverlay({
-    basePath: "/",
+    basePath: props.board ? `/b/${props.board.slug}` : "/",
     onPostClosed: (postNumber) => postsContainerRef.current?.updateSinglePost(postNumber),
   })
 
@@ -124,6 +147,7 @@ What can we do better? This is the place for you to vote, discuss and share idea
     <>
       <ShareFeedback
         tags={props.tags}
+        board={props.board}
         placeholder={fider.session.tenant.invitation || defaultInvitation}
         isOpen={isShareFeedbackOpen && !fider.isReadOnly}
         onClose={() => setIsShareFeedbackOpen(false)}
@@ -138,16 +162,24 @@ What can we do better? This is the place for you to vote, discuss and share idea
         >
           <div className="p-home__welcome-col">
             <VStack spacing={6}>
-              <div>
-                {fider.session.tenant.welcomeHeader && <h1 className="p-home__welcome-title mb-5">{parseWelcomeHeader(fider.session.tenant.welcomeHeader)}</h1>}
-                <Markdown className="p-home__welcome-body" text={fider.session.tenant.welcomeMessage || defaultWelcomeMessage} style="full" />
-              </div>
+              {props.board ? (
+                <div>
+                  <h1 className="p-home__welcome-title mb-5">{props.board.name}</h1>
+                  {props.board.description && <Markdown className="p-home__welcome-body" text={props.board.description} style="full" />}
+                </div>
+              ) : (
+                <div>
+                  {fider.session.tenant.welcomeHeader && <h1 className="p-home__welcome-title mb-5">{parseWelcomeHeader(fider.session.tenant.welcomeHeader)}</h1>}
+                  <Markdown className="p-home__welcome-body" text={fider.session.tenant.welcomeMessage || defaultWelcomeMessage} style="full" />
+                </div>
+              )}
             </VStack>
             <div>
               <PoweredByFider slot="home-input" className="sm:hidden md:hidden lg:block mt-3" />
             </div>
           </div>
           <div className="p-home__posts-col">
+            <BoardsNav boards={props.boards || []} board={props.board} />
             <button className="p-home__add-idea-btn" onClick={handleNewPost}>
               <HStack spacing={4} align="center">
                 <Icon sprite={IconPlusCircle} className="p-home__add-idea-icon" />
@@ -162,6 +194,7 @@ What can we do better? This is the place for you to vote, discuss and share idea
                 posts={props.posts}
                 tags={props.tags}
                 countPerStatus={props.countPerStatus}
+                board={props.board?.slug}
                 onPostClick={handlePostClick}
               />
             )}
@@ -8,7 +8,7 @@ import { Trans } from "@lingui/react/macro"
 import { actions, Failure, querystring, classSet, cache } from "@fider/services"
 import { plainText } from "@fider/services/markdown"
 import { i18n } from "@lingui/core"
-import { Tag } from "@fider/models"
+import { Board, Tag } from "@fider/models"
 import { SimilarPosts } from "../components/SimilarPosts"
 import { TagsSelect } from "@fider/components/common/TagsSelect"
 import CommentEditor from "@fider/components/common/form/CommentEditor"
@@ -30,6 +30,7 @@ interface ShareFeedbackProps {
   placeholder: string
   onClose: () => void
   tags: Tag[]
+  board?: Board
 }
 
 export const ShareFeedback: React.FC<ShareFeedbackProps> = (props) => {
@@ -56,7 +57,7 @@ export const ShareFeedback: React.FC<ShareFeedbackProps> = (props) => {
 
   const canEditTags = fider.settings.postWithTags && props.tags.length > 0
 
-  const descriptionTemplate = fider.session.tenant.descriptionTemplate || ""
+  const descriptionTemplate = props.board?.descriptionTemplate || fider.session.tenant.descriptionTemplate || ""
   const hasCachedDraft = getCachedDescription() !== ""
   const prefillTemplate = !hasCachedDraft && descriptionTemplate !== ""
 
@@ -181,7 +182,8 @@ export const ShareFeedback: React.FC<ShareFeedbackProps> = (props) => {
           title,
           description,
           attachments,
-          tags.map((tag) => tag.slug)
+          tags.map((tag) => tag.slug),
+          props.board?.slug
         ),
         minDelay,
       ])
@@ -0,0 +1,192 @@
+import React from "react"
+import { Button, Checkbox, Form, Input, Moment, Select, SelectOption } from "@fider/components"
+import { APIToken, APIScope } from "@fider/models"
+import { actions, Failure, Fider } from "@fider/services"
+import { HStack, VStack } from "@fider/components/layout"
+import { i18n } from "@lingui/core"
+import { Trans } from "@lingui/react/macro"
+
+interface APITokensFormProps {
+  tokens: APIToken[]
+}
+
+interface APITokensFormState {
+  tokens: APIToken[]
+  isCreating: boolean
+  name: string
+  scopes: APIScope[]
+  expiresInDays: number
+  newToken?: string
+  error?: Failure
+}
+
+const scopeLabels: { [key in APIScope]: string } = {
+  "posts:read": "Read posts, comments, votes and tags",
+  "posts:write": "Create and update posts, votes and subscriptions",
+  "comments:write": "Create, update and react to comments",
+  "tags:manage": "Create, assign and delete tags",
+  admin: "Everything your role allows, including managing users",
+}
+
+export class APITokensForm extends React.Component<APITokensFormProps, APITokensFormState> {
+  constructor(props: APITokensFormProps) {
+    super(props)
+    this.state = {
+      tokens: props.tokens,
+      isCreating: false,
+      name: "",
+      scopes: [],
+      expiresInDays: 90,
+    }
+  }
+
+  private startCreating = () => {
+    this.setState({ isCreating: true, newToken: undefined, name: "", scopes: [], expiresInDays: 90, error: undefined })
+  }
+
+  private cancel = () => {
+    this.setState({ isCreating: false, error: undefined })
+  }
+
+  private setName = (name: string) => {
+    this.setState({ name })
+  }
+
+  private setExpiration = (option?: SelectOption) => {
+    this.setState({ expiresInDays: option ? parseInt(option.value, 10) : 0 })
+  }
+
+  private toggleScope = (scope: APIScope, checked: boolean) => {
+    const scopes = this.state.scopes.filter((s) => s !== scope)
+    this.setState({ scopes: checked ? [...scopes, scope] : scopes })
+  }
+
+  private create = async () => {
+    const result = await actions.createAPIToken({
+      name: this.state.name,
+      scopes: this.state.scopes,
+      expiresInDays: this.state.expiresInDays,
+    })
+    if (result.ok) {
+      this.setState({
+        isCreating: false,
+        newToken: result.data.token,
+        tokens: [result.data.apiToken, ...this.state.tokens],
+        error: undefined,
+      })
+    } else if (result.error) {
+      this.setState({ error: result.error })
+    }
+  }
+
+  private revoke = async (token: APIToken) => {
+    const result = await actions.revokeAPIToken(token.id)
+    if (result.ok) {
+      this.setState({ tokens: this.state.tokens.filter((t) => t.id !== token.id) })
+    }
+  }
+
+  private renderToken(token: APIToken) {
+    const isExpired = token.expiresAt && new Date(token.expiresAt) < new Date()
+    return (
+      <HStack key={token.id} justify="between">
+        <VStack spacing={1}>
+          <strong>{token.name}</strong>
+          <span className="text-muted text-sm">{token.scopes.join(", ")}</span>
+          <span className="text-muted text-sm">
+            {token.expiresAt ? (
+              <>
+                {isExpired ? <Trans id="mysettings.apitokens.expired">Expired</Trans> : <Trans id="mysettings.apitokens.expires">Expires</Trans>}{" "}
+                <Moment locale={Fider.currentLocale} date={token.expiresAt} format="date" />
+              </>
+            ) : (
+              <Trans id="mysettings.apitokens.noexpiration">Never expires</Trans>
+            )}
+            {" · "}
+            {token.lastUsedAt ? (
+              <>
+                <Trans id="mysettings.apitokens.lastused">Last used</Trans> <Moment locale={Fider.currentLocale} date={token.lastUsedAt} />
+              </>
+            ) : (
+              <Trans id="mysettings.apitokens.neverused">Never used</Trans>
+            )}
+          </span>
+        </VStack>
+        <Button size="small" variant="danger" onClick={this.revoke.bind(this, token)}>
+          <Trans id="mysettings.apitokens.revoke">Revoke</Trans>
+        </Button>
+      </HStack>
+    )
+  }
+
+  private renderCreateForm() {
+    return (
+      <Form error={this.state.error}>
+        <Input field="name" label={i18n._({ id: "mysettings.apitokens.name", message: "Name" })} maxLength={100} value={this.state.name} onChange={this.setName} />
+        <div className="c-form-field">
+          <label>
+            <Trans id="mysettings.apitokens.scopes">Scopes</Trans>
+          </label>
+          {(Object.keys(scopeLabels) as APIScope[]).map((scope) => (
+            <Checkbox key={scope} field="scopes" checked={this.state.scopes.includes(scope)} onChange={this.toggleScope.bind(this, scope)}>
+              <code>{scope}</code> {scopeLabels[scope]}
+            </Checkbox>
+          ))}
+        </div>
+        <Select
+          field="expiresInDays"
+          label={i18n._({ id: "mysettings.apitokens.expiration", message: "Expiration" })}
+          defaultValue={this.state.expiresInDays.toString()}
+          options={[
+            { value: "7", label: "7 days" },
+            { value: "30", label: "30 days" },
+            { value: "90", label: "90 days" },
+            { value: "365", label: "1 year" },
+            { value: "0", label: i18n._({ id: "mysettings.apitokens.noexpiration", message: "Never expires" }) },
+          ]}
+          onChange={this.setExpiration}
+        />
+        <HStack>
+          <Button variant="primary" size="small" onClick={this.create}>
+            <Trans id="mysettings.apitokens.create">Create token</Trans>
+          </Button>
+          <Button variant="tertiary" size="small" onClick={this.cancel}>
+            <Trans id="action.cancel">Cancel</Trans>
+          </Button>
+        </HStack>
+      </Form>
+    )
+  }
+
+  public render() {
+    return (
+      <div>
+        <h4 className="text-title mb-1">
+          <Trans id="mysettings.apitokens.title">Personal Access Tokens</Trans>
+        </h4>
+        <p className="text-muted">
+          <Trans id="mysettings.apitokens.notice">
+            Tokens give automations access to the API on your behalf, limited to the scopes you choose. They are only shown when created.
+          </Trans>
+        </p>
+        {this.state.newToken && (
+          <p className="text-muted">
+            <Trans id="mysettings.apitokens.newtoken">
+              Your new token is: <code>{this.state.newToken}</code>
+            </Trans>
+          </p>
+        )}
+        <VStack spacing={4} className="mb-4">
+          {this.state.tokens.map((token) => this.renderToken(token))}
+        </VStack>
+        {this.state.isCreating ? (
+          this.renderCreateForm()
+        ) : (
+          <Button size="small" onClick={this.startCreating}>
+            <Trans id="mysettings.apitokens.new">New token</Trans>
+          </Button>
+        )}
+      </div>
+    )
+  }
+}
@@ -1,7 +1,7 @@
 import React, { useState } from "react"
 
 import { UserSettings } from "@fider/models"
-import { Toggle, Field } from "@fider/components"
+import { Toggle, Field, Select, SelectOption } from "@fider/components"
 import { HStack, VStack } from "@fider/components/layout"
 import { i18n } from "@lingui/core"
 import { Trans } from "@lingui/react/macro"
@@ -34,6 +34,15 @@ export const NotificationSettings = (props: NotificationSettingsProps) => {
     props.settingsChanged(nextSettings)
   }
 
+  const changeDigest = (option?: SelectOption) => {
+    if (!option) {
+      return
+    }
+    const nextSettings = { ...userSettings, email_digest: option.value }
+    setUserSettings(nextSettings)
+    props.settingsChanged(nextSettings)
+  }
+
   const labelWeb = i18n._({ id: "mysettings.notification.channelweb", message: "Web" })
   const labelEmail = i18n._({ id: "mysettings.notification.channelemail", message: "Email" })
 
@@ -100,6 +109,24 @@ export const NotificationSettings = (props: NotificationSettingsProps) => {
           </VStack>
         </div>
       </Field>
+
+      <Select
+        label={i18n._({ id: "mysettings.notification.digest.label", message: "Email Frequency" })}
+        field="email_digest"
+        defaultValue={userSettings.email_digest || "immediate"}
+        options={[
+          { label: i18n._({ id: "mysettings.notification.digest.immediate", message: "Immediately" }), value: "immediate" },
+          { label: i18n._({ id: "mysettings.notification.digest.daily", message: "Daily digest" }), value: "daily" },
+          { label: i18n._({ id: "mysettings.notification.digest.weekly", message: "Weekly digest" }), value: "weekly" },
+        ]}
+        onChange={changeDigest}
+      >
+        <p className="text-muted">
+          <Trans id="mysettings.notification.digest.description">
+            Comment, mention and status change emails can be grouped into a single daily or weekly email.
+          </Trans>
+        </p>
+      </Select>
     </>
   )
 }
@@ -3,7 +3,7 @@ import IconArrowLeft from "@fider/assets/images/heroicons-arrowleft.svg"
 import IconCheckCircle from "@fider/assets/images/heroicons-check-circle.svg"
 
 import React, { useState, useCallback } from "react"
-import { Post, Tag } from "@fider/models"
+import { Board, Post, Tag } from "@fider/models"
 import { Header, Button, Icon, ResponseLozenge, ShowTag, Moment } from "@fider/components"
 import { VStack, HStack } from "@fider/components/layout"
 import { useFider, usePostOverlay } from "@fider/hooks"
@@ -16,6 +16,7 @@ interface RoadmapPageProps {
   startedPosts?: Post[]
   completedPosts?: Post[]
   tags?: Tag[]
+  board?: Board
 }
 
 interface RoadmapColumnProps {
@@ -129,22 +130,24 @@ const RoadmapBoard = (props: RoadmapPageProps) => {
   const [startedLimit, setStartedLimit] = useState(ROADMAP_DEFAULT_LIMIT)
   const [completedLimit, setCompletedLimit] = useState(ROADMAP_DEFAULT_LIMIT)
   const tags = props.tags || []
+  // A board with its own roadmap only shows its posts, while the main roadmap leaves those posts out
+  const boardFilter = props.board ? { board: props.board.slug } : { excludeRoadmapBoards: true }
 
   const reloadPosts = useCallback(async () => {
     const [planned, started, completed] = await Promise.all([
-      actions.searchPosts({ view: "planned", limit: plannedLimit }),
-      actions.searchPosts({ view: "started", limit: startedLimit }),
-      actions.searchPosts({ view: "completed", limit: completedLimit }),
+      actions.searchPosts({ view: "planned", limit: plannedLimit, ...boardFilter }),
+      actions.searchPosts({ view: "started", limit: startedLimit, ...boardFilter }),
+      actions.searchPosts({ view: "completed", limit: completedLimit, ...boardFilter }),
     ])
     if (planned.ok) setPlannedPosts(planned.data)
     if (started.ok) setStartedPosts(started.data)
     if (completed.ok) setCompletedPosts(completed.data)
-  }, [plannedLimit, startedLimit, completedLimit])
+  }, [plannedLimit, startedLimit, completedLimit, props.board])
 
   const showMore = async (view: RoadmapView) => {
     const currentLimit = view === "planned" ? plannedLimit : view === "started" ? startedLimit : completedLimit
     const nextLimit = currentLimit + ROADMAP_LIMIT_STEP
-    const result = await actions.searchPosts({ view, limit: nextLimit })
+    const result = await actions.searchPosts({ view, limit: nextLimit, ...boardFilter })
     if (!result.ok) return
     if (view === "planned") {
       setPlannedLimit(nextLimit)
@@ -159,7 +162,7 @@ const RoadmapBoard = (props: RoadmapPageProps) => {
   }
 
   const { selectedPostId, handlePostClick, handleCloseOverlay, setIsPostDirty } = usePostOverlay({
-    basePath: "/roadmap",
+    basePath: props.board ? `/b/${props.board.slug}/roadmap` : "/roadmap",
     onPostClosed: () => reloadPosts(),
   })
 
@@ -173,6 +176,7 @@ const RoadmapBoard = (props: RoadmapPageProps) => {
     <div id="p-roadmap" className="page container">
       <div style={selectedPostId !== null ? { display: "none" } : undefined}>
         <VStack spacing={4}>
+          {props.board && <h1 className="text-display">{props.board.name}</h1>}
           <div className="c-roadmap-board">
             <RoadmapColumn
               status="planned"
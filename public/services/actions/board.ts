@@ -0,0 +1,27 @@
+import { http, Result } from "@fider/services/http"
+import { Board } from "@fider/models"
+
+export interface BoardInput {
+  name: string
+  description: string
+  descriptionTemplate: string
+  isPublic: boolean
+  hasRoadmap: boolean
+  defaultTags: string[]
+}
+
+export const listBoards = async (): Promise<Result<Board[]>> => {
+  return await http.get<Board[]>(`/api/v1/boards`)
+}
+
+export const createBoard = async (input: BoardInput): Promise<Result<Board>> => {
+  return http.post<Board>(`/api/v1/boards`, input).then(http.event("board", "create"))
+}
+
+export const updateBoard = async (slug: string, input: BoardInput): Promise<Result<Board>> => {
+  return http.put<Board>(`/api/v1/boards/${slug}`, input).then(http.event("board", "update"))
+}
+
+export const deleteBoard = async (slug: string): Promise<Result> => {
+  return http.delete(`/api/v1/boards/${slug}`).then(http.event("board", "delete"))
+}
@@ -1,5 +1,6 @@
 export * from "./user"
 export * from "./tag"
+export * from "./board"
 export * from "./post"
 export * from "./tenant"
 export * from "./notification"
@@ -23,6 +23,8 @@ export interface SearchPostsParams {
   myPosts?: boolean
   statuses?: string[]
   moderation?: string
+  board?: string
+  excludeRoadmapBoards?: boolean
 }
 
 export const searchPosts = async (params: SearchPostsParams): Promise<Result<Post[]>> => {
@@ -33,6 +35,7 @@ export const searchPosts = async (params: SearchPostsParams): Promise<Result<Pos
     view: params.view,
     limit: params.limit,
     moderation: params.moderation,
+    board: params.board,
   })
   if (params.myVotes) {
     qsParams += `&myvotes=true`
@@ -43,6 +46,9 @@ export const searchPosts = async (params: SearchPostsParams): Promise<Result<Pos
   if (params.myPosts) {
     qsParams += `&myposts=true`
   }
+  if (params.excludeRoadmapBoards) {
+    qsParams += `&excluderoadmapboards=true`
+  }
   return await http.get<Post[]>(`/api/v1/posts${qsParams}`)
 }
 
@@ -59,8 +65,8 @@ export const deletePost = async (postNumber: number, text: string): Promise<Resu
     .then(http.event("post", "delete"))
 }
 
-export const addVote = async (postNumber: number): Promise<Result> => {
-  return http.post(`/api/v1/posts/${postNumber}/votes`).then(http.event("post", "vote"))
+export const addVote = async (postNumber: number, amount?: number): Promise<Result<{ remaining?: number }>> => {
+  return http.post<{ remaining?: number }>(`/api/v1/posts/${postNumber}/votes`, amount ? { amount } : undefined).then(http.event("post", "vote"))
 }
 
 export const removeVote = async (postNumber: number): Promise<Result> => {
@@ -87,8 +93,8 @@ export const getTaggableUsers = async (userFilter: string): Promise<Result<UserN
   return http.get<UserNames[]>(`/api/v1/taggable-users${querystring.stringify({ query: userFilter })}`)
 }
 
-export const createComment = async (postNumber: number, content: string, attachments: ImageUpload[]): Promise<Result> => {
-  return http.post(`/api/v1/posts/${postNumber}/comments`, { content, attachments }).then(http.event("comment", "create"))
+export const createComment = async (postNumber: number, content: string, attachments: ImageUpload[], isInternal = false): Promise<Result> => {
+  return http.post(`/api/v1/posts/${postNumber}/comments`, { content, attachments, isInternal }).then(http.event("comment", "create"))
 }
 
 export const updateComment = async (postNumber: number, commentID: number, content: string, attachments: ImageUpload[]): Promise<Result> => {
@@ -130,8 +136,18 @@ interface CreatePostResponse {
   isApproved: boolean
 }
 
-export const createPost = async (title: string, description: string, attachments: ImageUpload[], tags: string[]): Promise<Result<CreatePostResponse>> => {
-  return http.post<CreatePostResponse>(`/api/v1/posts`, { title, description, attachments, tags }).then(http.event("post", "create"))
+export const createPost = async (
+  title: string,
+  description: string,
+  attachments: ImageUpload[],
+  tags: string[],
+  board?: string
+): Promise<Result<CreatePostResponse>> => {
+  return http.post<CreatePostResponse>(`/api/v1/posts`, { title, description, attachments, tags, board }).then(http.event("post", "create"))
+}
+
+export const setPostBoard = async (postNumber: number, board: string): Promise<Result> => {
+  return http.put(`/api/v1/posts/${postNumber}/board`, { board }).then(http.event("post", "setboard"))
 }
 
 export const updatePost = async (postNumber: number, title: string, description: string, attachments: ImageUpload[]): Promise<Result> => {
@@ -46,6 +46,10 @@ export const updateTenantPrivacy = async (request: PrivacySettingsPageState): Pr
   return await http.post("/_api/admin/settings/privacy", request)
 }
 
+export const updateTenantVotingSettings = async (voteBudget: number, maxVotesPerPost: number): Promise<Result> => {
+  return await http.post("/_api/admin/settings/voting", { voteBudget, maxVotesPerPost })
+}
+
 export const updateTenantEmailAuthAllowed = async (isEmailAuthAllowed: boolean): Promise<Result> => {
   return await http.post("/_api/admin/settings/emailauth", {
     isEmailAuthAllowed,
@@ -123,12 +127,36 @@ export interface CreateEditOAuthConfigRequest {
   allowedRoles: string
   logo?: ImageUpload
   isTrusted: boolean
+  isOIDC: boolean
+  issuerURL: string
 }
 
 export const saveOAuthConfig = async (request: CreateEditOAuthConfigRequest): Promise<Result> => {
   return await http.post("/_api/admin/oauth", request)
 }
 
+export interface SaveSAMLConfigRequest {
+  metadata: string
+  status: number
+  displayName: string
+  idpEntityID: string
+  idpSSOURL: string
+  idpCertificate: string
+  nameAttribute: string
+  emailAttribute: string
+  rolesAttribute: string
+  allowedRoles: string
+  isTrusted: boolean
+}
+
+export const saveSAMLConfig = async (request: SaveSAMLConfigRequest): Promise<Result> => {
+  return await http.post("/_api/admin/saml", request)
+}
+
+export const regenerateSCIMToken = async (): Promise<Result<{ token: string }>> => {
+  return await http.post<{ token: string }>("/_api/admin/scim/token")
+}
+
 export const setSystemProviderStatus = async (provider: string, isEnabled: boolean): Promise<Result> => {
   return await http.post(`/_api/admin/oauth/${provider}/status`, { provider, isEnabled })
 }
@@ -1,5 +1,5 @@
 import { http, Result } from "@fider/services/http"
-import { UserSettings, UserAvatarType, ImageUpload } from "@fider/models"
+import { UserSettings, UserAvatarType, ImageUpload, APIToken, APIScope } from "@fider/models"
 
 interface UpdateUserSettings {
   name: string
@@ -25,3 +25,17 @@ export const deleteCurrentAccount = async (): Promise<Result> => {
 export const regenerateAPIKey = async (): Promise<Result<{ apiKey: string }>> => {
   return await http.post<{ apiKey: string }>("/_api/user/regenerate-apikey")
 }
+
+interface CreateAPITokenRequest {
+  name: string
+  scopes: APIScope[]
+  expiresInDays: number
+}
+
+export const createAPIToken = async (request: CreateAPITokenRequest): Promise<Result<{ token: string; apiToken: APIToken }>> => {
+  return await http.post<{ token: string; apiToken: APIToken }>("/_api/user/tokens", request)
+}
+
+export const revokeAPIToken = async (id: number): Promise<Result> => {
+  return await http.delete(`/_api/user/tokens/${id}`)
+}
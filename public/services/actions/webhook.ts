@@ -1,7 +1,7 @@
 import { http, Result, StringObject } from "@fider/services"
-import { WebhookData, WebhookPreviewResult, WebhookTriggerResult, WebhookType } from "@fider/models"
+import { WebhookData, WebhookDelivery, WebhookPreviewResult, WebhookTriggerResult, WebhookType } from "@fider/models"
 
-export const createWebhook = async (data: WebhookData): Promise<Result<{ id: number }>> => {
+export const createWebhook = async (data: WebhookData): Promise<Result<{ id: number; secret: string }>> => {
   return await http.post(`/_api/admin/webhook`, data)
 }
 
@@ -13,6 +13,10 @@ export const deleteWebhook = async (id: number): Promise<Result> => {
   return await http.delete(`/_api/admin/webhook/${id}`)
 }
 
+export const rotateWebhookSecret = async (id: number): Promise<Result<{ secret: string }>> => {
+  return await http.post(`/_api/admin/webhook/rotate/${id}`)
+}
+
 export const testWebhook = async (id: number): Promise<Result<WebhookTriggerResult>> => {
   return await http.get(`/_api/admin/webhook/test/${id}`)
 }
@@ -24,3 +28,11 @@ export const previewWebhook = async (type: WebhookType, url: string, content: st
 export const getWebhookHelp = async (type: WebhookType): Promise<Result<StringObject>> => {
   return await http.get(`/_api/admin/webhook/props/${type}`)
 }
+
+export const getWebhookDeliveries = async (id: number): Promise<Result<WebhookDelivery[]>> => {
+  return await http.get(`/_api/admin/webhook/deliveries/${id}`)
+}
+
+export const redeliverWebhook = async (id: number, deliveryID: number): Promise<Result<WebhookTriggerResult>> => {
+  return await http.post(`/_api/admin/webhook/redeliver/${id}/${deliveryID}`)
+}